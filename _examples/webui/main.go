@@ -49,7 +49,7 @@ func main() {
 			return strconv.Itoa(enumValue)
 		},
 	))
-	http.HandleFunc(paths.ObjectData, webui.ObjectDataHandlerFunc(recordStore))
+	http.HandleFunc(paths.ObjectData, webui.ObjectDataHandlerFunc(recordStore, w))
 	http.HandleFunc(paths.Update, webui.UpdateHandlerFunc(recordStore))
 
 	fmt.Println("Head on over to 'http://localhost:9492' to view!")
@@ -165,5 +165,13 @@ func Example(rs workflow.RecordStore) *workflow.Workflow[ExampleData, Status] {
 		memstreamer.New(),
 		rs,
 		memrolescheduler.New(),
+		// Only show the first letter of the name in the web UI.
+		workflow.WithRedaction(func(object *ExampleData) error {
+			if len(object.Name) > 1 {
+				object.Name = object.Name[:1] + "***"
+			}
+
+			return nil
+		}),
 	)
 }
@@ -0,0 +1,68 @@
+package adaptertest
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+)
+
+func RunBlobStoreTest(t *testing.T, factory func() workflow.BlobStore) {
+	tests := []func(t *testing.T, factory func() workflow.BlobStore){
+		testPutAndGetBlob,
+		testDeleteBlob,
+	}
+
+	for _, test := range tests {
+		test(t, factory)
+	}
+}
+
+func testPutAndGetBlob(t *testing.T, factory func() workflow.BlobStore) {
+	t.Run("Put and Get blobs", func(t *testing.T) {
+		store := factory()
+		ctx := context.Background()
+
+		payload := strings.Repeat("payload", 1024)
+		size, err := store.Put(ctx, "workflow/foreignID/runID/1", strings.NewReader(payload))
+		require.Nil(t, err)
+		require.Equal(t, int64(len(payload)), size)
+
+		r, err := store.Get(ctx, "workflow/foreignID/runID/1")
+		require.Nil(t, err)
+		t.Cleanup(func() {
+			require.Nil(t, r.Close())
+		})
+
+		b, err := io.ReadAll(r)
+		require.Nil(t, err)
+		require.Equal(t, payload, string(b))
+
+		_, err = store.Get(ctx, "workflow/foreignID/runID/2")
+		require.ErrorIs(t, err, workflow.ErrBlobNotFound)
+	})
+}
+
+func testDeleteBlob(t *testing.T, factory func() workflow.BlobStore) {
+	t.Run("Delete blobs", func(t *testing.T) {
+		store := factory()
+		ctx := context.Background()
+
+		_, err := store.Put(ctx, "key", strings.NewReader("payload"))
+		require.Nil(t, err)
+
+		err = store.Delete(ctx, "key")
+		require.Nil(t, err)
+
+		_, err = store.Get(ctx, "key")
+		require.ErrorIs(t, err, workflow.ErrBlobNotFound)
+
+		// Deleting a blob that does not exist is not an error.
+		err = store.Delete(ctx, "key")
+		require.Nil(t, err)
+	})
+}
@@ -0,0 +1,83 @@
+package adaptertest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+)
+
+func RunDefinitionStoreTest(t *testing.T, factory func() workflow.DefinitionStore) {
+	tests := []func(t *testing.T, factory func() workflow.DefinitionStore){
+		testRegisterDefinition,
+		testDefinitionNotFound,
+	}
+
+	for _, test := range tests {
+		test(t, factory)
+	}
+}
+
+func testRegisterDefinition(t *testing.T, factory func() workflow.DefinitionStore) {
+	t.Run("Register definition only replaces older or equal versions", func(t *testing.T) {
+		store := factory()
+		ctx := context.Background()
+		now := time.Now()
+
+		err := store.RegisterDefinition(ctx, workflow.Definition{
+			WorkflowName: "example",
+			Version:      2,
+			Hash:         "v2",
+			RegisteredAt: now,
+		})
+		require.Nil(t, err)
+
+		// Older versions must be ignored.
+		err = store.RegisterDefinition(ctx, workflow.Definition{
+			WorkflowName: "example",
+			Version:      1,
+			Hash:         "v1",
+			RegisteredAt: now,
+		})
+		require.Nil(t, err)
+
+		active, err := store.ActiveDefinition(ctx, "example")
+		require.Nil(t, err)
+		require.Equal(t, 2, active.Version)
+		require.Equal(t, "v2", active.Hash)
+		require.WithinDuration(t, now, active.RegisteredAt, allowedTimeDeviation)
+
+		// Equal versions replace the active definition.
+		err = store.RegisterDefinition(ctx, workflow.Definition{
+			WorkflowName: "example",
+			Version:      2,
+			Hash:         "v2-changed",
+			RegisteredAt: now,
+		})
+		require.Nil(t, err)
+
+		active, err = store.ActiveDefinition(ctx, "example")
+		require.Nil(t, err)
+		require.Equal(t, "v2-changed", active.Hash)
+	})
+}
+
+func testDefinitionNotFound(t *testing.T, factory func() workflow.DefinitionStore) {
+	t.Run("Active definition not found", func(t *testing.T) {
+		store := factory()
+		ctx := context.Background()
+
+		err := store.RegisterDefinition(ctx, workflow.Definition{
+			WorkflowName: "example",
+			Version:      1,
+		})
+		require.Nil(t, err)
+
+		_, err = store.ActiveDefinition(ctx, "other")
+		require.True(t, errors.Is(err, workflow.ErrDefinitionNotFound))
+	})
+}
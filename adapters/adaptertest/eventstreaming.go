@@ -2,6 +2,7 @@ package adaptertest
 
 import (
 	"context"
+	"strconv"
 	"testing"
 	"time"
 
@@ -102,6 +103,138 @@ func RunEventStreamerTest(t *testing.T, constructor workflow.EventStreamer) {
 	require.Equal(t, "andrew@workflow.com", record.Object.Email)
 	require.Equal(t, SyncStatusCompleted.String(), record.Status.String())
 	require.NotEmpty(t, record.Object.UID)
+
+	t.Run("AsyncEventSender", func(t *testing.T) {
+		testAsyncEventSender(t, constructor)
+	})
+
+	t.Run("CursorManager", func(t *testing.T) {
+		testCursorManager(t, constructor)
+	})
+}
+
+// testAsyncEventSender ensures that senders that implement workflow.AsyncEventSender deliver every event and call
+// every callback once Flush has returned.
+func testAsyncEventSender(t *testing.T, constructor workflow.EventStreamer) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	t.Cleanup(cancel)
+
+	topic := "async-sender-" + uuid.New().String()
+	sender, err := constructor.NewSender(ctx, topic)
+	require.Nil(t, err)
+	t.Cleanup(func() {
+		_ = sender.Close()
+	})
+
+	async, ok := sender.(workflow.AsyncEventSender)
+	if !ok {
+		t.Skip("sender does not implement workflow.AsyncEventSender")
+	}
+
+	const count = 10
+	results := make(chan error, count)
+	for i := 0; i < count; i++ {
+		err := async.SendAsync(ctx, strconv.Itoa(i), int(SyncStatusStarted), map[workflow.Header]string{
+			workflow.HeaderTopic: topic,
+		}, func(err error) {
+			results <- err
+		})
+		require.Nil(t, err)
+	}
+
+	err = async.Flush(ctx)
+	require.Nil(t, err)
+	require.Len(t, results, count)
+	for i := 0; i < count; i++ {
+		require.Nil(t, <-results)
+	}
+
+	receiver, err := constructor.NewReceiver(ctx, topic, "async-sender-test")
+	require.Nil(t, err)
+	t.Cleanup(func() {
+		_ = receiver.Close()
+	})
+
+	received := make(map[string]bool)
+	for len(received) < count {
+		e, ack, err := receiver.Recv(ctx)
+		require.Nil(t, err)
+
+		received[e.ForeignID] = true
+		require.Nil(t, ack())
+	}
+}
+
+// testCursorManager ensures that streamers that implement workflow.CursorManager report the lag of a cursor and
+// reset the cursor so that its events are consumed again, or skipped.
+func testCursorManager(t *testing.T, constructor workflow.EventStreamer) {
+	manager, ok := constructor.(workflow.CursorManager)
+	if !ok {
+		t.Skip("streamer does not implement workflow.CursorManager")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	t.Cleanup(cancel)
+
+	cursor := workflow.Cursor{
+		Topic: "cursor-manager-" + uuid.New().String(),
+		Name:  "cursor-manager-test-" + uuid.New().String(),
+	}
+
+	sender, err := constructor.NewSender(ctx, cursor.Topic)
+	require.Nil(t, err)
+	t.Cleanup(func() {
+		_ = sender.Close()
+	})
+
+	const count = 3
+	for i := 0; i < count; i++ {
+		err := sender.Send(ctx, strconv.Itoa(i), int(SyncStatusStarted), map[workflow.Header]string{
+			workflow.HeaderTopic: cursor.Topic,
+		})
+		require.Nil(t, err)
+	}
+
+	// consume receives, and acknowledges, the provided number of events and then closes the receiver so that the
+	// cursor is no longer in use.
+	consume := func(n int) []string {
+		receiver, err := constructor.NewReceiver(ctx, cursor.Topic, cursor.Name)
+		require.Nil(t, err)
+		defer receiver.Close()
+
+		var foreignIDs []string
+		for len(foreignIDs) < n {
+			e, ack, err := receiver.Recv(ctx)
+			require.Nil(t, err)
+			require.Nil(t, ack())
+
+			foreignIDs = append(foreignIDs, e.ForeignID)
+		}
+
+		return foreignIDs
+	}
+
+	require.Equal(t, []string{"0", "1", "2"}, consume(count))
+
+	offset, err := manager.CursorOffset(ctx, cursor)
+	require.Nil(t, err)
+	require.Equal(t, cursor, offset.Cursor)
+	require.Equal(t, int64(0), offset.Lag)
+
+	err = manager.ResetCursor(ctx, cursor, workflow.CursorAtEarliest())
+	require.Nil(t, err)
+
+	offset, err = manager.CursorOffset(ctx, cursor)
+	require.Nil(t, err)
+	require.Equal(t, int64(count), offset.Lag)
+	require.Equal(t, []string{"0"}, consume(1))
+
+	err = manager.ResetCursor(ctx, cursor, workflow.CursorAtLatest())
+	require.Nil(t, err)
+
+	offset, err = manager.CursorOffset(ctx, cursor)
+	require.Nil(t, err)
+	require.Equal(t, int64(0), offset.Lag)
 }
 
 func setEmail() func(ctx context.Context, t *workflow.Run[User, SyncStatus]) (SyncStatus, error) {
@@ -0,0 +1,78 @@
+package adaptertest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+)
+
+func RunLegalHoldStoreTest(t *testing.T, factory func() workflow.LegalHoldStore) {
+	tests := []func(t *testing.T, factory func() workflow.LegalHoldStore){
+		testPlaceAndLiftLegalHold,
+		testLegalHoldErrors,
+	}
+
+	for _, test := range tests {
+		test(t, factory)
+	}
+}
+
+func testPlaceAndLiftLegalHold(t *testing.T, factory func() workflow.LegalHoldStore) {
+	t.Run("Place and Lift legal holds", func(t *testing.T) {
+		store := factory()
+		ctx := context.Background()
+		now := time.Now()
+
+		err := store.Place(ctx, "example", "andrew", "litigation", now)
+		require.Nil(t, err)
+
+		holds, err := store.List(ctx, "example", "andrew")
+		require.Nil(t, err)
+		require.Equal(t, 1, len(holds))
+		require.True(t, holds[0].Active())
+		require.Equal(t, "litigation", holds[0].Reason)
+		require.WithinDuration(t, now, holds[0].PlacedAt, allowedTimeDeviation)
+
+		err = store.Lift(ctx, "example", "andrew", "settled", now.Add(time.Hour))
+		require.Nil(t, err)
+
+		err = store.Place(ctx, "example", "andrew", "audit", now.Add(2*time.Hour))
+		require.Nil(t, err)
+
+		holds, err = store.List(ctx, "example", "andrew")
+		require.Nil(t, err)
+		require.Equal(t, 2, len(holds))
+
+		require.False(t, holds[0].Active())
+		require.Equal(t, "settled", holds[0].LiftReason)
+		require.WithinDuration(t, now.Add(time.Hour), holds[0].LiftedAt, allowedTimeDeviation)
+
+		require.True(t, holds[1].Active())
+		require.Equal(t, "audit", holds[1].Reason)
+
+		other, err := store.List(ctx, "example", "not andrew")
+		require.Nil(t, err)
+		require.Equal(t, 0, len(other))
+	})
+}
+
+func testLegalHoldErrors(t *testing.T, factory func() workflow.LegalHoldStore) {
+	t.Run("Legal hold errors", func(t *testing.T) {
+		store := factory()
+		ctx := context.Background()
+
+		err := store.Lift(ctx, "example", "andrew", "settled", time.Now())
+		require.True(t, errors.Is(err, workflow.ErrLegalHoldNotFound))
+
+		err = store.Place(ctx, "example", "andrew", "litigation", time.Now())
+		require.Nil(t, err)
+
+		err = store.Place(ctx, "example", "andrew", "litigation", time.Now())
+		require.True(t, errors.Is(err, workflow.ErrLegalHoldActive))
+	})
+}
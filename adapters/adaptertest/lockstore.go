@@ -0,0 +1,115 @@
+package adaptertest
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+)
+
+func RunLockStoreTest(t *testing.T, factory func(t *testing.T) workflow.LockStore) {
+	tests := []func(t *testing.T, factory func(t *testing.T) workflow.LockStore){
+		testLockReturnedContext,
+		testLockLimit,
+		testLockReleasing,
+		testLockWaitCancelled,
+	}
+
+	for _, test := range tests {
+		test(t, factory)
+	}
+}
+
+func testLockReturnedContext(t *testing.T, factory func(t *testing.T) workflow.LockStore) {
+	t.Run("Ensure that the passed in context is a parent of the returned context", func(t *testing.T) {
+		ls := factory(t)
+		ctxWithValue := context.WithValue(context.Background(), "parent", "context")
+
+		ctx, release, err := ls.Acquire(ctxWithValue, "lock-parent-ctx", 1)
+		require.Nil(t, err)
+		t.Cleanup(release)
+
+		require.Equal(t, "context", ctx.Value("parent"))
+	})
+}
+
+func testLockLimit(t *testing.T, factory func(t *testing.T) workflow.LockStore) {
+	t.Run("Ensure no more than the limit of callers hold the key", func(t *testing.T) {
+		ls := factory(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+
+		var held atomic.Int64
+		for range 5 {
+			go func() {
+				_, _, err := ls.Acquire(ctx, "lock-limit", 2)
+				if err != nil {
+					return
+				}
+
+				held.Add(1)
+			}()
+		}
+
+		require.Eventually(t, func() bool {
+			return held.Load() == 2
+		}, 5*time.Second, 10*time.Millisecond)
+
+		require.Never(t, func() bool {
+			return held.Load() > 2
+		}, 250*time.Millisecond, 50*time.Millisecond)
+	})
+}
+
+func testLockReleasing(t *testing.T, factory func(t *testing.T) workflow.LockStore) {
+	t.Run("Ensure the slot is released", func(t *testing.T) {
+		ls := factory(t)
+		ctx := context.Background()
+
+		_, release, err := ls.Acquire(ctx, "lock-releasing", 1)
+		require.Nil(t, err)
+
+		acquired := make(chan bool)
+		go func() {
+			_, release, err := ls.Acquire(ctx, "lock-releasing", 1)
+			require.Nil(t, err)
+			release()
+
+			acquired <- true
+		}()
+
+		select {
+		case <-acquired:
+			require.FailNow(t, "lock acquired whilst held")
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		release()
+
+		select {
+		case <-acquired:
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "lock not acquired after being released")
+		}
+	})
+}
+
+func testLockWaitCancelled(t *testing.T, factory func(t *testing.T) workflow.LockStore) {
+	t.Run("Ensure waiting for a held key stops on context cancellation", func(t *testing.T) {
+		ls := factory(t)
+
+		_, release, err := ls.Acquire(context.Background(), "lock-wait-cancelled", 1)
+		require.Nil(t, err)
+		t.Cleanup(release)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		t.Cleanup(cancel)
+
+		_, _, err = ls.Acquire(ctx, "lock-wait-cancelled", 1)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
@@ -0,0 +1,59 @@
+package adaptertest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+)
+
+func RunNonceStoreTest(t *testing.T, factory func() workflow.NonceStore) {
+	tests := []func(t *testing.T, factory func() workflow.NonceStore){
+		testClaimNonce,
+		testClaimExpiredNonce,
+	}
+
+	for _, test := range tests {
+		test(t, factory)
+	}
+}
+
+func testClaimNonce(t *testing.T, factory func() workflow.NonceStore) {
+	t.Run("Nonces can only be claimed once", func(t *testing.T) {
+		store := factory()
+		ctx := context.Background()
+		expireAt := time.Now().Add(time.Hour)
+
+		claimed, err := store.Claim(ctx, "nonce-1", expireAt)
+		require.Nil(t, err)
+		require.True(t, claimed)
+
+		claimed, err = store.Claim(ctx, "nonce-1", expireAt)
+		require.Nil(t, err)
+		require.False(t, claimed)
+
+		claimed, err = store.Claim(ctx, "nonce-2", expireAt)
+		require.Nil(t, err)
+		require.True(t, claimed)
+	})
+}
+
+func testClaimExpiredNonce(t *testing.T, factory func() workflow.NonceStore) {
+	t.Run("Expired nonces can be claimed again", func(t *testing.T) {
+		store := factory()
+		ctx := context.Background()
+
+		claimed, err := store.Claim(ctx, "nonce", time.Now().Add(time.Second))
+		require.Nil(t, err)
+		require.True(t, claimed)
+
+		require.Eventually(t, func() bool {
+			claimed, err := store.Claim(ctx, "nonce", time.Now().Add(time.Second))
+			require.Nil(t, err)
+			return claimed
+		}, 5*time.Second, 100*time.Millisecond)
+	})
+}
@@ -410,8 +410,11 @@ func dummyWireRecord(t *testing.T, workflowName string) *workflow.Record {
 		Status:       int(statusStarted),
 		RunState:     workflow.RunStateInitiated,
 		Object:       b,
-		CreatedAt:    createdAt,
-		UpdatedAt:    createdAt,
+		Meta: workflow.RecordMeta{
+			Version: 1,
+		},
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
 	}
 }
 
@@ -422,6 +425,7 @@ func recordIsEqual(t *testing.T, a, b workflow.Record) {
 	require.Equal(t, a.Status, b.Status)
 	require.Equal(t, a.Object, b.Object)
 	require.Equal(t, a.RunState, b.RunState)
+	require.Equal(t, a.Meta, b.Meta)
 	require.WithinDuration(t, a.CreatedAt, b.CreatedAt, allowedTimeDeviation)
 	require.WithinDuration(t, a.UpdatedAt, b.UpdatedAt, allowedTimeDeviation)
 }
@@ -0,0 +1,102 @@
+package adaptertest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+)
+
+// SearchableRecordStore is a workflow.RecordStore that implements workflow.SearchableRecordStore.
+type SearchableRecordStore interface {
+	workflow.RecordStore
+	workflow.SearchableRecordStore
+}
+
+func RunSearchableRecordStoreTest(t *testing.T, factory func() SearchableRecordStore) {
+	tests := []func(t *testing.T, factory func() SearchableRecordStore){
+		testSearch,
+		testSearchUpdatedValue,
+	}
+
+	for _, test := range tests {
+		test(t, factory)
+	}
+}
+
+func testSearch(t *testing.T, factory func() SearchableRecordStore) {
+	t.Run("Search by search index", func(t *testing.T) {
+		store := factory()
+		ctx := context.Background()
+
+		var expected []*workflow.Record
+		for i, customerID := range []string{"customer-1", "customer-2", "customer-1", "customer-1"} {
+			r := dummyWireRecord(t, "my_workflow")
+			r.ForeignID = "foreignID-" + string(rune('a'+i))
+			r.Meta.SearchIndex = map[string]string{
+				"customer_id": customerID,
+				"region":      "eu",
+			}
+
+			err := store.Store(ctx, r)
+			require.Nil(t, err)
+
+			if customerID == "customer-1" {
+				expected = append(expected, r)
+			}
+		}
+
+		other := dummyWireRecord(t, "other_workflow")
+		other.Meta.SearchIndex = map[string]string{"customer_id": "customer-1"}
+		err := store.Store(ctx, other)
+		require.Nil(t, err)
+
+		records, err := store.Search(ctx, "my_workflow", "customer_id", "customer-1", 0, 10)
+		require.Nil(t, err)
+		require.Len(t, records, 3)
+		for i, r := range records {
+			recordIsEqual(t, *expected[i], r)
+		}
+
+		records, err = store.Search(ctx, "my_workflow", "customer_id", "customer-1", 1, 1)
+		require.Nil(t, err)
+		require.Len(t, records, 1)
+		recordIsEqual(t, *expected[1], records[0])
+
+		records, err = store.Search(ctx, "my_workflow", "customer_id", "customer-3", 0, 10)
+		require.Nil(t, err)
+		require.Empty(t, records)
+
+		records, err = store.Search(ctx, "my_workflow", "region", "customer-1", 0, 10)
+		require.Nil(t, err)
+		require.Empty(t, records)
+	})
+}
+
+func testSearchUpdatedValue(t *testing.T, factory func() SearchableRecordStore) {
+	t.Run("Search uses the latest value of the search index", func(t *testing.T) {
+		store := factory()
+		ctx := context.Background()
+
+		r := dummyWireRecord(t, "my_workflow")
+		r.Meta.SearchIndex = map[string]string{"customer_id": "customer-1"}
+		err := store.Store(ctx, r)
+		require.Nil(t, err)
+
+		updated := *r
+		updated.Meta.SearchIndex = map[string]string{"customer_id": "customer-2"}
+		err = store.Store(ctx, &updated)
+		require.Nil(t, err)
+
+		records, err := store.Search(ctx, "my_workflow", "customer_id", "customer-1", 0, 10)
+		require.Nil(t, err)
+		require.Empty(t, records)
+
+		records, err = store.Search(ctx, "my_workflow", "customer_id", "customer-2", 0, 10)
+		require.Nil(t, err)
+		require.Len(t, records, 1)
+		recordIsEqual(t, updated, records[0])
+	})
+}
@@ -15,6 +15,7 @@ func RunTimeoutStoreTest(t *testing.T, factory func() workflow.TimeoutStore) {
 	tests := []func(t *testing.T, factory func() workflow.TimeoutStore){
 		testCompleteAndCancelTimeout,
 		testListTimeout,
+		testNamedTimeout,
 	}
 
 	for _, test := range tests {
@@ -85,3 +86,31 @@ func testListTimeout(t *testing.T, factory func() workflow.TimeoutStore) {
 		expect(t, 3, timeouts)
 	})
 }
+
+func testNamedTimeout(t *testing.T, factory func() workflow.TimeoutStore) {
+	t.Run("Named timeouts", func(t *testing.T) {
+		store := factory()
+		named, ok := store.(workflow.NamedTimeoutStore)
+		if !ok {
+			t.Skip("TimeoutStore does not implement NamedTimeoutStore")
+		}
+
+		ctx := context.Background()
+		err := store.Create(ctx, "example", "andrew", "1", int(statusStarted), time.Now().Add(-time.Hour))
+		require.Nil(t, err)
+
+		err = named.CreateNamed(ctx, "example", "andrew", "1", int(statusStarted), "reminder", time.Now().Add(-time.Hour))
+		require.Nil(t, err)
+
+		timeouts, err := store.ListValid(ctx, "example", int(statusStarted), time.Now())
+		require.Nil(t, err)
+		require.Len(t, timeouts, 2)
+		require.Equal(t, "", timeouts[0].Name)
+		require.Equal(t, "reminder", timeouts[1].Name)
+
+		timeouts, err = store.List(ctx, "example")
+		require.Nil(t, err)
+		require.Len(t, timeouts, 2)
+		require.Equal(t, "reminder", timeouts[1].Name)
+	})
+}
@@ -0,0 +1,239 @@
+package instrumented
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/luno/workflow"
+)
+
+// NewEventStreamer wraps the EventStreamer so that the creation of senders and receivers, and every event sent,
+// received, and acknowledged, records its latency and errors. The size of the headers of every event sent and
+// received is recorded as its payload size.
+func NewEventStreamer(name string, streamer workflow.EventStreamer) *EventStreamer {
+	return &EventStreamer{
+		name:     name,
+		streamer: streamer,
+	}
+}
+
+type EventStreamer struct {
+	name     string
+	streamer workflow.EventStreamer
+}
+
+func (s *EventStreamer) NewSender(ctx context.Context, topic string) (workflow.EventSender, error) {
+	t0 := time.Now()
+	inner, err := s.streamer.NewSender(ctx, topic)
+	observe(s.name, "new_sender", t0, err)
+	if err != nil {
+		return nil, err
+	}
+
+	instrumented := &sender{
+		name:   s.name,
+		sender: inner,
+	}
+
+	// The optional workflow.BatchEventSender and workflow.AsyncEventSender interfaces must remain visible to the
+	// outbox. The outbox prefers workflow.BatchEventSender and so it is the only one kept when both are implemented.
+	if batch, ok := inner.(workflow.BatchEventSender); ok {
+		return &batchSender{
+			sender: instrumented,
+			batch:  batch,
+		}, nil
+	}
+
+	if async, ok := inner.(workflow.AsyncEventSender); ok {
+		return &asyncSender{
+			sender: instrumented,
+			async:  async,
+		}, nil
+	}
+
+	return instrumented, nil
+}
+
+func (s *EventStreamer) NewReceiver(
+	ctx context.Context,
+	topic string,
+	name string,
+	opts ...workflow.ReceiverOption,
+) (workflow.EventReceiver, error) {
+	t0 := time.Now()
+	inner, err := s.streamer.NewReceiver(ctx, topic, name, opts...)
+	observe(s.name, "new_receiver", t0, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return &receiver{
+		name:     s.name,
+		receiver: inner,
+	}, nil
+}
+
+// NewMultiplexedReceiver instruments the receiver of the wrapped EventStreamer's workflow.MultiplexedEventStreamer
+// implementation and returns an error when it does not implement it.
+func (s *EventStreamer) NewMultiplexedReceiver(
+	ctx context.Context,
+	topics []string,
+	name string,
+	opts ...workflow.ReceiverOption,
+) (workflow.EventReceiver, error) {
+	multiplexed, ok := s.streamer.(workflow.MultiplexedEventStreamer)
+	if !ok {
+		return nil, errors.New("event streamer does not support multiplexed receivers")
+	}
+
+	t0 := time.Now()
+	inner, err := multiplexed.NewMultiplexedReceiver(ctx, topics, name, opts...)
+	observe(s.name, "new_receiver", t0, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return &receiver{
+		name:     s.name,
+		receiver: inner,
+	}, nil
+}
+
+// Unwrap returns the EventStreamer that is being instrumented.
+func (s *EventStreamer) Unwrap() workflow.EventStreamer {
+	return s.streamer
+}
+
+var _ workflow.MultiplexedEventStreamer = (*EventStreamer)(nil)
+
+type sender struct {
+	name   string
+	sender workflow.EventSender
+}
+
+func (s *sender) Send(ctx context.Context, foreignID string, statusType int, headers map[workflow.Header]string) error {
+	t0 := time.Now()
+	err := s.sender.Send(ctx, foreignID, statusType, headers)
+	observe(s.name, "send", t0, err)
+	if err != nil {
+		return err
+	}
+
+	observeSize(s.name, "send", headersSize(headers))
+	return nil
+}
+
+func (s *sender) Close() error {
+	t0 := time.Now()
+	err := s.sender.Close()
+	observe(s.name, "close_sender", t0, err)
+	return err
+}
+
+type asyncSender struct {
+	*sender
+	async workflow.AsyncEventSender
+}
+
+func (s *asyncSender) SendAsync(
+	ctx context.Context,
+	foreignID string,
+	statusType int,
+	headers map[workflow.Header]string,
+	callback workflow.DeliveryCallback,
+) error {
+	t0 := time.Now()
+	err := s.async.SendAsync(ctx, foreignID, statusType, headers, func(err error) {
+		// The latency of an asynchronous send is from when it is queued until it is delivered.
+		observe(s.name, "send_async", t0, err)
+		if err == nil {
+			observeSize(s.name, "send_async", headersSize(headers))
+		}
+
+		callback(err)
+	})
+	if err != nil {
+		observeErr(s.name, "send_async", err)
+		return err
+	}
+
+	return nil
+}
+
+func (s *asyncSender) Flush(ctx context.Context) error {
+	t0 := time.Now()
+	err := s.async.Flush(ctx)
+	observe(s.name, "flush", t0, err)
+	return err
+}
+
+var _ workflow.AsyncEventSender = (*asyncSender)(nil)
+
+type batchSender struct {
+	*sender
+	batch workflow.BatchEventSender
+}
+
+func (s *batchSender) SendBatch(ctx context.Context, events []workflow.BatchEvent) error {
+	t0 := time.Now()
+	err := s.batch.SendBatch(ctx, events)
+	observe(s.name, "send_batch", t0, err)
+	if err != nil {
+		return err
+	}
+
+	for _, e := range events {
+		observeSize(s.name, "send_batch", headersSize(e.Headers))
+	}
+
+	return nil
+}
+
+var _ workflow.BatchEventSender = (*batchSender)(nil)
+
+type receiver struct {
+	name     string
+	receiver workflow.EventReceiver
+}
+
+func (r *receiver) Recv(ctx context.Context) (*workflow.Event, workflow.Ack, error) {
+	// The latency of Recv includes the time spent waiting for a new event and is therefore not recorded.
+	e, ack, err := r.receiver.Recv(ctx)
+	if err != nil {
+		observeErr(r.name, "recv", err)
+		return nil, nil, err
+	}
+
+	observeSize(r.name, "recv", headersSize(e.Headers))
+	return e, func() error {
+		t0 := time.Now()
+		err := ack()
+		observe(r.name, "ack", t0, err)
+		return err
+	}, nil
+}
+
+func (r *receiver) Close() error {
+	t0 := time.Now()
+	err := r.receiver.Close()
+	observe(r.name, "close_receiver", t0, err)
+	return err
+}
+
+// Sharded preserves the workflow.ShardedReceiver implementation of the wrapped receiver.
+func (r *receiver) Sharded() bool {
+	sharded, ok := r.receiver.(workflow.ShardedReceiver)
+	return ok && sharded.Sharded()
+}
+
+var _ workflow.ShardedReceiver = (*receiver)(nil)
+
+func headersSize(headers map[workflow.Header]string) int {
+	var size int
+	for k, v := range headers {
+		size += len(k) + len(v)
+	}
+
+	return size
+}
@@ -0,0 +1,30 @@
+// Package instrumented provides decorators for the RecordStore, TimeoutStore, and EventStreamer adapters that record
+// the latency, error count, and payload size of every operation. The metrics are labelled with the name given to the
+// decorator and the operation so that the performance of any adapter implementation can be observed uniformly.
+package instrumented
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/luno/workflow/internal/metrics"
+)
+
+// observe records the latency of the operation, that started at t0, and counts the error if there is one.
+func observe(name, operation string, t0 time.Time, err error) {
+	metrics.AdapterLatency.WithLabelValues(name, operation).Observe(time.Since(t0).Seconds())
+	observeErr(name, operation, err)
+}
+
+// observeErr counts the error of the operation. Context cancellation is expected during shutdown and is not counted as
+// an error of the adapter.
+func observeErr(name, operation string, err error) {
+	if err != nil && !errors.Is(err, context.Canceled) {
+		metrics.AdapterErrors.WithLabelValues(name, operation).Inc()
+	}
+}
+
+func observeSize(name, operation string, size int) {
+	metrics.AdapterPayloadSize.WithLabelValues(name, operation).Observe(float64(size))
+}
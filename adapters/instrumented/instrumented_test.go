@@ -0,0 +1,67 @@
+package instrumented_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/adaptertest"
+	"github.com/luno/workflow/adapters/instrumented"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memstreamer"
+	"github.com/luno/workflow/adapters/memtimeoutstore"
+	"github.com/luno/workflow/internal/metrics"
+)
+
+func TestRecordStore(t *testing.T) {
+	adaptertest.RunRecordStoreTest(t, func() workflow.RecordStore {
+		return instrumented.NewRecordStore("memrecordstore", memrecordstore.New())
+	})
+}
+
+func TestTimeoutStore(t *testing.T) {
+	adaptertest.RunTimeoutStoreTest(t, func() workflow.TimeoutStore {
+		return instrumented.NewTimeoutStore("memtimeoutstore", memtimeoutstore.New())
+	})
+}
+
+func TestEventStreamer(t *testing.T) {
+	adaptertest.RunEventStreamerTest(t, instrumented.NewEventStreamer("memstreamer", memstreamer.New()))
+}
+
+func TestRecordStoreMetrics(t *testing.T) {
+	metrics.AdapterErrors.Reset()
+	metrics.AdapterLatency.Reset()
+	metrics.AdapterPayloadSize.Reset()
+
+	ctx := context.Background()
+	store := instrumented.NewRecordStore("test", memrecordstore.New())
+
+	err := store.Store(ctx, &workflow.Record{
+		WorkflowName: "example",
+		ForeignID:    "foreign-id",
+		RunID:        "run-id",
+		RunState:     workflow.RunStateInitiated,
+		Object:       []byte("object"),
+	})
+	require.Nil(t, err)
+
+	_, err = store.Lookup(ctx, "run-id")
+	require.Nil(t, err)
+
+	_, err = store.Lookup(ctx, "missing")
+	require.ErrorIs(t, err, workflow.ErrRecordNotFound)
+
+	require.Equal(t, 2, testutil.CollectAndCount(metrics.AdapterLatency))
+	require.Equal(t, 2, testutil.CollectAndCount(metrics.AdapterPayloadSize))
+	require.Equal(t, float64(1), testutil.ToFloat64(metrics.AdapterErrors.WithLabelValues("test", "lookup")))
+	require.Equal(t, float64(0), testutil.ToFloat64(metrics.AdapterErrors.WithLabelValues("test", "store")))
+}
+
+func TestUnwrap(t *testing.T) {
+	store := memrecordstore.New()
+	require.Equal(t, workflow.RecordStore(store), instrumented.NewRecordStore("test", store).Unwrap())
+}
@@ -0,0 +1,118 @@
+package instrumented
+
+import (
+	"context"
+	"time"
+
+	"github.com/luno/workflow"
+)
+
+// NewRecordStore wraps the RecordStore so that every operation records its latency, errors, and the size of the
+// record objects written or read. The workflow unwraps the RecordStore, using Unwrap, to find the optional interfaces
+// of the underlying RecordStore such as workflow.DefinitionStore.
+func NewRecordStore(name string, store workflow.RecordStore) *RecordStore {
+	return &RecordStore{
+		name:  name,
+		store: store,
+	}
+}
+
+type RecordStore struct {
+	name  string
+	store workflow.RecordStore
+}
+
+func (s *RecordStore) Store(ctx context.Context, record *workflow.Record) error {
+	t0 := time.Now()
+	err := s.store.Store(ctx, record)
+	observe(s.name, "store", t0, err)
+	if err != nil {
+		return err
+	}
+
+	observeSize(s.name, "store", len(record.Object))
+	return nil
+}
+
+func (s *RecordStore) Lookup(ctx context.Context, runID string) (*workflow.Record, error) {
+	t0 := time.Now()
+	record, err := s.store.Lookup(ctx, runID)
+	observe(s.name, "lookup", t0, err)
+	if err != nil {
+		return nil, err
+	}
+
+	observeSize(s.name, "lookup", len(record.Object))
+	return record, nil
+}
+
+func (s *RecordStore) Latest(ctx context.Context, workflowName, foreignID string) (*workflow.Record, error) {
+	t0 := time.Now()
+	record, err := s.store.Latest(ctx, workflowName, foreignID)
+	observe(s.name, "latest", t0, err)
+	if err != nil {
+		return nil, err
+	}
+
+	observeSize(s.name, "latest", len(record.Object))
+	return record, nil
+}
+
+func (s *RecordStore) List(
+	ctx context.Context,
+	workflowName string,
+	offsetID int64,
+	limit int,
+	order workflow.OrderType,
+	filters ...workflow.RecordFilter,
+) ([]workflow.Record, error) {
+	t0 := time.Now()
+	records, err := s.store.List(ctx, workflowName, offsetID, limit, order, filters...)
+	observe(s.name, "list", t0, err)
+	if err != nil {
+		return nil, err
+	}
+
+	var size int
+	for _, record := range records {
+		size += len(record.Object)
+	}
+
+	observeSize(s.name, "list", size)
+	return records, nil
+}
+
+func (s *RecordStore) ListOutboxEvents(
+	ctx context.Context,
+	workflowName string,
+	limit int64,
+) ([]workflow.OutboxEvent, error) {
+	t0 := time.Now()
+	events, err := s.store.ListOutboxEvents(ctx, workflowName, limit)
+	observe(s.name, "list_outbox_events", t0, err)
+	if err != nil {
+		return nil, err
+	}
+
+	var size int
+	for _, e := range events {
+		size += len(e.Data)
+	}
+
+	observeSize(s.name, "list_outbox_events", size)
+	return events, nil
+}
+
+func (s *RecordStore) DeleteOutboxEvent(ctx context.Context, id string) error {
+	t0 := time.Now()
+	err := s.store.DeleteOutboxEvent(ctx, id)
+	observe(s.name, "delete_outbox_event", t0, err)
+	return err
+}
+
+// Unwrap returns the RecordStore that is being instrumented.
+func (s *RecordStore) Unwrap() workflow.RecordStore {
+	return s.store
+}
+
+var _ workflow.RecordStore = (*RecordStore)(nil)
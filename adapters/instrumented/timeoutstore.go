@@ -0,0 +1,74 @@
+package instrumented
+
+import (
+	"context"
+	"time"
+
+	"github.com/luno/workflow"
+)
+
+// NewTimeoutStore wraps the TimeoutStore so that every operation records its latency and errors.
+func NewTimeoutStore(name string, store workflow.TimeoutStore) *TimeoutStore {
+	return &TimeoutStore{
+		name:  name,
+		store: store,
+	}
+}
+
+type TimeoutStore struct {
+	name  string
+	store workflow.TimeoutStore
+}
+
+func (s *TimeoutStore) Create(
+	ctx context.Context,
+	workflowName, foreignID, runID string,
+	status int,
+	expireAt time.Time,
+) error {
+	t0 := time.Now()
+	err := s.store.Create(ctx, workflowName, foreignID, runID, status, expireAt)
+	observe(s.name, "create", t0, err)
+	return err
+}
+
+// Unwrap returns the wrapped TimeoutStore so that its optional interfaces, such as workflow.NamedTimeoutStore, can be
+// found.
+func (s *TimeoutStore) Unwrap() workflow.TimeoutStore {
+	return s.store
+}
+
+func (s *TimeoutStore) Complete(ctx context.Context, id int64) error {
+	t0 := time.Now()
+	err := s.store.Complete(ctx, id)
+	observe(s.name, "complete", t0, err)
+	return err
+}
+
+func (s *TimeoutStore) Cancel(ctx context.Context, id int64) error {
+	t0 := time.Now()
+	err := s.store.Cancel(ctx, id)
+	observe(s.name, "cancel", t0, err)
+	return err
+}
+
+func (s *TimeoutStore) List(ctx context.Context, workflowName string) ([]workflow.TimeoutRecord, error) {
+	t0 := time.Now()
+	timeouts, err := s.store.List(ctx, workflowName)
+	observe(s.name, "list", t0, err)
+	return timeouts, err
+}
+
+func (s *TimeoutStore) ListValid(
+	ctx context.Context,
+	workflowName string,
+	status int,
+	now time.Time,
+) ([]workflow.TimeoutRecord, error) {
+	t0 := time.Now()
+	timeouts, err := s.store.ListValid(ctx, workflowName, status, now)
+	observe(s.name, "list_valid", t0, err)
+	return timeouts, err
+}
+
+var _ workflow.TimeoutStore = (*TimeoutStore)(nil)
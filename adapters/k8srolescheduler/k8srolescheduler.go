@@ -0,0 +1,188 @@
+// Package k8srolescheduler provides a RoleScheduler that coordinates the ownership of roles across instances using
+// coordination.k8s.io Lease objects and so follows native Kubernetes leader election. Each role is held by the
+// instance whose identity is the holder of the role's Lease and the Lease is renewed whilst the role is held.
+package k8srolescheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+
+	"github.com/luno/workflow"
+)
+
+// maxNameLength is the maximum length of the name of a Lease which is a DNS subdomain name.
+const maxNameLength = 253
+
+type options struct {
+	identity      string
+	leaseDuration time.Duration
+	renewDeadline time.Duration
+	retryPeriod   time.Duration
+	namePrefix    string
+}
+
+type Option func(o *options)
+
+// WithIdentity sets the identity that is recorded as the holder of the Leases of the roles held by the instance. The
+// identity must be unique to the instance and defaults to the hostname, which is the name of the pod when running in
+// Kubernetes.
+func WithIdentity(identity string) Option {
+	return func(o *options) {
+		o.identity = identity
+	}
+}
+
+// WithLeaseDuration sets how long other instances wait to take over a role whose Lease has not been renewed. The
+// default is 15 seconds.
+func WithLeaseDuration(d time.Duration) Option {
+	return func(o *options) {
+		o.leaseDuration = d
+	}
+}
+
+// WithRenewDeadline sets how long the holder of a role keeps trying to renew its Lease before giving up the role. It
+// must be less than the lease duration and the default is 10 seconds.
+func WithRenewDeadline(d time.Duration) Option {
+	return func(o *options) {
+		o.renewDeadline = d
+	}
+}
+
+// WithRetryPeriod sets how often Leases are acquired and renewed. The default is 2 seconds.
+func WithRetryPeriod(d time.Duration) Option {
+	return func(o *options) {
+		o.retryPeriod = d
+	}
+}
+
+// WithNamePrefix sets the prefix of the names of the Leases which allows multiple deployments to share the same
+// namespace. The default is "workflow-".
+func WithNamePrefix(prefix string) Option {
+	return func(o *options) {
+		o.namePrefix = prefix
+	}
+}
+
+// New returns a RoleScheduler that stores the Leases of its roles in the namespace.
+func New(client kubernetes.Interface, namespace string, opts ...Option) *RoleScheduler {
+	hostname, _ := os.Hostname()
+	o := options{
+		identity:      hostname,
+		leaseDuration: 15 * time.Second,
+		renewDeadline: 10 * time.Second,
+		retryPeriod:   2 * time.Second,
+		namePrefix:    "workflow-",
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &RoleScheduler{
+		client:    client,
+		namespace: namespace,
+		opts:      o,
+	}
+}
+
+type RoleScheduler struct {
+	client    kubernetes.Interface
+	namespace string
+	opts      options
+}
+
+var _ workflow.RoleScheduler = (*RoleScheduler)(nil)
+
+func (r *RoleScheduler) Await(ctx context.Context, role string) (context.Context, context.CancelFunc, error) {
+	if r.opts.identity == "" {
+		return nil, nil, errors.New("kubernetes role scheduler requires an identity")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	started := make(chan context.Context, 1)
+
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock: &resourcelock.LeaseLock{
+			LeaseMeta: metav1.ObjectMeta{
+				Name:      leaseName(r.opts.namePrefix, role),
+				Namespace: r.namespace,
+			},
+			Client: r.client.CoordinationV1(),
+			LockConfig: resourcelock.ResourceLockConfig{
+				Identity: r.opts.identity,
+			},
+		},
+		LeaseDuration:   r.opts.leaseDuration,
+		RenewDeadline:   r.opts.renewDeadline,
+		RetryPeriod:     r.opts.retryPeriod,
+		ReleaseOnCancel: true,
+		Name:            role,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				started <- ctx
+			},
+			// The role is given up when the Lease can no longer be renewed.
+			OnStoppedLeading: cancel,
+		},
+	})
+	if err != nil {
+		cancel()
+		return nil, nil, fmt.Errorf("new leader elector: %w, meta: %v", err, map[string]string{
+			"role": role,
+		})
+	}
+
+	go func() {
+		// Run returns once the context is cancelled or the role is lost, after releasing the Lease if it was held.
+		elector.Run(ctx)
+		cancel()
+	}()
+
+	select {
+	case <-ctx.Done():
+		cancel()
+		return nil, nil, ctx.Err()
+	case leaderCtx := <-started:
+		return leaderCtx, cancel, nil
+	}
+}
+
+// leaseName returns the name of the role's Lease. Roles are not always valid names of Kubernetes objects and so
+// invalid characters are replaced and a hash of the role is appended to keep the names of different roles unique.
+func leaseName(prefix, role string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '.':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		default:
+			return '-'
+		}
+	}, prefix+role)
+
+	if name == prefix+role && len(name) <= maxNameLength {
+		return name
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(role))
+	suffix := fmt.Sprintf("-%08x", h.Sum32())
+
+	name = strings.TrimRight(name, "-.")
+	if len(name)+len(suffix) > maxNameLength {
+		name = strings.TrimRight(name[:maxNameLength-len(suffix)], "-.")
+	}
+
+	return name + suffix
+}
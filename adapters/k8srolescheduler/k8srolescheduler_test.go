@@ -0,0 +1,77 @@
+package k8srolescheduler_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/adaptertest"
+	"github.com/luno/workflow/adapters/k8srolescheduler"
+)
+
+func TestRoleScheduler(t *testing.T) {
+	adaptertest.RunRoleSchedulerTest(t, func(t *testing.T, instances int) []workflow.RoleScheduler {
+		client := fake.NewSimpleClientset()
+
+		var rs []workflow.RoleScheduler
+		for i := range instances {
+			rs = append(rs, newRoleScheduler(client, "instance-"+strconv.Itoa(i)))
+		}
+
+		return rs
+	})
+}
+
+func TestRoleScheduler_lease(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	first := newRoleScheduler(client, "first")
+	second := newRoleScheduler(client, "second")
+
+	ctx := context.Background()
+	_, cancel, err := first.Await(ctx, "My Workflow-1-consumer_name")
+	require.Nil(t, err)
+
+	lease, err := client.CoordinationV1().Leases("workflow").Get(ctx, "test-my-workflow-1-consumer-name-673b3dc5", metav1.GetOptions{})
+	require.Nil(t, err)
+	require.Equal(t, "first", *lease.Spec.HolderIdentity)
+
+	// The role is assigned to the second instance once the first instance releases it.
+	acquired := make(chan struct{})
+	go func() {
+		_, _, err := second.Await(ctx, "My Workflow-1-consumer_name")
+		require.Nil(t, err)
+		close(acquired)
+	}()
+
+	select {
+	case <-acquired:
+		require.FailNow(t, "role assigned to more than one instance")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	cancel()
+
+	select {
+	case <-acquired:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "role not released")
+	}
+}
+
+func newRoleScheduler(client *fake.Clientset, identity string) *k8srolescheduler.RoleScheduler {
+	return k8srolescheduler.New(
+		client,
+		"workflow",
+		k8srolescheduler.WithIdentity(identity),
+		k8srolescheduler.WithLeaseDuration(time.Second),
+		k8srolescheduler.WithRenewDeadline(500*time.Millisecond),
+		k8srolescheduler.WithRetryPeriod(20*time.Millisecond),
+		k8srolescheduler.WithNamePrefix("test-"),
+	)
+}
@@ -2,6 +2,8 @@ package kafkastreamer
 
 import (
 	"context"
+	"errors"
+	"strconv"
 
 	"github.com/segmentio/kafka-go"
 
@@ -17,6 +19,25 @@ func NewConnector(c kafka.ReaderConfig, t Translator) *connector {
 
 type Translator func(m kafka.Message) workflow.ConnectorEvent
 
+// DefaultTranslator translates a Kafka message into a ConnectorEvent with the message's key as the ForeignID, its topic
+// as the Source, and its value as the Payload.
+func DefaultTranslator(m kafka.Message) workflow.ConnectorEvent {
+	headers := make(map[string]string, len(m.Headers))
+	for _, h := range m.Headers {
+		headers[h.Key] = string(h.Value)
+	}
+
+	return workflow.ConnectorEvent{
+		ID:        m.Topic + "-" + strconv.Itoa(m.Partition) + "-" + strconv.FormatInt(m.Offset, 10),
+		ForeignID: string(m.Key),
+		Type:      m.Topic,
+		Headers:   headers,
+		CreatedAt: m.Time,
+		Source:    m.Topic,
+		Payload:   m.Value,
+	}
+}
+
 type connector struct {
 	translator Translator
 	config     kafka.ReaderConfig
@@ -33,6 +54,25 @@ func (c *connector) Make(ctx context.Context, name string) (workflow.ConnectorCo
 	}, nil
 }
 
+// ResetConnectorCursor moves the offsets of the consumer group of the consumer on the connector's topic to the
+// position.
+func (c *connector) ResetConnectorCursor(
+	ctx context.Context,
+	consumerName string,
+	position workflow.CursorPosition,
+) error {
+	if c.config.Topic == "" {
+		return errors.New("resetting connector cursors requires the reader config's Topic")
+	}
+
+	return StreamConstructor{brokers: c.config.Brokers}.ResetCursor(ctx, workflow.Cursor{
+		Topic: c.config.Topic,
+		Name:  consumerName,
+	}, position)
+}
+
+var _ workflow.ConnectorCursorManager = (*connector)(nil)
+
 type consumer struct {
 	name       string
 	translator Translator
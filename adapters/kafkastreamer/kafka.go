@@ -3,7 +3,10 @@ package kafkastreamer
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/luno/workflow"
@@ -22,47 +25,295 @@ type StreamConstructor struct {
 	brokers []string
 }
 
+// ProvisionTopics creates the topics that do not exist yet with the configured number of partitions and retention.
+// The broker's default replication factor is used.
+func (s StreamConstructor) ProvisionTopics(ctx context.Context, topics []workflow.TopicConfig) error {
+	if len(s.brokers) == 0 {
+		return errors.New("no brokers configured")
+	}
+
+	conn, err := kafka.DialContext(ctx, "tcp", s.brokers[0])
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// Topics can only be created by the controller of the cluster.
+	controller, err := conn.Controller()
+	if err != nil {
+		return err
+	}
+
+	controllerConn, err := kafka.DialContext(
+		ctx,
+		"tcp",
+		net.JoinHostPort(controller.Host, strconv.Itoa(controller.Port)),
+	)
+	if err != nil {
+		return err
+	}
+	defer controllerConn.Close()
+
+	var configs []kafka.TopicConfig
+	for _, topic := range topics {
+		config := kafka.TopicConfig{
+			Topic:             topic.Name,
+			NumPartitions:     topic.Partitions,
+			ReplicationFactor: -1,
+		}
+
+		if topic.Retention > 0 {
+			config.ConfigEntries = append(config.ConfigEntries, kafka.ConfigEntry{
+				ConfigName:  "retention.ms",
+				ConfigValue: strconv.FormatInt(topic.Retention.Milliseconds(), 10),
+			})
+		}
+
+		configs = append(configs, config)
+	}
+
+	// CreateTopics does not modify topics that already exist.
+	return controllerConn.CreateTopics(configs...)
+}
+
+var _ workflow.TopicProvisioner = (*StreamConstructor)(nil)
+
+// CursorOffset returns the sum of the committed offsets of each partition of the topic for the consumer group of
+// the cursor.
+func (s StreamConstructor) CursorOffset(ctx context.Context, cursor workflow.Cursor) (workflow.CursorOffset, error) {
+	client := s.client()
+	partitions, err := s.partitions(ctx, client, cursor.Topic)
+	if err != nil {
+		return workflow.CursorOffset{}, err
+	}
+
+	committed, err := committedOffsets(ctx, client, cursor, partitions)
+	if err != nil {
+		return workflow.CursorOffset{}, err
+	}
+
+	var requests []kafka.OffsetRequest
+	for _, partition := range partitions {
+		requests = append(requests, kafka.FirstOffsetOf(partition), kafka.LastOffsetOf(partition))
+	}
+
+	resp, err := client.ListOffsets(ctx, &kafka.ListOffsetsRequest{
+		Topics: map[string][]kafka.OffsetRequest{cursor.Topic: requests},
+	})
+	if err != nil {
+		return workflow.CursorOffset{}, err
+	}
+
+	offset := workflow.CursorOffset{Cursor: cursor}
+	for _, partition := range resp.Topics[cursor.Topic] {
+		if partition.Error != nil {
+			return workflow.CursorOffset{}, partition.Error
+		}
+
+		// Consumer groups without a committed offset start consuming from the first offset of the partition.
+		position, ok := committed[partition.Partition]
+		if !ok || position < partition.FirstOffset {
+			position = partition.FirstOffset
+		}
+
+		offset.Offset += position
+		offset.Lag += partition.LastOffset - position
+	}
+
+	return offset, nil
+}
+
+// ResetCursor commits the offset of the position for each partition of the topic. Kafka rejects the commit when the
+// consumer group has active members and so all the consumers of the cursor must be stopped first.
+func (s StreamConstructor) ResetCursor(
+	ctx context.Context,
+	cursor workflow.Cursor,
+	position workflow.CursorPosition,
+) error {
+	client := s.client()
+	partitions, err := s.partitions(ctx, client, cursor.Topic)
+	if err != nil {
+		return err
+	}
+
+	var requests []kafka.OffsetRequest
+	for _, partition := range partitions {
+		switch position.Type {
+		case workflow.CursorPositionEarliest:
+			requests = append(requests, kafka.FirstOffsetOf(partition))
+		case workflow.CursorPositionLatest:
+			requests = append(requests, kafka.LastOffsetOf(partition))
+		case workflow.CursorPositionTimestamp:
+			requests = append(requests, kafka.TimeOffsetOf(partition, position.Timestamp), kafka.LastOffsetOf(partition))
+		default:
+			return fmt.Errorf("unknown cursor position type: %v", position.Type)
+		}
+	}
+
+	resp, err := client.ListOffsets(ctx, &kafka.ListOffsetsRequest{
+		Topics: map[string][]kafka.OffsetRequest{cursor.Topic: requests},
+	})
+	if err != nil {
+		return err
+	}
+
+	var commits []kafka.OffsetCommit
+	for _, partition := range resp.Topics[cursor.Topic] {
+		if partition.Error != nil {
+			return partition.Error
+		}
+
+		var offset int64
+		switch position.Type {
+		case workflow.CursorPositionEarliest:
+			offset = partition.FirstOffset
+		case workflow.CursorPositionLatest:
+			offset = partition.LastOffset
+		case workflow.CursorPositionTimestamp:
+			// Partitions without messages at, or after, the timestamp are reset to the end of the partition.
+			offset = partition.LastOffset
+			for o := range partition.Offsets {
+				if o >= 0 && o < offset {
+					offset = o
+				}
+			}
+		}
+
+		commits = append(commits, kafka.OffsetCommit{
+			Partition: partition.Partition,
+			Offset:    offset,
+		})
+	}
+
+	commitResp, err := client.OffsetCommit(ctx, &kafka.OffsetCommitRequest{
+		GroupID:      cursor.Name,
+		GenerationID: -1,
+		Topics:       map[string][]kafka.OffsetCommit{cursor.Topic: commits},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, partition := range commitResp.Topics[cursor.Topic] {
+		if partition.Error != nil {
+			return partition.Error
+		}
+	}
+
+	return nil
+}
+
+var _ workflow.CursorManager = (*StreamConstructor)(nil)
+
+func (s StreamConstructor) client() *kafka.Client {
+	return &kafka.Client{
+		Addr: kafka.TCP(s.brokers...),
+	}
+}
+
+func (s StreamConstructor) partitions(ctx context.Context, client *kafka.Client, topic string) ([]int, error) {
+	resp, err := client.Metadata(ctx, &kafka.MetadataRequest{
+		Topics: []string{topic},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var partitions []int
+	for _, t := range resp.Topics {
+		if t.Error != nil {
+			return nil, t.Error
+		}
+
+		for _, partition := range t.Partitions {
+			partitions = append(partitions, partition.ID)
+		}
+	}
+
+	return partitions, nil
+}
+
+func committedOffsets(
+	ctx context.Context,
+	client *kafka.Client,
+	cursor workflow.Cursor,
+	partitions []int,
+) (map[int]int64, error) {
+	resp, err := client.OffsetFetch(ctx, &kafka.OffsetFetchRequest{
+		GroupID: cursor.Name,
+		Topics:  map[string][]int{cursor.Topic: partitions},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Error != nil {
+		return nil, resp.Error
+	}
+
+	committed := make(map[int]int64)
+	for _, partition := range resp.Topics[cursor.Topic] {
+		if partition.Error != nil {
+			return nil, partition.Error
+		}
+
+		// A negative offset indicates that the consumer group has not committed an offset for the partition.
+		if partition.CommittedOffset >= 0 {
+			committed[partition.Partition] = partition.CommittedOffset
+		}
+	}
+
+	return committed, nil
+}
+
+// NewSender returns a sender that partitions the events by their foreign ID, which is the ID of the run, so that the
+// events of a run are consumed in order and by the same shard.
 func (s StreamConstructor) NewSender(ctx context.Context, topic string) (workflow.EventSender, error) {
-	return &Sender{
+	sender := &Sender{
 		Topic: topic,
 		Writer: &kafka.Writer{
 			Addr:                   kafka.TCP(s.brokers...),
 			Topic:                  topic,
+			Balancer:               &kafka.Hash{},
 			AllowAutoTopicCreation: true,
 			RequiredAcks:           kafka.RequireOne,
 		},
 		WriterTimeout: time.Second * 10,
-	}, nil
+	}
+
+	sender.asyncWriter = &kafka.Writer{
+		Addr:                   kafka.TCP(s.brokers...),
+		Topic:                  topic,
+		Balancer:               &kafka.Hash{},
+		AllowAutoTopicCreation: true,
+		RequiredAcks:           kafka.RequireOne,
+		Async:                  true,
+		BatchTimeout:           10 * time.Millisecond,
+		Completion:             sender.complete,
+	}
+
+	return sender, nil
 }
 
 type Sender struct {
 	Topic         string
 	Writer        *kafka.Writer
 	WriterTimeout time.Duration
+
+	// asyncWriter is used by SendAsync so that the outbox does not wait for each event to be acknowledged by the
+	// broker before sending the next.
+	asyncWriter *kafka.Writer
+	pending     sync.WaitGroup
 }
 
-var _ workflow.EventSender = (*Sender)(nil)
+var _ workflow.AsyncEventSender = (*Sender)(nil)
 
 func (p *Sender) Send(ctx context.Context, foreignID string, statusType int, headers map[workflow.Header]string) error {
 	for ctx.Err() == nil {
 		ctx, cancel := context.WithTimeout(ctx, p.WriterTimeout)
 		defer cancel()
 
-		var kHeaders []kafka.Header
-		for key, value := range headers {
-			kHeaders = append(kHeaders, kafka.Header{
-				Key:   string(key),
-				Value: []byte(value),
-			})
-		}
-
-		msg := kafka.Message{
-			Key:     []byte(foreignID),
-			Value:   []byte(strconv.FormatInt(int64(statusType), 10)),
-			Headers: kHeaders,
-		}
-
-		err := p.Writer.WriteMessages(ctx, msg)
+		err := p.Writer.WriteMessages(ctx, newMessage(foreignID, statusType, headers))
 		if errors.Is(err, kafka.LeaderNotAvailable) || errors.Is(err, context.DeadlineExceeded) {
 			time.Sleep(time.Millisecond * 250)
 			continue
@@ -76,16 +327,106 @@ func (p *Sender) Send(ctx context.Context, foreignID string, statusType int, hea
 	return ctx.Err()
 }
 
+func (p *Sender) SendAsync(
+	ctx context.Context,
+	foreignID string,
+	statusType int,
+	headers map[workflow.Header]string,
+	callback workflow.DeliveryCallback,
+) error {
+	msg := newMessage(foreignID, statusType, headers)
+	msg.WriterData = callback
+
+	p.pending.Add(1)
+	err := p.asyncWriter.WriteMessages(ctx, msg)
+	if err != nil {
+		// The Completion func is not called for messages that fail to be queued.
+		p.pending.Done()
+		return err
+	}
+
+	return nil
+}
+
+func (p *Sender) complete(messages []kafka.Message, err error) {
+	for _, msg := range messages {
+		if callback, ok := msg.WriterData.(workflow.DeliveryCallback); ok {
+			callback(err)
+		}
+
+		p.pending.Done()
+	}
+}
+
+func (p *Sender) Flush(ctx context.Context) error {
+	flushed := make(chan struct{})
+	go func() {
+		p.pending.Wait()
+		close(flushed)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-flushed:
+		return nil
+	}
+}
+
 func (p *Sender) Close() error {
+	// Closing the async writer blocks until all queued messages have been written and their callbacks called.
+	err := p.asyncWriter.Close()
+	if err != nil {
+		return err
+	}
+
 	return p.Writer.Close()
 }
 
+func newMessage(foreignID string, statusType int, headers map[workflow.Header]string) kafka.Message {
+	var kHeaders []kafka.Header
+	for key, value := range headers {
+		kHeaders = append(kHeaders, kafka.Header{
+			Key:   string(key),
+			Value: []byte(value),
+		})
+	}
+
+	return kafka.Message{
+		Key:     []byte(foreignID),
+		Value:   []byte(strconv.FormatInt(int64(statusType), 10)),
+		Headers: kHeaders,
+	}
+}
+
 func (s StreamConstructor) NewReceiver(
 	ctx context.Context,
 	topic string,
 	name string,
 	opts ...workflow.ReceiverOption,
 ) (workflow.EventReceiver, error) {
+	return s.newReceiver([]string{topic}, name, opts...), nil
+}
+
+// NewMultiplexedReceiver returns a receiver whose consumer group consumes all the topics using a single reader.
+func (s StreamConstructor) NewMultiplexedReceiver(
+	ctx context.Context,
+	topics []string,
+	name string,
+	opts ...workflow.ReceiverOption,
+) (workflow.EventReceiver, error) {
+	if len(topics) == 0 {
+		return nil, errors.New("multiplexed receiver requires at least one topic")
+	}
+
+	receiver := s.newReceiver(topics, name, opts...)
+	receiver.multiplexed = true
+	return receiver, nil
+}
+
+var _ workflow.MultiplexedEventStreamer = (*StreamConstructor)(nil)
+
+func (s StreamConstructor) newReceiver(topics []string, name string, opts ...workflow.ReceiverOption) *Receiver {
 	var copts workflow.ReceiverOptions
 	for _, opt := range opts {
 		opt(&copts)
@@ -93,32 +434,52 @@ func (s StreamConstructor) NewReceiver(
 
 	startOffset := kafka.FirstOffset
 
-	kafkaReader := kafka.NewReader(kafka.ReaderConfig{
+	// The reader backs off exponentially from ReadBackoffMin to ReadBackoffMax while there are no new messages.
+	readBackoffMax := copts.PollFrequency
+	if copts.MaxPollFrequency > readBackoffMax {
+		readBackoffMax = copts.MaxPollFrequency
+	}
+
+	config := kafka.ReaderConfig{
 		Brokers:        s.brokers,
 		GroupID:        name,
-		Topic:          topic,
 		ReadBackoffMin: copts.PollFrequency,
-		ReadBackoffMax: copts.PollFrequency,
+		ReadBackoffMax: readBackoffMax,
 		StartOffset:    startOffset,
 		QueueCapacity:  1000,
 		MinBytes:       10,  // 10B
 		MaxBytes:       1e9, // 9MB
 		MaxWait:        time.Second,
-	})
+	}
+
+	if len(topics) == 1 {
+		config.Topic = topics[0]
+	} else {
+		config.GroupTopics = topics
+	}
+
+	// Shards of a step consumer are assigned a subset of the topic's partitions instead of each shard receiving all
+	// the events and filtering out the events of the other shards.
+	if copts.TotalShards > 1 {
+		config.GroupBalancers = []kafka.GroupBalancer{
+			shardBalancer{shard: copts.Shard, totalShards: copts.TotalShards},
+		}
+	}
 
 	return &Receiver{
-		topic:   topic,
+		topics:  topics,
 		name:    name,
-		reader:  kafkaReader,
+		reader:  kafka.NewReader(config),
 		options: copts,
-	}, nil
+	}
 }
 
 type Receiver struct {
-	topic   string
-	name    string
-	reader  *kafka.Reader
-	options workflow.ReceiverOptions
+	topics      []string
+	name        string
+	reader      *kafka.Reader
+	options     workflow.ReceiverOptions
+	multiplexed bool
 }
 
 func (r *Receiver) Recv(ctx context.Context) (*workflow.Event, workflow.Ack, error) {
@@ -142,6 +503,11 @@ func (r *Receiver) Recv(ctx context.Context) (*workflow.Event, workflow.Ack, err
 			headers[workflow.Header(header.Key)] = string(header.Value)
 		}
 
+		// The events of a multiplexed receiver are routed using the topic that they were consumed from.
+		if r.multiplexed {
+			headers[workflow.HeaderTopic] = m.Topic
+		}
+
 		event := &workflow.Event{
 			ID:        m.Offset,
 			ForeignID: string(m.Key),
@@ -160,8 +526,13 @@ func (r *Receiver) Recv(ctx context.Context) (*workflow.Event, workflow.Ack, err
 	return nil, nil, ctx.Err()
 }
 
+// Sharded returns true when the receiver is only assigned the partitions of its shard.
+func (r *Receiver) Sharded() bool {
+	return r.options.TotalShards > 1
+}
+
 func (r *Receiver) Close() error {
 	return r.reader.Close()
 }
 
-var _ workflow.EventReceiver = (*Receiver)(nil)
+var _ workflow.ShardedReceiver = (*Receiver)(nil)
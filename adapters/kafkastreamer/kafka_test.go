@@ -33,15 +33,7 @@ func TestConnector(t *testing.T) {
 		MaxBytes:       1e9, // 9MB
 		MaxWait:        time.Second,
 	}
-	translator := func(m kafka.Message) workflow.ConnectorEvent {
-		return workflow.ConnectorEvent{
-			ID:        strconv.FormatInt(m.Offset, 10),
-			ForeignID: string(m.Key),
-			Type:      m.Topic,
-			CreatedAt: m.Time,
-		}
-	}
-	constructor := kafkastreamer.NewConnector(config, translator)
+	constructor := kafkastreamer.NewConnector(config, kafkastreamer.DefaultTranslator)
 	adaptertest.RunConnectorTest(t, func(seedEvents []workflow.ConnectorEvent) workflow.ConnectorConstructor {
 		writer := &kafka.Writer{
 			Addr:                   kafka.TCP(brokerAddress),
@@ -64,3 +56,30 @@ func TestConnector(t *testing.T) {
 		return constructor
 	})
 }
+
+func TestProvisionTopics(t *testing.T) {
+	ctx := context.Background()
+	constructor := kafkastreamer.New([]string{brokerAddress})
+
+	topic := "test-provision-topic-" + strconv.FormatInt(time.Now().UnixNano(), 10)
+	topics := []workflow.TopicConfig{
+		{Name: topic, Partitions: 3, Retention: time.Hour},
+	}
+
+	err := constructor.ProvisionTopics(ctx, topics)
+	require.Nil(t, err)
+
+	// Provisioning topics that already exist must succeed.
+	err = constructor.ProvisionTopics(ctx, topics)
+	require.Nil(t, err)
+
+	conn, err := kafka.DialContext(ctx, "tcp", brokerAddress)
+	require.Nil(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	partitions, err := conn.ReadPartitions(topic)
+	require.Nil(t, err)
+	require.Len(t, partitions, 3)
+}
@@ -0,0 +1,57 @@
+package kafkastreamer
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// shardBalancer assigns the partitions of the topic to a shard of a step consumer configured with a ParallelCount
+// greater than 1. Each shard has its own consumer group and so the shard's receiver is the only member of the group
+// and is assigned the partitions whose index modulo the total number of shards matches the shard. The topic needs at
+// least as many partitions as there are shards for every shard to receive events which is ensured when the topics
+// are created using ProvisionTopics.
+type shardBalancer struct {
+	shard       int
+	totalShards int
+}
+
+func (b shardBalancer) ProtocolName() string {
+	return "workflow-shard-" + strconv.Itoa(b.shard) + "-of-" + strconv.Itoa(b.totalShards)
+}
+
+func (b shardBalancer) UserData() ([]byte, error) {
+	return nil, nil
+}
+
+func (b shardBalancer) AssignGroups(
+	members []kafka.GroupMember,
+	partitions []kafka.Partition,
+) kafka.GroupMemberAssignments {
+	assignments := make(kafka.GroupMemberAssignments)
+	if len(members) == 0 {
+		return assignments
+	}
+
+	// The RoleScheduler ensures that a shard is only consumed by a single receiver. Should a previous receiver not
+	// have left the group yet then all the partitions are assigned to a single member to keep consuming in order.
+	ids := make([]string, 0, len(members))
+	for _, member := range members {
+		ids = append(ids, member.ID)
+		assignments[member.ID] = make(map[string][]int)
+	}
+	sort.Strings(ids)
+
+	for _, partition := range partitions {
+		if partition.ID%b.totalShards != b.shard-1 {
+			continue
+		}
+
+		assignments[ids[0]][partition.Topic] = append(assignments[ids[0]][partition.Topic], partition.ID)
+	}
+
+	return assignments
+}
+
+var _ kafka.GroupBalancer = shardBalancer{}
@@ -0,0 +1,63 @@
+package kafkastreamer
+
+import (
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShardBalancer(t *testing.T) {
+	var partitions []kafka.Partition
+	for i := 0; i < 5; i++ {
+		partitions = append(partitions, kafka.Partition{Topic: "topic", ID: i})
+	}
+
+	testCases := []struct {
+		name     string
+		balancer shardBalancer
+		members  []kafka.GroupMember
+		expected kafka.GroupMemberAssignments
+	}{
+		{
+			name:     "First shard",
+			balancer: shardBalancer{shard: 1, totalShards: 2},
+			members:  []kafka.GroupMember{{ID: "a"}},
+			expected: kafka.GroupMemberAssignments{
+				"a": {"topic": {0, 2, 4}},
+			},
+		},
+		{
+			name:     "Last shard",
+			balancer: shardBalancer{shard: 2, totalShards: 2},
+			members:  []kafka.GroupMember{{ID: "a"}},
+			expected: kafka.GroupMemberAssignments{
+				"a": {"topic": {1, 3}},
+			},
+		},
+		{
+			name:     "Shard without partitions",
+			balancer: shardBalancer{shard: 6, totalShards: 6},
+			members:  []kafka.GroupMember{{ID: "a"}},
+			expected: kafka.GroupMemberAssignments{
+				"a": {},
+			},
+		},
+		{
+			name:     "Multiple members are assigned to a single member",
+			balancer: shardBalancer{shard: 1, totalShards: 2},
+			members:  []kafka.GroupMember{{ID: "b"}, {ID: "a"}},
+			expected: kafka.GroupMemberAssignments{
+				"a": {"topic": {0, 2, 4}},
+				"b": {},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := tc.balancer.AssignGroups(tc.members, partitions)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package memblobstore
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+
+	"github.com/luno/workflow"
+)
+
+func New() *Store {
+	return &Store{
+		blobs: make(map[string][]byte),
+	}
+}
+
+var _ workflow.BlobStore = (*Store)(nil)
+
+// Store is an in-memory workflow.BlobStore for use in tests. Unlike other implementations the blobs are held in
+// memory.
+type Store struct {
+	mu    sync.Mutex
+	blobs map[string][]byte
+}
+
+func (s *Store) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.blobs[key] = b
+	return int64(len(b)), nil
+}
+
+func (s *Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.blobs[key]
+	if !ok {
+		return nil, workflow.ErrBlobNotFound
+	}
+
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
+
+func (s *Store) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.blobs, key)
+	return nil
+}
+
+// Keys returns the keys of all the blobs in the store.
+func (s *Store) Keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]string, 0, len(s.blobs))
+	for key := range s.blobs {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
@@ -0,0 +1,15 @@
+package memblobstore_test
+
+import (
+	"testing"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/adaptertest"
+	"github.com/luno/workflow/adapters/memblobstore"
+)
+
+func TestStore(t *testing.T) {
+	adaptertest.RunBlobStoreTest(t, func() workflow.BlobStore {
+		return memblobstore.New()
+	})
+}
@@ -0,0 +1,92 @@
+package memlegalholdstore
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/luno/workflow"
+)
+
+func New() *Store {
+	return &Store{
+		idIncrement: 1,
+	}
+}
+
+var _ workflow.LegalHoldStore = (*Store)(nil)
+
+type Store struct {
+	mu          sync.Mutex
+	idIncrement int64
+	holds       []*workflow.LegalHold
+}
+
+func (s *Store) Place(ctx context.Context, workflowName, foreignID, reason string, placedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.active(workflowName, foreignID) != nil {
+		return workflow.ErrLegalHoldActive
+	}
+
+	s.holds = append(s.holds, &workflow.LegalHold{
+		ID:           s.idIncrement,
+		WorkflowName: workflowName,
+		ForeignID:    foreignID,
+		Reason:       reason,
+		PlacedAt:     placedAt,
+	})
+	s.idIncrement++
+
+	return nil
+}
+
+func (s *Store) Lift(ctx context.Context, workflowName, foreignID, reason string, liftedAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hold := s.active(workflowName, foreignID)
+	if hold == nil {
+		return workflow.ErrLegalHoldNotFound
+	}
+
+	hold.LiftReason = reason
+	hold.LiftedAt = liftedAt
+
+	return nil
+}
+
+func (s *Store) List(ctx context.Context, workflowName, foreignID string) ([]workflow.LegalHold, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var ls []workflow.LegalHold
+	for _, hold := range s.holds {
+		if hold.WorkflowName != workflowName {
+			continue
+		}
+
+		if hold.ForeignID != foreignID {
+			continue
+		}
+
+		ls = append(ls, *hold)
+	}
+
+	return ls, nil
+}
+
+func (s *Store) active(workflowName, foreignID string) *workflow.LegalHold {
+	for _, hold := range s.holds {
+		if hold.WorkflowName != workflowName || hold.ForeignID != foreignID {
+			continue
+		}
+
+		if hold.Active() {
+			return hold
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,15 @@
+package memlegalholdstore_test
+
+import (
+	"testing"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/adaptertest"
+	"github.com/luno/workflow/adapters/memlegalholdstore"
+)
+
+func TestStore(t *testing.T) {
+	adaptertest.RunLegalHoldStoreTest(t, func() workflow.LegalHoldStore {
+		return memlegalholdstore.New()
+	})
+}
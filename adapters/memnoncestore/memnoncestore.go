@@ -0,0 +1,61 @@
+package memnoncestore
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
+
+	"github.com/luno/workflow"
+)
+
+func New(opts ...Option) *Store {
+	s := &Store{
+		clock:  clock.RealClock{},
+		nonces: make(map[string]time.Time),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+type Option func(s *Store)
+
+func WithClock(c clock.Clock) Option {
+	return func(s *Store) {
+		s.clock = c
+	}
+}
+
+var _ workflow.NonceStore = (*Store)(nil)
+
+// Store is an in-memory workflow.NonceStore for use in tests. Expired nonces are removed when nonces are claimed.
+type Store struct {
+	clock clock.Clock
+
+	mu     sync.Mutex
+	nonces map[string]time.Time
+}
+
+func (s *Store) Claim(ctx context.Context, nonce string, expireAt time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	for n, at := range s.nonces {
+		if !at.After(now) {
+			delete(s.nonces, n)
+		}
+	}
+
+	if _, ok := s.nonces[nonce]; ok {
+		return false, nil
+	}
+
+	s.nonces[nonce] = expireAt
+	return true, nil
+}
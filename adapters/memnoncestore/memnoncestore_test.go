@@ -0,0 +1,15 @@
+package memnoncestore_test
+
+import (
+	"testing"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/adaptertest"
+	"github.com/luno/workflow/adapters/memnoncestore"
+)
+
+func TestStore(t *testing.T) {
+	adaptertest.RunNonceStoreTest(t, func() workflow.NonceStore {
+		return memnoncestore.New()
+	})
+}
@@ -28,6 +28,7 @@ func New(opts ...Option) *Store {
 		store:            make(map[string]*workflow.Record),
 		snapshots:        make(map[string][]*workflow.Record),
 		snapshotsOffsets: make(map[string]int),
+		definitions:      make(map[string]workflow.Definition),
 		clock:            opt.clock,
 	}
 
@@ -46,7 +47,12 @@ func WithClock(clock clock.Clock) Option {
 	}
 }
 
-var _ workflow.RecordStore = (*Store)(nil)
+var (
+	_ workflow.RecordStore           = (*Store)(nil)
+	_ workflow.DefinitionStore       = (*Store)(nil)
+	_ workflow.SearchableRecordStore = (*Store)(nil)
+	_ workflow.OutboxCounter         = (*Store)(nil)
+)
 
 type Store struct {
 	mu          sync.Mutex
@@ -63,6 +69,8 @@ type Store struct {
 
 	snapshots        map[string][]*workflow.Record
 	snapshotsOffsets map[string]int
+
+	definitions map[string]workflow.Definition
 }
 
 func (s *Store) Lookup(ctx context.Context, id string) (*workflow.Record, error) {
@@ -82,6 +90,7 @@ func (s *Store) Lookup(ctx context.Context, id string) (*workflow.Record, error)
 		RunState:     record.RunState,
 		Status:       record.Status,
 		Object:       record.Object,
+		Meta:         record.Meta,
 		CreatedAt:    record.CreatedAt,
 		UpdatedAt:    record.UpdatedAt,
 	}, nil
@@ -136,6 +145,7 @@ func (s *Store) Latest(ctx context.Context, workflowName, foreignID string) (*wo
 		RunState:     record.RunState,
 		Status:       record.Status,
 		Object:       record.Object,
+		Meta:         record.Meta,
 		CreatedAt:    record.CreatedAt,
 		UpdatedAt:    record.UpdatedAt,
 	}, nil
@@ -164,6 +174,20 @@ func (s *Store) ListOutboxEvents(
 	return filtered, nil
 }
 
+func (s *Store) CountOutboxEvents(ctx context.Context, workflowName string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var count int64
+	for _, outboxEvent := range s.outbox {
+		if outboxEvent.WorkflowName == workflowName {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
 func (s *Store) DeleteOutboxEvent(ctx context.Context, id string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -294,3 +318,73 @@ func snapShotKey(workflowName, foreignID, runID string) string {
 func uniqueKey(s1, s2 string) string {
 	return s1 + "-" + s2
 }
+
+func (s *Store) RegisterDefinition(ctx context.Context, d workflow.Definition) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	active, ok := s.definitions[d.WorkflowName]
+	if ok && active.Version > d.Version {
+		return nil
+	}
+
+	s.definitions[d.WorkflowName] = d
+	return nil
+}
+
+func (s *Store) ActiveDefinition(ctx context.Context, workflowName string) (*workflow.Definition, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, ok := s.definitions[workflowName]
+	if !ok {
+		return nil, workflow.ErrDefinitionNotFound
+	}
+
+	return &d, nil
+}
+
+func (s *Store) Search(
+	ctx context.Context,
+	workflowName string,
+	index string,
+	value string,
+	offset int64,
+	limit int,
+) ([]workflow.Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+
+	var entries []workflow.Record
+	for _, runID := range s.order {
+		record, ok := s.store[runID]
+		if !ok {
+			continue
+		}
+
+		if record.WorkflowName != workflowName {
+			continue
+		}
+
+		v, ok := record.Meta.SearchIndex[index]
+		if !ok || v != value {
+			continue
+		}
+
+		if offset > 0 {
+			offset--
+			continue
+		}
+
+		entries = append(entries, *record)
+		if len(entries) >= limit {
+			break
+		}
+	}
+
+	return entries, nil
+}
@@ -13,3 +13,15 @@ func TestStore(t *testing.T) {
 		return memrecordstore.New()
 	})
 }
+
+func TestDefinitionStore(t *testing.T) {
+	adaptertest.RunDefinitionStoreTest(t, func() workflow.DefinitionStore {
+		return memrecordstore.New()
+	})
+}
+
+func TestSearchableRecordStore(t *testing.T) {
+	adaptertest.RunSearchableRecordStoreTest(t, func() adaptertest.SearchableRecordStore {
+		return memrecordstore.New()
+	})
+}
@@ -7,7 +7,7 @@ import (
 
 type RoleScheduler struct {
 	mu    sync.Mutex
-	roles map[string]*sync.Mutex
+	roles map[string]chan struct{}
 }
 
 func (r *RoleScheduler) Await(ctx context.Context, role string) (context.Context, context.CancelFunc, error) {
@@ -15,34 +15,35 @@ func (r *RoleScheduler) Await(ctx context.Context, role string) (context.Context
 		return nil, nil, ctx.Err()
 	}
 
-	ctx, cancel := context.WithCancel(ctx)
-
-	// Lock the main mutex whilst checking and potentially creating new role mutexes
+	// Lock the main mutex whilst checking and potentially creating new role locks
 	r.mu.Lock()
-	mu, ok := r.roles[role]
+	lock, ok := r.roles[role]
 	if !ok {
-		mu = &sync.Mutex{}
-		r.roles[role] = mu
+		lock = make(chan struct{}, 1)
+		r.roles[role] = lock
 	}
 	r.mu.Unlock()
 
-	mu.Lock()
+	// Wait for the role to be released, or for the caller to give up, so that processes waiting on a role held by
+	// another process can still be stopped.
+	select {
+	case lock <- struct{}{}:
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
 
-	go func(role string) {
-		for {
-			<-ctx.Done()
-			r.mu.Lock()
-			r.roles[role].Unlock()
-			r.mu.Unlock()
-			return
-		}
-	}(role)
+	go func() {
+		<-ctx.Done()
+		<-lock
+	}()
 
 	return ctx, cancel, nil
 }
 
 func New() *RoleScheduler {
 	return &RoleScheduler{
-		roles: make(map[string]*sync.Mutex),
+		roles: make(map[string]chan struct{}),
 	}
 }
@@ -3,6 +3,7 @@ package memstreamer
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
 	"time"
 
@@ -47,6 +48,37 @@ func (c *connector) Make(ctx context.Context, name string) (workflow.ConnectorCo
 	}, nil
 }
 
+func (c *connector) ResetConnectorCursor(
+	ctx context.Context,
+	consumerName string,
+	position workflow.CursorPosition,
+) error {
+	log := *c.log
+
+	var offset int
+	switch position.Type {
+	case workflow.CursorPositionEarliest:
+		offset = 0
+	case workflow.CursorPositionLatest:
+		offset = len(log)
+	case workflow.CursorPositionTimestamp:
+		offset = len(log)
+		for i, e := range log {
+			if !e.CreatedAt.Before(position.Timestamp) {
+				offset = i
+				break
+			}
+		}
+	default:
+		return fmt.Errorf("unknown cursor position type: %v", position.Type)
+	}
+
+	c.cursorStore.Set(consumerName, offset)
+	return nil
+}
+
+var _ workflow.ConnectorCursorManager = (*connector)(nil)
+
 type consumer struct {
 	mu          sync.Mutex
 	log         *[]*workflow.ConnectorEvent
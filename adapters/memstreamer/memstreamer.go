@@ -2,6 +2,7 @@ package memstreamer
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
@@ -88,13 +89,36 @@ func (s StreamConstructor) NewReceiver(
 	}, nil
 }
 
-var _ workflow.EventStreamer = (*StreamConstructor)(nil)
+// NewMultiplexedReceiver returns a receiver that receives the events of all the topics in the order that they were
+// sent.
+func (s StreamConstructor) NewMultiplexedReceiver(
+	ctx context.Context,
+	topics []string,
+	name string,
+	opts ...workflow.ReceiverOption,
+) (workflow.EventReceiver, error) {
+	receiver, err := s.NewReceiver(ctx, "", name, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	stream := receiver.(*Stream)
+	stream.topics = make(map[string]bool)
+	for _, topic := range topics {
+		stream.topics[topic] = true
+	}
+
+	return stream, nil
+}
+
+var _ workflow.MultiplexedEventStreamer = (*StreamConstructor)(nil)
 
 type Stream struct {
 	mu          *sync.Mutex
 	log         *[]*workflow.Event
 	cursorStore *cursorStore
 	topic       string
+	topics      map[string]bool // topics are the topics of a multiplexed receiver.
 	name        string
 	clock       clock.Clock
 	options     workflow.ReceiverOptions
@@ -116,22 +140,59 @@ func (s *Stream) Send(ctx context.Context, foreignID string, statusType int, hea
 	return nil
 }
 
+// idlePollFrequency is how often the head of the stream is polled for new events. When the receiver is configured
+// with a MaxPollFrequency the interval doubles, up to the MaxPollFrequency, each time no new events are found.
+const idlePollFrequency = 10 * time.Millisecond
+
+// SendBatch appends all the events of the batch to the log at once.
+func (s *Stream) SendBatch(ctx context.Context, events []workflow.BatchEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range events {
+		length := len(*s.log)
+		*s.log = append(*s.log, &workflow.Event{
+			ID:        int64(length) + 1,
+			ForeignID: e.ForeignID,
+			Type:      e.Type,
+			Headers:   e.Headers,
+			CreatedAt: s.clock.Now(),
+		})
+	}
+
+	return nil
+}
+
+var _ workflow.BatchEventSender = (*Stream)(nil)
+
 func (s *Stream) Recv(ctx context.Context) (*workflow.Event, workflow.Ack, error) {
+	idle := idlePollFrequency
 	for ctx.Err() == nil {
 		s.mu.Lock()
 		log := *s.log
 
 		cursorOffset := s.cursorStore.Get(s.name)
 		if len(log)-1 < cursorOffset {
-			time.Sleep(time.Millisecond * 10)
 			s.mu.Unlock()
+
+			t := time.NewTimer(idle)
+			select {
+			case <-ctx.Done():
+				t.Stop()
+			case <-t.C:
+			}
+
+			if s.options.MaxPollFrequency > idle {
+				idle = min(idle*2, s.options.MaxPollFrequency)
+			}
+
 			continue
 		}
 
 		e := log[cursorOffset]
 
 		// Skip events that are not related to this topic
-		if s.topic != e.Headers[workflow.HeaderTopic] {
+		if !s.receives(e.Headers[workflow.HeaderTopic]) {
 			s.cursorStore.Set(s.name, cursorOffset+1)
 			s.mu.Unlock()
 			continue
@@ -147,6 +208,14 @@ func (s *Stream) Recv(ctx context.Context) (*workflow.Event, workflow.Ack, error
 	return nil, nil, ctx.Err()
 }
 
+func (s *Stream) receives(topic string) bool {
+	if s.topics != nil {
+		return s.topics[topic]
+	}
+
+	return s.topic == topic
+}
+
 func (s *Stream) Close() error {
 	return nil
 }
@@ -156,6 +225,61 @@ var (
 	_ workflow.EventReceiver = (*Stream)(nil)
 )
 
+func (s StreamConstructor) CursorOffset(ctx context.Context, cursor workflow.Cursor) (workflow.CursorOffset, error) {
+	s.stream.mu.Lock()
+	defer s.stream.mu.Unlock()
+
+	log := *s.stream.log
+	offset := s.cursorStore.Get(cursor.Name)
+
+	var lag int64
+	for i := offset; i < len(log); i++ {
+		if log[i].Headers[workflow.HeaderTopic] == cursor.Topic {
+			lag++
+		}
+	}
+
+	return workflow.CursorOffset{
+		Cursor: cursor,
+		Offset: int64(offset),
+		Lag:    lag,
+	}, nil
+}
+
+func (s StreamConstructor) ResetCursor(
+	ctx context.Context,
+	cursor workflow.Cursor,
+	position workflow.CursorPosition,
+) error {
+	s.stream.mu.Lock()
+	defer s.stream.mu.Unlock()
+
+	log := *s.stream.log
+
+	var offset int
+	switch position.Type {
+	case workflow.CursorPositionEarliest:
+		offset = 0
+	case workflow.CursorPositionLatest:
+		offset = len(log)
+	case workflow.CursorPositionTimestamp:
+		offset = len(log)
+		for i, e := range log {
+			if !e.CreatedAt.Before(position.Timestamp) {
+				offset = i
+				break
+			}
+		}
+	default:
+		return fmt.Errorf("unknown cursor position type: %v", position.Type)
+	}
+
+	s.cursorStore.Set(cursor.Name, offset)
+	return nil
+}
+
+var _ workflow.CursorManager = (*StreamConstructor)(nil)
+
 func newCursorStore() *cursorStore {
 	return &cursorStore{
 		cursors: make(map[string]int),
@@ -31,7 +31,10 @@ func WithClock(c clock.Clock) Option {
 	}
 }
 
-var _ workflow.TimeoutStore = (*Store)(nil)
+var (
+	_ workflow.TimeoutStore      = (*Store)(nil)
+	_ workflow.NamedTimeoutStore = (*Store)(nil)
+)
 
 type Store struct {
 	clock clock.Clock
@@ -58,6 +61,16 @@ func (s *Store) List(ctx context.Context, workflowName string) ([]workflow.Timeo
 }
 
 func (s *Store) Create(ctx context.Context, workflowName, foreignID, runID string, status int, expireAt time.Time) error {
+	return s.CreateNamed(ctx, workflowName, foreignID, runID, status, "", expireAt)
+}
+
+func (s *Store) CreateNamed(
+	ctx context.Context,
+	workflowName, foreignID, runID string,
+	status int,
+	name string,
+	expireAt time.Time,
+) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -69,6 +82,7 @@ func (s *Store) Create(ctx context.Context, workflowName, foreignID, runID strin
 		Status:       status,
 		ExpireAt:     expireAt,
 		CreatedAt:    s.clock.Now(),
+		Name:         name,
 	})
 	s.timeoutIdIncrement++
 
@@ -0,0 +1,49 @@
+package msgpackcodec
+
+import (
+	"bytes"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/luno/workflow"
+)
+
+// New returns a workflow.Codec that encodes the Object of runs using MessagePack which results in smaller records
+// than JSON. Struct fields are encoded using their msgpack struct tags and fall back to their json struct tags.
+func New() *Codec {
+	return &Codec{}
+}
+
+type Codec struct{}
+
+func (c *Codec) Marshal(v any) ([]byte, error) {
+	enc := msgpack.GetEncoder()
+	defer msgpack.PutEncoder(enc)
+
+	var buf bytes.Buffer
+	enc.Reset(&buf)
+	enc.SetCustomStructTag("json")
+
+	err := enc.Encode(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (c *Codec) Unmarshal(data []byte, v any) error {
+	dec := msgpack.GetDecoder()
+	defer msgpack.PutDecoder(dec)
+
+	dec.Reset(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+
+	return dec.Decode(v)
+}
+
+func (c *Codec) ContentType() string {
+	return "application/msgpack"
+}
+
+var _ workflow.Codec = (*Codec)(nil)
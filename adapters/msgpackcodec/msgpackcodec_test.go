@@ -0,0 +1,94 @@
+package msgpackcodec_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+	"github.com/luno/workflow/adapters/msgpackcodec"
+)
+
+type object struct {
+	ID      int64             `json:"id"`
+	Name    string            `json:"name"`
+	Tags    []string          `json:"tags"`
+	Balance float64           `json:"balance"`
+	Meta    map[string]string `json:"meta"`
+}
+
+func TestCodec(t *testing.T) {
+	codec := msgpackcodec.New()
+	require.Equal(t, "application/msgpack", codec.ContentType())
+
+	expected := object{
+		ID:      9,
+		Name:    "Andrew",
+		Tags:    []string{"a", "b"},
+		Balance: 10.5,
+		Meta:    map[string]string{"key": "value"},
+	}
+
+	b, err := codec.Marshal(&expected)
+	require.Nil(t, err)
+
+	jsonBytes, err := json.Marshal(&expected)
+	require.Nil(t, err)
+	require.Less(t, len(b), len(jsonBytes))
+
+	var actual object
+	err = codec.Unmarshal(b, &actual)
+	require.Nil(t, err)
+	require.Equal(t, expected, actual)
+}
+
+type status int
+
+const (
+	statusUnknown status = 0
+	statusStart   status = 1
+	statusEnd     status = 2
+)
+
+func (s status) String() string {
+	switch s {
+	case statusStart:
+		return "Start"
+	case statusEnd:
+		return "End"
+	default:
+		return "Unknown"
+	}
+}
+
+func TestWorkflow(t *testing.T) {
+	b := workflow.NewBuilder[object, status]("msgpack")
+	b.AddStep(statusStart, func(ctx context.Context, r *workflow.Run[object, status]) (status, error) {
+		r.Object.Name = "Andrew"
+		return statusEnd, nil
+	}, statusEnd)
+
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+		workflow.WithCodec(msgpackcodec.New()),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "foreignID", statusStart, workflow.WithInitialValue[object, status](&object{ID: 9}))
+	require.Nil(t, err)
+
+	run, err := wf.Await(ctx, "foreignID", runID, statusEnd, workflow.WithAwaitPollingFrequency(10*time.Millisecond))
+	require.Nil(t, err)
+	require.Equal(t, object{ID: 9, Name: "Andrew"}, *run.Object)
+}
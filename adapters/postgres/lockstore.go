@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/luno/workflow"
+)
+
+// LockStore is a workflow.LockStore that holds the slots of a key as Postgres session level advisory locks. Each
+// held slot uses its own connection from the pool until it is released and the slot is lost, and its context
+// cancelled, when the connection can no longer be used.
+type LockStore struct {
+	db            *sql.DB
+	retryInterval time.Duration
+	checkInterval time.Duration
+}
+
+// NewLockStore returns a LockStore that tries to acquire the slots of a key every retryInterval whilst waiting and
+// checks the connection of a held slot every retryInterval. The retryInterval defaults to 1 second when it is not
+// positive.
+func NewLockStore(db *sql.DB, retryInterval time.Duration) *LockStore {
+	if retryInterval <= 0 {
+		retryInterval = time.Second
+	}
+
+	return &LockStore{
+		db:            db,
+		retryInterval: retryInterval,
+		checkInterval: retryInterval,
+	}
+}
+
+var _ workflow.LockStore = (*LockStore)(nil)
+
+func (s *LockStore) Acquire(
+	ctx context.Context,
+	key string,
+	limit int,
+) (context.Context, context.CancelFunc, error) {
+	if limit <= 0 {
+		return nil, nil, errors.New("postgres lock store requires a positive limit")
+	}
+
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for {
+		for i := range limit {
+			slot := key + ":" + strconv.Itoa(i)
+
+			var locked bool
+			err := conn.QueryRowContext(ctx, "select pg_try_advisory_lock(hashtextextended($1, 0))", slot).
+				Scan(&locked)
+			if err != nil {
+				_ = conn.Close()
+				return nil, nil, fmt.Errorf("acquire lock: %w, meta: %v", err, map[string]string{
+					"key": key,
+				})
+			}
+
+			if locked {
+				ctx, cancel := context.WithCancel(ctx)
+				go s.hold(ctx, cancel, conn, slot)
+
+				return ctx, cancel, nil
+			}
+		}
+
+		t := time.NewTimer(s.retryInterval)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			_ = conn.Close()
+			return nil, nil, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// hold checks the connection of the slot until the context is cancelled, or until the connection can no longer be
+// used, and then releases the slot and returns the connection to the pool.
+func (s *LockStore) hold(ctx context.Context, cancel context.CancelFunc, conn *sql.Conn, slot string) {
+	defer conn.Close()
+	defer cancel()
+
+	ticker := time.NewTicker(s.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.release(conn, slot)
+			return
+		case <-ticker.C:
+		}
+
+		pingCtx, stop := context.WithTimeout(context.Background(), s.checkInterval)
+		err := conn.PingContext(pingCtx)
+		stop()
+		if err != nil {
+			// The session may have ended along with its advisory lock and so another caller may hold the slot.
+			discard(conn)
+			return
+		}
+	}
+}
+
+// release unlocks the slot even though the slot's context has been cancelled.
+func (s *LockStore) release(conn *sql.Conn, slot string) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.checkInterval)
+	defer cancel()
+
+	_, err := conn.ExecContext(ctx, "select pg_advisory_unlock(hashtextextended($1, 0))", slot)
+	if err != nil {
+		discard(conn)
+	}
+}
+
+// discard closes the connection's session, along with any advisory lock that it holds, instead of returning the
+// connection to the pool.
+func discard(conn *sql.Conn) {
+	_ = conn.Raw(func(any) error {
+		return driver.ErrBadConn
+	})
+}
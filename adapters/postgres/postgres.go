@@ -0,0 +1,92 @@
+// Package postgres provides a RecordStore, with a transactional outbox, and a TimeoutStore that are backed by
+// Postgres along with the migrations that create their tables. It also provides a LockStore that uses advisory locks
+// and so does not require any tables.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Tables are the names of the tables used by the RecordStore and TimeoutStore.
+type Tables struct {
+	Records  string
+	Outbox   string
+	Timeouts string
+}
+
+// DefaultTables are the table names used when no table names are configured.
+var DefaultTables = Tables{
+	Records:  "workflow_records",
+	Outbox:   "workflow_outbox",
+	Timeouts: "workflow_timeouts",
+}
+
+// Migrations returns the statements that create the tables, and their indexes, when they don't exist yet. The
+// statements can be run by Migrate or added to an existing migration tool.
+func Migrations(t Tables) []string {
+	return []string{
+		`create table if not exists ` + t.Records + ` (
+			workflow_name varchar(255) not null,
+			foreign_id    varchar(255) not null,
+			run_id        varchar(255) not null,
+			run_state     int not null,
+			status        int not null,
+			object        bytea not null,
+			meta          bytea,
+			created_at    timestamptz not null,
+			updated_at    timestamptz not null,
+
+			primary key (run_id)
+		)`,
+		`create index if not exists ` + t.Records + `_by_workflow_name_foreign_id_status
+			on ` + t.Records + ` (workflow_name, foreign_id, status)`,
+		`create index if not exists ` + t.Records + `_by_run_state on ` + t.Records + ` (run_state)`,
+		`create index if not exists ` + t.Records + `_by_created_at on ` + t.Records + ` (created_at)`,
+		`create table if not exists ` + t.Outbox + ` (
+			id            varchar(255) not null,
+			workflow_name varchar(255) not null,
+			data          bytea,
+			created_at    timestamptz not null,
+
+			primary key (id)
+		)`,
+		`create index if not exists ` + t.Outbox + `_by_workflow_name_created_at
+			on ` + t.Outbox + ` (workflow_name, created_at)`,
+		`create table if not exists ` + t.Timeouts + ` (
+			id            bigserial not null,
+			workflow_name varchar(255) not null,
+			foreign_id    varchar(255) not null,
+			run_id        varchar(255) not null,
+			status        bigint not null,
+			completed     boolean not null default false,
+			expire_at     timestamptz not null,
+			created_at    timestamptz not null,
+			name          varchar(255) not null default '',
+
+			primary key (id)
+		)`,
+		// The name column was added after the timeouts table and so is added to existing tables.
+		`alter table ` + t.Timeouts + ` add column if not exists name varchar(255) not null default ''`,
+		`create index if not exists ` + t.Timeouts + `_by_completed_expire_at on ` + t.Timeouts + ` (completed, expire_at)`,
+		`create index if not exists ` + t.Timeouts + `_by_workflow_name_status on ` + t.Timeouts + ` (workflow_name, status)`,
+	}
+}
+
+// Migrate creates the tables, and their indexes, when they don't exist yet.
+func Migrate(ctx context.Context, db *sql.DB, t Tables) error {
+	for _, statement := range Migrations(t) {
+		_, err := db.ExecContext(ctx, statement)
+		if err != nil {
+			return fmt.Errorf("migrate: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// row is a common interface for *sql.Rows and *sql.Row.
+type row interface {
+	Scan(dest ...any) error
+}
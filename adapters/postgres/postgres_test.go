@@ -0,0 +1,90 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/adaptertest"
+	"github.com/luno/workflow/adapters/postgres"
+)
+
+func TestRecordStore(t *testing.T) {
+	dsn := dsnForTesting(t)
+	adaptertest.RunRecordStoreTest(t, func() workflow.RecordStore {
+		db, tables := connectForTesting(t, dsn)
+		return postgres.NewRecordStore(db, db, tables.Records, tables.Outbox)
+	})
+}
+
+func TestTimeoutStore(t *testing.T) {
+	dsn := dsnForTesting(t)
+	adaptertest.RunTimeoutStoreTest(t, func() workflow.TimeoutStore {
+		db, tables := connectForTesting(t, dsn)
+		return postgres.NewTimeoutStore(db, db, tables.Timeouts)
+	})
+}
+
+func TestLockStore(t *testing.T) {
+	dsn := dsnForTesting(t)
+	adaptertest.RunLockStoreTest(t, func(t *testing.T) workflow.LockStore {
+		db, _ := connectForTesting(t, dsn)
+		return postgres.NewLockStore(db, 10*time.Millisecond)
+	})
+}
+
+func TestMigrate_idempotent(t *testing.T) {
+	db, tables := connectForTesting(t, dsnForTesting(t))
+
+	err := postgres.Migrate(context.Background(), db, tables)
+	require.Nil(t, err)
+}
+
+var tableCount atomic.Int64
+
+// dsnForTesting returns the Postgres database of the POSTGRES_TEST_DSN environment variable and skips the test when
+// it is not set.
+func dsnForTesting(t *testing.T) string {
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_TEST_DSN is not set")
+	}
+
+	return dsn
+}
+
+// connectForTesting connects to the database and creates a new set of tables for the test.
+func connectForTesting(t *testing.T, dsn string) (*sql.DB, postgres.Tables) {
+	db, err := sql.Open("postgres", dsn)
+	require.Nil(t, err)
+
+	suffix := "_" + strconv.Itoa(os.Getpid()) + "_" + strconv.FormatInt(tableCount.Add(1), 10)
+	tables := postgres.Tables{
+		Records:  postgres.DefaultTables.Records + suffix,
+		Outbox:   postgres.DefaultTables.Outbox + suffix,
+		Timeouts: postgres.DefaultTables.Timeouts + suffix,
+	}
+
+	ctx := context.Background()
+	err = postgres.Migrate(ctx, db, tables)
+	require.Nil(t, err)
+
+	t.Cleanup(func() {
+		for _, table := range []string{tables.Records, tables.Outbox, tables.Timeouts} {
+			_, err := db.ExecContext(ctx, "drop table if exists "+table)
+			require.Nil(t, err)
+		}
+
+		require.Nil(t, db.Close())
+	})
+
+	return db, tables
+}
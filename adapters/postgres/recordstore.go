@@ -0,0 +1,283 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/luno/workflow"
+)
+
+const defaultListLimit = 25
+
+const (
+	recordCols = " workflow_name, foreign_id, run_id, run_state, status, object, meta, created_at, updated_at "
+	outboxCols = " id, workflow_name, data, created_at "
+)
+
+// RecordStore is a workflow.RecordStore that stores the records, and their outbox events, in Postgres. The reader
+// is used for all reads and can be a read replica.
+type RecordStore struct {
+	writer *sql.DB
+	reader *sql.DB
+
+	recordTableName string
+	outboxTableName string
+}
+
+func NewRecordStore(writer, reader *sql.DB, recordTableName, outboxTableName string) *RecordStore {
+	return &RecordStore{
+		writer:          writer,
+		reader:          reader,
+		recordTableName: recordTableName,
+		outboxTableName: outboxTableName,
+	}
+}
+
+var (
+	_ workflow.RecordStore   = (*RecordStore)(nil)
+	_ workflow.OutboxCounter = (*RecordStore)(nil)
+)
+
+// Store creates or updates the record and inserts its outbox event in the same transaction.
+func (s *RecordStore) Store(ctx context.Context, r *workflow.Record) error {
+	meta, err := json.Marshal(r.Meta)
+	if err != nil {
+		return fmt.Errorf("marshal record meta: %w", err)
+	}
+
+	eventData, err := workflow.MakeOutboxEventData(*r)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.writer.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, "insert into "+s.recordTableName+" ("+recordCols+") "+
+		"values ($1, $2, $3, $4, $5, $6, $7, now(), now()) "+
+		"on conflict (run_id) do update set "+
+		"run_state=excluded.run_state, status=excluded.status, object=excluded.object, meta=excluded.meta, "+
+		"updated_at=excluded.updated_at",
+		r.WorkflowName,
+		r.ForeignID,
+		r.RunID,
+		int(r.RunState),
+		r.Status,
+		r.Object,
+		meta,
+	)
+	if err != nil {
+		return fmt.Errorf("store record: %w, meta: %v", err, map[string]string{
+			"workflow_name": r.WorkflowName,
+			"foreign_id":    r.ForeignID,
+			"run_id":        r.RunID,
+		})
+	}
+
+	_, err = tx.ExecContext(ctx, "insert into "+s.outboxTableName+" ("+outboxCols+") values ($1, $2, $3, now())",
+		eventData.ID,
+		eventData.WorkflowName,
+		eventData.Data,
+	)
+	if err != nil {
+		return fmt.Errorf("insert outbox event: %w, meta: %v", err, map[string]string{
+			"workflow_name": r.WorkflowName,
+			"run_id":        r.RunID,
+		})
+	}
+
+	return tx.Commit()
+}
+
+func (s *RecordStore) Lookup(ctx context.Context, runID string) (*workflow.Record, error) {
+	return recordScan(s.reader.QueryRowContext(ctx, s.recordSelect()+"where run_id=$1", runID))
+}
+
+func (s *RecordStore) Latest(ctx context.Context, workflowName, foreignID string) (*workflow.Record, error) {
+	return recordScan(s.reader.QueryRowContext(
+		ctx,
+		s.recordSelect()+"where workflow_name=$1 and foreign_id=$2 order by created_at desc limit 1",
+		workflowName,
+		foreignID,
+	))
+}
+
+func (s *RecordStore) List(
+	ctx context.Context,
+	workflowName string,
+	offset int64,
+	limit int,
+	order workflow.OrderType,
+	filters ...workflow.RecordFilter,
+) ([]workflow.Record, error) {
+	filter := workflow.MakeFilter(filters...)
+
+	var wb whereBuilder
+	if workflowName != "" {
+		wb.Where("workflow_name", workflowName)
+	}
+
+	if filter.ByForeignID().Enabled {
+		wb.Where("foreign_id", filterValues(filter.ByForeignID())...)
+	}
+
+	if filter.ByStatus().Enabled {
+		wb.Where("status", filterValues(filter.ByStatus())...)
+	}
+
+	if filter.ByRunState().Enabled {
+		wb.Where("run_state", filterValues(filter.ByRunState())...)
+	}
+
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+
+	query := s.recordSelect()
+	if len(wb.conditions) > 0 {
+		query += "where " + strings.Join(wb.conditions, " and ")
+	}
+
+	query += " order by created_at " + order.String()
+	query += " limit " + wb.Param(limit) + " offset " + wb.Param(offset)
+
+	rows, err := s.reader.QueryContext(ctx, query, wb.params...)
+	if err != nil {
+		return nil, fmt.Errorf("list records: %w", err)
+	}
+	defer rows.Close()
+
+	var records []workflow.Record
+	for rows.Next() {
+		r, err := recordScan(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, *r)
+	}
+
+	return records, rows.Err()
+}
+
+func (s *RecordStore) ListOutboxEvents(
+	ctx context.Context,
+	workflowName string,
+	limit int64,
+) ([]workflow.OutboxEvent, error) {
+	rows, err := s.reader.QueryContext(
+		ctx,
+		"select "+outboxCols+" from "+s.outboxTableName+" where workflow_name=$1 order by created_at limit $2",
+		workflowName,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []workflow.OutboxEvent
+	for rows.Next() {
+		var e workflow.OutboxEvent
+		err := rows.Scan(&e.ID, &e.WorkflowName, &e.Data, &e.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("scan outbox event: %w", err)
+		}
+
+		events = append(events, e)
+	}
+
+	return events, rows.Err()
+}
+
+func (s *RecordStore) CountOutboxEvents(ctx context.Context, workflowName string) (int64, error) {
+	var count int64
+	err := s.reader.QueryRowContext(
+		ctx,
+		"select count(*) from "+s.outboxTableName+" where workflow_name=$1",
+		workflowName,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("count outbox events: %w", err)
+	}
+
+	return count, nil
+}
+
+func (s *RecordStore) DeleteOutboxEvent(ctx context.Context, id string) error {
+	_, err := s.writer.ExecContext(ctx, "delete from "+s.outboxTableName+" where id=$1", id)
+	return err
+}
+
+func (s *RecordStore) recordSelect() string {
+	return "select " + recordCols + " from " + s.recordTableName + " "
+}
+
+func recordScan(row row) (*workflow.Record, error) {
+	var (
+		r    workflow.Record
+		meta []byte
+	)
+	err := row.Scan(
+		&r.WorkflowName,
+		&r.ForeignID,
+		&r.RunID,
+		&r.RunState,
+		&r.Status,
+		&r.Object,
+		&meta,
+		&r.CreatedAt,
+		&r.UpdatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, workflow.ErrRecordNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("scan record: %w", err)
+	}
+
+	if len(meta) > 0 {
+		err = json.Unmarshal(meta, &r.Meta)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshal record meta: %w", err)
+		}
+	}
+
+	return &r, nil
+}
+
+func filterValues(f workflow.Filter) []string {
+	if f.IsMultiMatch {
+		return f.MultiValues()
+	}
+
+	return []string{f.Value()}
+}
+
+// whereBuilder builds the conditions of a query using Postgres' numbered parameters.
+type whereBuilder struct {
+	conditions []string
+	params     []any
+}
+
+// Param adds the parameter and returns its placeholder.
+func (wb *whereBuilder) Param(value any) string {
+	wb.params = append(wb.params, value)
+	return "$" + strconv.Itoa(len(wb.params))
+}
+
+func (wb *whereBuilder) Where(field string, values ...string) {
+	placeholders := make([]string, 0, len(values))
+	for _, value := range values {
+		placeholders = append(placeholders, wb.Param(value))
+	}
+
+	wb.conditions = append(wb.conditions, field+" in ("+strings.Join(placeholders, ", ")+")")
+}
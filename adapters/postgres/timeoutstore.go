@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/luno/workflow"
+)
+
+const timeoutCols = " id, workflow_name, foreign_id, run_id, status, completed, expire_at, created_at, name "
+
+// TimeoutStore is a workflow.TimeoutStore that stores the timeouts in Postgres. The reader is used for all reads and
+// can be a read replica.
+type TimeoutStore struct {
+	writer *sql.DB
+	reader *sql.DB
+
+	timeoutTableName string
+}
+
+func NewTimeoutStore(writer, reader *sql.DB, timeoutTableName string) *TimeoutStore {
+	return &TimeoutStore{
+		writer:           writer,
+		reader:           reader,
+		timeoutTableName: timeoutTableName,
+	}
+}
+
+var (
+	_ workflow.TimeoutStore      = (*TimeoutStore)(nil)
+	_ workflow.NamedTimeoutStore = (*TimeoutStore)(nil)
+)
+
+func (s *TimeoutStore) Create(
+	ctx context.Context,
+	workflowName, foreignID, runID string,
+	status int,
+	expireAt time.Time,
+) error {
+	return s.CreateNamed(ctx, workflowName, foreignID, runID, status, "", expireAt)
+}
+
+func (s *TimeoutStore) CreateNamed(
+	ctx context.Context,
+	workflowName, foreignID, runID string,
+	status int,
+	name string,
+	expireAt time.Time,
+) error {
+	_, err := s.writer.ExecContext(ctx, "insert into "+s.timeoutTableName+
+		" (workflow_name, foreign_id, run_id, status, completed, expire_at, created_at, name) "+
+		"values ($1, $2, $3, $4, false, $5, now(), $6)",
+		workflowName,
+		foreignID,
+		runID,
+		status,
+		expireAt,
+		name,
+	)
+	if err != nil {
+		return fmt.Errorf("create timeout: %w, meta: %v", err, map[string]string{
+			"workflow_name": workflowName,
+			"foreign_id":    foreignID,
+			"run_id":        runID,
+			"status":        strconv.Itoa(status),
+			"name":          name,
+		})
+	}
+
+	return nil
+}
+
+func (s *TimeoutStore) Complete(ctx context.Context, id int64) error {
+	_, err := s.writer.ExecContext(ctx, "update "+s.timeoutTableName+" set completed=true where id=$1", id)
+	if err != nil {
+		return fmt.Errorf("complete timeout: %w, meta: %v", err, map[string]string{
+			"id": strconv.FormatInt(id, 10),
+		})
+	}
+
+	return nil
+}
+
+func (s *TimeoutStore) Cancel(ctx context.Context, id int64) error {
+	_, err := s.writer.ExecContext(ctx, "delete from "+s.timeoutTableName+" where id=$1", id)
+	if err != nil {
+		return fmt.Errorf("cancel timeout: %w, meta: %v", err, map[string]string{
+			"id": strconv.FormatInt(id, 10),
+		})
+	}
+
+	return nil
+}
+
+func (s *TimeoutStore) List(ctx context.Context, workflowName string) ([]workflow.TimeoutRecord, error) {
+	return s.listWhere(ctx, "workflow_name=$1 and completed=false order by id", workflowName)
+}
+
+func (s *TimeoutStore) ListValid(
+	ctx context.Context,
+	workflowName string,
+	status int,
+	now time.Time,
+) ([]workflow.TimeoutRecord, error) {
+	return s.listWhere(
+		ctx,
+		"workflow_name=$1 and status=$2 and expire_at<$3 and completed=false order by id",
+		workflowName,
+		status,
+		now,
+	)
+}
+
+func (s *TimeoutStore) listWhere(ctx context.Context, where string, args ...any) ([]workflow.TimeoutRecord, error) {
+	rows, err := s.reader.QueryContext(ctx, "select "+timeoutCols+" from "+s.timeoutTableName+" where "+where, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list timeouts: %w", err)
+	}
+	defer rows.Close()
+
+	var timeouts []workflow.TimeoutRecord
+	for rows.Next() {
+		t, err := timeoutScan(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		timeouts = append(timeouts, *t)
+	}
+
+	return timeouts, rows.Err()
+}
+
+func timeoutScan(row row) (*workflow.TimeoutRecord, error) {
+	var t workflow.TimeoutRecord
+	err := row.Scan(
+		&t.ID,
+		&t.WorkflowName,
+		&t.ForeignID,
+		&t.RunID,
+		&t.Status,
+		&t.Completed,
+		&t.ExpireAt,
+		&t.CreatedAt,
+		&t.Name,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, workflow.ErrTimeoutNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("scan timeout: %w", err)
+	}
+
+	return &t, nil
+}
@@ -0,0 +1,239 @@
+// Package redisrolescheduler provides a RoleScheduler that coordinates the ownership of roles across instances using
+// leases stored in Redis. Each lease is held under a random token, with a TTL that is renewed whilst the role is held,
+// and so a role is released by an instance that stops without releasing it once its lease expires.
+//
+// When multiple independent Redis instances are provided the leases follow the Redlock algorithm: a role is only
+// assigned once a majority of the instances have granted the lease and the role is given up as soon as a majority of
+// the instances can no longer be renewed.
+//
+// The RoleScheduler is also a workflow.LockStore which holds the slots of a key using the same leases.
+package redisrolescheduler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/luno/workflow"
+)
+
+// releaseScript only deletes the lease when it is still held by the token.
+var releaseScript = redis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("del", KEYS[1])
+end
+return 0
+`)
+
+// renewScript only extends the lease when it is still held by the token.
+var renewScript = redis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("pexpire", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+type options struct {
+	ttl           time.Duration
+	retryInterval time.Duration
+	keyPrefix     string
+}
+
+type Option func(o *options)
+
+// WithTTL sets how long a lease is held for without being renewed. Leases are renewed every third of the TTL and so a
+// role held by an instance that stops without releasing it is assigned to another instance within the TTL. The
+// default is 10 seconds.
+func WithTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.ttl = ttl
+	}
+}
+
+// WithRetryInterval sets how often an instance that is waiting for a role tries to acquire its lease. The default is
+// 1 second.
+func WithRetryInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.retryInterval = d
+	}
+}
+
+// WithKeyPrefix sets the prefix of the keys of the leases which allows multiple deployments to share the same Redis
+// instances. The default is "workflow:role:".
+func WithKeyPrefix(prefix string) Option {
+	return func(o *options) {
+		o.keyPrefix = prefix
+	}
+}
+
+// New returns a RoleScheduler that stores its leases in the Redis instance.
+func New(client redis.UniversalClient, opts ...Option) *RoleScheduler {
+	return NewRedlock([]redis.UniversalClient{client}, opts...)
+}
+
+// NewRedlock returns a RoleScheduler that stores its leases in a majority of the Redis instances. The instances must
+// be independent, and not replicas of each other, to tolerate the failure of a minority of them.
+func NewRedlock(clients []redis.UniversalClient, opts ...Option) *RoleScheduler {
+	o := options{
+		ttl:           10 * time.Second,
+		retryInterval: time.Second,
+		keyPrefix:     "workflow:role:",
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &RoleScheduler{
+		clients: clients,
+		opts:    o,
+	}
+}
+
+type RoleScheduler struct {
+	clients []redis.UniversalClient
+	opts    options
+}
+
+var _ workflow.RoleScheduler = (*RoleScheduler)(nil)
+
+func (r *RoleScheduler) Await(ctx context.Context, role string) (context.Context, context.CancelFunc, error) {
+	return r.lease(ctx, r.opts.keyPrefix+role)
+}
+
+var _ workflow.LockStore = (*RoleScheduler)(nil)
+
+// Acquire holds one of the limit slots of the key using the same leases as roles so that the RoleScheduler can also
+// be configured as the workflow's LockStore using workflow.WithLockStore.
+func (r *RoleScheduler) Acquire(
+	ctx context.Context,
+	key string,
+	limit int,
+) (context.Context, context.CancelFunc, error) {
+	if limit <= 0 {
+		return nil, nil, errors.New("redis lock store requires a positive limit")
+	}
+
+	slots := make([]string, limit)
+	for i := range slots {
+		slots[i] = r.opts.keyPrefix + "lock:" + key + ":" + strconv.Itoa(i)
+	}
+
+	return r.lease(ctx, slots...)
+}
+
+// lease blocks until the lease of one of the keys is acquired and holds it until the returned context is cancelled.
+func (r *RoleScheduler) lease(ctx context.Context, keys ...string) (context.Context, context.CancelFunc, error) {
+	if len(r.clients) == 0 {
+		return nil, nil, errors.New("redis role scheduler requires at least one redis client")
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for {
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err()
+		}
+
+		for _, key := range keys {
+			if r.acquire(ctx, key, token) {
+				ctx, cancel := context.WithCancel(ctx)
+				go r.hold(ctx, cancel, key, token)
+
+				return ctx, cancel, nil
+			}
+		}
+
+		t := time.NewTimer(r.opts.retryInterval)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, nil, ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+func (r *RoleScheduler) quorum() int {
+	return len(r.clients)/2 + 1
+}
+
+// acquire tries to acquire the lease from each instance and returns true when a majority of the instances granted
+// the lease before it expired. The leases that were granted are released when the majority is not reached.
+func (r *RoleScheduler) acquire(ctx context.Context, key, token string) bool {
+	t0 := time.Now()
+
+	var acquired int
+	for _, client := range r.clients {
+		ok, err := client.SetNX(ctx, key, token, r.opts.ttl).Result()
+		if err == nil && ok {
+			acquired++
+		}
+	}
+
+	if acquired >= r.quorum() && time.Since(t0) < r.opts.ttl {
+		return true
+	}
+
+	r.release(key, token)
+	return false
+}
+
+// hold renews the lease until the context is cancelled, or until the lease can no longer be renewed on a majority
+// of the instances, and then releases it.
+func (r *RoleScheduler) hold(ctx context.Context, cancel context.CancelFunc, key, token string) {
+	defer r.release(key, token)
+	defer cancel()
+
+	ticker := time.NewTicker(r.opts.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		var renewed int
+		for _, client := range r.clients {
+			n, err := renewScript.Run(ctx, client, []string{key}, token, r.opts.ttl.Milliseconds()).Int()
+			if err == nil && n == 1 {
+				renewed++
+			}
+		}
+
+		if renewed < r.quorum() {
+			// The lease has been lost and another instance may be assigned the role.
+			return
+		}
+	}
+}
+
+func (r *RoleScheduler) release(key, token string) {
+	// The lease is released even when the role's context has been cancelled.
+	ctx, cancel := context.WithTimeout(context.Background(), r.opts.ttl)
+	defer cancel()
+
+	for _, client := range r.clients {
+		_ = releaseScript.Run(ctx, client, []string{key}, token).Err()
+	}
+}
+
+func newToken() (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
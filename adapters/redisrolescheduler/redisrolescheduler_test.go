@@ -0,0 +1,119 @@
+package redisrolescheduler_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/adaptertest"
+	"github.com/luno/workflow/adapters/redisrolescheduler"
+)
+
+func TestRoleScheduler(t *testing.T) {
+	adaptertest.RunRoleSchedulerTest(t, func(t *testing.T, instances int) []workflow.RoleScheduler {
+		client := newClient(t, miniredis.RunT(t))
+
+		var rs []workflow.RoleScheduler
+		for range instances {
+			rs = append(rs, redisrolescheduler.New(
+				client,
+				redisrolescheduler.WithTTL(time.Second),
+				redisrolescheduler.WithRetryInterval(10*time.Millisecond),
+			))
+		}
+
+		return rs
+	})
+}
+
+func TestLockStore(t *testing.T) {
+	adaptertest.RunLockStoreTest(t, func(t *testing.T) workflow.LockStore {
+		return redisrolescheduler.New(
+			newClient(t, miniredis.RunT(t)),
+			redisrolescheduler.WithTTL(time.Second),
+			redisrolescheduler.WithRetryInterval(10*time.Millisecond),
+		)
+	})
+}
+
+func TestRoleScheduler_redlock(t *testing.T) {
+	adaptertest.RunRoleSchedulerTest(t, func(t *testing.T, instances int) []workflow.RoleScheduler {
+		var clients []redis.UniversalClient
+		for range 3 {
+			clients = append(clients, newClient(t, miniredis.RunT(t)))
+		}
+
+		var rs []workflow.RoleScheduler
+		for range instances {
+			rs = append(rs, redisrolescheduler.NewRedlock(
+				clients,
+				redisrolescheduler.WithTTL(time.Second),
+				redisrolescheduler.WithRetryInterval(10*time.Millisecond),
+			))
+		}
+
+		return rs
+	})
+}
+
+func TestRoleScheduler_leaseLost(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := newClient(t, server)
+
+	rs := redisrolescheduler.New(
+		client,
+		redisrolescheduler.WithTTL(300*time.Millisecond),
+		redisrolescheduler.WithRetryInterval(10*time.Millisecond),
+		redisrolescheduler.WithKeyPrefix("test:"),
+	)
+
+	ctx, cancel, err := rs.Await(context.Background(), "role")
+	require.Nil(t, err)
+	t.Cleanup(cancel)
+
+	// The lease is renewed whilst the role is held.
+	time.Sleep(500 * time.Millisecond)
+	require.True(t, server.Exists("test:role"))
+	require.Nil(t, ctx.Err())
+
+	// Another owner taking the lease results in the role's context being cancelled on the next renewal.
+	server.Set("test:role", "other")
+
+	require.Eventually(t, func() bool {
+		return ctx.Err() != nil
+	}, time.Second, 10*time.Millisecond)
+
+	// The lease of the other owner is not released.
+	value, err := server.Get("test:role")
+	require.Nil(t, err)
+	require.Equal(t, "other", value)
+}
+
+func TestRoleScheduler_releasesOnCancel(t *testing.T) {
+	server := miniredis.RunT(t)
+	rs := redisrolescheduler.New(newClient(t, server), redisrolescheduler.WithKeyPrefix("test:"))
+
+	_, cancel, err := rs.Await(context.Background(), "role")
+	require.Nil(t, err)
+	require.True(t, server.Exists("test:role"))
+
+	cancel()
+
+	require.Eventually(t, func() bool {
+		return !server.Exists("test:role")
+	}, time.Second, 10*time.Millisecond)
+}
+
+func newClient(t *testing.T, server *miniredis.Miniredis) redis.UniversalClient {
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	return client
+}
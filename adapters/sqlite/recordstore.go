@@ -0,0 +1,349 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/luno/workflow"
+)
+
+const defaultListLimit = 25
+
+const (
+	recordCols = " workflow_name, foreign_id, run_id, run_state, status, object, meta, created_at, updated_at "
+	outboxCols = " id, workflow_name, data, created_at "
+)
+
+// RecordStore is a workflow.RecordStore that stores the records, and their outbox events, in SQLite.
+type RecordStore struct {
+	db *sql.DB
+
+	recordTableName string
+	outboxTableName string
+}
+
+func NewRecordStore(db *sql.DB, recordTableName, outboxTableName string) *RecordStore {
+	return &RecordStore{
+		db:              db,
+		recordTableName: recordTableName,
+		outboxTableName: outboxTableName,
+	}
+}
+
+var (
+	_ workflow.RecordStore           = (*RecordStore)(nil)
+	_ workflow.SearchableRecordStore = (*RecordStore)(nil)
+	_ workflow.OutboxCounter         = (*RecordStore)(nil)
+)
+
+// Store creates or updates the record, and the values of its search indexes, and inserts its outbox event in the same
+// transaction.
+func (s *RecordStore) Store(ctx context.Context, r *workflow.Record) error {
+	meta, err := json.Marshal(r.Meta)
+	if err != nil {
+		return fmt.Errorf("marshal record meta: %w", err)
+	}
+
+	eventData, err := workflow.MakeOutboxEventData(*r)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UnixNano()
+	_, err = tx.ExecContext(ctx, "insert into "+s.recordTableName+" ("+recordCols+") "+
+		"values (?, ?, ?, ?, ?, ?, ?, ?, ?) "+
+		"on conflict (run_id) do update set "+
+		"run_state=excluded.run_state, status=excluded.status, object=excluded.object, meta=excluded.meta, "+
+		"updated_at=excluded.updated_at",
+		r.WorkflowName,
+		r.ForeignID,
+		r.RunID,
+		int(r.RunState),
+		r.Status,
+		r.Object,
+		meta,
+		now,
+		now,
+	)
+	if err != nil {
+		return fmt.Errorf("store record: %w, meta: %v", err, map[string]string{
+			"workflow_name": r.WorkflowName,
+			"foreign_id":    r.ForeignID,
+			"run_id":        r.RunID,
+		})
+	}
+
+	_, err = tx.ExecContext(ctx, "delete from "+searchIndexTable(s.recordTableName)+" where run_id=?", r.RunID)
+	if err != nil {
+		return fmt.Errorf("delete search index: %w, meta: %v", err, map[string]string{
+			"workflow_name": r.WorkflowName,
+			"run_id":        r.RunID,
+		})
+	}
+
+	for name, value := range r.Meta.SearchIndex {
+		_, err = tx.ExecContext(ctx, "insert into "+searchIndexTable(s.recordTableName)+
+			" (run_id, workflow_name, name, value) values (?, ?, ?, ?)",
+			r.RunID,
+			r.WorkflowName,
+			name,
+			value,
+		)
+		if err != nil {
+			return fmt.Errorf("insert search index: %w, meta: %v", err, map[string]string{
+				"workflow_name": r.WorkflowName,
+				"run_id":        r.RunID,
+				"index":         name,
+			})
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, "insert into "+s.outboxTableName+" ("+outboxCols+") values (?, ?, ?, ?)",
+		eventData.ID,
+		eventData.WorkflowName,
+		eventData.Data,
+		now,
+	)
+	if err != nil {
+		return fmt.Errorf("insert outbox event: %w, meta: %v", err, map[string]string{
+			"workflow_name": r.WorkflowName,
+			"run_id":        r.RunID,
+		})
+	}
+
+	return tx.Commit()
+}
+
+func (s *RecordStore) Lookup(ctx context.Context, runID string) (*workflow.Record, error) {
+	return recordScan(s.db.QueryRowContext(ctx, s.recordSelect()+"where run_id=?", runID))
+}
+
+func (s *RecordStore) Latest(ctx context.Context, workflowName, foreignID string) (*workflow.Record, error) {
+	return recordScan(s.db.QueryRowContext(
+		ctx,
+		s.recordSelect()+"where workflow_name=? and foreign_id=? order by created_at desc, rowid desc limit 1",
+		workflowName,
+		foreignID,
+	))
+}
+
+func (s *RecordStore) List(
+	ctx context.Context,
+	workflowName string,
+	offset int64,
+	limit int,
+	order workflow.OrderType,
+	filters ...workflow.RecordFilter,
+) ([]workflow.Record, error) {
+	filter := workflow.MakeFilter(filters...)
+
+	var (
+		conditions []string
+		params     []any
+	)
+	where := func(field string, f workflow.Filter) {
+		values := []string{f.Value()}
+		if f.IsMultiMatch {
+			values = f.MultiValues()
+		}
+
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")
+		conditions = append(conditions, field+" in ("+placeholders+")")
+		for _, value := range values {
+			params = append(params, value)
+		}
+	}
+
+	if workflowName != "" {
+		conditions = append(conditions, "workflow_name=?")
+		params = append(params, workflowName)
+	}
+
+	if filter.ByForeignID().Enabled {
+		where("foreign_id", filter.ByForeignID())
+	}
+
+	if filter.ByStatus().Enabled {
+		where("status", filter.ByStatus())
+	}
+
+	if filter.ByRunState().Enabled {
+		where("run_state", filter.ByRunState())
+	}
+
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+
+	query := s.recordSelect()
+	if len(conditions) > 0 {
+		query += "where " + strings.Join(conditions, " and ")
+	}
+
+	// The rowid orders records that were created at the same time in the order that they were inserted.
+	query += " order by created_at " + order.String() + ", rowid " + order.String() + " limit ? offset ?"
+	params = append(params, limit, offset)
+
+	rows, err := s.db.QueryContext(ctx, query, params...)
+	if err != nil {
+		return nil, fmt.Errorf("list records: %w", err)
+	}
+	defer rows.Close()
+
+	var records []workflow.Record
+	for rows.Next() {
+		r, err := recordScan(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, *r)
+	}
+
+	return records, rows.Err()
+}
+
+func (s *RecordStore) Search(
+	ctx context.Context,
+	workflowName string,
+	index string,
+	value string,
+	offset int64,
+	limit int,
+) ([]workflow.Record, error) {
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		s.recordSelect()+"where run_id in (select run_id from "+searchIndexTable(s.recordTableName)+
+			" where workflow_name=? and name=? and value=?) order by created_at, rowid limit ? offset ?",
+		workflowName,
+		index,
+		value,
+		limit,
+		offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("search records: %w", err)
+	}
+	defer rows.Close()
+
+	var records []workflow.Record
+	for rows.Next() {
+		r, err := recordScan(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, *r)
+	}
+
+	return records, rows.Err()
+}
+
+func (s *RecordStore) ListOutboxEvents(
+	ctx context.Context,
+	workflowName string,
+	limit int64,
+) ([]workflow.OutboxEvent, error) {
+	rows, err := s.db.QueryContext(
+		ctx,
+		"select "+outboxCols+" from "+s.outboxTableName+
+			" where workflow_name=? order by created_at, rowid limit ?",
+		workflowName,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list outbox events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []workflow.OutboxEvent
+	for rows.Next() {
+		var (
+			e         workflow.OutboxEvent
+			createdAt int64
+		)
+		err := rows.Scan(&e.ID, &e.WorkflowName, &e.Data, &createdAt)
+		if err != nil {
+			return nil, fmt.Errorf("scan outbox event: %w", err)
+		}
+
+		e.CreatedAt = fromUnixNano(createdAt)
+		events = append(events, e)
+	}
+
+	return events, rows.Err()
+}
+
+func (s *RecordStore) CountOutboxEvents(ctx context.Context, workflowName string) (int64, error) {
+	var count int64
+	err := s.db.QueryRowContext(
+		ctx,
+		"select count(*) from "+s.outboxTableName+" where workflow_name=?",
+		workflowName,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("count outbox events: %w", err)
+	}
+
+	return count, nil
+}
+
+func (s *RecordStore) DeleteOutboxEvent(ctx context.Context, id string) error {
+	_, err := s.db.ExecContext(ctx, "delete from "+s.outboxTableName+" where id=?", id)
+	return err
+}
+
+func (s *RecordStore) recordSelect() string {
+	return "select " + recordCols + " from " + s.recordTableName + " "
+}
+
+func recordScan(row row) (*workflow.Record, error) {
+	var (
+		r                    workflow.Record
+		meta                 []byte
+		createdAt, updatedAt int64
+	)
+	err := row.Scan(
+		&r.WorkflowName,
+		&r.ForeignID,
+		&r.RunID,
+		&r.RunState,
+		&r.Status,
+		&r.Object,
+		&meta,
+		&createdAt,
+		&updatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, workflow.ErrRecordNotFound
+	} else if err != nil {
+		return nil, fmt.Errorf("scan record: %w", err)
+	}
+
+	r.CreatedAt = fromUnixNano(createdAt)
+	r.UpdatedAt = fromUnixNano(updatedAt)
+
+	if len(meta) > 0 {
+		err = json.Unmarshal(meta, &r.Meta)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshal record meta: %w", err)
+		}
+	}
+
+	return &r, nil
+}
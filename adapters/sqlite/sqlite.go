@@ -0,0 +1,140 @@
+// Package sqlite provides a RecordStore, with a transactional outbox, and a TimeoutStore that are backed by SQLite
+// so that single binary services and CLIs can run workflows durably without a database server.
+//
+// SQLite only allows a single writer at a time and so the *sql.DB should be configured with db.SetMaxOpenConns(1),
+// or with a busy timeout, to avoid "database is locked" errors. Times are stored as nanoseconds since the Unix epoch
+// so that they are ordered correctly regardless of their time zone.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Tables are the names of the tables used by the RecordStore and TimeoutStore.
+type Tables struct {
+	Records  string
+	Outbox   string
+	Timeouts string
+}
+
+// DefaultTables are the table names used when no table names are configured.
+var DefaultTables = Tables{
+	Records:  "workflow_records",
+	Outbox:   "workflow_outbox",
+	Timeouts: "workflow_timeouts",
+}
+
+// Migrations returns the statements that create the tables, and their indexes, when they don't exist yet.
+func Migrations(t Tables) []string {
+	return []string{
+		`create table if not exists ` + t.Records + ` (
+			workflow_name text not null,
+			foreign_id    text not null,
+			run_id        text not null primary key,
+			run_state     integer not null,
+			status        integer not null,
+			object        blob not null,
+			meta          blob,
+			created_at    integer not null,
+			updated_at    integer not null
+		)`,
+		`create index if not exists ` + t.Records + `_by_workflow_name_foreign_id_status
+			on ` + t.Records + ` (workflow_name, foreign_id, status)`,
+		`create index if not exists ` + t.Records + `_by_run_state on ` + t.Records + ` (run_state)`,
+		`create index if not exists ` + t.Records + `_by_created_at on ` + t.Records + ` (created_at)`,
+		`create table if not exists ` + searchIndexTable(t.Records) + ` (
+			run_id        text not null,
+			workflow_name text not null,
+			name          text not null,
+			value         text not null,
+			primary key (run_id, name)
+		)`,
+		`create index if not exists ` + searchIndexTable(t.Records) + `_by_workflow_name_name_value
+			on ` + searchIndexTable(t.Records) + ` (workflow_name, name, value)`,
+		`create table if not exists ` + t.Outbox + ` (
+			id            text not null primary key,
+			workflow_name text not null,
+			data          blob,
+			created_at    integer not null
+		)`,
+		`create index if not exists ` + t.Outbox + `_by_workflow_name_created_at
+			on ` + t.Outbox + ` (workflow_name, created_at)`,
+		`create table if not exists ` + t.Timeouts + ` (
+			id            integer primary key autoincrement,
+			workflow_name text not null,
+			foreign_id    text not null,
+			run_id        text not null,
+			status        integer not null,
+			completed     boolean not null default false,
+			expire_at     integer not null,
+			created_at    integer not null,
+			name          text not null default ''
+		)`,
+		`create index if not exists ` + t.Timeouts + `_by_completed_expire_at on ` + t.Timeouts + ` (completed, expire_at)`,
+		`create index if not exists ` + t.Timeouts + `_by_workflow_name_status on ` + t.Timeouts + ` (workflow_name, status)`,
+	}
+}
+
+// Migrate creates the tables, and their indexes, when they don't exist yet and adds the columns that were added to
+// the tables after they were first created.
+func Migrate(ctx context.Context, db *sql.DB, t Tables) error {
+	for _, statement := range Migrations(t) {
+		_, err := db.ExecContext(ctx, statement)
+		if err != nil {
+			return fmt.Errorf("migrate: %w", err)
+		}
+	}
+
+	return addColumn(ctx, db, t.Timeouts, "name", "text not null default ''")
+}
+
+// addColumn adds the column to the table when the table does not have it yet as SQLite does not support adding a
+// column only if it does not exist.
+func addColumn(ctx context.Context, db *sql.DB, table, column, definition string) error {
+	rows, err := db.QueryContext(ctx, "select name from pragma_table_info(?)", table)
+	if err != nil {
+		return fmt.Errorf("migrate: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		err := rows.Scan(&name)
+		if err != nil {
+			return fmt.Errorf("migrate: %w", err)
+		}
+
+		if name == column {
+			return nil
+		}
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return fmt.Errorf("migrate: %w", err)
+	}
+
+	_, err = db.ExecContext(ctx, "alter table "+table+" add column "+column+" "+definition)
+	if err != nil {
+		return fmt.Errorf("migrate: %w", err)
+	}
+
+	return nil
+}
+
+// searchIndexTable returns the name of the table that holds the values of the search indexes of the records.
+func searchIndexTable(recordTableName string) string {
+	return recordTableName + "_search_index"
+}
+
+func fromUnixNano(n int64) time.Time {
+	return time.Unix(0, n).UTC()
+}
+
+// row is a common interface for *sql.Rows and *sql.Row.
+type row interface {
+	Scan(dest ...any) error
+}
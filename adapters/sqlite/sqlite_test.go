@@ -0,0 +1,159 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/adaptertest"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+	"github.com/luno/workflow/adapters/sqlite"
+)
+
+func TestRecordStore(t *testing.T) {
+	adaptertest.RunRecordStoreTest(t, func() workflow.RecordStore {
+		db := connectForTesting(t)
+		return sqlite.NewRecordStore(db, sqlite.DefaultTables.Records, sqlite.DefaultTables.Outbox)
+	})
+}
+
+func TestSearchableRecordStore(t *testing.T) {
+	adaptertest.RunSearchableRecordStoreTest(t, func() adaptertest.SearchableRecordStore {
+		db := connectForTesting(t)
+		return sqlite.NewRecordStore(db, sqlite.DefaultTables.Records, sqlite.DefaultTables.Outbox)
+	})
+}
+
+func TestTimeoutStore(t *testing.T) {
+	adaptertest.RunTimeoutStoreTest(t, func() workflow.TimeoutStore {
+		db := connectForTesting(t)
+		return sqlite.NewTimeoutStore(db, sqlite.DefaultTables.Timeouts)
+	})
+}
+
+func TestMigrate_idempotent(t *testing.T) {
+	db := connectForTesting(t)
+
+	err := sqlite.Migrate(context.Background(), db, sqlite.DefaultTables)
+	require.Nil(t, err)
+}
+
+func TestMigrate_addsTimeoutName(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "workflow.db"))
+	require.Nil(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() {
+		require.Nil(t, db.Close())
+	})
+
+	ctx := context.Background()
+
+	// The timeouts table as it was before timers were named.
+	_, err = db.ExecContext(ctx, `create table workflow_timeouts (
+		id            integer primary key autoincrement,
+		workflow_name text not null,
+		foreign_id    text not null,
+		run_id        text not null,
+		status        integer not null,
+		completed     boolean not null default false,
+		expire_at     integer not null,
+		created_at    integer not null
+	)`)
+	require.Nil(t, err)
+
+	err = sqlite.Migrate(ctx, db, sqlite.DefaultTables)
+	require.Nil(t, err)
+
+	store := sqlite.NewTimeoutStore(db, sqlite.DefaultTables.Timeouts)
+	err = store.CreateNamed(ctx, "example", "andrew", "1", int(statusWaiting), "reminder", time.Now())
+	require.Nil(t, err)
+
+	timeouts, err := store.List(ctx, "example")
+	require.Nil(t, err)
+	require.Len(t, timeouts, 1)
+	require.Equal(t, "reminder", timeouts[0].Name)
+}
+
+type status int
+
+const (
+	statusUnknown status = 0
+	statusStart   status = 1
+	statusWaiting status = 2
+	statusEnd     status = 3
+)
+
+func (s status) String() string {
+	switch s {
+	case statusStart:
+		return "Start"
+	case statusWaiting:
+		return "Waiting"
+	case statusEnd:
+		return "End"
+	default:
+		return "Unknown"
+	}
+}
+
+func TestWorkflow(t *testing.T) {
+	db := connectForTesting(t)
+
+	b := workflow.NewBuilder[string, status]("sqlite")
+	b.AddStep(statusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		*r.Object = "started"
+		return statusWaiting, nil
+	}, statusWaiting)
+	b.AddTimeout(
+		statusWaiting,
+		workflow.DurationTimerFunc[string, status](time.Millisecond),
+		func(ctx context.Context, r *workflow.Run[string, status], now time.Time) (status, error) {
+			*r.Object += ", timed out"
+			return statusEnd, nil
+		},
+		statusEnd,
+	).WithOptions(workflow.PollingFrequency(10 * time.Millisecond))
+
+	wf := b.Build(
+		memstreamer.New(),
+		sqlite.NewRecordStore(db, sqlite.DefaultTables.Records, sqlite.DefaultTables.Outbox),
+		memrolescheduler.New(),
+		workflow.WithTimeoutStore(sqlite.NewTimeoutStore(db, sqlite.DefaultTables.Timeouts)),
+		workflow.WithOutboxPollingFrequency(10*time.Millisecond),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "foreignID", statusStart)
+	require.Nil(t, err)
+
+	run, err := wf.Await(ctx, "foreignID", runID, statusEnd, workflow.WithAwaitPollingFrequency(10*time.Millisecond))
+	require.Nil(t, err)
+	require.Equal(t, "started, timed out", *run.Object)
+}
+
+func connectForTesting(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "workflow.db"))
+	require.Nil(t, err)
+
+	// SQLite only supports a single writer.
+	db.SetMaxOpenConns(1)
+
+	err = sqlite.Migrate(context.Background(), db, sqlite.DefaultTables)
+	require.Nil(t, err)
+
+	t.Cleanup(func() {
+		require.Nil(t, db.Close())
+	})
+
+	return db
+}
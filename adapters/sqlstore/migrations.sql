@@ -0,0 +1,5 @@
+-- Migrations for tables created from an earlier version of schema.sql. They must be run, in order, before upgrading.
+-- Name of the table can be different and must match name provided to sqlstore.new("{{table_name}}"...)
+
+-- Adds the meta column that stores the run's RecordMeta. Existing records are left with a null meta.
+alter table workflow_records add column meta longblob null after object;
@@ -6,6 +6,7 @@ create table workflow_records (
     run_state              int not null,
     status                 int not null,
     object                 longblob not null,
+    meta                   longblob null,
     created_at             datetime(3) not null,
     updated_at             datetime(3) not null,
 
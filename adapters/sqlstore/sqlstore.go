@@ -33,7 +33,7 @@ func New(writer *sql.DB, reader *sql.DB, recordTableName string, outboxTableName
 		outboxTableName: outboxTableName,
 	}
 
-	e.recordCols = " `workflow_name`, `foreign_id`, `run_id`, `run_state`, `status`, `object`, `created_at`, `updated_at` "
+	e.recordCols = " `workflow_name`, `foreign_id`, `run_id`, `run_state`, `status`, `object`, `meta`, `created_at`, `updated_at` "
 	e.recordSelectPrefix = " select " + e.recordCols + " from " + e.recordTableName + " where "
 
 	e.outboxCols = " `id`, `workflow_name`, `data`, `created_at` "
@@ -42,7 +42,10 @@ func New(writer *sql.DB, reader *sql.DB, recordTableName string, outboxTableName
 	return e
 }
 
-var _ workflow.RecordStore = (*SQLStore)(nil)
+var (
+	_ workflow.RecordStore   = (*SQLStore)(nil)
+	_ workflow.OutboxCounter = (*SQLStore)(nil)
+)
 
 func (s *SQLStore) Store(ctx context.Context, r *workflow.Record) error {
 	tx, err := s.writer.BeginTx(ctx, nil)
@@ -65,12 +68,12 @@ func (s *SQLStore) Store(ctx context.Context, r *workflow.Record) error {
 	}
 
 	if mustCreate {
-		err := s.create(ctx, tx, r.WorkflowName, r.ForeignID, r.RunID, r.Status, r.Object, int(r.RunState))
+		err := s.create(ctx, tx, r.WorkflowName, r.ForeignID, r.RunID, r.Status, r.Object, int(r.RunState), r.Meta)
 		if err != nil {
 			return err
 		}
 	} else {
-		err := s.update(ctx, tx, r.RunID, r.Status, r.Object, int(r.RunState))
+		err := s.update(ctx, tx, r.RunID, r.Status, r.Object, int(r.RunState), r.Meta)
 		if err != nil {
 			return err
 		}
@@ -120,6 +123,20 @@ func (s *SQLStore) ListOutboxEvents(
 	return s.listOutboxWhere(ctx, s.reader, "workflow_name=? limit ?", workflowName, limit)
 }
 
+func (s *SQLStore) CountOutboxEvents(ctx context.Context, workflowName string) (int64, error) {
+	var count int64
+	err := s.reader.QueryRowContext(
+		ctx,
+		"select count(*) from "+s.outboxTableName+" where workflow_name=?",
+		workflowName,
+	).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
 func (s *SQLStore) DeleteOutboxEvent(ctx context.Context, id string) error {
 	_, err := s.writer.ExecContext(ctx, "delete from "+s.outboxTableName+" where id=?;", id)
 	if err != nil {
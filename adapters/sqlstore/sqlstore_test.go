@@ -1,8 +1,11 @@
 package sqlstore_test
 
 import (
+	"context"
 	"testing"
 
+	"github.com/stretchr/testify/require"
+
 	"github.com/luno/workflow"
 	"github.com/luno/workflow/adapters/adaptertest"
 
@@ -15,3 +18,26 @@ func TestStore(t *testing.T) {
 		return sqlstore.New(dbc, dbc, "workflow_records", "workflow_outbox")
 	})
 }
+
+func TestLookup_NullMeta(t *testing.T) {
+	ctx := context.Background()
+	dbc := ConnectForTesting(t)
+
+	// Records stored before the meta column was added have a null meta.
+	_, err := dbc.ExecContext(ctx, "insert into workflow_records set "+
+		" workflow_name=?, foreign_id=?, run_id=?, run_state=?, status=?, object=?, meta=null, created_at=now(), updated_at=now() ",
+		"example",
+		"andrew",
+		"run-id",
+		int(workflow.RunStateRunning),
+		1,
+		[]byte("{}"),
+	)
+	require.Nil(t, err)
+
+	store := sqlstore.New(dbc, dbc, "workflow_records", "workflow_outbox")
+	record, err := store.Lookup(ctx, "run-id")
+	require.Nil(t, err)
+	require.Equal(t, "andrew", record.ForeignID)
+	require.Equal(t, workflow.RecordMeta{}, record.Meta)
+}
@@ -3,6 +3,7 @@ package sqlstore
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 
 	"github.com/luno/jettison/errors"
 	"github.com/luno/jettison/j"
@@ -16,15 +17,22 @@ func (s *SQLStore) create(
 	status int,
 	object []byte,
 	runState int,
+	meta workflow.RecordMeta,
 ) error {
-	_, err := tx.ExecContext(ctx, "insert into "+s.recordTableName+" set "+
-		" workflow_name=?, foreign_id=?, run_id=?, run_state=?, status=?, object=?, created_at=now(), updated_at=now() ",
+	metaBytes, err := json.Marshal(meta)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal record meta")
+	}
+
+	_, err = tx.ExecContext(ctx, "insert into "+s.recordTableName+" set "+
+		" workflow_name=?, foreign_id=?, run_id=?, run_state=?, status=?, object=?, meta=?, created_at=now(), updated_at=now() ",
 		workflowName,
 		foreignID,
 		runID,
 		runState,
 		status,
 		object,
+		metaBytes,
 	)
 	if err != nil {
 		return errors.Wrap(err, "failed to create entry", j.MKV{
@@ -46,12 +54,19 @@ func (s *SQLStore) update(
 	status int,
 	object []byte,
 	runState int,
+	meta workflow.RecordMeta,
 ) error {
-	_, err := tx.ExecContext(ctx, "update "+s.recordTableName+" set "+
-		" run_state=?, status=?, object=?, updated_at=now() where run_id=?",
+	metaBytes, err := json.Marshal(meta)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal record meta")
+	}
+
+	_, err = tx.ExecContext(ctx, "update "+s.recordTableName+" set "+
+		" run_state=?, status=?, object=?, meta=?, updated_at=now() where run_id=?",
 		runState,
 		status,
 		object,
+		metaBytes,
 		runID,
 	)
 	if err != nil {
@@ -146,7 +161,10 @@ func (s *SQLStore) listOutboxWhere(
 }
 
 func recordScan(row row) (*workflow.Record, error) {
-	var r workflow.Record
+	var (
+		r    workflow.Record
+		meta []byte
+	)
 	err := row.Scan(
 		&r.WorkflowName,
 		&r.ForeignID,
@@ -154,6 +172,7 @@ func recordScan(row row) (*workflow.Record, error) {
 		&r.RunState,
 		&r.Status,
 		&r.Object,
+		&meta,
 		&r.CreatedAt,
 		&r.UpdatedAt,
 	)
@@ -163,6 +182,14 @@ func recordScan(row row) (*workflow.Record, error) {
 		return nil, errors.Wrap(err, "recordScan")
 	}
 
+	// Records created before the meta column was introduced will have a null meta.
+	if len(meta) > 0 {
+		err = json.Unmarshal(meta, &r.Meta)
+		if err != nil {
+			return nil, errors.Wrap(err, "recordScan: unmarshal meta")
+		}
+	}
+
 	return &r, nil
 }
 
@@ -35,6 +35,8 @@ var migrations = []string{
 		primary key (id)
 	)
 `,
+	// Tests run against a table created before the meta column was added to ensure that migrations.sql is valid.
+	`alter table workflow_records add column meta longblob null after object`,
 }
 
 func ConnectForTesting(t *testing.T) *sql.DB {
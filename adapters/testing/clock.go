@@ -0,0 +1,58 @@
+package testing
+
+import (
+	"time"
+
+	clock_testing "k8s.io/utils/clock/testing"
+)
+
+// SkewedClock is the clock of a host whose clock is skewed from the fake Clock of the Adapters. Time only moves when
+// the fake Clock is stepped and timers fire after the same durations as the fake Clock's timers, but the time of the
+// SkewedClock is always offset by the skew.
+type SkewedClock struct {
+	*clock_testing.FakeClock
+
+	skew time.Duration
+}
+
+// Now returns the time of the fake Clock offset by the skew.
+func (c *SkewedClock) Now() time.Time {
+	return c.FakeClock.Now().Add(c.skew)
+}
+
+// Since returns the time elapsed since t according to the SkewedClock.
+func (c *SkewedClock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+// SkewedClock returns a clock for a host whose clock is ahead of the fake Clock by skew, or behind it when the skew is
+// negative. Building a second workflow using the same Adapters and workflow.WithClock(adapters.SkewedClock(skew))
+// simulates a deployment where the clocks of the hosts disagree.
+func (a *Adapters) SkewedClock(skew time.Duration) *SkewedClock {
+	return &SkewedClock{
+		FakeClock: a.Clock,
+		skew:      skew,
+	}
+}
+
+// StepBack moves the fake Clock backwards by d, such as when a host's clock is corrected, without firing any timers.
+func (a *Adapters) StepBack(d time.Duration) {
+	a.Clock.SetTime(a.Clock.Now().Add(-d))
+}
+
+// DSTTransitions returns the times between from and to at which the offset of loc changes, such as for daylight
+// saving time, so that the fake Clock can be set to either side of them to test TimerFuncs that schedule using wall
+// clock times.
+func DSTTransitions(loc *time.Location, from, to time.Time) []time.Time {
+	var transitions []time.Time
+	t := from
+	for {
+		_, end := t.In(loc).ZoneBounds()
+		if end.IsZero() || end.After(to) {
+			return transitions
+		}
+
+		transitions = append(transitions, end)
+		t = end
+	}
+}
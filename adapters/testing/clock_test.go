@@ -0,0 +1,119 @@
+package testing_test
+
+import (
+	"context"
+	"testing"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	workflowtesting "github.com/luno/workflow/adapters/testing"
+)
+
+func TestSkewedClock(t *testing.T) {
+	adapters := workflowtesting.New()
+
+	behind := adapters.SkewedClock(-10 * time.Minute)
+	require.Equal(t, workflowtesting.DefaultStartTime.Add(-10*time.Minute), behind.Now())
+
+	// Timers of the skewed clock fire after the same durations as the timers of the fake clock.
+	timer := behind.NewTimer(time.Hour)
+	adapters.Clock.Step(time.Hour)
+	<-timer.C()
+
+	require.Equal(t, 50*time.Minute, behind.Since(workflowtesting.DefaultStartTime))
+
+	adapters.StepBack(time.Hour)
+	require.Equal(t, workflowtesting.DefaultStartTime, adapters.Clock.Now())
+}
+
+func TestDSTTransitions(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	require.Nil(t, err)
+
+	transitions := workflowtesting.DSTTransitions(
+		london,
+		time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+	)
+	require.Equal(t, []time.Time{
+		time.Date(2024, time.March, 31, 1, 0, 0, 0, time.UTC),
+		time.Date(2024, time.October, 27, 1, 0, 0, 0, time.UTC),
+	}, utc(transitions))
+
+	require.Empty(t, workflowtesting.DSTTransitions(
+		time.UTC,
+		time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+	))
+}
+
+func TestReminderAcrossDSTTransition(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	require.Nil(t, err)
+
+	// nineAM schedules the reminder for the next 9am in London.
+	nineAM := func(ctx context.Context, r *workflow.Run[string, status], now time.Time) (time.Time, error) {
+		local := now.In(london)
+		next := time.Date(local.Year(), local.Month(), local.Day(), 9, 0, 0, 0, london)
+		if !next.After(now) {
+			next = time.Date(local.Year(), local.Month(), local.Day()+1, 9, 0, 0, 0, london)
+		}
+
+		return next, nil
+	}
+
+	reminders := make(chan time.Time, 10)
+	b := workflow.NewBuilder[string, status]("dst reminders")
+	b.AddReminder(statusWaiting, "daily", nineAM, func(ctx context.Context, r *workflow.Run[string, status], now time.Time) error {
+		reminders <- now
+		return nil
+	}).WithOptions(workflow.PollingFrequency(10 * time.Millisecond))
+	b.AddStep(statusWaiting, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return r.Skip()
+	}, statusCompleted)
+
+	// Start the clock on the morning before the clocks go forward.
+	transition := workflowtesting.DSTTransitions(
+		london,
+		time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC),
+	)[0]
+	adapters := workflowtesting.New(workflowtesting.WithStartTime(transition.Add(-20 * time.Hour)))
+	wf := workflowtesting.Build(b, adapters)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "foreignID", statusWaiting)
+	require.Nil(t, err)
+
+	workflow.AwaitTimeoutInsert(t, wf, "foreignID", runID, statusWaiting)
+
+	adapters.Clock.SetTime(time.Date(2024, time.March, 30, 9, 0, 0, 0, london))
+	require.Equal(t, time.Date(2024, time.March, 30, 9, 0, 0, 0, time.UTC), (<-reminders).UTC())
+
+	// The next reminder is at 9am British Summer Time which is an hour earlier in UTC.
+	next := time.Date(2024, time.March, 31, 8, 0, 0, 0, time.UTC)
+	require.Eventually(t, func() bool {
+		pending, err := wf.PendingTimeouts(ctx, runID)
+		require.Nil(t, err)
+
+		return len(pending) == 1 && pending[0].ExpireAt.Equal(next)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	adapters.Clock.SetTime(next)
+	require.Equal(t, next, (<-reminders).UTC())
+}
+
+func utc(ts []time.Time) []time.Time {
+	var converted []time.Time
+	for _, t := range ts {
+		converted = append(converted, t.UTC())
+	}
+
+	return converted
+}
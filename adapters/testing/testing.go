@@ -0,0 +1,86 @@
+// Package testing bundles the in-memory adapters so that workflows can be unit tested without Kafka, a database, or
+// a role scheduling service. All the adapters share a fake clock which allows timeouts and time based behaviour to be
+// tested deterministically. Import the package with an alias, such as workflowtesting, to avoid conflicting with the
+// standard library's testing package.
+package testing
+
+import (
+	"time"
+
+	clock_testing "k8s.io/utils/clock/testing"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+	"github.com/luno/workflow/adapters/memtimeoutstore"
+)
+
+// DefaultStartTime is the time that the fake clock starts at, unless configured with WithStartTime, so that tests
+// don't depend on when they are run.
+var DefaultStartTime = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Adapters holds an in-memory implementation of each adapter that share the same fake Clock.
+type Adapters struct {
+	Clock         *clock_testing.FakeClock
+	EventStreamer *memstreamer.StreamConstructor
+	RecordStore   *memrecordstore.Store
+	TimeoutStore  *memtimeoutstore.Store
+	RoleScheduler *memrolescheduler.RoleScheduler
+}
+
+type options struct {
+	startTime time.Time
+}
+
+type Option func(o *options)
+
+// WithStartTime sets the time that the fake clock starts at.
+func WithStartTime(t time.Time) Option {
+	return func(o *options) {
+		o.startTime = t
+	}
+}
+
+// New returns a new set of in-memory adapters that do not share any state with other sets of adapters.
+func New(opts ...Option) *Adapters {
+	o := options{
+		startTime: DefaultStartTime,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	clock := clock_testing.NewFakeClock(o.startTime)
+	return &Adapters{
+		Clock:         clock,
+		EventStreamer: memstreamer.New(memstreamer.WithClock(clock)),
+		RecordStore:   memrecordstore.New(memrecordstore.WithClock(clock)),
+		TimeoutStore:  memtimeoutstore.New(memtimeoutstore.WithClock(clock)),
+		RoleScheduler: memrolescheduler.New(),
+	}
+}
+
+// BuildOptions configures the workflow to use the fake Clock and the in-memory TimeoutStore.
+func (a *Adapters) BuildOptions() []workflow.BuildOption {
+	return []workflow.BuildOption{
+		workflow.WithClock(a.Clock),
+		workflow.WithTimeoutStore(a.TimeoutStore),
+	}
+}
+
+// Build builds the workflow using the in-memory adapters. The provided options are applied after the options of
+// BuildOptions and so can override them.
+func Build[Type any, Status workflow.StatusType](
+	b *workflow.Builder[Type, Status],
+	a *Adapters,
+	opts ...workflow.BuildOption,
+) *workflow.Workflow[Type, Status] {
+	return b.Build(
+		a.EventStreamer,
+		a.RecordStore,
+		a.RoleScheduler,
+		append(a.BuildOptions(), opts...)...,
+	)
+}
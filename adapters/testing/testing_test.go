@@ -0,0 +1,78 @@
+package testing_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	workflowtesting "github.com/luno/workflow/adapters/testing"
+)
+
+type status int
+
+const (
+	statusUnknown   status = 0
+	statusStarted   status = 1
+	statusWaiting   status = 2
+	statusCompleted status = 3
+)
+
+func (s status) String() string {
+	switch s {
+	case statusStarted:
+		return "Started"
+	case statusWaiting:
+		return "Waiting"
+	case statusCompleted:
+		return "Completed"
+	default:
+		return "Unknown"
+	}
+}
+
+func TestBuild(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("testing adapters")
+	b.AddStep(statusStarted, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		*r.Object = "started"
+		return statusWaiting, nil
+	}, statusWaiting)
+	b.AddTimeout(
+		statusWaiting,
+		workflow.DurationTimerFunc[string, status](time.Hour),
+		func(ctx context.Context, r *workflow.Run[string, status], now time.Time) (status, error) {
+			*r.Object += " at " + now.Format(time.RFC3339)
+			return statusCompleted, nil
+		},
+		statusCompleted,
+	)
+
+	adapters := workflowtesting.New()
+	wf := workflowtesting.Build(b, adapters)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "foreignID", statusStarted)
+	require.Nil(t, err)
+
+	workflow.AwaitTimeoutInsert(t, wf, "foreignID", runID, statusWaiting)
+
+	adapters.Clock.Step(time.Hour)
+
+	workflow.Require(t, wf, "foreignID", statusCompleted, "started at 2024-01-01T01:00:00Z")
+}
+
+func TestNew(t *testing.T) {
+	start := time.Date(2023, time.June, 1, 0, 0, 0, 0, time.UTC)
+	adapters := workflowtesting.New(workflowtesting.WithStartTime(start))
+	require.Equal(t, start, adapters.Clock.Now())
+
+	// Each set of adapters has its own state.
+	other := workflowtesting.New()
+	require.Equal(t, workflowtesting.DefaultStartTime, other.Clock.Now())
+	require.NotSame(t, adapters.RecordStore, other.RecordStore)
+}
@@ -15,7 +15,19 @@ type ObjectDataRequest struct {
 
 type LookupFn func(ctx context.Context, runID string) (*workflow.Record, error)
 
-func ObjectData(lookup LookupFn) http.HandlerFunc {
+// Redactor provides the redacted view of the Object of a workflow's records. *workflow.Workflow implements Redactor
+// using the redaction configured with workflow.WithRedaction.
+type Redactor interface {
+	Name() string
+	Redact(record *workflow.Record) ([]byte, error)
+}
+
+func ObjectData(lookup LookupFn, redactors ...Redactor) http.HandlerFunc {
+	redactorByName := make(map[string]Redactor)
+	for _, redactor := range redactors {
+		redactorByName[redactor.Name()] = redactor
+	}
+
 	return func(w http.ResponseWriter, r *http.Request) {
 		body, err := io.ReadAll(r.Body)
 		if err != nil {
@@ -36,6 +48,15 @@ func ObjectData(lookup LookupFn) http.HandlerFunc {
 			return
 		}
 
-		_, _ = w.Write(record.Object)
+		object := record.Object
+		if redactor, ok := redactorByName[record.WorkflowName]; ok {
+			object, err = redactor.Redact(record)
+			if err != nil {
+				http.Error(w, "failed to redact record", http.StatusInternalServerError)
+				return
+			}
+		}
+
+		_, _ = w.Write(object)
 	}
 }
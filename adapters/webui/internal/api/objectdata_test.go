@@ -56,3 +56,77 @@ func TestObjectDataHandler(t *testing.T) {
 
 	require.Equal(t, expectedResponseData, actualResp)
 }
+
+type testRedactor struct{}
+
+func (testRedactor) Name() string {
+	return "example"
+}
+
+func (testRedactor) Redact(record *workflow.Record) ([]byte, error) {
+	var data testObjectData
+	err := workflow.Unmarshal(record.Object, &data)
+	if err != nil {
+		return nil, err
+	}
+
+	data.Email = "****"
+	return workflow.Marshal(&data)
+}
+
+func TestObjectDataHandler_Redacted(t *testing.T) {
+	stored := testObjectData{
+		Name:  "Andrew Wormald",
+		Email: "andrew@workflow.com",
+	}
+
+	testCases := []struct {
+		name         string
+		workflowName string
+		expected     testObjectData
+	}{
+		{
+			name:         "Redacts records of workflows with a redactor",
+			workflowName: "example",
+			expected: testObjectData{
+				Name:  "Andrew Wormald",
+				Email: "****",
+			},
+		},
+		{
+			name:         "Returns the object of workflows without a redactor",
+			workflowName: "other",
+			expected:     stored,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(api.ObjectData(func(ctx context.Context, runID string) (*workflow.Record, error) {
+				b, err := workflow.Marshal(&stored)
+				require.NoError(t, err)
+
+				return &workflow.Record{
+					WorkflowName: tc.workflowName,
+					Object:       b,
+				}, nil
+			}, testRedactor{}))
+			t.Cleanup(srv.Close)
+
+			body, err := json.Marshal(api.ObjectDataRequest{RunID: "1"})
+			require.NoError(t, err)
+
+			resp, err := http.Post(srv.URL, "application/json", bytes.NewReader(body))
+			require.NoError(t, err)
+			require.Equal(t, 200, resp.StatusCode)
+
+			respBody, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+
+			var actual testObjectData
+			err = json.Unmarshal(respBody, &actual)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
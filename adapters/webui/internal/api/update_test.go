@@ -45,6 +45,16 @@ func TestUpdateHandler(t *testing.T) {
 					RunID:        "1",
 					RunState:     workflow.RunStatePaused,
 					Status:       2,
+					Meta: workflow.RecordMeta{
+						History: []workflow.Transition{
+							{
+								FromStatus:   2,
+								ToStatus:     2,
+								FromRunState: workflow.RunStateRunning,
+								ToRunState:   workflow.RunStatePaused,
+							},
+						},
+					},
 				},
 			},
 			expectedStatusCode: 200,
@@ -71,6 +81,16 @@ func TestUpdateHandler(t *testing.T) {
 					RunID:        "1",
 					RunState:     workflow.RunStateRunning,
 					Status:       2,
+					Meta: workflow.RecordMeta{
+						History: []workflow.Transition{
+							{
+								FromStatus:   2,
+								ToStatus:     2,
+								FromRunState: workflow.RunStatePaused,
+								ToRunState:   workflow.RunStateRunning,
+							},
+						},
+					},
 				},
 			},
 			expectedStatusCode: 200,
@@ -97,6 +117,16 @@ func TestUpdateHandler(t *testing.T) {
 					RunID:        "1",
 					RunState:     workflow.RunStateCancelled,
 					Status:       2,
+					Meta: workflow.RecordMeta{
+						History: []workflow.Transition{
+							{
+								FromStatus:   2,
+								ToStatus:     2,
+								FromRunState: workflow.RunStateRunning,
+								ToRunState:   workflow.RunStateCancelled,
+							},
+						},
+					},
 				},
 			},
 			expectedStatusCode: 200,
@@ -124,6 +154,16 @@ func TestUpdateHandler(t *testing.T) {
 					RunState:     workflow.RunStateRequestedDataDeleted,
 					Status:       9,
 					Object:       []byte("Deleted"),
+					Meta: workflow.RecordMeta{
+						History: []workflow.Transition{
+							{
+								FromStatus:   9,
+								ToStatus:     9,
+								FromRunState: workflow.RunStateCompleted,
+								ToRunState:   workflow.RunStateRequestedDataDeleted,
+							},
+						},
+					},
 				},
 			},
 			expectedStatusCode: 200,
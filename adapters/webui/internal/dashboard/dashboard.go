@@ -0,0 +1,337 @@
+package dashboard
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/luno/workflow"
+)
+
+//go:embed static
+var static embed.FS
+
+// Workflow is implemented by every *workflow.Workflow.
+type Workflow interface {
+	workflow.AdminWorkflow
+	States() map[string]workflow.State
+	Stats() workflow.Stats
+}
+
+// defaultRunStates are the run states of the runs that need attention and are listed when no run states are
+// requested.
+var defaultRunStates = []workflow.RunState{workflow.RunStatePaused, workflow.RunStateQuarantined}
+
+const maxRuns = 100
+
+// Handler serves the dashboard's static assets and the JSON API that the dashboard polls.
+func Handler(workflows ...Workflow) http.Handler {
+	h := &handler{
+		workflows: make(map[string]Workflow),
+	}
+
+	for _, w := range workflows {
+		if _, ok := h.workflows[w.Name()]; ok {
+			panic("duplicate workflow provided to dashboard: " + w.Name())
+		}
+
+		h.workflows[w.Name()] = w
+		h.names = append(h.names, w.Name())
+	}
+
+	sort.Strings(h.names)
+
+	assets, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /", http.FileServerFS(assets))
+	mux.HandleFunc("GET /api/workflows", h.listWorkflows)
+	mux.HandleFunc("GET /api/workflows/{workflow}/runs", h.withWorkflow(h.listRuns))
+	mux.HandleFunc("GET /api/workflows/{workflow}/runs/{run_id}/timeline", h.withWorkflow(h.timeline))
+
+	return mux
+}
+
+type handler struct {
+	workflows map[string]Workflow
+	names     []string
+}
+
+// WorkflowSummary is the live overview of a workflow that is shown on the dashboard.
+type WorkflowSummary struct {
+	Name string `json:"name"`
+	// Mermaid is the diagram of the workflow's graph in Mermaid syntax.
+	Mermaid   string      `json:"mermaid"`
+	Statuses  []Status    `json:"statuses"`
+	Processes []Process   `json:"processes"`
+	Lag       []StatusLag `json:"lag"`
+}
+
+type Status struct {
+	Status      int    `json:"status"`
+	Description string `json:"description"`
+	Starting    bool   `json:"starting"`
+	Terminal    bool   `json:"terminal"`
+}
+
+// Process is the state of one of the workflow's processes running on this instance.
+type Process struct {
+	Name  string `json:"name"`
+	State string `json:"state"`
+}
+
+// StatusLag is the lag of the step consumers of a status running on this instance.
+type StatusLag struct {
+	Status      int    `json:"status"`
+	Description string `json:"description"`
+	// LagMillis is the largest lag of the shards of the status' consumers.
+	LagMillis       int64 `json:"lag_millis"`
+	ProcessedEvents int64 `json:"processed_events"`
+	Shards          int   `json:"shards"`
+	HotShards       int   `json:"hot_shards"`
+}
+
+// Run is a run that is shown on the dashboard.
+type Run struct {
+	ForeignID   string    `json:"foreign_id"`
+	RunID       string    `json:"run_id"`
+	Status      int       `json:"status"`
+	Description string    `json:"description"`
+	RunState    string    `json:"run_state"`
+	LastError   string    `json:"last_error,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Timeline is the run and the transitions that it has made, in order.
+type Timeline struct {
+	Run         Run          `json:"run"`
+	Transitions []Transition `json:"transitions"`
+}
+
+type Transition struct {
+	FromStatus      string    `json:"from_status,omitempty"`
+	ToStatus        string    `json:"to_status"`
+	FromRunState    string    `json:"from_run_state,omitempty"`
+	ToRunState      string    `json:"to_run_state"`
+	At              time.Time `json:"at"`
+	SincePrevMillis int64     `json:"since_prev_millis"`
+	Process         string    `json:"process,omitempty"`
+	Error           string    `json:"error,omitempty"`
+	Actor           string    `json:"actor,omitempty"`
+	Reason          string    `json:"reason,omitempty"`
+	Forced          bool      `json:"forced,omitempty"`
+	Replay          bool      `json:"replay,omitempty"`
+}
+
+func (h *handler) withWorkflow(fn func(w Workflow, rw http.ResponseWriter, r *http.Request)) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		w, ok := h.workflows[r.PathValue("workflow")]
+		if !ok {
+			http.Error(rw, "workflow not found", http.StatusNotFound)
+			return
+		}
+
+		fn(w, rw, r)
+	}
+}
+
+func (h *handler) listWorkflows(rw http.ResponseWriter, r *http.Request) {
+	summaries := make([]WorkflowSummary, 0, len(h.names))
+	for _, name := range h.names {
+		summaries = append(summaries, summarise(h.workflows[name]))
+	}
+
+	writeJSON(rw, summaries)
+}
+
+func summarise(w Workflow) WorkflowSummary {
+	g := w.Graph()
+	descriptions := make(map[int]string, len(g.Statuses))
+	summary := WorkflowSummary{
+		Name:      w.Name(),
+		Mermaid:   g.MermaidDiagram(),
+		Statuses:  make([]Status, 0, len(g.Statuses)),
+		Processes: []Process{},
+		Lag:       []StatusLag{},
+	}
+
+	for _, s := range g.Statuses {
+		descriptions[s.Status] = s.Description
+		summary.Statuses = append(summary.Statuses, Status{
+			Status:      s.Status,
+			Description: s.Description,
+			Starting:    s.Starting,
+			Terminal:    s.Terminal,
+		})
+	}
+
+	for name, state := range w.States() {
+		summary.Processes = append(summary.Processes, Process{Name: name, State: state.String()})
+	}
+
+	sort.Slice(summary.Processes, func(i, j int) bool {
+		return summary.Processes[i].Name < summary.Processes[j].Name
+	})
+
+	lagByStatus := make(map[int]*StatusLag)
+	for _, shard := range w.Stats().Shards {
+		lag, ok := lagByStatus[shard.Status]
+		if !ok {
+			lag = &StatusLag{
+				Status:      shard.Status,
+				Description: descriptions[shard.Status],
+			}
+			lagByStatus[shard.Status] = lag
+		}
+
+		lag.LagMillis = max(lag.LagMillis, shard.Lag.Milliseconds())
+		lag.ProcessedEvents += shard.ProcessedEvents
+		lag.Shards++
+		if shard.Hot {
+			lag.HotShards++
+		}
+	}
+
+	for _, lag := range lagByStatus {
+		summary.Lag = append(summary.Lag, *lag)
+	}
+
+	sort.Slice(summary.Lag, func(i, j int) bool {
+		return summary.Lag[i].Status < summary.Lag[j].Status
+	})
+
+	return summary
+}
+
+func (h *handler) listRuns(w Workflow, rw http.ResponseWriter, r *http.Request) {
+	runStates := defaultRunStates
+	if values := r.URL.Query()["run_state"]; len(values) > 0 {
+		runStates = nil
+		for _, v := range values {
+			rs, err := strconv.Atoi(v)
+			if err != nil || !workflow.RunState(rs).Valid() {
+				http.Error(rw, "invalid run_state", http.StatusBadRequest)
+				return
+			}
+
+			runStates = append(runStates, workflow.RunState(rs))
+		}
+	}
+
+	records, err := w.AdminListRuns(r.Context(), workflow.AdminRunFilter{
+		RunStates: runStates,
+		Limit:     maxRuns,
+		Order:     workflow.OrderTypeDescending,
+	})
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	descriptions := statusDescriptions(w.Graph())
+	runs := make([]Run, 0, len(records))
+	for i := range records {
+		runs = append(runs, newRun(&records[i], descriptions))
+	}
+
+	writeJSON(rw, runs)
+}
+
+func (h *handler) timeline(w Workflow, rw http.ResponseWriter, r *http.Request) {
+	record, err := w.AdminGetRun(r.Context(), r.PathValue("run_id"))
+	if errors.Is(err, workflow.ErrRecordNotFound) {
+		http.Error(rw, "run not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	descriptions := statusDescriptions(w.Graph())
+	timeline := Timeline{
+		Run:         newRun(record, descriptions),
+		Transitions: make([]Transition, 0, len(record.Meta.History)),
+	}
+
+	var prev time.Time
+	for _, t := range record.Meta.History {
+		transition := Transition{
+			ToStatus:   descriptions[t.ToStatus],
+			ToRunState: t.ToRunState.String(),
+			At:         t.At,
+			Process:    t.Process,
+			Error:      t.Error,
+			Actor:      t.Actor,
+			Reason:     t.Reason,
+			Forced:     t.Forced,
+			Replay:     t.Replay,
+		}
+
+		if t.FromStatus != 0 {
+			transition.FromStatus = descriptions[t.FromStatus]
+		}
+
+		if t.FromRunState != workflow.RunStateUnknown {
+			transition.FromRunState = t.FromRunState.String()
+		}
+
+		if !prev.IsZero() {
+			transition.SincePrevMillis = t.At.Sub(prev).Milliseconds()
+		}
+
+		prev = t.At
+		timeline.Transitions = append(timeline.Transitions, transition)
+	}
+
+	writeJSON(rw, timeline)
+}
+
+func newRun(r *workflow.Record, descriptions map[int]string) Run {
+	run := Run{
+		ForeignID:   r.ForeignID,
+		RunID:       r.RunID,
+		Status:      r.Status,
+		Description: descriptions[r.Status],
+		RunState:    r.RunState.String(),
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+
+	// The last error is the error of the latest transition that was caused by one, such as the step's error that
+	// paused the run.
+	for i := len(r.Meta.History) - 1; i >= 0; i-- {
+		if r.Meta.History[i].Error != "" {
+			run.LastError = r.Meta.History[i].Error
+			break
+		}
+	}
+
+	if run.LastError == "" {
+		run.LastError = r.Meta.QuarantineReason
+	}
+
+	return run
+}
+
+func statusDescriptions(g workflow.Graph) map[int]string {
+	descriptions := make(map[int]string, len(g.Statuses))
+	for _, s := range g.Statuses {
+		descriptions[s.Status] = s.Description
+	}
+
+	return descriptions
+}
+
+func writeJSON(rw http.ResponseWriter, v any) {
+	rw.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(rw).Encode(v)
+}
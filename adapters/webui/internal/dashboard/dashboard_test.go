@@ -0,0 +1,152 @@
+package dashboard_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+
+	"github.com/luno/workflow/adapters/webui/internal/dashboard"
+)
+
+type status int
+
+const (
+	statusUnknown status = 0
+	statusStart   status = 1
+	statusEnd     status = 2
+)
+
+func (s status) String() string {
+	switch s {
+	case statusStart:
+		return "Start"
+	case statusEnd:
+		return "End"
+	default:
+		return "Unknown"
+	}
+}
+
+func TestDashboard(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("orders")
+	b.AddStep(statusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		if r.ForeignID == "broken" {
+			return 0, errors.New("payment provider unavailable")
+		}
+
+		return statusEnd, nil
+	}, statusEnd).WithOptions(
+		workflow.PauseAfterErrCount(1),
+		workflow.ErrBackOff(time.Millisecond),
+	)
+
+	wf := b.Build(memstreamer.New(), memrecordstore.New(), memrolescheduler.New())
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	okRunID, err := wf.Trigger(ctx, "ok", statusStart)
+	require.Nil(t, err)
+
+	_, err = wf.Await(ctx, "ok", okRunID, statusEnd)
+	require.Nil(t, err)
+
+	brokenRunID, err := wf.Trigger(ctx, "broken", statusStart)
+	require.Nil(t, err)
+
+	srv := httptest.NewServer(dashboard.Handler(wf))
+	t.Cleanup(srv.Close)
+
+	var runs []dashboard.Run
+	require.Eventually(t, func() bool {
+		get(t, srv, "/api/workflows/orders/runs", http.StatusOK, &runs)
+		return len(runs) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, brokenRunID, runs[0].RunID)
+	require.Equal(t, "Start", runs[0].Description)
+	require.Equal(t, "Paused", runs[0].RunState)
+	require.Contains(t, runs[0].LastError, "payment provider unavailable")
+
+	var summaries []dashboard.WorkflowSummary
+	get(t, srv, "/api/workflows", http.StatusOK, &summaries)
+	require.Len(t, summaries, 1)
+
+	summary := summaries[0]
+	require.Equal(t, "orders", summary.Name)
+	require.Contains(t, summary.Mermaid, "Start")
+	require.Len(t, summary.Statuses, 2)
+	require.NotEmpty(t, summary.Processes)
+	require.Len(t, summary.Lag, 1)
+	require.Equal(t, int(statusStart), summary.Lag[0].Status)
+	require.Equal(t, int64(2), summary.Lag[0].ProcessedEvents)
+
+	var timeline dashboard.Timeline
+	get(t, srv, "/api/workflows/orders/runs/"+okRunID+"/timeline", http.StatusOK, &timeline)
+	require.Equal(t, "ok", timeline.Run.ForeignID)
+
+	last := timeline.Transitions[len(timeline.Transitions)-1]
+	require.Equal(t, "Start", last.FromStatus)
+	require.Equal(t, "End", last.ToStatus)
+	require.Equal(t, "Completed", last.ToRunState)
+
+	get(t, srv, "/api/workflows/payments/runs", http.StatusNotFound, nil)
+	get(t, srv, "/api/workflows/orders/runs/missing/timeline", http.StatusNotFound, nil)
+	get(t, srv, "/api/workflows/orders/runs?run_state=42", http.StatusBadRequest, nil)
+}
+
+func TestDashboardAssets(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("orders")
+	b.AddStep(statusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return statusEnd, nil
+	}, statusEnd)
+
+	wf := b.Build(memstreamer.New(), memrecordstore.New(), memrolescheduler.New())
+
+	mux := http.NewServeMux()
+	mux.Handle("/dashboard/", http.StripPrefix("/dashboard", dashboard.Handler(wf)))
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	for path, contains := range map[string]string{
+		"/dashboard/":             `<script src="dashboard.js"></script>`,
+		"/dashboard/dashboard.js": "const workflowsPath = 'api/workflows'",
+	} {
+		resp, err := http.Get(srv.URL + path)
+		require.Nil(t, err)
+
+		body, err := io.ReadAll(resp.Body)
+		require.Nil(t, err)
+		_ = resp.Body.Close()
+
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Contains(t, string(body), contains)
+	}
+}
+
+func get(t *testing.T, srv *httptest.Server, path string, code int, resp any) {
+	t.Helper()
+
+	res, err := http.Get(srv.URL + path)
+	require.Nil(t, err)
+	defer res.Body.Close()
+
+	require.Equal(t, code, res.StatusCode)
+
+	if resp != nil {
+		err = json.NewDecoder(res.Body).Decode(resp)
+		require.Nil(t, err)
+	}
+}
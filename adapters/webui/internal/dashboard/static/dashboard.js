@@ -0,0 +1,224 @@
+document.addEventListener("DOMContentLoaded", function() {
+    mermaid.initialize({ startOnLoad: false });
+    pollForUpdates();
+});
+
+// Paths are relative so that the dashboard can be mounted under any prefix.
+const workflowsPath = 'api/workflows'
+
+let selectedWorkflow = ''
+let renderedMermaid = ''
+
+async function pollForUpdates() {
+    await refresh();
+    setTimeout(pollForUpdates, 5000);
+}
+
+async function refresh() {
+    const workflows = await fetchJSON(workflowsPath);
+    if (!workflows || workflows.length === 0) {
+        return;
+    }
+
+    if (!workflows.some(w => w.name === selectedWorkflow)) {
+        selectedWorkflow = workflows[0].name;
+    }
+
+    renderWorkflows(workflows);
+
+    const summary = workflows.find(w => w.name === selectedWorkflow);
+    document.getElementById('workflowName').textContent = summary.name;
+    await renderGraph(summary.mermaid);
+    renderLag(summary.lag);
+    renderProcesses(summary.processes);
+
+    const runs = await fetchJSON(`${workflowsPath}/${encodeURIComponent(selectedWorkflow)}/runs`);
+    renderRuns(runs || []);
+}
+
+function selectWorkflow(name) {
+    selectedWorkflow = name;
+    document.getElementById('timelineSection').classList.add('hidden');
+    refresh();
+}
+
+function renderWorkflows(workflows) {
+    const list = document.getElementById('workflows');
+    list.innerHTML = '';
+
+    workflows.forEach(w => {
+        const item = document.createElement('li');
+        const selected = w.name === selectedWorkflow ? 'bg-blue-50 dark:bg-blue-800 font-bold' : '';
+        item.className = `cursor-pointer rounded px-2 py-1 hover:bg-gray-100 dark:hover:bg-gray-700 ${selected}`;
+        item.textContent = w.name;
+        item.onclick = () => selectWorkflow(w.name);
+        list.appendChild(item);
+    });
+}
+
+async function renderGraph(diagram) {
+    // Only re-render the diagram when it changes as rendering is expensive and resets the scroll position.
+    if (diagram === renderedMermaid) {
+        return;
+    }
+
+    const { svg } = await mermaid.render('workflowGraph', diagram);
+    document.getElementById('graph').innerHTML = svg;
+    renderedMermaid = diagram;
+}
+
+function renderLag(lag) {
+    const tableBody = document.getElementById('lag');
+    tableBody.innerHTML = '';
+
+    if (lag.length === 0) {
+        tableBody.innerHTML = `<tr><td colspan="4" class="py-2 text-gray-500">No step consumers running on this instance</td></tr>`;
+        return;
+    }
+
+    lag.forEach(l => {
+        const hot = l.hot_shards > 0 ? ` <span class="text-red-600 dark:text-red-300">(${l.hot_shards} hot)</span>` : '';
+        const row = document.createElement('tr');
+        row.className = 'border-t border-gray-200 dark:border-gray-700';
+        row.innerHTML = `
+            <td class="py-2">${escapeHTML(l.description)}</td>
+            <td class="py-2">${formatDuration(l.lag_millis)}</td>
+            <td class="py-2">${l.processed_events}</td>
+            <td class="py-2">${l.shards}${hot}</td>`;
+        tableBody.appendChild(row);
+    });
+}
+
+function renderProcesses(processes) {
+    const container = document.getElementById('processes');
+    container.innerHTML = '';
+
+    processes.forEach(p => {
+        const badge = document.createElement('span');
+        badge.className = `text-xs py-1 px-3 rounded-full ${stateClass(p.state)}`;
+        badge.textContent = `${p.name}: ${p.state}`;
+        container.appendChild(badge);
+    });
+}
+
+function stateClass(state) {
+    switch (state) {
+        case 'Running':
+            return 'bg-green-50 dark:bg-green-800 text-green-600 dark:text-green-300';
+        case 'Idle':
+            return 'bg-gray-50 dark:bg-gray-700 text-gray-600 dark:text-gray-300';
+        case 'Paused':
+            return 'bg-yellow-50 dark:bg-yellow-800 text-yellow-600 dark:text-yellow-300';
+        default:
+            return 'bg-red-50 dark:bg-red-800 text-red-600 dark:text-red-300';
+    }
+}
+
+function renderRuns(runs) {
+    const tableBody = document.getElementById('runs');
+    tableBody.innerHTML = '';
+
+    if (runs.length === 0) {
+        tableBody.innerHTML = `<tr><td colspan="6" class="py-2 text-gray-500">No runs need attention</td></tr>`;
+        return;
+    }
+
+    runs.forEach(run => {
+        const row = document.createElement('tr');
+        row.className = 'border-t border-gray-200 dark:border-gray-700 cursor-pointer hover:bg-gray-50 dark:hover:bg-gray-700';
+        row.onclick = () => showTimeline(run.run_id);
+        row.innerHTML = `
+            <td class="py-2">${escapeHTML(run.foreign_id)}</td>
+            <td class="py-2 font-mono">${escapeHTML(run.run_id)}</td>
+            <td class="py-2">${escapeHTML(run.description)}</td>
+            <td class="py-2">${escapeHTML(run.run_state)}</td>
+            <td class="py-2 text-red-600 dark:text-red-300">${escapeHTML(run.last_error || '')}</td>
+            <td class="py-2">${formatTime(run.updated_at)}</td>`;
+        tableBody.appendChild(row);
+    });
+}
+
+async function showTimeline(runID) {
+    const timeline = await fetchJSON(`${workflowsPath}/${encodeURIComponent(selectedWorkflow)}/runs/${encodeURIComponent(runID)}/timeline`);
+    if (!timeline) {
+        return;
+    }
+
+    document.getElementById('timelineTitle').textContent = `Timeline of ${timeline.run.foreign_id} (${timeline.run.run_id})`;
+
+    const list = document.getElementById('timeline');
+    list.innerHTML = '';
+
+    timeline.transitions.forEach(t => {
+        let change = escapeHTML(t.to_status);
+        if (t.from_status && t.from_status !== t.to_status) {
+            change = `${escapeHTML(t.from_status)} &rarr; ${change}`;
+        }
+
+        if (t.from_run_state !== t.to_run_state) {
+            change += ` <span class="text-gray-500">[${escapeHTML(t.from_run_state || 'Created')} &rarr; ${escapeHTML(t.to_run_state)}]</span>`;
+        }
+
+        const details = [];
+        if (t.process) details.push(`process: ${escapeHTML(t.process)}`);
+        if (t.actor) details.push(`actor: ${escapeHTML(t.actor)}`);
+        if (t.reason) details.push(`reason: ${escapeHTML(t.reason)}`);
+        if (t.forced) details.push('forced');
+        if (t.replay) details.push('replay');
+
+        const item = document.createElement('li');
+        item.className = 'ml-4';
+        item.innerHTML = `
+            <div class="text-xs text-gray-500">${formatTime(t.at)} (+${formatDuration(t.since_prev_millis)})</div>
+            <div>${change}</div>
+            <div class="text-xs text-gray-500">${details.join(', ')}</div>
+            ${t.error ? `<div class="text-xs text-red-600 dark:text-red-300">${escapeHTML(t.error)}</div>` : ''}`;
+        list.appendChild(item);
+    });
+
+    document.getElementById('timelineSection').classList.remove('hidden');
+}
+
+async function fetchJSON(path) {
+    try {
+        const response = await fetch(path);
+        if (!response.ok) {
+            console.error(`Failed to fetch ${path}: ${response.status}`);
+            return null;
+        }
+
+        return await response.json();
+    } catch (error) {
+        console.error(`Failed to fetch ${path}:`, error);
+        return null;
+    }
+}
+
+function formatTime(timestamp) {
+    return new Date(timestamp).toISOString().replace('T', ' ').replace('Z', '');
+}
+
+function formatDuration(millis) {
+    if (millis < 1000) {
+        return `${millis}ms`;
+    }
+
+    const seconds = Math.floor(millis / 1000);
+    if (seconds < 60) {
+        return `${seconds}s`;
+    }
+
+    const minutes = Math.floor(seconds / 60);
+    if (minutes < 60) {
+        return `${minutes}min ${seconds % 60}s`;
+    }
+
+    const hours = Math.floor(minutes / 60);
+    return `${hours}hr ${minutes % 60}min`;
+}
+
+function escapeHTML(value) {
+    const div = document.createElement('div');
+    div.textContent = value;
+    return div.innerHTML;
+}
@@ -104,6 +104,9 @@ async function collectAndUpdateTable() {
             case 'Requested Data Deleted':
                 selectedClass = yellow
                 break
+            case 'Quarantined':
+                selectedClass = red
+                break
         }
 
 
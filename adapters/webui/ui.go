@@ -6,6 +6,7 @@ import (
 	"github.com/luno/workflow"
 
 	"github.com/luno/workflow/adapters/webui/internal/api"
+	"github.com/luno/workflow/adapters/webui/internal/dashboard"
 	"github.com/luno/workflow/adapters/webui/internal/frontend"
 )
 
@@ -17,17 +18,30 @@ type (
 	Stringer            = api.Stringer
 	ListWorkflowRecords = api.ListWorkflowRecords
 	LookupFn            = api.LookupFn
+	Redactor            = api.Redactor
 	Paths               = frontend.Paths
+	DashboardWorkflow   = dashboard.Workflow
 )
 
 func ListHandlerFunc(store workflow.RecordStore, stringer Stringer) http.HandlerFunc {
 	return api.List(store.List, stringer)
 }
 
-func ObjectDataHandlerFunc(store workflow.RecordStore) http.HandlerFunc {
-	return api.ObjectData(store.Lookup)
+// ObjectDataHandlerFunc serves the Object of a record. The Object of records belonging to any of the provided
+// redactors, such as a *workflow.Workflow built using workflow.WithRedaction, is redacted before it is served.
+func ObjectDataHandlerFunc(store workflow.RecordStore, redactors ...Redactor) http.HandlerFunc {
+	return api.ObjectData(store.Lookup, redactors...)
 }
 
 func UpdateHandlerFunc(store workflow.RecordStore) http.HandlerFunc {
 	return api.Update(store)
 }
+
+// DashboardHandler serves a dashboard of the workflows, such as the *workflow.Workflow of each workflow run by the
+// service, showing each workflow's graph, the states of its consumers and the lag of each status on this instance,
+// its paused and quarantined runs, and the timeline of each run. The dashboard's assets are embedded and it only
+// needs to be mounted with a trailing slash, such as
+// http.Handle("/dashboard/", http.StripPrefix("/dashboard", webui.DashboardHandler(orders, payments))).
+func DashboardHandler(workflows ...DashboardWorkflow) http.Handler {
+	return dashboard.Handler(workflows...)
+}
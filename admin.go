@@ -0,0 +1,203 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/luno/workflow/internal/metrics"
+)
+
+// PauseRun pauses the run so that it is no longer processed until ResumeRun is called. The OnPause hook is called
+// once the run has been paused. ErrInvalidTransition is returned when the run is not in a state to be paused, such as
+// when it has finished. The actor set on the context using WithActor is recorded in the run's history.
+func (w *Workflow[Type, Status]) PauseRun(ctx context.Context, runID string) error {
+	return w.updateRunState(ctx, runID, RunStatePaused, "")
+}
+
+// ResumeRun resumes the paused run so that it is processed again. ErrInvalidTransition is returned when the run is not
+// paused. The actor set on the context using WithActor is recorded in the run's history.
+func (w *Workflow[Type, Status]) ResumeRun(ctx context.Context, runID string) error {
+	return w.updateRunState(ctx, runID, RunStateRunning, "")
+}
+
+// CancelRun permanently cancels the run, such as one that is no longer needed, and records the reason, along with the
+// actor set on the context using WithActor, in the run's history. The OnCancel hook is called once the run has been
+// cancelled. ErrInvalidTransition is returned when the run is not in a state to be cancelled, such as when it has
+// finished.
+func (w *Workflow[Type, Status]) CancelRun(ctx context.Context, runID, reason string) error {
+	return w.updateRunState(ctx, runID, RunStateCancelled, reason)
+}
+
+func (w *Workflow[Type, Status]) updateRunState(ctx context.Context, runID string, to RunState, reason string) error {
+	record, err := w.recordStore.Lookup(ctx, runID)
+	if err != nil {
+		return err
+	}
+
+	if record.WorkflowName != w.Name() {
+		return fmt.Errorf("update run state: %w, meta: %v", ErrRecordNotFound, map[string]string{
+			"run_id": runID,
+		})
+	}
+
+	// Runs that have not yet been consumed are marked as running in order to be cancelled.
+	if record.RunState == RunStateInitiated && to == RunStateCancelled {
+		record.RunState = RunStateRunning
+	}
+
+	valid := runStateTransitions[record.RunState][to]
+	if to == RunStateRunning {
+		// Only paused runs are resumed as quarantined runs are reprocessed using ReprocessQuarantined.
+		valid = record.RunState == RunStatePaused
+	}
+
+	if !valid {
+		return fmt.Errorf("update run state: %w, meta: %v", ErrInvalidTransition, map[string]string{
+			"run_id": runID,
+			"from":   record.RunState.String(),
+			"to":     to.String(),
+		})
+	}
+
+	ctx = withTransitionProcess(ctx, "", w.clock)
+	ctx = withTransitionReason(ctx, reason)
+
+	record.UpdatedAt = w.clock.Now()
+	controller := NewRunStateController(w.recordStore.Store, record)
+	switch to {
+	case RunStatePaused:
+		err = controller.Pause(ctx)
+	case RunStateRunning:
+		err = controller.Resume(ctx)
+	case RunStateCancelled:
+		err = controller.Cancel(ctx)
+	}
+	if err != nil {
+		return err
+	}
+
+	w.logger.event(ctx, DebugEvent{
+		Type:    DebugEventRunStateUpdated,
+		Message: "updated run state",
+		Meta: map[string]string{
+			"workflow_name": w.Name(),
+			"run_id":        runID,
+			"run_state":     to.String(),
+			"reason":        reason,
+			"actor":         transitionFromContext(ctx).actor,
+		},
+	})
+
+	return nil
+}
+
+type forceTransitionOpts struct {
+	graphOverride bool
+}
+
+type ForceTransitionOption func(o *forceTransitionOpts)
+
+// WithGraphOverride allows ForceTransition to move the run to a status that is not one of the destinations of the
+// run's current status, such as when the run needs to be moved back to an earlier status.
+func WithGraphOverride() ForceTransitionOption {
+	return func(o *forceTransitionOpts) {
+		o.graphOverride = true
+	}
+}
+
+// ForceTransition moves the run from its current status to the status without calling the step of the current status
+// so that operators can unstick a run whose step is permanently failing. The status must be one of the destinations
+// of the run's current status unless WithGraphOverride is provided. Paused runs are resumed in the status. The
+// transition is recorded as forced in the run's history along with the reason and the actor set on the context using
+// WithActor. ErrInvalidTransition is returned when the run has stopped, other than being paused, or has finished or
+// when the status is not a valid destination.
+func (w *Workflow[Type, Status]) ForceTransition(
+	ctx context.Context,
+	runID string,
+	to Status,
+	reason string,
+	opts ...ForceTransitionOption,
+) error {
+	var o forceTransitionOpts
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	record, err := w.recordStore.Lookup(ctx, runID)
+	if err != nil {
+		return err
+	}
+
+	if record.WorkflowName != w.Name() {
+		return fmt.Errorf("force transition: %w, meta: %v", ErrRecordNotFound, map[string]string{
+			"run_id": runID,
+		})
+	}
+
+	from := Status(record.Status)
+	meta := map[string]string{
+		"run_id":    runID,
+		"run_state": record.RunState.String(),
+		"from":      from.String(),
+		"to":        to.String(),
+	}
+
+	switch record.RunState {
+	case RunStateInitiated, RunStateRunning, RunStatePaused:
+	default:
+		return fmt.Errorf("force transition: %w, meta: %v", ErrInvalidTransition, meta)
+	}
+
+	if !w.statusGraph.IsValid(int(to)) {
+		return fmt.Errorf("force transition: %w: status not defined in graph, meta: %v", ErrInvalidTransition, meta)
+	}
+
+	if !o.graphOverride && !slices.Contains(w.statusGraph.Transitions(int(from)), int(to)) {
+		return fmt.Errorf("force transition: %w: status is not a destination of the current status, meta: %v",
+			ErrInvalidTransition, meta)
+	}
+
+	ctx = withTransitionProcess(ctx, "", w.clock)
+	ctx = withTransitionReason(ctx, reason)
+
+	runState := RunStateRunning
+	if w.statusGraph.IsTerminal(int(to)) {
+		runState = RunStateCompleted
+	}
+
+	previousRunState := record.RunState
+	record.Status = int(to)
+	record.RunState = runState
+	record.UpdatedAt = w.clock.Now()
+	record.Meta.History = appendTransition(ctx, record.Meta.History, Transition{
+		FromStatus:   int(from),
+		ToStatus:     int(to),
+		FromRunState: previousRunState,
+		ToRunState:   runState,
+		At:           record.UpdatedAt,
+		Forced:       true,
+	})
+
+	metrics.RunStateChanges.WithLabelValues(w.Name(), previousRunState.String(), runState.String()).Inc()
+
+	err = w.recordStore.Store(ctx, record)
+	if err != nil {
+		return err
+	}
+
+	w.logger.event(ctx, DebugEvent{
+		Type:    DebugEventTransitionForced,
+		Message: "forced transition",
+		Meta: map[string]string{
+			"workflow_name": w.Name(),
+			"run_id":        runID,
+			"from":          from.String(),
+			"to":            to.String(),
+			"reason":        reason,
+			"actor":         transitionFromContext(ctx).actor,
+		},
+	})
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package workflow_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestRunAdmin(t *testing.T) {
+	paused := make(chan string, 1)
+	cancelled := make(chan string, 1)
+
+	b := workflow.NewBuilder[string, status]("run admin")
+	b.AddCallback(StatusStart, func(ctx context.Context, r *workflow.Run[string, status], reader io.Reader) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+	b.OnPause(func(ctx context.Context, record *workflow.TypedRecord[string, status]) error {
+		paused <- record.RunID
+		return nil
+	})
+	b.OnCancel(func(ctx context.Context, record *workflow.TypedRecord[string, status]) error {
+		cancelled <- record.RunID
+		return nil
+	})
+
+	recordStore := memrecordstore.New()
+	wf := b.Build(memstreamer.New(), recordStore, memrolescheduler.New())
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "foreignID", StatusStart)
+	require.Nil(t, err)
+
+	ctx = workflow.WithActor(ctx, "operator@example.com")
+
+	err = wf.ResumeRun(ctx, runID)
+	require.ErrorIs(t, err, workflow.ErrInvalidTransition)
+
+	err = wf.PauseRun(ctx, runID)
+	require.Nil(t, err)
+	require.Equal(t, runID, awaitHook(t, paused))
+
+	err = wf.ResumeRun(ctx, runID)
+	require.Nil(t, err)
+
+	record, err := recordStore.Lookup(ctx, runID)
+	require.Nil(t, err)
+	require.Equal(t, workflow.RunStateRunning, record.RunState)
+
+	err = wf.CancelRun(ctx, runID, "customer request")
+	require.Nil(t, err)
+	require.Equal(t, runID, awaitHook(t, cancelled))
+
+	history, err := wf.RunHistory(ctx, runID)
+	require.Nil(t, err)
+
+	last := history[len(history)-1]
+	require.Equal(t, workflow.RunStateCancelled, last.ToRunState)
+	require.Equal(t, "operator@example.com", last.Actor)
+	require.Equal(t, "customer request", last.Reason)
+
+	err = wf.CancelRun(ctx, runID, "customer request")
+	require.ErrorIs(t, err, workflow.ErrInvalidTransition)
+
+	err = wf.PauseRun(ctx, "unknown")
+	require.ErrorIs(t, err, workflow.ErrRecordNotFound)
+}
+
+func awaitHook(t *testing.T, ch chan string) string {
+	select {
+	case runID := <-ch:
+		return runID
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "hook not called")
+		return ""
+	}
+}
+
+func TestForceTransition(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("force transition")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return 0, errors.New("permanently failing")
+	}, StatusMiddle).WithOptions(workflow.PauseAfterErrCount(1))
+	b.AddStep(StatusMiddle, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	recordStore := memrecordstore.New()
+	wf := b.Build(memstreamer.New(), recordStore, memrolescheduler.New())
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "foreignID", StatusStart)
+	require.Nil(t, err)
+
+	require.Eventually(t, func() bool {
+		record, err := recordStore.Lookup(ctx, runID)
+		require.Nil(t, err)
+		return record.RunState == workflow.RunStatePaused
+	}, 5*time.Second, 10*time.Millisecond)
+
+	ctx = workflow.WithActor(ctx, "operator@example.com")
+
+	err = wf.ForceTransition(ctx, runID, StatusEnd, "skip")
+	require.ErrorIs(t, err, workflow.ErrInvalidTransition)
+
+	err = wf.ForceTransition(ctx, runID, StatusMiddle, "provider is down")
+	require.Nil(t, err)
+
+	_, err = wf.Await(ctx, "foreignID", runID, StatusEnd)
+	require.Nil(t, err)
+
+	history, err := wf.RunHistory(ctx, runID)
+	require.Nil(t, err)
+
+	var forced []workflow.Transition
+	for _, transition := range history {
+		if transition.Forced {
+			forced = append(forced, transition)
+		}
+	}
+	require.Len(t, forced, 1)
+	require.Equal(t, int(StatusStart), forced[0].FromStatus)
+	require.Equal(t, int(StatusMiddle), forced[0].ToStatus)
+	require.Equal(t, workflow.RunStatePaused, forced[0].FromRunState)
+	require.Equal(t, workflow.RunStateRunning, forced[0].ToRunState)
+	require.Equal(t, "operator@example.com", forced[0].Actor)
+	require.Equal(t, "provider is down", forced[0].Reason)
+
+	// Finished runs cannot be moved on.
+	err = wf.ForceTransition(ctx, runID, StatusMiddle, "retry", workflow.WithGraphOverride())
+	require.ErrorIs(t, err, workflow.ErrInvalidTransition)
+
+	runID, err = wf.Trigger(ctx, "foreignID-2", StatusStart)
+	require.Nil(t, err)
+
+	err = wf.ForceTransition(ctx, runID, StatusEnd, "skip", workflow.WithGraphOverride())
+	require.Nil(t, err)
+
+	record, err := recordStore.Lookup(ctx, runID)
+	require.Nil(t, err)
+	require.Equal(t, int(StatusEnd), record.Status)
+	require.Equal(t, workflow.RunStateCompleted, record.RunState)
+}
@@ -0,0 +1,438 @@
+package workflow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// HeaderAdminActor is the HTTP header of the requests to the handler of NewAdminHandler that identifies who made the
+// request, such as the operator that cancelled a run, so that it is recorded in the run's history.
+const HeaderAdminActor = "X-Workflow-Actor"
+
+// AdminRun is the JSON representation of a run returned by the handler of NewAdminHandler.
+type AdminRun struct {
+	WorkflowName string `json:"workflow_name"`
+	ForeignID    string `json:"foreign_id"`
+	RunID        string `json:"run_id"`
+	Status       int    `json:"status"`
+	// StatusDescription is the description of the status, such as its name, from the workflow's Graph.
+	StatusDescription string   `json:"status_description"`
+	RunState          RunState `json:"run_state"`
+	// Object is the run's redacted Object when it is valid JSON, such as when the workflow uses the default JSONCodec,
+	// and ObjectBytes holds it otherwise.
+	Object      json.RawMessage   `json:"object,omitempty"`
+	ObjectBytes []byte            `json:"object_bytes,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+}
+
+// AdminTriggerRequest is the body of the request to trigger a run. Object is the initial value of the run's Object
+// encoded using the workflow's Codec and is optional.
+type AdminTriggerRequest struct {
+	ForeignID string          `json:"foreign_id"`
+	Status    int             `json:"status"`
+	Object    json.RawMessage `json:"object,omitempty"`
+}
+
+// AdminCancelRequest is the body of the request to cancel a run.
+type AdminCancelRequest struct {
+	Reason string `json:"reason"`
+}
+
+// AdminReplayRequest is the body of the request to replay a run from the status, see ReplayRun.
+type AdminReplayRequest struct {
+	FromStatus int    `json:"from_status"`
+	Reason     string `json:"reason"`
+}
+
+// NewAdminHandler returns an http.Handler serving a JSON API to administer the runs of the workflows, such as the
+// *Workflow of each workflow run by the service, so that an admin API can be added to an existing service with
+// http.Handle("/admin/", http.StripPrefix("/admin", workflow.NewAdminHandler(orders, payments))). The handler
+// doesn't authenticate requests and so should be protected by the service's own middleware. The routes are:
+//
+//	GET  /workflows
+//	GET  /workflows/{workflow}/graph
+//	GET  /workflows/{workflow}/runs?status=&run_state=&foreign_id_prefix=&created_from=&created_to=&offset=&limit=&order=
+//	POST /workflows/{workflow}/runs
+//	GET  /workflows/{workflow}/runs/{run_id}
+//	GET  /workflows/{workflow}/runs/{run_id}/history
+//	POST /workflows/{workflow}/runs/{run_id}/pause
+//	POST /workflows/{workflow}/runs/{run_id}/resume
+//	POST /workflows/{workflow}/runs/{run_id}/cancel
+//	POST /workflows/{workflow}/runs/{run_id}/replay
+//	POST /workflows/{workflow}/callbacks/{foreign_id}/{status}
+//
+// Statuses are referred to by their integer values. The body of the callback request is the callback's payload. The
+// actor provided in the HeaderAdminActor header is recorded in the history of runs that are updated.
+func NewAdminHandler(workflows ...AdminWorkflow) http.Handler {
+	h := &adminHandler{
+		workflows: make(map[string]AdminWorkflow),
+	}
+
+	for _, w := range workflows {
+		if _, ok := h.workflows[w.Name()]; ok {
+			panic("duplicate workflow provided to NewAdminHandler: " + w.Name())
+		}
+
+		h.workflows[w.Name()] = w
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /workflows", h.listWorkflows)
+	mux.HandleFunc("GET /workflows/{workflow}/graph", h.withWorkflow(h.graph))
+	mux.HandleFunc("GET /workflows/{workflow}/runs", h.withWorkflow(h.listRuns))
+	mux.HandleFunc("POST /workflows/{workflow}/runs", h.withWorkflow(h.trigger))
+	mux.HandleFunc("GET /workflows/{workflow}/runs/{run_id}", h.withWorkflow(h.getRun))
+	mux.HandleFunc("GET /workflows/{workflow}/runs/{run_id}/history", h.withWorkflow(h.runHistory))
+	mux.HandleFunc("POST /workflows/{workflow}/runs/{run_id}/pause", h.withWorkflow(h.pause))
+	mux.HandleFunc("POST /workflows/{workflow}/runs/{run_id}/resume", h.withWorkflow(h.resume))
+	mux.HandleFunc("POST /workflows/{workflow}/runs/{run_id}/cancel", h.withWorkflow(h.cancel))
+	mux.HandleFunc("POST /workflows/{workflow}/runs/{run_id}/replay", h.withWorkflow(h.replay))
+	mux.HandleFunc("POST /workflows/{workflow}/callbacks/{foreign_id}/{status}", h.withWorkflow(h.callback))
+
+	return mux
+}
+
+type adminHandler struct {
+	workflows map[string]AdminWorkflow
+}
+
+type adminHandlerFunc func(w AdminWorkflow, rw http.ResponseWriter, r *http.Request) error
+
+// withWorkflow looks up the workflow of the request's path and writes the error returned by the handler.
+func (h *adminHandler) withWorkflow(fn adminHandlerFunc) http.HandlerFunc {
+	return func(rw http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("workflow")
+		w, ok := h.workflows[name]
+		if !ok {
+			writeAdminError(rw, http.StatusNotFound, fmt.Errorf("workflow not found: %q", name))
+			return
+		}
+
+		actor := r.Header.Get(HeaderAdminActor)
+		if actor != "" {
+			r = r.WithContext(WithActor(r.Context(), actor))
+		}
+
+		err := fn(w, rw, r)
+		if err != nil {
+			writeAdminError(rw, adminErrorCode(err), err)
+		}
+	}
+}
+
+func (h *adminHandler) listWorkflows(rw http.ResponseWriter, r *http.Request) {
+	names := make([]string, 0, len(h.workflows))
+	for name := range h.workflows {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	writeAdminJSON(rw, http.StatusOK, map[string][]string{"workflows": names})
+}
+
+func (h *adminHandler) graph(w AdminWorkflow, rw http.ResponseWriter, r *http.Request) error {
+	writeAdminJSON(rw, http.StatusOK, w.Graph())
+	return nil
+}
+
+func (h *adminHandler) listRuns(w AdminWorkflow, rw http.ResponseWriter, r *http.Request) error {
+	filter, err := parseAdminRunFilter(r)
+	if err != nil {
+		return err
+	}
+
+	records, err := w.AdminListRuns(r.Context(), filter)
+	if err != nil {
+		return err
+	}
+
+	descriptions := statusDescriptions(w.Graph())
+	runs := make([]AdminRun, 0, len(records))
+	for i := range records {
+		runs = append(runs, newAdminRun(&records[i], descriptions))
+	}
+
+	writeAdminJSON(rw, http.StatusOK, map[string][]AdminRun{"runs": runs})
+	return nil
+}
+
+func (h *adminHandler) getRun(w AdminWorkflow, rw http.ResponseWriter, r *http.Request) error {
+	record, err := w.AdminGetRun(r.Context(), r.PathValue("run_id"))
+	if err != nil {
+		return err
+	}
+
+	writeAdminJSON(rw, http.StatusOK, newAdminRun(record, statusDescriptions(w.Graph())))
+	return nil
+}
+
+func (h *adminHandler) runHistory(w AdminWorkflow, rw http.ResponseWriter, r *http.Request) error {
+	history, err := w.RunHistory(r.Context(), r.PathValue("run_id"))
+	if err != nil {
+		return err
+	}
+
+	if history == nil {
+		history = []Transition{}
+	}
+
+	writeAdminJSON(rw, http.StatusOK, map[string][]Transition{"history": history})
+	return nil
+}
+
+func (h *adminHandler) trigger(w AdminWorkflow, rw http.ResponseWriter, r *http.Request) error {
+	var req AdminTriggerRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return &adminRequestError{err: fmt.Errorf("decode trigger request: %w", err)}
+	}
+
+	if req.ForeignID == "" {
+		return &adminRequestError{err: errors.New("foreign_id is required")}
+	}
+
+	err = validateAdminStatus(w, req.Status)
+	if err != nil {
+		return err
+	}
+
+	runID, err := w.AdminTrigger(r.Context(), req.ForeignID, req.Status, req.Object)
+	if err != nil {
+		return err
+	}
+
+	writeAdminJSON(rw, http.StatusCreated, map[string]string{"run_id": runID})
+	return nil
+}
+
+func (h *adminHandler) pause(w AdminWorkflow, rw http.ResponseWriter, r *http.Request) error {
+	err := w.PauseRun(r.Context(), r.PathValue("run_id"))
+	if err != nil {
+		return err
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (h *adminHandler) resume(w AdminWorkflow, rw http.ResponseWriter, r *http.Request) error {
+	err := w.ResumeRun(r.Context(), r.PathValue("run_id"))
+	if err != nil {
+		return err
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (h *adminHandler) cancel(w AdminWorkflow, rw http.ResponseWriter, r *http.Request) error {
+	var req AdminCancelRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return &adminRequestError{err: fmt.Errorf("decode cancel request: %w", err)}
+	}
+
+	err = w.CancelRun(r.Context(), r.PathValue("run_id"), req.Reason)
+	if err != nil {
+		return err
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (h *adminHandler) replay(w AdminWorkflow, rw http.ResponseWriter, r *http.Request) error {
+	var req AdminReplayRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		return &adminRequestError{err: fmt.Errorf("decode replay request: %w", err)}
+	}
+
+	err = validateAdminStatus(w, req.FromStatus)
+	if err != nil {
+		return err
+	}
+
+	err = w.AdminReplayRun(r.Context(), r.PathValue("run_id"), req.FromStatus, req.Reason)
+	if err != nil {
+		return err
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (h *adminHandler) callback(w AdminWorkflow, rw http.ResponseWriter, r *http.Request) error {
+	status, err := strconv.Atoi(r.PathValue("status"))
+	if err != nil {
+		return &adminRequestError{err: fmt.Errorf("invalid status: %w", err)}
+	}
+
+	err = validateAdminStatus(w, status)
+	if err != nil {
+		return err
+	}
+
+	err = w.AdminCallback(r.Context(), r.PathValue("foreign_id"), status, r.Body)
+	if err != nil {
+		return err
+	}
+
+	rw.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func parseAdminRunFilter(r *http.Request) (AdminRunFilter, error) {
+	q := r.URL.Query()
+	filter := AdminRunFilter{
+		ForeignIDPrefix: q.Get("foreign_id_prefix"),
+	}
+
+	for _, v := range q["status"] {
+		status, err := strconv.Atoi(v)
+		if err != nil {
+			return AdminRunFilter{}, &adminRequestError{err: fmt.Errorf("invalid status: %w", err)}
+		}
+
+		filter.Statuses = append(filter.Statuses, status)
+	}
+
+	for _, v := range q["run_state"] {
+		runState, err := strconv.Atoi(v)
+		if err != nil {
+			return AdminRunFilter{}, &adminRequestError{err: fmt.Errorf("invalid run_state: %w", err)}
+		}
+
+		filter.RunStates = append(filter.RunStates, RunState(runState))
+	}
+
+	var err error
+	if v := q.Get("created_from"); v != "" {
+		filter.CreatedFrom, err = time.Parse(time.RFC3339, v)
+		if err != nil {
+			return AdminRunFilter{}, &adminRequestError{err: fmt.Errorf("invalid created_from: %w", err)}
+		}
+	}
+
+	if v := q.Get("created_to"); v != "" {
+		filter.CreatedTo, err = time.Parse(time.RFC3339, v)
+		if err != nil {
+			return AdminRunFilter{}, &adminRequestError{err: fmt.Errorf("invalid created_to: %w", err)}
+		}
+	}
+
+	if v := q.Get("offset"); v != "" {
+		filter.Offset, err = strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return AdminRunFilter{}, &adminRequestError{err: fmt.Errorf("invalid offset: %w", err)}
+		}
+	}
+
+	if v := q.Get("limit"); v != "" {
+		filter.Limit, err = strconv.Atoi(v)
+		if err != nil {
+			return AdminRunFilter{}, &adminRequestError{err: fmt.Errorf("invalid limit: %w", err)}
+		}
+	}
+
+	switch q.Get("order") {
+	case "", "asc":
+		filter.Order = OrderTypeAscending
+	case "desc":
+		filter.Order = OrderTypeDescending
+	default:
+		return AdminRunFilter{}, &adminRequestError{err: fmt.Errorf("invalid order: %q", q.Get("order"))}
+	}
+
+	return filter, nil
+}
+
+// validateAdminStatus returns an *adminRequestError when the status is not one of the statuses of the workflow.
+func validateAdminStatus(w AdminWorkflow, status int) error {
+	for _, s := range w.Graph().Statuses {
+		if s.Status == status {
+			return nil
+		}
+	}
+
+	return &adminRequestError{err: fmt.Errorf("status not configured for workflow: %d", status)}
+}
+
+func statusDescriptions(g Graph) map[int]string {
+	descriptions := make(map[int]string, len(g.Statuses))
+	for _, s := range g.Statuses {
+		descriptions[s.Status] = s.Description
+	}
+
+	return descriptions
+}
+
+func newAdminRun(r *Record, descriptions map[int]string) AdminRun {
+	run := AdminRun{
+		WorkflowName:      r.WorkflowName,
+		ForeignID:         r.ForeignID,
+		RunID:             r.RunID,
+		Status:            r.Status,
+		StatusDescription: descriptions[r.Status],
+		RunState:          r.RunState,
+		Metadata:          r.Meta.Metadata,
+		CreatedAt:         r.CreatedAt,
+		UpdatedAt:         r.UpdatedAt,
+	}
+
+	if json.Valid(r.Object) {
+		run.Object = r.Object
+	} else {
+		run.ObjectBytes = r.Object
+	}
+
+	return run
+}
+
+// adminRequestError is returned by the admin handlers when the request is invalid.
+type adminRequestError struct {
+	err error
+}
+
+func (e *adminRequestError) Error() string {
+	return e.err.Error()
+}
+
+func (e *adminRequestError) Unwrap() error {
+	return e.err
+}
+
+func adminErrorCode(err error) int {
+	var reqErr *adminRequestError
+	switch {
+	case errors.As(err, &reqErr):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, ErrInvalidTransition),
+		errors.Is(err, ErrStatusMismatch),
+		errors.Is(err, ErrWorkflowInProgress):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeAdminError(rw http.ResponseWriter, code int, err error) {
+	writeAdminJSON(rw, code, map[string]string{"error": err.Error()})
+}
+
+func writeAdminJSON(rw http.ResponseWriter, code int, v any) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(code)
+	_ = json.NewEncoder(rw).Encode(v)
+}
@@ -0,0 +1,197 @@
+package workflow_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestAdminHandler(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("orders")
+	b.AddCallback(StatusStart, func(ctx context.Context, r *workflow.Run[string, status], reader io.Reader) (status, error) {
+		payload, err := io.ReadAll(reader)
+		if err != nil {
+			return 0, err
+		}
+
+		*r.Object = string(payload)
+		return StatusMiddle, nil
+	}, StatusMiddle)
+	b.AddCallback(StatusMiddle, func(ctx context.Context, r *workflow.Run[string, status], reader io.Reader) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	wf := b.Build(memstreamer.New(), memrecordstore.New(), memrolescheduler.New())
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	srv := httptest.NewServer(http.StripPrefix("/admin", workflow.NewAdminHandler(wf)))
+	t.Cleanup(srv.Close)
+
+	var workflows struct {
+		Workflows []string `json:"workflows"`
+	}
+	adminRequest(t, srv, http.MethodGet, "/admin/workflows", "", http.StatusOK, &workflows)
+	require.Equal(t, []string{"orders"}, workflows.Workflows)
+
+	var triggered struct {
+		RunID string `json:"run_id"`
+	}
+	adminRequest(t, srv, http.MethodPost, "/admin/workflows/orders/runs",
+		`{"foreign_id": "order-1", "status": 9, "object": "created"}`, http.StatusCreated, &triggered)
+	runID := triggered.RunID
+	require.NotEmpty(t, runID)
+
+	var runs struct {
+		Runs []workflow.AdminRun `json:"runs"`
+	}
+	adminRequest(t, srv, http.MethodGet, "/admin/workflows/orders/runs?status=9&foreign_id_prefix=order-", "",
+		http.StatusOK, &runs)
+	require.Len(t, runs.Runs, 1)
+	require.Equal(t, runID, runs.Runs[0].RunID)
+	require.Equal(t, "Start", runs.Runs[0].StatusDescription)
+	require.Equal(t, `"created"`, string(runs.Runs[0].Object))
+
+	adminRequest(t, srv, http.MethodPost, "/admin/workflows/orders/callbacks/order-1/9", "paid",
+		http.StatusNoContent, nil)
+
+	require.Eventually(t, func() bool {
+		var run workflow.AdminRun
+		adminRequest(t, srv, http.MethodGet, "/admin/workflows/orders/runs/"+runID, "", http.StatusOK, &run)
+		return run.Status == int(StatusMiddle) && string(run.Object) == `"paid"`
+	}, 5*time.Second, 10*time.Millisecond)
+
+	adminRequest(t, srv, http.MethodPost, "/admin/workflows/orders/runs/"+runID+"/pause", "", http.StatusNoContent, nil)
+	adminRequest(t, srv, http.MethodPost, "/admin/workflows/orders/runs/"+runID+"/resume", "", http.StatusNoContent, nil)
+
+	// Resuming a run that is not paused is rejected.
+	adminRequest(t, srv, http.MethodPost, "/admin/workflows/orders/runs/"+runID+"/resume", "", http.StatusConflict, nil)
+
+	adminRequest(t, srv, http.MethodPost, "/admin/workflows/orders/runs/"+runID+"/cancel",
+		`{"reason": "customer request"}`, http.StatusNoContent, nil)
+
+	var history struct {
+		History []workflow.Transition `json:"history"`
+	}
+	adminRequest(t, srv, http.MethodGet, "/admin/workflows/orders/runs/"+runID+"/history", "", http.StatusOK, &history)
+
+	last := history.History[len(history.History)-1]
+	require.Equal(t, workflow.RunStateCancelled, last.ToRunState)
+	require.Equal(t, "bob", last.Actor)
+	require.Equal(t, "customer request", last.Reason)
+}
+
+func TestAdminHandlerErrors(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("orders")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	wf := b.Build(memstreamer.New(), memrecordstore.New(), memrolescheduler.New())
+
+	srv := httptest.NewServer(workflow.NewAdminHandler(wf))
+	t.Cleanup(srv.Close)
+
+	testCases := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		code   int
+	}{
+		{
+			name:   "Unknown workflow",
+			method: http.MethodGet,
+			path:   "/workflows/payments/runs",
+			code:   http.StatusNotFound,
+		},
+		{
+			name:   "Unknown run",
+			method: http.MethodGet,
+			path:   "/workflows/orders/runs/run",
+			code:   http.StatusNotFound,
+		},
+		{
+			name:   "Invalid order",
+			method: http.MethodGet,
+			path:   "/workflows/orders/runs?order=sideways",
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "Trigger with status not configured",
+			method: http.MethodPost,
+			path:   "/workflows/orders/runs",
+			body:   `{"foreign_id": "order-1", "status": 42}`,
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "Trigger without foreign ID",
+			method: http.MethodPost,
+			path:   "/workflows/orders/runs",
+			body:   `{"status": 9}`,
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "Replay from status not configured",
+			method: http.MethodPost,
+			path:   "/workflows/orders/runs/run/replay",
+			body:   `{"from_status": 42}`,
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:   "Replay unknown run",
+			method: http.MethodPost,
+			path:   "/workflows/orders/runs/run/replay",
+			body:   `{"from_status": 9}`,
+			code:   http.StatusNotFound,
+		},
+		{
+			name:   "Callback with invalid status",
+			method: http.MethodPost,
+			path:   "/workflows/orders/callbacks/order-1/start",
+			code:   http.StatusBadRequest,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var resp struct {
+				Error string `json:"error"`
+			}
+			adminRequest(t, srv, tc.method, tc.path, tc.body, tc.code, &resp)
+			require.NotEmpty(t, resp.Error)
+		})
+	}
+}
+
+func adminRequest(t *testing.T, srv *httptest.Server, method, path, body string, code int, resp any) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+	require.Nil(t, err)
+	req.Header.Set(workflow.HeaderAdminActor, "bob")
+
+	res, err := srv.Client().Do(req)
+	require.Nil(t, err)
+	defer res.Body.Close()
+
+	require.Equal(t, code, res.StatusCode)
+
+	if resp != nil {
+		err = json.NewDecoder(res.Body).Decode(resp)
+		require.Nil(t, err)
+	}
+}
@@ -0,0 +1,152 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// AdminWorkflow is implemented by every *Workflow, regardless of its Type and Status, so that tooling, such as the
+// admin service of workflowpb, can administer the runs of many workflows without binding to their types. Statuses are
+// referred to by their integer values and the Objects of runs are redacted using the redaction configured with
+// WithRedaction.
+type AdminWorkflow interface {
+	Name() string
+	Graph() Graph
+	AdminListRuns(ctx context.Context, filter AdminRunFilter) ([]Record, error)
+	AdminGetRun(ctx context.Context, runID string) (*Record, error)
+	RunHistory(ctx context.Context, runID string) ([]Transition, error)
+	PauseRun(ctx context.Context, runID string) error
+	ResumeRun(ctx context.Context, runID string) error
+	CancelRun(ctx context.Context, runID, reason string) error
+	AdminForceTransition(ctx context.Context, runID string, to int, reason string, opts ...ForceTransitionOption) error
+	AdminTrigger(ctx context.Context, foreignID string, startingStatus int, object []byte) (string, error)
+	AdminCallback(ctx context.Context, foreignID string, status int, payload io.Reader) error
+	AdminReplayRun(ctx context.Context, runID string, fromStatus int, reason string) error
+}
+
+// AdminRunFilter is the RunFilter of AdminListRuns with the statuses referred to by their integer values.
+type AdminRunFilter struct {
+	Statuses        []int
+	RunStates       []RunState
+	ForeignIDPrefix string
+	CreatedFrom     time.Time
+	CreatedTo       time.Time
+	Offset          int64
+	Limit           int
+	Order           OrderType
+}
+
+// AdminListRuns returns the records of the workflow's runs that match the filter in the same way as ListRuns.
+func (w *Workflow[Type, Status]) AdminListRuns(ctx context.Context, filter AdminRunFilter) ([]Record, error) {
+	statuses := make([]Status, 0, len(filter.Statuses))
+	for _, s := range filter.Statuses {
+		statuses = append(statuses, Status(s))
+	}
+
+	records, err := w.listRecords(ctx, RunFilter[Status]{
+		Statuses:        statuses,
+		RunStates:       filter.RunStates,
+		ForeignIDPrefix: filter.ForeignIDPrefix,
+		CreatedFrom:     filter.CreatedFrom,
+		CreatedTo:       filter.CreatedTo,
+		Offset:          filter.Offset,
+		Limit:           filter.Limit,
+		Order:           filter.Order,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range records {
+		records[i].Object, err = w.Redact(&records[i])
+		if err != nil {
+			return nil, fmt.Errorf("admin list runs: %w, meta: %v", err, map[string]string{
+				"run_id": records[i].RunID,
+			})
+		}
+	}
+
+	return records, nil
+}
+
+// AdminGetRun returns the record of the run. ErrRecordNotFound is returned when the run does not exist or does not
+// belong to the workflow. Runs that are not found in the RecordStore are looked up in the ArchiveStore when one is
+// configured using WithArchiveStore.
+func (w *Workflow[Type, Status]) AdminGetRun(ctx context.Context, runID string) (*Record, error) {
+	record, err := w.recordStore.Lookup(ctx, runID)
+	record, _, err = w.lookupArchived(ctx, runID, record, err)
+	if err != nil {
+		return nil, err
+	}
+
+	if record.WorkflowName != w.Name() {
+		return nil, fmt.Errorf("admin get run: %w, meta: %v", ErrRecordNotFound, map[string]string{
+			"run_id": runID,
+		})
+	}
+
+	record.Object, err = w.Redact(record)
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// AdminForceTransition calls ForceTransition with the status of the integer value.
+func (w *Workflow[Type, Status]) AdminForceTransition(
+	ctx context.Context,
+	runID string,
+	to int,
+	reason string,
+	opts ...ForceTransitionOption,
+) error {
+	return w.ForceTransition(ctx, runID, Status(to), reason, opts...)
+}
+
+// AdminTrigger calls Trigger with the status of the integer value. The initial value of the run's Object is decoded
+// from the object using the workflow's Codec, without its compression or encryption, unless the object is empty.
+func (w *Workflow[Type, Status]) AdminTrigger(
+	ctx context.Context,
+	foreignID string,
+	startingStatus int,
+	object []byte,
+) (string, error) {
+	var opts []TriggerOption[Type, Status]
+	if len(object) > 0 {
+		var t Type
+		// Operators provide the plain object and Trigger compresses and encrypts it when it is stored.
+		err := unwrapCodec(w.codec).Unmarshal(object, &t)
+		if err != nil {
+			return "", fmt.Errorf("admin trigger: decode object: %w, meta: %v", err, map[string]string{
+				"foreign_id": foreignID,
+			})
+		}
+
+		opts = append(opts, WithInitialValue[Type, Status](&t))
+	}
+
+	return w.Trigger(ctx, foreignID, Status(startingStatus), opts...)
+}
+
+// AdminCallback calls Callback with the status of the integer value.
+func (w *Workflow[Type, Status]) AdminCallback(
+	ctx context.Context,
+	foreignID string,
+	status int,
+	payload io.Reader,
+) error {
+	return w.Callback(ctx, foreignID, Status(status), payload)
+}
+
+// AdminReplayRun calls ReplayRun with the status of the integer value.
+func (w *Workflow[Type, Status]) AdminReplayRun(
+	ctx context.Context,
+	runID string,
+	fromStatus int,
+	reason string,
+) error {
+	return w.ReplayRun(ctx, runID, Status(fromStatus), reason)
+}
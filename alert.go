@@ -0,0 +1,275 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Owner identifies who is responsible for a workflow so that its alerts can be routed to them.
+type Owner struct {
+	Team         string
+	Email        string
+	SlackChannel string
+}
+
+// WithOwner registers the owner of the workflow which is included in every Alert raised by the workflow.
+func WithOwner(o Owner) BuildOption {
+	return func(bo *buildOptions) {
+		bo.owner = o
+	}
+}
+
+// Owner returns the owner of the workflow that was registered using WithOwner.
+func (w *Workflow[Type, Status]) Owner() Owner {
+	return w.owner
+}
+
+type AlertType int
+
+const (
+	AlertTypeUnknown AlertType = 0
+	// AlertTypeStuckRun is raised when a run has not been updated within the duration provided to WithStuckRunAlert.
+	AlertTypeStuckRun AlertType = 1
+	// AlertTypeCircuitOpen is raised when the circuit breaker of a step, configured using CircuitBreaker, opens and
+	// the step stops consuming.
+	AlertTypeCircuitOpen AlertType = 2
+	// AlertTypeHeartbeatTimeout is raised when a step, configured using WithHeartbeatTimeout, has not called
+	// Run.Heartbeat within the heartbeat timeout and is abandoned.
+	AlertTypeHeartbeatTimeout AlertType = 3
+	// AlertTypeLagAlert is raised when a process of the workflow starts consuming events that are older than its
+	// LagAlert.
+	AlertTypeLagAlert AlertType = 4
+)
+
+func (a AlertType) String() string {
+	switch a {
+	case AlertTypeStuckRun:
+		return "StuckRun"
+	case AlertTypeCircuitOpen:
+		return "CircuitOpen"
+	case AlertTypeHeartbeatTimeout:
+		return "HeartbeatTimeout"
+	case AlertTypeLagAlert:
+		return "LagAlert"
+	default:
+		return "Unknown"
+	}
+}
+
+// Alert is an operational signal about a workflow, or one of its runs, that is routed to the workflow's Owner.
+type Alert struct {
+	Type         AlertType
+	WorkflowName string
+	Owner        Owner
+	// ForeignID and RunID are set when the alert relates to a specific run.
+	ForeignID string
+	RunID     string
+	// Status is the status that the run is in, when the alert relates to a specific run.
+	Status string
+	// Process is set when the alert relates to a specific process of the workflow, such as a lagging consumer.
+	Process string
+	Message string
+}
+
+// AlertHook is called for every Alert raised by the workflow, such as to notify the workflow's Owner by email or
+// Slack. The hook is called from the workflow's processes and should not block for long.
+type AlertHook func(ctx context.Context, alert Alert)
+
+// WithAlertHook sets the hook that is called for every Alert raised by the workflow.
+func WithAlertHook(hook AlertHook) BuildOption {
+	return func(bo *buildOptions) {
+		bo.alertHook = hook
+	}
+}
+
+// LagAlertInfo describes a process of the workflow that has started, or stopped, lagging beyond its LagAlert.
+type LagAlertInfo struct {
+	WorkflowName string
+	// Process is the name of the process, such as the consumer of a step.
+	Process string
+	// Status is the status of the step when the process is the consumer of a step and is empty otherwise.
+	Status string
+	// Lag is the age of the event that the process consumed.
+	Lag time.Duration
+	// Threshold is the LagAlert of the process.
+	Threshold time.Duration
+	// Alerting is true when the process has started lagging beyond its threshold and false once it has caught up.
+	Alerting bool
+}
+
+// LagAlertHandler is called when a process of the workflow starts lagging beyond its LagAlert and again once it has
+// caught up, such as to page the workflow's Owner, scale out the consumers, or shed load. The handler is called by the
+// lagging process and should not block for long.
+type LagAlertHandler func(ctx context.Context, info LagAlertInfo)
+
+// WithLagAlertHandler sets the handler that is called when a process of the workflow starts lagging beyond its
+// LagAlert and once it has caught up. The processes only report their lag when they consume an event and so a process
+// is considered to have caught up once it consumes an event within its LagAlert.
+func WithLagAlertHandler(h LagAlertHandler) BuildOption {
+	return func(bo *buildOptions) {
+		bo.lagAlertHandler = h
+	}
+}
+
+// WithStuckRunAlert raises an AlertTypeStuckRun alert for every run that is initiated, running, or paused, and has not
+// been updated within the duration. Each run is only alerted on once until it is updated again. The runs are checked every
+// minute, or every duration when it is less than a minute.
+func WithStuckRunAlert(after time.Duration) BuildOption {
+	return func(bo *buildOptions) {
+		bo.stuckRunAlert = after
+	}
+}
+
+// alert raises the Alert with the workflow's name and owner.
+func (w *Workflow[Type, Status]) alert(ctx context.Context, a Alert) {
+	if a.Type == AlertTypeLagAlert && len(w.webhooks) > 0 {
+		err := w.sendWebhooks(ctx, WebhookEventLagAlert, WebhookPayload{
+			Process: a.Process,
+			Message: a.Message,
+		})
+		if err != nil {
+			w.logger.Error(ctx, err)
+		}
+	}
+
+	if w.alertHook == nil {
+		return
+	}
+
+	a.WorkflowName = w.Name()
+	a.Owner = w.owner
+	w.alertHook(ctx, a)
+}
+
+// lagAlert raises an AlertTypeLagAlert alert, and calls the LagAlertHandler and the process' own handlers, when the
+// process starts lagging beyond its lag alert and calls the handlers again once the process has caught up.
+func (w *Workflow[Type, Status]) lagAlert(ctx context.Context, info LagAlertInfo, handlers ...LagAlertHandler) {
+	w.lagAlertingMu.Lock()
+	if w.lagAlerting == nil {
+		w.lagAlerting = make(map[string]bool)
+	}
+
+	changed := w.lagAlerting[info.Process] != info.Alerting
+	w.lagAlerting[info.Process] = info.Alerting
+	w.lagAlertingMu.Unlock()
+
+	if !changed {
+		return
+	}
+
+	for _, h := range append([]LagAlertHandler{w.lagAlertHandler}, handlers...) {
+		if h != nil {
+			h(ctx, info)
+		}
+	}
+
+	if !info.Alerting {
+		return
+	}
+
+	w.alert(ctx, Alert{
+		Type:    AlertTypeLagAlert,
+		Status:  info.Status,
+		Process: info.Process,
+		Message: fmt.Sprintf(
+			"process is consuming events that are %s old which exceeds its lag alert of %s",
+			info.Lag.Round(time.Second),
+			info.Threshold,
+		),
+	})
+}
+
+func stuckRunAlertConsumer[Type any, Status StatusType](w *Workflow[Type, Status]) {
+	role := makeRole(
+		w.Name(),
+		"stuck",
+		"run",
+		"alert",
+	)
+
+	processName := makeRole("stuck", "run", "alert")
+	w.run(role, processName, w.hookShutdownOrder(), func(ctx context.Context) error {
+		interval := min(w.stuckRunAlert, time.Minute)
+
+		// alerted holds the UpdatedAt of the runs that have been alerted on so that runs are only alerted on again
+		// once they have been updated.
+		alerted := make(map[string]time.Time)
+		for {
+			err := checkStuckRuns(ctx, w, alerted)
+			if err != nil {
+				return err
+			}
+
+			err = waitUntil(ctx, w.clock, w.clock.Now().Add(interval))
+			if err != nil {
+				return err
+			}
+		}
+	}, w.defaultOpts.errBackOff)
+}
+
+func checkStuckRuns[Type any, Status StatusType](
+	ctx context.Context,
+	w *Workflow[Type, Status],
+	alerted map[string]time.Time,
+) error {
+	threshold := w.clock.Now().Add(-w.stuckRunAlert)
+	seen := make(map[string]bool)
+
+	var offset int64
+	for {
+		records, err := w.recordStore.List(
+			ctx,
+			w.Name(),
+			offset,
+			listRunsPageSize,
+			OrderTypeAscending,
+			FilterByRunState(RunStateInitiated, RunStateRunning, RunStatePaused),
+		)
+		if err != nil {
+			return err
+		}
+
+		for _, r := range records {
+			seen[r.RunID] = true
+			if !r.UpdatedAt.Before(threshold) {
+				continue
+			}
+
+			if updatedAt, ok := alerted[r.RunID]; ok && updatedAt.Equal(r.UpdatedAt) {
+				continue
+			}
+
+			alerted[r.RunID] = r.UpdatedAt
+			w.alert(ctx, Alert{
+				Type:      AlertTypeStuckRun,
+				ForeignID: r.ForeignID,
+				RunID:     r.RunID,
+				Status:    Status(r.Status).String(),
+				Message: fmt.Sprintf(
+					"run has been %s in %s without being updated for %s",
+					strings.ToLower(r.RunState.String()),
+					Status(r.Status),
+					w.clock.Since(r.UpdatedAt).Round(time.Second),
+				),
+			})
+		}
+
+		if len(records) < listRunsPageSize {
+			break
+		}
+
+		offset += int64(len(records))
+	}
+
+	// Forget the runs that are no longer in progress.
+	for runID := range alerted {
+		if !seen[runID] {
+			delete(alerted, runID)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package workflow_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	clock_testing "k8s.io/utils/clock/testing"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestWithStuckRunAlert(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("stuck")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		// Skipping leaves the run in its current status without being updated.
+		return 0, nil
+	}, StatusEnd)
+
+	clock := clock_testing.NewFakeClock(time.Date(2024, time.April, 19, 23, 0, 0, 0, time.UTC))
+	owner := workflow.Owner{
+		Team:         "payments",
+		SlackChannel: "#payments-alerts",
+	}
+
+	var (
+		mu     sync.Mutex
+		alerts []workflow.Alert
+	)
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+		workflow.WithClock(clock),
+		workflow.WithOwner(owner),
+		workflow.WithStuckRunAlert(time.Hour),
+		workflow.WithAlertHook(func(ctx context.Context, alert workflow.Alert) {
+			mu.Lock()
+			defer mu.Unlock()
+			alerts = append(alerts, alert)
+		}),
+	)
+	require.Equal(t, owner, wf.Owner())
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "foreignID", StatusStart)
+	require.Nil(t, err)
+
+	alerted := func() []workflow.Alert {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]workflow.Alert(nil), alerts...)
+	}
+
+	require.Eventually(t, func() bool {
+		clock.Step(time.Minute)
+		return len(alerted()) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	alert := alerted()[0]
+	require.Equal(t, workflow.AlertTypeStuckRun, alert.Type)
+	require.Equal(t, "stuck", alert.WorkflowName)
+	require.Equal(t, owner, alert.Owner)
+	require.Equal(t, "foreignID", alert.ForeignID)
+	require.Equal(t, runID, alert.RunID)
+	require.Equal(t, StatusStart.String(), alert.Status)
+
+	// The run is only alerted on once until it is updated.
+	for range 5 {
+		clock.Step(time.Minute)
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	require.Len(t, alerted(), 1)
+}
+
+func TestLagAlert(t *testing.T) {
+	clock := clock_testing.NewFakeClock(time.Date(2024, time.April, 19, 23, 0, 0, 0, time.UTC))
+	triggered := make(chan struct{})
+
+	b := workflow.NewBuilder[string, status]("lagging")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		// The first run holds up the step for an hour whilst the second run's event waits to be consumed.
+		if r.ForeignID == "first" {
+			<-triggered
+			clock.Step(time.Hour)
+		}
+
+		return StatusEnd, nil
+	}, StatusEnd).WithOptions(
+		workflow.LagAlert(time.Minute),
+	)
+
+	alerts := make(chan workflow.Alert, 10)
+	wf := b.Build(
+		memstreamer.New(memstreamer.WithClock(clock)),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+		workflow.WithClock(clock),
+		workflow.WithAlertHook(func(ctx context.Context, alert workflow.Alert) {
+			alerts <- alert
+		}),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	_, err := wf.Trigger(ctx, "first", StatusStart)
+	require.Nil(t, err)
+
+	runID, err := wf.Trigger(ctx, "second", StatusStart)
+	require.Nil(t, err)
+	close(triggered)
+
+	_, err = wf.Await(ctx, "second", runID, StatusEnd)
+	require.Nil(t, err)
+
+	alert := <-alerts
+	require.Equal(t, workflow.AlertTypeLagAlert, alert.Type)
+	require.Equal(t, "lagging", alert.WorkflowName)
+	require.Equal(t, StatusStart.String(), alert.Status)
+	require.Equal(t, "start-consumer-1-of-1", alert.Process)
+	require.Equal(t, "process is consuming events that are 1h0m0s old which exceeds its lag alert of 1m0s", alert.Message)
+}
+
+func TestWithLagAlertHandler(t *testing.T) {
+	clock := clock_testing.NewFakeClock(time.Date(2024, time.April, 19, 23, 0, 0, 0, time.UTC))
+	triggered := make(chan struct{})
+
+	b := workflow.NewBuilder[string, status]("lagging")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		// The first run holds up the step for an hour whilst the second run's event waits to be consumed.
+		if r.ForeignID == "first" {
+			<-triggered
+			clock.Step(time.Hour)
+		}
+
+		return StatusEnd, nil
+	}, StatusEnd).WithOptions(
+		workflow.LagAlert(time.Minute),
+	)
+
+	infos := make(chan workflow.LagAlertInfo, 10)
+	wf := b.Build(
+		memstreamer.New(memstreamer.WithClock(clock)),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+		workflow.WithClock(clock),
+		workflow.WithLagAlertHandler(func(ctx context.Context, info workflow.LagAlertInfo) {
+			if info.Status == StatusStart.String() {
+				infos <- info
+			}
+		}),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	_, err := wf.Trigger(ctx, "first", StatusStart)
+	require.Nil(t, err)
+
+	_, err = wf.Trigger(ctx, "second", StatusStart)
+	require.Nil(t, err)
+	close(triggered)
+
+	require.Equal(t, workflow.LagAlertInfo{
+		WorkflowName: "lagging",
+		Process:      "start-consumer-1-of-1",
+		Status:       StatusStart.String(),
+		Lag:          time.Hour,
+		Threshold:    time.Minute,
+		Alerting:     true,
+	}, <-infos)
+
+	// The step catches up once it consumes an event within its lag alert.
+	_, err = wf.Trigger(ctx, "third", StatusStart)
+	require.Nil(t, err)
+
+	info := <-infos
+	require.False(t, info.Alerting)
+	require.Equal(t, time.Duration(0), info.Lag)
+}
@@ -0,0 +1,124 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ArchiveStore provides read access to runs that have been moved out of the RecordStore into cold storage. Reading
+// from the ArchiveStore is expected to be slower than reading from the RecordStore and so it is only read from when a
+// run is not found in the RecordStore or when all the runs of a foreign ID are requested.
+type ArchiveStore interface {
+	// Lookup returns the archived run and ErrRecordNotFound when the run has not been archived.
+	Lookup(ctx context.Context, runID string) (*Record, error)
+	// List returns all the archived runs of the foreign ID.
+	List(ctx context.Context, workflowName, foreignID string) ([]Record, error)
+}
+
+// WithArchiveStore allows the runs that have been archived to be read using GetRun and ListForeignIDRuns alongside
+// the runs in the RecordStore. Archived runs are returned with Archived set to true.
+func WithArchiveStore(s ArchiveStore) BuildOption {
+	return func(bo *buildOptions) {
+		bo.archiveStore = s
+	}
+}
+
+// lookupArchived returns the archived run when the run could not be found in the RecordStore and otherwise returns
+// the result of the RecordStore's lookup.
+func (w *Workflow[Type, Status]) lookupArchived(
+	ctx context.Context,
+	runID string,
+	record *Record,
+	err error,
+) (*Record, bool, error) {
+	if w.archiveStore == nil || !errors.Is(err, ErrRecordNotFound) {
+		return record, false, err
+	}
+
+	record, err = w.archiveStore.Lookup(ctx, runID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return record, true, nil
+}
+
+// ListForeignIDRuns returns all the runs of the foreign ID, including archived runs when an ArchiveStore is
+// configured, ordered by when they were created.
+func (w *Workflow[Type, Status]) ListForeignIDRuns(
+	ctx context.Context,
+	foreignID string,
+) ([]TypedRecord[Type, Status], error) {
+	var (
+		runs   []TypedRecord[Type, Status]
+		seen   = make(map[string]bool)
+		offset int64
+	)
+	for {
+		records, err := w.recordStore.List(
+			ctx,
+			w.Name(),
+			offset,
+			listRunsPageSize,
+			OrderTypeAscending,
+			FilterByForeignID(foreignID),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range records {
+			typed, err := newTypedRecord[Type, Status](w.codec, &records[i])
+			if err != nil {
+				return nil, fmt.Errorf("list foreign id runs: %w, meta: %v", err, map[string]string{
+					"run_id": records[i].RunID,
+				})
+			}
+
+			seen[records[i].RunID] = true
+			runs = append(runs, *typed)
+		}
+
+		if len(records) < listRunsPageSize {
+			break
+		}
+
+		offset += int64(len(records))
+	}
+
+	if w.archiveStore == nil {
+		return runs, nil
+	}
+
+	archived, err := w.archiveStore.List(ctx, w.Name(), foreignID)
+	if err != nil {
+		return nil, fmt.Errorf("list archived runs: %w, meta: %v", err, map[string]string{
+			"foreign_id": foreignID,
+		})
+	}
+
+	for i := range archived {
+		// A run that is still in the RecordStore whilst being archived is returned from the RecordStore.
+		if seen[archived[i].RunID] {
+			continue
+		}
+
+		typed, err := newTypedRecord[Type, Status](w.codec, &archived[i])
+		if err != nil {
+			return nil, fmt.Errorf("list foreign id runs: %w, meta: %v", err, map[string]string{
+				"run_id": archived[i].RunID,
+			})
+		}
+
+		typed.Archived = true
+		runs = append(runs, *typed)
+	}
+
+	slices.SortStableFunc(runs, func(a, b TypedRecord[Type, Status]) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return runs, nil
+}
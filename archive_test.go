@@ -0,0 +1,105 @@
+package workflow_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+type archiveStore struct {
+	records []workflow.Record
+}
+
+func (s *archiveStore) Lookup(ctx context.Context, runID string) (*workflow.Record, error) {
+	for _, r := range s.records {
+		if r.RunID == runID {
+			return &r, nil
+		}
+	}
+
+	return nil, workflow.ErrRecordNotFound
+}
+
+func (s *archiveStore) List(ctx context.Context, workflowName, foreignID string) ([]workflow.Record, error) {
+	var records []workflow.Record
+	for _, r := range s.records {
+		if r.WorkflowName == workflowName && r.ForeignID == foreignID {
+			records = append(records, r)
+		}
+	}
+
+	return records, nil
+}
+
+func TestArchiveStore(t *testing.T) {
+	b := workflow.NewBuilder[MyType, status]("archive")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	object, err := workflow.Marshal(&MyType{UserID: 1})
+	require.Nil(t, err)
+
+	archived := workflow.Record{
+		WorkflowName: "archive",
+		ForeignID:    "user-1",
+		RunID:        "archived-run",
+		RunState:     workflow.RunStateCompleted,
+		Status:       int(StatusEnd),
+		Object:       object,
+		CreatedAt:    time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+		workflow.WithArchiveStore(&archiveStore{records: []workflow.Record{archived}}),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "user-1", StatusStart, workflow.WithInitialValue[MyType, status](&MyType{
+		UserID: 2,
+	}))
+	require.Nil(t, err)
+
+	_, err = wf.Await(ctx, "user-1", runID, StatusEnd, workflow.WithAwaitPollingFrequency(10*time.Millisecond))
+	require.Nil(t, err)
+
+	t.Run("GetRun includes archived runs", func(t *testing.T) {
+		run, err := wf.GetRun(ctx, "user-1", "archived-run")
+		require.Nil(t, err)
+		require.True(t, run.Archived)
+		require.Equal(t, int64(1), run.Object.UserID)
+
+		run, err = wf.GetRun(ctx, "user-1", runID)
+		require.Nil(t, err)
+		require.False(t, run.Archived)
+	})
+
+	t.Run("GetRun checks the foreign ID of archived runs", func(t *testing.T) {
+		_, err := wf.GetRun(ctx, "user-2", "archived-run")
+		require.ErrorIs(t, err, workflow.ErrRecordNotFound)
+	})
+
+	t.Run("ListForeignIDRuns includes archived runs", func(t *testing.T) {
+		runs, err := wf.ListForeignIDRuns(ctx, "user-1")
+		require.Nil(t, err)
+		require.Len(t, runs, 2)
+
+		require.Equal(t, "archived-run", runs[0].RunID)
+		require.True(t, runs[0].Archived)
+		require.Equal(t, runID, runs[1].RunID)
+		require.False(t, runs[1].Archived)
+	})
+}
@@ -7,11 +7,13 @@ import (
 	"k8s.io/utils/clock"
 
 	"github.com/luno/workflow/internal/errorcounter"
+	"github.com/luno/workflow/internal/metrics"
 )
 
 // maybePause will either return a nil error if it has failed to pause the record and should be retried. A non-nil
 // error is returned when no faults have taken place and the corresponding bool returns true when the Run is paused
-// and returns false when the Run was not paused.
+// and returns false when the Run was not paused. When deadLetter is not nil the Run is published to the dead-letter
+// queue before it is paused.
 func maybePause[Type any, Status StatusType](
 	ctx context.Context,
 	pauseAfterErrCount int,
@@ -19,7 +21,8 @@ func maybePause[Type any, Status StatusType](
 	originalErr error,
 	processName string,
 	run *Run[Type, Status],
-	logger Logger,
+	logger *logger,
+	deadLetter deadLetterFunc,
 ) (paused bool, err error) {
 	// Only keep track of errors only if we need to
 	if pauseAfterErrCount == 0 {
@@ -31,15 +34,30 @@ func maybePause[Type any, Status StatusType](
 		return false, nil
 	}
 
-	_, err = run.Pause(ctx)
+	if deadLetter != nil {
+		err = deadLetter(ctx, processName, &run.Record, originalErr)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	_, err = run.Pause(withTransitionError(ctx, originalErr))
 	if err != nil {
 		return false, err
 	}
 
-	logger.Debug(ctx, "paused record after exceeding allowed error count", map[string]string{
-		"workflow_name": run.WorkflowName,
-		"foreign_id":    run.ForeignID,
-		"run_id":        run.RunID,
+	metrics.RunsPaused.WithLabelValues(run.WorkflowName, processName).Inc()
+
+	logger.event(ctx, DebugEvent{
+		Type:    DebugEventRunPaused,
+		Message: "paused record after exceeding allowed error count",
+		Meta: map[string]string{
+			"workflow_name": run.WorkflowName,
+			"foreign_id":    run.ForeignID,
+			"run_id":        run.RunID,
+			"process_name":  processName,
+			"error":         originalErr.Error(),
+		},
 	})
 
 	// Run paused - now clear the error counter.
@@ -49,7 +67,7 @@ func maybePause[Type any, Status StatusType](
 
 func pausedRecordsRetryConsumer[Type any, Status StatusType](w *Workflow[Type, Status]) {
 	role := makeRole(
-		w.Name(),
+		w.roleName(),
 		"paused",
 		"records",
 		"retry",
@@ -62,7 +80,7 @@ func pausedRecordsRetryConsumer[Type any, Status StatusType](w *Workflow[Type, S
 		"retry",
 		"consumer",
 	)
-	w.run(role, processName, func(ctx context.Context) error {
+	w.run(role, processName, w.hookShutdownOrder(), func(ctx context.Context) error {
 		topic := RunStateChangeTopic(w.Name())
 		stream, err := w.eventStreamer.NewReceiver(
 			ctx,
@@ -85,15 +103,16 @@ func pausedRecordsRetryConsumer[Type any, Status StatusType](w *Workflow[Type, S
 			w.Name(),
 			processName,
 			stream,
-			autoRetryConsumer(
+			w.versionGuard(autoRetryConsumer(
 				w.recordStore.Lookup,
 				w.recordStore.Store,
 				w.clock,
 				w.pausedRecordsRetry.resumeAfter,
-			),
+			)),
 			w.clock,
 			w.pausedRecordsRetry.resumeAfter,
 			lagAlert,
+			w.versionFilter(),
 		)
 	}, w.defaultOpts.errBackOff)
 }
@@ -26,6 +26,7 @@ func Test_maybeAutoPause(t *testing.T) {
 		errCount           int
 		expectedErr        error
 		pauseFn            func(ctx context.Context) error
+		deadLetter         deadLetterFunc
 	}{
 		{
 			name:               "Default - not configured, no previous errors - does not pause workflow run",
@@ -61,6 +62,31 @@ func Test_maybeAutoPause(t *testing.T) {
 			},
 			expectedErr: pauseErr,
 		},
+		{
+			name:               "Publish to dead letter queue before pausing",
+			pauseAfterErrCount: 1,
+			pausesRecord:       true,
+			errCount:           1,
+			deadLetter: func(ctx context.Context, processName string, record *Record, err error) error {
+				require.Equal(t, "run-id", record.RunID)
+				require.Equal(t, testErr, err)
+				return nil
+			},
+		},
+		{
+			name:               "Do not pause when dead letter queue fails",
+			pauseAfterErrCount: 1,
+			pausesRecord:       false,
+			errCount:           1,
+			pauseFn: func(ctx context.Context) error {
+				t.Fatal("run must not be paused")
+				return nil
+			},
+			deadLetter: func(ctx context.Context, processName string, record *Record, err error) error {
+				return pauseErr
+			},
+			expectedErr: pauseErr,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -82,6 +108,7 @@ func Test_maybeAutoPause(t *testing.T) {
 				processName,
 				r,
 				&logger{},
+				tc.deadLetter,
 			)
 			require.ErrorIs(t, err, tc.expectedErr)
 			require.Equal(t, tc.pausesRecord, paused)
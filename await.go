@@ -23,7 +23,7 @@ func (w *Workflow[Type, Status]) Await(
 		pollFrequency = opt.pollFrequency
 	}
 
-	role := makeRole("await", w.Name(), strconv.FormatInt(int64(status), 10), foreignID)
+	role := makeRole("await", w.roleName(), strconv.FormatInt(int64(status), 10), foreignID)
 	return awaitWorkflowStatusByForeignID[Type, Status](ctx, w, status, foreignID, runID, role, pollFrequency)
 }
 
@@ -35,24 +35,52 @@ func awaitWorkflowStatusByForeignID[Type any, Status StatusType](
 	role string,
 	pollFrequency time.Duration,
 ) (*Run[Type, Status], error) {
-	topic := Topic(w.Name(), int(status))
+	if w.awaitHub != nil {
+		return awaitSharedStatus(ctx, w, status, foreignID, runID, pollFrequency)
+	}
+
 	// Terminal statuses result in the RunState changing to Completed and are stored in the RunStateChangeTopic
 	// as it is a key event in the Workflow Run's lifecycle.
 	if w.statusGraph.IsTerminal(int(status)) {
-		topic = RunStateChangeTopic(w.Name())
+		stream, err := w.eventStreamer.NewReceiver(
+			ctx,
+			RunStateChangeTopic(w.Name()),
+			role,
+			WithReceiverPollFrequency(pollFrequency),
+		)
+		if err != nil {
+			return nil, err
+		}
+		defer stream.Close()
+
+		// The RunStateChangeTopic includes the run state changes of the run in its other statuses, such as when it
+		// is paused.
+		return awaitRun(ctx, w, stream, foreignID, runID, filterByStatus(int(status)))
 	}
 
-	stream, err := w.eventStreamer.NewReceiver(
-		ctx,
-		topic,
-		role,
-		WithReceiverPollFrequency(pollFrequency),
-	)
+	stream, err := w.newStatusReceiver(ctx, status, role, WithReceiverPollFrequency(pollFrequency))
 	if err != nil {
 		return nil, err
 	}
 	defer stream.Close()
 
+	return awaitRun(ctx, w, stream, foreignID, runID)
+}
+
+// awaitRun returns the run of the first event received from the stream that belongs to the run and is not filtered
+// out.
+func awaitRun[Type any, Status StatusType](
+	ctx context.Context,
+	w *Workflow[Type, Status],
+	stream EventReceiver,
+	foreignID, runID string,
+	filters ...EventFilter,
+) (*Run[Type, Status], error) {
+	filters = append([]EventFilter{
+		filterByForeignID(foreignID),
+		filterByRunID(runID),
+	}, filters...)
+
 	for {
 		if ctx.Err() != nil {
 			return nil, ctx.Err()
@@ -63,10 +91,7 @@ func awaitWorkflowStatusByForeignID[Type any, Status StatusType](
 			return nil, err
 		}
 
-		shouldFilter := FilterUsing(e,
-			filterByForeignID(foreignID),
-			filterByRunID(runID),
-		)
+		shouldFilter := FilterUsing(e, filters...)
 		if shouldFilter {
 			err = ack()
 			if err != nil {
@@ -88,21 +113,197 @@ func awaitWorkflowStatusByForeignID[Type any, Status StatusType](
 			return nil, err
 		}
 
-		var t Type
-		err = Unmarshal(r.Object, &t)
+		run, err := awaitedRun(w, r)
 		if err != nil {
 			return nil, err
 		}
 
-		return &Run[Type, Status]{
-			TypedRecord: TypedRecord[Type, Status]{
-				Record: *r,
-				Status: Status(r.Status),
-				Object: &t,
-			},
-			controller: NewRunStateController(w.recordStore.Store, r),
-		}, ack()
+		return run, ack()
+	}
+}
+
+// awaitSharedStatus waits for the run to reach the status using the workflow's shared await receivers, see
+// WithSharedAwait.
+func awaitSharedStatus[Type any, Status StatusType](
+	ctx context.Context,
+	w *Workflow[Type, Status],
+	status Status,
+	foreignID, runID string,
+	pollFrequency time.Duration,
+) (*Run[Type, Status], error) {
+	// Terminal statuses are awaited using the RunStateChangeTopic in the same way as Await.
+	topic := w.topic(status)
+	newReceiver := func(ctx context.Context, name string) (EventReceiver, error) {
+		return w.newStatusReceiver(ctx, status, name, WithReceiverPollFrequency(pollFrequency))
+	}
+	var filters []EventFilter
+	if w.statusGraph.IsTerminal(int(status)) {
+		topic = RunStateChangeTopic(w.Name())
+		newReceiver = func(ctx context.Context, name string) (EventReceiver, error) {
+			return w.eventStreamer.NewReceiver(ctx, topic, name, WithReceiverPollFrequency(pollFrequency))
+		}
+		filters = append(filters, filterByStatus(int(status)))
+	}
+
+	r, _, err := awaitShared(ctx, w, topic, newReceiver, foreignID, runID, func(r *Record) bool {
+		return statusReached(r, int(status))
+	}, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	return awaitedRun(w, r)
+}
+
+// awaitedRun returns the run of the record that has been awaited.
+func awaitedRun[Type any, Status StatusType](w *Workflow[Type, Status], r *Record) (*Run[Type, Status], error) {
+	var t Type
+	err := w.codec.Unmarshal(r.Object, &t)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Run[Type, Status]{
+		TypedRecord: TypedRecord[Type, Status]{
+			Record: *r,
+			Status: Status(r.Status),
+			Object: &t,
+		},
+		controller: NewRunStateController(w.recordStore.Store, r),
+		codec:      w.codec,
+	}, nil
+}
+
+// AwaitAny blocks until the run reaches any of the provided statuses and returns the status that was reached along
+// with the run. The run is looked up once the status has been reached and so the returned run may have since moved
+// on from the status.
+func (w *Workflow[Type, Status]) AwaitAny(
+	ctx context.Context,
+	foreignID, runID string,
+	statuses ...Status,
+) (Status, *Run[Type, Status], error) {
+	if len(statuses) == 0 {
+		return 0, nil, errors.New("await any: at least one status is required")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		status Status
+		run    *Run[Type, Status]
+		err    error
+	}
+
+	// Each status has its own receiver and the first status to be reached is returned.
+	results := make(chan result, len(statuses))
+	for _, status := range statuses {
+		go func() {
+			role := makeRole("await", w.roleName(), strconv.FormatInt(int64(status), 10), foreignID)
+			r, err := awaitWorkflowStatusByForeignID[Type, Status](
+				ctx,
+				w,
+				status,
+				foreignID,
+				runID,
+				role,
+				w.defaultOpts.pollingFrequency,
+			)
+			results <- result{status: status, run: r, err: err}
+		}()
+	}
+
+	res := <-results
+	if res.err != nil {
+		return 0, nil, res.err
+	}
+
+	return res.status, res.run, nil
+}
+
+// AwaitTerminal blocks until the run has finished, by being completed, cancelled, or having its data deleted, and
+// returns the RunState that the run finished in along with the run.
+func (w *Workflow[Type, Status]) AwaitTerminal(
+	ctx context.Context,
+	foreignID, runID string,
+	opts ...AwaitOption,
+) (RunState, *Run[Type, Status], error) {
+	var opt awaitOpts
+	for _, option := range opts {
+		option(&opt)
+	}
+
+	pollFrequency := w.defaultOpts.pollingFrequency
+	if opt.pollFrequency > 0 {
+		pollFrequency = opt.pollFrequency
+	}
+
+	if w.awaitHub != nil {
+		return awaitSharedTerminal(ctx, w, foreignID, runID, pollFrequency)
+	}
+
+	role := makeRole("await", w.roleName(), "terminal", foreignID)
+	stream, err := w.eventStreamer.NewReceiver(
+		ctx,
+		RunStateChangeTopic(w.Name()),
+		role,
+		WithReceiverPollFrequency(pollFrequency),
+	)
+	if err != nil {
+		return RunStateUnknown, nil, err
+	}
+	defer stream.Close()
+
+	var finished RunState
+	r, err := awaitRun(ctx, w, stream, foreignID, runID, func(e *Event) bool {
+		rs, err := strconv.ParseInt(e.Headers[HeaderRunState], 10, 64)
+		if err != nil || !RunState(rs).Finished() {
+			return true
+		}
+
+		finished = RunState(rs)
+		return false
+	})
+	if err != nil {
+		return RunStateUnknown, nil, err
+	}
+
+	return finished, r, nil
+}
+
+// awaitSharedTerminal waits for the run to finish using the workflow's shared await receivers, see WithSharedAwait.
+func awaitSharedTerminal[Type any, Status StatusType](
+	ctx context.Context,
+	w *Workflow[Type, Status],
+	foreignID, runID string,
+	pollFrequency time.Duration,
+) (RunState, *Run[Type, Status], error) {
+	topic := RunStateChangeTopic(w.Name())
+	r, e, err := awaitShared(ctx, w, topic, func(ctx context.Context, name string) (EventReceiver, error) {
+		return w.eventStreamer.NewReceiver(ctx, topic, name, WithReceiverPollFrequency(pollFrequency))
+	}, foreignID, runID, func(r *Record) bool {
+		return r.RunState.Finished()
+	}, filterByFinished())
+	if err != nil {
+		return RunStateUnknown, nil, err
+	}
+
+	// The run finished in the run state of the event as the run's current run state may have changed since, such as
+	// when its data has since been deleted.
+	finished := r.RunState
+	if e != nil {
+		rs, err := strconv.ParseInt(e.Headers[HeaderRunState], 10, 64)
+		if err == nil {
+			finished = RunState(rs)
+		}
+	}
+
+	run, err := awaitedRun(w, r)
+	if err != nil {
+		return RunStateUnknown, nil, err
 	}
+
+	return finished, run, nil
 }
 
 type awaitOpts struct {
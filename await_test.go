@@ -2,6 +2,8 @@ package workflow_test
 
 import (
 	"context"
+	"strconv"
+	"sync"
 	"testing"
 	"time"
 
@@ -42,3 +44,164 @@ func TestAwait(t *testing.T) {
 	require.Equal(t, StatusEnd, res.Status)
 	require.Equal(t, "hello world", *res.Object)
 }
+
+func buildAwaitWorkflow(t *testing.T) *workflow.Workflow[string, status] {
+	b := workflow.NewBuilder[string, status]("await")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return StatusMiddle, nil
+	}, StatusMiddle)
+	b.AddStep(StatusMiddle, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		if *r.Object == "cancel" {
+			return r.Cancel(ctx)
+		}
+
+		return StatusEnd, nil
+	}, StatusEnd)
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+		workflow.WithDefaultOptions(
+			workflow.PollingFrequency(10*time.Millisecond),
+			workflow.ErrBackOff(10*time.Millisecond),
+		),
+	)
+
+	wf.Run(context.Background())
+	t.Cleanup(wf.Stop)
+	return wf
+}
+
+func TestAwaitAny(t *testing.T) {
+	wf := buildAwaitWorkflow(t)
+	ctx := context.Background()
+
+	runID, err := wf.Trigger(ctx, "1", StatusStart)
+	require.Nil(t, err)
+
+	// The run passes through StatusMiddle before it reaches StatusEnd.
+	reached, res, err := wf.AwaitAny(ctx, "1", runID, StatusMiddle, StatusEnd)
+	require.Nil(t, err)
+	require.Equal(t, StatusMiddle, reached)
+	require.Equal(t, runID, res.RunID)
+
+	reached, res, err = wf.AwaitAny(ctx, "1", runID, StatusEnd)
+	require.Nil(t, err)
+	require.Equal(t, StatusEnd, reached)
+	require.Equal(t, StatusEnd, res.Status)
+
+	_, _, err = wf.AwaitAny(ctx, "1", runID)
+	require.NotNil(t, err)
+}
+
+func TestAwaitTerminal(t *testing.T) {
+	testCases := []struct {
+		name     string
+		object   string
+		expected workflow.RunState
+	}{
+		{
+			name:     "Completed",
+			object:   "complete",
+			expected: workflow.RunStateCompleted,
+		},
+		{
+			name:     "Cancelled",
+			object:   "cancel",
+			expected: workflow.RunStateCancelled,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			wf := buildAwaitWorkflow(t)
+			ctx := context.Background()
+
+			runID, err := wf.Trigger(ctx, "1", StatusStart, workflow.WithInitialValue[string, status](&tc.object))
+			require.Nil(t, err)
+
+			runState, res, err := wf.AwaitTerminal(ctx, "1", runID, workflow.WithAwaitPollingFrequency(10*time.Millisecond))
+			require.Nil(t, err)
+			require.Equal(t, tc.expected, runState)
+			require.Equal(t, tc.expected, res.RunState)
+		})
+	}
+}
+
+func TestWithSharedAwait(t *testing.T) {
+	release := make(chan struct{})
+
+	b := workflow.NewBuilder[string, status]("shared await")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		<-release
+		*r.Object = "hello world"
+		return StatusMiddle, nil
+	}, StatusMiddle)
+	b.AddStep(StatusMiddle, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	streamer := &receiverCountingStreamer{StreamConstructor: memstreamer.New()}
+	wf := b.Build(
+		streamer,
+		memrecordstore.New(),
+		memrolescheduler.New(),
+		workflow.WithSharedAwait(),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	streamer.mu.Lock()
+	launched := len(streamer.receivers)
+	streamer.mu.Unlock()
+
+	const runs = 10
+	runIDs := make(map[string]string)
+	for i := range runs {
+		foreignID := "foreignID-" + strconv.Itoa(i)
+		runID, err := wf.Trigger(ctx, foreignID, StatusStart)
+		require.Nil(t, err)
+
+		runIDs[foreignID] = runID
+	}
+
+	var wg sync.WaitGroup
+	for foreignID, runID := range runIDs {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+
+			run, err := wf.Await(ctx, foreignID, runID, StatusMiddle)
+			require.Nil(t, err)
+			require.Equal(t, foreignID, run.ForeignID)
+			require.Equal(t, "hello world", *run.Object)
+		}()
+		go func() {
+			defer wg.Done()
+
+			runState, run, err := wf.AwaitTerminal(ctx, foreignID, runID)
+			require.Nil(t, err)
+			require.Equal(t, workflow.RunStateCompleted, runState)
+			require.Equal(t, StatusEnd, run.Status)
+		}()
+	}
+
+	// Wait for the callers to subscribe before the runs move on.
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	// The callers share a receiver per topic rather than each creating their own.
+	streamer.mu.Lock()
+	require.Len(t, streamer.receivers[launched:], 2)
+	streamer.mu.Unlock()
+
+	// Statuses that the run has already reached are returned without waiting for an event.
+	for foreignID, runID := range runIDs {
+		run, err := wf.Await(ctx, foreignID, runID, StatusMiddle)
+		require.Nil(t, err)
+		require.Equal(t, StatusEnd, run.Status)
+	}
+}
@@ -0,0 +1,222 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WithSharedAwait has the calls to Await, AwaitAny, and AwaitTerminal on this instance of the workflow share a single
+// receiver per topic, rather than each call creating its own receiver, so that services with many concurrent callers,
+// such as API pods each awaiting different runs, can await runs without a consumer per call. The shared receivers are
+// started by the first caller awaiting the topic and are stopped once no callers are awaiting it. The run is looked up
+// once the caller is waiting so that statuses that the run reached before the call are returned without waiting for
+// an event.
+func WithSharedAwait() BuildOption {
+	return func(bo *buildOptions) {
+		bo.sharedAwait = true
+	}
+}
+
+// awaitHub fans out the events of the shared receivers of the workflow's topics to the callers awaiting them.
+type awaitHub struct {
+	// name is unique to the instance of the workflow so that each instance receives all the events of the topics.
+	name       string
+	errBackOff time.Duration
+
+	mu            sync.Mutex
+	subscriptions map[string]*awaitSubscription
+}
+
+func newAwaitHub(errBackOff time.Duration) *awaitHub {
+	return &awaitHub{
+		name:          uuid.New().String(),
+		errBackOff:    errBackOff,
+		subscriptions: make(map[string]*awaitSubscription),
+	}
+}
+
+type awaitSubscription struct {
+	cancel  context.CancelFunc
+	waiters map[*awaitWaiter]bool
+}
+
+type awaitWaiter struct {
+	filters []EventFilter
+	// events receives the first event that is not filtered out.
+	events chan *Event
+}
+
+// subscribe registers a waiter of the events of the topic, starting the topic's shared receiver using newReceiver if
+// it is not already running. The waiter needs to be unsubscribed once it is no longer waiting.
+func (h *awaitHub) subscribe(
+	topic string,
+	newReceiver func(ctx context.Context, name string) (EventReceiver, error),
+	filters ...EventFilter,
+) *awaitWaiter {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	waiter := &awaitWaiter{
+		filters: filters,
+		events:  make(chan *Event, 1),
+	}
+
+	sub, ok := h.subscriptions[topic]
+	if !ok {
+		ctx, cancel := context.WithCancel(context.Background())
+		sub = &awaitSubscription{
+			cancel:  cancel,
+			waiters: make(map[*awaitWaiter]bool),
+		}
+		h.subscriptions[topic] = sub
+
+		go h.receive(ctx, sub, makeRole("await", "shared", topic, h.name), newReceiver)
+	}
+
+	sub.waiters[waiter] = true
+	return waiter
+}
+
+// unsubscribe removes the waiter and stops the topic's shared receiver once it has no waiters.
+func (h *awaitHub) unsubscribe(topic string, waiter *awaitWaiter) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	sub, ok := h.subscriptions[topic]
+	if !ok {
+		return
+	}
+
+	delete(sub.waiters, waiter)
+	if len(sub.waiters) == 0 {
+		sub.cancel()
+		delete(h.subscriptions, topic)
+	}
+}
+
+// receive passes the events of the shared receiver to the subscription's waiters until the subscription is stopped,
+// creating the receiver again after errors.
+func (h *awaitHub) receive(
+	ctx context.Context,
+	sub *awaitSubscription,
+	name string,
+	newReceiver func(ctx context.Context, name string) (EventReceiver, error),
+) {
+	for ctx.Err() == nil {
+		err := h.receiveOnce(ctx, sub, name, newReceiver)
+		if err == nil || ctx.Err() != nil {
+			return
+		}
+
+		t := time.NewTimer(h.errBackOff)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+		case <-t.C:
+		}
+	}
+}
+
+func (h *awaitHub) receiveOnce(
+	ctx context.Context,
+	sub *awaitSubscription,
+	name string,
+	newReceiver func(ctx context.Context, name string) (EventReceiver, error),
+) error {
+	stream, err := newReceiver(ctx, name)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	for {
+		e, ack, err := stream.Recv(ctx)
+		if err != nil {
+			return err
+		}
+
+		h.mu.Lock()
+		for waiter := range sub.waiters {
+			if FilterUsing(e, waiter.filters...) {
+				continue
+			}
+
+			select {
+			case waiter.events <- e:
+			default:
+				// The waiter has already received an event.
+			}
+		}
+		h.mu.Unlock()
+
+		err = ack()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// awaitShared waits for the run using the shared receiver of the topic and returns the run's record once an event of
+// the run that is not filtered out is received, along with the event. The record is returned without an event when
+// the run has already been reached according to the record store.
+func awaitShared[Type any, Status StatusType](
+	ctx context.Context,
+	w *Workflow[Type, Status],
+	topic string,
+	newReceiver func(ctx context.Context, name string) (EventReceiver, error),
+	foreignID, runID string,
+	reached func(r *Record) bool,
+	filters ...EventFilter,
+) (*Record, *Event, error) {
+	filters = append([]EventFilter{
+		filterByForeignID(foreignID),
+		filterByRunID(runID),
+	}, filters...)
+
+	waiter := w.awaitHub.subscribe(topic, newReceiver, filters...)
+	defer w.awaitHub.unsubscribe(topic, waiter)
+
+	// The run is looked up once the waiter is subscribed so that the run cannot be reached without either the
+	// lookup or the waiter seeing it.
+	r, err := w.recordStore.Lookup(ctx, runID)
+	if err == nil && r.WorkflowName == w.Name() && r.ForeignID == foreignID && reached(r) {
+		return r, nil, nil
+	} else if err != nil && !errors.Is(err, ErrRecordNotFound) {
+		return nil, nil, err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case e := <-waiter.events:
+			r, err := w.recordStore.Lookup(ctx, e.ForeignID)
+			if errors.Is(err, ErrRecordNotFound) {
+				continue
+			} else if err != nil {
+				return nil, nil, err
+			}
+
+			return r, e, nil
+		}
+	}
+}
+
+// statusReached returns true when the run is in the status or has been in the status according to its history.
+func statusReached(r *Record, status int) bool {
+	if r.Status == status {
+		return true
+	}
+
+	for _, t := range r.Meta.History {
+		if t.ToStatus == status {
+			return true
+		}
+	}
+
+	return false
+}
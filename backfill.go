@@ -0,0 +1,200 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const defaultBackfillConcurrency = 10
+
+// BackfillProgress is the progress of a backfill which is provided to the progress func set using
+// WithBackfillProgress after every page of runs and can be provided to ResumeBackfill to continue a backfill that was
+// stopped.
+type BackfillProgress struct {
+	// Offset is the number of matching runs that have been processed.
+	Offset int64
+	// CreatedTo is the time that the backfill started which excludes the runs triggered by the backfill, and any
+	// other runs created after the backfill started, from the backfill.
+	CreatedTo time.Time
+
+	// Triggered is the number of runs that were triggered.
+	Triggered int64
+	// Skipped is the number of runs whose foreign ID already has a run that was created after the backfill started,
+	// such as when the run was backfilled before the backfill was resumed, or has a run in progress.
+	Skipped int64
+	// Failed is the number of runs that could not be triggered. The errors are logged.
+	Failed int64
+
+	// Done is true once all the matching runs have been processed.
+	Done bool
+}
+
+type backfillOpts struct {
+	concurrency int
+	progress    func(ctx context.Context, p BackfillProgress)
+	resume      *BackfillProgress
+}
+
+type BackfillOption func(o *backfillOpts)
+
+// WithBackfillConcurrency sets the maximum number of runs that are triggered concurrently. The default is 10.
+func WithBackfillConcurrency(n int) BackfillOption {
+	return func(o *backfillOpts) {
+		o.concurrency = n
+	}
+}
+
+// WithBackfillProgress sets a func that is called with the progress of the backfill after every page of runs has
+// been processed. The progress can be stored to resume the backfill using ResumeBackfill if it is stopped.
+func WithBackfillProgress(fn func(ctx context.Context, p BackfillProgress)) BackfillOption {
+	return func(o *backfillOpts) {
+		o.progress = fn
+	}
+}
+
+// ResumeBackfill continues a backfill from its last reported progress. The same filter and starting status must be
+// provided to resume the backfill.
+func ResumeBackfill(p BackfillProgress) BackfillOption {
+	return func(o *backfillOpts) {
+		o.resume = &p
+	}
+}
+
+// Backfill triggers a new run, at the starting status, for every run that matches the filter using the matching
+// run's Object as the initial value. Providing the status of a step as the starting status re-runs that step, and the
+// steps after it, for every matching run. Runs created after the backfill started are not included and each foreign
+// ID is only triggered once, which allows a backfill to be resumed, using ResumeBackfill, without triggering the
+// runs that were processed before it stopped again.
+//
+// The filter's Limit is the number of runs processed per page and the filter's Offset is ignored. Backfill returns
+// once all the matching runs have been processed or the context is cancelled.
+func (w *Workflow[Type, Status]) Backfill(
+	ctx context.Context,
+	filter RunFilter[Status],
+	startingStatus Status,
+	opts ...BackfillOption,
+) (*BackfillProgress, error) {
+	o := backfillOpts{
+		concurrency: defaultBackfillConcurrency,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	progress := BackfillProgress{
+		CreatedTo: w.clock.Now(),
+	}
+
+	if o.resume != nil {
+		progress = *o.resume
+	}
+
+	if !filter.CreatedTo.IsZero() && filter.CreatedTo.Before(progress.CreatedTo) {
+		progress.CreatedTo = filter.CreatedTo
+	}
+
+	filter.CreatedTo = progress.CreatedTo
+	for !progress.Done {
+		if ctx.Err() != nil {
+			return &progress, ctx.Err()
+		}
+
+		filter.Offset = progress.Offset
+		runs, err := w.ListRuns(ctx, filter)
+		if err != nil {
+			return &progress, fmt.Errorf("backfill: %w, meta: %v", err, map[string]string{
+				"offset": fmt.Sprint(progress.Offset),
+			})
+		}
+
+		var (
+			wg  sync.WaitGroup
+			mu  sync.Mutex
+			sem = make(chan struct{}, max(o.concurrency, 1))
+		)
+
+		for i := range runs {
+			select {
+			case <-ctx.Done():
+				wg.Wait()
+				return &progress, ctx.Err()
+			case sem <- struct{}{}:
+			}
+
+			wg.Add(1)
+			go func(run *TypedRecord[Type, Status]) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				triggered, err := w.backfillRun(ctx, run, startingStatus, progress.CreatedTo)
+
+				mu.Lock()
+				defer mu.Unlock()
+
+				switch {
+				case err != nil:
+					progress.Failed++
+					w.logger.Error(ctx, fmt.Errorf("backfill run: %w, meta: %v", err, map[string]string{
+						"workflow_name": w.Name(),
+						"run_id":        run.RunID,
+						"foreign_id":    run.ForeignID,
+					}))
+				case triggered:
+					progress.Triggered++
+				default:
+					progress.Skipped++
+				}
+			}(&runs[i])
+		}
+
+		wg.Wait()
+
+		if ctx.Err() != nil {
+			return &progress, ctx.Err()
+		}
+
+		progress.Offset += int64(len(runs))
+		limit := filter.Limit
+		if limit <= 0 {
+			limit = defaultListRunsLimit
+		}
+
+		progress.Done = len(runs) < limit
+		if o.progress != nil {
+			o.progress(ctx, progress)
+		}
+	}
+
+	return &progress, nil
+}
+
+// backfillRun triggers a new run for the foreign ID of the run and returns false when the foreign ID has a run that
+// was created after the backfill started or has a run in progress.
+func (w *Workflow[Type, Status]) backfillRun(
+	ctx context.Context,
+	run *TypedRecord[Type, Status],
+	startingStatus Status,
+	createdTo time.Time,
+) (bool, error) {
+	latest, err := w.recordStore.Latest(ctx, w.Name(), run.ForeignID)
+	if err != nil {
+		return false, err
+	}
+
+	if !latest.CreatedAt.Before(createdTo) {
+		return false, nil
+	}
+
+	_, err = w.Trigger(ctx, run.ForeignID, startingStatus, WithInitialValue[Type, Status](run.Object))
+	if errors.Is(err, ErrWorkflowInProgress) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
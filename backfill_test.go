@@ -0,0 +1,100 @@
+package workflow_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestBackfill(t *testing.T) {
+	var middleCalls atomic.Int64
+	b := workflow.NewBuilder[MyType, status]("backfill")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		return StatusMiddle, nil
+	}, StatusMiddle)
+	b.AddStep(StatusMiddle, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		middleCalls.Add(1)
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	recordStore := memrecordstore.New()
+	wf := b.Build(
+		memstreamer.New(),
+		recordStore,
+		memrolescheduler.New(),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	for _, foreignID := range []string{"user-1", "user-2", "user-3", "admin-1"} {
+		runID, err := wf.Trigger(ctx, foreignID, StatusStart, workflow.WithInitialValue[MyType, status](&MyType{
+			Name: foreignID,
+		}))
+		require.Nil(t, err)
+
+		_, err = wf.Await(ctx, foreignID, runID, StatusEnd, workflow.WithAwaitPollingFrequency(10*time.Millisecond))
+		require.Nil(t, err)
+	}
+
+	require.Equal(t, int64(4), middleCalls.Load())
+
+	filter := workflow.RunFilter[status]{
+		Statuses:        []status{StatusEnd},
+		RunStates:       []workflow.RunState{workflow.RunStateCompleted},
+		ForeignIDPrefix: "user-",
+		Limit:           2,
+	}
+
+	// Stop the backfill after its first page.
+	stopCtx, stop := context.WithCancel(ctx)
+	var reported []workflow.BackfillProgress
+	progress, err := wf.Backfill(stopCtx, filter, StatusMiddle,
+		workflow.WithBackfillConcurrency(2),
+		workflow.WithBackfillProgress(func(ctx context.Context, p workflow.BackfillProgress) {
+			reported = append(reported, p)
+			stop()
+		}),
+	)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Len(t, reported, 1)
+	require.Equal(t, int64(2), progress.Offset)
+	require.Equal(t, int64(2), progress.Triggered)
+	require.False(t, progress.Done)
+
+	// Resuming the backfill only triggers the runs that were not processed before it stopped.
+	progress, err = wf.Backfill(ctx, filter, StatusMiddle, workflow.ResumeBackfill(reported[0]))
+	require.Nil(t, err)
+	require.Equal(t, int64(3), progress.Offset)
+	require.Equal(t, int64(3), progress.Triggered)
+	require.Equal(t, int64(0), progress.Skipped)
+	require.True(t, progress.Done)
+
+	require.Eventually(t, func() bool {
+		return middleCalls.Load() == 7
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Resuming from the start again skips the foreign IDs that have already been backfilled.
+	progress, err = wf.Backfill(ctx, filter, StatusMiddle, workflow.ResumeBackfill(workflow.BackfillProgress{
+		CreatedTo: reported[0].CreatedTo,
+	}))
+	require.Nil(t, err)
+	require.Equal(t, int64(0), progress.Triggered)
+	require.Equal(t, int64(3), progress.Skipped)
+
+	// The backfilled runs start at the step's status with the Object of the run that was backfilled.
+	latest, err := recordStore.Latest(ctx, "backfill", "user-2")
+	require.Nil(t, err)
+	run, err := wf.GetRun(ctx, "user-2", latest.RunID)
+	require.Nil(t, err)
+	require.Equal(t, "user-2", run.Object.Name)
+}
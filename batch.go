@@ -0,0 +1,307 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"k8s.io/utils/clock"
+
+	"github.com/luno/workflow/internal/errorcounter"
+	"github.com/luno/workflow/internal/metrics"
+)
+
+// BatchOutcome is the outcome of processing a single run of a batch.
+type BatchOutcome[Status StatusType] struct {
+	// Next is the status that the run moves to. The values returned by r.Skip, r.Pause, and r.Cancel can also be
+	// used as Next.
+	Next Status
+	// Err results in the run being retried in a later batch and counts towards the PauseAfterErrCount of the run.
+	Err error
+}
+
+// BatchResult holds the outcome of each run of a batch keyed by the run's RunID.
+type BatchResult[Status StatusType] map[string]BatchOutcome[Status]
+
+// BatchConsumerFunc processes a batch of runs and returns the outcome of each run. Runs that are successful are
+// moved to their next status even if other runs in the batch fail. Runs that fail, or that are missing from the
+// BatchResult, are retried in a later batch. A non-nil error results in the entire batch being retried.
+type BatchConsumerFunc[Type any, Status StatusType] func(
+	ctx context.Context,
+	runs []*Run[Type, Status],
+) (BatchResult[Status], error)
+
+var errMissingBatchOutcome = errors.New("run missing from batch result")
+
+// AddBatchStep adds a consumer of the provided status that processes runs in batches instead of one at a time. This
+// is useful for high volume statuses where the work is more efficiently done in bulk, such as bulk database writes or
+// bulk API calls. A batch is processed once it reaches size runs or once flushInterval has passed since the first run
+// of the batch was received, whichever comes first.
+//
+// Batch steps support the same options as AddStep. Consumer middleware and tracing are not applied to batch steps.
+func (b *Builder[Type, Status]) AddBatchStep(
+	from Status,
+	fn BatchConsumerFunc[Type, Status],
+	size int,
+	flushInterval time.Duration,
+	allowedDestinations ...Status,
+) *stepUpdater[Type, Status] {
+	if _, exists := b.workflow.consumers[from]; exists {
+		panic("'AddBatchStep(" + from.String() + ",' already exists. Only one Step can be configured to consume the status")
+	}
+
+	if size < 1 {
+		panic("'AddBatchStep(" + from.String() + ",' batch size must be greater than zero")
+	}
+
+	if flushInterval <= 0 {
+		panic("'AddBatchStep(" + from.String() + ",' flush interval must be greater than zero")
+	}
+
+	b.addTransitions(TransitionKindBatchStep, from, allowedDestinations...)
+
+	p := consumerConfig[Type, Status]{
+		batch: &batchConfig[Type, Status]{
+			consumer:      fn,
+			size:          size,
+			flushInterval: flushInterval,
+		},
+	}
+
+	b.workflow.consumers[from] = p
+
+	return &stepUpdater[Type, Status]{
+		from:     from,
+		workflow: b.workflow,
+	}
+}
+
+type batchConfig[Type any, Status StatusType] struct {
+	consumer      BatchConsumerFunc[Type, Status]
+	size          int
+	flushInterval time.Duration
+}
+
+// consumeBatch receives events until there are size events or flushInterval has passed since the first event was
+// received and then provides the events to consumeFn. Events are only acknowledged once consumeFn has returned a nil
+// error.
+func consumeBatch(
+	ctx context.Context,
+	workflowName string,
+	processName string,
+	receiver EventReceiver,
+	size int,
+	flushInterval time.Duration,
+	consumeFn func(ctx context.Context, events []*Event) error,
+	clock clock.Clock,
+	lag time.Duration,
+	lagAlert time.Duration,
+	filters ...EventFilter,
+) error {
+	for {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		var (
+			events   []*Event
+			acks     []Ack
+			deadline time.Time
+		)
+
+		for len(events) < size {
+			recvCtx, cancel := ctx, context.CancelFunc(func() {})
+			if len(events) > 0 {
+				recvCtx, cancel = context.WithTimeout(ctx, deadline.Sub(clock.Now()))
+			}
+
+			e, ack, err := receiver.Recv(recvCtx)
+			cancel()
+			if len(events) > 0 && ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded) {
+				// The flush interval has passed and so the batch is processed without being full.
+				break
+			} else if err != nil {
+				return err
+			}
+
+			// Wait until the event's timestamp matches or is older than the specified lag.
+			delay := lag - clock.Since(e.CreatedAt)
+			if lag > 0 && delay > 0 {
+				t := clock.NewTimer(delay)
+				select {
+				case <-ctx.Done():
+					t.Stop()
+					return ctx.Err()
+				case <-t.C():
+					// Resume to consume the event now that it matches or is older than specified lag.
+				}
+			}
+
+			// Push metrics and alerting around the age of the event being processed.
+			pushLagMetricAndAlerting(ctx, workflowName, processName, e.CreatedAt, lagAlert, clock)
+
+			if FilterUsing(e, filters...) {
+				metrics.ProcessSkippedEvents.WithLabelValues(workflowName, processName, "filtered out").Inc()
+
+				// Events can only be acknowledged in order and so filtered events are only acknowledged
+				// immediately when there are no events waiting to be processed.
+				if len(events) == 0 {
+					err = ack()
+					if err != nil {
+						return err
+					}
+
+					continue
+				}
+
+				acks = append(acks, ack)
+				continue
+			}
+
+			if len(events) == 0 {
+				deadline = clock.Now().Add(flushInterval)
+			}
+
+			events = append(events, e)
+			acks = append(acks, ack)
+		}
+
+		t0 := clock.Now()
+		err := consumeFn(ctx, events)
+		if err != nil {
+			return err
+		}
+
+		for _, ack := range acks {
+			err = ack()
+			if err != nil {
+				return err
+			}
+		}
+
+		metrics.ProcessLatency.WithLabelValues(workflowName, processName).Observe(clock.Since(t0).Seconds())
+		metrics.ProcessConsumedEvents.WithLabelValues(workflowName, processName).Add(float64(len(events)))
+	}
+}
+
+func batchStepConsumer[Type any, Status StatusType](
+	workflowName string,
+	processName string,
+	batchLogic BatchConsumerFunc[Type, Status],
+	currentStatus Status,
+	lookupFn lookupFunc,
+	store storeFunc,
+	codec Codec,
+	logger *logger,
+	updater updater[Type, Status],
+	pauseAfterErrCount int,
+	errorCounter errorcounter.ErrorCounter,
+	quarantine quarantineFunc,
+	deadLetter deadLetterFunc,
+) func(ctx context.Context, events []*Event) error {
+	return func(ctx context.Context, events []*Event) error {
+		var runs []*Run[Type, Status]
+		included := make(map[string]bool)
+		for _, e := range events {
+			run, err := consumableRun[Type, Status](
+				ctx,
+				workflowName,
+				processName,
+				e,
+				currentStatus,
+				lookupFn,
+				store,
+				codec,
+				logger,
+				quarantine,
+			)
+			if err != nil {
+				return err
+			} else if run == nil {
+				continue
+			}
+
+			// A run can only be included once per batch.
+			if included[run.RunID] {
+				metrics.ProcessSkippedEvents.WithLabelValues(workflowName, processName, "run already in batch").Inc()
+				continue
+			}
+
+			included[run.RunID] = true
+			runs = append(runs, run)
+		}
+
+		if len(runs) == 0 {
+			return nil
+		}
+
+		result, err := batchLogic(ctx, runs)
+		if err != nil {
+			return fmt.Errorf("batch consumer error: %v, meta: %v", err, map[string]string{
+				"batch_size": strconv.Itoa(len(runs)),
+			})
+		}
+
+		var (
+			failed   int
+			firstErr error
+		)
+		for _, run := range runs {
+			outcome, ok := result[run.RunID]
+			if !ok {
+				outcome.Err = errMissingBatchOutcome
+			}
+
+			if outcome.Err != nil {
+				paused, err := maybePause(
+					ctx,
+					pauseAfterErrCount,
+					errorCounter,
+					outcome.Err,
+					processName,
+					run,
+					logger,
+					deadLetter,
+				)
+				if err != nil {
+					return fmt.Errorf("pause error: %v, meta: %v", err, map[string]string{
+						"run_id":     run.RunID,
+						"foreign_id": run.ForeignID,
+					})
+				}
+
+				if !paused {
+					failed++
+					if firstErr == nil {
+						firstErr = outcome.Err
+					}
+				}
+
+				continue
+			}
+
+			if skipUpdate(outcome.Next) {
+				metrics.ProcessSkippedEvents.WithLabelValues(workflowName, processName, "next value specified skip").Inc()
+				continue
+			}
+
+			err := updater(ctx, currentStatus, outcome.Next, run)
+			if err != nil {
+				return err
+			}
+		}
+
+		if failed > 0 {
+			// The events of the batch are not acknowledged so that the failed runs are retried. The runs that were
+			// updated are skipped when the events are received again as they are no longer in the current status.
+			return fmt.Errorf("batch consumer error: %v, meta: %v", firstErr, map[string]string{
+				"failed":     strconv.Itoa(failed),
+				"batch_size": strconv.Itoa(len(runs)),
+			})
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,191 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/clock"
+
+	"github.com/luno/workflow/internal/errorcounter"
+)
+
+func Test_batchStepConsumer(t *testing.T) {
+	ctx := context.Background()
+	testErr := errors.New("test error")
+
+	value := "data"
+	b, err := Marshal(&value)
+	require.Nil(t, err)
+
+	records := map[string]*Record{
+		"run-1": {WorkflowName: "example", RunID: "run-1", RunState: RunStateRunning, Status: int(statusStart), Object: b},
+		"run-2": {WorkflowName: "example", RunID: "run-2", RunState: RunStateRunning, Status: int(statusStart), Object: b},
+		"run-3": {WorkflowName: "example", RunID: "run-3", RunState: RunStateRunning, Status: int(statusStart), Object: b},
+		// run-4 has already moved on and must not be included in the batch.
+		"run-4": {WorkflowName: "example", RunID: "run-4", RunState: RunStateRunning, Status: int(statusEnd), Object: b},
+	}
+
+	lookup := func(ctx context.Context, runID string) (*Record, error) {
+		return records[runID], nil
+	}
+
+	events := []*Event{
+		{ForeignID: "run-1"},
+		{ForeignID: "run-2"},
+		{ForeignID: "run-2"},
+		{ForeignID: "run-3"},
+		{ForeignID: "run-4"},
+	}
+
+	t.Run("Updates successful runs and returns error for failed runs", func(t *testing.T) {
+		var batch []string
+		updated := make(map[string]testStatus)
+		err := batchStepConsumer[string, testStatus](
+			"example",
+			"process",
+			func(ctx context.Context, runs []*Run[string, testStatus]) (BatchResult[testStatus], error) {
+				for _, r := range runs {
+					batch = append(batch, r.RunID)
+				}
+
+				return BatchResult[testStatus]{
+					"run-1": {Next: statusEnd},
+					"run-2": {Err: testErr},
+					// run-3 is missing from the result and must be retried.
+				}, nil
+			},
+			statusStart,
+			lookup,
+			nil,
+			JSONCodec{},
+			&logger{},
+			func(ctx context.Context, current testStatus, next testStatus, r *Run[string, testStatus]) error {
+				updated[r.RunID] = next
+				return nil
+			},
+			0,
+			errorcounter.New(),
+			nil,
+			nil,
+		)(ctx, events)
+		require.ErrorContains(t, err, testErr.Error())
+		require.Equal(t, []string{"run-1", "run-2", "run-3"}, batch)
+		require.Equal(t, map[string]testStatus{"run-1": statusEnd}, updated)
+	})
+
+	t.Run("Returns error when batch fails", func(t *testing.T) {
+		err := batchStepConsumer[string, testStatus](
+			"example",
+			"process",
+			func(ctx context.Context, runs []*Run[string, testStatus]) (BatchResult[testStatus], error) {
+				return nil, testErr
+			},
+			statusStart,
+			lookup,
+			nil,
+			JSONCodec{},
+			&logger{},
+			func(ctx context.Context, current testStatus, next testStatus, r *Run[string, testStatus]) error {
+				t.Fatal("runs of a failed batch must not be updated")
+				return nil
+			},
+			0,
+			errorcounter.New(),
+			nil,
+			nil,
+		)(ctx, events)
+		require.ErrorContains(t, err, testErr.Error())
+	})
+}
+
+type batchTestReceiver struct {
+	events []*Event
+	acked  int
+}
+
+func (r *batchTestReceiver) Recv(ctx context.Context) (*Event, Ack, error) {
+	if len(r.events) == 0 {
+		<-ctx.Done()
+		return nil, nil, ctx.Err()
+	}
+
+	e := r.events[0]
+	r.events = r.events[1:]
+	return e, func() error {
+		r.acked++
+		return nil
+	}, nil
+}
+
+func (r *batchTestReceiver) Close() error {
+	return nil
+}
+
+func Test_consumeBatch(t *testing.T) {
+	t.Run("Flushes full batches and partial batches after the flush interval", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+
+		receiver := &batchTestReceiver{
+			events: []*Event{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 5}},
+		}
+
+		var batches [][]int64
+		err := consumeBatch(
+			ctx,
+			"example",
+			"process",
+			receiver,
+			2,
+			10*time.Millisecond,
+			func(ctx context.Context, events []*Event) error {
+				var ids []int64
+				for _, e := range events {
+					ids = append(ids, e.ID)
+				}
+
+				batches = append(batches, ids)
+				if len(batches) == 3 {
+					cancel()
+				}
+
+				return nil
+			},
+			clock.RealClock{},
+			0,
+			0,
+		)
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, [][]int64{{1, 2}, {3, 4}, {5}}, batches)
+		require.Equal(t, 5, receiver.acked)
+	})
+
+	t.Run("Does not acknowledge events of a failed batch", func(t *testing.T) {
+		ctx := context.Background()
+		testErr := errors.New("test error")
+
+		receiver := &batchTestReceiver{
+			events: []*Event{{ID: 1}, {ID: 2}},
+		}
+
+		err := consumeBatch(
+			ctx,
+			"example",
+			"process",
+			receiver,
+			2,
+			time.Minute,
+			func(ctx context.Context, events []*Event) error {
+				return testErr
+			},
+			clock.RealClock{},
+			0,
+			0,
+		)
+		require.ErrorIs(t, err, testErr)
+		require.Equal(t, 0, receiver.acked)
+	})
+}
@@ -0,0 +1,98 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ErrBlobNotFound is returned by a BlobStore when there is no blob for the key.
+var ErrBlobNotFound = errors.New("blob not found")
+
+// BlobStore implementations should all be tested with adaptertest.RunBlobStoreTest. Blobs must be streamed to the
+// underlying storage, such as an object store, rather than being buffered in memory so that large payloads can be
+// stored.
+type BlobStore interface {
+	// Put stores the contents of the reader under the key and returns the number of bytes stored.
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+	// Get returns the contents of the blob stored under the key. ErrBlobNotFound is returned when there is no blob
+	// for the key.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the blob stored under the key. Deleting a blob that does not exist is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// WithBlobStore allows the configuration of a BlobStore which is required when using AddBlobCallback.
+func WithBlobStore(s BlobStore) BuildOption {
+	return func(bo *buildOptions) {
+		bo.blobStore = s
+	}
+}
+
+// BlobRef is a reference to a payload that was stored in the workflow's BlobStore. It can be stored in the run's
+// Object in place of the payload and the payload read using the BlobStore's Get.
+type BlobRef struct {
+	Key  string `json:"key"`
+	Size int64  `json:"size"`
+}
+
+// BlobCallbackFunc is called with the reference to the callback's payload once the payload has been stored in the
+// workflow's BlobStore.
+type BlobCallbackFunc[Type any, Status StatusType] func(
+	ctx context.Context,
+	r *Run[Type, Status],
+	ref BlobRef,
+) (Status, error)
+
+// AddBlobCallback adds a callback whose payload, such as an uploaded document, is streamed to the workflow's BlobStore,
+// configured using WithBlobStore, instead of being provided to the callback as an io.Reader. This avoids buffering
+// large payloads in memory and storing them in the run's Object. The payload is only stored when the run is in the
+// status and is deleted again if fn returns an error.
+func (b *Builder[Type, Status]) AddBlobCallback(
+	from Status,
+	fn BlobCallbackFunc[Type, Status],
+	allowedDestinations ...Status,
+) {
+	b.addTransitions(TransitionKindCallback, from, allowedDestinations...)
+
+	b.workflow.callback[from] = append(b.workflow.callback[from], callback[Type, Status]{
+		CallbackFunc: blobCallback(b.workflow, fn),
+		blob:         true,
+	})
+}
+
+func blobCallback[Type any, Status StatusType](
+	w *Workflow[Type, Status],
+	fn BlobCallbackFunc[Type, Status],
+) CallbackFunc[Type, Status] {
+	return func(ctx context.Context, r *Run[Type, Status], reader io.Reader) (Status, error) {
+		key := blobKey(w.Name(), r.ForeignID, r.RunID)
+
+		// The BlobStore is only configured once the workflow has been built.
+		size, err := w.blobStore.Put(ctx, key, reader)
+		if err != nil {
+			return 0, fmt.Errorf("store callback payload: %w, meta: %v", err, map[string]string{
+				"run_id": r.RunID,
+				"key":    key,
+			})
+		}
+
+		next, err := fn(ctx, r, BlobRef{Key: key, Size: size})
+		if err != nil {
+			// The payload is only kept for callbacks that succeed as the callback is retried with a new payload.
+			deleteErr := w.blobStore.Delete(context.WithoutCancel(ctx), key)
+			return 0, errors.Join(err, deleteErr)
+		}
+
+		return next, nil
+	}
+}
+
+// blobKey returns a new key for a payload of the run so that each callback's payload is stored separately.
+func blobKey(workflowName, foreignID, runID string) string {
+	return strings.Join([]string{workflowName, foreignID, runID, uuid.NewString()}, "/")
+}
@@ -0,0 +1,72 @@
+package workflow_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memblobstore"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestAddBlobCallback(t *testing.T) {
+	b := workflow.NewBuilder[workflow.BlobRef, status]("blob callback")
+	b.AddBlobCallback(StatusStart, func(ctx context.Context, r *workflow.Run[workflow.BlobRef, status], ref workflow.BlobRef) (status, error) {
+		if ref.Size == 0 {
+			return 0, errors.New("empty document")
+		}
+
+		*r.Object = ref
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	blobStore := memblobstore.New()
+	recordStore := memrecordstore.New()
+	wf := b.Build(memstreamer.New(), recordStore, memrolescheduler.New(), workflow.WithBlobStore(blobStore))
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "foreignID", StatusStart)
+	require.Nil(t, err)
+
+	// The payload of a failed callback is not kept.
+	err = wf.Callback(ctx, "foreignID", StatusStart, strings.NewReader(""))
+	require.ErrorContains(t, err, "empty document")
+	require.Empty(t, blobStore.Keys())
+
+	document := strings.Repeat("document", 1024)
+	err = wf.Callback(ctx, "foreignID", StatusStart, strings.NewReader(document))
+	require.Nil(t, err)
+
+	run, err := wf.Await(ctx, "foreignID", runID, StatusEnd)
+	require.Nil(t, err)
+	require.Equal(t, int64(len(document)), run.Object.Size)
+	require.True(t, strings.HasPrefix(run.Object.Key, "blob callback/foreignID/"+runID+"/"))
+
+	r, err := blobStore.Get(ctx, run.Object.Key)
+	require.Nil(t, err)
+
+	stored, err := io.ReadAll(r)
+	require.Nil(t, err)
+	require.Equal(t, document, string(stored))
+}
+
+func TestAddBlobCallbackRequiresBlobStore(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("blob callback")
+	b.AddBlobCallback(StatusStart, func(ctx context.Context, r *workflow.Run[string, status], ref workflow.BlobRef) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	require.PanicsWithValue(t, "'AddBlobCallback(Start,' requires a BlobStore. Use WithBlobStore to configure one", func() {
+		b.Build(memstreamer.New(), memrecordstore.New(), memrolescheduler.New())
+	})
+}
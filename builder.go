@@ -2,9 +2,13 @@ package workflow
 
 import (
 	"context"
+	"net/url"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
 	"k8s.io/utils/clock"
 
 	"github.com/luno/workflow/internal/errorcounter"
@@ -17,27 +21,37 @@ const (
 	defaultErrBackOff       = 1 * time.Second
 	defaultLagAlert         = 30 * time.Minute
 
+	defaultStoreUnavailableBackOff = 5 * time.Second
+
 	defaultOutboxLagAlert         = time.Minute
 	defaultOutboxPollingFrequency = 250 * time.Millisecond
 	defaultOutboxErrBackOff       = 500 * time.Millisecond
+	defaultOutboxBatchSize        = 100
 )
 
 func NewBuilder[Type any, Status StatusType](name string) *Builder[Type, Status] {
 	return &Builder[Type, Status]{
 		workflow: &Workflow[Type, Status]{
-			name:          name,
-			clock:         clock.RealClock{},
-			consumers:     make(map[Status]consumerConfig[Type, Status]),
-			callback:      make(map[Status][]callback[Type, Status]),
-			timeouts:      make(map[Status]timeouts[Type, Status]),
-			statusGraph:   graph.New(),
-			errorCounter:  errorcounter.New(),
-			internalState: make(map[string]State),
+			name:            name,
+			clock:           clock.RealClock{},
+			codec:           JSONCodec{},
+			consumers:       make(map[Status]consumerConfig[Type, Status]),
+			callback:        make(map[Status][]callback[Type, Status]),
+			namedCallbacks:  make(map[string]namedCallback[Type, Status]),
+			timeouts:        make(map[Status]timeouts[Type, Status]),
+			timers:          make(map[timerKey[Status]]timeouts[Type, Status]),
+			statusGraph:     graph.New(),
+			transitionKinds: make(map[graph.Transition][]TransitionKind),
+			errorCounter:    errorcounter.New(),
+			internalState:   make(map[string]State),
 			logger: &logger{
 				debugMode: false, // Explicit for readability
 				inner:     interal_logger.New(os.Stdout),
 			},
 			runStateChangeHooks: make(map[RunState]RunStateChangeHookFunc[Type, Status]),
+			compensations:       make(map[Status]CompensationFunc[Type, Status]),
+			statusOptions:       make(map[Status][]Option),
+			statusMiddleware:    make(map[Status][]ConsumerMiddleware[Type, Status]),
 		},
 	}
 }
@@ -46,6 +60,8 @@ type Builder[Type any, Status StatusType] struct {
 	workflow *Workflow[Type, Status]
 }
 
+// AddStep adds the consumer of the provided status. Only one consumer can be added for each status. Use AddFork to
+// fan a run out into branches that are processed concurrently and joined before the run moves onto its next status.
 func (b *Builder[Type, Status]) AddStep(
 	from Status,
 	c ConsumerFunc[Type, Status],
@@ -55,9 +71,7 @@ func (b *Builder[Type, Status]) AddStep(
 		panic("'AddStep(" + from.String() + ",' already exists. Only one Step can be configured to consume the status")
 	}
 
-	for _, to := range allowedDestinations {
-		b.workflow.statusGraph.AddTransition(int(from), int(to))
-	}
+	b.addTransitions(TransitionKindStep, from, allowedDestinations...)
 
 	p := consumerConfig[Type, Status]{
 		consumer: c,
@@ -85,11 +99,18 @@ func (s *stepUpdater[Type, Status]) WithOptions(opts ...Option) {
 	}
 
 	consumer.pollingFrequency = consumerOpts.pollingFrequency
+	consumer.maxPollingFrequency = consumerOpts.maxPollingFrequency
 	consumer.parallelCount = consumerOpts.parallelCount
 	consumer.errBackOff = consumerOpts.errBackOff
 	consumer.lag = consumerOpts.lag
 	consumer.lagAlert = consumerOpts.lagAlert
 	consumer.pauseAfterErrCount = consumerOpts.pauseAfterErrCount
+	consumer.rateLimit = consumerOpts.rateLimit
+	consumer.circuitBreaker = consumerOpts.circuitBreaker
+	consumer.retryPolicy = consumerOpts.retryPolicy
+	consumer.stepTimeout = consumerOpts.stepTimeout
+	consumer.sla = consumerOpts.sla
+	consumer.deadlineFromSLA = consumerOpts.deadlineFromSLA
 	s.workflow.consumers[s.from] = consumer
 }
 
@@ -98,9 +119,7 @@ func (b *Builder[Type, Status]) AddCallback(from Status, fn CallbackFunc[Type, S
 		CallbackFunc: fn,
 	}
 
-	for _, to := range allowedDestinations {
-		b.workflow.statusGraph.AddTransition(int(from), int(to))
-	}
+	b.addTransitions(TransitionKindCallback, from, allowedDestinations...)
 
 	b.workflow.callback[from] = append(b.workflow.callback[from], c)
 }
@@ -118,26 +137,53 @@ func (b *Builder[Type, Status]) AddTimeout(
 		TimeoutFunc: tf,
 	}
 
-	for _, to := range allowedDestinations {
-		b.workflow.statusGraph.AddTransition(int(from), int(to))
-	}
+	b.addTransitions(TransitionKindTimeout, from, allowedDestinations...)
 
 	timeouts.transitions = append(timeouts.transitions, t)
 	b.workflow.timeouts[from] = timeouts
 
 	return &timeoutUpdater[Type, Status]{
 		from:     from,
+		index:    len(timeouts.transitions) - 1,
 		workflow: b.workflow,
 	}
 }
 
 type timeoutUpdater[Type any, Status StatusType] struct {
-	from     Status
+	from  Status
+	index int
+	// name is only set for timers added using AddTimer.
+	name     string
 	workflow *Workflow[Type, Status]
 }
 
+// WithVeto sets a TimeoutVetoFunc that is called with the latest version of the run once the timeout's TimeoutFunc
+// has decided to move the run on. The timeout is cancelled, instead of moving the run, when the veto returns true
+// which avoids the timeout overriding a callback, or another update, that landed just as the timeout expired.
+func (s *timeoutUpdater[Type, Status]) WithVeto(veto TimeoutVetoFunc[Type, Status]) *timeoutUpdater[Type, Status] {
+	s.get().transitions[s.index].VetoFunc = veto
+	return s
+}
+
+func (s *timeoutUpdater[Type, Status]) get() timeouts[Type, Status] {
+	if s.name != "" {
+		return s.workflow.timers[timerKey[Status]{status: s.from, name: s.name}]
+	}
+
+	return s.workflow.timeouts[s.from]
+}
+
+func (s *timeoutUpdater[Type, Status]) set(t timeouts[Type, Status]) {
+	if s.name != "" {
+		s.workflow.timers[timerKey[Status]{status: s.from, name: s.name}] = t
+		return
+	}
+
+	s.workflow.timeouts[s.from] = t
+}
+
 func (s *timeoutUpdater[Type, Status]) WithOptions(opts ...Option) {
-	timeout := s.workflow.timeouts[s.from]
+	timeout := s.get()
 
 	var timeoutOpts options
 	for _, opt := range opts {
@@ -153,10 +199,11 @@ func (s *timeoutUpdater[Type, Status]) WithOptions(opts ...Option) {
 	}
 
 	timeout.pollingFrequency = timeoutOpts.pollingFrequency
+	timeout.maxPollingFrequency = timeoutOpts.maxPollingFrequency
 	timeout.errBackOff = timeoutOpts.errBackOff
 	timeout.lagAlert = timeoutOpts.lagAlert
 	timeout.pauseAfterErrCount = timeoutOpts.pauseAfterErrCount
-	s.workflow.timeouts[s.from] = timeout
+	s.set(timeout)
 }
 
 func (b *Builder[Type, Status]) AddConnector(
@@ -174,6 +221,7 @@ func (b *Builder[Type, Status]) AddConnector(
 		name:        name,
 		constructor: csc,
 		connectorFn: cf,
+		control:     newConnectorControl(),
 	}
 
 	b.workflow.connectorConfigs = append(b.workflow.connectorConfigs, config)
@@ -198,6 +246,7 @@ func (c *connectorUpdater[Type, Status]) WithOptions(opts ...Option) {
 	c.config.errBackOff = connectorOpts.errBackOff
 	c.config.lag = connectorOpts.lag
 	c.config.lagAlert = connectorOpts.lagAlert
+	c.config.dedupWindow = connectorOpts.dedupWindow
 }
 
 func (b *Builder[Type, Status]) OnPause(hook RunStateChangeHookFunc[Type, Status]) {
@@ -219,7 +268,6 @@ func (b *Builder[Type, Status]) Build(
 	opts ...BuildOption,
 ) *Workflow[Type, Status] {
 	b.workflow.eventStreamer = eventStreamer
-	b.workflow.recordStore = recordStore
 	b.workflow.scheduler = roleScheduler
 
 	bo := defaultBuildOptions()
@@ -227,6 +275,8 @@ func (b *Builder[Type, Status]) Build(
 		opt(&bo)
 	}
 
+	b.workflow.recordStore = newPolicyRecordStore(recordStore, bo.storeUnavailablePolicy)
+
 	if bo.clock != nil {
 		b.workflow.clock = bo.clock
 	}
@@ -235,32 +285,363 @@ func (b *Builder[Type, Status]) Build(
 		b.workflow.customDelete = bo.customDelete
 	}
 
+	if bo.codec != nil {
+		b.workflow.codec = bo.codec
+	}
+
+	// Objects are compressed before they are encrypted as encrypted data does not compress.
+	if bo.compressor != nil {
+		b.workflow.codec = &compressedCodec{
+			codec:      b.workflow.codec,
+			compressor: bo.compressor,
+		}
+	}
+
+	if bo.keyProvider != nil {
+		b.workflow.codec = &encryptedCodec{
+			codec: b.workflow.codec,
+			keys:  bo.keyProvider,
+		}
+	}
+
+	b.workflow.redact = bo.redact
+	b.workflow.priorityLanes = bo.priorityLanes
+	b.workflow.topicRetention = bo.topicRetention
+	b.workflow.multiplex = bo.multiplex
 	b.workflow.timeoutStore = bo.timeoutStore
+	b.workflow.legalHoldStore = bo.legalHoldStore
+	b.workflow.archiveStore = bo.archiveStore
 	b.workflow.defaultOpts = bo.defaultOptions
 	b.workflow.outboxConfig = bo.outboxConfig
+	if bo.outboxConfig.batchSize <= 0 {
+		panic("outbox batch size needs to be positive")
+	}
+
 	b.workflow.logger.debugMode = bo.debugMode
+	b.workflow.logger.sink = bo.debugEventSink
+	b.workflow.preflight = bo.preflight
+	b.workflow.dedicatedOutboxDrain = bo.dedicatedOutboxDrain
+	b.workflow.runMode = bo.runMode
+	b.workflow.shutdownOrder = bo.shutdownOrder
+	b.workflow.childCancelPolicy = bo.childCancelPolicy
+
+	b.workflow.blobStore = bo.blobStore
+	for status, callbacks := range b.workflow.callback {
+		for _, c := range callbacks {
+			if c.blob && b.workflow.blobStore == nil {
+				panic("'AddBlobCallback(" + status.String() + ",' requires a BlobStore. Use WithBlobStore to configure one")
+			}
+		}
+	}
+
+	b.workflow.callbackVerifier = bo.callbackVerifier
+
+	b.workflow.lockStore = bo.lockStore
+	if b.workflow.lockStore == nil {
+		b.workflow.lockStore = NewRoleSchedulerLockStore(roleScheduler)
+	}
+
+	b.workflow.searchIndexes = buildSearchIndexes[Type](bo.searchIndexes)
+	if len(b.workflow.searchIndexes) > 0 {
+		_, ok := unwrapRecordStore(recordStore).(SearchableRecordStore)
+		if !ok {
+			panic("cannot configure search indexes without a RecordStore that implements SearchableRecordStore")
+		}
+
+		b.workflow.recordStore = &searchIndexRecordStore[Type, Status]{
+			RecordStore: b.workflow.recordStore,
+			workflow:    b.workflow,
+		}
+	}
+
+	if bo.childCancelPolicy == ChildCancelBlock && len(b.workflow.subWorkflows) > 0 {
+		b.workflow.recordStore = &childBlockingRecordStore[Type, Status]{
+			RecordStore: b.workflow.recordStore,
+			workflow:    b.workflow,
+		}
+	}
+
+	if bo.triggerRateLimit != nil {
+		b.workflow.triggerLimiter = newRateLimiter(*bo.triggerRateLimit, b.workflow.clock)
+	}
+
+	for _, q := range bo.triggerQuotas {
+		if q.Window <= 0 {
+			panic("trigger quota '" + q.Name + "' requires a positive Window")
+		}
+	}
+
+	if len(bo.triggerQuotas) > 0 {
+		b.workflow.triggerQuotas = newQuotas(bo.triggerQuotas, bo.quotaExceededHook, b.workflow.clock)
+	}
+
+	b.workflow.consumerMiddleware = buildConsumerMiddleware[Type, Status](bo.consumerMiddleware)
+
+	registerMetrics(bo.metricsRegisterer)
+
+	if bo.tracerProvider != nil {
+		b.workflow.tracer = bo.tracerProvider.Tracer(tracerName)
+	}
+	if bo.version != 0 {
+		if b.workflow.pinned {
+			// WithGraphVersion would otherwise change the version that the roles and topics of the pinned workflow
+			// are named after.
+			panic("cannot configure WithGraphVersion for a workflow pinned using Builder.Version")
+		}
+
+		b.workflow.version = bo.version
+	}
+
+	b.workflow.compatibilityPolicy = bo.compatibilityPolicy
+	if b.workflow.pinned {
+		// Each pinned version of the workflow only processes the runs that it triggered.
+		b.workflow.compatibilityPolicy = SkipOtherVersions
+	}
+
 	b.workflow.pausedRecordsRetry = bo.autoPauseRetry
+	b.workflow.unmarshalQuarantine = bo.unmarshalQuarantine
+	b.workflow.quarantineAlert = bo.quarantineAlert
+	b.workflow.owner = bo.owner
+	b.workflow.alertHook = bo.alertHook
+	b.workflow.lagAlertHandler = bo.lagAlertHandler
+	for _, h := range bo.webhooks {
+		_, err := url.ParseRequestURI(h.url)
+		if err != nil {
+			panic("webhook requires a valid url: " + err.Error())
+		}
+
+		h.queue = make(chan []byte, webhookQueueSize)
+		b.workflow.webhooks = append(b.workflow.webhooks, h)
+	}
+	b.workflow.stuckRunAlert = bo.stuckRunAlert
+	b.workflow.deadLetterStreamer = bo.deadLetterStreamer
+	b.workflow.deadLetterTopic = bo.deadLetterTopic
+	if bo.sharedAwait {
+		b.workflow.awaitHub = newAwaitHub(b.workflow.defaultOpts.errBackOff)
+	}
+	b.workflow.unknownStatusPolicy = bo.unknownStatusPolicy
+	if bo.unknownStatusPolicy == UnknownStatusForward && bo.deadLetterStreamer == nil {
+		panic("cannot forward the events of unknown statuses without a dead-letter queue. Use WithDeadLetterQueue to configure one")
+	}
 
 	if bo.logger != nil {
 		b.workflow.logger.inner = bo.logger
 	}
 
-	if len(b.workflow.timeouts) > 0 && b.workflow.timeoutStore == nil {
+	for status, defaults := range b.workflow.statusOptions {
+		consumer, ok := b.workflow.consumers[status]
+		if !ok {
+			continue
+		}
+
+		consumer.withDefaults(defaults)
+		b.workflow.consumers[status] = consumer
+	}
+
+	for status, consumer := range b.workflow.consumers {
+		if consumer.batch != nil && b.workflow.priorityLanes > 0 {
+			panic("'AddBatchStep(" + status.String() + ",' priority lanes are not supported by batch steps")
+		}
+
+		if consumer.rateLimit != nil {
+			if consumer.batch != nil {
+				panic("'AddBatchStep(" + status.String() + ",' rate limits are not supported by batch steps")
+			}
+
+			if consumer.rateLimit.perSecond <= 0 {
+				panic("'AddStep(" + status.String() + ",' rate limit requires a positive rate")
+			}
+
+			consumer.limiter = newRateLimiter(*consumer.rateLimit, b.workflow.clock)
+		}
+
+		if consumer.circuitBreaker != nil {
+			if consumer.batch != nil {
+				panic("'AddBatchStep(" + status.String() + ",' circuit breakers are not supported by batch steps")
+			}
+
+			if consumer.circuitBreaker.threshold <= 0 || consumer.circuitBreaker.cooldown <= 0 {
+				panic("'AddStep(" + status.String() + ",' circuit breaker requires a positive threshold and cooldown")
+			}
+
+			consumer.breaker = newCircuitBreaker(b.workflow, status, consumer)
+		}
+
+		if consumer.retryPolicy != nil {
+			if consumer.batch != nil {
+				panic("'AddBatchStep(" + status.String() + ",' retry policies are not supported by batch steps")
+			}
+
+			if !consumer.retryPolicy.validate() {
+				panic("'AddStep(" + status.String() + ",' retry policy requires a positive initial backoff, a max that is not less than the initial backoff, a multiplier of at least 1, and a jitter between 0 and 1")
+			}
+
+			consumer.retrier = newRetryPolicy(*consumer.retryPolicy)
+		}
+
+		if consumer.stepTimeout != 0 {
+			if consumer.batch != nil {
+				panic("'AddBatchStep(" + status.String() + ",' step timeouts are not supported by batch steps")
+			}
+
+			if consumer.stepTimeout < 0 {
+				panic("'AddStep(" + status.String() + ",' step timeout needs to be positive")
+			}
+		}
+
+		if consumer.sla < 0 {
+			panic("'AddStep(" + status.String() + ",' sla needs to be positive")
+		}
+
+		if consumer.deadlineFromSLA {
+			if consumer.batch != nil {
+				panic("'AddBatchStep(" + status.String() + ",' sla deadlines are not supported by batch steps")
+			}
+
+			if consumer.sla == 0 {
+				panic("'AddStep(" + status.String() + ",' sla deadline requires an sla")
+			}
+		}
+
+		if consumer.heartbeatTimeout != 0 {
+			if consumer.batch != nil {
+				panic("'AddBatchStep(" + status.String() + ",' heartbeat timeouts are not supported by batch steps")
+			}
+
+			if consumer.heartbeatTimeout < 0 {
+				panic("'AddStep(" + status.String() + ",' heartbeat timeout needs to be positive")
+			}
+		}
+
+		if consumer.stepConfig != nil {
+			if consumer.batch != nil {
+				panic("'AddBatchStep(" + status.String() + ",' step configs are not supported by batch steps")
+			}
+
+			if v, ok := consumer.stepConfig.(StepConfigValidator); ok {
+				err := v.Validate()
+				if err != nil {
+					panic("'AddStep(" + status.String() + ",' invalid step config: " + err.Error())
+				}
+			}
+		}
+
+		if consumer.concurrencyKey != nil {
+			if consumer.batch != nil {
+				panic("'AddBatchStep(" + status.String() + ",' concurrency keys are not supported by batch steps")
+			}
+
+			if consumer.concurrencyKey.limit <= 0 {
+				panic("'AddStep(" + status.String() + ",' concurrency key requires a positive limit")
+			}
+		}
+
+		b.workflow.consumers[status] = consumer
+	}
+
+	if !bo.skipGraphValidation {
+		problems := b.workflow.validateGraph()
+		if len(problems) > 0 {
+			panic("invalid workflow graph: " + strings.Join(problems, "; ") + ". Use WithoutGraphValidation to disable the validation of the graph")
+		}
+	}
+
+	if bo.multiplex {
+		if b.workflow.multiplexedStreamer() == nil {
+			panic("cannot configure multiplexed consumers without an EventStreamer that implements MultiplexedEventStreamer")
+		}
+
+		if b.workflow.priorityLanes > 0 {
+			panic("cannot configure multiplexed consumers with priority lanes")
+		}
+	}
+
+	if (len(b.workflow.timeouts) > 0 || len(b.workflow.timers) > 0) && b.workflow.timeoutStore == nil {
 		panic("cannot configure timeouts without providing TimeoutStore for workflow")
 	}
 
+	if len(b.workflow.timers) > 0 {
+		_, ok := unwrapTimeoutStore(b.workflow.timeoutStore).(NamedTimeoutStore)
+		if !ok {
+			panic("cannot configure timers without a TimeoutStore that implements NamedTimeoutStore")
+		}
+	}
+
+	if bo.deploymentFencing {
+		store, ok := unwrapRecordStore(recordStore).(DefinitionStore)
+		if !ok {
+			panic("cannot configure deployment fencing without a RecordStore that implements DefinitionStore")
+		}
+
+		b.workflow.fence = newFence(store, Definition{
+			WorkflowName: b.workflow.name,
+			Version:      b.workflow.version,
+			Hash:         b.workflow.statusGraph.Hash(),
+		}, b.workflow.clock)
+	}
+
 	return b.workflow
 }
 
 type buildOptions struct {
 	clock          clock.Clock
 	customDelete   customDelete
+	redact         redactFunc
+	codec          Codec
+	keyProvider    KeyProvider
+	compressor     Compressor
+	priorityLanes  int
+	topicRetention time.Duration
+	multiplex      bool
 	debugMode      bool
+	debugEventSink DebugEventSink
+	preflight      bool
 	defaultOptions options
 	outboxConfig   outboxConfig
 	timeoutStore   TimeoutStore
+	legalHoldStore LegalHoldStore
+	archiveStore   ArchiveStore
 	logger         Logger
 	autoPauseRetry pausedRecordsRetry
+
+	storeUnavailablePolicy StoreUnavailablePolicy
+	triggerRateLimit       *rateLimit
+	triggerQuotas          []Quota
+	quotaExceededHook      QuotaExceededHook
+
+	version             int
+	compatibilityPolicy CompatibilityPolicy
+	deploymentFencing   bool
+
+	dedicatedOutboxDrain bool
+	runMode              RunMode
+	shutdownOrder        ShutdownOrderFunc
+	tracerProvider       trace.TracerProvider
+	metricsRegisterer    prometheus.Registerer
+
+	unmarshalQuarantine bool
+	quarantineAlert     QuarantineAlertFunc
+	deadLetterStreamer  EventStreamer
+	deadLetterTopic     string
+	unknownStatusPolicy UnknownStatusPolicy
+	sharedAwait         bool
+
+	owner           Owner
+	alertHook       AlertHook
+	lagAlertHandler LagAlertHandler
+	webhooks        []webhook
+	stuckRunAlert   time.Duration
+
+	childCancelPolicy ChildCancelPolicy
+	lockStore         LockStore
+	blobStore         BlobStore
+	callbackVerifier  CallbackVerifier
+
+	skipGraphValidation bool
+	searchIndexes       []searchIndex
+
+	// consumerMiddleware holds ConsumerMiddleware of the workflow's types which are only known at Build.
+	consumerMiddleware []any
 }
 
 func defaultBuildOptions() buildOptions {
@@ -268,6 +649,9 @@ func defaultBuildOptions() buildOptions {
 		outboxConfig:   defaultOutboxConfig(),
 		defaultOptions: defaultOptions(),
 		autoPauseRetry: defaultPausedRecordsRetry(),
+
+		storeUnavailablePolicy: defaultStoreUnavailablePolicy(),
+		compatibilityPolicy:    SkipNewerVersions,
 	}
 }
 
@@ -299,6 +683,15 @@ func WithDebugMode() BuildOption {
 	}
 }
 
+// WithoutGraphValidation disables the validation of the workflow's graph when the workflow is built. By default Build
+// panics when statuses are unreachable from the starting statuses, when a cycle never reaches a terminal status, or
+// when a terminal status has a step, callback, timeout, or timer.
+func WithoutGraphValidation() BuildOption {
+	return func(bo *buildOptions) {
+		bo.skipGraphValidation = true
+	}
+}
+
 // WithLogger allows for specifying a custom logger. The default is to use a wrapped version of log/slog's Logger.
 func WithLogger(l Logger) BuildOption {
 	return func(bo *buildOptions) {
@@ -323,9 +716,9 @@ func WithDefaultOptions(opts ...Option) BuildOption {
 // RunStateDataDeleted.
 func WithCustomDelete[Type any](fn func(object *Type) error) BuildOption {
 	return func(bo *buildOptions) {
-		bo.customDelete = func(wr *Record) ([]byte, error) {
+		bo.customDelete = func(codec Codec, wr *Record) ([]byte, error) {
 			var t Type
-			err := Unmarshal(wr.Object, &t)
+			err := codec.Unmarshal(wr.Object, &t)
 			if err != nil {
 				return nil, err
 			}
@@ -335,7 +728,7 @@ func WithCustomDelete[Type any](fn func(object *Type) error) BuildOption {
 				return nil, err
 			}
 
-			return Marshal(&t)
+			return codec.Marshal(&t)
 		}
 	}
 }
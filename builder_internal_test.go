@@ -99,6 +99,19 @@ func TestWithParallelCount(t *testing.T) {
 	require.Equal(t, int(100), wf.consumers[statusStart].parallelCount)
 }
 
+func TestOnStatusesWithOptions(t *testing.T) {
+	b := NewBuilder[string, testStatus]("status sets")
+	b.OnStatuses(statusStart, statusMiddle).WithOptions(SLA(time.Minute), ErrBackOff(time.Hour))
+	b.AddStep(statusStart, nil, statusMiddle).WithOptions(ErrBackOff(time.Second))
+	b.AddStep(statusMiddle, nil, statusEnd)
+	wf := b.Build(nil, nil, nil)
+
+	require.Equal(t, time.Minute, wf.consumers[statusStart].sla)
+	require.Equal(t, time.Second, wf.consumers[statusStart].errBackOff)
+	require.Equal(t, time.Minute, wf.consumers[statusMiddle].sla)
+	require.Equal(t, time.Hour, wf.consumers[statusMiddle].errBackOff)
+}
+
 func TestWithClock(t *testing.T) {
 	now := time.Now()
 	clock := clock_testing.NewFakeClock(now)
@@ -156,7 +169,7 @@ func TestBuildOptions(t *testing.T) {
 	require.Equal(t, logger, w.logger.inner)
 	require.Equal(t, opts, w.defaultOpts)
 	require.True(t, strings.Contains(runtime.FuncForPC(reflect.ValueOf(w.customDelete).Pointer()).Name(), "github.com/luno/workflow.TestBuildOptions.WithCustomDelete"))
-	object, err := w.customDelete(&Record{
+	object, err := w.customDelete(JSONCodec{}, &Record{
 		Object: []byte(`"hello world"`),
 	})
 	require.NoError(t, err)
@@ -359,6 +372,20 @@ func TestAddStepSingleUseValidation(t *testing.T) {
 				statusEnd,
 			)
 		}, "Adding duplicate step should panic")
+
+	require.PanicsWithValue(t,
+		fmt.Sprintf("'AddBatchStep(%v,' already exists. Only one Step can be configured to consume the status", statusStart.String()),
+		func() {
+			b.AddBatchStep(
+				statusStart,
+				func(ctx context.Context, runs []*Run[string, testStatus]) (BatchResult[testStatus], error) {
+					return nil, nil
+				},
+				10,
+				time.Second,
+				statusEnd,
+			)
+		}, "Adding duplicate batch step should panic")
 }
 
 func TestConfigureTimeoutWithoutTimeoutStore(t *testing.T) {
@@ -0,0 +1,160 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+const defaultBulkRatePerSecond = 50
+
+// BulkResult is the outcome of a bulk operation.
+type BulkResult struct {
+	// Updated is the number of runs whose run state was changed.
+	Updated int64
+	// Skipped is the number of runs that were no longer in a run state that could be changed by the time they were
+	// updated, such as runs that completed during the bulk operation.
+	Skipped int64
+	// Failed is the number of runs that could not be updated. The errors are logged.
+	Failed int64
+}
+
+type bulkOpts struct {
+	perSecond float64
+	reason    string
+}
+
+type BulkOption func(o *bulkOpts)
+
+// WithBulkRateLimit sets the maximum number of runs that are updated per second so that a bulk operation doesn't
+// overwhelm the RecordStore, or the consumers of the workflow's events, such as its hooks. The default is 50 runs per
+// second.
+func WithBulkRateLimit(perSecond float64) BulkOption {
+	return func(o *bulkOpts) {
+		o.perSecond = perSecond
+	}
+}
+
+// WithBulkReason sets the reason that is recorded in the history of each of the updated runs.
+func WithBulkReason(reason string) BulkOption {
+	return func(o *bulkOpts) {
+		o.reason = reason
+	}
+}
+
+// BulkPause pauses all the initiated and running runs that match the filter, such as the runs that are stuck in a bad
+// status during an incident. See PauseRun.
+func (w *Workflow[Type, Status]) BulkPause(
+	ctx context.Context,
+	filter RunFilter[Status],
+	opts ...BulkOption,
+) (*BulkResult, error) {
+	return w.bulkUpdateRunState(ctx, filter, RunStatePaused, []RunState{RunStateInitiated, RunStateRunning}, opts...)
+}
+
+// BulkResume resumes all the paused runs that match the filter. See ResumeRun.
+func (w *Workflow[Type, Status]) BulkResume(
+	ctx context.Context,
+	filter RunFilter[Status],
+	opts ...BulkOption,
+) (*BulkResult, error) {
+	return w.bulkUpdateRunState(ctx, filter, RunStateRunning, []RunState{RunStatePaused}, opts...)
+}
+
+// BulkCancel cancels all the runs that match the filter and have not finished. See CancelRun.
+func (w *Workflow[Type, Status]) BulkCancel(
+	ctx context.Context,
+	filter RunFilter[Status],
+	opts ...BulkOption,
+) (*BulkResult, error) {
+	return w.bulkUpdateRunState(
+		ctx,
+		filter,
+		RunStateCancelled,
+		[]RunState{RunStateInitiated, RunStateRunning, RunStatePaused, RunStateQuarantined},
+		opts...,
+	)
+}
+
+// bulkUpdateRunState moves the runs that match the filter, and are in one of the from run states, into the run state.
+// Runs created after the bulk operation started are not included. The filter's Limit is the number of runs updated
+// per page and the filter's Offset and Order are ignored.
+func (w *Workflow[Type, Status]) bulkUpdateRunState(
+	ctx context.Context,
+	filter RunFilter[Status],
+	to RunState,
+	from []RunState,
+	opts ...BulkOption,
+) (*BulkResult, error) {
+	o := bulkOpts{
+		perSecond: defaultBulkRatePerSecond,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.perSecond <= 0 {
+		return nil, errors.New("bulk update: rate limit requires a positive rate")
+	}
+
+	// Updated runs are no longer in one of the from run states and so they drop out of the filter which allows the
+	// remaining runs to be listed from the start of the filter.
+	if len(filter.RunStates) > 0 {
+		from = slices.DeleteFunc(slices.Clone(from), func(rs RunState) bool {
+			return !slices.Contains(filter.RunStates, rs)
+		})
+
+		if len(from) == 0 {
+			return &BulkResult{}, nil
+		}
+	}
+
+	filter.RunStates = from
+	filter.Order = OrderTypeAscending
+	if filter.CreatedTo.IsZero() || filter.CreatedTo.After(w.clock.Now()) {
+		filter.CreatedTo = w.clock.Now()
+	}
+
+	limiter := newRateLimiter(rateLimit{perSecond: o.perSecond, burst: 1}, w.clock)
+
+	var result BulkResult
+	for {
+		// Runs that failed to be updated still match the filter and are skipped over whereas the skipped runs are no
+		// longer in one of the from run states.
+		filter.Offset = result.Failed
+		records, err := w.listRecords(ctx, filter)
+		if err != nil {
+			return &result, fmt.Errorf("bulk update: %w, meta: %v", err, map[string]string{
+				"offset": fmt.Sprint(filter.Offset),
+			})
+		}
+
+		if len(records) == 0 {
+			return &result, nil
+		}
+
+		for _, record := range records {
+			err := limiter.wait(ctx)
+			if err != nil {
+				return &result, err
+			}
+
+			err = w.updateRunState(ctx, record.RunID, to, o.reason)
+			switch {
+			case errors.Is(err, ErrInvalidTransition):
+				result.Skipped++
+			case err != nil:
+				result.Failed++
+				w.logger.Error(ctx, fmt.Errorf("bulk update run: %w, meta: %v", err, map[string]string{
+					"workflow_name": w.Name(),
+					"run_id":        record.RunID,
+					"run_state":     to.String(),
+				}))
+			default:
+				result.Updated++
+			}
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package workflow_test
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestBulkOperations(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("bulk")
+	b.AddCallback(StatusStart, func(ctx context.Context, r *workflow.Run[string, status], reader io.Reader) (status, error) {
+		return StatusMiddle, nil
+	}, StatusMiddle)
+	b.AddCallback(StatusMiddle, func(ctx context.Context, r *workflow.Run[string, status], reader io.Reader) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	recordStore := memrecordstore.New()
+	wf := b.Build(memstreamer.New(), recordStore, memrolescheduler.New())
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	var stuck []string
+	for i := range 7 {
+		runID, err := wf.Trigger(ctx, strconv.Itoa(i), StatusStart)
+		require.Nil(t, err)
+
+		// Only the runs with an even foreign ID are stuck in StatusStart.
+		if i%2 == 1 {
+			err := wf.Callback(ctx, strconv.Itoa(i), StatusStart, nil)
+			require.Nil(t, err)
+			continue
+		}
+
+		stuck = append(stuck, runID)
+	}
+
+	filter := workflow.RunFilter[status]{
+		Statuses: []status{StatusStart},
+		Limit:    2,
+	}
+
+	requireRunStates := func(runState workflow.RunState) {
+		for _, runID := range stuck {
+			record, err := recordStore.Lookup(ctx, runID)
+			require.Nil(t, err)
+			require.Equal(t, runState, record.RunState)
+		}
+	}
+
+	res, err := wf.BulkPause(ctx, filter, workflow.WithBulkRateLimit(1000))
+	require.Nil(t, err)
+	require.Equal(t, &workflow.BulkResult{Updated: 4}, res)
+	requireRunStates(workflow.RunStatePaused)
+
+	res, err = wf.BulkResume(ctx, filter, workflow.WithBulkRateLimit(1000))
+	require.Nil(t, err)
+	require.Equal(t, &workflow.BulkResult{Updated: 4}, res)
+	requireRunStates(workflow.RunStateRunning)
+
+	res, err = wf.BulkCancel(ctx, filter, workflow.WithBulkRateLimit(1000), workflow.WithBulkReason("incident"))
+	require.Nil(t, err)
+	require.Equal(t, &workflow.BulkResult{Updated: 4}, res)
+	requireRunStates(workflow.RunStateCancelled)
+
+	history, err := wf.RunHistory(ctx, stuck[0])
+	require.Nil(t, err)
+	require.Equal(t, "incident", history[len(history)-1].Reason)
+
+	// Runs in the other statuses were left alone.
+	record, err := recordStore.Latest(ctx, wf.Name(), "1")
+	require.Nil(t, err)
+	require.Equal(t, int(StatusMiddle), record.Status)
+	require.Equal(t, workflow.RunStateRunning, record.RunState)
+}
@@ -3,11 +3,16 @@ package workflow
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"io"
+	"slices"
 )
 
 type callback[Type any, Status StatusType] struct {
 	CallbackFunc CallbackFunc[Type, Status]
+	// blob is true for callbacks added using AddBlobCallback which require a BlobStore.
+	blob bool
 }
 
 type CallbackFunc[Type any, Status StatusType] func(ctx context.Context, r *Run[Type, Status], reader io.Reader) (Status, error)
@@ -18,9 +23,13 @@ func (w *Workflow[Type, Status]) Callback(
 	status Status,
 	payload io.Reader,
 ) error {
-	updateFn := newUpdater[Type, Status](w.recordStore.Lookup, w.recordStore.Store, w.statusGraph, w.clock)
+	if w.runMode == RunModeConsumer {
+		return fmt.Errorf("callback failed: %w", ErrConsumerRunMode)
+	}
+
+	updateFn := newUpdater[Type, Status](w.recordStore.Lookup, w.recordStore.Store, w.codec, w.statusGraph, w.clock)
 
-	for _, s := range w.callback[status] {
+	for i, s := range w.callback[status] {
 		err := processCallback(
 			ctx,
 			w,
@@ -32,7 +41,10 @@ func (w *Workflow[Type, Status]) Callback(
 			w.recordStore.Store,
 			updateFn,
 		)
-		if err != nil {
+		if i > 0 && errors.Is(err, ErrStatusMismatch) {
+			// The run has been moved on by one of the earlier callbacks of the status.
+			return nil
+		} else if err != nil {
 			return err
 		}
 	}
@@ -40,6 +52,50 @@ func (w *Workflow[Type, Status]) Callback(
 	return nil
 }
 
+// ErrStatusMismatch is returned by Callback, wrapped in a *StatusMismatchError, when the run of the foreign ID is not
+// in the status of the callback, such as when the run has already been moved on by an earlier callback.
+var ErrStatusMismatch = errors.New("run not in callback status")
+
+// StatusMismatchError is returned by Callback when the run is not in the status of the callback. It wraps
+// ErrStatusMismatch and provides the run's current status and run state so that callers can decide whether to retry
+// or ignore the callback.
+type StatusMismatchError struct {
+	WorkflowName string
+	ForeignID    string
+	RunID        string
+	// Status is the status of the callback. It is zero for the callbacks called using NamedCallback.
+	Status int
+	// Callback is the name of the callback called using NamedCallback.
+	Callback      string
+	CurrentStatus int
+	RunState      RunState
+
+	statusName        string
+	currentStatusName string
+}
+
+func (e *StatusMismatchError) Error() string {
+	meta := map[string]string{
+		"workflow_name":  e.WorkflowName,
+		"foreign_id":     e.ForeignID,
+		"run_id":         e.RunID,
+		"current_status": e.currentStatusName,
+		"run_state":      e.RunState.String(),
+	}
+
+	if e.Callback != "" {
+		meta["callback"] = e.Callback
+	} else {
+		meta["status"] = e.statusName
+	}
+
+	return fmt.Sprintf("callback failed: %v, meta: %v", ErrStatusMismatch, meta)
+}
+
+func (e *StatusMismatchError) Unwrap() error {
+	return ErrStatusMismatch
+}
+
 type latestLookup func(ctx context.Context, workflowName, foreignID string) (*Record, error)
 
 func processCallback[Type any, Status StatusType](
@@ -59,11 +115,24 @@ func processCallback[Type any, Status StatusType](
 	}
 
 	if Status(wr.Status) != currentStatus {
-		// Latest record shows that the current status is in a different State than expected so skip.
-		return nil
+		return &StatusMismatchError{
+			WorkflowName:      w.Name(),
+			ForeignID:         foreignID,
+			RunID:             wr.RunID,
+			Status:            int(currentStatus),
+			CurrentStatus:     wr.Status,
+			RunState:          wr.RunState,
+			statusName:        currentStatus.String(),
+			currentStatusName: Status(wr.Status).String(),
+		}
 	}
 
-	run, err := buildRun[Type, Status](store, wr)
+	if !isCompatible(w.compatibilityPolicy, w.version, wr.Meta.Version) {
+		// The run must be called back using the version of the workflow that is able to process it.
+		return ErrRunVersionMismatch
+	}
+
+	run, err := buildRun[Type, Status](store, w.codec, wr)
 	if err != nil {
 		return err
 	}
@@ -74,19 +143,26 @@ func processCallback[Type any, Status StatusType](
 		payload = bytes.NewReader([]byte{})
 	}
 
+	ctx, span := w.startRunSpan(ctx, &run.Record, "callback")
 	next, err := fn(ctx, run, payload)
+	w.endRunSpan(span, next, err)
 	if err != nil {
 		return err
 	}
 
 	if skipUpdate(next) {
-		w.logger.Debug(ctx, "skipping update", map[string]string{
-			"description":   skipUpdateDescription(next),
-			"workflow_name": w.Name(),
-			"foreign_id":    run.ForeignID,
-			"run_id":        run.RunID,
-			"run_state":     run.RunState.String(),
-			"record_status": run.Status.String(),
+		w.logger.event(ctx, DebugEvent{
+			Type:    DebugEventSkipped,
+			Message: "skipping update",
+			Meta: map[string]string{
+				"description":   skipUpdateDescription(next),
+				"workflow_name": w.Name(),
+				"foreign_id":    run.ForeignID,
+				"run_id":        run.RunID,
+				"run_state":     run.RunState.String(),
+				"record_status": run.Status.String(),
+				"reason":        "next value specified skip",
+			},
 		})
 
 		return nil
@@ -94,3 +170,93 @@ func processCallback[Type any, Status StatusType](
 
 	return updater(ctx, currentStatus, next, run)
 }
+
+type namedCallback[Type any, Status StatusType] struct {
+	statuses []Status
+	fn       CallbackFunc[Type, Status]
+}
+
+// AddMultiStatusCallback adds a callback, identified by its name, that can be called whilst the run is in any of the
+// statuses, such as a document that can be uploaded during any of several review stages. NamedCallback resolves the
+// run's current status so that callers don't need to know it and fn can use the status of the run to decide which of
+// the allowed destinations to move the run to. The callback is also called by Callback for each of the statuses.
+func (b *Builder[Type, Status]) AddMultiStatusCallback(
+	name string,
+	from []Status,
+	fn CallbackFunc[Type, Status],
+	allowedDestinations ...Status,
+) {
+	if name == "" {
+		panic("'AddMultiStatusCallback(' callbacks need to be named")
+	}
+
+	if _, ok := b.workflow.namedCallbacks[name]; ok {
+		panic("'AddMultiStatusCallback(" + name + ",' callback names need to be unique")
+	}
+
+	if len(from) == 0 {
+		panic("'AddMultiStatusCallback(" + name + ",' requires at least one status")
+	}
+
+	for _, status := range from {
+		b.AddCallback(status, fn, allowedDestinations...)
+	}
+
+	b.workflow.namedCallbacks[name] = namedCallback[Type, Status]{
+		statuses: slices.Clone(from),
+		fn:       fn,
+	}
+}
+
+// NamedCallback calls the callback added using AddMultiStatusCallback with the name using the current status of the
+// run of the foreign ID. A *StatusMismatchError is returned, as with Callback, when the run is not in any of the
+// callback's statuses.
+func (w *Workflow[Type, Status]) NamedCallback(
+	ctx context.Context,
+	foreignID string,
+	name string,
+	payload io.Reader,
+) error {
+	if w.runMode == RunModeConsumer {
+		return fmt.Errorf("named callback: %w", ErrConsumerRunMode)
+	}
+
+	nc, ok := w.namedCallbacks[name]
+	if !ok {
+		return fmt.Errorf("named callback: callback not found, meta: %v", map[string]string{
+			"workflow_name": w.Name(),
+			"name":          name,
+		})
+	}
+
+	wr, err := w.recordStore.Latest(ctx, w.Name(), foreignID)
+	if err != nil {
+		return err
+	}
+
+	status := Status(wr.Status)
+	if !slices.Contains(nc.statuses, status) {
+		return &StatusMismatchError{
+			WorkflowName:      w.Name(),
+			ForeignID:         foreignID,
+			RunID:             wr.RunID,
+			Callback:          name,
+			CurrentStatus:     wr.Status,
+			RunState:          wr.RunState,
+			currentStatusName: status.String(),
+		}
+	}
+
+	updateFn := newUpdater[Type, Status](w.recordStore.Lookup, w.recordStore.Store, w.codec, w.statusGraph, w.clock)
+	return processCallback(
+		ctx,
+		w,
+		status,
+		nc.fn,
+		foreignID,
+		payload,
+		w.recordStore.Latest,
+		w.recordStore.Store,
+		updateFn,
+	)
+}
@@ -20,6 +20,7 @@ func TestProcessCallback(t *testing.T) {
 		ctx:         ctx,
 		clock:       clock_testing.NewFakeClock(time.Date(2024, time.April, 19, 0, 0, 0, 0, time.UTC)),
 		statusGraph: graph.New(),
+		codec:       JSONCodec{},
 		logger:      &logger{},
 	}
 
@@ -188,9 +189,11 @@ func TestProcessCallback(t *testing.T) {
 		require.Equal(t, errors.New("test error"), err)
 	})
 
-	t.Run("Ignore if record is in different state", func(t *testing.T) {
+	t.Run("Return status mismatch if record is in different state", func(t *testing.T) {
 		currentRecord := &Record{
-			Status: int(statusMiddle),
+			RunID:    "JHFJDS-LSFKHJSLD-KSJDBLSL",
+			RunState: RunStatePaused,
+			Status:   int(statusMiddle),
 		}
 
 		latestLookup := func(ctx context.Context, workflowName, foreignID string) (*Record, error) {
@@ -198,6 +201,13 @@ func TestProcessCallback(t *testing.T) {
 		}
 
 		err := processCallback(ctx, w, statusStart, nil, current.ForeignID, nil, latestLookup, nil, nil)
-		require.Nil(t, err)
+		require.ErrorIs(t, err, ErrStatusMismatch)
+
+		var mismatch *StatusMismatchError
+		require.True(t, errors.As(err, &mismatch))
+		require.Equal(t, "JHFJDS-LSFKHJSLD-KSJDBLSL", mismatch.RunID)
+		require.Equal(t, int(statusStart), mismatch.Status)
+		require.Equal(t, int(statusMiddle), mismatch.CurrentStatus)
+		require.Equal(t, RunStatePaused, mismatch.RunState)
 	})
 }
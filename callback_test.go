@@ -0,0 +1,123 @@
+package workflow_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestAddMultiStatusCallback(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("multi status callback")
+	b.AddMultiStatusCallback(
+		"document uploaded",
+		[]status{StatusStart, StatusMiddle},
+		func(ctx context.Context, r *workflow.Run[string, status], reader io.Reader) (status, error) {
+			document, err := io.ReadAll(reader)
+			if err != nil {
+				return 0, err
+			}
+
+			*r.Object += string(document)
+			if r.Status == StatusStart {
+				return StatusMiddle, nil
+			}
+
+			return StatusEnd, nil
+		},
+		StatusMiddle,
+		StatusEnd,
+	)
+
+	recordStore := memrecordstore.New()
+	wf := b.Build(memstreamer.New(), recordStore, memrolescheduler.New())
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "foreignID", StatusStart)
+	require.Nil(t, err)
+
+	err = wf.NamedCallback(ctx, "foreignID", "document uploaded", strings.NewReader("a"))
+	require.Nil(t, err)
+
+	_, err = wf.Await(ctx, "foreignID", runID, StatusMiddle)
+	require.Nil(t, err)
+
+	err = wf.NamedCallback(ctx, "foreignID", "document uploaded", strings.NewReader("b"))
+	require.Nil(t, err)
+
+	run, err := wf.Await(ctx, "foreignID", runID, StatusEnd)
+	require.Nil(t, err)
+	require.Equal(t, "ab", *run.Object)
+
+	// The run is no longer in one of the callback's statuses.
+	err = wf.NamedCallback(ctx, "foreignID", "document uploaded", strings.NewReader("c"))
+	var mismatch *workflow.StatusMismatchError
+	require.True(t, errors.As(err, &mismatch))
+	require.Equal(t, "document uploaded", mismatch.Callback)
+	require.Equal(t, int(StatusEnd), mismatch.CurrentStatus)
+	require.Equal(t, workflow.RunStateCompleted, mismatch.RunState)
+
+	record, err := recordStore.Lookup(ctx, runID)
+	require.Nil(t, err)
+	require.Equal(t, int(StatusEnd), record.Status)
+
+	err = wf.NamedCallback(ctx, "foreignID", "unknown", strings.NewReader("c"))
+	require.ErrorContains(t, err, "callback not found")
+}
+
+func TestAddMultiStatusCallbackValidation(t *testing.T) {
+	fn := func(ctx context.Context, r *workflow.Run[string, status], reader io.Reader) (status, error) {
+		return StatusEnd, nil
+	}
+
+	require.PanicsWithValue(t, "'AddMultiStatusCallback(document uploaded,' requires at least one status", func() {
+		b := workflow.NewBuilder[string, status]("multi status callback")
+		b.AddMultiStatusCallback("document uploaded", nil, fn, StatusEnd)
+	})
+
+	require.PanicsWithValue(t, "'AddMultiStatusCallback(document uploaded,' callback names need to be unique", func() {
+		b := workflow.NewBuilder[string, status]("multi status callback")
+		b.AddMultiStatusCallback("document uploaded", []status{StatusStart}, fn, StatusEnd)
+		b.AddMultiStatusCallback("document uploaded", []status{StatusMiddle}, fn, StatusEnd)
+	})
+}
+
+func TestCallbackStatusMismatch(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("callback status mismatch")
+	b.AddCallback(StatusStart, func(ctx context.Context, r *workflow.Run[string, status], reader io.Reader) (status, error) {
+		return StatusMiddle, nil
+	}, StatusMiddle)
+	b.AddCallback(StatusMiddle, func(ctx context.Context, r *workflow.Run[string, status], reader io.Reader) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	wf := b.Build(memstreamer.New(), memrecordstore.New(), memrolescheduler.New())
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "foreignID", StatusStart)
+	require.Nil(t, err)
+
+	err = wf.Callback(ctx, "foreignID", StatusMiddle, nil)
+	require.ErrorIs(t, err, workflow.ErrStatusMismatch)
+
+	var mismatch *workflow.StatusMismatchError
+	require.True(t, errors.As(err, &mismatch))
+	require.Equal(t, runID, mismatch.RunID)
+	require.Equal(t, int(StatusMiddle), mismatch.Status)
+	require.Equal(t, int(StatusStart), mismatch.CurrentStatus)
+	require.Equal(t, workflow.RunStateInitiated, mismatch.RunState)
+}
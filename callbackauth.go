@@ -0,0 +1,227 @@
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+const defaultTimestampWindow = 5 * time.Minute
+
+// ErrCallbackUnauthorized is returned by VerifiedCallback when the callback fails verification.
+var ErrCallbackUnauthorized = errors.New("callback unauthorized")
+
+// CallbackAuth is the authentication provided by the sender of a callback, such as the values of the signature,
+// timestamp, and nonce headers of a webhook request.
+type CallbackAuth struct {
+	Signature string
+	Timestamp time.Time
+	Nonce     string
+}
+
+// CallbackRequest is the callback that is provided to a CallbackVerifier.
+type CallbackRequest struct {
+	WorkflowName string
+	ForeignID    string
+	Status       int
+	Payload      []byte
+	Auth         CallbackAuth
+	// ReceivedAt is the time, according to the workflow's clock, that the callback was received.
+	ReceivedAt time.Time
+}
+
+// CallbackVerifier returns an error that wraps ErrCallbackUnauthorized when the callback should be rejected. Any other
+// error, such as the NonceStore being unavailable, is returned as is.
+type CallbackVerifier func(ctx context.Context, req CallbackRequest) error
+
+// WithCallbackVerifier configures the verification of callbacks that are received using VerifiedCallback, such as
+// those received from publicly reachable webhook endpoints. See NewHMACVerifier.
+func WithCallbackVerifier(v CallbackVerifier) BuildOption {
+	return func(bo *buildOptions) {
+		bo.callbackVerifier = v
+	}
+}
+
+// VerifiedCallback verifies the callback using the CallbackVerifier configured with WithCallbackVerifier before
+// calling Callback. The payload is read into memory so that it can be verified. Callbacks that fail verification
+// return an error that wraps ErrCallbackUnauthorized and the run is not called back. Callback does not verify
+// callbacks and so should only be used by trusted callers.
+func (w *Workflow[Type, Status]) VerifiedCallback(
+	ctx context.Context,
+	foreignID string,
+	status Status,
+	payload io.Reader,
+	auth CallbackAuth,
+) error {
+	if w.callbackVerifier == nil {
+		return errors.New("verified callback: no CallbackVerifier configured, use WithCallbackVerifier to configure one")
+	}
+
+	var b []byte
+	if payload != nil {
+		var err error
+		b, err = io.ReadAll(payload)
+		if err != nil {
+			return err
+		}
+	}
+
+	err := w.callbackVerifier(ctx, CallbackRequest{
+		WorkflowName: w.Name(),
+		ForeignID:    foreignID,
+		Status:       int(status),
+		Payload:      b,
+		Auth:         auth,
+		ReceivedAt:   w.clock.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("verify callback: %w, meta: %v", err, map[string]string{
+			"workflow_name": w.Name(),
+			"foreign_id":    foreignID,
+			"status":        status.String(),
+		})
+	}
+
+	return w.Callback(ctx, foreignID, status, bytes.NewReader(b))
+}
+
+// NonceStore implementations should all be tested with adaptertest.RunNonceStoreTest. The NonceStore is used to
+// reject callbacks that are replayed within the timestamp window.
+type NonceStore interface {
+	// Claim records the nonce and returns false if the nonce has already been claimed. The nonce only needs to be
+	// kept until expireAt.
+	Claim(ctx context.Context, nonce string, expireAt time.Time) (bool, error)
+}
+
+type hmacVerifierOpts struct {
+	timestampWindow time.Duration
+	nonceStore      NonceStore
+}
+
+type HMACVerifierOption func(o *hmacVerifierOpts)
+
+// WithTimestampWindow sets how far the callback's timestamp may be from the time it is received. The default is 5
+// minutes.
+func WithTimestampWindow(d time.Duration) HMACVerifierOption {
+	return func(o *hmacVerifierOpts) {
+		o.timestampWindow = d
+	}
+}
+
+// WithNonceStore rejects callbacks whose nonce has already been claimed in the NonceStore. Callbacks are required to
+// have a nonce when a NonceStore is configured.
+func WithNonceStore(s NonceStore) HMACVerifierOption {
+	return func(o *hmacVerifierOpts) {
+		o.nonceStore = s
+	}
+}
+
+// NewHMACVerifier returns a CallbackVerifier that requires callbacks to be signed with the secret, see SignCallback,
+// and to have a timestamp within the timestamp window. When a NonceStore is configured each nonce is only accepted
+// once.
+func NewHMACVerifier(secret []byte, opts ...HMACVerifierOption) CallbackVerifier {
+	o := hmacVerifierOpts{
+		timestampWindow: defaultTimestampWindow,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return func(ctx context.Context, req CallbackRequest) error {
+		if req.Auth.Timestamp.IsZero() {
+			return fmt.Errorf("%w: missing timestamp", ErrCallbackUnauthorized)
+		}
+
+		skew := req.ReceivedAt.Sub(req.Auth.Timestamp).Abs()
+		if skew > o.timestampWindow {
+			return fmt.Errorf("%w: timestamp outside of window", ErrCallbackUnauthorized)
+		}
+
+		if o.nonceStore != nil && req.Auth.Nonce == "" {
+			return fmt.Errorf("%w: missing nonce", ErrCallbackUnauthorized)
+		}
+
+		signature, err := hex.DecodeString(req.Auth.Signature)
+		if err != nil {
+			return fmt.Errorf("%w: invalid signature", ErrCallbackUnauthorized)
+		}
+
+		expected := callbackMAC(
+			secret,
+			req.WorkflowName,
+			req.ForeignID,
+			req.Status,
+			req.Payload,
+			req.Auth.Timestamp,
+			req.Auth.Nonce,
+		)
+		if !hmac.Equal(signature, expected) {
+			return fmt.Errorf("%w: invalid signature", ErrCallbackUnauthorized)
+		}
+
+		// The nonce is only claimed once the callback is known to be signed so that unsigned callbacks cannot use up
+		// the nonces of legitimate callbacks.
+		if o.nonceStore != nil {
+			claimed, err := o.nonceStore.Claim(ctx, req.Auth.Nonce, req.Auth.Timestamp.Add(o.timestampWindow))
+			if err != nil {
+				return err
+			}
+
+			if !claimed {
+				return fmt.Errorf("%w: nonce already used", ErrCallbackUnauthorized)
+			}
+		}
+
+		return nil
+	}
+}
+
+// SignCallback returns the hex encoded HMAC-SHA256 signature of the callback that is expected by NewHMACVerifier. The
+// signature is computed over the workflow name, the foreign ID, the status, the timestamp in unix seconds, the nonce,
+// and the payload, each of which is preceded by its length, so that a signed callback cannot be replayed against
+// another run or status, or have its fields reinterpreted as a different nonce and payload.
+func SignCallback(
+	secret []byte,
+	workflowName string,
+	foreignID string,
+	status int,
+	payload []byte,
+	timestamp time.Time,
+	nonce string,
+) string {
+	return hex.EncodeToString(callbackMAC(secret, workflowName, foreignID, status, payload, timestamp, nonce))
+}
+
+func callbackMAC(
+	secret []byte,
+	workflowName string,
+	foreignID string,
+	status int,
+	payload []byte,
+	timestamp time.Time,
+	nonce string,
+) []byte {
+	mac := hmac.New(sha256.New, secret)
+	for _, field := range [][]byte{
+		[]byte(workflowName),
+		[]byte(foreignID),
+		[]byte(strconv.Itoa(status)),
+		[]byte(strconv.FormatInt(timestamp.Unix(), 10)),
+		[]byte(nonce),
+		payload,
+	} {
+		mac.Write(binary.AppendUvarint(nil, uint64(len(field))))
+		mac.Write(field)
+	}
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,162 @@
+package workflow_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	clock_testing "k8s.io/utils/clock/testing"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memnoncestore"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestVerifiedCallback(t *testing.T) {
+	secret := []byte("secret")
+	now := time.Date(2024, time.April, 19, 23, 0, 0, 0, time.UTC)
+	clock := clock_testing.NewFakeClock(now)
+
+	b := workflow.NewBuilder[string, status]("verified callback")
+	b.AddCallback(StatusStart, func(ctx context.Context, r *workflow.Run[string, status], reader io.Reader) (status, error) {
+		payload, err := io.ReadAll(reader)
+		if err != nil {
+			return 0, err
+		}
+
+		*r.Object = string(payload)
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+		workflow.WithClock(clock),
+		workflow.WithCallbackVerifier(workflow.NewHMACVerifier(
+			secret,
+			workflow.WithTimestampWindow(time.Minute),
+			workflow.WithNonceStore(memnoncestore.New(memnoncestore.WithClock(clock))),
+		)),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "foreignID", StatusStart)
+	require.Nil(t, err)
+
+	payload := []byte("payload")
+	signed := func(timestamp time.Time, nonce string) workflow.CallbackAuth {
+		return workflow.CallbackAuth{
+			Signature: workflow.SignCallback(secret, wf.Name(), "foreignID", int(StatusStart), payload, timestamp, nonce),
+			Timestamp: timestamp,
+			Nonce:     nonce,
+		}
+	}
+
+	testCases := []struct {
+		name    string
+		payload string
+		auth    workflow.CallbackAuth
+	}{
+		{
+			name:    "Wrong secret",
+			payload: string(payload),
+			auth: workflow.CallbackAuth{
+				Signature: workflow.SignCallback([]byte("wrong"), wf.Name(), "foreignID", int(StatusStart), payload, now, "nonce-1"),
+				Timestamp: now,
+				Nonce:     "nonce-1",
+			},
+		},
+		{
+			name:    "Tampered payload",
+			payload: "tampered",
+			auth:    signed(now, "nonce-1"),
+		},
+		{
+			name:    "Timestamp outside of window",
+			payload: string(payload),
+			auth:    signed(now.Add(-2*time.Minute), "nonce-1"),
+		},
+		{
+			name:    "Missing nonce",
+			payload: string(payload),
+			auth:    signed(now, ""),
+		},
+		{
+			name:    "Signed for another run",
+			payload: string(payload),
+			auth: workflow.CallbackAuth{
+				Signature: workflow.SignCallback(secret, wf.Name(), "otherForeignID", int(StatusStart), payload, now, "nonce-1"),
+				Timestamp: now,
+				Nonce:     "nonce-1",
+			},
+		},
+		{
+			name:    "Signed for another status",
+			payload: string(payload),
+			auth: workflow.CallbackAuth{
+				Signature: workflow.SignCallback(secret, wf.Name(), "foreignID", int(StatusMiddle), payload, now, "nonce-1"),
+				Timestamp: now,
+				Nonce:     "nonce-1",
+			},
+		},
+		{
+			name:    "Signed for another workflow",
+			payload: string(payload),
+			auth: workflow.CallbackAuth{
+				Signature: workflow.SignCallback(secret, "other workflow", "foreignID", int(StatusStart), payload, now, "nonce-1"),
+				Timestamp: now,
+				Nonce:     "nonce-1",
+			},
+		},
+		{
+			// The nonce and payload of a callback signed with the nonce "nonce" and the payload ".payload" cannot be
+			// shifted to present the nonce "nonce." as a fresh nonce.
+			name:    "Boundary shifted between nonce and payload",
+			payload: string(payload),
+			auth: workflow.CallbackAuth{
+				Signature: workflow.SignCallback(secret, wf.Name(), "foreignID", int(StatusStart), []byte("."+string(payload)), now, "nonce"),
+				Timestamp: now,
+				Nonce:     "nonce.",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := wf.VerifiedCallback(ctx, "foreignID", StatusStart, strings.NewReader(tc.payload), tc.auth)
+			require.ErrorIs(t, err, workflow.ErrCallbackUnauthorized)
+		})
+	}
+
+	err = wf.VerifiedCallback(ctx, "foreignID", StatusStart, strings.NewReader(string(payload)), signed(now, "nonce-1"))
+	require.Nil(t, err)
+
+	run, err := wf.Await(ctx, "foreignID", runID, StatusEnd)
+	require.Nil(t, err)
+	require.Equal(t, string(payload), *run.Object)
+
+	// Replaying the callback is rejected as the nonce has already been used.
+	err = wf.VerifiedCallback(ctx, "foreignID", StatusStart, strings.NewReader(string(payload)), signed(now, "nonce-1"))
+	require.ErrorIs(t, err, workflow.ErrCallbackUnauthorized)
+}
+
+func TestVerifiedCallbackRequiresVerifier(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("verified callback")
+	b.AddCallback(StatusStart, func(ctx context.Context, r *workflow.Run[string, status], reader io.Reader) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	wf := b.Build(memstreamer.New(), memrecordstore.New(), memrolescheduler.New())
+
+	err := wf.VerifiedCallback(context.Background(), "foreignID", StatusStart, nil, workflow.CallbackAuth{})
+	require.ErrorContains(t, err, "no CallbackVerifier configured")
+}
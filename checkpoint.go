@@ -0,0 +1,72 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+)
+
+// Checkpoint is the progress of the step of a run's status that was saved using Run.Checkpoint.
+type Checkpoint struct {
+	// Status is the status of the step that saved the checkpoint.
+	Status int `json:"status"`
+	// Progress is the progress encoded using the workflow's Codec.
+	Progress []byte `json:"progress"`
+}
+
+// Checkpoint saves the progress of a step that processes many items, such as the index of the last row of a file that
+// has been imported, with the run in the RecordStore so that the step can resume from the checkpoint using
+// LoadCheckpoint when it is called again for the run, such as after the instance crashes or the step returns an
+// error, rather than starting again from the beginning. The progress is encoded using the workflow's Codec and
+// replaces the previous checkpoint. The checkpoint is cleared once the step moves the run onto its next status.
+//
+// Saving a checkpoint stores the run, which publishes an event for the run's current status that the step skips once
+// the run has moved on, and so checkpoints should be saved every batch of items rather than after every item. Runs
+// created using NewTestingRun keep the checkpoint in memory.
+func (r *Run[Type, Status]) Checkpoint(ctx context.Context, progress any) error {
+	b, err := r.runCodec().Marshal(progress)
+	if err != nil {
+		return fmt.Errorf("checkpoint: %w, meta: %v", err, map[string]string{
+			"run_id": r.RunID,
+		})
+	}
+
+	checkpoint := &Checkpoint{
+		Status:   int(r.Status),
+		Progress: b,
+	}
+	r.Meta.Checkpoint = checkpoint
+
+	if r.store == nil {
+		return nil
+	}
+
+	r.record.Meta.Checkpoint = checkpoint
+	err = r.store(ctx, r.record)
+	if err != nil {
+		return fmt.Errorf("store checkpoint: %w, meta: %v", err, map[string]string{
+			"run_id":     r.RunID,
+			"foreign_id": r.ForeignID,
+		})
+	}
+
+	return nil
+}
+
+// LoadCheckpoint decodes the progress saved by the latest call to Checkpoint into progress, which needs to be a
+// pointer, and returns true. False is returned, and progress is left unchanged, when the step has not saved a
+// checkpoint for the run's current status.
+func (r *Run[Type, Status]) LoadCheckpoint(progress any) (bool, error) {
+	checkpoint := r.Meta.Checkpoint
+	if checkpoint == nil || checkpoint.Status != int(r.Status) {
+		return false, nil
+	}
+
+	err := r.runCodec().Unmarshal(checkpoint.Progress, progress)
+	if err != nil {
+		return false, fmt.Errorf("load checkpoint: %w, meta: %v", err, map[string]string{
+			"run_id": r.RunID,
+		})
+	}
+
+	return true, nil
+}
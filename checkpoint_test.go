@@ -0,0 +1,101 @@
+package workflow_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+type importProgress struct {
+	Row int
+}
+
+func TestCheckpoint(t *testing.T) {
+	const rows = 10
+
+	var (
+		imported []int
+		failed   bool
+	)
+
+	b := workflow.NewBuilder[string, status]("checkpoint")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		var progress importProgress
+		_, err := r.LoadCheckpoint(&progress)
+		if err != nil {
+			return 0, err
+		}
+
+		for row := progress.Row; row < rows; row++ {
+			// The first attempt fails half way through the import.
+			if row == 5 && !failed {
+				failed = true
+				return 0, errors.New("database unavailable")
+			}
+
+			imported = append(imported, row)
+
+			err := r.Checkpoint(ctx, importProgress{Row: row + 1})
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		return StatusEnd, nil
+	}, StatusEnd).WithOptions(
+		workflow.ErrBackOff(time.Millisecond),
+	)
+
+	recordStore := memrecordstore.New()
+	wf := b.Build(memstreamer.New(), recordStore, memrolescheduler.New())
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "foreignID", StatusStart)
+	require.Nil(t, err)
+
+	run, err := wf.Await(ctx, "foreignID", runID, StatusEnd)
+	require.Nil(t, err)
+
+	// Each row is imported once as the retry resumes from the checkpoint.
+	require.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, imported)
+	require.Nil(t, run.Meta.Checkpoint)
+}
+
+func TestLoadCheckpoint(t *testing.T) {
+	r := workflow.NewTestingRun[string, status](t, workflow.Record{Status: int(StatusStart)}, "")
+	ctx := context.Background()
+
+	var progress importProgress
+	ok, err := r.LoadCheckpoint(&progress)
+	require.Nil(t, err)
+	require.False(t, ok)
+
+	err = r.Checkpoint(ctx, importProgress{Row: 42})
+	require.Nil(t, err)
+
+	ok, err = r.LoadCheckpoint(&progress)
+	require.Nil(t, err)
+	require.True(t, ok)
+	require.Equal(t, importProgress{Row: 42}, progress)
+
+	// Checkpoints saved by the step of another status are ignored.
+	other := workflow.NewTestingRun[string, status](t, workflow.Record{
+		Status: int(StatusMiddle),
+		Meta:   r.Meta,
+	}, "")
+
+	ok, err = other.LoadCheckpoint(&progress)
+	require.Nil(t, err)
+	require.False(t, ok)
+}
@@ -0,0 +1,220 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrChildRunInProgress is returned when cancelling a run whose sub-workflow's run is still in progress and the
+// workflow was built using WithChildCancelPolicy(ChildCancelBlock).
+var ErrChildRunInProgress = errors.New("child run in progress")
+
+// ChildCancelPolicy defines what happens to the in progress runs of a workflow's sub-workflows, added using
+// AddSubWorkflow or AddChildWorkflows, when the parent's run is cancelled.
+type ChildCancelPolicy int
+
+const (
+	// ChildCancelDetach leaves the child's run to continue on its own when the parent's run is cancelled and is the
+	// default.
+	ChildCancelDetach ChildCancelPolicy = 0
+	// ChildCancelCascade cancels the child's run once the parent's run has been cancelled. Children that are
+	// parents themselves cascade the cancellation further when their workflow is also configured to cascade.
+	ChildCancelCascade ChildCancelPolicy = 1
+	// ChildCancelBlock prevents the parent's run from being cancelled, by returning ErrChildRunInProgress, until the
+	// child's run has finished. A step that returns the error of r.Cancel is retried and so the cancellation takes
+	// place once the child's run has finished.
+	ChildCancelBlock ChildCancelPolicy = 2
+)
+
+func (p ChildCancelPolicy) String() string {
+	switch p {
+	case ChildCancelDetach:
+		return "Detach"
+	case ChildCancelCascade:
+		return "Cascade"
+	case ChildCancelBlock:
+		return "Block"
+	default:
+		return "Unknown"
+	}
+}
+
+// WithChildCancelPolicy defines what happens to the in progress runs of the workflow's sub-workflows when the parent's
+// run is cancelled. The policy applies to the runs of all the sub-workflows of the workflow and ChildCancelBlock is
+// only enforced for the cancellations that are stored using the workflow, such as those made using r.Cancel.
+func WithChildCancelPolicy(policy ChildCancelPolicy) BuildOption {
+	return func(bo *buildOptions) {
+		bo.childCancelPolicy = policy
+	}
+}
+
+// childRunsInProgress returns the runs of the sub-workflows that the parent's run is waiting on and that have not
+// finished.
+func childRunsInProgress[Type any, Status StatusType](
+	ctx context.Context,
+	w *Workflow[Type, Status],
+	parent *Record,
+) ([]*subWorkflowRun, error) {
+	var children []*subWorkflowRun
+	for _, sw := range w.subWorkflows {
+		if parent.Status != int(sw.from) {
+			continue
+		}
+
+		store := sw.childRecordStore()
+		if sw.join != nil {
+			runs, _, err := childRuns(ctx, store.Latest, sw.childName, parent.RunID)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, child := range runs {
+				if child.RunState.Finished() {
+					continue
+				}
+
+				children = append(children, &subWorkflowRun{
+					record: child,
+					store:  store,
+				})
+			}
+
+			continue
+		}
+
+		// The child's foreignID is the run ID of the parent's run that triggered it.
+		child, err := store.Latest(ctx, sw.childName, parent.RunID)
+		if errors.Is(err, ErrRecordNotFound) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		if child.RunState.Finished() {
+			continue
+		}
+
+		children = append(children, &subWorkflowRun{
+			record: child,
+			store:  store,
+		})
+	}
+
+	return children, nil
+}
+
+type subWorkflowRun struct {
+	record *Record
+	store  RecordStore
+}
+
+func cancelChildrenConsumer[Type any, Status StatusType](w *Workflow[Type, Status]) {
+	role := makeRole(
+		w.roleName(),
+		"cancel-children",
+		"consumer",
+	)
+
+	processName := makeRole("cancel-children", "consumer")
+	w.run(role, processName, w.hookShutdownOrder(), func(ctx context.Context) error {
+		stream, err := w.eventStreamer.NewReceiver(
+			ctx,
+			RunStateChangeTopic(w.Name()),
+			role,
+			WithReceiverPollFrequency(w.defaultOpts.pollingFrequency),
+		)
+		if err != nil {
+			return err
+		}
+		defer stream.Close()
+
+		return consume(
+			ctx,
+			w.Name(),
+			processName,
+			stream,
+			w.versionGuard(cancelChildren(w)),
+			w.clock,
+			0,
+			w.defaultOpts.lagAlert,
+			filterByRunState(RunStateCancelled),
+			w.versionFilter(),
+		)
+	}, w.defaultOpts.errBackOff)
+}
+
+func cancelChildren[Type any, Status StatusType](w *Workflow[Type, Status]) func(ctx context.Context, e *Event) error {
+	return func(ctx context.Context, e *Event) error {
+		parent, err := w.recordStore.Lookup(ctx, e.ForeignID)
+		if errors.Is(err, ErrRecordNotFound) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		children, err := childRunsInProgress(ctx, w, parent)
+		if err != nil {
+			return err
+		}
+
+		for _, child := range children {
+			// Runs that have not yet been consumed are marked as running in order to be cancelled.
+			if child.record.RunState == RunStateInitiated {
+				child.record.RunState = RunStateRunning
+			}
+
+			err := NewRunStateController(child.store.Store, child.record).Cancel(ctx)
+			if err != nil {
+				return fmt.Errorf("cancel child run: %w, meta: %v", err, map[string]string{
+					"run_id":         parent.RunID,
+					"child_run_id":   child.record.RunID,
+					"child_workflow": child.record.WorkflowName,
+				})
+			}
+
+			w.logger.event(ctx, DebugEvent{
+				Type:    DebugEventChildRunCancelled,
+				Message: "cancelled child run",
+				Meta: map[string]string{
+					"workflow_name":  w.Name(),
+					"run_id":         parent.RunID,
+					"child_run_id":   child.record.RunID,
+					"child_workflow": child.record.WorkflowName,
+				},
+			})
+		}
+
+		return nil
+	}
+}
+
+// childBlockingRecordStore wraps the RecordStore of a workflow configured with ChildCancelBlock and refuses to store
+// the cancellation of a run whose sub-workflow's run is still in progress.
+type childBlockingRecordStore[Type any, Status StatusType] struct {
+	RecordStore
+	workflow *Workflow[Type, Status]
+}
+
+func (s *childBlockingRecordStore[Type, Status]) Store(ctx context.Context, record *Record) error {
+	if record.RunState == RunStateCancelled && record.WorkflowName == s.workflow.Name() {
+		children, err := childRunsInProgress(ctx, s.workflow, record)
+		if err != nil {
+			return err
+		}
+
+		if len(children) > 0 {
+			return fmt.Errorf("cancel run: %w, meta: %v", ErrChildRunInProgress, map[string]string{
+				"run_id":         record.RunID,
+				"child_run_id":   children[0].record.RunID,
+				"child_workflow": children[0].record.WorkflowName,
+			})
+		}
+	}
+
+	return s.RecordStore.Store(ctx, record)
+}
+
+func (s *childBlockingRecordStore[Type, Status]) Unwrap() RecordStore {
+	return s.RecordStore
+}
@@ -0,0 +1,176 @@
+package workflow_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestChildCancelPolicy(t *testing.T) {
+	testCases := []struct {
+		name     string
+		policy   workflow.ChildCancelPolicy
+		expected workflow.RunState
+	}{
+		{
+			name:     "Detach - child run continues",
+			policy:   workflow.ChildCancelDetach,
+			expected: workflow.RunStateInitiated,
+		},
+		{
+			name:     "Cascade - child run is cancelled",
+			policy:   workflow.ChildCancelCascade,
+			expected: workflow.RunStateCancelled,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			streamer := memstreamer.New()
+			recordStore := memrecordstore.New()
+			roleScheduler := memrolescheduler.New()
+
+			child := buildChild(streamer, recordStore, roleScheduler)
+			parent := buildParent(child, streamer, recordStore, roleScheduler, workflow.WithChildCancelPolicy(tc.policy))
+
+			child.Run(ctx)
+			t.Cleanup(child.Stop)
+			parent.Run(ctx)
+			t.Cleanup(parent.Stop)
+
+			runID, err := parent.Trigger(ctx, "foreignID", StatusStart)
+			require.Nil(t, err)
+
+			childRun := awaitChildRunState(t, recordStore, runID, workflow.RunStateInitiated)
+
+			err = parent.Callback(ctx, "foreignID", StatusStart, strings.NewReader(""))
+			require.Nil(t, err)
+
+			if tc.expected == workflow.RunStateInitiated {
+				require.Never(t, func() bool {
+					r, err := recordStore.Lookup(ctx, childRun.RunID)
+					require.Nil(t, err)
+					return r.RunState != workflow.RunStateInitiated
+				}, 200*time.Millisecond, 10*time.Millisecond)
+				return
+			}
+
+			awaitChildRunState(t, recordStore, runID, tc.expected)
+		})
+	}
+}
+
+func TestChildCancelPolicyBlock(t *testing.T) {
+	ctx := context.Background()
+	streamer := memstreamer.New()
+	recordStore := memrecordstore.New()
+	roleScheduler := memrolescheduler.New()
+
+	child := buildChild(streamer, recordStore, roleScheduler)
+	parent := buildParent(child, streamer, recordStore, roleScheduler, workflow.WithChildCancelPolicy(workflow.ChildCancelBlock))
+
+	child.Run(ctx)
+	t.Cleanup(child.Stop)
+	parent.Run(ctx)
+	t.Cleanup(parent.Stop)
+
+	runID, err := parent.Trigger(ctx, "foreignID", StatusStart)
+	require.Nil(t, err)
+
+	awaitChildRunState(t, recordStore, runID, workflow.RunStateInitiated)
+
+	err = parent.Callback(ctx, "foreignID", StatusStart, strings.NewReader(""))
+	require.ErrorIs(t, err, workflow.ErrChildRunInProgress)
+
+	record, err := recordStore.Lookup(ctx, runID)
+	require.Nil(t, err)
+	require.Equal(t, workflow.RunStateInitiated, record.RunState)
+
+	// The parent's run can be cancelled once the child's run has finished.
+	err = child.Callback(ctx, runID, StatusStart, strings.NewReader(""))
+	require.Nil(t, err)
+
+	awaitChildRunState(t, recordStore, runID, workflow.RunStateCompleted)
+
+	err = parent.Callback(ctx, "foreignID", StatusStart, strings.NewReader(""))
+	require.Nil(t, err)
+
+	record, err = recordStore.Lookup(ctx, runID)
+	require.Nil(t, err)
+	require.Equal(t, workflow.RunStateCancelled, record.RunState)
+}
+
+func buildChild(
+	streamer workflow.EventStreamer,
+	recordStore workflow.RecordStore,
+	roleScheduler workflow.RoleScheduler,
+) *workflow.Workflow[MyType, status] {
+	// The child's run remains in progress until its callback is called.
+	b := workflow.NewBuilder[MyType, status]("child")
+	b.AddCallback(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status], reader io.Reader) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	return b.Build(streamer, recordStore, roleScheduler)
+}
+
+func buildParent(
+	child *workflow.Workflow[MyType, status],
+	streamer workflow.EventStreamer,
+	recordStore workflow.RecordStore,
+	roleScheduler workflow.RoleScheduler,
+	opts ...workflow.BuildOption,
+) *workflow.Workflow[MyType, status] {
+	b := workflow.NewBuilder[MyType, status]("parent")
+	workflow.AddSubWorkflow(
+		b,
+		StatusStart,
+		child,
+		StatusStart,
+		func(ctx context.Context, r *workflow.Run[MyType, status]) (MyType, error) {
+			return MyType{}, nil
+		},
+		func(ctx context.Context, r *workflow.Run[MyType, status], child *workflow.TypedRecord[MyType, status]) (status, error) {
+			// The parent's run waits to be cancelled.
+			return r.Skip()
+		},
+		StatusEnd,
+	)
+	b.AddCallback(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status], reader io.Reader) (status, error) {
+		return r.Cancel(ctx)
+	})
+
+	return b.Build(streamer, recordStore, roleScheduler, opts...)
+}
+
+func awaitChildRunState(
+	t *testing.T,
+	recordStore workflow.RecordStore,
+	parentRunID string,
+	runState workflow.RunState,
+) *workflow.Record {
+	var child *workflow.Record
+	require.Eventually(t, func() bool {
+		r, err := recordStore.Latest(context.Background(), "child", parentRunID)
+		if errors.Is(err, workflow.ErrRecordNotFound) {
+			return false
+		}
+		require.Nil(t, err)
+
+		child = r
+		return r.RunState == runState
+	}, 5*time.Second, 10*time.Millisecond)
+
+	return child
+}
@@ -0,0 +1,184 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"strconv"
+)
+
+const (
+	metadataParentRunID = "workflow.parent_run_id"
+	metadataChildCount  = "workflow.child_count"
+)
+
+// ChildrenTriggerFunc provides the initial values of the child workflow's runs from the parent's run. A run of the
+// child workflow is triggered for each value.
+type ChildrenTriggerFunc[Type any, Status StatusType, ChildType any] func(
+	ctx context.Context,
+	r *Run[Type, Status],
+) ([]ChildType, error)
+
+// ChildrenJoinFunc is called once all the child workflow's runs have finished. It provides the aggregate outcome of
+// the children so that it can be written onto the parent's run and returns the parent's next status.
+type ChildrenJoinFunc[Type any, Status StatusType, ChildType any, ChildStatus StatusType] func(
+	ctx context.Context,
+	r *Run[Type, Status],
+	outcome *ChildrenOutcome[ChildType, ChildStatus],
+) (Status, error)
+
+// ChildrenOutcome is the aggregate outcome of the child runs triggered using AddChildWorkflows.
+type ChildrenOutcome[ChildType any, ChildStatus StatusType] struct {
+	// Children are the finished child runs in the order that their values were returned by the ChildrenTriggerFunc.
+	Children []*TypedRecord[ChildType, ChildStatus]
+}
+
+// AllSucceeded returns true when all the child runs completed.
+func (o *ChildrenOutcome[ChildType, ChildStatus]) AllSucceeded() bool {
+	return len(o.Failed()) == 0
+}
+
+// AnyFailed returns true when any of the child runs finished without completing, such as those that were cancelled.
+func (o *ChildrenOutcome[ChildType, ChildStatus]) AnyFailed() bool {
+	return !o.AllSucceeded()
+}
+
+// Failed returns the child runs that finished without completing.
+func (o *ChildrenOutcome[ChildType, ChildStatus]) Failed() []*TypedRecord[ChildType, ChildStatus] {
+	var failed []*TypedRecord[ChildType, ChildStatus]
+	for _, child := range o.Children {
+		if child.RunState != RunStateCompleted {
+			failed = append(failed, child)
+		}
+	}
+
+	return failed
+}
+
+// AddChildWorkflows adds a step that triggers a run of the child workflow, starting at childStatus, for each of the
+// values returned by trigger when the parent's run reaches the provided status. The parent's run remains in the status
+// until all the child runs have finished, whether they completed, were cancelled, or had their data deleted, after
+// which join is called with the aggregate outcome of the children and the parent's run moves onto the status returned
+// by join. If trigger returns no values then join is called straight away.
+//
+// The child runs use the parent's run ID, suffixed with the index of their value, as their foreignID. Children that
+// have already been triggered are not triggered again when the step is retried. Like AddSubWorkflow, the child
+// workflow must be built before the parent workflow is run and the cancellation of the child runs is configured using
+// WithChildCancelPolicy.
+func AddChildWorkflows[Type any, Status StatusType, ChildType any, ChildStatus StatusType](
+	b *Builder[Type, Status],
+	from Status,
+	child *Workflow[ChildType, ChildStatus],
+	childStatus ChildStatus,
+	trigger ChildrenTriggerFunc[Type, Status, ChildType],
+	join ChildrenJoinFunc[Type, Status, ChildType, ChildStatus],
+	allowedDestinations ...Status,
+) *stepUpdater[Type, Status] {
+	joinChildren := func(ctx context.Context, r *Run[Type, Status], children []*Record) (Status, error) {
+		var outcome ChildrenOutcome[ChildType, ChildStatus]
+		for _, record := range children {
+			// The child's codec is only configured once the child workflow has been built.
+			var t ChildType
+			err := child.codec.Unmarshal(record.Object, &t)
+			if err != nil {
+				return 0, err
+			}
+
+			outcome.Children = append(outcome.Children, &TypedRecord[ChildType, ChildStatus]{
+				Record: *record,
+				Status: ChildStatus(record.Status),
+				Object: &t,
+			})
+		}
+
+		return join(ctx, r, &outcome)
+	}
+
+	b.workflow.subWorkflows = append(b.workflow.subWorkflows, subWorkflow[Type, Status]{
+		from:      from,
+		childName: child.Name(),
+		child: func() (EventStreamer, lookupFunc) {
+			return child.eventStreamer, child.recordStore.Lookup
+		},
+		childRecordStore: func() RecordStore {
+			return child.recordStore
+		},
+		join: joinChildren,
+	})
+
+	return b.AddStep(from, func(ctx context.Context, r *Run[Type, Status]) (Status, error) {
+		values, err := trigger(ctx, r)
+		if err != nil {
+			return 0, err
+		}
+
+		if len(values) == 0 {
+			return joinChildren(ctx, r, nil)
+		}
+
+		for i, value := range values {
+			foreignID := childForeignID(r.RunID, i)
+			_, err := child.recordStore.Latest(ctx, child.Name(), foreignID)
+			if err == nil {
+				// The child was triggered by a previous attempt of the step.
+				continue
+			} else if !errors.Is(err, ErrRecordNotFound) {
+				return 0, err
+			}
+
+			_, err = child.triggerChild(
+				ctx,
+				foreignID,
+				childStatus,
+				WithInitialValue[ChildType, ChildStatus](&value),
+				WithMetadata[ChildType, ChildStatus](map[string]string{
+					metadataParentRunID: r.RunID,
+					metadataChildCount:  strconv.Itoa(len(values)),
+				}),
+			)
+			if err != nil && !errors.Is(err, ErrWorkflowInProgress) {
+				return 0, err
+			}
+		}
+
+		// The parent's run remains in its current status until all the child runs have finished.
+		return r.Skip()
+	}, allowedDestinations...)
+}
+
+func childForeignID(parentRunID string, index int) string {
+	return parentRunID + "-" + strconv.Itoa(index)
+}
+
+// childRuns returns the latest runs of the children triggered by the parent's run using AddChildWorkflows and whether
+// all of them have been triggered and have finished.
+func childRuns(ctx context.Context, latest latestLookup, childName, parentRunID string) ([]*Record, bool, error) {
+	first, err := latest(ctx, childName, childForeignID(parentRunID, 0))
+	if errors.Is(err, ErrRecordNotFound) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	count, err := strconv.Atoi(first.Meta.Metadata[metadataChildCount])
+	if err != nil {
+		return nil, false, err
+	}
+
+	children := []*Record{first}
+	finished := first.RunState.Finished()
+	for i := 1; i < count; i++ {
+		child, err := latest(ctx, childName, childForeignID(parentRunID, i))
+		if errors.Is(err, ErrRecordNotFound) {
+			// The child has not been triggered yet.
+			finished = false
+			continue
+		} else if err != nil {
+			return nil, false, err
+		}
+
+		children = append(children, child)
+		finished = finished && child.RunState.Finished()
+	}
+
+	return children, finished, nil
+}
@@ -0,0 +1,108 @@
+package workflow_test
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestAddChildWorkflows(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	streamer := memstreamer.New()
+	recordStore := memrecordstore.New()
+	roleScheduler := memrolescheduler.New()
+
+	// Children with an even UserID complete and the others are cancelled.
+	cb := workflow.NewBuilder[MyType, status]("child")
+	cb.AddCallback(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status], reader io.Reader) (status, error) {
+		if r.Object.UserID%2 == 0 {
+			return StatusEnd, nil
+		}
+
+		return r.Cancel(ctx)
+	}, StatusEnd)
+	child := cb.Build(streamer, recordStore, roleScheduler)
+
+	pb := workflow.NewBuilder[MyType, status]("parent")
+	workflow.AddChildWorkflows(
+		pb,
+		StatusStart,
+		child,
+		StatusStart,
+		func(ctx context.Context, r *workflow.Run[MyType, status]) ([]MyType, error) {
+			return []MyType{{UserID: 2}, {UserID: 4}, {UserID: r.Object.UserID}}, nil
+		},
+		func(ctx context.Context, r *workflow.Run[MyType, status], outcome *workflow.ChildrenOutcome[MyType, status]) (status, error) {
+			r.Object.OTPVerified = outcome.AllSucceeded()
+			r.Object.OTP = len(outcome.Failed())
+			return StatusEnd, nil
+		},
+		StatusEnd,
+	)
+	parent := pb.Build(streamer, recordStore, roleScheduler)
+
+	child.Run(ctx)
+	t.Cleanup(child.Stop)
+	parent.Run(ctx)
+	t.Cleanup(parent.Stop)
+
+	testCases := []struct {
+		name              string
+		userID            int64
+		expectSucceeded   bool
+		expectFailedCount int
+	}{
+		{
+			name:            "All children succeeded",
+			userID:          6,
+			expectSucceeded: true,
+		},
+		{
+			name:              "Any child failed",
+			userID:            7,
+			expectFailedCount: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			foreignID := tc.name
+			runID, err := parent.Trigger(ctx, foreignID, StatusStart, workflow.WithInitialValue[MyType, status](&MyType{
+				UserID: tc.userID,
+			}))
+			require.Nil(t, err)
+
+			for i := range 3 {
+				childForeignID := runID + "-" + strconv.Itoa(i)
+				require.Eventually(t, func() bool {
+					err := child.Callback(ctx, childForeignID, StatusStart, strings.NewReader(""))
+					if err != nil {
+						return false
+					}
+
+					r, err := recordStore.Latest(ctx, "child", childForeignID)
+					return err == nil && r.RunState.Finished()
+				}, 5*time.Second, 10*time.Millisecond)
+			}
+
+			run, err := parent.Await(ctx, foreignID, runID, StatusEnd)
+			require.Nil(t, err)
+			require.Equal(t, tc.expectSucceeded, run.Object.OTPVerified)
+			require.Equal(t, tc.expectFailedCount, run.Object.OTP)
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
+
+	"github.com/luno/workflow/internal/metrics"
+)
+
+// CircuitBreaker stops the step from consuming once it has failed threshold times in a row, such as when a downstream
+// dependency of the step is unavailable. Whilst the circuit is open the step's events are left unconsumed, instead
+// of the runs being retried until they are paused by PauseAfterErrCount, and once the cooldown has passed a single
+// run is processed to probe the dependency. The circuit closes when the probe succeeds and opens for another cooldown
+// when it fails. The circuit breaker is shared by all the shards of the step but is per instance of the workflow. An
+// AlertTypeCircuitOpen alert is raised when the circuit opens. Circuit breakers are not supported by batch steps.
+func CircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(opt *options) {
+		opt.circuitBreaker = &circuitBreakerConfig{
+			threshold: threshold,
+			cooldown:  cooldown,
+		}
+	}
+}
+
+type circuitBreakerConfig struct {
+	threshold int
+	cooldown  time.Duration
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// circuitBreaker tracks the consecutive failures of a step and holds back the step's shards whilst the circuit is
+// open.
+type circuitBreaker struct {
+	config circuitBreakerConfig
+	clock  clock.Clock
+	// onChange is called whenever the circuit opens or closes.
+	onChange func(ctx context.Context, open bool, err error)
+
+	mu        sync.Mutex
+	state     circuitState
+	failures  int
+	openUntil time.Time
+	// changed is closed, and replaced, whenever the state changes so that the shards waiting on a probe are woken.
+	changed chan struct{}
+}
+
+func newCircuitBreaker[Type any, Status StatusType](
+	w *Workflow[Type, Status],
+	status Status,
+	p consumerConfig[Type, Status],
+) *circuitBreaker {
+	processName := makeRole(status.String(), "consumer")
+	return &circuitBreaker{
+		config:  *p.circuitBreaker,
+		clock:   w.clock,
+		changed: make(chan struct{}),
+		onChange: func(ctx context.Context, open bool, err error) {
+			if !open {
+				metrics.CircuitBreakerOpen.WithLabelValues(w.Name(), processName).Set(0)
+				return
+			}
+
+			metrics.CircuitBreakerOpen.WithLabelValues(w.Name(), processName).Set(1)
+			w.logger.Error(ctx, fmt.Errorf("circuit breaker opened: %w, meta: %v", err, map[string]string{
+				"workflow_name": w.Name(),
+				"process_name":  processName,
+			}))
+
+			w.alert(ctx, Alert{
+				Type:   AlertTypeCircuitOpen,
+				Status: status.String(),
+				Message: fmt.Sprintf(
+					"circuit breaker of %s opened for %s after %d consecutive failures: %v",
+					processName,
+					p.circuitBreaker.cooldown,
+					p.circuitBreaker.threshold,
+					err,
+				),
+			})
+		},
+	}
+}
+
+// wait blocks whilst the circuit is open, or is being probed by another shard, and returns once the caller can
+// process its run.
+func (c *circuitBreaker) wait(ctx context.Context) error {
+	for {
+		c.mu.Lock()
+		state, openUntil, changed := c.state, c.openUntil, c.changed
+		if state == circuitOpen && !c.clock.Now().Before(openUntil) {
+			// The caller processes its run as the probe.
+			c.setState(circuitHalfOpen)
+			c.mu.Unlock()
+			return nil
+		}
+		c.mu.Unlock()
+
+		switch state {
+		case circuitClosed:
+			return nil
+		case circuitOpen:
+			err := waitUntil(ctx, c.clock, openUntil)
+			if err != nil {
+				return err
+			}
+		case circuitHalfOpen:
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-changed:
+			}
+		}
+	}
+}
+
+// result records the outcome of processing a run.
+func (c *circuitBreaker) result(ctx context.Context, err error) {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		// A probe that was stopped, such as by the workflow stopping, did not probe the dependency and so another
+		// shard takes over the probe.
+		if c.state == circuitHalfOpen {
+			c.setState(circuitOpen)
+		}
+
+		return
+	}
+
+	c.mu.Lock()
+	var opened, closed bool
+	if err == nil {
+		c.failures = 0
+		if c.state == circuitHalfOpen {
+			c.setState(circuitClosed)
+			closed = true
+		}
+	} else {
+		c.failures++
+		if c.state == circuitHalfOpen || (c.state == circuitClosed && c.failures >= c.config.threshold) {
+			c.openUntil = c.clock.Now().Add(c.config.cooldown)
+			c.setState(circuitOpen)
+			opened = true
+		}
+	}
+	c.mu.Unlock()
+
+	// The metric, log, and alert are emitted without holding the lock as the alert hook can be slow.
+	if opened || closed {
+		c.onChange(ctx, opened, err)
+	}
+}
+
+func (c *circuitBreaker) setState(state circuitState) {
+	c.state = state
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// circuitBreakerConsumer waits for the circuit breaker before calling the consumer and records the consumer's
+// outcome.
+func circuitBreakerConsumer[Type any, Status StatusType](
+	breaker *circuitBreaker,
+	consumer ConsumerFunc[Type, Status],
+) ConsumerFunc[Type, Status] {
+	if breaker == nil {
+		return consumer
+	}
+
+	return func(ctx context.Context, r *Run[Type, Status]) (Status, error) {
+		err := breaker.wait(ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		next, err := consumer(ctx, r)
+		breaker.result(ctx, err)
+		return next, err
+	}
+}
@@ -0,0 +1,95 @@
+package workflow_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	var (
+		failing atomic.Bool
+		calls   atomic.Int64
+		mu      sync.Mutex
+		alerts  []workflow.Alert
+	)
+	failing.Store(true)
+
+	b := workflow.NewBuilder[MyType, status]("circuit breaker")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		calls.Add(1)
+		if failing.Load() {
+			return 0, errors.New("downstream unavailable")
+		}
+
+		return StatusEnd, nil
+	}, StatusEnd).WithOptions(
+		workflow.ErrBackOff(time.Millisecond),
+		workflow.CircuitBreaker(2, time.Second),
+	)
+
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+		workflow.WithAlertHook(func(ctx context.Context, alert workflow.Alert) {
+			mu.Lock()
+			defer mu.Unlock()
+			alerts = append(alerts, alert)
+		}),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "foreignID", StatusStart)
+	require.Nil(t, err)
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(alerts) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	require.Equal(t, workflow.AlertTypeCircuitOpen, alerts[0].Type)
+	require.Equal(t, "Start", alerts[0].Status)
+	mu.Unlock()
+
+	// The step stops consuming whilst the circuit is open.
+	require.Equal(t, int64(2), calls.Load())
+	require.Never(t, func() bool {
+		return calls.Load() > 2
+	}, 300*time.Millisecond, 10*time.Millisecond)
+
+	// The probe after the cooldown succeeds and closes the circuit.
+	failing.Store(false)
+	r, err := wf.Await(ctx, "foreignID", runID, StatusEnd, workflow.WithAwaitPollingFrequency(10*time.Millisecond))
+	require.Nil(t, err)
+	require.Equal(t, int64(3), calls.Load())
+
+	// The run is not paused by the circuit breaker.
+	require.Equal(t, workflow.RunStateCompleted, r.RunState)
+}
+
+func TestCircuitBreakerValidation(t *testing.T) {
+	b := workflow.NewBuilder[MyType, status]("circuit breaker")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd).WithOptions(workflow.CircuitBreaker(0, time.Second))
+
+	require.PanicsWithValue(t, "'AddStep(Start,' circuit breaker requires a positive threshold and cooldown", func() {
+		b.Build(memstreamer.New(), memrecordstore.New(), memrolescheduler.New())
+	})
+}
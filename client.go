@@ -0,0 +1,127 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// Client interacts with a workflow that is run by another deployment, such as a service that triggers and awaits the
+// runs of a workflow that is owned by another team. A Client does not start any consumers and, unlike Workflow, has
+// no Run method so that a deployment cannot accidentally run the workflow's consumers. The deployment that runs the
+// workflow must use the same name, EventStreamer, and RecordStore as the Client.
+type Client[Type any, Status StatusType] struct {
+	workflow *Workflow[Type, Status]
+}
+
+// NewClient returns a Client of the workflow with the name without requiring the workflow's definition. As the
+// Client does not know the statuses of the workflow, Trigger does not validate the starting status, Await can only
+// await the statuses that the workflow's steps move runs on from and AwaitTerminal needs to be used to await runs
+// finishing, and Callback is not supported. Use Builder.BuildClient to create a Client from the workflow's
+// definition instead. Build options that only configure the workflow's consumers have no effect.
+func NewClient[Type any, Status StatusType](
+	name string,
+	eventStreamer EventStreamer,
+	recordStore RecordStore,
+	opts ...BuildOption,
+) *Client[Type, Status] {
+	c := NewBuilder[Type, Status](name).BuildClient(eventStreamer, recordStore, opts...)
+	c.workflow.undefined = true
+	return c
+}
+
+// BuildClient returns a Client of the workflow from its definition, which supports the workflow's callbacks and
+// terminal statuses, without starting any consumers. It is intended for services that share the definition of a
+// workflow that is run by another deployment.
+func (b *Builder[Type, Status]) BuildClient(
+	eventStreamer EventStreamer,
+	recordStore RecordStore,
+	opts ...BuildOption,
+) *Client[Type, Status] {
+	w := b.Build(eventStreamer, recordStore, nil, opts...)
+
+	// Clients are ready to be used once built as the workflow is run elsewhere.
+	w.ctx = context.Background()
+	w.calledRun = true
+
+	return &Client[Type, Status]{workflow: w}
+}
+
+// Name returns the name of the workflow.
+func (c *Client[Type, Status]) Name() string {
+	return c.workflow.Name()
+}
+
+// Trigger triggers a run of the workflow in the same way as Workflow.Trigger.
+func (c *Client[Type, Status]) Trigger(
+	ctx context.Context,
+	foreignID string,
+	startingStatus Status,
+	opts ...TriggerOption[Type, Status],
+) (runID string, err error) {
+	return c.workflow.Trigger(ctx, foreignID, startingStatus, opts...)
+}
+
+// Await waits for the run to reach the status in the same way as Workflow.Await.
+func (c *Client[Type, Status]) Await(
+	ctx context.Context,
+	foreignID, runID string,
+	status Status,
+	opts ...AwaitOption,
+) (*Run[Type, Status], error) {
+	return c.workflow.Await(ctx, foreignID, runID, status, opts...)
+}
+
+// AwaitAny waits for the run to reach any of the statuses in the same way as Workflow.AwaitAny.
+func (c *Client[Type, Status]) AwaitAny(
+	ctx context.Context,
+	foreignID, runID string,
+	statuses ...Status,
+) (Status, *Run[Type, Status], error) {
+	return c.workflow.AwaitAny(ctx, foreignID, runID, statuses...)
+}
+
+// AwaitTerminal waits for the run to finish in the same way as Workflow.AwaitTerminal.
+func (c *Client[Type, Status]) AwaitTerminal(
+	ctx context.Context,
+	foreignID, runID string,
+	opts ...AwaitOption,
+) (RunState, *Run[Type, Status], error) {
+	return c.workflow.AwaitTerminal(ctx, foreignID, runID, opts...)
+}
+
+// Callback calls the workflow's callbacks of the status in the same way as Workflow.Callback. An error is returned
+// when the Client has no callbacks for the status, such as for clients created using NewClient.
+func (c *Client[Type, Status]) Callback(ctx context.Context, foreignID string, status Status, payload io.Reader) error {
+	if len(c.workflow.callback[status]) == 0 {
+		return fmt.Errorf("callback failed: no callback configured for status: %s", status)
+	}
+
+	return c.workflow.Callback(ctx, foreignID, status, payload)
+}
+
+// GetRun returns the run in the same way as Workflow.GetRun.
+func (c *Client[Type, Status]) GetRun(ctx context.Context, foreignID, runID string) (*TypedRecord[Type, Status], error) {
+	return c.workflow.GetRun(ctx, foreignID, runID)
+}
+
+// ListRuns lists the runs of the workflow that match the filter in the same way as Workflow.ListRuns.
+func (c *Client[Type, Status]) ListRuns(
+	ctx context.Context,
+	filter RunFilter[Status],
+) ([]TypedRecord[Type, Status], error) {
+	return c.workflow.ListRuns(ctx, filter)
+}
+
+// ListForeignIDRuns lists the runs of the foreign ID in the same way as Workflow.ListForeignIDRuns.
+func (c *Client[Type, Status]) ListForeignIDRuns(
+	ctx context.Context,
+	foreignID string,
+) ([]TypedRecord[Type, Status], error) {
+	return c.workflow.ListForeignIDRuns(ctx, foreignID)
+}
+
+// RunHistory returns the transitions of the run in the same way as Workflow.RunHistory.
+func (c *Client[Type, Status]) RunHistory(ctx context.Context, runID string) ([]Transition, error) {
+	return c.workflow.RunHistory(ctx, runID)
+}
@@ -0,0 +1,104 @@
+package workflow_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+var (
+	_ workflow.API[string, status]       = (*workflow.Workflow[string, status])(nil)
+	_ workflow.ClientAPI[string, status] = (*workflow.Workflow[string, status])(nil)
+	_ workflow.ClientAPI[string, status] = (*workflow.Client[string, status])(nil)
+)
+
+func clientDefinition() *workflow.Builder[string, status] {
+	b := workflow.NewBuilder[string, status]("client")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return StatusMiddle, nil
+	}, StatusMiddle)
+	b.AddCallback(StatusMiddle, func(ctx context.Context, r *workflow.Run[string, status], reader io.Reader) (status, error) {
+		b, err := io.ReadAll(reader)
+		if err != nil {
+			return 0, err
+		}
+
+		*r.Object = string(b)
+		return StatusEnd, nil
+	}, StatusEnd)
+	return b
+}
+
+func TestNewClient(t *testing.T) {
+	streamer := memstreamer.New()
+	recordStore := memrecordstore.New()
+
+	wf := clientDefinition().Build(streamer, recordStore, memrolescheduler.New())
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	client := workflow.NewClient[string, status]("client", streamer, recordStore)
+	require.Equal(t, "client", client.Name())
+
+	runID, err := client.Trigger(ctx, "foreignID", StatusStart)
+	require.Nil(t, err)
+
+	r, err := client.Await(ctx, "foreignID", runID, StatusMiddle, workflow.WithAwaitPollingFrequency(10*time.Millisecond))
+	require.Nil(t, err)
+	require.Equal(t, StatusMiddle, r.Status)
+
+	// Clients created without the workflow's definition do not have its callbacks.
+	err = client.Callback(ctx, "foreignID", StatusMiddle, strings.NewReader("payload"))
+	require.ErrorContains(t, err, "no callback configured for status")
+
+	err = wf.Callback(ctx, "foreignID", StatusMiddle, strings.NewReader("payload"))
+	require.Nil(t, err)
+
+	runState, r, err := client.AwaitTerminal(ctx, "foreignID", runID, workflow.WithAwaitPollingFrequency(10*time.Millisecond))
+	require.Nil(t, err)
+	require.Equal(t, workflow.RunStateCompleted, runState)
+	require.Equal(t, "payload", *r.Object)
+
+	history, err := client.RunHistory(ctx, runID)
+	require.Nil(t, err)
+	require.Len(t, history, 3)
+}
+
+func TestBuilder_BuildClient(t *testing.T) {
+	streamer := memstreamer.New()
+	recordStore := memrecordstore.New()
+
+	wf := clientDefinition().Build(streamer, recordStore, memrolescheduler.New())
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	client := clientDefinition().BuildClient(streamer, recordStore)
+
+	_, err := client.Trigger(ctx, "foreignID", StatusCompleted)
+	require.ErrorContains(t, err, "status provided is not configured for workflow")
+
+	runID, err := client.Trigger(ctx, "foreignID", StatusStart)
+	require.Nil(t, err)
+
+	_, err = client.Await(ctx, "foreignID", runID, StatusMiddle, workflow.WithAwaitPollingFrequency(10*time.Millisecond))
+	require.Nil(t, err)
+
+	err = client.Callback(ctx, "foreignID", StatusMiddle, strings.NewReader("payload"))
+	require.Nil(t, err)
+
+	r, err := client.Await(ctx, "foreignID", runID, StatusEnd, workflow.WithAwaitPollingFrequency(10*time.Millisecond))
+	require.Nil(t, err)
+	require.Equal(t, "payload", *r.Object)
+	require.Equal(t, workflow.RunStateCompleted, r.RunState)
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/luno/workflow"
+)
+
+// client calls the admin API served by workflow.NewAdminHandler.
+type client struct {
+	addr  string
+	actor string
+	http  *http.Client
+}
+
+func newClient(addr, actor string) *client {
+	return &client{
+		addr:  strings.TrimSuffix(addr, "/"),
+		actor: actor,
+		http:  http.DefaultClient,
+	}
+}
+
+func (c *client) workflows(ctx context.Context) ([]string, error) {
+	var resp struct {
+		Workflows []string `json:"workflows"`
+	}
+
+	err := c.do(ctx, http.MethodGet, "/workflows", nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Workflows, nil
+}
+
+func (c *client) listRuns(ctx context.Context, workflowName string, query url.Values) ([]workflow.AdminRun, error) {
+	var resp struct {
+		Runs []workflow.AdminRun `json:"runs"`
+	}
+
+	err := c.do(ctx, http.MethodGet, workflowPath(workflowName, "runs")+"?"+query.Encode(), nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.Runs, nil
+}
+
+func (c *client) getRun(ctx context.Context, workflowName, runID string) (*workflow.AdminRun, error) {
+	var run workflow.AdminRun
+	err := c.do(ctx, http.MethodGet, workflowPath(workflowName, "runs", runID), nil, &run)
+	if err != nil {
+		return nil, err
+	}
+
+	return &run, nil
+}
+
+func (c *client) history(ctx context.Context, workflowName, runID string) ([]workflow.Transition, error) {
+	var resp struct {
+		History []workflow.Transition `json:"history"`
+	}
+
+	err := c.do(ctx, http.MethodGet, workflowPath(workflowName, "runs", runID, "history"), nil, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.History, nil
+}
+
+func (c *client) graph(ctx context.Context, workflowName string) (*workflow.Graph, error) {
+	var g workflow.Graph
+	err := c.do(ctx, http.MethodGet, workflowPath(workflowName, "graph"), nil, &g)
+	if err != nil {
+		return nil, err
+	}
+
+	return &g, nil
+}
+
+func (c *client) trigger(ctx context.Context, workflowName string, req workflow.AdminTriggerRequest) (string, error) {
+	var resp struct {
+		RunID string `json:"run_id"`
+	}
+
+	err := c.do(ctx, http.MethodPost, workflowPath(workflowName, "runs"), req, &resp)
+	if err != nil {
+		return "", err
+	}
+
+	return resp.RunID, nil
+}
+
+func (c *client) cancel(ctx context.Context, workflowName, runID, reason string) error {
+	req := workflow.AdminCancelRequest{Reason: reason}
+	return c.do(ctx, http.MethodPost, workflowPath(workflowName, "runs", runID, "cancel"), req, nil)
+}
+
+func (c *client) replay(ctx context.Context, workflowName, runID string, fromStatus int, reason string) error {
+	req := workflow.AdminReplayRequest{FromStatus: fromStatus, Reason: reason}
+	return c.do(ctx, http.MethodPost, workflowPath(workflowName, "runs", runID, "replay"), req, nil)
+}
+
+// do sends the request with the JSON encoding of the body, unless it is nil, and decodes the response into resp,
+// unless it is nil. The error returned by the admin API is returned when the response is not successful.
+func (c *client) do(ctx context.Context, method, path string, body, resp any) error {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+
+		reader = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.addr+path, reader)
+	if err != nil {
+		return err
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if c.actor != "" {
+		req.Header.Set(workflow.HeaderAdminActor, c.actor)
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= http.StatusBadRequest {
+		var errResp struct {
+			Error string `json:"error"`
+		}
+
+		// The body of the response is not JSON when the path is not served by the admin API.
+		b, _ := io.ReadAll(res.Body)
+		if json.Unmarshal(b, &errResp) != nil || errResp.Error == "" {
+			errResp.Error = strings.TrimSpace(string(b))
+		}
+
+		return fmt.Errorf("%s %s: %s: %s", method, path, res.Status, errResp.Error)
+	}
+
+	if resp == nil {
+		return nil
+	}
+
+	return json.NewDecoder(res.Body).Decode(resp)
+}
+
+func workflowPath(workflowName string, elems ...string) string {
+	path := "/workflows/" + url.PathEscape(workflowName)
+	for _, elem := range elems {
+		path += "/" + url.PathEscape(elem)
+	}
+
+	return path
+}
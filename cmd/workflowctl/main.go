@@ -0,0 +1,492 @@
+// Command workflowctl administers the runs of workflows through the admin API served by workflow.NewAdminHandler so
+// that on-call engineers can list, describe, trigger, cancel, and replay runs, export graphs, and tail the history of
+// runs without writing Go programs.
+//
+// Usage:
+//
+//	workflowctl [-addr url] [-actor name] <command> [flags] [args]
+//
+// The commands are:
+//
+//	workflows                                             list the workflows served by the admin API
+//	list      -workflow name [-status n] [-run-state n]   list the runs of a workflow
+//	describe  -workflow name <run id>                     show a run and its Object
+//	trigger   -workflow name -foreign-id id -status n     trigger a run
+//	cancel    -workflow name [-reason text] <run id>      cancel a run
+//	replay    -workflow name -from-status n <run id>      replay a run from a status
+//	graph     -workflow name [-format mermaid|dot|json]   export the graph of a workflow
+//	history   -workflow name [-follow] <run id>           show, or tail, the history of a run
+//
+// The address of the admin API defaults to the WORKFLOWCTL_ADDR environment variable and the actor, which is recorded
+// in the history of the runs that are changed, defaults to the USER environment variable.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/luno/workflow"
+)
+
+const usage = `Usage: workflowctl [-addr url] [-actor name] <command> [flags] [args]
+
+Commands:
+  workflows   List the workflows served by the admin API
+  list        List the runs of a workflow
+  describe    Show a run and its Object
+  trigger     Trigger a run
+  cancel      Cancel a run
+  replay      Replay a run from a status
+  graph       Export the graph of a workflow
+  history     Show, or tail, the history of a run
+
+Run 'workflowctl <command> -h' for the flags of a command.
+`
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err := run(ctx, os.Args[1:], os.Stdout)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(2)
+	} else if err != nil {
+		fmt.Fprintln(os.Stderr, "workflowctl:", err)
+		os.Exit(1)
+	}
+}
+
+type command func(ctx context.Context, c *client, args []string, out io.Writer) error
+
+var commands = map[string]command{
+	"workflows": listWorkflows,
+	"list":      listRuns,
+	"describe":  describeRun,
+	"trigger":   triggerRun,
+	"cancel":    cancelRun,
+	"replay":    replayRun,
+	"graph":     exportGraph,
+	"history":   runHistory,
+}
+
+func run(ctx context.Context, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("workflowctl", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), usage)
+		fs.PrintDefaults()
+	}
+	addr := fs.String("addr", os.Getenv("WORKFLOWCTL_ADDR"), "URL of the admin API, such as http://localhost:8080/admin")
+	actor := fs.String("actor", os.Getenv("USER"), "who is making the changes, recorded in the history of runs")
+
+	err := fs.Parse(args)
+	if err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+
+	cmd, ok := commands[fs.Arg(0)]
+	if !ok {
+		return fmt.Errorf("unknown command: %q", fs.Arg(0))
+	}
+
+	if *addr == "" {
+		return errors.New("address of the admin API is required, see -addr")
+	}
+
+	return cmd(ctx, newClient(*addr, *actor), fs.Args()[1:], out)
+}
+
+// newFlagSet returns the flag set of the command with the required -workflow flag.
+func newFlagSet(name string) (*flag.FlagSet, *string) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	workflowName := fs.String("workflow", "", "name of the workflow (required)")
+	return fs, workflowName
+}
+
+// parse parses the flags of the command and returns the command's positional argument when it expects one.
+func parse(fs *flag.FlagSet, args []string, workflowName *string, argName string) (string, error) {
+	err := fs.Parse(args)
+	if err != nil {
+		return "", err
+	}
+
+	if workflowName != nil && *workflowName == "" {
+		return "", fmt.Errorf("%s: -workflow is required", fs.Name())
+	}
+
+	if argName == "" {
+		if fs.NArg() != 0 {
+			return "", fmt.Errorf("%s: unexpected arguments: %v", fs.Name(), fs.Args())
+		}
+
+		return "", nil
+	}
+
+	if fs.NArg() != 1 {
+		return "", fmt.Errorf("%s: expected a single %s", fs.Name(), argName)
+	}
+
+	return fs.Arg(0), nil
+}
+
+func listWorkflows(ctx context.Context, c *client, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("workflows", flag.ContinueOnError)
+	_, err := parse(fs, args, nil, "")
+	if err != nil {
+		return err
+	}
+
+	names, err := c.workflows(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		fmt.Fprintln(out, name)
+	}
+
+	return nil
+}
+
+// intsFlag is a flag that can be repeated to provide many integers.
+type intsFlag []int
+
+func (f *intsFlag) String() string {
+	return fmt.Sprint(*f)
+}
+
+func (f *intsFlag) Set(value string) error {
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+
+	*f = append(*f, i)
+	return nil
+}
+
+func listRuns(ctx context.Context, c *client, args []string, out io.Writer) error {
+	var statuses, runStates intsFlag
+
+	fs, workflowName := newFlagSet("list")
+	fs.Var(&statuses, "status", "only list runs in the status, can be repeated")
+	fs.Var(&runStates, "run-state", "only list runs in the run state, such as 3 for paused, can be repeated")
+	foreignIDPrefix := fs.String("foreign-id-prefix", "", "only list runs whose foreign ID has the prefix")
+	limit := fs.Int("limit", 20, "maximum number of runs to list")
+	offset := fs.Int64("offset", 0, "number of runs to skip")
+	desc := fs.Bool("desc", false, "list the newest runs first")
+
+	_, err := parse(fs, args, workflowName, "")
+	if err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	for _, s := range statuses {
+		query.Add("status", strconv.Itoa(s))
+	}
+
+	for _, rs := range runStates {
+		query.Add("run_state", strconv.Itoa(rs))
+	}
+
+	if *foreignIDPrefix != "" {
+		query.Set("foreign_id_prefix", *foreignIDPrefix)
+	}
+
+	query.Set("limit", strconv.Itoa(*limit))
+	query.Set("offset", strconv.FormatInt(*offset, 10))
+	if *desc {
+		query.Set("order", "desc")
+	}
+
+	runs, err := c.listRuns(ctx, *workflowName, query)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "RUN ID\tFOREIGN ID\tSTATUS\tRUN STATE\tUPDATED AT")
+	for _, r := range runs {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			r.RunID, r.ForeignID, statusString(r.Status, r.StatusDescription), r.RunState, formatTime(r.UpdatedAt))
+	}
+
+	return tw.Flush()
+}
+
+func describeRun(ctx context.Context, c *client, args []string, out io.Writer) error {
+	fs, workflowName := newFlagSet("describe")
+	runID, err := parse(fs, args, workflowName, "run id")
+	if err != nil {
+		return err
+	}
+
+	r, err := c.getRun(ctx, *workflowName, runID)
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Workflow:\t%s\n", r.WorkflowName)
+	fmt.Fprintf(tw, "Run ID:\t%s\n", r.RunID)
+	fmt.Fprintf(tw, "Foreign ID:\t%s\n", r.ForeignID)
+	fmt.Fprintf(tw, "Status:\t%s\n", statusString(r.Status, r.StatusDescription))
+	fmt.Fprintf(tw, "Run State:\t%s\n", r.RunState)
+	fmt.Fprintf(tw, "Created At:\t%s\n", formatTime(r.CreatedAt))
+	fmt.Fprintf(tw, "Updated At:\t%s\n", formatTime(r.UpdatedAt))
+	for k, v := range r.Metadata {
+		fmt.Fprintf(tw, "Metadata %s:\t%s\n", k, v)
+	}
+
+	err = tw.Flush()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case len(r.Object) > 0:
+		object, err := json.MarshalIndent(r.Object, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(out, "Object:\n%s\n", object)
+	case len(r.ObjectBytes) > 0:
+		fmt.Fprintf(out, "Object: %d bytes that are not JSON\n", len(r.ObjectBytes))
+	}
+
+	return nil
+}
+
+func triggerRun(ctx context.Context, c *client, args []string, out io.Writer) error {
+	fs, workflowName := newFlagSet("trigger")
+	foreignID := fs.String("foreign-id", "", "foreign ID of the run (required)")
+	status := fs.Int("status", 0, "status to start the run in (required)")
+	object := fs.String("object", "", "initial Object of the run encoded using the workflow's Codec, such as JSON")
+
+	_, err := parse(fs, args, workflowName, "")
+	if err != nil {
+		return err
+	}
+
+	if *foreignID == "" || *status == 0 {
+		return errors.New("trigger: -foreign-id and -status are required")
+	}
+
+	req := workflow.AdminTriggerRequest{
+		ForeignID: *foreignID,
+		Status:    *status,
+	}
+
+	if *object != "" {
+		if !json.Valid([]byte(*object)) {
+			return errors.New("trigger: -object is not valid JSON")
+		}
+
+		req.Object = json.RawMessage(*object)
+	}
+
+	runID, err := c.trigger(ctx, *workflowName, req)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(out, runID)
+	return nil
+}
+
+func cancelRun(ctx context.Context, c *client, args []string, out io.Writer) error {
+	fs, workflowName := newFlagSet("cancel")
+	reason := fs.String("reason", "", "why the run is cancelled, recorded in the run's history")
+
+	runID, err := parse(fs, args, workflowName, "run id")
+	if err != nil {
+		return err
+	}
+
+	err = c.cancel(ctx, *workflowName, runID, *reason)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "cancelled %s\n", runID)
+	return nil
+}
+
+func replayRun(ctx context.Context, c *client, args []string, out io.Writer) error {
+	fs, workflowName := newFlagSet("replay")
+	fromStatus := fs.Int("from-status", 0, "status whose step is called again (required)")
+	reason := fs.String("reason", "", "why the run is replayed, recorded in the run's history")
+
+	runID, err := parse(fs, args, workflowName, "run id")
+	if err != nil {
+		return err
+	}
+
+	if *fromStatus == 0 {
+		return errors.New("replay: -from-status is required")
+	}
+
+	err = c.replay(ctx, *workflowName, runID, *fromStatus, *reason)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "replaying %s from status %d\n", runID, *fromStatus)
+	return nil
+}
+
+func exportGraph(ctx context.Context, c *client, args []string, out io.Writer) error {
+	fs, workflowName := newFlagSet("graph")
+	format := fs.String("format", "mermaid", "format of the graph: mermaid, dot, or json")
+
+	_, err := parse(fs, args, workflowName, "")
+	if err != nil {
+		return err
+	}
+
+	g, err := c.graph(ctx, *workflowName)
+	if err != nil {
+		return err
+	}
+
+	switch *format {
+	case "mermaid":
+		_, err = fmt.Fprint(out, g.MermaidDiagram())
+	case "dot":
+		_, err = fmt.Fprint(out, g.DOT())
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(g)
+	default:
+		return fmt.Errorf("graph: unknown format: %q", *format)
+	}
+
+	return err
+}
+
+func runHistory(ctx context.Context, c *client, args []string, out io.Writer) error {
+	fs, workflowName := newFlagSet("history")
+	follow := fs.Bool("follow", false, "keep printing the transitions of the run until it has finished")
+	interval := fs.Duration("interval", 2*time.Second, "how often the history is polled when following")
+
+	runID, err := parse(fs, args, workflowName, "run id")
+	if err != nil {
+		return err
+	}
+
+	g, err := c.graph(ctx, *workflowName)
+	if err != nil {
+		return err
+	}
+
+	descriptions := make(map[int]string)
+	for _, s := range g.Statuses {
+		descriptions[s.Status] = s.Description
+	}
+
+	var printed int
+	for {
+		history, err := c.history(ctx, *workflowName, runID)
+		if err != nil {
+			return err
+		}
+
+		// The oldest transitions are dropped from the history of long runs and so the transitions that have not
+		// been printed are found by their count.
+		if printed > len(history) {
+			printed = 0
+		}
+
+		for _, t := range history[printed:] {
+			fmt.Fprintln(out, formatTransition(t, descriptions))
+		}
+
+		printed = len(history)
+
+		if !*follow || (len(history) > 0 && history[len(history)-1].ToRunState.Finished()) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(*interval):
+		}
+	}
+}
+
+func formatTransition(t workflow.Transition, descriptions map[int]string) string {
+	var sb strings.Builder
+	sb.WriteString(formatTime(t.At))
+	sb.WriteString("  ")
+
+	if t.FromStatus != 0 && t.FromStatus != t.ToStatus {
+		sb.WriteString(statusString(t.FromStatus, descriptions[t.FromStatus]) + " -> ")
+	}
+
+	sb.WriteString(statusString(t.ToStatus, descriptions[t.ToStatus]))
+
+	if t.FromRunState != t.ToRunState {
+		from := "Created"
+		if t.FromRunState != workflow.RunStateUnknown {
+			from = t.FromRunState.String()
+		}
+
+		sb.WriteString(" [" + from + " -> " + t.ToRunState.String() + "]")
+	}
+
+	details := []struct {
+		name  string
+		value string
+	}{
+		{name: "process", value: t.Process},
+		{name: "actor", value: t.Actor},
+		{name: "reason", value: t.Reason},
+		{name: "error", value: t.Error},
+	}
+	for _, d := range details {
+		if d.value != "" {
+			sb.WriteString(" " + d.name + "=" + strconv.Quote(d.value))
+		}
+	}
+
+	if t.Forced {
+		sb.WriteString(" forced")
+	}
+
+	if t.Replay {
+		sb.WriteString(" replay")
+	}
+
+	return sb.String()
+}
+
+func statusString(status int, description string) string {
+	if description == "" {
+		return strconv.Itoa(status)
+	}
+
+	return description + " (" + strconv.Itoa(status) + ")"
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
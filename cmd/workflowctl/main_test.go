@@ -0,0 +1,174 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+type status int
+
+const (
+	statusUnknown  status = 0
+	statusCreated  status = 1
+	statusApproved status = 2
+	statusShipped  status = 3
+)
+
+func (s status) String() string {
+	switch s {
+	case statusCreated:
+		return "Created"
+	case statusApproved:
+		return "Approved"
+	case statusShipped:
+		return "Shipped"
+	default:
+		return "Unknown"
+	}
+}
+
+func TestWorkflowctl(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("orders")
+	b.AddStep(statusCreated, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return statusApproved, nil
+	}, statusApproved)
+	b.AddCallback(statusApproved, func(ctx context.Context, r *workflow.Run[string, status], reader io.Reader) (status, error) {
+		return statusShipped, nil
+	}, statusShipped)
+
+	wf := b.Build(memstreamer.New(), memrecordstore.New(), memrolescheduler.New())
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	srv := httptest.NewServer(http.StripPrefix("/admin", workflow.NewAdminHandler(wf)))
+	t.Cleanup(srv.Close)
+
+	workflowctl := func(args ...string) string {
+		var out bytes.Buffer
+		args = append([]string{"-addr", srv.URL + "/admin", "-actor", "alice"}, args...)
+		err := run(ctx, args, &out)
+		require.Nil(t, err)
+
+		return out.String()
+	}
+
+	require.Equal(t, "orders\n", workflowctl("workflows"))
+
+	runID := strings.TrimSpace(workflowctl("trigger", "-workflow", "orders", "-foreign-id", "order-1", "-status", "1",
+		"-object", `"gift wrapped"`))
+	require.NotEmpty(t, runID)
+
+	_, err := wf.Await(ctx, "order-1", runID, statusApproved)
+	require.Nil(t, err)
+
+	list := workflowctl("list", "-workflow", "orders", "-status", "2")
+	require.Contains(t, list, runID)
+	require.Contains(t, list, "Approved (2)")
+
+	describe := workflowctl("describe", "-workflow", "orders", runID)
+	require.Contains(t, describe, "order-1")
+	require.Contains(t, describe, `"gift wrapped"`)
+
+	require.Contains(t, workflowctl("graph", "-workflow", "orders"), "1 --> 2: step")
+	require.Contains(t, workflowctl("graph", "-workflow", "orders", "-format", "dot"), "digraph")
+
+	workflowctl("replay", "-workflow", "orders", "-from-status", "1", "-reason", "fixed approval", runID)
+
+	_, err = wf.Await(ctx, "order-1", runID, statusApproved)
+	require.Nil(t, err)
+
+	workflowctl("cancel", "-workflow", "orders", "-reason", "customer request", runID)
+
+	history := workflowctl("history", "-workflow", "orders", "-follow", "-interval", "10ms", runID)
+	require.Contains(t, history, `Approved (2) -> Created (1) actor="alice" reason="fixed approval" replay`)
+	require.Contains(t, history, `[Running -> Cancelled] actor="alice" reason="customer request"`)
+}
+
+func TestWorkflowctlErrors(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("orders")
+	b.AddStep(statusCreated, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return statusShipped, nil
+	}, statusShipped)
+
+	wf := b.Build(memstreamer.New(), memrecordstore.New(), memrolescheduler.New())
+
+	srv := httptest.NewServer(workflow.NewAdminHandler(wf))
+	t.Cleanup(srv.Close)
+
+	testCases := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{
+			name: "Unknown command",
+			args: []string{"-addr", srv.URL, "delete"},
+			err:  `unknown command: "delete"`,
+		},
+		{
+			name: "Missing address",
+			args: []string{"-addr", "", "workflows"},
+			err:  "address of the admin API is required, see -addr",
+		},
+		{
+			name: "Missing workflow",
+			args: []string{"-addr", srv.URL, "describe", "run-1"},
+			err:  "describe: -workflow is required",
+		},
+		{
+			name: "Missing run ID",
+			args: []string{"-addr", srv.URL, "cancel", "-workflow", "orders"},
+			err:  "cancel: expected a single run id",
+		},
+		{
+			name: "Run not found",
+			args: []string{"-addr", srv.URL, "describe", "-workflow", "orders", "run-1"},
+			err:  "404 Not Found",
+		},
+		{
+			name: "Unknown workflow",
+			args: []string{"-addr", srv.URL, "graph", "-workflow", "payments"},
+			err:  `workflow not found: "payments"`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := run(context.Background(), tc.args, io.Discard)
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestFormatTransition(t *testing.T) {
+	at := time.Date(2024, time.April, 9, 10, 0, 0, 0, time.UTC)
+	descriptions := map[int]string{1: "Created", 2: "Approved"}
+
+	require.Equal(t, "2024-04-09T10:00:00Z  Created (1) [Created -> Initiated]",
+		formatTransition(workflow.Transition{ToStatus: 1, ToRunState: workflow.RunStateInitiated, At: at}, descriptions))
+
+	require.Equal(t, `2024-04-09T10:00:00Z  Created (1) -> Approved (2) process="created-consumer-1-of-1" error="timeout"`,
+		formatTransition(workflow.Transition{
+			FromStatus:   1,
+			ToStatus:     2,
+			FromRunState: workflow.RunStateRunning,
+			ToRunState:   workflow.RunStateRunning,
+			At:           at,
+			Process:      "created-consumer-1-of-1",
+			Error:        "timeout",
+		}, descriptions))
+}
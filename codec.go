@@ -0,0 +1,89 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Codec encodes and decodes the Object of a workflow's runs. The default Codec is JSONCodec and WithCodec can be used
+// to configure a different encoding such as ProtoCodec or the msgpackcodec adapter.
+//
+// Changing the Codec of a workflow that has existing runs results in those runs being unable to be unmarshalled and
+// so a new workflow, or version of the workflow, should be used when changing the Codec.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+	// ContentType is the MIME type of the encoding, such as "application/json", which allows tooling to decode the
+	// Object of a run.
+	ContentType() string
+}
+
+// WithCodec sets the Codec that is used to encode and decode the Object of the workflow's runs so that the payload
+// format can match the standards of the organisation or reduce the size of the stored records.
+func WithCodec(codec Codec) BuildOption {
+	return func(bo *buildOptions) {
+		bo.codec = codec
+	}
+}
+
+// JSONCodec encodes the Object of runs as JSON and is the default Codec.
+type JSONCodec struct{}
+
+func (JSONCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSONCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+func (JSONCodec) ContentType() string {
+	return "application/json"
+}
+
+var _ Codec = (*JSONCodec)(nil)
+
+// ProtoCodec encodes the Object of runs using the protobuf wire format. The workflow's Type must be a generated
+// protobuf message so that *Type implements proto.Message.
+type ProtoCodec struct{}
+
+func (ProtoCodec) Marshal(v any) ([]byte, error) {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("proto codec: %T does not implement proto.Message", v)
+	}
+
+	return proto.Marshal(m)
+}
+
+func (ProtoCodec) Unmarshal(data []byte, v any) error {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("proto codec: %T does not implement proto.Message", v)
+	}
+
+	return proto.Unmarshal(data, m)
+}
+
+func (ProtoCodec) ContentType() string {
+	return "application/x-protobuf"
+}
+
+var _ Codec = (*ProtoCodec)(nil)
+
+// unwrapCodec returns the codec that the compression and encryption codecs wrap so that the Object can be rendered
+// in its plain form.
+func unwrapCodec(codec Codec) Codec {
+	for {
+		switch c := codec.(type) {
+		case *compressedCodec:
+			codec = c.codec
+		case *encryptedCodec:
+			codec = c.codec
+		default:
+			return codec
+		}
+	}
+}
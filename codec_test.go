@@ -0,0 +1,91 @@
+package workflow_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestJSONCodec(t *testing.T) {
+	codec := workflow.JSONCodec{}
+	require.Equal(t, "application/json", codec.ContentType())
+
+	b, err := codec.Marshal(&MyType{UserID: 9, Name: "Andrew"})
+	require.Nil(t, err)
+
+	// JSONCodec must remain compatible with Marshal and Unmarshal.
+	expected, err := workflow.Marshal(&MyType{UserID: 9, Name: "Andrew"})
+	require.Nil(t, err)
+	require.Equal(t, expected, b)
+
+	var actual MyType
+	err = codec.Unmarshal(b, &actual)
+	require.Nil(t, err)
+	require.Equal(t, MyType{UserID: 9, Name: "Andrew"}, actual)
+}
+
+func TestProtoCodec(t *testing.T) {
+	codec := workflow.ProtoCodec{}
+	require.Equal(t, "application/x-protobuf", codec.ContentType())
+
+	b, err := codec.Marshal(wrapperspb.String("hello"))
+	require.Nil(t, err)
+
+	var actual wrapperspb.StringValue
+	err = codec.Unmarshal(b, &actual)
+	require.Nil(t, err)
+	require.Equal(t, "hello", actual.GetValue())
+
+	_, err = codec.Marshal(&MyType{})
+	require.ErrorContains(t, err, "does not implement proto.Message")
+
+	err = codec.Unmarshal(b, &MyType{})
+	require.ErrorContains(t, err, "does not implement proto.Message")
+}
+
+func TestWithCodec(t *testing.T) {
+	b := workflow.NewBuilder[wrapperspb.StringValue, status]("codec")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[wrapperspb.StringValue, status]) (status, error) {
+		r.Object.Value = r.Object.GetValue() + " world"
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	recordStore := memrecordstore.New()
+	wf := b.Build(
+		memstreamer.New(),
+		recordStore,
+		memrolescheduler.New(),
+		workflow.WithCodec(workflow.ProtoCodec{}),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "foreignID", StatusStart, workflow.WithInitialValue[wrapperspb.StringValue, status](
+		wrapperspb.String("hello"),
+	))
+	require.Nil(t, err)
+
+	run, err := wf.Await(ctx, "foreignID", runID, StatusEnd, workflow.WithAwaitPollingFrequency(10*time.Millisecond))
+	require.Nil(t, err)
+	require.Equal(t, "hello world", run.Object.GetValue())
+
+	// The stored Object must be encoded using the protobuf wire format.
+	record, err := recordStore.Lookup(ctx, runID)
+	require.Nil(t, err)
+
+	var stored wrapperspb.StringValue
+	err = proto.Unmarshal(record.Object, &stored)
+	require.Nil(t, err)
+	require.Equal(t, "hello world", stored.GetValue())
+}
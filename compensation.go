@@ -0,0 +1,199 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"k8s.io/utils/clock"
+)
+
+// CompensationFunc undoes the effects of the step that consumed the status that it was added for. Compensation
+// functions are run at least once and must be idempotent.
+type CompensationFunc[Type any, Status StatusType] func(ctx context.Context, record *TypedRecord[Type, Status]) error
+
+// AddCompensation adds a compensation function for the step that consumes the provided status. When a run is
+// cancelled, or fails terminally, the compensation functions of all the steps that the run completed are called in
+// the reverse order to which the steps were completed. A run fails terminally when it is paused after exceeding its
+// PauseAfterErrCount and is not going to be resumed automatically, which is when the workflow is built using
+// DisablePauseRetry or the run was published to the dead-letter queue configured using WithDeadLetterQueue. A run that
+// failed terminally is cancelled once it is compensated so that it cannot be resumed after being rolled back. Only
+// one compensation function can be added per status.
+func (b *Builder[Type, Status]) AddCompensation(from Status, fn CompensationFunc[Type, Status]) {
+	if _, exists := b.workflow.compensations[from]; exists {
+		panic("'AddCompensation(" + from.String() + ",' already exists. Only one compensation can be configured for the status")
+	}
+
+	b.workflow.compensations[from] = fn
+}
+
+func compensationConsumer[Type any, Status StatusType](w *Workflow[Type, Status]) {
+	role := makeRole(
+		w.roleName(),
+		"compensation",
+		"consumer",
+	)
+
+	processName := makeRole("compensation", "consumer")
+	w.run(role, processName, w.hookShutdownOrder(), func(ctx context.Context) error {
+		topic := RunStateChangeTopic(w.Name())
+		stream, err := w.eventStreamer.NewReceiver(
+			ctx,
+			topic,
+			role,
+			WithReceiverPollFrequency(w.defaultOpts.pollingFrequency),
+		)
+		if err != nil {
+			return err
+		}
+		defer stream.Close()
+
+		return consume(
+			ctx,
+			w.Name(),
+			processName,
+			stream,
+			w.versionGuard(runCompensations(
+				w.Name(),
+				w.recordStore.Lookup,
+				w.recordStore.Store,
+				w.codec,
+				w.compensations,
+				w.compensatesFailures(),
+				w.clock,
+				w.logger,
+			)),
+			w.clock,
+			0,
+			w.defaultOpts.lagAlert,
+			filterByCompensated(),
+			w.versionFilter(),
+		)
+	}, w.defaultOpts.errBackOff)
+}
+
+// compensatesFailures returns true when the runs that are paused after exceeding their PauseAfterErrCount have
+// failed terminally and so are compensated.
+func (w *Workflow[Type, Status]) compensatesFailures() bool {
+	return !w.pausedRecordsRetry.enabled || w.deadLetterStreamer != nil
+}
+
+// filterByCompensated filters out the run state change events of runs that are not cancelled or paused.
+func filterByCompensated() EventFilter {
+	return func(e *Event) bool {
+		rs, err := strconv.ParseInt(e.Headers[HeaderRunState], 10, 64)
+		if err != nil {
+			return true
+		}
+
+		return RunState(rs) != RunStateCancelled && RunState(rs) != RunStatePaused
+	}
+}
+
+// compensatedReason is the reason recorded when a run that failed terminally is cancelled after being compensated.
+const compensatedReason = "compensated after failing terminally"
+
+// compensatedFailure returns true when the run was cancelled after being compensated for failing terminally, and so
+// must not be compensated again.
+func compensatedFailure(history []Transition) bool {
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].ToRunState == RunStateCancelled {
+			return history[i].Reason == compensatedReason
+		}
+	}
+
+	return false
+}
+
+func runCompensations[Type any, Status StatusType](
+	workflowName string,
+	lookup lookupFunc,
+	store storeFunc,
+	codec Codec,
+	compensations map[Status]CompensationFunc[Type, Status],
+	compensateFailures bool,
+	clock clock.Clock,
+	logger *logger,
+) func(ctx context.Context, e *Event) error {
+	return func(ctx context.Context, e *Event) error {
+		record, err := lookup(ctx, e.ForeignID)
+		if err != nil {
+			return err
+		}
+
+		failed := e.Headers[HeaderRunState] == strconv.FormatInt(int64(RunStatePaused), 10)
+		if failed {
+			// Only runs that are still paused after failing are compensated, rather than runs that were paused by an
+			// operator or have since been resumed.
+			stillFailed := record.RunState == RunStatePaused && pausedError(record.Meta.History) != ""
+			if !compensateFailures || !stillFailed {
+				return nil
+			}
+		} else if compensatedFailure(record.Meta.History) {
+			return nil
+		}
+
+		var t Type
+		err = codec.Unmarshal(record.Object, &t)
+		if err != nil {
+			// The compensations are retried rather than skipped so that the effects of the run are not left in place.
+			return fmt.Errorf("compensation error: unmarshal object: %w, meta: %v", err, map[string]string{
+				"run_id":     record.RunID,
+				"foreign_id": record.ForeignID,
+			})
+		}
+
+		typed := &TypedRecord[Type, Status]{
+			Record: *record,
+			Status: Status(record.Status),
+			Object: &t,
+		}
+
+		history := slices.Clone(record.Meta.StepHistory)
+		slices.Reverse(history)
+		for _, status := range history {
+			fn, ok := compensations[Status(status)]
+			if !ok {
+				continue
+			}
+
+			err := fn(ctx, typed)
+			if err != nil {
+				return fmt.Errorf("compensation error: %v, meta: %v", err, map[string]string{
+					"run_id":     record.RunID,
+					"foreign_id": record.ForeignID,
+					"status":     Status(status).String(),
+				})
+			}
+
+			logger.event(ctx, DebugEvent{
+				Type:    DebugEventStepCompensated,
+				Message: "compensated step",
+				Meta: map[string]string{
+					"workflow_name": workflowName,
+					"run_id":        record.RunID,
+					"foreign_id":    record.ForeignID,
+					"status":        Status(status).String(),
+				},
+			})
+		}
+
+		if !failed {
+			return nil
+		}
+
+		// The run has been rolled back and so is cancelled, rather than left paused, so that neither Resume nor the
+		// retrying of paused runs processes it again.
+		record.UpdatedAt = clock.Now()
+		err = NewRunStateController(store, record).Cancel(withTransitionReason(ctx, compensatedReason))
+		if err != nil {
+			return fmt.Errorf("compensation error: cancel run: %w, meta: %v", err, map[string]string{
+				"run_id":     record.RunID,
+				"foreign_id": record.ForeignID,
+			})
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,312 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	clock_testing "k8s.io/utils/clock/testing"
+)
+
+func Test_runCompensations(t *testing.T) {
+	ctx := context.Background()
+
+	value := "data"
+	b, err := Marshal(&value)
+	require.Nil(t, err)
+
+	record := &Record{
+		WorkflowName: "example",
+		ForeignID:    "32948623984623",
+		RunID:        "JHFJDS-LSFKHJSLD-KSJDBLSL",
+		RunState:     RunStateCancelled,
+		Status:       int(statusEnd),
+		Object:       b,
+		Meta: RecordMeta{
+			StepHistory: []int{int(statusStart), int(statusMiddle)},
+		},
+	}
+
+	lookup := func(ctx context.Context, runID string) (*Record, error) {
+		return record, nil
+	}
+
+	t.Run("Compensations run in reverse order", func(t *testing.T) {
+		var called []testStatus
+		compensations := map[testStatus]CompensationFunc[string, testStatus]{
+			statusStart: func(ctx context.Context, r *TypedRecord[string, testStatus]) error {
+				called = append(called, statusStart)
+				return nil
+			},
+			statusMiddle: func(ctx context.Context, r *TypedRecord[string, testStatus]) error {
+				require.Equal(t, "data", *r.Object)
+				called = append(called, statusMiddle)
+				return nil
+			},
+		}
+
+		err := runCompensations(
+			"workflow_name",
+			lookup,
+			nil,
+			JSONCodec{},
+			compensations,
+			false,
+			clock_testing.NewFakeClock(time.Now()),
+			&logger{},
+		)(ctx, &Event{})
+		require.Nil(t, err)
+		require.Equal(t, []testStatus{statusMiddle, statusStart}, called)
+	})
+
+	t.Run("Steps without compensations are skipped", func(t *testing.T) {
+		var called []testStatus
+		compensations := map[testStatus]CompensationFunc[string, testStatus]{
+			statusStart: func(ctx context.Context, r *TypedRecord[string, testStatus]) error {
+				called = append(called, statusStart)
+				return nil
+			},
+		}
+
+		err := runCompensations(
+			"workflow_name",
+			lookup,
+			nil,
+			JSONCodec{},
+			compensations,
+			false,
+			clock_testing.NewFakeClock(time.Now()),
+			&logger{},
+		)(ctx, &Event{})
+		require.Nil(t, err)
+		require.Equal(t, []testStatus{statusStart}, called)
+	})
+
+	t.Run("Return error if compensation errors", func(t *testing.T) {
+		testErr := errors.New("test error")
+		compensations := map[testStatus]CompensationFunc[string, testStatus]{
+			statusStart: func(ctx context.Context, r *TypedRecord[string, testStatus]) error {
+				return testErr
+			},
+		}
+
+		err := runCompensations(
+			"workflow_name",
+			lookup,
+			nil,
+			JSONCodec{},
+			compensations,
+			false,
+			clock_testing.NewFakeClock(time.Now()),
+			&logger{},
+		)(ctx, &Event{})
+		require.ErrorContains(t, err, testErr.Error())
+	})
+}
+
+func Test_runCompensations_Paused(t *testing.T) {
+	ctx := context.Background()
+
+	value := "data"
+	b, err := Marshal(&value)
+	require.Nil(t, err)
+
+	pausedEvent := &Event{
+		Headers: map[Header]string{
+			HeaderRunState: strconv.FormatInt(int64(RunStatePaused), 10),
+		},
+	}
+
+	newRecord := func(history ...Transition) *Record {
+		return &Record{
+			WorkflowName: "example",
+			ForeignID:    "32948623984623",
+			RunID:        "JHFJDS-LSFKHJSLD-KSJDBLSL",
+			RunState:     RunStatePaused,
+			Status:       int(statusMiddle),
+			Object:       b,
+			Meta: RecordMeta{
+				StepHistory: []int{int(statusStart)},
+				History:     history,
+			},
+		}
+	}
+
+	failed := Transition{
+		FromStatus:   int(statusMiddle),
+		ToStatus:     int(statusMiddle),
+		FromRunState: RunStateRunning,
+		ToRunState:   RunStatePaused,
+		Error:        "provider unavailable",
+	}
+
+	testCases := []struct {
+		name               string
+		record             *Record
+		compensateFailures bool
+		expected           []testStatus
+		expectedRunState   RunState
+	}{
+		{
+			name:               "Runs that failed terminally are compensated and cancelled",
+			record:             newRecord(failed),
+			compensateFailures: true,
+			expected:           []testStatus{statusStart},
+			expectedRunState:   RunStateCancelled,
+		},
+		{
+			name:               "Runs that are resumed automatically are not compensated",
+			record:             newRecord(failed),
+			compensateFailures: false,
+			expectedRunState:   RunStatePaused,
+		},
+		{
+			name: "Runs paused without an error are not compensated",
+			record: newRecord(Transition{
+				FromStatus:   int(statusMiddle),
+				ToStatus:     int(statusMiddle),
+				FromRunState: RunStateRunning,
+				ToRunState:   RunStatePaused,
+				Actor:        "operator",
+			}),
+			compensateFailures: true,
+			expectedRunState:   RunStatePaused,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var called []testStatus
+			compensations := map[testStatus]CompensationFunc[string, testStatus]{
+				statusStart: func(ctx context.Context, r *TypedRecord[string, testStatus]) error {
+					called = append(called, statusStart)
+					return nil
+				},
+			}
+
+			lookup := func(ctx context.Context, runID string) (*Record, error) {
+				return tc.record, nil
+			}
+
+			store := func(ctx context.Context, record *Record) error {
+				tc.record = record
+				return nil
+			}
+
+			err := runCompensations(
+				"workflow_name",
+				lookup,
+				store,
+				JSONCodec{},
+				compensations,
+				tc.compensateFailures,
+				clock_testing.NewFakeClock(time.Now()),
+				&logger{},
+			)(ctx, pausedEvent)
+			require.Nil(t, err)
+			require.Equal(t, tc.expected, called)
+			require.Equal(t, tc.expectedRunState, tc.record.RunState)
+		})
+	}
+}
+
+func Test_runCompensations_CancelledAfterFailing(t *testing.T) {
+	value := "data"
+	b, err := Marshal(&value)
+	require.Nil(t, err)
+
+	record := &Record{
+		WorkflowName: "example",
+		RunID:        "JHFJDS-LSFKHJSLD-KSJDBLSL",
+		RunState:     RunStateCancelled,
+		Status:       int(statusMiddle),
+		Object:       b,
+		Meta: RecordMeta{
+			StepHistory: []int{int(statusStart)},
+			History: []Transition{
+				{FromRunState: RunStateRunning, ToRunState: RunStatePaused, Error: "provider unavailable"},
+				{FromRunState: RunStatePaused, ToRunState: RunStateCancelled, Reason: compensatedReason},
+			},
+		},
+	}
+
+	lookup := func(ctx context.Context, runID string) (*Record, error) {
+		return record, nil
+	}
+
+	compensations := map[testStatus]CompensationFunc[string, testStatus]{
+		statusStart: func(ctx context.Context, r *TypedRecord[string, testStatus]) error {
+			t.Fatal("run was compensated twice")
+			return nil
+		},
+	}
+
+	cancelledEvent := &Event{
+		Headers: map[Header]string{
+			HeaderRunState: strconv.FormatInt(int64(RunStateCancelled), 10),
+		},
+	}
+
+	err = runCompensations(
+		"workflow_name",
+		lookup,
+		nil,
+		JSONCodec{},
+		compensations,
+		true,
+		clock_testing.NewFakeClock(time.Now()),
+		&logger{},
+	)(context.Background(), cancelledEvent)
+	require.Nil(t, err)
+}
+
+func Test_runCompensations_UnmarshalError(t *testing.T) {
+	record := &Record{
+		WorkflowName: "example",
+		RunID:        "JHFJDS-LSFKHJSLD-KSJDBLSL",
+		RunState:     RunStateCancelled,
+		Object:       []byte("not json"),
+		Meta: RecordMeta{
+			StepHistory: []int{int(statusStart)},
+		},
+	}
+
+	lookup := func(ctx context.Context, runID string) (*Record, error) {
+		return record, nil
+	}
+
+	compensations := map[testStatus]CompensationFunc[string, testStatus]{
+		statusStart: func(ctx context.Context, r *TypedRecord[string, testStatus]) error {
+			return nil
+		},
+	}
+
+	err := runCompensations(
+		"workflow_name",
+		lookup,
+		nil,
+		JSONCodec{},
+		compensations,
+		false,
+		clock_testing.NewFakeClock(time.Now()),
+		&logger{},
+	)(context.Background(), &Event{})
+	require.ErrorContains(t, err, "unmarshal object")
+}
+
+func TestAddCompensationSingleUseValidation(t *testing.T) {
+	b := NewBuilder[string, testStatus]("compensation")
+	fn := func(ctx context.Context, r *TypedRecord[string, testStatus]) error {
+		return nil
+	}
+	b.AddCompensation(statusStart, fn)
+
+	require.PanicsWithValue(t,
+		"'AddCompensation("+statusStart.String()+",' already exists. Only one compensation can be configured for the status",
+		func() {
+			b.AddCompensation(statusStart, fn)
+		})
+}
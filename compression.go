@@ -0,0 +1,199 @@
+package workflow
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
+)
+
+// compressedObjectPrefix marks the Object of runs that have been compressed using WithCompression and is followed by
+// the name of the Compressor. None of the codecs encode the Object of a run, which is always a struct, starting with a
+// zero byte.
+var compressedObjectPrefix = []byte("\x00wfzip1")
+
+// Compressor compresses the encoded Object of a workflow's runs, see WithCompression.
+type Compressor interface {
+	// Name identifies the compression algorithm, such as "gzip", and is stored with each compressed Object so that
+	// Objects compressed with a different algorithm are detected.
+	Name() string
+	Compress(data []byte) ([]byte, error)
+	Decompress(data []byte) ([]byte, error)
+}
+
+// WithCompression compresses the Object of the workflow's runs, after it has been encoded by the workflow's Codec, so
+// that workflows with large Objects use less storage. Compressed Objects are flagged with the name of the Compressor
+// so that Objects stored before compression was enabled are still decoded and are compressed the next time that they
+// are stored. GzipCompressor, ZstdCompressor, and SnappyCompressor are provided and other algorithms can be used by
+// implementing Compressor. Objects are compressed before being encrypted when WithEncryption is also used.
+func WithCompression(c Compressor) BuildOption {
+	return func(bo *buildOptions) {
+		bo.compressor = c
+	}
+}
+
+// GzipCompressor compresses Objects using gzip. Level is one of the compress/gzip levels and the zero value uses
+// gzip.DefaultCompression.
+type GzipCompressor struct {
+	Level int
+}
+
+func (c GzipCompressor) Name() string {
+	return "gzip"
+}
+
+func (c GzipCompressor) Compress(data []byte) ([]byte, error) {
+	level := c.Level
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, level)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = zw.Write(data)
+	if err != nil {
+		return nil, err
+	}
+
+	err = zw.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (c GzipCompressor) Decompress(data []byte) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	return io.ReadAll(zr)
+}
+
+var _ Compressor = (*GzipCompressor)(nil)
+
+// ZstdCompressor compresses Objects using zstd. Level is one of the zstd levels, from 1 to 22, which is mapped onto
+// the closest level that is supported and the zero value uses the default level.
+type ZstdCompressor struct {
+	Level int
+}
+
+func (c ZstdCompressor) Name() string {
+	return "zstd"
+}
+
+func (c ZstdCompressor) Compress(data []byte) ([]byte, error) {
+	level := zstd.SpeedDefault
+	if c.Level != 0 {
+		level = zstd.EncoderLevelFromZstd(c.Level)
+	}
+
+	zw, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(level), zstd.WithEncoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+	defer zw.Close()
+
+	return zw.EncodeAll(data, nil), nil
+}
+
+func (c ZstdCompressor) Decompress(data []byte) ([]byte, error) {
+	zr, err := zstd.NewReader(nil, zstd.WithDecoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	return zr.DecodeAll(data, nil)
+}
+
+var _ Compressor = (*ZstdCompressor)(nil)
+
+// SnappyCompressor compresses Objects using the snappy block format which is faster, but compresses less, than gzip
+// and zstd.
+type SnappyCompressor struct{}
+
+func (c SnappyCompressor) Name() string {
+	return "snappy"
+}
+
+func (c SnappyCompressor) Compress(data []byte) ([]byte, error) {
+	return s2.EncodeSnappy(nil, data), nil
+}
+
+func (c SnappyCompressor) Decompress(data []byte) ([]byte, error) {
+	// Snappy blocks are a subset of S2 blocks and so are decoded by S2.
+	return s2.Decode(nil, data)
+}
+
+var _ Compressor = (*SnappyCompressor)(nil)
+
+// compressedCodec compresses the Objects encoded by its Codec.
+type compressedCodec struct {
+	codec      Codec
+	compressor Compressor
+}
+
+func (c *compressedCodec) Marshal(v any) ([]byte, error) {
+	data, err := c.codec.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	compressed, err := c.compressor.Compress(data)
+	if err != nil {
+		return nil, fmt.Errorf("compress object: %w", err)
+	}
+
+	name := c.compressor.Name()
+	object := bytes.Clone(compressedObjectPrefix)
+	object = binary.AppendUvarint(object, uint64(len(name)))
+	object = append(object, name...)
+	return append(object, compressed...), nil
+}
+
+func (c *compressedCodec) Unmarshal(data []byte, v any) error {
+	if !bytes.HasPrefix(data, compressedObjectPrefix) {
+		// The Object was stored before compression was enabled.
+		return c.codec.Unmarshal(data, v)
+	}
+
+	data = data[len(compressedObjectPrefix):]
+	n, read := binary.Uvarint(data)
+	if read <= 0 || uint64(len(data)-read) < n {
+		return errors.New("decompress object: invalid header")
+	}
+
+	name := string(data[read : read+int(n)])
+	if name != c.compressor.Name() {
+		return fmt.Errorf("decompress object: unknown compressor, meta: %v", map[string]string{
+			"compressor":            name,
+			"configured_compressor": c.compressor.Name(),
+		})
+	}
+
+	decompressed, err := c.compressor.Decompress(data[read+int(n):])
+	if err != nil {
+		return fmt.Errorf("decompress object: %w", err)
+	}
+
+	return c.codec.Unmarshal(decompressed, v)
+}
+
+func (c *compressedCodec) ContentType() string {
+	return "application/octet-stream"
+}
+
+var _ Codec = (*compressedCodec)(nil)
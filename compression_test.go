@@ -0,0 +1,195 @@
+package workflow_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+// renamedCompressor is a GzipCompressor that flags its Objects with a different name.
+type renamedCompressor struct {
+	workflow.GzipCompressor
+}
+
+func (renamedCompressor) Name() string {
+	return "renamed"
+}
+
+func TestWithCompression(t *testing.T) {
+	ctx := context.Background()
+	value := strings.Repeat("a large json blob ", 1000)
+
+	testCases := []struct {
+		name string
+		opts []workflow.BuildOption
+	}{
+		{
+			name: "Compression",
+			opts: []workflow.BuildOption{
+				workflow.WithCompression(workflow.GzipCompressor{}),
+			},
+		},
+		{
+			name: "Zstd compression",
+			opts: []workflow.BuildOption{
+				workflow.WithCompression(workflow.ZstdCompressor{}),
+			},
+		},
+		{
+			name: "Snappy compression",
+			opts: []workflow.BuildOption{
+				workflow.WithCompression(workflow.SnappyCompressor{}),
+			},
+		},
+		{
+			name: "Compression with encryption",
+			opts: []workflow.BuildOption{
+				workflow.WithCompression(workflow.GzipCompressor{}),
+				workflow.WithEncryption(workflow.StaticKeyProvider{
+					CurrentID: "key-1",
+					Keys: map[string][]byte{
+						"key-1": bytes.Repeat([]byte{1}, 32),
+					},
+				}),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recordStore := memrecordstore.New()
+			client := workflow.NewClient[string, status]("compression", memstreamer.New(), recordStore, tc.opts...)
+
+			runID, err := client.Trigger(ctx, "foreignID", StatusStart, workflow.WithInitialValue[string, status](&value))
+			require.Nil(t, err)
+
+			record, err := recordStore.Lookup(ctx, runID)
+			require.Nil(t, err)
+			require.Less(t, len(record.Object), len(value)/10)
+
+			r, err := client.GetRun(ctx, "foreignID", runID)
+			require.Nil(t, err)
+			require.Equal(t, value, *r.Object)
+		})
+	}
+}
+
+func TestCompressors(t *testing.T) {
+	data := []byte(strings.Repeat("a large json blob ", 1000))
+
+	compressors := []workflow.Compressor{
+		workflow.GzipCompressor{},
+		workflow.GzipCompressor{Level: 9},
+		workflow.ZstdCompressor{},
+		workflow.ZstdCompressor{Level: 19},
+		workflow.SnappyCompressor{},
+	}
+
+	names := make(map[string]bool)
+	for _, c := range compressors {
+		names[c.Name()] = true
+
+		compressed, err := c.Compress(data)
+		require.Nil(t, err, c.Name())
+		require.Less(t, len(compressed), len(data)/10, c.Name())
+
+		decompressed, err := c.Decompress(compressed)
+		require.Nil(t, err, c.Name())
+		require.Equal(t, data, decompressed, c.Name())
+	}
+
+	// Each algorithm flags its Objects with its own name.
+	require.Len(t, names, 3)
+}
+
+func TestWithCompression_MixedObjects(t *testing.T) {
+	ctx := context.Background()
+	recordStore := memrecordstore.New()
+	streamer := memstreamer.New()
+
+	value := "stored before compression"
+	uncompressed := workflow.NewClient[string, status]("mixed", streamer, recordStore)
+	oldRunID, err := uncompressed.Trigger(ctx, "old", StatusStart, workflow.WithInitialValue[string, status](&value))
+	require.Nil(t, err)
+
+	compressed := workflow.NewClient[string, status]("mixed", streamer, recordStore,
+		workflow.WithCompression(workflow.GzipCompressor{}),
+	)
+	newRunID, err := compressed.Trigger(ctx, "new", StatusStart, workflow.WithInitialValue[string, status](&value))
+	require.Nil(t, err)
+
+	for foreignID, runID := range map[string]string{"old": oldRunID, "new": newRunID} {
+		r, err := compressed.GetRun(ctx, foreignID, runID)
+		require.Nil(t, err)
+		require.Equal(t, value, *r.Object)
+	}
+
+	renamed := workflow.NewClient[string, status]("mixed", streamer, recordStore,
+		workflow.WithCompression(renamedCompressor{}),
+	)
+	_, err = renamed.GetRun(ctx, "new", newRunID)
+	require.ErrorContains(t, err, "unknown compressor")
+}
+
+func TestWithCompression_Redact(t *testing.T) {
+	ctx := context.Background()
+	object := MyType{UserID: 9, Email: "andrew@workflow.com"}
+
+	testCases := []struct {
+		name     string
+		opts     []workflow.BuildOption
+		expected MyType
+	}{
+		{
+			name: "Redacts decompressed object",
+			opts: []workflow.BuildOption{
+				workflow.WithRedaction(func(object *MyType) error {
+					object.Email = "****"
+					return nil
+				}),
+			},
+			expected: MyType{UserID: 9, Email: "****"},
+		},
+		{
+			name:     "Returns decompressed object when no redaction is configured",
+			expected: object,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := workflow.NewBuilder[MyType, status]("compression redaction")
+			b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+				return StatusEnd, nil
+			}, StatusEnd)
+
+			recordStore := memrecordstore.New()
+			opts := append([]workflow.BuildOption{workflow.WithCompression(workflow.GzipCompressor{})}, tc.opts...)
+			wf := b.Build(memstreamer.New(), recordStore, memrolescheduler.New(), opts...)
+			wf.Run(ctx)
+			t.Cleanup(wf.Stop)
+
+			runID, err := wf.Trigger(ctx, "foreignID", StatusStart, workflow.WithInitialValue[MyType, status](&object))
+			require.Nil(t, err)
+
+			record, err := recordStore.Lookup(ctx, runID)
+			require.Nil(t, err)
+
+			redacted, err := wf.Redact(record)
+			require.Nil(t, err)
+
+			var actual MyType
+			err = workflow.Unmarshal(redacted, &actual)
+			require.Nil(t, err)
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
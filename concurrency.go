@@ -0,0 +1,62 @@
+package workflow
+
+import (
+	"context"
+	"strconv"
+)
+
+// ConcurrencyKeyFunc returns the key of the resource, such as a merchant account, that the run uses in the step.
+type ConcurrencyKeyFunc[Type any, Status StatusType] func(r *Run[Type, Status]) string
+
+type concurrencyKey[Type any, Status StatusType] struct {
+	key   ConcurrencyKeyFunc[Type, Status]
+	limit int
+}
+
+// WithConcurrencyKey limits the step to processing at most limit runs that share the same key at the same time across
+// all the shards of the step and all the instances of the workflow. Each key has limit slots that are held using the
+// workflow's LockStore, see WithLockStore, and a run waits for one of the slots before the step is called. The context
+// provided to the step is cancelled if the slot is lost. Runs that are waiting for a slot block the shard that is
+// processing them and so the ParallelCount of the step should account for the number of keys that are expected to be
+// busy at once. Concurrency keys are not supported by batch steps.
+func (s *stepUpdater[Type, Status]) WithConcurrencyKey(
+	key ConcurrencyKeyFunc[Type, Status],
+	limit int,
+) *stepUpdater[Type, Status] {
+	consumer := s.workflow.consumers[s.from]
+	consumer.concurrencyKey = &concurrencyKey[Type, Status]{
+		key:   key,
+		limit: limit,
+	}
+	s.workflow.consumers[s.from] = consumer
+	return s
+}
+
+// concurrencyKeyConsumer holds a slot of the run's key, using the LockStore, whilst calling the consumer.
+func concurrencyKeyConsumer[Type any, Status StatusType](
+	w *Workflow[Type, Status],
+	currentStatus Status,
+	p consumerConfig[Type, Status],
+	consumer ConsumerFunc[Type, Status],
+) ConsumerFunc[Type, Status] {
+	if p.concurrencyKey == nil {
+		return consumer
+	}
+
+	return func(ctx context.Context, r *Run[Type, Status]) (Status, error) {
+		key := makeRole(
+			w.roleName(),
+			strconv.FormatInt(int64(currentStatus), 10),
+			"concurrency",
+			p.concurrencyKey.key(r),
+		)
+
+		ctx, cancel, err := w.lockStore.Acquire(ctx, key, p.concurrencyKey.limit)
+		if err != nil {
+			return 0, err
+		}
+		defer cancel()
+
+		return consumer(ctx, r)
+	}
+}
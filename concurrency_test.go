@@ -0,0 +1,77 @@
+package workflow_test
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestWithConcurrencyKey(t *testing.T) {
+	var (
+		inFlight    atomic.Int64
+		maxInFlight atomic.Int64
+		calls       atomic.Int64
+	)
+
+	b := workflow.NewBuilder[string, status]("concurrency key")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		calls.Add(1)
+		return StatusEnd, nil
+	}, StatusEnd).WithConcurrencyKey(func(r *workflow.Run[string, status]) string {
+		return *r.Object
+	}, 1).WithOptions(
+		workflow.ParallelCount(4),
+	)
+
+	wf := b.Build(memstreamer.New(), memrecordstore.New(), memrolescheduler.New())
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	merchant := "merchant"
+	for i := 0; i < 8; i++ {
+		_, err := wf.Trigger(ctx, strconv.Itoa(i), StatusStart, workflow.WithInitialValue[string, status](&merchant))
+		require.Nil(t, err)
+	}
+
+	require.Eventually(t, func() bool {
+		return calls.Load() == 8
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Runs that share the same key never run the step at the same time across the shards of the step.
+	require.Equal(t, int64(1), maxInFlight.Load())
+}
+
+func TestWithConcurrencyKeyValidation(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("concurrency key")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd).WithConcurrencyKey(func(r *workflow.Run[string, status]) string {
+		return *r.Object
+	}, 0)
+
+	require.PanicsWithValue(t, "'AddStep(Start,' concurrency key requires a positive limit", func() {
+		b.Build(memstreamer.New(), memrecordstore.New(), memrolescheduler.New())
+	})
+}
@@ -25,11 +25,13 @@ type connectorConfig[Type any, Status StatusType] struct {
 	name        string
 	constructor ConnectorConstructor
 	connectorFn ConnectorFunc[Type, Status]
+	control     *connectorControl
 
 	errBackOff    time.Duration
 	parallelCount int
 	lag           time.Duration
 	lagAlert      time.Duration
+	dedupWindow   time.Duration
 }
 
 func connectorConsumer[Type any, Status StatusType](
@@ -37,16 +39,7 @@ func connectorConsumer[Type any, Status StatusType](
 	config *connectorConfig[Type, Status],
 	shard, totalShards int,
 ) {
-	role := makeRole(
-		config.name,
-		"connector",
-		"to",
-		w.Name(),
-		"consumer",
-		strconv.FormatInt(int64(shard), 10),
-		"of",
-		strconv.FormatInt(int64(totalShards), 10),
-	)
+	role := connectorConsumerRole(w, config, shard, totalShards)
 
 	errBackOff := w.defaultOpts.errBackOff
 	if config.errBackOff > 0 {
@@ -66,7 +59,12 @@ func connectorConsumer[Type any, Status StatusType](
 	// processName can have the same name as the role. It is the same here due to the fact that there are no enums
 	// that can be converted to a meaningful string
 	processName := role
-	w.run(role, processName, func(ctx context.Context) error {
+	var api API[Type, Status] = w
+	if config.dedupWindow > 0 {
+		api = dedupAPI[Type, Status]{API: w, window: config.dedupWindow}
+	}
+
+	w.run(role, processName, shutdownOrderFirst, func(ctx context.Context) error {
 		consumer, err := config.constructor.Make(ctx, role)
 		if err != nil {
 			return err
@@ -77,14 +75,24 @@ func connectorConsumer[Type any, Status StatusType](
 			ctx,
 			w.Name(),
 			processName,
-			newConnectorStreamer(consumer),
+			pausableReceiver{
+				EventReceiver: newConnectorStreamer(consumer),
+				control:       config.control,
+				onPause: func(paused bool) {
+					if paused {
+						w.updateState(processName, StatePaused)
+					} else {
+						w.updateState(processName, StateRunning)
+					}
+				},
+			},
 			func(ctx context.Context, e *Event) error {
 				ce, err := streamerEventToConnectorEvent(e)
 				if err != nil {
 					return err
 				}
 
-				return config.connectorFn(ctx, w, ce)
+				return config.connectorFn(ctx, api, ce)
 			},
 			w.clock,
 			lag,
@@ -94,6 +102,34 @@ func connectorConsumer[Type any, Status StatusType](
 	}, errBackOff)
 }
 
+// connectorConsumerRole is the role of the connector's shard which is also the name its consumer is made with.
+func connectorConsumerRole[Type any, Status StatusType](
+	w *Workflow[Type, Status],
+	config *connectorConfig[Type, Status],
+	shard, totalShards int,
+) string {
+	return makeRole(
+		config.name,
+		"connector",
+		"to",
+		w.Name(),
+		"consumer",
+		strconv.FormatInt(int64(shard), 10),
+		"of",
+		strconv.FormatInt(int64(totalShards), 10),
+	)
+}
+
+// connectorShards returns the number of consumers that the connector is sharded across.
+func (w *Workflow[Type, Status]) connectorShards(config *connectorConfig[Type, Status]) int {
+	parallelCount := w.defaultOpts.parallelCount
+	if config.parallelCount != 0 {
+		parallelCount = config.parallelCount
+	}
+
+	return max(parallelCount, 1)
+}
+
 type connectorStreamer struct {
 	hasher   hash.Hash64
 	consumer ConnectorConsumer
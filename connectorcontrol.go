@@ -0,0 +1,189 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// ErrConnectorNotFound is returned when controlling a connector that has not been added to the workflow.
+var ErrConnectorNotFound = errors.New("connector not found")
+
+// ConnectorCursorManager can optionally be implemented by a ConnectorConstructor to allow the cursors of a
+// connector's consumers to be reset, such as to replay an upstream feed, using ResetConnectorCursors.
+type ConnectorCursorManager interface {
+	// ResetConnectorCursor moves the cursor of the consumer, made using the consumer name, to the position.
+	ResetConnectorCursor(ctx context.Context, consumerName string, position CursorPosition) error
+}
+
+// PauseConnector stops the connector's consumers from receiving events until ResumeConnector is called, such as to
+// halt a misbehaving upstream feed without stopping the workflow. Connectors are paused on this instance only and so
+// the connector should be paused on every instance that runs the workflow.
+func (w *Workflow[Type, Status]) PauseConnector(name string) error {
+	config, err := w.connectorConfig(name)
+	if err != nil {
+		return err
+	}
+
+	config.control.pause()
+	return nil
+}
+
+// ResumeConnector resumes a connector that was paused using PauseConnector.
+func (w *Workflow[Type, Status]) ResumeConnector(name string) error {
+	config, err := w.connectorConfig(name)
+	if err != nil {
+		return err
+	}
+
+	config.control.resume()
+	return nil
+}
+
+// ConnectorPaused returns true when the connector has been paused using PauseConnector.
+func (w *Workflow[Type, Status]) ConnectorPaused(name string) (bool, error) {
+	config, err := w.connectorConfig(name)
+	if err != nil {
+		return false, err
+	}
+
+	return config.control.isPaused(), nil
+}
+
+// ResetConnectorCursors moves the cursors of all the connector's consumers to the position so that the connector
+// replays, or skips, the upstream feed. ErrCursorsNotSupported is returned when the connector's ConnectorConstructor
+// does not implement ConnectorCursorManager. The connector should be paused before resetting its cursors as a consumer
+// can acknowledge an event that it received before the reset and move the cursor back.
+func (w *Workflow[Type, Status]) ResetConnectorCursors(ctx context.Context, name string, position CursorPosition) error {
+	config, err := w.connectorConfig(name)
+	if err != nil {
+		return err
+	}
+
+	manager, ok := config.constructor.(ConnectorCursorManager)
+	if !ok {
+		return ErrCursorsNotSupported
+	}
+
+	totalShards := w.connectorShards(config)
+	for shard := 1; shard <= totalShards; shard++ {
+		consumerName := connectorConsumerRole(w, config, shard, totalShards)
+		err := manager.ResetConnectorCursor(ctx, consumerName, position)
+		if err != nil {
+			return fmt.Errorf("reset connector cursor: %w, meta: %v", err, map[string]string{
+				"connector":     name,
+				"consumer":      consumerName,
+				"position_type": strconv.Itoa(int(position.Type)),
+			})
+		}
+	}
+
+	return nil
+}
+
+func (w *Workflow[Type, Status]) connectorConfig(name string) (*connectorConfig[Type, Status], error) {
+	for _, config := range w.connectorConfigs {
+		if config.name == name {
+			return config, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w, meta: %v", ErrConnectorNotFound, map[string]string{
+		"connector": name,
+	})
+}
+
+// connectorControl pauses and resumes the consumers of a connector.
+type connectorControl struct {
+	mu      sync.Mutex
+	paused  bool
+	resumed chan struct{}
+}
+
+func newConnectorControl() *connectorControl {
+	return &connectorControl{
+		resumed: make(chan struct{}),
+	}
+}
+
+func (c *connectorControl) pause() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.paused {
+		return
+	}
+
+	c.paused = true
+	c.resumed = make(chan struct{})
+}
+
+func (c *connectorControl) resume() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.paused {
+		return
+	}
+
+	c.paused = false
+	close(c.resumed)
+}
+
+func (c *connectorControl) isPaused() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.paused
+}
+
+// wait blocks whilst the connector is paused and calls onPause before, and after, waiting.
+func (c *connectorControl) wait(ctx context.Context, onPause func(paused bool)) error {
+	c.mu.Lock()
+	paused, resumed := c.paused, c.resumed
+	c.mu.Unlock()
+
+	if !paused {
+		return nil
+	}
+
+	onPause(true)
+	defer onPause(false)
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-resumed:
+		return nil
+	}
+}
+
+// pausableReceiver only receives events whilst its connector is not paused. An event that was being received when
+// the connector was paused is held until the connector is resumed.
+type pausableReceiver struct {
+	EventReceiver
+
+	control *connectorControl
+	onPause func(paused bool)
+}
+
+func (p pausableReceiver) Recv(ctx context.Context) (*Event, Ack, error) {
+	err := p.control.wait(ctx, p.onPause)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	e, ack, err := p.EventReceiver.Recv(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = p.control.wait(ctx, p.onPause)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return e, ack, nil
+}
@@ -0,0 +1,98 @@
+package workflow_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestConnectorControls(t *testing.T) {
+	events := []workflow.ConnectorEvent{
+		{ID: "1", ForeignID: "a"},
+		{ID: "2", ForeignID: "b"},
+	}
+
+	var (
+		mu       sync.Mutex
+		received []string
+	)
+	receivedIDs := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), received...)
+	}
+
+	b := workflow.NewBuilder[string, status]("connector-controls")
+	b.AddConnector(
+		"feed",
+		memstreamer.NewConnector(events),
+		func(ctx context.Context, api workflow.API[string, status], e *workflow.ConnectorEvent) error {
+			mu.Lock()
+			defer mu.Unlock()
+			received = append(received, e.ID)
+			return nil
+		},
+	)
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+	)
+
+	err := wf.PauseConnector("feed")
+	require.Nil(t, err)
+
+	paused, err := wf.ConnectorPaused("feed")
+	require.Nil(t, err)
+	require.True(t, paused)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	processName := "feed-connector-to-connector-controls-consumer-1-of-1"
+	require.Eventually(t, func() bool {
+		return wf.States()[processName] == workflow.StatePaused
+	}, 5*time.Second, 10*time.Millisecond)
+
+	time.Sleep(100 * time.Millisecond)
+	require.Empty(t, receivedIDs())
+
+	err = wf.ResumeConnector("feed")
+	require.Nil(t, err)
+
+	require.Eventually(t, func() bool {
+		return len(receivedIDs()) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, workflow.StateRunning, wf.States()[processName])
+
+	// Rewinding the paused connector's cursor replays the feed once it is resumed.
+	err = wf.PauseConnector("feed")
+	require.Nil(t, err)
+
+	err = wf.ResetConnectorCursors(ctx, "feed", workflow.CursorAtEarliest())
+	require.Nil(t, err)
+
+	err = wf.ResumeConnector("feed")
+	require.Nil(t, err)
+
+	require.Eventually(t, func() bool {
+		return len(receivedIDs()) == 4
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, []string{"1", "2", "1", "2"}, receivedIDs())
+
+	err = wf.PauseConnector("unknown")
+	require.ErrorIs(t, err, workflow.ErrConnectorNotFound)
+}
@@ -0,0 +1,75 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ConnectorEnvelope is the adapter agnostic view of a ConnectorEvent with its Payload decoded into Value.
+type ConnectorEnvelope[Value any] struct {
+	ID     string
+	Source string
+	// Key is the ForeignID of the ConnectorEvent.
+	Key       string
+	Type      string
+	Timestamp time.Time
+	Headers   map[string]string
+	// Payload is the raw body of the event.
+	Payload []byte
+	// Value is the Payload decoded using the codec provided to DecodeConnectorEvents. Value is nil when the event
+	// has no Payload.
+	Value *Value
+}
+
+// TypedConnectorFunc is the ConnectorFunc of connectors that use DecodeConnectorEvents.
+type TypedConnectorFunc[Type any, Status StatusType, Value any] func(
+	ctx context.Context,
+	api API[Type, Status],
+	e *ConnectorEnvelope[Value],
+) error
+
+// DecodeConnectorEvents returns a ConnectorFunc that provides fn with each ConnectorEvent wrapped in a
+// ConnectorEnvelope, with the event's Payload decoded using the codec. JSONCodec is used when codec is nil. Events
+// whose Payload cannot be decoded return an error and are retried like any other error from a ConnectorFunc.
+//
+//	b.AddConnector("orders", constructor, workflow.DecodeConnectorEvents(nil,
+//		func(ctx context.Context, api workflow.API[Order, Status], e *workflow.ConnectorEnvelope[OrderPlaced]) error {
+//			...
+//		},
+//	))
+func DecodeConnectorEvents[Type any, Status StatusType, Value any](
+	codec Codec,
+	fn TypedConnectorFunc[Type, Status, Value],
+) ConnectorFunc[Type, Status] {
+	if codec == nil {
+		codec = JSONCodec{}
+	}
+
+	return func(ctx context.Context, api API[Type, Status], e *ConnectorEvent) error {
+		envelope := ConnectorEnvelope[Value]{
+			ID:        e.ID,
+			Source:    e.Source,
+			Key:       e.ForeignID,
+			Type:      e.Type,
+			Timestamp: e.CreatedAt,
+			Headers:   e.Headers,
+			Payload:   e.Payload,
+		}
+
+		if len(e.Payload) > 0 {
+			var v Value
+			err := codec.Unmarshal(e.Payload, &v)
+			if err != nil {
+				return fmt.Errorf("decode connector event: %w, meta: %v", err, map[string]string{
+					"event_id": e.ID,
+					"source":   e.Source,
+				})
+			}
+
+			envelope.Value = &v
+		}
+
+		return fn(ctx, api, &envelope)
+	}
+}
@@ -0,0 +1,113 @@
+package workflow_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestDecodeConnectorEvents(t *testing.T) {
+	type orderPlaced struct {
+		Amount int `json:"amount"`
+	}
+
+	type order struct {
+		Amount int
+		Source string
+		Region string
+	}
+
+	events := []workflow.ConnectorEvent{
+		{
+			ID:        "1",
+			ForeignID: "order-1",
+			Source:    "orders",
+			Headers:   map[string]string{"region": "eu"},
+			Payload:   []byte(`{"amount": 100}`),
+		},
+	}
+
+	b := workflow.NewBuilder[order, status]("decode")
+	b.AddConnector(
+		"orders",
+		memstreamer.NewConnector(events),
+		workflow.DecodeConnectorEvents(nil,
+			func(ctx context.Context, api workflow.API[order, status], e *workflow.ConnectorEnvelope[orderPlaced]) error {
+				_, err := api.Trigger(ctx, e.Key, StatusStart, workflow.WithInitialValue[order, status](&order{
+					Amount: e.Value.Amount,
+					Source: e.Source,
+					Region: e.Headers["region"],
+				}))
+				return err
+			},
+		),
+	)
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[order, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	workflow.Require(t, wf, "order-1", StatusEnd, order{
+		Amount: 100,
+		Source: "orders",
+		Region: "eu",
+	})
+}
+
+func TestDecodeConnectorEvents_envelope(t *testing.T) {
+	type payload struct {
+		Name string `json:"name"`
+	}
+
+	var received *workflow.ConnectorEnvelope[payload]
+	fn := workflow.DecodeConnectorEvents(nil,
+		func(ctx context.Context, api workflow.API[string, status], e *workflow.ConnectorEnvelope[payload]) error {
+			received = e
+			return nil
+		},
+	)
+
+	createdAt := time.Date(2024, time.April, 19, 23, 0, 0, 0, time.UTC)
+	err := fn(context.Background(), nil, &workflow.ConnectorEvent{
+		ID:        "1",
+		ForeignID: "key",
+		Type:      "created",
+		CreatedAt: createdAt,
+		Source:    "users",
+		Payload:   []byte(`{"name": "Andrew"}`),
+	})
+	require.Nil(t, err)
+	require.Equal(t, &workflow.ConnectorEnvelope[payload]{
+		ID:        "1",
+		Source:    "users",
+		Key:       "key",
+		Type:      "created",
+		Timestamp: createdAt,
+		Payload:   []byte(`{"name": "Andrew"}`),
+		Value:     &payload{Name: "Andrew"},
+	}, received)
+
+	// Events without a payload are not decoded.
+	err = fn(context.Background(), nil, &workflow.ConnectorEvent{ID: "2"})
+	require.Nil(t, err)
+	require.Nil(t, received.Value)
+
+	err = fn(context.Background(), nil, &workflow.ConnectorEvent{ID: "3", Payload: []byte("{")})
+	require.NotNil(t, err)
+}
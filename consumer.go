@@ -17,13 +17,42 @@ import (
 type ConsumerFunc[Type any, Status StatusType] func(ctx context.Context, r *Run[Type, Status]) (Status, error)
 
 type consumerConfig[Type any, Status StatusType] struct {
-	pollingFrequency   time.Duration
-	errBackOff         time.Duration
-	consumer           ConsumerFunc[Type, Status]
-	parallelCount      int
-	lag                time.Duration
-	lagAlert           time.Duration
-	pauseAfterErrCount int
+	pollingFrequency time.Duration
+	// maxPollingFrequency is only configured when using AdaptivePolling.
+	maxPollingFrequency time.Duration
+	errBackOff          time.Duration
+	consumer            ConsumerFunc[Type, Status]
+	parallelCount       int
+	lag                 time.Duration
+	lagAlert            time.Duration
+	pauseAfterErrCount  int
+	// batch is only configured for consumers added using AddBatchStep.
+	batch *batchConfig[Type, Status]
+
+	rateLimit *rateLimit
+	// limiter is created from the rateLimit when the workflow is built and is shared by the shards of the consumer.
+	limiter *rateLimiter
+
+	circuitBreaker *circuitBreakerConfig
+	// breaker is created from the circuitBreaker when the workflow is built and is shared by the shards of the
+	// consumer.
+	breaker *circuitBreaker
+
+	retryPolicy *retryPolicyConfig
+	// retrier is created from the retryPolicy when the workflow is built and is shared by the shards of the consumer.
+	retrier *retryPolicy
+
+	// concurrencyKey is only configured using WithConcurrencyKey.
+	concurrencyKey *concurrencyKey[Type, Status]
+	// replay is only configured using WithReplayConsumer.
+	replay ConsumerFunc[Type, Status]
+	// heartbeatTimeout is only configured using WithHeartbeatTimeout.
+	heartbeatTimeout time.Duration
+	// stepConfig is only configured using WithStepConfig.
+	stepConfig      any
+	stepTimeout     time.Duration
+	sla             time.Duration
+	deadlineFromSLA bool
 }
 
 func consume(
@@ -61,7 +90,7 @@ func consume(
 		}
 
 		// Push metrics and alerting around the age of the event being processed.
-		pushLagMetricAndAlerting(workflowName, processName, e.CreatedAt, lagAlert, clock)
+		pushLagMetricAndAlerting(ctx, workflowName, processName, e.CreatedAt, lagAlert, clock)
 
 		shouldFilter := FilterUsing(e, filters...)
 		if shouldFilter {
@@ -85,16 +114,23 @@ func consume(
 			return err
 		}
 
-		metrics.ProcessLatency.WithLabelValues(workflowName, processName).Observe(clock.Since(t0).Seconds())
+		metrics.ObserveWithExemplar(
+			metrics.ProcessLatency.WithLabelValues(workflowName, processName),
+			clock.Since(t0).Seconds(),
+			exemplar(e.ForeignID, e.Headers[HeaderTraceParent]),
+		)
+		metrics.ProcessConsumedEvents.WithLabelValues(workflowName, processName).Inc()
 	}
 }
 
 // pushLagMetricAndAlerting will push metrics around the age of the event being processed. If the age of the event is
 // greater than the threshold then the processName for the workflow specified (workflowName) will be set to 1 which
-// signals that this process for this workflow is in an alerting state.
+// signals that this process for this workflow is in an alerting state and the lagAlertFunc of the context, if any, is
+// called with the process' alerting state.
 //
 // See internal/metrics/metrics.go for the prometheus metrics configured.
 func pushLagMetricAndAlerting(
+	ctx context.Context,
 	workflowName string,
 	processName string,
 	timestamp time.Time,
@@ -114,5 +150,26 @@ func pushLagMetricAndAlerting(
 		}
 
 		metrics.ConsumerLagAlert.WithLabelValues(workflowName, processName).Set(alert)
+
+		fn, ok := ctx.Value(lagAlertContextKey{}).(lagAlertFunc)
+		if ok {
+			fn(ctx, LagAlertInfo{
+				WorkflowName: workflowName,
+				Process:      processName,
+				Lag:          lag,
+				Threshold:    lagThreshold,
+				Alerting:     lag > lagThreshold,
+			})
+		}
 	}
 }
+
+type lagAlertContextKey struct{}
+
+// lagAlertFunc is called with whether the process is lagging beyond its lag alert for every event that it consumes.
+type lagAlertFunc func(ctx context.Context, info LagAlertInfo)
+
+// withLagAlertFunc sets the function that is called with the lag alerting state of the process using the context.
+func withLagAlertFunc(ctx context.Context, fn lagAlertFunc) context.Context {
+	return context.WithValue(ctx, lagAlertContextKey{}, fn)
+}
@@ -0,0 +1,160 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// ErrCursorsNotSupported is returned when the cursors of a workflow are viewed or reset but the EventStreamer does not
+// implement CursorManager.
+var ErrCursorsNotSupported = errors.New("event streamer does not support managing cursors")
+
+// Cursor identifies the position of a consumer, by its Name, on a Topic. The Name is used as the consumer group, or
+// cursor name, by the EventStreamer when the consumer calls NewReceiver.
+type Cursor struct {
+	Topic string
+	Name  string
+}
+
+// CursorOffset is the position of a Cursor.
+type CursorOffset struct {
+	Cursor
+
+	// Offset is the adapter specific position of the cursor, such as the sum of the committed offsets of each
+	// partition of a Kafka topic.
+	Offset int64
+	// Lag is the number of events on the topic that have not been consumed yet.
+	Lag int64
+}
+
+// CursorPositionType is the type of position that a cursor is reset to.
+type CursorPositionType int
+
+const (
+	CursorPositionUnknown CursorPositionType = 0
+	// CursorPositionEarliest results in all the events that are still on the topic being consumed again.
+	CursorPositionEarliest CursorPositionType = 1
+	// CursorPositionLatest results in all the events that are on the topic being skipped.
+	CursorPositionLatest CursorPositionType = 2
+	// CursorPositionTimestamp results in the events created at, or after, the timestamp being consumed again, and
+	// the events created before the timestamp being skipped.
+	CursorPositionTimestamp CursorPositionType = 3
+)
+
+// CursorPosition is the position that a cursor is reset to. Use CursorAtEarliest, CursorAtLatest, or
+// CursorAtTimestamp to create a CursorPosition.
+type CursorPosition struct {
+	Type      CursorPositionType
+	Timestamp time.Time
+}
+
+func CursorAtEarliest() CursorPosition {
+	return CursorPosition{Type: CursorPositionEarliest}
+}
+
+func CursorAtLatest() CursorPosition {
+	return CursorPosition{Type: CursorPositionLatest}
+}
+
+func CursorAtTimestamp(t time.Time) CursorPosition {
+	return CursorPosition{Type: CursorPositionTimestamp, Timestamp: t}
+}
+
+// CursorManager can optionally be implemented by an EventStreamer to allow the cursors of a workflow's consumers to
+// be viewed and reset, such as to replay the events of a single step, without adapter specific tooling.
+type CursorManager interface {
+	CursorOffset(ctx context.Context, cursor Cursor) (CursorOffset, error)
+	// ResetCursor moves the cursor to the position. Implementations may return an error when the cursor is in use
+	// by a running consumer.
+	ResetCursor(ctx context.Context, cursor Cursor, position CursorPosition) error
+}
+
+// StepCursors returns the cursor of every consumer of the status which includes the cursor of each shard when
+// ParallelCount is used and each priority lane.
+func (w *Workflow[Type, Status]) StepCursors(status Status) []Cursor {
+	config, ok := w.consumers[status]
+	if !ok {
+		return nil
+	}
+
+	var cursors []Cursor
+	parallelCount := w.defaultOpts.parallelCount
+	if config.parallelCount != 0 {
+		parallelCount = config.parallelCount
+	}
+
+	totalShards := max(parallelCount, 1)
+	for shard := 1; shard <= totalShards; shard++ {
+		role := stepConsumerRole(w, status, shard, totalShards)
+		if w.multiplexed(config) {
+			role = multiplexedConsumerRole(w, shard, totalShards)
+		}
+
+		for priority := 0; priority <= w.priorityLanes; priority++ {
+			cursors = append(cursors, Cursor{
+				Topic: PriorityTopic(w.topic(status), priority),
+				Name:  laneReceiverName(role, priority),
+			})
+		}
+	}
+
+	return cursors
+}
+
+// StepCursorOffsets returns the position of every cursor of the status' consumers.
+func (w *Workflow[Type, Status]) StepCursorOffsets(ctx context.Context, status Status) ([]CursorOffset, error) {
+	manager, err := w.cursorManager()
+	if err != nil {
+		return nil, err
+	}
+
+	var offsets []CursorOffset
+	for _, cursor := range w.StepCursors(status) {
+		offset, err := manager.CursorOffset(ctx, cursor)
+		if err != nil {
+			return nil, fmt.Errorf("cursor offset: %w, meta: %v", err, map[string]string{
+				"topic":  cursor.Topic,
+				"cursor": cursor.Name,
+			})
+		}
+
+		offsets = append(offsets, offset)
+	}
+
+	return offsets, nil
+}
+
+// ResetStepCursors moves every cursor of the status' consumers to the position so that the step replays, or skips,
+// events. The step's consumers should be stopped before resetting its cursors as a running consumer can acknowledge
+// an event that it received before the reset and move the cursor back.
+func (w *Workflow[Type, Status]) ResetStepCursors(ctx context.Context, status Status, position CursorPosition) error {
+	manager, err := w.cursorManager()
+	if err != nil {
+		return err
+	}
+
+	for _, cursor := range w.StepCursors(status) {
+		err := manager.ResetCursor(ctx, cursor, position)
+		if err != nil {
+			return fmt.Errorf("reset cursor: %w, meta: %v", err, map[string]string{
+				"topic":         cursor.Topic,
+				"cursor":        cursor.Name,
+				"position_type": strconv.Itoa(int(position.Type)),
+			})
+		}
+	}
+
+	return nil
+}
+
+func (w *Workflow[Type, Status]) cursorManager() (CursorManager, error) {
+	manager, ok := unwrapEventStreamer(w.eventStreamer).(CursorManager)
+	if !ok {
+		return nil, ErrCursorsNotSupported
+	}
+
+	return manager, nil
+}
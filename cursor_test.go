@@ -0,0 +1,131 @@
+package workflow_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestStepCursors(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("cursors")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd).WithOptions(workflow.ParallelCount(2))
+
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+		workflow.WithPriorityLanes(1),
+	)
+
+	expected := []workflow.Cursor{
+		{Topic: "cursors-9", Name: "cursors-9-consumer-1-of-2"},
+		{Topic: "cursors-9-p1", Name: "cursors-9-consumer-1-of-2-p1"},
+		{Topic: "cursors-9", Name: "cursors-9-consumer-2-of-2"},
+		{Topic: "cursors-9-p1", Name: "cursors-9-consumer-2-of-2-p1"},
+	}
+	require.Equal(t, expected, wf.StepCursors(StatusStart))
+	require.Empty(t, wf.StepCursors(StatusEnd))
+}
+
+func TestResetStepCursors(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("reset cursors")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+
+	startedAt := time.Now()
+	runID, err := wf.Trigger(ctx, "foreignID", StatusStart)
+	require.Nil(t, err)
+
+	_, err = wf.Await(ctx, "foreignID", runID, StatusEnd, workflow.WithAwaitPollingFrequency(10*time.Millisecond))
+	require.Nil(t, err)
+
+	// Stop the consumers so that the cursors are no longer in use.
+	wf.Stop()
+
+	requireLag := func(t *testing.T, expected int64) {
+		offsets, err := wf.StepCursorOffsets(ctx, StatusStart)
+		require.Nil(t, err)
+		require.Len(t, offsets, 1)
+		require.Equal(t, "reset_cursors-9-consumer-1-of-1", offsets[0].Name)
+		require.Equal(t, expected, offsets[0].Lag)
+	}
+
+	requireLag(t, 0)
+
+	testCases := []struct {
+		name     string
+		position workflow.CursorPosition
+		lag      int64
+	}{
+		{
+			name:     "Earliest",
+			position: workflow.CursorAtEarliest(),
+			lag:      1,
+		},
+		{
+			name:     "Latest",
+			position: workflow.CursorAtLatest(),
+			lag:      0,
+		},
+		{
+			name:     "Timestamp before the event",
+			position: workflow.CursorAtTimestamp(startedAt),
+			lag:      1,
+		},
+		{
+			name:     "Timestamp after the event",
+			position: workflow.CursorAtTimestamp(time.Now().Add(time.Hour)),
+			lag:      0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := wf.ResetStepCursors(ctx, StatusStart, tc.position)
+			require.Nil(t, err)
+
+			requireLag(t, tc.lag)
+		})
+	}
+}
+
+func TestResetStepCursors_notSupported(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("reset cursors")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	// Embedding the interface hides the CursorManager implementation of memstreamer.
+	streamer := struct{ workflow.EventStreamer }{memstreamer.New()}
+	wf := b.Build(
+		streamer,
+		memrecordstore.New(),
+		memrolescheduler.New(),
+	)
+
+	ctx := context.Background()
+	_, err := wf.StepCursorOffsets(ctx, StatusStart)
+	require.ErrorIs(t, err, workflow.ErrCursorsNotSupported)
+
+	err = wf.ResetStepCursors(ctx, StatusStart, workflow.CursorAtEarliest())
+	require.ErrorIs(t, err, workflow.ErrCursorsNotSupported)
+}
@@ -0,0 +1,118 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// WithDeadLetterQueue publishes runs that exceed their PauseAfterErrCount to the topic of the provided
+// EventStreamer, along with the last error, before the run is paused. This allows for the external triage of runs that
+// have failed permanently. Events published to the dead-letter queue can be decoded using ParseDeadLetter and the
+// run can be re-driven back into the workflow using RedriveDeadLetter once the cause of the failure has been resolved.
+//
+// Paused runs are automatically resumed unless DisablePauseRetry is provided and so it should be provided if
+// runs in the dead-letter queue must only be re-driven manually.
+func WithDeadLetterQueue(streamer EventStreamer, topic string) BuildOption {
+	return func(bo *buildOptions) {
+		bo.deadLetterStreamer = streamer
+		bo.deadLetterTopic = topic
+	}
+}
+
+// DeadLetter is a run that exceeded its PauseAfterErrCount and was published to the dead-letter queue.
+type DeadLetter struct {
+	Record Record
+	// ProcessName is the name of the process, as found in States, that failed to process the run.
+	ProcessName string
+	// Error is the last error returned when processing the run.
+	Error    string
+	FailedAt time.Time
+}
+
+// ParseDeadLetter decodes an Event received from the dead-letter queue configured using WithDeadLetterQueue.
+func ParseDeadLetter(e *Event) (*DeadLetter, error) {
+	recordJSON, ok := e.Headers[HeaderDeadLetterRecord]
+	if !ok {
+		return nil, errors.New("event is not a dead letter")
+	}
+
+	var record Record
+	err := json.Unmarshal([]byte(recordJSON), &record)
+	if err != nil {
+		return nil, err
+	}
+
+	var failedAt time.Time
+	if v := e.Headers[HeaderDeadLetterFailedAt]; v != "" {
+		unix, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		failedAt = time.Unix(unix, 0)
+	}
+
+	return &DeadLetter{
+		Record:      record,
+		ProcessName: e.Headers[HeaderDeadLetterProcess],
+		Error:       e.Headers[HeaderDeadLetterError],
+		FailedAt:    failedAt,
+	}, nil
+}
+
+type deadLetterFunc func(ctx context.Context, processName string, record *Record, err error) error
+
+// deadLetterFunc returns nil when the workflow has not been built using WithDeadLetterQueue.
+func (w *Workflow[Type, Status]) deadLetterFunc() deadLetterFunc {
+	if w.deadLetterStreamer == nil {
+		return nil
+	}
+
+	return func(ctx context.Context, processName string, record *Record, err error) error {
+		recordJSON, jsonErr := json.Marshal(record)
+		if jsonErr != nil {
+			return jsonErr
+		}
+
+		sender, sendErr := w.deadLetterStreamer.NewSender(ctx, w.deadLetterTopic)
+		if sendErr != nil {
+			return sendErr
+		}
+		defer sender.Close()
+
+		headers := map[Header]string{
+			HeaderWorkflowName:       record.WorkflowName,
+			HeaderForeignID:          record.ForeignID,
+			HeaderRunID:              record.RunID,
+			HeaderTopic:              w.deadLetterTopic,
+			HeaderDeadLetterRecord:   string(recordJSON),
+			HeaderDeadLetterProcess:  processName,
+			HeaderDeadLetterError:    err.Error(),
+			HeaderDeadLetterFailedAt: strconv.FormatInt(w.clock.Now().Unix(), 10),
+		}
+
+		return sender.Send(ctx, record.RunID, record.Status, headers)
+	}
+}
+
+// RedriveDeadLetter resumes the run, that was paused after being published to the dead-letter queue, so that it is
+// processed again by the workflow. The run must still be paused.
+func (w *Workflow[Type, Status]) RedriveDeadLetter(ctx context.Context, runID string) error {
+	record, err := w.recordStore.Lookup(ctx, runID)
+	if err != nil {
+		return err
+	}
+
+	if record.WorkflowName != w.Name() {
+		return fmt.Errorf("redrive failed: %w, meta: %v", ErrRecordNotFound, map[string]string{
+			"run_id": runID,
+		})
+	}
+
+	record.UpdatedAt = w.clock.Now()
+	return NewRunStateController(w.recordStore.Store, record).Resume(ctx)
+}
@@ -0,0 +1,54 @@
+package workflow
+
+import (
+	"context"
+)
+
+// DebugEventType is the type of a DebugEvent.
+type DebugEventType string
+
+const (
+	DebugEventProcessStarted      DebugEventType = "process_started"
+	DebugEventProcessStopped      DebugEventType = "process_stopped"
+	DebugEventRoleLost            DebugEventType = "role_lost"
+	DebugEventProcessFenced       DebugEventType = "process_fenced"
+	DebugEventIncompatibleVersion DebugEventType = "incompatible_version"
+	DebugEventStoreUnavailable    DebugEventType = "store_unavailable"
+	DebugEventSkipped             DebugEventType = "skipped"
+	DebugEventRunPaused           DebugEventType = "run_paused"
+	DebugEventRunStateUpdated     DebugEventType = "run_state_updated"
+	DebugEventTransitionForced    DebugEventType = "transition_forced"
+	DebugEventRunReplayed         DebugEventType = "run_replayed"
+	DebugEventStepCompensated     DebugEventType = "step_compensated"
+	DebugEventChildRunCancelled   DebugEventType = "child_run_cancelled"
+	DebugEventTimeoutVetoed       DebugEventType = "timeout_vetoed"
+	DebugEventTriggerDeduplicated DebugEventType = "trigger_deduplicated"
+	DebugEventStatusNotConfigured DebugEventType = "status_not_configured"
+	DebugEventLegalHoldPlaced     DebugEventType = "legal_hold_placed"
+	DebugEventLegalHoldLifted     DebugEventType = "legal_hold_lifted"
+)
+
+// DebugEvent is a machine-readable event about what the workflow's engine is doing, such as a process being launched
+// or an event being skipped, that is written to the debug logs when the workflow is built using WithDebugMode
+// and emitted to the DebugEventSink configured using WithDebugEventSink.
+type DebugEvent struct {
+	Type DebugEventType
+	// Message is the human-readable description of the event that is written to the debug logs.
+	Message string
+	// Meta holds the details of the event, such as the workflow_name, process_name, run_id, and, for skipped events,
+	// the reason that the event was skipped.
+	Meta map[string]string
+}
+
+// DebugEventSink is called with every DebugEvent emitted by the workflow, such as to assert on the engine's
+// behaviour in tests or to ingest the events in a log pipeline. The sink is called from the workflow's processes and
+// should not block for long.
+type DebugEventSink func(ctx context.Context, e DebugEvent)
+
+// WithDebugEventSink emits the workflow's DebugEvents to the sink. Events are emitted to the sink regardless of
+// whether the workflow is built using WithDebugMode.
+func WithDebugEventSink(sink DebugEventSink) BuildOption {
+	return func(bo *buildOptions) {
+		bo.debugEventSink = sink
+	}
+}
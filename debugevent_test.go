@@ -0,0 +1,112 @@
+package workflow_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+type debugEventRecorder struct {
+	mu     sync.Mutex
+	events []workflow.DebugEvent
+}
+
+func (r *debugEventRecorder) sink(ctx context.Context, e workflow.DebugEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, e)
+}
+
+// find returns the first event of the type whose meta includes the provided meta.
+func (r *debugEventRecorder) find(typ workflow.DebugEventType, meta map[string]string) (workflow.DebugEvent, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, e := range r.events {
+		if e.Type != typ {
+			continue
+		}
+
+		matches := true
+		for k, v := range meta {
+			if e.Meta[k] != v {
+				matches = false
+			}
+		}
+
+		if matches {
+			return e, true
+		}
+	}
+
+	return workflow.DebugEvent{}, false
+}
+
+func TestWithDebugEventSink(t *testing.T) {
+	recorder := &debugEventRecorder{}
+
+	b := workflow.NewBuilder[string, status]("debug events")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		if r.ForeignID == "skip" {
+			return r.Skip()
+		}
+
+		return 0, errors.New("downstream unavailable")
+	}, StatusEnd).WithOptions(
+		workflow.PauseAfterErrCount(1),
+		workflow.ErrBackOff(time.Millisecond),
+	)
+
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+		workflow.WithDebugEventSink(recorder.sink),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+
+	skippedRunID, err := wf.Trigger(ctx, "skip", StatusStart)
+	require.Nil(t, err)
+
+	pausedRunID, err := wf.Trigger(ctx, "pause", StatusStart)
+	require.Nil(t, err)
+
+	require.Eventually(t, func() bool {
+		_, skipped := recorder.find(workflow.DebugEventSkipped, map[string]string{
+			"run_id": skippedRunID,
+			"reason": "next value specified skip",
+		})
+
+		_, paused := recorder.find(workflow.DebugEventRunPaused, map[string]string{
+			"run_id": pausedRunID,
+			"error":  "downstream unavailable",
+		})
+
+		return skipped && paused
+	}, 5*time.Second, 10*time.Millisecond)
+
+	started, ok := recorder.find(workflow.DebugEventProcessStarted, map[string]string{
+		"process_name": "start-consumer-1-of-1",
+	})
+	require.True(t, ok)
+	require.Equal(t, "debug events", started.Meta["workflow_name"])
+
+	wf.Stop()
+
+	_, ok = recorder.find(workflow.DebugEventProcessStopped, map[string]string{
+		"process_name": "start-consumer-1-of-1",
+	})
+	require.True(t, ok)
+}
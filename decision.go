@@ -0,0 +1,79 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidDecision is returned by a decision step when its DecisionFunc returns a status that is not one of the
+// decision's destinations.
+var ErrInvalidDecision = errors.New("decision returned a status that is not a destination")
+
+// DecisionFunc decides which of a decision step's destinations the run moves to. Returning zero skips the run in the
+// same way as a ConsumerFunc.
+type DecisionFunc[Type any, Status StatusType] func(ctx context.Context, r *Run[Type, Status]) (Status, error)
+
+// AddDecision adds a step that only routes the run from the status to one of the destinations, without the branching
+// logic and the allowed transitions of the step being declared separately. The destinations are validated when the
+// workflow is built and a status returned by the decision that is not one of the destinations fails with
+// ErrInvalidDecision, which is retried like any other error of a step, instead of the run being moved. The decision
+// step can be configured in the same way as a step added using AddStep.
+func (b *Builder[Type, Status]) AddDecision(
+	from Status,
+	decide DecisionFunc[Type, Status],
+	destinations ...Status,
+) *stepUpdater[Type, Status] {
+	validateDecision(from, destinations)
+
+	return b.AddStep(from, func(ctx context.Context, r *Run[Type, Status]) (Status, error) {
+		next, err := decide(ctx, r)
+		if err != nil {
+			return 0, err
+		}
+
+		if skipUpdate(next) || slices.Contains(destinations, next) {
+			return next, nil
+		}
+
+		return 0, fmt.Errorf("%w, meta: %v", ErrInvalidDecision, map[string]string{
+			"from":         from.String(),
+			"returned":     next.String(),
+			"destinations": statusNames(destinations),
+		})
+	}, destinations...)
+}
+
+func validateDecision[Status StatusType](from Status, destinations []Status) {
+	if len(destinations) == 0 {
+		panic("'AddDecision(" + from.String() + ",' requires at least one destination")
+	}
+
+	seen := make(map[Status]bool)
+	for _, to := range destinations {
+		if skipUpdate(to) {
+			panic("'AddDecision(" + from.String() + ",' destination '" + to.String() + "' is not a valid status")
+		}
+
+		if to == from {
+			panic("'AddDecision(" + from.String() + ",' cannot have its own status as a destination")
+		}
+
+		if seen[to] {
+			panic("'AddDecision(" + from.String() + ",' destination '" + to.String() + "' is provided more than once")
+		}
+
+		seen[to] = true
+	}
+}
+
+func statusNames[Status StatusType](statuses []Status) string {
+	names := make([]string, 0, len(statuses))
+	for _, s := range statuses {
+		names = append(names, s.String())
+	}
+
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,133 @@
+package workflow_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestAddDecision(t *testing.T) {
+	b := workflow.NewBuilder[MyType, status]("decision")
+	b.AddDecision(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		if r.Object.OTPVerified {
+			return StatusEnd, nil
+		}
+
+		return StatusMiddle, nil
+	}, StatusMiddle, StatusEnd)
+	b.AddStep(StatusMiddle, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	verifiedRunID, err := wf.Trigger(ctx, "verified", StatusStart, workflow.WithInitialValue[MyType, status](&MyType{
+		OTPVerified: true,
+	}))
+	require.Nil(t, err)
+
+	unverifiedRunID, err := wf.Trigger(ctx, "unverified", StatusStart)
+	require.Nil(t, err)
+
+	_, err = wf.Await(ctx, "verified", verifiedRunID, StatusEnd, workflow.WithAwaitPollingFrequency(10*time.Millisecond))
+	require.Nil(t, err)
+
+	_, err = wf.Await(ctx, "unverified", unverifiedRunID, StatusEnd, workflow.WithAwaitPollingFrequency(10*time.Millisecond))
+	require.Nil(t, err)
+
+	history, err := wf.RunHistory(ctx, verifiedRunID)
+	require.Nil(t, err)
+	require.Equal(t, int(StatusEnd), history[len(history)-1].ToStatus)
+	require.Equal(t, int(StatusStart), history[len(history)-1].FromStatus)
+}
+
+func TestAddDecisionInvalidStatus(t *testing.T) {
+	b := workflow.NewBuilder[MyType, status]("decision")
+	b.AddDecision(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		// StatusEnd is not one of the destinations of the decision.
+		return StatusEnd, nil
+	}, StatusMiddle).WithOptions(
+		workflow.PauseAfterErrCount(1),
+		workflow.ErrBackOff(time.Millisecond),
+	)
+
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+		workflow.DisablePauseRetry(),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "foreignID", StatusStart)
+	require.Nil(t, err)
+
+	require.Eventually(t, func() bool {
+		history, err := wf.RunHistory(ctx, runID)
+		require.Nil(t, err)
+
+		last := history[len(history)-1]
+		return last.ToRunState == workflow.RunStatePaused &&
+			strings.Contains(last.Error, workflow.ErrInvalidDecision.Error())
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestAddDecisionValidation(t *testing.T) {
+	decide := func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		return StatusEnd, nil
+	}
+
+	testCases := []struct {
+		name         string
+		destinations []status
+		expected     string
+	}{
+		{
+			name:     "No destinations",
+			expected: "'AddDecision(Start,' requires at least one destination",
+		},
+		{
+			name:         "Own status",
+			destinations: []status{StatusStart},
+			expected:     "'AddDecision(Start,' cannot have its own status as a destination",
+		},
+		{
+			name:         "Duplicate destination",
+			destinations: []status{StatusEnd, StatusEnd},
+			expected:     "'AddDecision(Start,' destination 'End' is provided more than once",
+		},
+		{
+			name:         "Skip status",
+			destinations: []status{0},
+			expected:     "'AddDecision(Start,' destination 'Unknown' is not a valid status",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := workflow.NewBuilder[MyType, status]("decision")
+			require.PanicsWithValue(t, tc.expected, func() {
+				b.AddDecision(StatusStart, decide, tc.destinations...)
+			})
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package workflow
+
+import (
+	"context"
+	"time"
+)
+
+// WithDedupWindow prevents a new run from being created for the foreignID if the latest run was created with the same
+// starting status within the provided window. The run ID of the existing run is returned instead along with a nil
+// error, which is also the case if the existing run is still in progress.
+func WithDedupWindow[Type any, Status StatusType](window time.Duration) TriggerOption[Type, Status] {
+	return func(o *triggerOpts[Type, Status]) {
+		o.dedupWindow = window
+	}
+}
+
+// isDuplicateRun returns true if the record is the latest run of the foreignID and was started at the same status
+// within the window.
+func isDuplicateRun(record *Record, startingStatus int, window time.Duration, now time.Time) bool {
+	if window <= 0 || !record.RunState.Valid() {
+		return false
+	}
+
+	if runStartingStatus(record) != startingStatus {
+		return false
+	}
+
+	return now.Sub(record.CreatedAt) < window
+}
+
+// runStartingStatus returns the status that the run was triggered with.
+func runStartingStatus(record *Record) int {
+	if len(record.Meta.StepHistory) > 0 {
+		return record.Meta.StepHistory[0]
+	}
+
+	return record.Status
+}
+
+// dedupAPI is provided to connectors that are configured with a DedupWindow and ensures that all the runs that they
+// trigger are deduplicated.
+type dedupAPI[Type any, Status StatusType] struct {
+	API[Type, Status]
+	window time.Duration
+}
+
+func (d dedupAPI[Type, Status]) Trigger(
+	ctx context.Context,
+	foreignID string,
+	startingStatus Status,
+	opts ...TriggerOption[Type, Status],
+) (string, error) {
+	opts = append(opts, WithDedupWindow[Type, Status](d.window))
+	return d.API.Trigger(ctx, foreignID, startingStatus, opts...)
+}
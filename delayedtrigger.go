@@ -0,0 +1,148 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"k8s.io/utils/clock"
+
+	"github.com/luno/workflow/internal/metrics"
+)
+
+// scheduledStartTimer is the name of the timeouts that start the runs triggered using WithStartAt or WithDelay. The
+// name keeps the timeouts apart from those of the starting status's timeouts and timers.
+const scheduledStartTimer = "workflow.scheduled-start"
+
+// WithStartAt triggers a run that only becomes consumable at the provided time. The run is stored when Trigger is
+// called, and so a new run cannot be triggered for the foreignID in the meantime, but the steps of the starting status
+// don't consume it until the start time. The start is scheduled using the workflow's TimeoutStore which must
+// implement NamedTimeoutStore. Start times that are not in the future trigger the run immediately.
+func WithStartAt[Type any, Status StatusType](t time.Time) TriggerOption[Type, Status] {
+	return func(o *triggerOpts[Type, Status]) {
+		o.startAt = t
+	}
+}
+
+// WithDelay triggers a run that only becomes consumable once the delay has passed. See WithStartAt.
+func WithDelay[Type any, Status StatusType](d time.Duration) TriggerOption[Type, Status] {
+	return func(o *triggerOpts[Type, Status]) {
+		o.delay = d
+	}
+}
+
+// scheduleStart creates the timeout that starts the run at startAt.
+func (w *Workflow[Type, Status]) scheduleStart(ctx context.Context, r *Record, startAt time.Time) error {
+	if w.timeoutStore == nil {
+		return fmt.Errorf("schedule start: %w", errNoTimeoutStore)
+	}
+
+	store, ok := unwrapTimeoutStore(w.timeoutStore).(NamedTimeoutStore)
+	if !ok {
+		return errors.New("schedule start: timeout store does not implement NamedTimeoutStore")
+	}
+
+	return store.CreateNamed(ctx, r.WorkflowName, r.ForeignID, r.RunID, r.Status, scheduledStartTimer, startAt)
+}
+
+// filterUnstarted filters out the events of runs whose scheduled start time has not yet been reached. The run's
+// event is published again once it has started.
+func filterUnstarted(clock clock.Clock) EventFilter {
+	return func(e *Event) bool {
+		v, ok := e.Headers[HeaderStartAt]
+		if !ok {
+			return false
+		}
+
+		startAt, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return false
+		}
+
+		return time.Unix(0, startAt).After(clock.Now())
+	}
+}
+
+func scheduledStartPoller[Type any, Status StatusType](w *Workflow[Type, Status]) {
+	role := makeRole(w.roleName(), "scheduled-start-consumer")
+	processName := makeRole("scheduled-start-consumer")
+
+	poll := newPollInterval(w.defaultOpts.pollingFrequency, w.defaultOpts.maxPollingFrequency)
+	w.run(role, processName, w.hookShutdownOrder(), func(ctx context.Context) error {
+		for {
+			err := w.fence.check(ctx)
+			if err != nil {
+				return err
+			}
+
+			started, err := startScheduledRuns(ctx, w, processName)
+			if err != nil {
+				return err
+			}
+
+			err = poll.wait(ctx, started > 0)
+			if err != nil {
+				return err
+			}
+		}
+	}, w.defaultOpts.errBackOff)
+}
+
+// startScheduledRuns starts the runs whose scheduled start time has been reached and returns how many were started.
+func startScheduledRuns[Type any, Status StatusType](
+	ctx context.Context,
+	w *Workflow[Type, Status],
+	processName string,
+) (int, error) {
+	timeouts, err := w.timeoutStore.List(ctx, w.Name())
+	if err != nil {
+		return 0, err
+	}
+
+	now := w.clock.Now()
+	var started int
+	for _, t := range timeouts {
+		if t.Name != scheduledStartTimer || t.Completed || t.ExpireAt.After(now) {
+			continue
+		}
+
+		r, err := w.recordStore.Lookup(ctx, t.RunID)
+		if errors.Is(err, ErrRecordNotFound) {
+			// The run was not stored, such as when storing the run failed after its start was scheduled.
+			err = w.timeoutStore.Cancel(ctx, t.ID)
+			if err != nil {
+				return 0, err
+			}
+
+			continue
+		} else if err != nil {
+			return 0, err
+		}
+
+		if r.Meta.StartAt != nil {
+			if !isCompatible(w.compatibilityPolicy, w.version, r.Meta.Version) {
+				metrics.ProcessSkippedEvents.WithLabelValues(w.Name(), processName, "incompatible run version").Inc()
+				continue
+			}
+
+			// Storing the run again publishes its event without the start time so that the run is consumed.
+			r.Meta.StartAt = nil
+			r.UpdatedAt = now
+			err = w.recordStore.Store(ctx, r)
+			if err != nil {
+				return 0, err
+			}
+
+			started++
+		}
+
+		err = w.timeoutStore.Complete(ctx, t.ID)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return started, nil
+}
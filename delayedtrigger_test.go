@@ -0,0 +1,108 @@
+package workflow_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	clock_testing "k8s.io/utils/clock/testing"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+	"github.com/luno/workflow/adapters/memtimeoutstore"
+)
+
+func TestDelayedTrigger(t *testing.T) {
+	testCases := []struct {
+		name string
+		opt  func(now time.Time) workflow.TriggerOption[MyType, status]
+	}{
+		{
+			name: "WithDelay",
+			opt: func(now time.Time) workflow.TriggerOption[MyType, status] {
+				return workflow.WithDelay[MyType, status](time.Hour)
+			},
+		},
+		{
+			name: "WithStartAt",
+			opt: func(now time.Time) workflow.TriggerOption[MyType, status] {
+				return workflow.WithStartAt[MyType, status](now.Add(time.Hour))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := workflow.NewBuilder[MyType, status]("delayed trigger")
+			b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+				return StatusEnd, nil
+			}, StatusEnd)
+
+			now := time.Date(2024, time.April, 9, 0, 0, 0, 0, time.UTC)
+			clock := clock_testing.NewFakeClock(now)
+			recordStore := memrecordstore.New()
+			wf := b.Build(
+				memstreamer.New(),
+				recordStore,
+				memrolescheduler.New(),
+				workflow.WithTimeoutStore(memtimeoutstore.New()),
+				workflow.WithClock(clock),
+			)
+
+			ctx := context.Background()
+			wf.Run(ctx)
+			t.Cleanup(wf.Stop)
+
+			runID, err := wf.Trigger(ctx, "foreignID", StatusStart, tc.opt(now))
+			require.Nil(t, err)
+
+			// A new run cannot be triggered whilst the delayed run is waiting to start.
+			_, err = wf.Trigger(ctx, "foreignID", StatusStart)
+			require.ErrorIs(t, err, workflow.ErrWorkflowInProgress)
+
+			pending, err := wf.PendingTimeouts(ctx, runID)
+			require.Nil(t, err)
+			require.Len(t, pending, 1)
+			require.Equal(t, now.Add(time.Hour), pending[0].ExpireAt)
+
+			require.Never(t, func() bool {
+				r, err := recordStore.Lookup(ctx, runID)
+				require.Nil(t, err)
+				return r.Status != int(StatusStart)
+			}, time.Second, 50*time.Millisecond)
+
+			clock.Step(time.Hour)
+
+			_, err = wf.Await(ctx, "foreignID", runID, StatusEnd, workflow.WithAwaitPollingFrequency(10*time.Millisecond))
+			require.Nil(t, err)
+
+			pending, err = wf.PendingTimeouts(ctx, runID)
+			require.Nil(t, err)
+			require.Empty(t, pending)
+		})
+	}
+}
+
+func TestDelayedTriggerWithoutTimeoutStore(t *testing.T) {
+	b := workflow.NewBuilder[MyType, status]("delayed trigger")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	recordStore := memrecordstore.New()
+	wf := b.Build(memstreamer.New(), recordStore, memrolescheduler.New())
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	_, err := wf.Trigger(ctx, "foreignID", StatusStart, workflow.WithDelay[MyType, status](time.Hour))
+	require.ErrorContains(t, err, "no TimeoutStore configured for workflow")
+
+	// The run is not stored when its start cannot be scheduled.
+	_, err = recordStore.Latest(ctx, wf.Name(), "foreignID")
+	require.ErrorIs(t, err, workflow.ErrRecordNotFound)
+}
@@ -2,6 +2,8 @@ package workflow
 
 import (
 	"context"
+
+	"github.com/luno/workflow/internal/metrics"
 )
 
 func deleteConsumer[Type any, Status StatusType](w *Workflow[Type, Status]) {
@@ -12,7 +14,7 @@ func deleteConsumer[Type any, Status StatusType](w *Workflow[Type, Status]) {
 	)
 
 	processName := makeRole("delete", "consumer")
-	w.run(role, processName, func(ctx context.Context) error {
+	w.run(role, processName, w.hookShutdownOrder(), func(ctx context.Context) error {
 		topic := DeleteTopic(w.Name())
 		stream, err := w.eventStreamer.NewReceiver(
 			ctx,
@@ -31,9 +33,13 @@ func deleteConsumer[Type any, Status StatusType](w *Workflow[Type, Status]) {
 			processName,
 			stream,
 			runDelete(
+				w.Name(),
+				processName,
 				w.recordStore.Store,
 				w.recordStore.Lookup,
 				w.customDelete,
+				w.codec,
+				newLegalHoldCheck(w.legalHoldStore),
 			),
 			w.clock,
 			0,
@@ -43,9 +49,13 @@ func deleteConsumer[Type any, Status StatusType](w *Workflow[Type, Status]) {
 }
 
 func runDelete(
+	workflowName string,
+	processName string,
 	store storeFunc,
 	lookup lookupFunc,
 	customDeleteFn customDelete,
+	codec Codec,
+	isHeld legalHoldCheck,
 ) func(ctx context.Context, e *Event) error {
 	return func(ctx context.Context, e *Event) error {
 		record, err := lookup(ctx, e.ForeignID)
@@ -53,10 +63,24 @@ func runDelete(
 			return err
 		}
 
+		// Records under a legal hold are left in RunStateRequestedDataDeleted and the deletion request is
+		// re-emitted once the hold is lifted.
+		if isHeld != nil {
+			held, err := isHeld(ctx, record.WorkflowName, record.ForeignID)
+			if err != nil {
+				return err
+			}
+
+			if held {
+				metrics.ProcessSkippedEvents.WithLabelValues(workflowName, processName, "legal hold active").Inc()
+				return nil
+			}
+		}
+
 		replacementData := []byte("{'result': 'deleted'}")
 		// If a custom delete has been configured then use the custom delete
 		if customDeleteFn != nil {
-			bytes, err := customDeleteFn(record)
+			bytes, err := customDeleteFn(codec, record)
 			if err != nil {
 				return err
 			}
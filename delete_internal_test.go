@@ -20,7 +20,8 @@ func TestRunDelete(t *testing.T) {
 		Name        string
 		storeFn     func(ctx context.Context, record *Record) error
 		lookupFn    func(ctx context.Context, runID string) (*Record, error)
-		deleteFn    func(wr *Record) ([]byte, error)
+		deleteFn    customDelete
+		isHeld      legalHoldCheck
 		expectedErr error
 	}{
 		{
@@ -43,15 +44,15 @@ func TestRunDelete(t *testing.T) {
 					RunState: RunStateRequestedDataDeleted,
 				}, nil
 			},
-			deleteFn: func(wr *Record) ([]byte, error) {
+			deleteFn: func(codec Codec, wr *Record) ([]byte, error) {
 				var o object
 
-				err := Unmarshal(wr.Object, &o)
+				err := codec.Unmarshal(wr.Object, &o)
 				require.Nil(t, err)
 
 				o.pii = ""
 
-				return Marshal(&o)
+				return codec.Marshal(&o)
 			},
 			expectedErr: nil,
 		},
@@ -96,15 +97,47 @@ func TestRunDelete(t *testing.T) {
 			},
 			expectedErr: testErr,
 		},
+		{
+			Name: "Skip deletion when legal hold is active",
+			lookupFn: func(ctx context.Context, runID string) (*Record, error) {
+				return &Record{
+					RunState: RunStateRequestedDataDeleted,
+				}, nil
+			},
+			storeFn: func(ctx context.Context, record *Record) error {
+				require.Fail(t, "record should not be deleted whilst under legal hold")
+				return nil
+			},
+			isHeld: func(ctx context.Context, workflowName, foreignID string) (bool, error) {
+				return true, nil
+			},
+			expectedErr: nil,
+		},
+		{
+			Name: "Return err on legal hold check error",
+			lookupFn: func(ctx context.Context, runID string) (*Record, error) {
+				return &Record{
+					RunState: RunStateRequestedDataDeleted,
+				}, nil
+			},
+			isHeld: func(ctx context.Context, workflowName, foreignID string) (bool, error) {
+				return false, testErr
+			},
+			expectedErr: testErr,
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.Name, func(t *testing.T) {
 			ctx := context.Background()
 			err := runDelete(
+				"workflow",
+				"delete-consumer",
 				tc.storeFn,
 				tc.lookupFn,
 				tc.deleteFn,
+				JSONCodec{},
+				tc.isHeld,
 			)(ctx, &Event{})
 			require.True(t, errors.Is(err, tc.expectedErr))
 		})
@@ -0,0 +1,212 @@
+package workflow
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// encryptedObjectPrefix marks the Object of runs that have been encrypted using WithEncryption. None of the codecs
+// encode the Object of a run, which is always a struct, starting with a zero byte.
+var encryptedObjectPrefix = []byte("\x00wfenc1")
+
+// ErrKeyNotFound is returned by a KeyProvider when it does not have the key of the ID.
+var ErrKeyNotFound = errors.New("encryption key not found")
+
+// KeyProvider provides the key encryption keys used by WithEncryption. Keys need to be 16, 24, or 32 bytes long to
+// select AES-128, AES-192, or AES-256. The keys are requested every time the Object of a run is encoded or decoded
+// and so implementations that fetch keys from a remote key management service should cache them.
+type KeyProvider interface {
+	// CurrentKey returns the key, and its ID, that the data keys of newly encoded Objects are encrypted with.
+	CurrentKey() (id string, key []byte, err error)
+	// Key returns the key of the ID, which may no longer be the current key, or ErrKeyNotFound.
+	Key(id string) ([]byte, error)
+}
+
+// StaticKeyProvider is a KeyProvider of a fixed set of keys, such as keys loaded from a secrets manager at startup.
+// Keys are rotated by adding a new key to Keys and making it the CurrentID while keeping the previous keys until no
+// runs are encrypted with them.
+type StaticKeyProvider struct {
+	CurrentID string
+	Keys      map[string][]byte
+}
+
+func (p StaticKeyProvider) CurrentKey() (string, []byte, error) {
+	key, err := p.Key(p.CurrentID)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return p.CurrentID, key, nil
+}
+
+func (p StaticKeyProvider) Key(id string) ([]byte, error) {
+	key, ok := p.Keys[id]
+	if !ok {
+		return nil, fmt.Errorf("%w, meta: %v", ErrKeyNotFound, map[string]string{"key_id": id})
+	}
+
+	return key, nil
+}
+
+var _ KeyProvider = (*StaticKeyProvider)(nil)
+
+// WithEncryption envelope encrypts the Object of the workflow's runs, after it has been encoded by the workflow's
+// Codec, so that the Object is encrypted at rest in the RecordStore without each adapter implementing encryption.
+// Every Object is encrypted using AES-GCM with a new data key which is in turn encrypted with the current key of the
+// KeyProvider and stored, along with the ID of that key, alongside the Object. Keys are rotated by changing the
+// KeyProvider's current key as runs are encrypted with the current key the next time that they are stored and the
+// runs that were encrypted with previous keys are decrypted using the ID of their key. Objects that were stored
+// before encryption was enabled are still decoded and are encrypted the next time that they are stored.
+func WithEncryption(keys KeyProvider) BuildOption {
+	return func(bo *buildOptions) {
+		bo.keyProvider = keys
+	}
+}
+
+// encryptedCodec encrypts the Objects encoded by its Codec.
+type encryptedCodec struct {
+	codec Codec
+	keys  KeyProvider
+}
+
+func (c *encryptedCodec) Marshal(v any) ([]byte, error) {
+	plaintext, err := c.codec.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return encryptObject(c.keys, plaintext)
+}
+
+func (c *encryptedCodec) Unmarshal(data []byte, v any) error {
+	if !bytes.HasPrefix(data, encryptedObjectPrefix) {
+		// The Object was stored before encryption was enabled.
+		return c.codec.Unmarshal(data, v)
+	}
+
+	plaintext, err := decryptObject(c.keys, data)
+	if err != nil {
+		return err
+	}
+
+	return c.codec.Unmarshal(plaintext, v)
+}
+
+// ContentType is that of arbitrary binary data as the encrypted Object can only be decoded with the workflow's keys.
+func (c *encryptedCodec) ContentType() string {
+	return "application/octet-stream"
+}
+
+var _ Codec = (*encryptedCodec)(nil)
+
+// encryptObject encrypts the plaintext with a new data key and returns the envelope of the prefix, the ID of the key
+// encryption key, the encrypted data key, and the encrypted plaintext, each of which is preceded by its length.
+func encryptObject(keys KeyProvider, plaintext []byte) ([]byte, error) {
+	keyID, key, err := keys.CurrentKey()
+	if err != nil {
+		return nil, fmt.Errorf("encrypt object: %w", err)
+	}
+
+	dataKey := make([]byte, 32)
+	_, err = rand.Read(dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("encrypt object: %w", err)
+	}
+
+	wrappedKey, err := seal(key, dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("encrypt object: %w, meta: %v", err, map[string]string{"key_id": keyID})
+	}
+
+	ciphertext, err := seal(dataKey, plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("encrypt object: %w", err)
+	}
+
+	envelope := bytes.Clone(encryptedObjectPrefix)
+	for _, part := range [][]byte{[]byte(keyID), wrappedKey, ciphertext} {
+		envelope = binary.AppendUvarint(envelope, uint64(len(part)))
+		envelope = append(envelope, part...)
+	}
+
+	return envelope, nil
+}
+
+// decryptObject decrypts the envelope created by encryptObject using the key encryption key of its ID.
+func decryptObject(keys KeyProvider, envelope []byte) ([]byte, error) {
+	data := envelope[len(encryptedObjectPrefix):]
+
+	var parts [3][]byte
+	for i := range parts {
+		n, read := binary.Uvarint(data)
+		if read <= 0 || uint64(len(data)-read) < n {
+			return nil, errors.New("decrypt object: invalid envelope")
+		}
+
+		parts[i] = data[read : read+int(n)]
+		data = data[read+int(n):]
+	}
+
+	keyID := string(parts[0])
+	key, err := keys.Key(keyID)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt object: %w", err)
+	}
+
+	dataKey, err := open(key, parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("decrypt object: %w, meta: %v", err, map[string]string{"key_id": keyID})
+	}
+
+	plaintext, err := open(dataKey, parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("decrypt object: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+// seal encrypts the plaintext using AES-GCM with the key and returns the nonce followed by the ciphertext.
+func seal(key, plaintext []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open decrypts the nonce and ciphertext returned by seal.
+func open(key, sealed []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
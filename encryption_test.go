@@ -0,0 +1,236 @@
+package workflow_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestWithEncryption(t *testing.T) {
+	keys := workflow.StaticKeyProvider{
+		CurrentID: "key-1",
+		Keys: map[string][]byte{
+			"key-1": bytes.Repeat([]byte{1}, 32),
+		},
+	}
+
+	b := workflow.NewBuilder[string, status]("encryption")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		*r.Object += " number"
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	streamer := memstreamer.New()
+	recordStore := memrecordstore.New()
+	wf := b.Build(streamer, recordStore, memrolescheduler.New(), workflow.WithEncryption(keys))
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	value := "card"
+	runID, err := wf.Trigger(ctx, "foreignID", StatusStart, workflow.WithInitialValue[string, status](&value))
+	require.Nil(t, err)
+
+	r, err := wf.Await(ctx, "foreignID", runID, StatusEnd, workflow.WithAwaitPollingFrequency(10*time.Millisecond))
+	require.Nil(t, err)
+	require.Equal(t, "card number", *r.Object)
+
+	record, err := recordStore.Lookup(ctx, runID)
+	require.Nil(t, err)
+	require.NotContains(t, string(record.Object), "card")
+
+	// The workflow can no longer decrypt its runs without the key.
+	withoutKey := workflow.NewClient[string, status](wf.Name(), streamer, recordStore, workflow.WithEncryption(
+		workflow.StaticKeyProvider{
+			CurrentID: "key-2",
+			Keys: map[string][]byte{
+				"key-2": bytes.Repeat([]byte{2}, 32),
+			},
+		},
+	))
+	_, err = withoutKey.GetRun(ctx, "foreignID", runID)
+	require.ErrorIs(t, err, workflow.ErrKeyNotFound)
+}
+
+func TestWithEncryption_KeyRotation(t *testing.T) {
+	streamer := memstreamer.New()
+	recordStore := memrecordstore.New()
+	ctx := context.Background()
+
+	keys := workflow.StaticKeyProvider{
+		CurrentID: "key-1",
+		Keys: map[string][]byte{
+			"key-1": bytes.Repeat([]byte{1}, 32),
+		},
+	}
+
+	client := workflow.NewClient[string, status]("rotation", streamer, recordStore, workflow.WithEncryption(keys))
+
+	value := "secret"
+	runID, err := client.Trigger(ctx, "foreignID", StatusStart, workflow.WithInitialValue[string, status](&value))
+	require.Nil(t, err)
+
+	before, err := recordStore.Lookup(ctx, runID)
+	require.Nil(t, err)
+
+	// Rotate the current key while keeping the previous key for the runs that are encrypted with it.
+	keys.Keys["key-2"] = bytes.Repeat([]byte{2}, 32)
+	keys.CurrentID = "key-2"
+	rotated := workflow.NewClient[string, status]("rotation", streamer, recordStore, workflow.WithEncryption(keys))
+
+	r, err := rotated.GetRun(ctx, "foreignID", runID)
+	require.Nil(t, err)
+	require.Equal(t, "secret", *r.Object)
+
+	secondRunID, err := rotated.Trigger(ctx, "foreignID-2", StatusStart, workflow.WithInitialValue[string, status](&value))
+	require.Nil(t, err)
+
+	after, err := recordStore.Lookup(ctx, secondRunID)
+	require.Nil(t, err)
+	require.Contains(t, string(before.Object), "key-1")
+	require.Contains(t, string(after.Object), "key-2")
+}
+
+func TestWithEncryption_PlaintextObjects(t *testing.T) {
+	recordStore := memrecordstore.New()
+	ctx := context.Background()
+
+	value := "stored before encryption"
+	payload, err := workflow.Marshal(&value)
+	require.Nil(t, err)
+
+	err = update(ctx, recordStore, &workflow.Record{
+		WorkflowName: "plaintext",
+		ForeignID:    "foreignID",
+		RunID:        "runID",
+		Status:       int(StatusStart),
+		RunState:     workflow.RunStateRunning,
+		Object:       payload,
+	})
+	require.Nil(t, err)
+
+	client := workflow.NewClient[string, status]("plaintext", memstreamer.New(), recordStore, workflow.WithEncryption(
+		workflow.StaticKeyProvider{
+			CurrentID: "key-1",
+			Keys: map[string][]byte{
+				"key-1": bytes.Repeat([]byte{1}, 32),
+			},
+		},
+	))
+
+	r, err := client.GetRun(ctx, "foreignID", "runID")
+	require.Nil(t, err)
+	require.Equal(t, value, *r.Object)
+}
+
+func TestWithEncryption_Redact(t *testing.T) {
+	keys := workflow.StaticKeyProvider{
+		CurrentID: "key-1",
+		Keys: map[string][]byte{
+			"key-1": bytes.Repeat([]byte{1}, 32),
+		},
+	}
+
+	build := func(recordStore workflow.RecordStore, opts ...workflow.BuildOption) *workflow.Workflow[MyType, status] {
+		b := workflow.NewBuilder[MyType, status]("encryption redaction")
+		b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+			return StatusEnd, nil
+		}, StatusEnd)
+
+		opts = append(opts, workflow.WithEncryption(keys))
+		return b.Build(memstreamer.New(), recordStore, memrolescheduler.New(), opts...)
+	}
+
+	ctx := context.Background()
+	object := MyType{UserID: 9, Email: "andrew@workflow.com"}
+
+	t.Run("Redacts decrypted object", func(t *testing.T) {
+		recordStore := memrecordstore.New()
+		wf := build(recordStore, workflow.WithRedaction(func(object *MyType) error {
+			object.Email = "****"
+			return nil
+		}))
+		wf.Run(ctx)
+		t.Cleanup(wf.Stop)
+
+		runID, err := wf.Trigger(ctx, "foreignID", StatusStart, workflow.WithInitialValue[MyType, status](&object))
+		require.Nil(t, err)
+
+		record, err := recordStore.Lookup(ctx, runID)
+		require.Nil(t, err)
+
+		redacted, err := wf.Redact(record)
+		require.Nil(t, err)
+
+		var actual MyType
+		err = workflow.Unmarshal(redacted, &actual)
+		require.Nil(t, err)
+		require.Equal(t, MyType{UserID: 9, Email: "****"}, actual)
+	})
+
+	t.Run("Returns decrypted object when no redaction is configured", func(t *testing.T) {
+		recordStore := memrecordstore.New()
+		wf := build(recordStore)
+		wf.Run(ctx)
+		t.Cleanup(wf.Stop)
+
+		runID, err := wf.Trigger(ctx, "foreignID", StatusStart, workflow.WithInitialValue[MyType, status](&object))
+		require.Nil(t, err)
+
+		record, err := recordStore.Lookup(ctx, runID)
+		require.Nil(t, err)
+
+		plain, err := wf.Redact(record)
+		require.Nil(t, err)
+
+		var actual MyType
+		err = workflow.Unmarshal(plain, &actual)
+		require.Nil(t, err)
+		require.Equal(t, object, actual)
+	})
+}
+
+func TestWithEncryption_AdminTrigger(t *testing.T) {
+	keys := workflow.StaticKeyProvider{
+		CurrentID: "key-1",
+		Keys: map[string][]byte{
+			"key-1": bytes.Repeat([]byte{1}, 32),
+		},
+	}
+
+	b := workflow.NewBuilder[MyType, status]("encryption admin trigger")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	recordStore := memrecordstore.New()
+	wf := b.Build(memstreamer.New(), recordStore, memrolescheduler.New(), workflow.WithEncryption(keys))
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	object := MyType{UserID: 9, Email: "andrew@workflow.com"}
+	plain, err := workflow.Marshal(&object)
+	require.Nil(t, err)
+
+	runID, err := wf.AdminTrigger(ctx, "foreignID", int(StatusStart), plain)
+	require.Nil(t, err)
+
+	record, err := recordStore.Lookup(ctx, runID)
+	require.Nil(t, err)
+	require.NotContains(t, string(record.Object), "andrew@workflow.com")
+
+	r, err := wf.Await(ctx, "foreignID", runID, StatusEnd, workflow.WithAwaitPollingFrequency(10*time.Millisecond))
+	require.Nil(t, err)
+	require.Equal(t, object, *r.Object)
+}
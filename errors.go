@@ -8,4 +8,7 @@ var (
 	ErrWorkflowInProgress   = errors.New("current workflow still in progress - retry once complete")
 	ErrOutboxRecordNotFound = errors.New("outbox record not found")
 	ErrInvalidTransition    = errors.New("invalid transition")
+	ErrLegalHoldActive      = errors.New("legal hold already active")
+	ErrLegalHoldNotFound    = errors.New("legal hold not found")
+	ErrRunVersionMismatch   = errors.New("run triggered by a different version of the workflow")
 )
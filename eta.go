@@ -0,0 +1,208 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// estimateSampleSize is the number of the workflow's latest runs whose histories are used to estimate the
+	// completion of runs.
+	estimateSampleSize = 500
+	// estimateStatsTTL is how long the statistics computed from the histories of the latest runs are reused for.
+	estimateStatsTTL = time.Minute
+)
+
+// CompletionEstimate is the estimated completion of a run, as returned by EstimateCompletion.
+type CompletionEstimate[Status StatusType] struct {
+	RunID  string
+	Status Status
+	// Path is the statuses that the run is expected to move through after its current status, ending with the
+	// status that the run is expected to complete in. It is empty when the run has finished.
+	Path []Status
+	// Remaining is the expected time until the run completes.
+	Remaining time.Duration
+	// CompletesAt is when the run is expected to complete, or when the run finished for runs that have finished.
+	CompletesAt time.Time
+	// Progress is the fraction, between zero and one, of the run's expected total duration that has elapsed since the
+	// run was created.
+	Progress float64
+}
+
+// EstimateCompletion estimates when the run will complete, such as for telling a customer when their application is
+// expected to be processed by. The run is expected to spend the median time that the workflow's latest runs spent in
+// each status and to move on from each status to the status that the latest runs most often moved on to, stopping at
+// a terminal status or once the path revisits a status. The time that the run has already spent in its current status
+// is taken off of the status' median. The statistics are computed from the histories of the latest 500 runs of the
+// workflow and are reused for a minute. Statuses that none of the latest runs have moved on from are expected to take
+// no time. ErrRecordNotFound is returned when the run does not exist or does not belong to the workflow.
+func (w *Workflow[Type, Status]) EstimateCompletion(
+	ctx context.Context,
+	runID string,
+) (*CompletionEstimate[Status], error) {
+	record, err := w.recordStore.Lookup(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	if record.WorkflowName != w.Name() {
+		return nil, fmt.Errorf("estimate completion: %w, meta: %v", ErrRecordNotFound, map[string]string{
+			"run_id": runID,
+		})
+	}
+
+	estimate := CompletionEstimate[Status]{
+		RunID:  record.RunID,
+		Status: Status(record.Status),
+	}
+
+	if record.RunState.Finished() {
+		estimate.CompletesAt = record.UpdatedAt
+		estimate.Progress = 1
+		return &estimate, nil
+	}
+
+	stats, err := w.estimateStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := w.clock.Now()
+	estimate.Remaining = max(0, stats.medians[record.Status]-now.Sub(statusEnteredAt(record)))
+
+	visited := map[int]bool{record.Status: true}
+	current := record.Status
+	for !w.statusGraph.IsTerminal(current) {
+		next, ok := stats.next(current, w.statusGraph.Transitions(current))
+		if !ok || visited[next] {
+			break
+		}
+
+		visited[next] = true
+		estimate.Path = append(estimate.Path, Status(next))
+		estimate.Remaining += stats.medians[next]
+		current = next
+	}
+
+	estimate.CompletesAt = now.Add(estimate.Remaining)
+
+	elapsed := now.Sub(record.CreatedAt)
+	estimate.Progress = 1
+	if elapsed+estimate.Remaining > 0 {
+		estimate.Progress = float64(elapsed) / float64(elapsed+estimate.Remaining)
+	}
+
+	return &estimate, nil
+}
+
+// statusStats are the statistics of the time that runs spent in each status and the statuses that they moved on to.
+type statusStats struct {
+	computedAt time.Time
+	// medians are the median durations that runs spent in each status before moving on.
+	medians map[int]time.Duration
+	// transitions are the number of times that runs moved on from each status to each of the next statuses.
+	transitions map[int]map[int]int
+}
+
+// next returns the status that runs most often moved on to from the status, preferring the earliest of the status'
+// transitions when they were equally common.
+func (s *statusStats) next(status int, transitions []int) (int, bool) {
+	var (
+		next  int
+		count int
+	)
+	for _, to := range transitions {
+		if s.transitions[status][to] > count {
+			next = to
+			count = s.transitions[status][to]
+		}
+	}
+
+	if count == 0 {
+		if len(transitions) == 0 {
+			return 0, false
+		}
+
+		return transitions[0], true
+	}
+
+	return next, true
+}
+
+// estimateStats returns the statistics of the workflow's latest runs, computing them again once they are older than
+// estimateStatsTTL.
+func (w *Workflow[Type, Status]) estimateStats(ctx context.Context) (*statusStats, error) {
+	w.estimateStatsMu.Lock()
+	defer w.estimateStatsMu.Unlock()
+
+	if w.statusStats != nil && w.clock.Since(w.statusStats.computedAt) < estimateStatsTTL {
+		return w.statusStats, nil
+	}
+
+	durations := make(map[int][]time.Duration)
+	transitions := make(map[int]map[int]int)
+
+	var offset int64
+	for offset < estimateSampleSize {
+		records, err := w.recordStore.List(ctx, w.Name(), offset, listRunsPageSize, OrderTypeDescending)
+		if err != nil {
+			return nil, fmt.Errorf("estimate completion: %w, meta: %v", err, map[string]string{
+				"offset": fmt.Sprint(offset),
+			})
+		}
+
+		for _, record := range records {
+			collectStatusStats(record.Meta.History, durations, transitions)
+		}
+
+		if len(records) < listRunsPageSize {
+			break
+		}
+
+		offset += int64(len(records))
+	}
+
+	stats := statusStats{
+		computedAt:  w.clock.Now(),
+		medians:     make(map[int]time.Duration, len(durations)),
+		transitions: transitions,
+	}
+
+	for status, d := range durations {
+		stats.medians[status] = median(d)
+	}
+
+	w.statusStats = &stats
+	return &stats, nil
+}
+
+// collectStatusStats adds the time that the run spent in each status, and the status that it moved on to, from the
+// run's history.
+func collectStatusStats(history []Transition, durations map[int][]time.Duration, transitions map[int]map[int]int) {
+	var (
+		current   int
+		enteredAt time.Time
+	)
+	for i, t := range history {
+		if i == 0 {
+			current = t.ToStatus
+			enteredAt = t.At
+			continue
+		}
+
+		// Transitions that only change the run state, such as pausing the run, keep the status of the run.
+		if t.ToStatus == current {
+			continue
+		}
+
+		durations[current] = append(durations[current], t.At.Sub(enteredAt))
+		if transitions[current] == nil {
+			transitions[current] = make(map[int]int)
+		}
+
+		transitions[current][t.ToStatus]++
+		current = t.ToStatus
+		enteredAt = t.At
+	}
+}
@@ -0,0 +1,94 @@
+package workflow_test
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	clock_testing "k8s.io/utils/clock/testing"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestEstimateCompletion(t *testing.T) {
+	now := time.Date(2024, time.April, 19, 9, 0, 0, 0, time.UTC)
+	clock := clock_testing.NewFakeClock(now)
+
+	next := func(to status) workflow.CallbackFunc[string, status] {
+		return func(ctx context.Context, r *workflow.Run[string, status], reader io.Reader) (status, error) {
+			return to, nil
+		}
+	}
+
+	b := workflow.NewBuilder[string, status]("estimate")
+	b.AddCallback(StatusStart, next(StatusMiddle), StatusMiddle)
+	b.AddCallback(StatusMiddle, next(StatusEnd), StatusEnd)
+
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(memrecordstore.WithClock(clock)),
+		memrolescheduler.New(),
+		workflow.WithClock(clock),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	// Each run waits in the start and middle statuses for the durations before moving on.
+	runs := []struct {
+		start  time.Duration
+		middle time.Duration
+	}{
+		{start: time.Minute, middle: 10 * time.Minute},
+		{start: 2 * time.Minute, middle: 10 * time.Minute},
+		{start: 3 * time.Minute, middle: 20 * time.Minute},
+	}
+
+	var lastRunID string
+	for i, run := range runs {
+		foreignID := "foreignID-" + strconv.Itoa(i)
+		runID, err := wf.Trigger(ctx, foreignID, StatusStart)
+		require.Nil(t, err)
+
+		clock.Step(run.start)
+		err = wf.Callback(ctx, foreignID, StatusStart, nil)
+		require.Nil(t, err)
+
+		clock.Step(run.middle)
+		err = wf.Callback(ctx, foreignID, StatusMiddle, nil)
+		require.Nil(t, err)
+
+		lastRunID = runID
+	}
+
+	runID, err := wf.Trigger(ctx, "foreignID", StatusStart)
+	require.Nil(t, err)
+
+	clock.Step(30 * time.Second)
+
+	estimate, err := wf.EstimateCompletion(ctx, runID)
+	require.Nil(t, err)
+	require.Equal(t, StatusStart, estimate.Status)
+	require.Equal(t, []status{StatusMiddle, StatusEnd}, estimate.Path)
+	require.Equal(t, 11*time.Minute+30*time.Second, estimate.Remaining)
+	require.Equal(t, clock.Now().Add(estimate.Remaining), estimate.CompletesAt)
+	require.InDelta(t, 30.0/720.0, estimate.Progress, 0.0001)
+
+	// Runs that have finished are estimated to have completed when they finished.
+	estimate, err = wf.EstimateCompletion(ctx, lastRunID)
+	require.Nil(t, err)
+	require.Equal(t, StatusEnd, estimate.Status)
+	require.Empty(t, estimate.Path)
+	require.Equal(t, time.Duration(0), estimate.Remaining)
+	require.Equal(t, 1.0, estimate.Progress)
+
+	_, err = wf.EstimateCompletion(ctx, "unknown")
+	require.ErrorIs(t, err, workflow.ErrRecordNotFound)
+}
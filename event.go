@@ -42,6 +42,10 @@ type ConnectorEvent struct {
 	Headers map[string]string
 	// CreatedAt is the time that the event was produced and is generated by the event streamer.
 	CreatedAt time.Time
+	// Source is the name of the stream, such as the topic, that the event was consumed from.
+	Source string
+	// Payload is the body of the event which can be decoded into a type using DecodeConnectorEvents.
+	Payload []byte
 }
 
 type OutboxEvent struct {
@@ -74,6 +78,11 @@ type OutboxEventData struct {
 // retrievable from the outbox.
 func MakeOutboxEventData(record Record) (OutboxEventData, error) {
 	topic := Topic(record.WorkflowName, record.Status)
+	if record.Meta.Pinned {
+		topic = VersionedTopic(record.WorkflowName, record.Meta.Version, record.Status)
+	}
+
+	topic = PriorityTopic(topic, record.Meta.Priority)
 
 	// Any record that is updated with a RunState of RunStateRequestedDataDeleted has it's events pushed into
 	// the "delete" topic so that the event can be processed async and not be spread across the workflow's status
@@ -89,7 +98,8 @@ func MakeOutboxEventData(record Record) (OutboxEventData, error) {
 	if record.RunState == RunStatePaused ||
 		record.RunState == RunStateCancelled ||
 		record.RunState == RunStateDataDeleted ||
-		record.RunState == RunStateCompleted {
+		record.RunState == RunStateCompleted ||
+		record.RunState == RunStateQuarantined {
 		topic = RunStateChangeTopic(record.WorkflowName)
 	}
 
@@ -99,6 +109,17 @@ func MakeOutboxEventData(record Record) (OutboxEventData, error) {
 	headers[string(HeaderTopic)] = topic
 	headers[string(HeaderRunID)] = record.RunID
 	headers[string(HeaderRunState)] = strconv.FormatInt(int64(record.RunState), 10)
+	if record.Meta.Version != 0 {
+		headers[string(HeaderVersion)] = strconv.FormatInt(int64(record.Meta.Version), 10)
+	}
+
+	if record.Meta.TraceParent != "" {
+		headers[string(HeaderTraceParent)] = record.Meta.TraceParent
+	}
+
+	if record.Meta.StartAt != nil {
+		headers[string(HeaderStartAt)] = strconv.FormatInt(record.Meta.StartAt.UnixNano(), 10)
+	}
 
 	r := outboxpb.OutboxRecord{
 		RunId:   record.RunID,
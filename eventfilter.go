@@ -93,3 +93,20 @@ func filterByRunState(runState RunState) EventFilter {
 		return rs != strconv.FormatInt(int64(runState), 10)
 	}
 }
+
+func filterByFinished() EventFilter {
+	return func(e *Event) bool {
+		rs, err := strconv.ParseInt(e.Headers[HeaderRunState], 10, 64)
+		if err != nil {
+			return true
+		}
+
+		return !RunState(rs).Finished()
+	}
+}
+
+func filterByStatus(status int) EventFilter {
+	return func(e *Event) bool {
+		return e.Type != status
+	}
+}
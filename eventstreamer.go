@@ -14,11 +14,61 @@ type EventStreamer interface {
 
 // EventSender defines the common interface that the EventStreamer adapter must implement for allowing the workflow
 // to send events to the event streamer.
+//
+// Retry contract: workflow delivers events at least once. An event is sent again when Send returns an error, or when
+// the event was sent but could not be removed from the outbox, and so implementations must allow the same event to
+// be sent more than once. Implementations may retry internally but must return an error if the event may not have
+// been delivered, as returning nil results in the event being removed from the outbox. Consumers skip events of runs
+// that have already moved on and so duplicate events are safe.
 type EventSender interface {
 	Send(ctx context.Context, foreignID string, statusType int, headers map[Header]string) error
 	Close() error
 }
 
+// DeliveryCallback is called once an event provided to SendAsync has been delivered, in which case err is nil, or
+// has failed to be delivered.
+type DeliveryCallback func(err error)
+
+// AsyncEventSender can optionally be implemented by an EventSender for event streaming platforms that support
+// asynchronous producing, such as Kafka. When implemented, the outbox sends all the events of a batch using SendAsync
+// and then calls Flush instead of waiting for each event to be delivered before sending the next. The same retry
+// contract as EventSender applies where only the events whose callback received a nil error are removed from the
+// outbox.
+type AsyncEventSender interface {
+	EventSender
+
+	// SendAsync queues the event to be sent and returns without waiting for the event to be delivered. An error
+	// is only returned if the event could not be queued, in which case callback must not be called.
+	SendAsync(
+		ctx context.Context,
+		foreignID string,
+		statusType int,
+		headers map[Header]string,
+		callback DeliveryCallback,
+	) error
+	// Flush blocks until all events queued by SendAsync have either been delivered or have failed and their
+	// callbacks have returned.
+	Flush(ctx context.Context) error
+}
+
+// BatchEvent is an event that is sent together with the other events of its batch using BatchEventSender.
+type BatchEvent struct {
+	ForeignID string
+	Type      int
+	Headers   map[Header]string
+}
+
+// BatchEventSender can optionally be implemented by an EventSender for event streaming platforms that can publish
+// several events in a single request. When implemented, the outbox sends the events of each topic using SendBatch in
+// batches of up to the outbox's batch size, see WithOutboxBatchSize, rather than sending the events one at a time.
+// The same retry contract as EventSender applies to the batch as a whole where the events of a batch are only
+// removed from the outbox once SendBatch returns nil. BatchEventSender takes precedence over AsyncEventSender.
+type BatchEventSender interface {
+	EventSender
+
+	SendBatch(ctx context.Context, events []BatchEvent) error
+}
+
 // EventReceiver defines the common interface that the EventStreamer adapter must implement for allowing the workflow
 // to receive events.
 type EventReceiver interface {
@@ -26,6 +76,20 @@ type EventReceiver interface {
 	Close() error
 }
 
+// ShardedReceiver can optionally be implemented by an EventReceiver that only receives the events of the shard
+// provided using WithReceiverShard, such as by assigning the partitions of a topic to the shards. When Sharded
+// returns true workflow does not filter the events of the receiver by shard.
+type ShardedReceiver interface {
+	EventReceiver
+	Sharded() bool
+}
+
+// isSharded returns true when the receiver only receives the events of its shard.
+func isSharded(receiver EventReceiver) bool {
+	sharded, ok := receiver.(ShardedReceiver)
+	return ok && sharded.Sharded()
+}
+
 // Ack is used for the event streamer to safeUpdate its cursor of what messages have
 // been consumed. If Ack is not called then the event streamer, depending on implementation,
 // will likely not keep track of which records / events have been consumed.
@@ -40,11 +104,27 @@ const (
 	HeaderRunID         Header = "run_id"
 	HeaderRunState      Header = "run_state"
 	HeaderConnectorData Header = "connector_data"
+	HeaderVersion       Header = "version"
+	HeaderTraceParent   Header = "traceparent"
+	HeaderStartAt       Header = "start_at"
+
+	HeaderDeadLetterRecord   Header = "dead_letter_record"
+	HeaderDeadLetterProcess  Header = "dead_letter_process"
+	HeaderDeadLetterError    Header = "dead_letter_error"
+	HeaderDeadLetterFailedAt Header = "dead_letter_failed_at"
 )
 
 type ReceiverOptions struct {
 	PollFrequency time.Duration
-	Lag           time.Duration
+	// MaxPollFrequency is only set when the consumer is configured with AdaptivePolling. Implementations that
+	// support adaptive polling should back off from PollFrequency towards MaxPollFrequency while the topic is idle
+	// and return to PollFrequency once events are received.
+	MaxPollFrequency time.Duration
+	Lag              time.Duration
+	// Shard and TotalShards are only set for the receivers of step consumers configured with a ParallelCount
+	// greater than 1. Shard starts at 1.
+	Shard       int
+	TotalShards int
 }
 
 type ReceiverOption func(*ReceiverOptions)
@@ -54,3 +134,16 @@ func WithReceiverPollFrequency(d time.Duration) ReceiverOption {
 		opt.PollFrequency = d
 	}
 }
+
+func WithReceiverMaxPollFrequency(d time.Duration) ReceiverOption {
+	return func(opt *ReceiverOptions) {
+		opt.MaxPollFrequency = d
+	}
+}
+
+func WithReceiverShard(shard, totalShards int) ReceiverOption {
+	return func(opt *ReceiverOptions) {
+		opt.Shard = shard
+		opt.TotalShards = totalShards
+	}
+}
@@ -0,0 +1,59 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ExecuteSync triggers a run and blocks until the run has finished, by being completed, cancelled, or having its data
+// deleted, and returns the run's final record. If the provided context ends before the run has finished then the run
+// is cancelled, so that work isn't carried out on behalf of a caller that has gone away, and the context's error is
+// returned.
+func (w *Workflow[Type, Status]) ExecuteSync(
+	ctx context.Context,
+	foreignID string,
+	startingStatus Status,
+	opts ...TriggerOption[Type, Status],
+) (*TypedRecord[Type, Status], error) {
+	runID, err := w.Trigger(ctx, foreignID, startingStatus, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	_, r, err := w.AwaitTerminal(ctx, foreignID, runID)
+	if ctx.Err() != nil {
+		return nil, errors.Join(ctx.Err(), w.cancelUnfinished(context.WithoutCancel(ctx), runID))
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &r.TypedRecord, nil
+}
+
+// cancelUnfinished cancels the run if it has not yet finished.
+func (w *Workflow[Type, Status]) cancelUnfinished(ctx context.Context, runID string) error {
+	record, err := w.recordStore.Lookup(ctx, runID)
+	if err != nil {
+		return err
+	}
+
+	if record.RunState.Finished() {
+		return nil
+	}
+
+	// Runs that have not yet been consumed are marked as running in order to be cancelled.
+	if record.RunState == RunStateInitiated {
+		record.RunState = RunStateRunning
+	}
+
+	err = NewRunStateController(w.recordStore.Store, record).Cancel(ctx)
+	if err != nil {
+		return fmt.Errorf("cancel run: %w, meta: %v", err, map[string]string{
+			"run_id":     runID,
+			"foreign_id": record.ForeignID,
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package workflow_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestExecuteSync(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("execute sync")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		*r.Object = "processed"
+		return StatusMiddle, nil
+	}, StatusMiddle)
+	b.AddStep(StatusMiddle, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	wf := b.Build(memstreamer.New(), memrecordstore.New(), memrolescheduler.New())
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	record, err := wf.ExecuteSync(ctx, "foreignID", StatusStart)
+	require.Nil(t, err)
+	require.Equal(t, StatusEnd, record.Status)
+	require.Equal(t, workflow.RunStateCompleted, record.RunState)
+	require.Equal(t, "processed", *record.Object)
+}
+
+func TestExecuteSyncCancelsRunWhenContextEnds(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("execute sync")
+	b.AddCallback(StatusStart, func(ctx context.Context, r *workflow.Run[string, status], reader io.Reader) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	recordStore := memrecordstore.New()
+	wf := b.Build(memstreamer.New(), recordStore, memrolescheduler.New())
+
+	wf.Run(context.Background())
+	t.Cleanup(wf.Stop)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	t.Cleanup(cancel)
+
+	_, err := wf.ExecuteSync(ctx, "foreignID", StatusStart)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	record, err := recordStore.Latest(context.Background(), wf.Name(), "foreignID")
+	require.Nil(t, err)
+	require.Equal(t, workflow.RunStateCancelled, record.RunState)
+}
@@ -0,0 +1,156 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+const defaultFenceCheckInterval = 10 * time.Second
+
+// ErrFenced is returned by a process when a newer, incompatible, definition of the workflow has been registered and
+// the host must stop consuming to avoid corrupting runs.
+var ErrFenced = errors.New("workflow fenced by a newer definition")
+
+// ErrDefinitionNotFound is returned by a DefinitionStore when no definition has been registered for the workflow.
+var ErrDefinitionNotFound = errors.New("definition not found")
+
+// Definition describes the shape of the workflow that a host is running.
+type Definition struct {
+	WorkflowName string
+	// Version is the graph version configured with WithGraphVersion.
+	Version int
+	// Hash is a hash of the workflow's status graph.
+	Hash         string
+	RegisteredAt time.Time
+}
+
+// DefinitionStore can optionally be implemented by the RecordStore to support WithDeploymentFencing.
+type DefinitionStore interface {
+	// RegisterDefinition must replace the active definition of the workflow if the provided definition's version is
+	// equal to or greater than the active definition's version. Older definitions must be ignored without error.
+	RegisterDefinition(ctx context.Context, d Definition) error
+	// ActiveDefinition returns the definition with the highest version that has been registered for the workflow.
+	// ErrDefinitionNotFound is returned if none have been registered.
+	ActiveDefinition(ctx context.Context, workflowName string) (*Definition, error)
+}
+
+// WithDeploymentFencing registers the host's definition of the workflow (its graph version and graph hash) with
+// the RecordStore when the workflow's consumers start. Consumers and timeouts stop processing, and move into
+// StateFenced, when the RecordStore shows that a newer version has been registered by another host. Fenced processes
+// give up their roles so that the hosts running the newer version can take them over. This prevents old hosts from
+// processing runs during a rollout as long as the version, configured using WithGraphVersion, is incremented
+// whenever the graph changes. The RecordStore must implement DefinitionStore.
+func WithDeploymentFencing() BuildOption {
+	return func(bo *buildOptions) {
+		bo.deploymentFencing = true
+	}
+}
+
+type fence struct {
+	store      DefinitionStore
+	definition Definition
+	clock      clock.Clock
+	interval   time.Duration
+
+	mu         sync.Mutex
+	registered bool
+	checkedAt  time.Time
+	fenced     bool
+}
+
+func newFence(store DefinitionStore, definition Definition, clock clock.Clock) *fence {
+	return &fence{
+		store:      store,
+		definition: definition,
+		clock:      clock,
+		interval:   defaultFenceCheckInterval,
+	}
+}
+
+// check returns ErrFenced if a newer version of the definition is active. The active definition is only looked up once per
+// interval. A nil fence is never fenced.
+func (f *fence) check(ctx context.Context) error {
+	if f == nil {
+		return nil
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if !f.registered {
+		d := f.definition
+		d.RegisteredAt = f.clock.Now()
+		err := f.store.RegisterDefinition(ctx, d)
+		if err != nil {
+			return err
+		}
+
+		f.registered = true
+	}
+
+	if !f.checkedAt.IsZero() && f.clock.Since(f.checkedAt) < f.interval {
+		if f.fenced {
+			return ErrFenced
+		}
+
+		return nil
+	}
+
+	active, err := f.store.ActiveDefinition(ctx, f.definition.WorkflowName)
+	if errors.Is(err, ErrDefinitionNotFound) {
+		// Registration could have been removed from the store and should be attempted again.
+		f.registered = false
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	f.checkedAt = f.clock.Now()
+	// Only a strictly higher version fences the host. Hosts of the same version but with a different graph can't
+	// tell which of them is newer, as each replaces the other's registration when it restarts, and so changes to the
+	// graph must be accompanied by a new version.
+	f.fenced = active.Version > f.definition.Version
+
+	if f.fenced {
+		return ErrFenced
+	}
+
+	return nil
+}
+
+// guard wraps the consumer function and ensures that no events are processed, nor acknowledged, once the host
+// has been fenced.
+func (f *fence) guard(fn func(ctx context.Context, e *Event) error) func(ctx context.Context, e *Event) error {
+	if f == nil {
+		return fn
+	}
+
+	return func(ctx context.Context, e *Event) error {
+		err := f.check(ctx)
+		if err != nil {
+			return err
+		}
+
+		return fn(ctx, e)
+	}
+}
+
+// guardBatch is the equivalent of guard for batch consumers.
+func (f *fence) guardBatch(fn func(ctx context.Context, events []*Event) error) func(ctx context.Context, events []*Event) error {
+	if f == nil {
+		return fn
+	}
+
+	return func(ctx context.Context, events []*Event) error {
+		err := f.check(ctx)
+		if err != nil {
+			return err
+		}
+
+		return fn(ctx, events)
+	}
+}
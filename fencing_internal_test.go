@@ -0,0 +1,113 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	clock_testing "k8s.io/utils/clock/testing"
+)
+
+type definitionStore struct {
+	active *Definition
+}
+
+func (s *definitionStore) RegisterDefinition(ctx context.Context, d Definition) error {
+	if s.active != nil && s.active.Version > d.Version {
+		return nil
+	}
+
+	s.active = &d
+	return nil
+}
+
+func (s *definitionStore) ActiveDefinition(ctx context.Context, workflowName string) (*Definition, error) {
+	if s.active == nil {
+		return nil, ErrDefinitionNotFound
+	}
+
+	d := *s.active
+	return &d, nil
+}
+
+func TestFence(t *testing.T) {
+	ctx := context.Background()
+	clock := clock_testing.NewFakeClock(time.Now())
+	store := &definitionStore{}
+
+	f := newFence(store, Definition{WorkflowName: "example", Version: 1, Hash: "a"}, clock)
+	require.Nil(t, f.check(ctx))
+	require.Equal(t, "a", store.active.Hash)
+
+	// A newer host registers its definition.
+	err := store.RegisterDefinition(ctx, Definition{WorkflowName: "example", Version: 2, Hash: "b"})
+	require.Nil(t, err)
+
+	// The active definition is cached until the interval has passed.
+	require.Nil(t, f.check(ctx))
+
+	clock.Step(defaultFenceCheckInterval)
+	require.True(t, errors.Is(f.check(ctx), ErrFenced))
+
+	// The fence remains whilst the cached result is used.
+	clock.Step(time.Second)
+	require.True(t, errors.Is(f.check(ctx), ErrFenced))
+}
+
+func TestFence_sameVersionDifferentGraph(t *testing.T) {
+	ctx := context.Background()
+	clock := clock_testing.NewFakeClock(time.Now())
+	store := &definitionStore{}
+
+	rollout := newFence(store, Definition{WorkflowName: "example", Version: 1, Hash: "b"}, clock)
+	require.Nil(t, rollout.check(ctx))
+
+	// A host of the same version, such as an old pod restarting during the rollout, replaces the active definition.
+	old := newFence(store, Definition{WorkflowName: "example", Version: 1, Hash: "a"}, clock)
+	require.Nil(t, old.check(ctx))
+	require.Equal(t, "a", store.active.Hash)
+
+	// Neither host is fenced as only a newer version fences a host.
+	clock.Step(defaultFenceCheckInterval)
+	require.Nil(t, rollout.check(ctx))
+	require.Nil(t, old.check(ctx))
+}
+
+func TestFence_nil(t *testing.T) {
+	var f *fence
+	require.Nil(t, f.check(context.Background()))
+
+	called := false
+	fn := f.guard(func(ctx context.Context, e *Event) error {
+		called = true
+		return nil
+	})
+	require.Nil(t, fn(context.Background(), &Event{}))
+	require.True(t, called)
+}
+
+func TestFence_guard(t *testing.T) {
+	clock := clock_testing.NewFakeClock(time.Now())
+	store := &definitionStore{active: &Definition{WorkflowName: "example", Version: 2}}
+	f := newFence(store, Definition{WorkflowName: "example", Version: 1}, clock)
+
+	called := false
+	fn := f.guard(func(ctx context.Context, e *Event) error {
+		called = true
+		return nil
+	})
+	require.True(t, errors.Is(fn(context.Background(), &Event{}), ErrFenced))
+	require.False(t, called)
+}
+
+func TestWithDeploymentFencing_requiresDefinitionStore(t *testing.T) {
+	require.PanicsWithValue(t, "cannot configure deployment fencing without a RecordStore that implements DefinitionStore", func() {
+		b := NewBuilder[string, testStatus]("example")
+		b.AddStep(statusStart, func(ctx context.Context, r *Run[string, testStatus]) (testStatus, error) {
+			return statusEnd, nil
+		}, statusEnd)
+		_ = b.Build(nil, nil, nil, WithDeploymentFencing())
+	})
+}
@@ -0,0 +1,130 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Branch is a named branch of a fork that is processed concurrently with the fork's other branches.
+type Branch[Type any, Status StatusType] struct {
+	Name string
+	Fn   BranchFunc[Type, Status]
+}
+
+// BranchFunc processes a branch of a fork. The run provided to each branch has its own copy of the Object and the
+// changes made to the copy are the branch's result which is provided to the fork's MergeFunc.
+type BranchFunc[Type any, Status StatusType] func(ctx context.Context, r *Run[Type, Status]) error
+
+// MergeFunc merges the results of a fork's branches, keyed by the name of the branch, into the run's Object once all
+// the branches have completed.
+type MergeFunc[Type any, Status StatusType] func(ctx context.Context, r *Run[Type, Status], results map[string]*Type) error
+
+// AddFork adds a step that fans the run out into the branches which are processed concurrently. Once all the branches
+// have completed their results are merged into the run's Object using merge and the run moves onto the join status.
+// If any branch returns an error then the step fails with the errors of all the failed branches and all the branches
+// are processed again when the step is retried, and so branches should be idempotent. The fork can be configured in
+// the same way as a step added using AddStep.
+func (b *Builder[Type, Status]) AddFork(
+	from Status,
+	branches []Branch[Type, Status],
+	merge MergeFunc[Type, Status],
+	join Status,
+) *stepUpdater[Type, Status] {
+	validateFork(from, branches, join)
+
+	return b.AddStep(from, func(ctx context.Context, r *Run[Type, Status]) (Status, error) {
+		results, err := forkBranches(ctx, b.workflow.codec, r, branches)
+		if err != nil {
+			return 0, err
+		}
+
+		err = merge(ctx, r, results)
+		if err != nil {
+			return 0, fmt.Errorf("merge fork: %w", err)
+		}
+
+		return join, nil
+	}, join)
+}
+
+func validateFork[Type any, Status StatusType](from Status, branches []Branch[Type, Status], join Status) {
+	if len(branches) == 0 {
+		panic("'AddFork(" + from.String() + ",' requires at least one branch")
+	}
+
+	if join == from {
+		panic("'AddFork(" + from.String() + ",' cannot join on its own status")
+	}
+
+	names := make(map[string]bool)
+	for _, branch := range branches {
+		if branch.Name == "" {
+			panic("'AddFork(" + from.String() + ",' branches need to be named")
+		}
+
+		if names[branch.Name] {
+			panic("'AddFork(" + from.String() + ",' branch names need to be unique")
+		}
+
+		names[branch.Name] = true
+	}
+}
+
+// forkBranches processes the branches concurrently, each with its own copy of the run's Object, and returns the
+// branches' results keyed by the name of the branch.
+func forkBranches[Type any, Status StatusType](
+	ctx context.Context,
+	codec Codec,
+	r *Run[Type, Status],
+	branches []Branch[Type, Status],
+) (map[string]*Type, error) {
+	object, err := codec.Marshal(r.Object)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results = make(map[string]*Type, len(branches))
+		errs    []error
+	)
+
+	for _, branch := range branches {
+		var t Type
+		err := codec.Unmarshal(object, &t)
+		if err != nil {
+			return nil, err
+		}
+
+		branchRun := *r
+		branchRun.Object = &t
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			err := branch.Fn(ctx, &branchRun)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				errs = append(errs, fmt.Errorf("branch %s: %w", branch.Name, err))
+				return
+			}
+
+			results[branch.Name] = branchRun.Object
+		}()
+	}
+
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+
+	return results, nil
+}
@@ -0,0 +1,120 @@
+package workflow_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestAddFork(t *testing.T) {
+	var attempts int
+	b := workflow.NewBuilder[MyType, status]("fork")
+	b.AddFork(StatusStart, []workflow.Branch[MyType, status]{
+		{
+			Name: "email",
+			Fn: func(ctx context.Context, r *workflow.Run[MyType, status]) error {
+				r.Object.Email = "andrew@workflow.com"
+				return nil
+			},
+		},
+		{
+			Name: "cellphone",
+			Fn: func(ctx context.Context, r *workflow.Run[MyType, status]) error {
+				attempts++
+				if attempts == 1 {
+					return errors.New("cellphone lookup failed")
+				}
+
+				r.Object.Cellphone = "+44 7467623292"
+				return nil
+			},
+		},
+	}, func(ctx context.Context, r *workflow.Run[MyType, status], results map[string]*MyType) error {
+		r.Object.Email = results["email"].Email
+		r.Object.Cellphone = results["cellphone"].Cellphone
+		return nil
+	}, StatusEnd).WithOptions(workflow.ErrBackOff(time.Millisecond))
+
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "foreignID", StatusStart, workflow.WithInitialValue[MyType, status](&MyType{
+		Name: "Andrew",
+	}))
+	require.Nil(t, err)
+
+	run, err := wf.Await(ctx, "foreignID", runID, StatusEnd, workflow.WithAwaitPollingFrequency(10*time.Millisecond))
+	require.Nil(t, err)
+
+	require.Equal(t, MyType{
+		Name:      "Andrew",
+		Email:     "andrew@workflow.com",
+		Cellphone: "+44 7467623292",
+	}, *run.Object)
+	require.Equal(t, 2, attempts)
+}
+
+func TestAddForkValidation(t *testing.T) {
+	branch := func(ctx context.Context, r *workflow.Run[MyType, status]) error {
+		return nil
+	}
+
+	merge := func(ctx context.Context, r *workflow.Run[MyType, status], results map[string]*MyType) error {
+		return nil
+	}
+
+	testCases := []struct {
+		name     string
+		branches []workflow.Branch[MyType, status]
+		join     status
+		expected string
+	}{
+		{
+			name:     "No branches",
+			join:     StatusEnd,
+			expected: "'AddFork(Start,' requires at least one branch",
+		},
+		{
+			name:     "Join on own status",
+			branches: []workflow.Branch[MyType, status]{{Name: "a", Fn: branch}},
+			join:     StatusStart,
+			expected: "'AddFork(Start,' cannot join on its own status",
+		},
+		{
+			name:     "Unnamed branch",
+			branches: []workflow.Branch[MyType, status]{{Fn: branch}},
+			join:     StatusEnd,
+			expected: "'AddFork(Start,' branches need to be named",
+		},
+		{
+			name:     "Duplicate branch names",
+			branches: []workflow.Branch[MyType, status]{{Name: "a", Fn: branch}, {Name: "a", Fn: branch}},
+			join:     StatusEnd,
+			expected: "'AddFork(Start,' branch names need to be unique",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := workflow.NewBuilder[MyType, status]("fork")
+			require.PanicsWithValue(t, tc.expected, func() {
+				b.AddFork(StatusStart, tc.branches, merge, tc.join)
+			})
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Funnel is the conversion of the workflow's runs through an ordered list of statuses, such as the stages of a sign
+// up flow, as returned by Funnel.
+type Funnel[Status StatusType] struct {
+	From   time.Time
+	To     time.Time
+	Stages []FunnelStage[Status]
+}
+
+type FunnelStage[Status StatusType] struct {
+	Status Status
+	// Entered is the number of runs that reached the status after reaching each of the previous stages in order.
+	Entered int64
+	// DropOff is the number of runs that entered the stage but have not reached the next stage. It is zero for the
+	// last stage.
+	DropOff int64
+	// ConversionRate is the fraction of the runs that entered the previous stage that went on to enter the stage. It
+	// is one for the first stage.
+	ConversionRate float64
+	// MedianDuration is the median time that the runs took to reach the stage from the previous stage. It is zero for
+	// the first stage.
+	MedianDuration time.Duration
+}
+
+// Funnel computes the conversion of the workflow's runs through the statuses, in order, from the runs' histories. The
+// funnel includes the runs that entered the first status at, or after, from and before to, and follows them through
+// the remaining statuses regardless of when they reached them. A run enters a later stage the first time it reaches
+// the stage's status after entering the previous stage. All the runs created before to are scanned and only the
+// latest 200 transitions of a run are kept in its history, see RunHistory, and so the funnel should be computed
+// offline for workflows with many runs.
+func (w *Workflow[Type, Status]) Funnel(
+	ctx context.Context,
+	from time.Time,
+	to time.Time,
+	statuses ...Status,
+) (*Funnel[Status], error) {
+	if len(statuses) < 2 {
+		return nil, errors.New("funnel: at least two statuses are required")
+	}
+
+	if !to.After(from) {
+		return nil, errors.New("funnel: to needs to be after from")
+	}
+
+	for _, status := range statuses {
+		if !w.statusGraph.IsValid(int(status)) {
+			return nil, fmt.Errorf("funnel: status not in workflow, meta: %v", map[string]string{
+				"workflow_name": w.Name(),
+				"status":        status.String(),
+			})
+		}
+	}
+
+	durations := make([][]time.Duration, len(statuses))
+	entered := make([]int64, len(statuses))
+
+	var offset int64
+	for {
+		records, err := w.recordStore.List(ctx, w.Name(), offset, listRunsPageSize, OrderTypeAscending)
+		if err != nil {
+			return nil, fmt.Errorf("funnel: %w, meta: %v", err, map[string]string{
+				"offset": fmt.Sprint(offset),
+			})
+		}
+
+		for _, record := range records {
+			// Runs created after the time range cannot have entered the first status within it.
+			if !record.CreatedAt.Before(to) {
+				continue
+			}
+
+			entries := funnelEntries(record.Meta.History, statuses)
+			if len(entries) == 0 || entries[0].Before(from) || !entries[0].Before(to) {
+				continue
+			}
+
+			for i, at := range entries {
+				entered[i]++
+				if i > 0 {
+					durations[i] = append(durations[i], at.Sub(entries[i-1]))
+				}
+			}
+		}
+
+		if len(records) < listRunsPageSize {
+			break
+		}
+
+		offset += int64(len(records))
+	}
+
+	funnel := Funnel[Status]{
+		From: from,
+		To:   to,
+	}
+
+	for i, status := range statuses {
+		stage := FunnelStage[Status]{
+			Status:         status,
+			Entered:        entered[i],
+			ConversionRate: 1,
+			MedianDuration: median(durations[i]),
+		}
+
+		if i > 0 {
+			stage.ConversionRate = 0
+			if entered[i-1] > 0 {
+				stage.ConversionRate = float64(entered[i]) / float64(entered[i-1])
+			}
+		}
+
+		if i < len(statuses)-1 {
+			stage.DropOff = entered[i] - entered[i+1]
+		}
+
+		funnel.Stages = append(funnel.Stages, stage)
+	}
+
+	return &funnel, nil
+}
+
+// funnelEntries returns the times at which the run entered each of the statuses in order, stopping at the first
+// status that the run did not reach after the previous one.
+func funnelEntries[Status StatusType](history []Transition, statuses []Status) []time.Time {
+	var entries []time.Time
+	for _, t := range history {
+		if len(entries) == len(statuses) {
+			break
+		}
+
+		if t.ToStatus != int(statuses[len(entries)]) {
+			continue
+		}
+
+		// Transitions that only change the run state, such as pausing the run, keep the status of the run.
+		if t.FromStatus == t.ToStatus && len(entries) > 0 {
+			continue
+		}
+
+		entries = append(entries, t.At)
+	}
+
+	return entries
+}
+
+func median(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+
+	return sorted[mid]
+}
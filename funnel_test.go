@@ -0,0 +1,108 @@
+package workflow_test
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	clock_testing "k8s.io/utils/clock/testing"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestFunnel(t *testing.T) {
+	now := time.Date(2024, time.April, 19, 9, 0, 0, 0, time.UTC)
+	clock := clock_testing.NewFakeClock(now)
+
+	next := func(to status) workflow.CallbackFunc[string, status] {
+		return func(ctx context.Context, r *workflow.Run[string, status], reader io.Reader) (status, error) {
+			return to, nil
+		}
+	}
+
+	b := workflow.NewBuilder[string, status]("funnel")
+	b.AddCallback(StatusStart, next(StatusMiddle), StatusMiddle)
+	b.AddCallback(StatusMiddle, next(StatusEnd), StatusEnd)
+
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(memrecordstore.WithClock(clock)),
+		memrolescheduler.New(),
+		workflow.WithClock(clock),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	// Each run reaches the number of stages, waiting between each of its statuses.
+	runs := []struct {
+		stages int
+		wait   time.Duration
+	}{
+		{stages: 3, wait: time.Minute},
+		{stages: 3, wait: 3 * time.Minute},
+		{stages: 2, wait: 2 * time.Minute},
+		{stages: 1},
+	}
+
+	for i, run := range runs {
+		foreignID := "foreignID-" + strconv.Itoa(i)
+		_, err := wf.Trigger(ctx, foreignID, StatusStart)
+		require.Nil(t, err)
+
+		for _, s := range []status{StatusStart, StatusMiddle}[:run.stages-1] {
+			clock.Step(run.wait)
+			err := wf.Callback(ctx, foreignID, s, nil)
+			require.Nil(t, err)
+		}
+
+		clock.Step(time.Hour)
+	}
+
+	// Runs triggered after the time range are not included.
+	_, err := wf.Trigger(ctx, "late", StatusStart)
+	require.Nil(t, err)
+
+	funnel, err := wf.Funnel(ctx, now, now.Add(4*time.Hour), StatusStart, StatusMiddle, StatusEnd)
+	require.Nil(t, err)
+	require.Equal(t, []workflow.FunnelStage[status]{
+		{
+			Status:         StatusStart,
+			Entered:        4,
+			DropOff:        1,
+			ConversionRate: 1,
+		},
+		{
+			Status:         StatusMiddle,
+			Entered:        3,
+			DropOff:        1,
+			ConversionRate: 0.75,
+			MedianDuration: 2 * time.Minute,
+		},
+		{
+			Status:         StatusEnd,
+			Entered:        2,
+			ConversionRate: float64(2) / float64(3),
+			MedianDuration: 2 * time.Minute,
+		},
+	}, funnel.Stages)
+
+	// The funnel can skip over statuses.
+	funnel, err = wf.Funnel(ctx, now, now.Add(4*time.Hour), StatusStart, StatusEnd)
+	require.Nil(t, err)
+	require.Equal(t, int64(2), funnel.Stages[1].Entered)
+	require.Equal(t, 4*time.Minute, funnel.Stages[1].MedianDuration)
+
+	_, err = wf.Funnel(ctx, now, now.Add(time.Hour), StatusStart)
+	require.ErrorContains(t, err, "at least two statuses are required")
+
+	_, err = wf.Funnel(ctx, now, now.Add(time.Hour), StatusStart, status(99))
+	require.ErrorContains(t, err, "status not in workflow")
+}
@@ -0,0 +1,217 @@
+package workflow
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/luno/workflow/internal/graph"
+)
+
+// TransitionKind is the kind of builder method that added a transition to the workflow's graph.
+type TransitionKind string
+
+const (
+	TransitionKindStep      TransitionKind = "step"
+	TransitionKindBatchStep TransitionKind = "batch step"
+	TransitionKindCallback  TransitionKind = "callback"
+	TransitionKindTimeout   TransitionKind = "timeout"
+	TransitionKindTimer     TransitionKind = "timer"
+)
+
+// Graph describes the statuses of a built workflow and the transitions between them.
+type Graph struct {
+	WorkflowName string
+	Statuses     []GraphStatus
+	Transitions  []GraphTransition
+}
+
+type GraphStatus struct {
+	Status      int
+	Description string
+	// Starting is true for statuses that are not the destination of any transition.
+	Starting bool
+	// Terminal is true for statuses that have no transitions.
+	Terminal bool
+}
+
+type GraphTransition struct {
+	From int
+	To   int
+	// Kinds are the kinds of builder methods that added the transition, such as a step and a timeout that both move
+	// the run to the same status.
+	Kinds []TransitionKind
+}
+
+func (b *Builder[Type, Status]) addTransitions(kind TransitionKind, from Status, allowedDestinations ...Status) {
+	for _, to := range allowedDestinations {
+		b.workflow.statusGraph.AddTransition(int(from), int(to))
+
+		t := graph.Transition{From: int(from), To: int(to)}
+		if !slices.Contains(b.workflow.transitionKinds[t], kind) {
+			b.workflow.transitionKinds[t] = append(b.workflow.transitionKinds[t], kind)
+		}
+	}
+}
+
+// Graph returns the statuses and transitions of the workflow, including those of its callbacks, timeouts, and timers,
+// so that diagrams of the workflow can be generated from code. See Graph.MermaidDiagram and Graph.DOT.
+func (w *Workflow[Type, Status]) Graph() Graph {
+	info := w.statusGraph.Info()
+
+	g := Graph{
+		WorkflowName: w.Name(),
+	}
+
+	for _, node := range w.statusGraph.Nodes() {
+		g.Statuses = append(g.Statuses, GraphStatus{
+			Status:      node,
+			Description: description[Status](node),
+			Starting:    slices.Contains(info.StartingNodes, node),
+			Terminal:    slices.Contains(info.TerminalNodes, node),
+		})
+	}
+
+	seen := make(map[graph.Transition]bool)
+	for _, t := range info.Transitions {
+		// The same transition is added to the graph by each of the builder methods that allow it.
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+
+		g.Transitions = append(g.Transitions, GraphTransition{
+			From:  t.From,
+			To:    t.To,
+			Kinds: slices.Clone(w.transitionKinds[t]),
+		})
+	}
+
+	return g
+}
+
+// MermaidDiagram returns the graph as a Mermaid state diagram, without a surrounding code block, with each transition
+// labelled with its kinds.
+func (g Graph) MermaidDiagram() string {
+	var sb strings.Builder
+	sb.WriteString("---\n")
+	sb.WriteString("title: Workflow diagram of " + g.WorkflowName + "\n")
+	sb.WriteString("---\n")
+	sb.WriteString("stateDiagram-v2\n")
+	sb.WriteString("\tdirection " + string(LeftToRightDirection) + "\n")
+
+	for _, s := range g.Statuses {
+		sb.WriteString("\t" + strconv.Itoa(s.Status) + ": " + s.Description + "\n")
+	}
+
+	for _, s := range g.Statuses {
+		if s.Starting {
+			sb.WriteString("\t[*] --> " + strconv.Itoa(s.Status) + "\n")
+		}
+	}
+
+	for _, t := range g.Transitions {
+		sb.WriteString("\t" + strconv.Itoa(t.From) + " --> " + strconv.Itoa(t.To))
+		if len(t.Kinds) > 0 {
+			sb.WriteString(": " + t.label())
+		}
+		sb.WriteString("\n")
+	}
+
+	for _, s := range g.Statuses {
+		if s.Terminal {
+			sb.WriteString("\t" + strconv.Itoa(s.Status) + " --> [*]\n")
+		}
+	}
+
+	return sb.String()
+}
+
+// DOT returns the graph in the Graphviz DOT language with each transition labelled with its kinds. Starting statuses
+// are drawn in bold and terminal statuses with a double border.
+func (g Graph) DOT() string {
+	var sb strings.Builder
+	sb.WriteString("digraph " + strconv.Quote(g.WorkflowName) + " {\n")
+	sb.WriteString("\trankdir=LR;\n")
+	sb.WriteString("\tnode [shape=box, style=rounded];\n")
+
+	for _, s := range g.Statuses {
+		attrs := []string{"label=" + strconv.Quote(s.Description)}
+		if s.Starting {
+			attrs = append(attrs, "style=\"rounded,bold\"")
+		}
+		if s.Terminal {
+			attrs = append(attrs, "peripheries=2")
+		}
+
+		sb.WriteString("\t" + strconv.Quote(strconv.Itoa(s.Status)) + " [" + strings.Join(attrs, ", ") + "];\n")
+	}
+
+	for _, t := range g.Transitions {
+		sb.WriteString("\t" + strconv.Quote(strconv.Itoa(t.From)) + " -> " + strconv.Quote(strconv.Itoa(t.To)))
+		if len(t.Kinds) > 0 {
+			sb.WriteString(" [label=" + strconv.Quote(t.label()) + "]")
+		}
+		sb.WriteString(";\n")
+	}
+
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func (t GraphTransition) label() string {
+	kinds := make([]string, 0, len(t.Kinds))
+	for _, kind := range t.Kinds {
+		kinds = append(kinds, string(kind))
+	}
+
+	return strings.Join(kinds, ", ")
+}
+
+// validateGraph returns the problems with the workflow's graph, such as statuses that cannot be reached from the
+// starting statuses, cycles that never reach a terminal status, and terminal statuses that have steps, callbacks,
+// timeouts, or timers which are never called as runs complete when they reach a terminal status. Statuses that are
+// not terminal always have a step, callback, timeout, or timer as they are the only way that transitions are added.
+func (w *Workflow[Type, Status]) validateGraph() []string {
+	var problems []string
+	for _, node := range w.statusGraph.Unreachable() {
+		problems = append(problems, "status "+Status(node).String()+" is unreachable from the starting statuses")
+	}
+
+	for _, cycle := range w.statusGraph.ClosedCycles() {
+		statuses := make([]string, 0, len(cycle))
+		for _, node := range cycle {
+			statuses = append(statuses, Status(node).String())
+		}
+
+		problems = append(problems, "statuses "+strings.Join(statuses, ", ")+" form a cycle that never reaches a terminal status")
+	}
+
+	var terminalSources []string
+	checkSource := func(status Status, kind TransitionKind) {
+		if len(w.statusGraph.Transitions(int(status))) > 0 {
+			return
+		}
+
+		terminalSources = append(terminalSources, "status "+status.String()+" is terminal but has a "+string(kind))
+	}
+
+	for status := range w.consumers {
+		checkSource(status, TransitionKindStep)
+	}
+	for status := range w.callback {
+		checkSource(status, TransitionKindCallback)
+	}
+	for status := range w.timeouts {
+		checkSource(status, TransitionKindTimeout)
+	}
+	for key := range w.timers {
+		checkSource(key.status, TransitionKindTimer)
+	}
+
+	// The handlers are held in maps and so are sorted for the problems to be deterministic.
+	slices.Sort(terminalSources)
+	terminalSources = slices.Compact(terminalSources)
+
+	return append(problems, terminalSources...)
+}
@@ -0,0 +1,152 @@
+package workflow_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memtimeoutstore"
+)
+
+func buildGraphWorkflow() *workflow.Workflow[string, status] {
+	b := workflow.NewBuilder[string, status]("example")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return StatusMiddle, nil
+	}, StatusMiddle, StatusEnd)
+	b.AddCallback(StatusMiddle, func(ctx context.Context, r *workflow.Run[string, status], reader io.Reader) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+	b.AddTimeout(
+		StatusMiddle,
+		workflow.DurationTimerFunc[string, status](time.Hour),
+		func(ctx context.Context, r *workflow.Run[string, status], now time.Time) (status, error) {
+			return StatusEnd, nil
+		},
+		StatusEnd,
+	)
+
+	return b.Build(nil, nil, nil, workflow.WithTimeoutStore(memtimeoutstore.New()))
+}
+
+func TestGraph(t *testing.T) {
+	wf := buildGraphWorkflow()
+
+	require.Equal(t, workflow.Graph{
+		WorkflowName: "example",
+		Statuses: []workflow.GraphStatus{
+			{Status: int(StatusStart), Description: "Start", Starting: true},
+			{Status: int(StatusMiddle), Description: "Middle"},
+			{Status: int(StatusEnd), Description: "End", Terminal: true},
+		},
+		Transitions: []workflow.GraphTransition{
+			{From: int(StatusStart), To: int(StatusMiddle), Kinds: []workflow.TransitionKind{workflow.TransitionKindStep}},
+			{From: int(StatusStart), To: int(StatusEnd), Kinds: []workflow.TransitionKind{workflow.TransitionKindStep}},
+			{
+				From:  int(StatusMiddle),
+				To:    int(StatusEnd),
+				Kinds: []workflow.TransitionKind{workflow.TransitionKindCallback, workflow.TransitionKindTimeout},
+			},
+		},
+	}, wf.Graph())
+}
+
+func TestGraphMermaidDiagram(t *testing.T) {
+	wf := buildGraphWorkflow()
+
+	expected := "---\n" +
+		"title: Workflow diagram of example\n" +
+		"---\n" +
+		"stateDiagram-v2\n" +
+		"\tdirection LR\n" +
+		"\t9: Start\n" +
+		"\t10: Middle\n" +
+		"\t11: End\n" +
+		"\t[*] --> 9\n" +
+		"\t9 --> 10: step\n" +
+		"\t9 --> 11: step\n" +
+		"\t10 --> 11: callback, timeout\n" +
+		"\t11 --> [*]\n"
+	require.Equal(t, expected, wf.Graph().MermaidDiagram())
+}
+
+func TestGraphDOT(t *testing.T) {
+	wf := buildGraphWorkflow()
+
+	expected := "digraph \"example\" {\n" +
+		"\trankdir=LR;\n" +
+		"\tnode [shape=box, style=rounded];\n" +
+		"\t\"9\" [label=\"Start\", style=\"rounded,bold\"];\n" +
+		"\t\"10\" [label=\"Middle\"];\n" +
+		"\t\"11\" [label=\"End\", peripheries=2];\n" +
+		"\t\"9\" -> \"10\" [label=\"step\"];\n" +
+		"\t\"9\" -> \"11\" [label=\"step\"];\n" +
+		"\t\"10\" -> \"11\" [label=\"callback, timeout\"];\n" +
+		"}\n"
+	require.Equal(t, expected, wf.Graph().DOT())
+}
+
+func TestGraphValidation(t *testing.T) {
+	step := func(next status) workflow.ConsumerFunc[string, status] {
+		return func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+			return next, nil
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		build    func(b *workflow.Builder[string, status])
+		expected string
+	}{
+		{
+			name: "Unreachable statuses",
+			build: func(b *workflow.Builder[string, status]) {
+				b.AddStep(StatusStart, step(StatusEnd), StatusEnd)
+				b.AddStep(StatusMiddle, step(StatusEnd), StatusEnd, StatusMiddle)
+			},
+			expected: "invalid workflow graph: status Middle is unreachable from the starting statuses. " +
+				"Use WithoutGraphValidation to disable the validation of the graph",
+		},
+		{
+			name: "Cycle without exit",
+			build: func(b *workflow.Builder[string, status]) {
+				b.AddStep(StatusStart, step(StatusMiddle), StatusMiddle)
+				b.AddStep(StatusMiddle, step(StatusEnd), StatusEnd)
+				b.AddStep(StatusEnd, step(StatusMiddle), StatusMiddle)
+			},
+			expected: "invalid workflow graph: statuses Middle, End form a cycle that never reaches a terminal status. " +
+				"Use WithoutGraphValidation to disable the validation of the graph",
+		},
+		{
+			name: "Terminal status with a step and a callback",
+			build: func(b *workflow.Builder[string, status]) {
+				b.AddStep(StatusStart, step(StatusEnd), StatusEnd)
+				b.AddStep(StatusEnd, step(StatusEnd))
+				b.AddCallback(StatusEnd, func(ctx context.Context, r *workflow.Run[string, status], reader io.Reader) (status, error) {
+					return StatusEnd, nil
+				})
+			},
+			expected: "invalid workflow graph: status End is terminal but has a callback; status End is terminal but has a step. " +
+				"Use WithoutGraphValidation to disable the validation of the graph",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.PanicsWithValue(t, tc.expected, func() {
+				b := workflow.NewBuilder[string, status]("graph validation")
+				tc.build(b)
+				b.Build(nil, nil, nil)
+			})
+
+			require.NotPanics(t, func() {
+				b := workflow.NewBuilder[string, status]("graph validation")
+				tc.build(b)
+				b.Build(nil, nil, nil, workflow.WithoutGraphValidation())
+			})
+		})
+	}
+}
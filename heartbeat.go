@@ -0,0 +1,106 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrHeartbeatTimeout is the cause of the cancellation of the context provided to a step, configured using
+// WithHeartbeatTimeout, that has not called Run.Heartbeat within the heartbeat timeout.
+var ErrHeartbeatTimeout = errors.New("heartbeat timeout")
+
+// WithHeartbeatTimeout detects a stuck step, such as a step that runs for minutes and whose worker has hung, by
+// requiring the step to call Run.Heartbeat at least once every timeout. When the step has not heartbeated within the
+// timeout the context provided to the step is cancelled, an AlertTypeHeartbeatTimeout alert is raised, and the step is
+// abandoned so that the run is no longer held by it and is retried like any other error of the step. The result of an
+// abandoned step is ignored. Heartbeat timeouts are not supported by batch steps.
+func (s *stepUpdater[Type, Status]) WithHeartbeatTimeout(timeout time.Duration) *stepUpdater[Type, Status] {
+	consumer := s.workflow.consumers[s.from]
+	consumer.heartbeatTimeout = timeout
+	s.workflow.consumers[s.from] = consumer
+	return s
+}
+
+// Heartbeat reports that the step is still making progress and should be called by steps configured using
+// WithHeartbeatTimeout at least once every heartbeat timeout. The error is ErrHeartbeatTimeout once the step has been
+// abandoned, and the context's error when the context has been cancelled, so that the step can stop early. Heartbeat
+// does nothing for steps without a heartbeat timeout.
+func (r *Run[Type, Status]) Heartbeat(ctx context.Context) error {
+	if ctx.Err() != nil {
+		return context.Cause(ctx)
+	}
+
+	if r.heartbeat != nil {
+		r.heartbeat()
+	}
+
+	return nil
+}
+
+// heartbeatConsumer calls the consumer in its own goroutine and abandons it when it has not heartbeated within the
+// timeout.
+func heartbeatConsumer[Type any, Status StatusType](
+	w *Workflow[Type, Status],
+	currentStatus Status,
+	timeout time.Duration,
+	consumer ConsumerFunc[Type, Status],
+) ConsumerFunc[Type, Status] {
+	if timeout <= 0 {
+		return consumer
+	}
+
+	type result struct {
+		next Status
+		err  error
+	}
+
+	return func(ctx context.Context, r *Run[Type, Status]) (Status, error) {
+		stepCtx, cancel := context.WithCancelCause(ctx)
+		defer cancel(nil)
+
+		beats := make(chan struct{}, 1)
+		r.heartbeat = func() {
+			select {
+			case beats <- struct{}{}:
+			default:
+			}
+		}
+
+		done := make(chan result, 1)
+		go func() {
+			next, err := consumer(stepCtx, r)
+			done <- result{next: next, err: err}
+		}()
+
+		t := w.clock.NewTimer(timeout)
+		defer t.Stop()
+
+		for {
+			select {
+			case res := <-done:
+				return res.next, res.err
+			case <-beats:
+				t.Reset(timeout)
+			case <-ctx.Done():
+				return 0, ctx.Err()
+			case <-t.C():
+				cancel(ErrHeartbeatTimeout)
+
+				w.alert(ctx, Alert{
+					Type:      AlertTypeHeartbeatTimeout,
+					ForeignID: r.ForeignID,
+					RunID:     r.RunID,
+					Status:    currentStatus.String(),
+					Message:   fmt.Sprintf("step of %s has not heartbeated within %s", currentStatus, timeout),
+				})
+
+				return 0, fmt.Errorf("%w, meta: %v", ErrHeartbeatTimeout, map[string]string{
+					"run_id":  r.RunID,
+					"timeout": timeout.String(),
+				})
+			}
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package workflow_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestHeartbeat(t *testing.T) {
+	var (
+		attempts  atomic.Int64
+		abandoned = make(chan error, 1)
+		alerts    = make(chan workflow.Alert, 1)
+	)
+
+	b := workflow.NewBuilder[string, status]("heartbeat")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		// The step runs for longer than the heartbeat timeout whilst heartbeating.
+		for range 5 {
+			time.Sleep(20 * time.Millisecond)
+
+			err := r.Heartbeat(ctx)
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		return StatusMiddle, nil
+	}, StatusMiddle).WithHeartbeatTimeout(50 * time.Millisecond)
+
+	b.AddStep(StatusMiddle, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		// The first attempt is stuck until it is abandoned.
+		if attempts.Add(1) == 1 {
+			<-ctx.Done()
+			abandoned <- r.Heartbeat(ctx)
+			return 0, ctx.Err()
+		}
+
+		return StatusEnd, nil
+	}, StatusEnd).WithHeartbeatTimeout(50 * time.Millisecond).WithOptions(
+		workflow.ErrBackOff(time.Millisecond),
+	)
+
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+		workflow.WithAlertHook(func(ctx context.Context, alert workflow.Alert) {
+			alerts <- alert
+		}),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "foreignID", StatusStart)
+	require.Nil(t, err)
+
+	_, err = wf.Await(ctx, "foreignID", runID, StatusEnd)
+	require.Nil(t, err)
+	require.Equal(t, int64(2), attempts.Load())
+
+	require.True(t, errors.Is(<-abandoned, workflow.ErrHeartbeatTimeout))
+
+	alert := <-alerts
+	require.Equal(t, workflow.AlertTypeHeartbeatTimeout, alert.Type)
+	require.Equal(t, runID, alert.RunID)
+	require.Equal(t, StatusMiddle.String(), alert.Status)
+}
+
+func TestHeartbeatWithoutTimeout(t *testing.T) {
+	r := workflow.NewTestingRun[string, status](t, workflow.Record{}, "")
+	require.Nil(t, r.Heartbeat(context.Background()))
+}
@@ -0,0 +1,151 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+// maxHistoryLength is the number of transitions kept in a run's history. The oldest transitions are dropped once a
+// run's history is full.
+const maxHistoryLength = 200
+
+// Transition is a change to the status, or run state, of a run that is recorded in the run's history.
+type Transition struct {
+	// FromStatus is zero for the first transition of a run.
+	FromStatus   int       `json:"from_status,omitempty"`
+	ToStatus     int       `json:"to_status,omitempty"`
+	FromRunState RunState  `json:"from_run_state,omitempty"`
+	ToRunState   RunState  `json:"to_run_state,omitempty"`
+	At           time.Time `json:"at"`
+	// Process is the name of the workflow process, such as a step consumer, that made the transition. It is empty
+	// when the transition was made by calling the workflow, such as by Trigger.
+	Process string `json:"process,omitempty"`
+	// Error is the error that caused the transition, such as the error of a step that paused the run after
+	// exceeding its PauseAfterErrCount.
+	Error string `json:"error,omitempty"`
+	// Actor is who made the transition, as set using WithActor, such as the operator that cancelled the run.
+	Actor string `json:"actor,omitempty"`
+	// Reason is why the transition was made, such as the reason provided to CancelRun.
+	Reason string `json:"reason,omitempty"`
+	// Reasons are the decisions recorded by the step, using Run.Reason, that led to the transition.
+	Reasons []DecisionReason `json:"reasons,omitempty"`
+	// Forced is true when the transition was made by ForceTransition rather than by the step of the status.
+	Forced bool `json:"forced,omitempty"`
+	// Replay is true when the transition was made by ReplayRun or ReplayAll.
+	Replay bool `json:"replay,omitempty"`
+}
+
+// DecisionReason is a structured reason, recorded by a step using Run.Reason, for the decision that the step made,
+// such as the branch that it took, so that audits and support teams can see why a run moved to its next status.
+type DecisionReason struct {
+	// Code identifies the reason, such as "kyc_rejected".
+	Code    string            `json:"code"`
+	Details map[string]string `json:"details,omitempty"`
+}
+
+// RunHistory returns the transitions of the run in the order that they were made, so that support teams can answer
+// what happened to a run. Only the latest 200 transitions of a run are kept. ErrRecordNotFound is returned when the
+// run does not exist or does not belong to the workflow.
+func (w *Workflow[Type, Status]) RunHistory(ctx context.Context, runID string) ([]Transition, error) {
+	record, err := w.recordStore.Lookup(ctx, runID)
+	record, _, err = w.lookupArchived(ctx, runID, record, err)
+	if err != nil {
+		return nil, err
+	}
+
+	if record.WorkflowName != w.Name() {
+		return nil, fmt.Errorf("run history: %w, meta: %v", ErrRecordNotFound, map[string]string{
+			"run_id": runID,
+		})
+	}
+
+	return record.Meta.History, nil
+}
+
+type transitionContextKey struct{}
+
+// transitionContext holds the details of the process that is updating records so that they can be included in the
+// transitions recorded by updateRecord.
+type transitionContext struct {
+	process string
+	clock   clock.Clock
+	err     error
+	actor   string
+	reason  string
+	reasons []DecisionReason
+}
+
+func transitionFromContext(ctx context.Context) transitionContext {
+	tc, _ := ctx.Value(transitionContextKey{}).(transitionContext)
+	return tc
+}
+
+// withTransitionProcess sets the process name and clock of the transitions made using the context.
+func withTransitionProcess(ctx context.Context, process string, clock clock.Clock) context.Context {
+	tc := transitionFromContext(ctx)
+	tc.process = process
+	tc.clock = clock
+	return context.WithValue(ctx, transitionContextKey{}, tc)
+}
+
+// withTransitionError sets the error that caused the transitions made using the context.
+func withTransitionError(ctx context.Context, err error) context.Context {
+	tc := transitionFromContext(ctx)
+	tc.err = err
+	return context.WithValue(ctx, transitionContextKey{}, tc)
+}
+
+// WithActor returns a context that records the actor, such as the operator or service making the call, in the
+// transitions of the runs that are changed using the context, such as by PauseRun, ResumeRun, and CancelRun.
+func WithActor(ctx context.Context, actor string) context.Context {
+	tc := transitionFromContext(ctx)
+	tc.actor = actor
+	return context.WithValue(ctx, transitionContextKey{}, tc)
+}
+
+// withTransitionReason sets the reason for the transitions made using the context.
+func withTransitionReason(ctx context.Context, reason string) context.Context {
+	tc := transitionFromContext(ctx)
+	tc.reason = reason
+	return context.WithValue(ctx, transitionContextKey{}, tc)
+}
+
+// withDecisionReasons sets the decision reasons, recorded by the step using Run.Reason, of the transitions made using
+// the context.
+func withDecisionReasons(ctx context.Context, reasons []DecisionReason) context.Context {
+	if len(reasons) == 0 {
+		return ctx
+	}
+
+	tc := transitionFromContext(ctx)
+	tc.reasons = reasons
+	return context.WithValue(ctx, transitionContextKey{}, tc)
+}
+
+// appendTransition returns a copy of the history with the transition appended, keeping only the latest
+// maxHistoryLength transitions.
+func appendTransition(ctx context.Context, history []Transition, t Transition) []Transition {
+	tc := transitionFromContext(ctx)
+	t.Process = tc.process
+	t.Actor = tc.actor
+	t.Reason = tc.reason
+	t.Reasons = tc.reasons
+	if tc.err != nil {
+		t.Error = tc.err.Error()
+	}
+
+	if t.At.IsZero() && tc.clock != nil {
+		t.At = tc.clock.Now()
+	}
+
+	history = append(slices.Clone(history), t)
+	if len(history) > maxHistoryLength {
+		history = history[len(history)-maxHistoryLength:]
+	}
+
+	return history
+}
@@ -0,0 +1,131 @@
+package workflow_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestRunHistory(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("history")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return StatusMiddle, nil
+	}, StatusMiddle)
+	b.AddStep(StatusMiddle, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return 0, errors.New("provider unavailable")
+	}, StatusEnd).WithOptions(
+		workflow.PauseAfterErrCount(1),
+	)
+
+	recordStore := memrecordstore.New()
+	wf := b.Build(
+		memstreamer.New(),
+		recordStore,
+		memrolescheduler.New(),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "foreignID", StatusStart)
+	require.Nil(t, err)
+
+	require.Eventually(t, func() bool {
+		r, err := recordStore.Lookup(ctx, runID)
+		require.Nil(t, err)
+		return r.RunState == workflow.RunStatePaused
+	}, 5*time.Second, 10*time.Millisecond)
+
+	history, err := wf.RunHistory(ctx, runID)
+	require.Nil(t, err)
+
+	for i := range history {
+		require.False(t, history[i].At.IsZero())
+		if i > 0 {
+			require.False(t, history[i].At.Before(history[i-1].At))
+		}
+		history[i].At = time.Time{}
+	}
+
+	require.Equal(t, []workflow.Transition{
+		{
+			ToStatus:     int(StatusStart),
+			FromRunState: workflow.RunStateUnknown,
+			ToRunState:   workflow.RunStateInitiated,
+		},
+		{
+			FromStatus:   int(StatusStart),
+			ToStatus:     int(StatusMiddle),
+			FromRunState: workflow.RunStateRunning,
+			ToRunState:   workflow.RunStateRunning,
+			Process:      "start-consumer-1-of-1",
+		},
+		{
+			FromStatus:   int(StatusMiddle),
+			ToStatus:     int(StatusMiddle),
+			FromRunState: workflow.RunStateRunning,
+			ToRunState:   workflow.RunStatePaused,
+			Process:      "middle-consumer-1-of-1",
+			Error:        "provider unavailable",
+		},
+	}, history)
+
+	_, err = wf.RunHistory(ctx, "unknown")
+	require.ErrorIs(t, err, workflow.ErrRecordNotFound)
+}
+
+func TestRunHistory_DecisionReasons(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("history")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		r.Reason("kyc_approved", map[string]string{"provider": "acme"})
+		return StatusMiddle, nil
+	}, StatusMiddle)
+	b.AddStep(StatusMiddle, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		r.Reason("kyc_rejected", map[string]string{"check": "sanctions"})
+		r.Reason("manual_review", nil)
+		return r.Pause(ctx)
+	}, StatusEnd)
+
+	recordStore := memrecordstore.New()
+	wf := b.Build(
+		memstreamer.New(),
+		recordStore,
+		memrolescheduler.New(),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "foreignID", StatusStart)
+	require.Nil(t, err)
+
+	require.Eventually(t, func() bool {
+		r, err := recordStore.Lookup(ctx, runID)
+		require.Nil(t, err)
+		return r.RunState == workflow.RunStatePaused
+	}, 5*time.Second, 10*time.Millisecond)
+
+	history, err := wf.RunHistory(ctx, runID)
+	require.Nil(t, err)
+	require.Len(t, history, 3)
+
+	require.Nil(t, history[0].Reasons)
+	require.Equal(t, []workflow.DecisionReason{
+		{Code: "kyc_approved", Details: map[string]string{"provider": "acme"}},
+	}, history[1].Reasons)
+	require.Equal(t, workflow.RunStatePaused, history[2].ToRunState)
+	require.Equal(t, []workflow.DecisionReason{
+		{Code: "kyc_rejected", Details: map[string]string{"check": "sanctions"}},
+		{Code: "manual_review"},
+	}, history[2].Reasons)
+}
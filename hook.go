@@ -15,14 +15,14 @@ func runStateChangeHookConsumer[Type any, Status StatusType](
 	hook RunStateChangeHookFunc[Type, Status],
 ) {
 	role := makeRole(
-		w.Name(),
+		w.roleName(),
 		runState.String(),
 		"run-state-change-hook",
 		"consumer",
 	)
 
 	processName := makeRole(runState.String(), "run-state-change-hook", "consumer")
-	w.run(role, processName, func(ctx context.Context) error {
+	w.run(role, processName, w.hookShutdownOrder(), func(ctx context.Context) error {
 		topic := RunStateChangeTopic(w.Name())
 		stream, err := w.eventStreamer.NewReceiver(
 			ctx,
@@ -40,16 +40,18 @@ func runStateChangeHookConsumer[Type any, Status StatusType](
 			w.Name(),
 			processName,
 			stream,
-			runHook(
+			w.versionGuard(runHook(
 				w.Name(),
 				processName,
 				w.recordStore.Lookup,
+				w.codec,
 				hook,
-			),
+			)),
 			w.clock,
 			0,
 			w.defaultOpts.lagAlert,
 			filterByRunState(runState),
+			w.versionFilter(),
 		)
 	}, w.defaultOpts.errBackOff)
 }
@@ -58,6 +60,7 @@ func runHook[Type any, Status StatusType](
 	workflowName string,
 	processName string,
 	lookup lookupFunc,
+	codec Codec,
 	hook RunStateChangeHookFunc[Type, Status],
 ) func(ctx context.Context, e *Event) error {
 	return func(ctx context.Context, e *Event) error {
@@ -67,7 +70,7 @@ func runHook[Type any, Status StatusType](
 		}
 
 		var t Type
-		err = Unmarshal(record.Object, &t)
+		err = codec.Unmarshal(record.Object, &t)
 		if err != nil {
 			metrics.ProcessSkippedEvents.WithLabelValues(workflowName, processName, "unable to unmarshal object").Inc()
 			return nil
@@ -19,6 +19,7 @@ func Test_runHooks(t *testing.T) {
 			func(ctx context.Context, runID string) (*Record, error) {
 				return nil, testErr
 			},
+			JSONCodec{},
 			func(ctx context.Context, record *TypedRecord[string, testStatus]) error {
 				return nil
 			},
@@ -35,6 +36,7 @@ func Test_runHooks(t *testing.T) {
 					Object: []byte("INVALID JSON"),
 				}, nil
 			},
+			JSONCodec{},
 			func(ctx context.Context, record *TypedRecord[string, testStatus]) error {
 				return nil
 			},
@@ -63,6 +65,7 @@ func Test_runHooks(t *testing.T) {
 
 				return current, nil
 			},
+			JSONCodec{},
 			func(ctx context.Context, record *TypedRecord[string, testStatus]) error {
 				return testErr
 			},
@@ -1,6 +1,11 @@
 package graph
 
-import "slices"
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strconv"
+)
 
 func New() *Graph {
 	return &Graph{
@@ -122,3 +127,170 @@ func (g *Graph) Info() Info {
 
 	return i
 }
+
+// Hash returns a deterministic hash of all the transitions in the graph. The order in which transitions were added
+// does not affect the hash.
+func (g *Graph) Hash() string {
+	var transitions []string
+	for from, tos := range g.graph {
+		for _, to := range tos {
+			transitions = append(transitions, strconv.Itoa(from)+">"+strconv.Itoa(to))
+		}
+	}
+
+	slices.Sort(transitions)
+
+	h := sha256.New()
+	for _, t := range transitions {
+		h.Write([]byte(t + ";"))
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Depths returns the shortest number of transitions from any of the starting nodes to each node. Nodes that cannot be
+// reached from a starting node, such as those in a cycle without a starting node, have a depth of 0.
+func (g *Graph) Depths() map[int]int {
+	depths := make(map[int]int)
+	var queue []int
+	for _, node := range g.nodeOrder {
+		if g.starting[node] {
+			depths[node] = 0
+			queue = append(queue, node)
+		}
+	}
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		for _, to := range g.graph[node] {
+			if _, ok := depths[to]; ok {
+				continue
+			}
+
+			depths[to] = depths[node] + 1
+			queue = append(queue, to)
+		}
+	}
+
+	for _, node := range g.nodeOrder {
+		if _, ok := depths[node]; !ok {
+			depths[node] = 0
+		}
+	}
+
+	return depths
+}
+
+// Unreachable returns the nodes that cannot be reached from any of the starting nodes, such as the nodes of a cycle
+// that has no starting node, in the order that they were added.
+func (g *Graph) Unreachable() []int {
+	reached := make(map[int]bool)
+	var queue []int
+	for _, node := range g.nodeOrder {
+		if g.starting[node] {
+			reached[node] = true
+			queue = append(queue, node)
+		}
+	}
+
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+
+		for _, to := range g.graph[node] {
+			if reached[to] {
+				continue
+			}
+
+			reached[to] = true
+			queue = append(queue, to)
+		}
+	}
+
+	var unreachable []int
+	for _, node := range g.nodeOrder {
+		if !reached[node] {
+			unreachable = append(unreachable, node)
+		}
+	}
+
+	return unreachable
+}
+
+// ClosedCycles returns the cycles that have no transition out of the cycle and so never reach a terminal node. Each
+// cycle is the sorted nodes of a strongly connected component of the graph.
+func (g *Graph) ClosedCycles() [][]int {
+	var (
+		index    int
+		indices  = make(map[int]int)
+		lowLinks = make(map[int]int)
+		onStack  = make(map[int]bool)
+		stack    []int
+		cycles   [][]int
+	)
+
+	var connect func(node int)
+	connect = func(node int) {
+		indices[node] = index
+		lowLinks[node] = index
+		index++
+		stack = append(stack, node)
+		onStack[node] = true
+
+		for _, to := range g.graph[node] {
+			if _, ok := indices[to]; !ok {
+				connect(to)
+				lowLinks[node] = min(lowLinks[node], lowLinks[to])
+			} else if onStack[to] {
+				lowLinks[node] = min(lowLinks[node], indices[to])
+			}
+		}
+
+		if lowLinks[node] != indices[node] {
+			return
+		}
+
+		var component []int
+		for {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[n] = false
+			component = append(component, n)
+			if n == node {
+				break
+			}
+		}
+
+		if g.isClosedCycle(component) {
+			slices.Sort(component)
+			cycles = append(cycles, component)
+		}
+	}
+
+	for _, node := range g.nodeOrder {
+		if _, ok := indices[node]; !ok {
+			connect(node)
+		}
+	}
+
+	return cycles
+}
+
+func (g *Graph) isClosedCycle(component []int) bool {
+	// A single node is only a cycle when it transitions to itself.
+	if len(component) == 1 && !slices.Contains(g.graph[component[0]], component[0]) {
+		return false
+	}
+
+	for _, node := range component {
+		for _, to := range g.graph[node] {
+			if !slices.Contains(component, to) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
@@ -58,3 +58,64 @@ func TestGraph(t *testing.T) {
 	expectedNodes := []int{1, 2, 3, 4, 5}
 	require.Equal(t, expectedNodes, actualNodes)
 }
+
+func TestHash(t *testing.T) {
+	a := graph.New()
+	a.AddTransition(1, 2)
+	a.AddTransition(2, 3)
+
+	b := graph.New()
+	b.AddTransition(2, 3)
+	b.AddTransition(1, 2)
+	require.Equal(t, a.Hash(), b.Hash())
+
+	b.AddTransition(1, 3)
+	require.NotEqual(t, a.Hash(), b.Hash())
+}
+
+func TestDepths(t *testing.T) {
+	g := graph.New()
+	g.AddTransition(1, 2)
+	g.AddTransition(2, 3)
+	g.AddTransition(1, 3)
+	g.AddTransition(3, 4)
+	g.AddTransition(4, 3)
+	g.AddTransition(5, 6)
+	g.AddTransition(6, 5)
+
+	require.Equal(t, map[int]int{
+		1: 0,
+		2: 1,
+		3: 1,
+		4: 2,
+		5: 0,
+		6: 0,
+	}, g.Depths())
+}
+
+func TestUnreachable(t *testing.T) {
+	g := graph.New()
+	g.AddTransition(1, 2)
+	g.AddTransition(2, 3)
+	require.Empty(t, g.Unreachable())
+
+	// 4 and 5 form a cycle that has no starting node.
+	g.AddTransition(4, 5)
+	g.AddTransition(5, 4)
+	g.AddTransition(5, 3)
+	require.Equal(t, []int{4, 5}, g.Unreachable())
+}
+
+func TestClosedCycles(t *testing.T) {
+	g := graph.New()
+	g.AddTransition(1, 2)
+	g.AddTransition(2, 1)
+	g.AddTransition(2, 3)
+	require.Empty(t, g.ClosedCycles())
+
+	g.AddTransition(3, 4)
+	g.AddTransition(4, 5)
+	g.AddTransition(5, 4)
+	g.AddTransition(6, 6)
+	require.Equal(t, [][]int{{4, 5}, {6}}, g.ClosedCycles())
+}
@@ -1,6 +1,19 @@
 package metrics
 
-import "github.com/prometheus/client_golang/prometheus"
+import (
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Native histograms are exposed alongside the classic buckets of the latency histograms and are only scraped by
+// Prometheus servers that have native histograms enabled.
+const (
+	nativeHistogramBucketFactor     = 1.1
+	nativeHistogramMaxBucketNumber  = 100
+	nativeHistogramMinResetDuration = time.Hour
+)
 
 const (
 	workflowName     = "workflow_name"
@@ -8,6 +21,10 @@ const (
 	previousRunState = "previous_run_state"
 	currentRunState  = "current_run_state"
 	reason           = "reason"
+	adapter          = "adapter"
+	operation        = "operation"
+	shard            = "shard"
+	status           = "status"
 )
 
 var (
@@ -32,9 +49,12 @@ var (
 
 	// ProcessLatency is how long the process is taking to process an event
 	ProcessLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
-		Name:    "workflow_process_latency_seconds",
-		Help:    "Event loop latency in seconds",
-		Buckets: []float64{0.01, 0.1, 1, 5, 10, 60, 300},
+		Name:                            "workflow_process_latency_seconds",
+		Help:                            "Event loop latency in seconds",
+		Buckets:                         []float64{0.01, 0.1, 1, 5, 10, 60, 300},
+		NativeHistogramBucketFactor:     nativeHistogramBucketFactor,
+		NativeHistogramMaxBucketNumber:  nativeHistogramMaxBucketNumber,
+		NativeHistogramMinResetDuration: nativeHistogramMinResetDuration,
 	}, []string{workflowName, processName})
 
 	// ProcessErrors is the number of errors from processing events
@@ -43,27 +63,177 @@ var (
 		Help: "Number of errors processing events",
 	}, []string{workflowName, processName})
 
+	// ProcessConsumedEvents is the number of events consumed by the process
+	ProcessConsumedEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "workflow_process_consumed_events_count",
+		Help: "Number of events consumed by the process",
+	}, []string{workflowName, processName})
+
+	// ProcessStoreUnavailable is the number of times a process has backed off due to the record store being unavailable
+	ProcessStoreUnavailable = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "workflow_process_store_unavailable_count",
+		Help: "Number of times the process backed off due to the record store being unavailable",
+	}, []string{workflowName, processName})
+
+	// ProcessFenced is the number of times a process has backed off due to a newer workflow definition being active
+	ProcessFenced = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "workflow_process_fenced_count",
+		Help: "Number of times the process backed off due to a newer workflow definition being active",
+	}, []string{workflowName, processName})
+
 	// ProcessSkippedEvents is the number of events skipped by the process
 	ProcessSkippedEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Name: "workflow_process_skipped_events_count",
 		Help: "Number of events skipped by consumer",
 	}, []string{workflowName, processName, reason})
 
+	// ShardLag is the age of the last event processed by each shard of consumers with more than one shard
+	ShardLag = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "workflow_process_shard_lag_seconds",
+		Help: "lag between now and the timestamp of the last event processed by the shard in seconds",
+	}, []string{workflowName, processName, shard})
+
+	// ShardProcessedEvents is the number of events processed by each shard of consumers with more than one shard
+	ShardProcessedEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "workflow_process_shard_processed_events_count",
+		Help: "Number of events processed by the shard",
+	}, []string{workflowName, processName, shard})
+
+	// RunsQuarantined is the number of runs moved into RunStateQuarantined by the process
+	RunsQuarantined = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "workflow_process_quarantined_runs_count",
+		Help: "Number of runs quarantined due to their object being unable to be unmarshalled",
+	}, []string{workflowName, processName})
+
+	// RunsPaused is the number of runs paused by the process after exceeding their allowed error count
+	RunsPaused = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "workflow_process_paused_runs_count",
+		Help: "Number of runs paused after exceeding the allowed error count",
+	}, []string{workflowName, processName})
+
+	// UnknownStatusEvents is the number of events received by the process for statuses that the workflow does not
+	// know, such as statuses that have been removed
+	UnknownStatusEvents = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "workflow_process_unknown_status_events_count",
+		Help: "Number of events received for statuses that the workflow does not know",
+	}, []string{workflowName, processName, status})
+
+	// CircuitBreakerOpen is whether the circuit breaker of a step is open and the step has stopped consuming
+	CircuitBreakerOpen = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "workflow_process_circuit_breaker_open",
+		Help: "Whether or not the circuit breaker of the step is open",
+	}, []string{workflowName, processName})
+
+	// TimeInStatus is how long runs spend in each status before moving onto their next status
+	TimeInStatus = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:                            "workflow_run_time_in_status_seconds",
+		Help:                            "Time spent by runs in a status before moving onto the next status in seconds",
+		Buckets:                         []float64{0.1, 1, 10, 60, 300, 900, 3600, 21600, 86400},
+		NativeHistogramBucketFactor:     nativeHistogramBucketFactor,
+		NativeHistogramMaxBucketNumber:  nativeHistogramMaxBucketNumber,
+		NativeHistogramMinResetDuration: nativeHistogramMinResetDuration,
+	}, []string{workflowName, status})
+
+	// OutboxEvents is the number of events in the outbox that are waiting to be published. The number is capped at
+	// the outbox's lookup limit when the RecordStore does not implement workflow.OutboxCounter
+	OutboxEvents = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "workflow_outbox_events",
+		Help: "Number of events waiting to be published from the outbox",
+	}, []string{workflowName})
+
+	// OutboxOldestEventAge is the age of the oldest event in the outbox, up to the outbox's lookup limit, and is zero
+	// when the outbox is empty
+	OutboxOldestEventAge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "workflow_outbox_oldest_event_age_seconds",
+		Help: "Age of the oldest event waiting to be published from the outbox in seconds",
+	}, []string{workflowName})
+
 	// RunStateChanges reflects the states of all the runs for the workflow
 	RunStateChanges = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Name: "workflow_run_state_changes",
 		Help: "The number of workflow run state changes going from state to a new state",
 	}, []string{workflowName, previousRunState, currentRunState})
+
+	// AdapterLatency is how long each operation of an instrumented adapter takes
+	AdapterLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:                            "workflow_adapter_operation_latency_seconds",
+		Help:                            "Adapter operation latency in seconds",
+		Buckets:                         []float64{0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5},
+		NativeHistogramBucketFactor:     nativeHistogramBucketFactor,
+		NativeHistogramMaxBucketNumber:  nativeHistogramMaxBucketNumber,
+		NativeHistogramMinResetDuration: nativeHistogramMinResetDuration,
+	}, []string{adapter, operation})
+
+	// AdapterErrors is the number of errors returned by each operation of an instrumented adapter
+	AdapterErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "workflow_adapter_operation_error_count",
+		Help: "Number of errors returned by adapter operations",
+	}, []string{adapter, operation})
+
+	// AdapterPayloadSize is the size of the data written or read by each operation of an instrumented adapter
+	AdapterPayloadSize = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "workflow_adapter_operation_payload_bytes",
+		Help:    "Size of the data written or read by adapter operations in bytes",
+		Buckets: prometheus.ExponentialBuckets(64, 4, 8),
+	}, []string{adapter, operation})
 )
 
-func init() {
-	prometheus.MustRegister(
+// ObserveWithExemplar observes the value with the exemplar's labels, such as the ID of the run's trace, so that an
+// observation can be linked to the run that it was made for. The value is observed without an exemplar when the
+// exemplar has no labels or the Observer does not support exemplars.
+func ObserveWithExemplar(o prometheus.Observer, value float64, exemplar prometheus.Labels) {
+	eo, ok := o.(prometheus.ExemplarObserver)
+	if !ok || len(exemplar) == 0 {
+		o.Observe(value)
+		return
+	}
+
+	eo.ObserveWithExemplar(value, exemplar)
+}
+
+// Collectors returns all the metrics.
+func Collectors() []prometheus.Collector {
+	return []prometheus.Collector{
 		ConsumerLag,
 		ConsumerLagAlert,
 		ProcessStates,
 		ProcessLatency,
 		ProcessErrors,
+		ProcessConsumedEvents,
+		ProcessStoreUnavailable,
+		ProcessFenced,
 		ProcessSkippedEvents,
+		ShardLag,
+		ShardProcessedEvents,
+		RunsQuarantined,
+		RunsPaused,
+		UnknownStatusEvents,
+		CircuitBreakerOpen,
+		TimeInStatus,
+		OutboxEvents,
+		OutboxOldestEventAge,
 		RunStateChanges,
-	)
+		AdapterLatency,
+		AdapterErrors,
+		AdapterPayloadSize,
+	}
+}
+
+// Register registers all the metrics with the Registerer. Metrics that have already been registered with the
+// Registerer, such as by another workflow, are skipped.
+func Register(r prometheus.Registerer) error {
+	for _, c := range Collectors() {
+		err := r.Register(c)
+		if errors.As(err, &prometheus.AlreadyRegisteredError{}) {
+			continue
+		} else if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func init() {
+	prometheus.MustRegister(Collectors()...)
 }
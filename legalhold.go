@@ -0,0 +1,164 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// LegalHold represents a hold placed on all runs of a foreignID. Whilst a hold is active, the data of any of the
+// foreignID's runs will not be deleted, even if the run has been moved into RunStateRequestedDataDeleted.
+type LegalHold struct {
+	ID           int64
+	WorkflowName string
+	ForeignID    string
+	Reason       string
+	PlacedAt     time.Time
+
+	// LiftReason and LiftedAt are only populated once the hold has been lifted.
+	LiftReason string
+	LiftedAt   time.Time
+}
+
+// Active returns true if the hold has not yet been lifted.
+func (h LegalHold) Active() bool {
+	return h.LiftedAt.IsZero()
+}
+
+// LegalHoldStore implementations should all be tested with adaptertest.RunLegalHoldStoreTest. Lifted holds must be
+// retained and returned by List so that the store serves as the audit log of all holds placed on a foreignID.
+type LegalHoldStore interface {
+	// Place creates a new active hold. ErrLegalHoldActive is returned if there is already an active hold for
+	// the foreignID.
+	Place(ctx context.Context, workflowName, foreignID, reason string, placedAt time.Time) error
+	// Lift marks the active hold as lifted. ErrLegalHoldNotFound is returned if there is no active hold for
+	// the foreignID.
+	Lift(ctx context.Context, workflowName, foreignID, reason string, liftedAt time.Time) error
+	// List returns all the holds, active and lifted, for the foreignID in the order that they were placed.
+	List(ctx context.Context, workflowName, foreignID string) ([]LegalHold, error)
+}
+
+// WithLegalHoldStore allows the configuration of a LegalHoldStore which is required to place legal holds on the runs
+// of a foreignID.
+func WithLegalHoldStore(s LegalHoldStore) BuildOption {
+	return func(bo *buildOptions) {
+		bo.legalHoldStore = s
+	}
+}
+
+// PlaceLegalHold blocks the deletion of the data of all runs for the provided foreignID until LiftLegalHold is called.
+// Requests to delete data that are made whilst the hold is active are retained and processed once the hold is lifted.
+func (w *Workflow[Type, Status]) PlaceLegalHold(ctx context.Context, foreignID, reason string) error {
+	if w.legalHoldStore == nil {
+		return fmt.Errorf("place legal hold failed: %w", errNoLegalHoldStore)
+	}
+
+	err := w.legalHoldStore.Place(ctx, w.Name(), foreignID, reason, w.clock.Now())
+	if err != nil {
+		return err
+	}
+
+	w.logger.event(ctx, DebugEvent{
+		Type:    DebugEventLegalHoldPlaced,
+		Message: "legal hold placed",
+		Meta: map[string]string{
+			"workflow_name": w.Name(),
+			"foreign_id":    foreignID,
+			"reason":        reason,
+		},
+	})
+
+	return nil
+}
+
+// LiftLegalHold lifts the active hold for the provided foreignID and resumes the deletion of data for any of its runs
+// that were requested to be deleted whilst the hold was active.
+func (w *Workflow[Type, Status]) LiftLegalHold(ctx context.Context, foreignID, reason string) error {
+	if w.legalHoldStore == nil {
+		return fmt.Errorf("lift legal hold failed: %w", errNoLegalHoldStore)
+	}
+
+	err := w.legalHoldStore.Lift(ctx, w.Name(), foreignID, reason, w.clock.Now())
+	if err != nil {
+		return err
+	}
+
+	w.logger.event(ctx, DebugEvent{
+		Type:    DebugEventLegalHoldLifted,
+		Message: "legal hold lifted",
+		Meta: map[string]string{
+			"workflow_name": w.Name(),
+			"foreign_id":    foreignID,
+			"reason":        reason,
+		},
+	})
+
+	// Any deletion requests that were skipped whilst the hold was active need to be re-emitted so that the delete
+	// consumer can process them.
+	var offset int64
+	for {
+		records, err := w.recordStore.List(
+			ctx,
+			w.Name(),
+			offset,
+			legalHoldListLimit,
+			OrderTypeAscending,
+			FilterByForeignID(foreignID),
+			FilterByRunState(RunStateRequestedDataDeleted),
+		)
+		if err != nil {
+			return err
+		}
+
+		for _, record := range records {
+			err := updateRecord(ctx, w.recordStore.Store, &record, RunStateRequestedDataDeleted)
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(records) < legalHoldListLimit {
+			return nil
+		}
+
+		offset += int64(len(records))
+	}
+}
+
+// LegalHolds returns all the holds, active and lifted, that have been placed on the provided foreignID.
+func (w *Workflow[Type, Status]) LegalHolds(ctx context.Context, foreignID string) ([]LegalHold, error) {
+	if w.legalHoldStore == nil {
+		return nil, fmt.Errorf("list legal holds failed: %w", errNoLegalHoldStore)
+	}
+
+	return w.legalHoldStore.List(ctx, w.Name(), foreignID)
+}
+
+// legalHoldListLimit is the page size used when listing the records that need to be re-emitted once a hold is lifted.
+const legalHoldListLimit = 100
+
+var errNoLegalHoldStore = errors.New("no LegalHoldStore configured for workflow")
+
+type legalHoldCheck func(ctx context.Context, workflowName, foreignID string) (bool, error)
+
+func newLegalHoldCheck(store LegalHoldStore) legalHoldCheck {
+	if store == nil {
+		return nil
+	}
+
+	return func(ctx context.Context, workflowName, foreignID string) (bool, error) {
+		holds, err := store.List(ctx, workflowName, foreignID)
+		if err != nil {
+			return false, err
+		}
+
+		for _, hold := range holds {
+			if hold.Active() {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	}
+}
@@ -0,0 +1,94 @@
+package workflow_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memlegalholdstore"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestWorkflow_LegalHold(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	b := workflow.NewBuilder[MyType, status]("legal hold")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	recordStore := memrecordstore.New()
+	wf := b.Build(
+		memstreamer.New(),
+		recordStore,
+		memrolescheduler.New(),
+		workflow.WithLegalHoldStore(memlegalholdstore.New()),
+	)
+
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	foreignID := "andrew"
+	runID, err := wf.Trigger(ctx, foreignID, StatusStart, workflow.WithInitialValue[MyType, status](&MyType{
+		Name: "Andrew Wormald",
+	}))
+	require.Nil(t, err)
+
+	_, err = wf.Await(ctx, foreignID, runID, StatusEnd)
+	require.Nil(t, err)
+
+	err = wf.PlaceLegalHold(ctx, foreignID, "litigation")
+	require.Nil(t, err)
+
+	err = wf.PlaceLegalHold(ctx, foreignID, "litigation")
+	require.True(t, errors.Is(err, workflow.ErrLegalHoldActive))
+
+	record, err := recordStore.Lookup(ctx, runID)
+	require.Nil(t, err)
+
+	err = workflow.NewRunStateController(recordStore.Store, record).DeleteData(ctx)
+	require.Nil(t, err)
+
+	// Allow the delete consumer time to attempt to process the deletion request.
+	time.Sleep(time.Second)
+
+	record, err = recordStore.Lookup(ctx, runID)
+	require.Nil(t, err)
+	require.Equal(t, workflow.RunStateRequestedDataDeleted, record.RunState)
+
+	err = wf.LiftLegalHold(ctx, foreignID, "settled")
+	require.Nil(t, err)
+
+	require.Eventually(t, func() bool {
+		record, err := recordStore.Lookup(ctx, runID)
+		require.Nil(t, err)
+
+		return record.RunState == workflow.RunStateDataDeleted
+	}, 5*time.Second, 10*time.Millisecond)
+
+	holds, err := wf.LegalHolds(ctx, foreignID)
+	require.Nil(t, err)
+	require.Equal(t, 1, len(holds))
+	require.Equal(t, "litigation", holds[0].Reason)
+	require.Equal(t, "settled", holds[0].LiftReason)
+	require.False(t, holds[0].Active())
+}
+
+func TestWorkflow_LegalHoldRequiresStore(t *testing.T) {
+	b := workflow.NewBuilder[MyType, status]("legal hold")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	wf := b.Build(memstreamer.New(), memrecordstore.New(), memrolescheduler.New())
+
+	err := wf.PlaceLegalHold(context.Background(), "andrew", "litigation")
+	require.NotNil(t, err)
+}
@@ -0,0 +1,129 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"strconv"
+)
+
+// LockStore implementations should all be tested with adaptertest.RunLockStoreTest. A LockStore is a distributed
+// semaphore that is used by the workflow for concurrency keys, see WithConcurrencyKey, and can be used by ConsumerFuncs
+// to take short-lived locks that are coordinated in the same way as the workflow's.
+type LockStore interface {
+	// Acquire must return a child context of the provided (parent) context. Acquire should block until one of the
+	// limit slots of the key is held by the caller and so at most limit callers hold the key at any given time. The
+	// returned context must be cancelled when the slot is lost and the returned context.CancelFunc releases the slot.
+	Acquire(ctx context.Context, key string, limit int) (context.Context, context.CancelFunc, error)
+}
+
+// WithLockStore configures the LockStore used for concurrency keys. By default the slots of a key are held as roles
+// using the workflow's RoleScheduler. The LockStore is returned by Workflow.LockStore so that ConsumerFuncs can use
+// the same LockStore as the workflow.
+func WithLockStore(s LockStore) BuildOption {
+	return func(bo *buildOptions) {
+		bo.lockStore = s
+	}
+}
+
+// LockStore returns the LockStore configured using WithLockStore or the LockStore that holds the slots of a key as
+// roles using the workflow's RoleScheduler.
+func (w *Workflow[Type, Status]) LockStore() LockStore {
+	return w.lockStore
+}
+
+// NewRoleSchedulerLockStore returns a LockStore that holds each of the slots of a key as a role using the
+// RoleScheduler. Acquire waits for all the slots of the key at once and holds the first slot that is assigned.
+func NewRoleSchedulerLockStore(scheduler RoleScheduler) LockStore {
+	return &roleSchedulerLockStore{scheduler: scheduler}
+}
+
+type roleSchedulerLockStore struct {
+	scheduler RoleScheduler
+}
+
+func (s *roleSchedulerLockStore) Acquire(
+	ctx context.Context,
+	key string,
+	limit int,
+) (context.Context, context.CancelFunc, error) {
+	if limit <= 0 {
+		return nil, nil, errors.New("acquire lock: limit must be positive")
+	}
+
+	if limit == 1 {
+		return s.scheduler.Await(ctx, makeRole(key, "0"))
+	}
+
+	type slot struct {
+		index  int
+		ctx    context.Context
+		cancel context.CancelFunc
+		err    error
+	}
+
+	slots := make(chan slot, limit)
+	stops := make([]context.CancelFunc, limit)
+	for i := range limit {
+		slotCtx, stop := context.WithCancel(ctx)
+		stops[i] = stop
+
+		go func() {
+			ctx, cancel, err := s.scheduler.Await(slotCtx, makeRole(key, strconv.Itoa(i)))
+			if err != nil {
+				slots <- slot{index: i, err: err}
+				return
+			}
+
+			slots <- slot{
+				index: i,
+				ctx:   ctx,
+				cancel: func() {
+					cancel()
+					stop()
+				},
+			}
+		}()
+	}
+
+	var (
+		held  slot
+		errs  []error
+		count int
+	)
+	for held.ctx == nil && count < limit {
+		res := <-slots
+		count++
+		if res.err != nil {
+			errs = append(errs, res.err)
+			continue
+		}
+
+		held = res
+	}
+
+	if held.ctx == nil {
+		if ctx.Err() != nil {
+			return nil, nil, ctx.Err()
+		}
+
+		return nil, nil, errors.Join(errs...)
+	}
+
+	// The other slots stop waiting and those that were assigned at the same time as the held slot are released.
+	for i, stop := range stops {
+		if i != held.index {
+			stop()
+		}
+	}
+
+	go func() {
+		for range limit - count {
+			res := <-slots
+			if res.cancel != nil {
+				res.cancel()
+			}
+		}
+	}()
+
+	return held.ctx, held.cancel, nil
+}
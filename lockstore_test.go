@@ -0,0 +1,15 @@
+package workflow_test
+
+import (
+	"testing"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/adaptertest"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+)
+
+func TestRoleSchedulerLockStore(t *testing.T) {
+	adaptertest.RunLockStoreTest(t, func(t *testing.T) workflow.LockStore {
+		return workflow.NewRoleSchedulerLockStore(memrolescheduler.New())
+	})
+}
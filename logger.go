@@ -23,6 +23,7 @@ type Logger interface {
 type logger struct {
 	debugMode bool
 	inner     Logger
+	sink      DebugEventSink
 }
 
 // Debug only writes the log if the Workflow was built using WithDebugMode
@@ -38,3 +39,17 @@ func (l *logger) Debug(ctx context.Context, msg string, meta map[string]string)
 func (l *logger) Error(ctx context.Context, err error) {
 	l.inner.Error(ctx, err)
 }
+
+// event emits the event to the DebugEventSink, when one is configured using WithDebugEventSink, and writes it to the
+// debug logs if the Workflow was built using WithDebugMode. Events are dropped when no logger is configured.
+func (l *logger) event(ctx context.Context, e DebugEvent) {
+	if l == nil {
+		return
+	}
+
+	if l.sink != nil {
+		l.sink(ctx, e)
+	}
+
+	l.Debug(ctx, e.Message, e.Meta)
+}
@@ -4,7 +4,7 @@ import (
 	"encoding/json"
 )
 
-// Marshal create a single point of change if the encoding changes.
+// Marshal encodes using JSONCodec, the default Codec of a workflow.
 func Marshal[T any](t *T) ([]byte, error) {
 	return json.Marshal(t)
 }
@@ -0,0 +1,42 @@
+package workflow
+
+import "maps"
+
+// WithMetadata sets the metadata of the run when it is triggered, such as a correlation ID or the tenant that the run
+// belongs to. See Run.SetMetadata.
+func WithMetadata[Type any, Status StatusType](metadata map[string]string) TriggerOption[Type, Status] {
+	return func(o *triggerOpts[Type, Status]) {
+		o.metadata = metadata
+	}
+}
+
+// Metadata returns the value of the run's metadata for the key and false when the run has no metadata for the key.
+func (r *Run[Type, Status]) Metadata(key string) (string, bool) {
+	value, ok := r.Meta.Metadata[key]
+	return value, ok
+}
+
+// SetMetadata sets the value of the run's metadata for the key. Metadata is stored with the run, separately from its
+// Object, when a step moves the run onto its next status and so cross-cutting data, such as correlation IDs, the actor,
+// or the tenant, is available to every step without being added to the Object's type.
+func (r *Run[Type, Status]) SetMetadata(key, value string) {
+	// The metadata is copied as the run's Meta may be shared with the record it was built from.
+	metadata := maps.Clone(r.Meta.Metadata)
+	if metadata == nil {
+		metadata = make(map[string]string)
+	}
+
+	metadata[key] = value
+	r.Meta.Metadata = metadata
+}
+
+// DeleteMetadata removes the key from the run's metadata.
+func (r *Run[Type, Status]) DeleteMetadata(key string) {
+	if _, ok := r.Meta.Metadata[key]; !ok {
+		return
+	}
+
+	metadata := maps.Clone(r.Meta.Metadata)
+	delete(metadata, key)
+	r.Meta.Metadata = metadata
+}
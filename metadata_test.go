@@ -0,0 +1,60 @@
+package workflow_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestRunMetadata(t *testing.T) {
+	b := workflow.NewBuilder[MyType, status]("metadata")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		correlationID, ok := r.Metadata("correlation_id")
+		if !ok {
+			return 0, nil
+		}
+
+		r.SetMetadata("actor", "pricing-service")
+		r.SetMetadata("seen_correlation_id", correlationID)
+		return StatusMiddle, nil
+	}, StatusMiddle)
+	b.AddStep(StatusMiddle, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		r.DeleteMetadata("seen_correlation_id")
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "foreignID", StatusStart, workflow.WithMetadata[MyType, status](map[string]string{
+		"correlation_id": "abc-123",
+		"tenant":         "acme",
+	}))
+	require.Nil(t, err)
+
+	run, err := wf.Await(ctx, "foreignID", runID, StatusEnd, workflow.WithAwaitPollingFrequency(10*time.Millisecond))
+	require.Nil(t, err)
+
+	require.Equal(t, map[string]string{
+		"correlation_id": "abc-123",
+		"tenant":         "acme",
+		"actor":          "pricing-service",
+	}, run.Meta.Metadata)
+
+	// The Object is not affected by the metadata.
+	require.Equal(t, MyType{}, *run.Object)
+}
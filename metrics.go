@@ -0,0 +1,47 @@
+package workflow
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/luno/workflow/internal/metrics"
+)
+
+// WithMetricsRegistry registers the workflow's Prometheus metrics with the Registerer, such as a service's own
+// prometheus.Registry, in addition to the default registry. The metrics are shared by all the workflows of the
+// process and are labelled by workflow name, and so multiple workflows can be built using the same Registerer.
+func WithMetricsRegistry(r prometheus.Registerer) BuildOption {
+	return func(bo *buildOptions) {
+		bo.metricsRegisterer = r
+	}
+}
+
+func registerMetrics(r prometheus.Registerer) {
+	if r == nil {
+		return
+	}
+
+	err := metrics.Register(r)
+	if err != nil {
+		panic("failed to register metrics: " + err.Error())
+	}
+}
+
+// exemplar returns the labels of the exemplar that links a latency observation to the run and, when tracing is enabled
+// using WithTracerProvider, to the run's trace so that a latency spike can be followed to an affected run. Exemplars
+// are only exposed when the metrics are scraped using the OpenMetrics format.
+func exemplar(runID, traceParent string) prometheus.Labels {
+	labels := make(prometheus.Labels)
+	if runID != "" {
+		labels["run_id"] = runID
+	}
+
+	sc := trace.SpanContextFromContext(extractTraceParent(context.Background(), traceParent))
+	if sc.IsValid() {
+		labels["trace_id"] = sc.TraceID().String()
+	}
+
+	return labels
+}
@@ -9,8 +9,10 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	clock_testing "k8s.io/utils/clock/testing"
 
 	"github.com/luno/workflow"
@@ -407,6 +409,123 @@ func TestMetricProcessSkippedEvents(t *testing.T) {
 	metrics.ProcessSkippedEvents.Reset()
 }
 
+func TestWithMetricsRegistry(t *testing.T) {
+	metrics.ProcessConsumedEvents.Reset()
+	metrics.TimeInStatus.Reset()
+
+	registry := prometheus.NewRegistry()
+
+	build := func(name string) *workflow.Workflow[string, status] {
+		b := workflow.NewBuilder[string, status](name)
+		b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+			return StatusMiddle, nil
+		}, StatusMiddle)
+		b.AddStep(StatusMiddle, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+			return StatusEnd, nil
+		}, StatusEnd)
+
+		return b.Build(
+			memstreamer.New(),
+			memrecordstore.New(),
+			memrolescheduler.New(),
+			workflow.WithMetricsRegistry(registry),
+		)
+	}
+
+	// Multiple workflows can share the same registry.
+	w := build("registry")
+	_ = build("registry-other")
+
+	ctx := context.Background()
+	w.Run(ctx)
+	t.Cleanup(w.Stop)
+
+	runID, err := w.Trigger(ctx, "foreignID", StatusStart)
+	require.Nil(t, err)
+
+	_, err = w.Await(ctx, "foreignID", runID, StatusEnd, workflow.WithAwaitPollingFrequency(10*time.Millisecond))
+	require.Nil(t, err)
+
+	families, err := registry.Gather()
+	require.Nil(t, err)
+
+	observed := make(map[string]bool)
+	for _, family := range families {
+		observed[family.GetName()] = true
+	}
+
+	require.True(t, observed["workflow_process_consumed_events_count"])
+	require.True(t, observed["workflow_run_time_in_status_seconds"])
+
+	// The time spent in the start and middle statuses is observed.
+	require.Equal(t, 2, testutil.CollectAndCount(metrics.TimeInStatus))
+	require.Equal(t, float64(1), testutil.ToFloat64(metrics.ProcessConsumedEvents.WithLabelValues("registry", "start-consumer-1-of-1")))
+
+	metrics.ProcessConsumedEvents.Reset()
+	metrics.TimeInStatus.Reset()
+}
+
+func TestMetricExemplars(t *testing.T) {
+	metrics.TimeInStatus.Reset()
+
+	registry := prometheus.NewRegistry()
+
+	b := workflow.NewBuilder[string, status]("exemplars")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	w := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+		workflow.WithMetricsRegistry(registry),
+		workflow.WithTracerProvider(sdktrace.NewTracerProvider()),
+	)
+
+	ctx := context.Background()
+	w.Run(ctx)
+	t.Cleanup(w.Stop)
+
+	runID, err := w.Trigger(ctx, "foreignID", StatusStart)
+	require.Nil(t, err)
+
+	_, err = w.Await(ctx, "foreignID", runID, StatusEnd, workflow.WithAwaitPollingFrequency(10*time.Millisecond))
+	require.Nil(t, err)
+
+	families, err := registry.Gather()
+	require.Nil(t, err)
+
+	var found bool
+	for _, family := range families {
+		if family.GetName() != "workflow_run_time_in_status_seconds" {
+			continue
+		}
+
+		for _, m := range family.GetMetric() {
+			h := m.GetHistogram()
+
+			// Native histograms are exposed alongside the classic buckets.
+			require.NotNil(t, h.Schema)
+
+			labels := make(map[string]string)
+			for _, bucket := range h.GetBucket() {
+				for _, l := range bucket.GetExemplar().GetLabel() {
+					labels[l.GetName()] = l.GetValue()
+				}
+			}
+
+			require.Equal(t, runID, labels["run_id"])
+			require.Len(t, labels["trace_id"], 32)
+			found = true
+		}
+	}
+
+	require.True(t, found)
+
+	metrics.TimeInStatus.Reset()
+}
+
 func update(ctx context.Context, store workflow.RecordStore, wr *workflow.Record) error {
 	return store.Store(ctx, wr)
 }
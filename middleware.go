@@ -0,0 +1,49 @@
+package workflow
+
+// ConsumerMiddleware wraps a ConsumerFunc to add cross-cutting behaviour, such as logging, panic recovery, or rate
+// limiting, to every step of the workflow.
+type ConsumerMiddleware[Type any, Status StatusType] func(next ConsumerFunc[Type, Status]) ConsumerFunc[Type, Status]
+
+// WithConsumerMiddleware wraps every step's ConsumerFunc with the provided middleware. Middleware is applied in the
+// order provided where the first middleware is the outermost and is called first. The Type and Status of the
+// middleware must match those of the workflow otherwise Build will panic.
+func WithConsumerMiddleware[Type any, Status StatusType](mw ...ConsumerMiddleware[Type, Status]) BuildOption {
+	return func(bo *buildOptions) {
+		for _, m := range mw {
+			bo.consumerMiddleware = append(bo.consumerMiddleware, m)
+		}
+	}
+}
+
+// buildConsumerMiddleware converts the untyped middleware provided via the BuildOptions into the workflow's types.
+func buildConsumerMiddleware[Type any, Status StatusType](mw []any) []ConsumerMiddleware[Type, Status] {
+	var typed []ConsumerMiddleware[Type, Status]
+	for _, m := range mw {
+		t, ok := m.(ConsumerMiddleware[Type, Status])
+		if !ok {
+			panic("consumer middleware must have the same Type and Status as the workflow")
+		}
+
+		typed = append(typed, t)
+	}
+
+	return typed
+}
+
+// applyConsumerMiddleware wraps the ConsumerFunc of the status with the middleware configured for the status, using
+// OnStatuses, and then with the middleware of the workflow.
+func (w *Workflow[Type, Status]) applyConsumerMiddleware(
+	status Status,
+	fn ConsumerFunc[Type, Status],
+) ConsumerFunc[Type, Status] {
+	statusMiddleware := w.statusMiddleware[status]
+	for i := len(statusMiddleware) - 1; i >= 0; i-- {
+		fn = statusMiddleware[i](fn)
+	}
+
+	for i := len(w.consumerMiddleware) - 1; i >= 0; i-- {
+		fn = w.consumerMiddleware[i](fn)
+	}
+
+	return fn
+}
@@ -0,0 +1,148 @@
+package workflow_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestWithConsumerMiddleware(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	var (
+		mu    sync.Mutex
+		calls []string
+	)
+
+	record := func(name string) workflow.ConsumerMiddleware[MyType, status] {
+		return func(next workflow.ConsumerFunc[MyType, status]) workflow.ConsumerFunc[MyType, status] {
+			return func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+				mu.Lock()
+				calls = append(calls, fmt.Sprintf("%s %s", name, r.Status))
+				mu.Unlock()
+
+				return next(ctx, r)
+			}
+		}
+	}
+
+	b := workflow.NewBuilder[MyType, status]("middleware")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		mu.Lock()
+		calls = append(calls, "step "+r.Status.String())
+		mu.Unlock()
+
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+		workflow.WithConsumerMiddleware(record("outer"), record("inner")),
+	)
+
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "example", StatusStart)
+	require.Nil(t, err)
+
+	_, err = wf.Await(ctx, "example", runID, StatusEnd)
+	require.Nil(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, []string{"outer Start", "inner Start", "step Start"}, calls)
+}
+
+func TestWithConsumerMiddleware_typeMismatch(t *testing.T) {
+	b := workflow.NewBuilder[MyType, status]("middleware")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	mw := func(next workflow.ConsumerFunc[string, status]) workflow.ConsumerFunc[string, status] {
+		return next
+	}
+
+	require.PanicsWithValue(t, "consumer middleware must have the same Type and Status as the workflow", func() {
+		b.Build(nil, nil, nil, workflow.WithConsumerMiddleware[string, status](mw))
+	})
+}
+
+func TestOnStatusesWithConsumerMiddleware(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	var (
+		mu    sync.Mutex
+		calls []string
+	)
+
+	record := func(name string) workflow.ConsumerMiddleware[MyType, status] {
+		return func(next workflow.ConsumerFunc[MyType, status]) workflow.ConsumerFunc[MyType, status] {
+			return func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+				mu.Lock()
+				calls = append(calls, fmt.Sprintf("%s %s", name, r.Status))
+				mu.Unlock()
+
+				return next(ctx, r)
+			}
+		}
+	}
+
+	step := func(next status) workflow.ConsumerFunc[MyType, status] {
+		return func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+			mu.Lock()
+			calls = append(calls, "step "+r.Status.String())
+			mu.Unlock()
+
+			return next, nil
+		}
+	}
+
+	b := workflow.NewBuilder[MyType, status]("status middleware")
+	b.AddStep(StatusStart, step(StatusMiddle), StatusMiddle)
+	b.AddStep(StatusMiddle, step(StatusEnd), StatusEnd)
+	b.OnStatuses(StatusMiddle).WithConsumerMiddleware(record("status"))
+
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+		workflow.WithConsumerMiddleware(record("workflow")),
+	)
+
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "example", StatusStart)
+	require.Nil(t, err)
+
+	_, err = wf.Await(ctx, "example", runID, StatusEnd)
+	require.Nil(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, []string{
+		"workflow Start",
+		"step Start",
+		"workflow Middle",
+		"status Middle",
+		"step Middle",
+	}, calls)
+}
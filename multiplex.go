@@ -0,0 +1,231 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// MultiplexedEventStreamer can optionally be implemented by an EventStreamer that is able to receive the events of
+// multiple topics using a single receiver. This allows for the step consumers of all the statuses of a workflow to
+// share a receiver, and its connection, per shard when configured using WithMultiplexedConsumers.
+//
+// The receiver must receive the events of all the topics and set the HeaderTopic header of each event to the topic
+// that it was received from so that the event can be routed to the consumer of its status.
+type MultiplexedEventStreamer interface {
+	EventStreamer
+	NewMultiplexedReceiver(
+		ctx context.Context,
+		topics []string,
+		name string,
+		opts ...ReceiverOption,
+	) (EventReceiver, error)
+}
+
+// WithMultiplexedConsumers runs the step consumers of all the statuses of the workflow using a single receiver per
+// shard instead of a receiver per status and shard. This reduces the number of receivers, and the connections of
+// streamers where connections are expensive, from one per status and shard to one per shard. The EventStreamer must
+// implement MultiplexedEventStreamer and priority lanes are not supported.
+//
+// Only the step consumers that are not batch steps and that are not configured with ConsumeLag are
+// multiplexed as the others cannot share the progress of a single receiver. Multiplexed consumers poll using the
+// fastest PollingFrequency of the statuses and use the workflow's default ErrBackOff and LagAlert.
+//
+// Enabling multiplexing changes the cursors of the step consumers and so the multiplexed consumers start consuming
+// from the position that the EventStreamer uses for new cursors. The cursor of a multiplexed consumer is shared by all
+// the statuses and resetting the cursor of one status resets it for all of them.
+func WithMultiplexedConsumers() BuildOption {
+	return func(bo *buildOptions) {
+		bo.multiplex = true
+	}
+}
+
+// multiplexedStreamer returns the MultiplexedEventStreamer of the workflow, or nil when the EventStreamer does not
+// implement it. Decorators of the EventStreamer are used when they preserve the implementation.
+func (w *Workflow[Type, Status]) multiplexedStreamer() MultiplexedEventStreamer {
+	inner, ok := unwrapEventStreamer(w.eventStreamer).(MultiplexedEventStreamer)
+	if !ok {
+		return nil
+	}
+
+	if outer, ok := w.eventStreamer.(MultiplexedEventStreamer); ok {
+		return outer
+	}
+
+	return inner
+}
+
+// multiplexed returns true when the step consumer is run by a multiplexed consumer.
+func (w *Workflow[Type, Status]) multiplexed(p consumerConfig[Type, Status]) bool {
+	if !w.multiplex {
+		return false
+	}
+
+	lag := w.defaultOpts.lag
+	if p.lag > 0 {
+		lag = p.lag
+	}
+
+	return p.batch == nil && lag == 0
+}
+
+// multiplexedGroups returns the statuses of the multiplexed consumers grouped by their number of shards as the
+// consumers of each shard share a receiver.
+func (w *Workflow[Type, Status]) multiplexedGroups() map[int][]Status {
+	groups := make(map[int][]Status)
+	for _, status := range w.statusGraph.Nodes() {
+		config, ok := w.consumers[Status(status)]
+		if !ok || !w.multiplexed(config) {
+			continue
+		}
+
+		parallelCount := w.defaultOpts.parallelCount
+		if config.parallelCount != 0 {
+			parallelCount = config.parallelCount
+		}
+
+		totalShards := max(parallelCount, 1)
+		groups[totalShards] = append(groups[totalShards], Status(status))
+	}
+
+	return groups
+}
+
+func multiplexedConsumerRole[Type any, Status StatusType](w *Workflow[Type, Status], shard, totalShards int) string {
+	return makeRole(
+		w.roleName(),
+		"multiplexed-consumer",
+		strconv.FormatInt(int64(shard), 10),
+		"of",
+		strconv.FormatInt(int64(totalShards), 10),
+	)
+}
+
+func consumeMultiplexedEvents[Type any, Status StatusType](
+	w *Workflow[Type, Status],
+	statuses []Status,
+	shard, totalShards int,
+) {
+	role := multiplexedConsumerRole(w, shard, totalShards)
+	processName := makeRole(
+		"multiplexed-consumer",
+		strconv.FormatInt(int64(shard), 10),
+		"of",
+		strconv.FormatInt(int64(totalShards), 10),
+	)
+
+	pauseAfterErrCount := w.defaultOpts.pauseAfterErrCount
+	maxPollingFrequency := w.defaultOpts.maxPollingFrequency
+
+	var (
+		topics           []string
+		pollingFrequency time.Duration
+		shutdownOrder    int
+	)
+	for i, status := range statuses {
+		topics = append(topics, w.topic(status))
+
+		order := w.statusShutdownOrder(status)
+		if i == 0 || order < shutdownOrder {
+			shutdownOrder = order
+		}
+
+		p := w.consumers[status]
+
+		frequency := w.defaultOpts.pollingFrequency
+		if p.pollingFrequency > 0 {
+			frequency = p.pollingFrequency
+		}
+
+		if pollingFrequency == 0 || frequency < pollingFrequency {
+			pollingFrequency = frequency
+		}
+
+		maxPollingFrequency = max(maxPollingFrequency, p.maxPollingFrequency)
+	}
+
+	w.run(role, processName, shutdownOrder, func(ctx context.Context) error {
+		receiverOpts := []ReceiverOption{
+			WithReceiverPollFrequency(pollingFrequency),
+			WithReceiverMaxPollFrequency(maxPollingFrequency),
+		}
+		if totalShards > 1 {
+			receiverOpts = append(receiverOpts, WithReceiverShard(shard, totalShards))
+		}
+
+		stream, err := w.multiplexedStreamer().NewMultiplexedReceiver(ctx, topics, role, receiverOpts...)
+		if err != nil {
+			return err
+		}
+		defer stream.Close()
+
+		updater := newUpdater[Type, Status](w.recordStore.Lookup, w.recordStore.Store, w.codec, w.statusGraph, w.clock)
+
+		// Each event is routed to the consumer of the status of its topic.
+		consumers := make(map[string]func(ctx context.Context, e *Event) error)
+		for _, status := range statuses {
+			p := w.consumers[status]
+
+			statusProcessName := makeRole(
+				status.String(),
+				"consumer",
+				strconv.FormatInt(int64(shard), 10),
+				"of",
+				strconv.FormatInt(int64(totalShards), 10),
+			)
+
+			pauseAfterErrCount := pauseAfterErrCount
+			if p.pauseAfterErrCount != 0 {
+				pauseAfterErrCount = p.pauseAfterErrCount
+			}
+
+			consumeFn := w.fence.guard(w.versionGuard(newStepConsumeFn(w, status, p, statusProcessName, updater, pauseAfterErrCount)))
+			shardProcessName := makeRole(status.String(), "consumer")
+			consumers[w.topic(status)] = w.shardObserved(shardProcessName, status, shard, totalShards, consumeFn)
+		}
+
+		filters := []EventFilter{
+			w.versionFilter(),
+			filterUnstarted(w.clock),
+		}
+
+		// Receivers that only receive the events of their shard don't need their events to be filtered by shard.
+		if !isSharded(stream) {
+			filters = append(filters, shardFilter(shard, totalShards))
+		}
+
+		return consume(
+			ctx,
+			w.Name(),
+			processName,
+			stream,
+			func(ctx context.Context, e *Event) error {
+				consumeFn, ok := consumers[e.Headers[HeaderTopic]]
+				if !ok && !w.statusGraph.IsValid(e.Type) {
+					return w.unknownStatus(ctx, processName, e)
+				} else if !ok {
+					w.logger.event(ctx, DebugEvent{
+						Type:    DebugEventSkipped,
+						Message: "skipping event of unknown topic",
+						Meta: map[string]string{
+							"workflow_name": w.Name(),
+							"process_name":  processName,
+							"topic":         e.Headers[HeaderTopic],
+							"event_id":      fmt.Sprintf("%v", e.ID),
+							"reason":        "unknown topic",
+						},
+					})
+
+					return nil
+				}
+
+				return consumeFn(ctx, e)
+			},
+			w.clock,
+			0,
+			w.defaultOpts.lagAlert,
+			filters...,
+		)
+	}, w.defaultOpts.errBackOff)
+}
@@ -0,0 +1,141 @@
+package workflow_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+// receiverCountingStreamer records the topics of every receiver that is created.
+type receiverCountingStreamer struct {
+	*memstreamer.StreamConstructor
+
+	mu          sync.Mutex
+	receivers   []string
+	multiplexed [][]string
+}
+
+func (s *receiverCountingStreamer) NewReceiver(
+	ctx context.Context,
+	topic string,
+	name string,
+	opts ...workflow.ReceiverOption,
+) (workflow.EventReceiver, error) {
+	s.mu.Lock()
+	s.receivers = append(s.receivers, topic)
+	s.mu.Unlock()
+
+	return s.StreamConstructor.NewReceiver(ctx, topic, name, opts...)
+}
+
+func (s *receiverCountingStreamer) NewMultiplexedReceiver(
+	ctx context.Context,
+	topics []string,
+	name string,
+	opts ...workflow.ReceiverOption,
+) (workflow.EventReceiver, error) {
+	s.mu.Lock()
+	s.multiplexed = append(s.multiplexed, topics)
+	s.mu.Unlock()
+
+	return s.StreamConstructor.NewMultiplexedReceiver(ctx, topics, name, opts...)
+}
+
+func TestWithMultiplexedConsumers(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("multiplexed")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		*r.Object += "start,"
+		return StatusMiddle, nil
+	}, StatusMiddle).WithOptions(
+		workflow.ParallelCount(2),
+		workflow.PollingFrequency(10*time.Millisecond),
+	)
+	b.AddStep(StatusMiddle, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		*r.Object += "middle"
+		return StatusEnd, nil
+	}, StatusEnd).WithOptions(
+		workflow.ParallelCount(2),
+		workflow.PollingFrequency(10*time.Millisecond),
+	)
+
+	streamer := &receiverCountingStreamer{StreamConstructor: memstreamer.New()}
+	wf := b.Build(
+		streamer,
+		memrecordstore.New(),
+		memrolescheduler.New(),
+		workflow.WithMultiplexedConsumers(),
+		workflow.WithOutboxPollingFrequency(10*time.Millisecond),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	for i := 0; i < 4; i++ {
+		foreignID := strconv.Itoa(i)
+		runID, err := wf.Trigger(ctx, foreignID, StatusStart)
+		require.Nil(t, err)
+
+		run, err := wf.Await(ctx, foreignID, runID, StatusEnd, workflow.WithAwaitPollingFrequency(10*time.Millisecond))
+		require.Nil(t, err)
+		require.Equal(t, "start,middle", *run.Object)
+	}
+
+	streamer.mu.Lock()
+	defer streamer.mu.Unlock()
+
+	// Both statuses share a receiver per shard.
+	require.Len(t, streamer.multiplexed, 2)
+	for _, topics := range streamer.multiplexed {
+		require.ElementsMatch(t, []string{
+			workflow.Topic(wf.Name(), int(StatusStart)),
+			workflow.Topic(wf.Name(), int(StatusMiddle)),
+		}, topics)
+	}
+
+	for _, topic := range streamer.receivers {
+		require.NotEqual(t, workflow.Topic(wf.Name(), int(StatusStart)), topic)
+		require.NotEqual(t, workflow.Topic(wf.Name(), int(StatusMiddle)), topic)
+	}
+}
+
+func TestWithMultiplexedConsumers_panics(t *testing.T) {
+	testCases := []struct {
+		name     string
+		streamer workflow.EventStreamer
+		opts     []workflow.BuildOption
+	}{
+		{
+			name:     "EventStreamer does not implement MultiplexedEventStreamer",
+			streamer: &asyncStreamer{EventStreamer: memstreamer.New()},
+		},
+		{
+			name:     "Priority lanes",
+			streamer: memstreamer.New(),
+			opts:     []workflow.BuildOption{workflow.WithPriorityLanes(1)},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := workflow.NewBuilder[string, status]("multiplexed panics")
+			b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+				return StatusEnd, nil
+			}, StatusEnd)
+
+			opts := append([]workflow.BuildOption{workflow.WithMultiplexedConsumers()}, tc.opts...)
+			require.Panics(t, func() {
+				b.Build(tc.streamer, memrecordstore.New(), memrolescheduler.New(), opts...)
+			})
+		})
+	}
+}
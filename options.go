@@ -6,8 +6,10 @@ import "time"
 type options struct {
 	parallelCount    int
 	pollingFrequency time.Duration
-	errBackOff       time.Duration
-	lag              time.Duration
+	// maxPollingFrequency is only configured when using AdaptivePolling.
+	maxPollingFrequency time.Duration
+	errBackOff          time.Duration
+	lag                 time.Duration
 
 	lagAlert          time.Duration
 	customLagAlertSet bool
@@ -15,6 +17,17 @@ type options struct {
 	// pauseAfterErrCount defines the number of errors before moving the record to RunStatePaused. Value of 0 will be
 	// treated as it not being configured and the user will retry forever as is default behaviour.
 	pauseAfterErrCount int
+
+	// dedupWindow is only used by connectors.
+	dedupWindow time.Duration
+
+	// rateLimit, circuitBreaker, retryPolicy, stepTimeout, sla, and deadlineFromSLA are only used by steps.
+	rateLimit       *rateLimit
+	circuitBreaker  *circuitBreakerConfig
+	retryPolicy     *retryPolicyConfig
+	stepTimeout     time.Duration
+	sla             time.Duration
+	deadlineFromSLA bool
 }
 
 func defaultOptions() options {
@@ -81,3 +94,12 @@ func PauseAfterErrCount(count int) Option {
 		opt.pauseAfterErrCount = count
 	}
 }
+
+// DedupWindow is only supported by connectors and prevents the connector from creating a new run for a foreignID if
+// the latest run of the foreignID was created with the same starting status within the window. This protects against
+// upstream replays creating duplicate runs. See WithDedupWindow.
+func DedupWindow(d time.Duration) Option {
+	return func(opt *options) {
+		opt.dedupWindow = d
+	}
+}
@@ -2,6 +2,8 @@ package workflow
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"time"
 
 	"google.golang.org/protobuf/proto"
@@ -25,12 +27,22 @@ func outboxConsumer[Type any, Status StatusType](w *Workflow[Type, Status], conf
 		pollingFrequency = config.pollingFrequency
 	}
 
+	maxPollingFrequency := w.outboxConfig.maxPollingFrequency
+	if config.maxPollingFrequency > 0 {
+		maxPollingFrequency = config.maxPollingFrequency
+	}
+
 	lagAlert := w.outboxConfig.lagAlert
 	if config.lagAlert > 0 {
 		lagAlert = config.lagAlert
 	}
 
-	w.run(role, processName, func(ctx context.Context) error {
+	poll := newPollInterval(pollingFrequency, maxPollingFrequency)
+	w.run(role, processName, w.outboxShutdownOrder(), func(ctx context.Context) error {
+		ctx = withLagAlertFunc(ctx, func(ctx context.Context, info LagAlertInfo) {
+			w.lagAlert(ctx, info, w.outboxConfig.lagAlertHandler)
+		})
+
 		return purgeOutbox[Type, Status](
 			ctx,
 			w.Name(),
@@ -38,27 +50,100 @@ func outboxConsumer[Type any, Status StatusType](w *Workflow[Type, Status], conf
 			w.recordStore,
 			w.eventStreamer,
 			w.clock,
-			pollingFrequency,
+			poll,
 			lagAlert,
 			config.limit,
+			config.batchSize,
 		)
 	}, errBackOff)
 }
 
+// WithDedicatedOutboxDrain results in Run not starting the outbox consumer or the timeout pollers. These processes
+// must then be run in a separate process, or binary, by calling RunOutboxDrain on a workflow built with the same
+// name and adapters. This allows the publishing of events to be scaled and isolated from the execution of steps.
+func WithDedicatedOutboxDrain() BuildOption {
+	return func(bo *buildOptions) {
+		bo.dedicatedOutboxDrain = true
+	}
+}
+
+// RunOutboxDrain starts only the outbox consumer and the timeout pollers of the workflow. It is intended for
+// processes that are dedicated to publishing events whilst the consumers are run elsewhere, using Run, on a workflow
+// built with WithDedicatedOutboxDrain. The RoleScheduler ensures that only one instance of each process is active
+// across all instances. Only one of Run or RunOutboxDrain should be called for a workflow and any subsequent calls to
+// either are noop.
+func (w *Workflow[Type, Status]) RunOutboxDrain(ctx context.Context) {
+	w.once.Do(func() {
+		w.provisionTopics(ctx)
+
+		if w.preflight {
+			err := w.Preflight(ctx)
+			if err != nil {
+				panic(err)
+			}
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		w.ctx = ctx
+		w.cancel = cancel
+		w.calledRun = true
+
+		w.launchOutboxDrain()
+	})
+
+	w.launching.Wait()
+}
+
+func (w *Workflow[Type, Status]) launchOutboxDrain() {
+	track(w, func() {
+		outboxConsumer(w, w.outboxConfig)
+	})
+
+	// Only start timeout pollers if the timeout store is provided.
+	if w.timeoutStore != nil {
+		for status, timeouts := range w.timeouts {
+			track(w, func() {
+				timeoutPoller(w, status, timeouts)
+			})
+		}
+
+		for key, timeouts := range w.timers {
+			track(w, func() {
+				timeoutPoller(w, key.status, timeouts)
+			})
+		}
+
+		// Runs triggered using WithStartAt or WithDelay can only be scheduled using a NamedTimeoutStore.
+		if _, ok := unwrapTimeoutStore(w.timeoutStore).(NamedTimeoutStore); ok {
+			track(w, func() {
+				scheduledStartPoller(w)
+			})
+		}
+	}
+}
+
 func defaultOutboxConfig() outboxConfig {
 	return outboxConfig{
 		errBackOff:       defaultOutboxErrBackOff,
 		pollingFrequency: defaultOutboxPollingFrequency,
 		lagAlert:         defaultOutboxLagAlert,
 		limit:            1000,
+		batchSize:        defaultOutboxBatchSize,
 	}
 }
 
 type outboxConfig struct {
 	errBackOff       time.Duration
 	pollingFrequency time.Duration
-	lagAlert         time.Duration
-	limit            int64
+	// maxPollingFrequency is only configured when using WithOutboxAdaptivePolling.
+	maxPollingFrequency time.Duration
+	lagAlert            time.Duration
+	limit               int64
+	// batchSize is the maximum number of events of a topic that are sent together by senders that implement
+	// BatchEventSender.
+	batchSize int
+	// lagAlertHandler is only configured when using WithOutboxLagAlertHandler.
+	lagAlertHandler LagAlertHandler
 }
 
 func WithOutboxPollingFrequency(d time.Duration) BuildOption {
@@ -79,12 +164,69 @@ func WithOutboxLookupLimit(limit int64) BuildOption {
 	}
 }
 
+// WithOutboxBatchSize sets the maximum number of events of a topic that the outbox sends in a single request when the
+// EventStreamer's senders implement BatchEventSender. The outbox reads up to its lookup limit of events, see
+// WithOutboxLookupLimit, each time it polls and creates a single sender per topic for all of them. Larger batches
+// increase the throughput of high volume workflows whereas smaller batches result in fewer events being sent again
+// when a batch fails. The batch size defaults to 100 and needs to be positive.
+func WithOutboxBatchSize(size int) BuildOption {
+	return func(bo *buildOptions) {
+		bo.outboxConfig.batchSize = size
+	}
+}
+
 func WithOutboxLagAlert(d time.Duration) BuildOption {
 	return func(bo *buildOptions) {
 		bo.outboxConfig.lagAlert = d
 	}
 }
 
+// WithOutboxLagAlertHandler sets the handler that is called when the outbox consumer starts publishing events that
+// are older than the outbox's lag alert, see WithOutboxLagAlert, and again once it has caught up, such as to page the
+// workflow's Owner when the EventStreamer is degraded and events are building up in the outbox. The handler is called
+// in addition to the handler set using WithLagAlertHandler. The outbox consumer is considered to have caught up once
+// it publishes an event within the outbox's lag alert or finds the outbox empty.
+func WithOutboxLagAlertHandler(h LagAlertHandler) BuildOption {
+	return func(bo *buildOptions) {
+		bo.outboxConfig.lagAlertHandler = h
+	}
+}
+
+// DrainOutbox publishes the events that are waiting in the workflow's outbox until the outbox is empty, such as to
+// flush the outbox once a degraded EventStreamer has recovered, or before shutting down the last instance of a
+// workflow. DrainOutbox does not require the workflow to be running and reports the outbox's lag in the same way as the outbox
+// consumer. Events may be published more than once when the
+// outbox consumer is publishing the same events at the same time which is allowed as events are delivered at least
+// once. The error of the first batch that fails to be published is returned and the remaining events are left in the
+// outbox.
+func (w *Workflow[Type, Status]) DrainOutbox(ctx context.Context) error {
+	processName := makeRole("outbox", "consumer")
+	ctx = withLagAlertFunc(ctx, func(ctx context.Context, info LagAlertInfo) {
+		w.lagAlert(ctx, info, w.outboxConfig.lagAlertHandler)
+	})
+
+	for {
+		n, err := publishOutbox[Type, Status](
+			ctx,
+			w.Name(),
+			processName,
+			w.recordStore,
+			w.eventStreamer,
+			w.clock,
+			w.outboxConfig.lagAlert,
+			w.outboxConfig.limit,
+			w.outboxConfig.batchSize,
+		)
+		if err != nil {
+			return fmt.Errorf("drain outbox: %w", err)
+		}
+
+		if n == 0 {
+			return nil
+		}
+	}
+}
+
 func purgeOutbox[Type any, Status StatusType](
 	ctx context.Context,
 	workflowName string,
@@ -92,25 +234,125 @@ func purgeOutbox[Type any, Status StatusType](
 	recordStore RecordStore,
 	stream EventStreamer,
 	clock clock.Clock,
-	pollingFrequency time.Duration,
+	poll *pollInterval,
 	lagAlert time.Duration,
 	lookupLimit int64,
+	batchSize int,
 ) error {
-	events, err := recordStore.ListOutboxEvents(ctx, workflowName, lookupLimit)
+	n, err := publishOutbox[Type, Status](
+		ctx,
+		workflowName,
+		processName,
+		recordStore,
+		stream,
+		clock,
+		lagAlert,
+		lookupLimit,
+		batchSize,
+	)
 	if err != nil {
 		return err
 	}
 
+	if n == 0 {
+		return poll.wait(ctx, false)
+	}
+
+	poll.reset()
+	return nil
+}
+
+// OutboxCounter can optionally be implemented by a RecordStore to report the number of events in the workflow's outbox
+// as the workflow_outbox_events metric. Without it the metric is capped at the number of events that are read from the
+// outbox at a time. CountOutboxEvents is only called when the outbox holds at least that many events.
+type OutboxCounter interface {
+	CountOutboxEvents(ctx context.Context, workflowName string) (int64, error)
+}
+
+// publishOutbox publishes up to lookupLimit events from the outbox and returns the number of events that were read
+// from the outbox.
+func publishOutbox[Type any, Status StatusType](
+	ctx context.Context,
+	workflowName string,
+	processName string,
+	recordStore RecordStore,
+	stream EventStreamer,
+	clock clock.Clock,
+	lagAlert time.Duration,
+	lookupLimit int64,
+	batchSize int,
+) (int, error) {
+	events, err := recordStore.ListOutboxEvents(ctx, workflowName, lookupLimit)
+	if err != nil {
+		return 0, err
+	}
+
+	depth := int64(len(events))
+	if depth >= lookupLimit {
+		// The outbox holds at least a full page of events and so its depth is counted, when supported, so that the
+		// metric is not capped at the lookup limit.
+		counter, ok := unwrapRecordStore(recordStore).(OutboxCounter)
+		if ok {
+			depth, err = counter.CountOutboxEvents(ctx, workflowName)
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	metrics.OutboxEvents.WithLabelValues(workflowName).Set(float64(depth))
+
 	if len(events) == 0 {
-		return wait(ctx, pollingFrequency)
+		metrics.OutboxOldestEventAge.WithLabelValues(workflowName).Set(0)
+
+		// An empty outbox means that the outbox consumer has caught up. The lag metric is left at the lag of the last
+		// event that was published.
+		fn, ok := ctx.Value(lagAlertContextKey{}).(lagAlertFunc)
+		if ok && lagAlert > 0 {
+			fn(ctx, LagAlertInfo{
+				WorkflowName: workflowName,
+				Process:      processName,
+				Threshold:    lagAlert,
+			})
+		}
+
+		return 0, nil
 	}
 
+	oldest := events[0].CreatedAt
+	for _, e := range events {
+		if e.CreatedAt.Before(oldest) {
+			oldest = e.CreatedAt
+		}
+	}
+
+	metrics.OutboxOldestEventAge.WithLabelValues(workflowName).Set(clock.Since(oldest).Seconds())
+
+	// Senders are reused for all the events of the same topic that were read from the outbox.
+	senders := make(map[string]EventSender)
+	defer func() {
+		for _, sender := range senders {
+			_ = sender.Close()
+		}
+	}()
+
+	var (
+		mu          sync.Mutex
+		delivered   []string
+		deliveryErr error
+
+		// batches holds the events of each topic that are yet to be sent by senders that implement
+		// BatchEventSender, in the order that the topics were first read from the outbox.
+		batches     = make(map[string]*outboxBatch)
+		batchTopics []string
+	)
+
 	// Send the events to the EventStreamer.
 	for _, e := range events {
 		var outboxRecord outboxpb.OutboxRecord
 		err := proto.Unmarshal(e.Data, &outboxRecord)
 		if err != nil {
-			return err
+			return 0, err
 		}
 
 		headers := make(map[Header]string)
@@ -122,28 +364,168 @@ func purgeOutbox[Type any, Status StatusType](
 		eventType := int(outboxRecord.Type)
 
 		// Push metrics and alerting around the age of the event being processed.
-		pushLagMetricAndAlerting(workflowName, processName, e.CreatedAt, lagAlert, clock)
+		pushLagMetricAndAlerting(ctx, workflowName, processName, e.CreatedAt, lagAlert, clock)
 
 		t0 := clock.Now()
 		topic := headers[HeaderTopic]
-		producer, err := stream.NewSender(ctx, topic)
-		if err != nil {
-			return err
+		producer, ok := senders[topic]
+		if !ok {
+			producer, err = stream.NewSender(ctx, topic)
+			if err != nil {
+				return 0, err
+			}
+
+			senders[topic] = producer
+		}
+
+		if batchSender, ok := producer.(BatchEventSender); ok {
+			batch, ok := batches[topic]
+			if !ok {
+				batch = &outboxBatch{sender: batchSender}
+				batches[topic] = batch
+				batchTopics = append(batchTopics, topic)
+			}
+
+			batch.add(e.ID, BatchEvent{
+				ForeignID: foreignID,
+				Type:      eventType,
+				Headers:   headers,
+			})
+
+			if len(batch.events) < batchSize {
+				continue
+			}
+
+			err = batch.send(ctx, workflowName, processName, recordStore, clock)
+			if err != nil {
+				return 0, err
+			}
+
+			continue
+		}
+
+		if async, ok := producer.(AsyncEventSender); ok {
+			id := e.ID
+			err = async.SendAsync(ctx, foreignID, eventType, headers, func(err error) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				if err != nil {
+					if deliveryErr == nil {
+						deliveryErr = err
+					}
+
+					return
+				}
+
+				delivered = append(delivered, id)
+
+				// Push the time it took to queue and deliver the event.
+				metrics.ProcessLatency.WithLabelValues(workflowName, processName).Observe(clock.Since(t0).Seconds())
+			})
+			if err != nil {
+				// The events that have already been queued are still flushed so that they are removed from the outbox.
+				mu.Lock()
+				deliveryErr = err
+				mu.Unlock()
+				break
+			}
+
+			continue
 		}
 
 		err = producer.Send(ctx, foreignID, eventType, headers)
 		if err != nil {
-			return err
+			return 0, err
 		}
 
 		err = recordStore.DeleteOutboxEvent(ctx, e.ID)
 		if err != nil {
-			return err
+			return 0, err
 		}
 
 		// Push the time it took to create the producer, send the event, and delete the outbox entry.
 		metrics.ProcessLatency.WithLabelValues(workflowName, processName).Observe(clock.Since(t0).Seconds())
 	}
 
+	for _, topic := range batchTopics {
+		err := batches[topic].send(ctx, workflowName, processName, recordStore, clock)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	for _, sender := range senders {
+		if _, ok := sender.(BatchEventSender); ok {
+			continue
+		}
+
+		async, ok := sender.(AsyncEventSender)
+		if !ok {
+			continue
+		}
+
+		err := async.Flush(ctx)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	// Only the events that were delivered are removed from the outbox and the rest are sent again in the next batch.
+	for _, id := range delivered {
+		err := recordStore.DeleteOutboxEvent(ctx, id)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(events), deliveryErr
+}
+
+// outboxBatch is the events of a topic that are sent together using a BatchEventSender.
+type outboxBatch struct {
+	sender BatchEventSender
+	ids    []string
+	events []BatchEvent
+}
+
+func (b *outboxBatch) add(id string, e BatchEvent) {
+	b.ids = append(b.ids, id)
+	b.events = append(b.events, e)
+}
+
+// send sends the events of the batch and removes them from the outbox once they have all been sent.
+func (b *outboxBatch) send(
+	ctx context.Context,
+	workflowName string,
+	processName string,
+	recordStore RecordStore,
+	clock clock.Clock,
+) error {
+	if len(b.events) == 0 {
+		return nil
+	}
+
+	t0 := clock.Now()
+	err := b.sender.SendBatch(ctx, b.events)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range b.ids {
+		err := recordStore.DeleteOutboxEvent(ctx, id)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Push the time it took to send the batch and delete its outbox entries.
+	metrics.ProcessLatency.WithLabelValues(workflowName, processName).Observe(clock.Since(t0).Seconds())
+
+	b.ids = nil
+	b.events = nil
 	return nil
 }
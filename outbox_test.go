@@ -0,0 +1,435 @@
+package workflow_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+	"github.com/luno/workflow/internal/metrics"
+)
+
+// asyncStreamer wraps an EventStreamer so that its senders implement workflow.AsyncEventSender.
+type asyncStreamer struct {
+	workflow.EventStreamer
+
+	mu sync.Mutex
+	// failFirst results in the first delivery of every event failing.
+	failFirst bool
+	attempts  map[string]int
+	flushes   int
+}
+
+func (s *asyncStreamer) NewSender(ctx context.Context, topic string) (workflow.EventSender, error) {
+	sender, err := s.EventStreamer.NewSender(ctx, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	return &asyncSender{EventSender: sender, streamer: s}, nil
+}
+
+type asyncSender struct {
+	workflow.EventSender
+
+	streamer *asyncStreamer
+	pending  []func()
+}
+
+func (s *asyncSender) SendAsync(
+	ctx context.Context,
+	foreignID string,
+	statusType int,
+	headers map[workflow.Header]string,
+	callback workflow.DeliveryCallback,
+) error {
+	s.pending = append(s.pending, func() {
+		s.streamer.mu.Lock()
+		key := strings.Join([]string{
+			headers[workflow.HeaderTopic],
+			foreignID,
+			strconv.Itoa(statusType),
+			headers[workflow.HeaderRunState],
+		}, "/")
+		s.streamer.attempts[key]++
+		fail := s.streamer.failFirst && s.streamer.attempts[key] == 1
+		s.streamer.mu.Unlock()
+
+		if fail {
+			callback(errors.New("delivery failed"))
+			return
+		}
+
+		callback(s.Send(ctx, foreignID, statusType, headers))
+	})
+
+	return nil
+}
+
+func (s *asyncSender) Flush(ctx context.Context) error {
+	for _, deliver := range s.pending {
+		deliver()
+	}
+
+	s.pending = nil
+
+	s.streamer.mu.Lock()
+	s.streamer.flushes++
+	s.streamer.mu.Unlock()
+
+	return nil
+}
+
+func TestOutbox_AsyncEventSender(t *testing.T) {
+	testCases := []struct {
+		name      string
+		failFirst bool
+	}{
+		{
+			name: "Sends events asynchronously",
+		},
+		{
+			name:      "Resends events that failed to be delivered",
+			failFirst: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			streamer := &asyncStreamer{
+				EventStreamer: memstreamer.New(),
+				failFirst:     tc.failFirst,
+				attempts:      make(map[string]int),
+			}
+
+			b := workflow.NewBuilder[string, status]("async outbox")
+			b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+				return StatusMiddle, nil
+			}, StatusMiddle)
+			b.AddStep(StatusMiddle, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+				return StatusEnd, nil
+			}, StatusEnd)
+
+			recordStore := memrecordstore.New()
+			wf := b.Build(
+				streamer,
+				recordStore,
+				memrolescheduler.New(),
+				workflow.WithOutboxPollingFrequency(10*time.Millisecond),
+				workflow.WithOutboxErrBackoff(10*time.Millisecond),
+			)
+
+			ctx := context.Background()
+			wf.Run(ctx)
+			t.Cleanup(wf.Stop)
+
+			runID, err := wf.Trigger(ctx, "foreignID", StatusStart)
+			require.Nil(t, err)
+
+			_, err = wf.Await(ctx, "foreignID", runID, StatusEnd, workflow.WithAwaitPollingFrequency(10*time.Millisecond))
+			require.Nil(t, err)
+
+			require.Eventually(t, func() bool {
+				events, err := recordStore.ListOutboxEvents(ctx, wf.Name(), 100)
+				require.Nil(t, err)
+				return len(events) == 0
+			}, time.Second, 10*time.Millisecond)
+
+			streamer.mu.Lock()
+			defer streamer.mu.Unlock()
+			require.NotZero(t, streamer.flushes)
+
+			for key, attempts := range streamer.attempts {
+				expected := 1
+				if tc.failFirst {
+					expected = 2
+				}
+
+				require.Equal(t, expected, attempts, key)
+			}
+		})
+	}
+}
+
+func TestDrainOutbox(t *testing.T) {
+	metrics.OutboxOldestEventAge.Reset()
+	t.Cleanup(metrics.OutboxOldestEventAge.Reset)
+
+	b := workflow.NewBuilder[string, status]("drain outbox")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	var (
+		mu    sync.Mutex
+		infos []workflow.LagAlertInfo
+	)
+
+	clock := newClock()
+	streamer := memstreamer.New(memstreamer.WithClock(clock))
+	recordStore := memrecordstore.New(memrecordstore.WithClock(clock))
+	wf := b.Build(
+		streamer,
+		recordStore,
+		memrolescheduler.New(),
+		workflow.WithClock(clock),
+		workflow.WithOutboxLagAlert(time.Minute),
+		workflow.WithOutboxLagAlertHandler(func(ctx context.Context, info workflow.LagAlertInfo) {
+			mu.Lock()
+			defer mu.Unlock()
+			infos = append(infos, info)
+		}),
+	)
+
+	ctx := context.Background()
+
+	var s string
+	payload, err := workflow.Marshal(&s)
+	require.Nil(t, err)
+
+	err = update(ctx, recordStore, &workflow.Record{
+		WorkflowName: wf.Name(),
+		ForeignID:    "foreignID",
+		RunID:        "runID",
+		Status:       int(StatusStart),
+		RunState:     workflow.RunStateInitiated,
+		Object:       payload,
+		CreatedAt:    clock.Now(),
+	})
+	require.Nil(t, err)
+
+	clock.Step(time.Hour)
+
+	receiver, err := streamer.NewReceiver(ctx, workflow.Topic(wf.Name(), int(StatusStart)), "test")
+	require.Nil(t, err)
+	t.Cleanup(func() { _ = receiver.Close() })
+
+	// The workflow is not running and so the outbox is only drained by DrainOutbox.
+	err = wf.DrainOutbox(ctx)
+	require.Nil(t, err)
+
+	events, err := recordStore.ListOutboxEvents(ctx, wf.Name(), 100)
+	require.Nil(t, err)
+	require.Empty(t, events)
+
+	recvCtx, cancel := context.WithTimeout(ctx, time.Second)
+	t.Cleanup(cancel)
+
+	e, _, err := receiver.Recv(recvCtx)
+	require.Nil(t, err)
+	require.Equal(t, "runID", e.ForeignID)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, []workflow.LagAlertInfo{
+		{
+			WorkflowName: wf.Name(),
+			Process:      "outbox-consumer",
+			Lag:          time.Hour,
+			Threshold:    time.Minute,
+			Alerting:     true,
+		},
+		{
+			WorkflowName: wf.Name(),
+			Process:      "outbox-consumer",
+			Threshold:    time.Minute,
+		},
+	}, infos)
+
+	require.Equal(t, 0.0, testutil.ToFloat64(metrics.OutboxOldestEventAge.WithLabelValues(wf.Name())))
+}
+
+// batchStreamer wraps an EventStreamer so that its senders implement workflow.BatchEventSender.
+type batchStreamer struct {
+	workflow.EventStreamer
+
+	mu      sync.Mutex
+	senders map[string]int
+	batches map[string][]int
+}
+
+func (s *batchStreamer) NewSender(ctx context.Context, topic string) (workflow.EventSender, error) {
+	sender, err := s.EventStreamer.NewSender(ctx, topic)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.senders[topic]++
+	s.mu.Unlock()
+
+	return &batchSender{EventSender: sender, streamer: s, topic: topic}, nil
+}
+
+type batchSender struct {
+	workflow.EventSender
+
+	streamer *batchStreamer
+	topic    string
+}
+
+func (s *batchSender) SendBatch(ctx context.Context, events []workflow.BatchEvent) error {
+	s.streamer.mu.Lock()
+	s.streamer.batches[s.topic] = append(s.streamer.batches[s.topic], len(events))
+	s.streamer.mu.Unlock()
+
+	for _, e := range events {
+		err := s.Send(ctx, e.ForeignID, e.Type, e.Headers)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func TestOutbox_BatchEventSender(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("batch outbox")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	streamer := &batchStreamer{
+		EventStreamer: memstreamer.New(),
+		senders:       make(map[string]int),
+		batches:       make(map[string][]int),
+	}
+	recordStore := memrecordstore.New()
+	wf := b.Build(
+		streamer,
+		recordStore,
+		memrolescheduler.New(),
+		workflow.WithOutboxBatchSize(2),
+	)
+
+	ctx := context.Background()
+
+	var s string
+	payload, err := workflow.Marshal(&s)
+	require.Nil(t, err)
+
+	for i := range 5 {
+		err = update(ctx, recordStore, &workflow.Record{
+			WorkflowName: wf.Name(),
+			ForeignID:    "foreignID-" + strconv.Itoa(i),
+			RunID:        "runID-" + strconv.Itoa(i),
+			Status:       int(StatusStart),
+			RunState:     workflow.RunStateInitiated,
+			Object:       payload,
+		})
+		require.Nil(t, err)
+	}
+
+	err = wf.DrainOutbox(ctx)
+	require.Nil(t, err)
+
+	events, err := recordStore.ListOutboxEvents(ctx, wf.Name(), 100)
+	require.Nil(t, err)
+	require.Empty(t, events)
+
+	streamer.mu.Lock()
+	defer streamer.mu.Unlock()
+
+	topic := workflow.Topic(wf.Name(), int(StatusStart))
+	require.Equal(t, 1, streamer.senders[topic])
+	require.Equal(t, []int{2, 2, 1}, streamer.batches[topic])
+}
+
+func TestWithOutboxBatchSize_Invalid(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("invalid batch size")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	require.PanicsWithValue(t, "outbox batch size needs to be positive", func() {
+		b.Build(memstreamer.New(), memrecordstore.New(), memrolescheduler.New(), workflow.WithOutboxBatchSize(0))
+	})
+}
+
+// undeletableOutbox fails to delete the events of its outbox so that the outbox is not drained.
+type undeletableOutbox struct {
+	workflow.RecordStore
+	store *memrecordstore.Store
+}
+
+func (s *undeletableOutbox) DeleteOutboxEvent(ctx context.Context, id string) error {
+	return errors.New("delete failed")
+}
+
+func (s *undeletableOutbox) CountOutboxEvents(ctx context.Context, workflowName string) (int64, error) {
+	return s.store.CountOutboxEvents(ctx, workflowName)
+}
+
+// uncountedOutbox hides the OutboxCounter implementation of the RecordStore.
+type uncountedOutbox struct {
+	workflow.RecordStore
+}
+
+func TestOutboxEventsMetric(t *testing.T) {
+	testCases := []struct {
+		name     string
+		store    func(s *memrecordstore.Store) workflow.RecordStore
+		expected float64
+	}{
+		{
+			name: "Counts the outbox when the RecordStore implements OutboxCounter",
+			store: func(s *memrecordstore.Store) workflow.RecordStore {
+				return &undeletableOutbox{RecordStore: s, store: s}
+			},
+			expected: 5,
+		},
+		{
+			name: "Capped at the lookup limit otherwise",
+			store: func(s *memrecordstore.Store) workflow.RecordStore {
+				return &uncountedOutbox{RecordStore: &undeletableOutbox{RecordStore: s, store: s}}
+			},
+			expected: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			metrics.OutboxEvents.Reset()
+			t.Cleanup(metrics.OutboxEvents.Reset)
+
+			b := workflow.NewBuilder[string, status]("outbox events")
+			b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+				return StatusEnd, nil
+			}, StatusEnd)
+
+			memStore := memrecordstore.New()
+			wf := b.Build(
+				memstreamer.New(),
+				tc.store(memStore),
+				memrolescheduler.New(),
+				workflow.WithOutboxLookupLimit(2),
+			)
+
+			ctx := context.Background()
+			for i := range 5 {
+				err := memStore.Store(ctx, &workflow.Record{
+					WorkflowName: wf.Name(),
+					ForeignID:    strconv.Itoa(i),
+					RunID:        strconv.Itoa(i),
+					Status:       int(StatusStart),
+					RunState:     workflow.RunStateInitiated,
+				})
+				require.Nil(t, err)
+			}
+
+			err := wf.DrainOutbox(ctx)
+			require.ErrorContains(t, err, "delete failed")
+			require.Equal(t, tc.expected, testutil.ToFloat64(metrics.OutboxEvents.WithLabelValues(wf.Name())))
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+var errNoTimeoutStore = errors.New("no TimeoutStore configured for workflow")
+
+// PendingTimeout is a timeout that has been scheduled for a run and has not yet been processed. A pending timeout
+// whose ExpireAt has passed is waiting to be processed by the status's timeout consumer.
+type PendingTimeout[Status StatusType] struct {
+	ID        int64
+	ForeignID string
+	RunID     string
+	// Status is the status of the run that the timeout was scheduled for and that the timeout moves the run on from.
+	Status    Status
+	ExpireAt  time.Time
+	CreatedAt time.Time
+	// Name is the name of the timer, added using AddTimer, that the timeout was scheduled for. It is empty for the
+	// timeouts of AddTimeout.
+	Name string
+}
+
+// PendingTimeouts returns the timeouts that are scheduled for the run, in the order that they expire, so that
+// operators can find out when a run will time out.
+func (w *Workflow[Type, Status]) PendingTimeouts(ctx context.Context, runID string) ([]PendingTimeout[Status], error) {
+	timeouts, err := w.pendingTimeouts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []PendingTimeout[Status]
+	for _, t := range timeouts {
+		if t.RunID != runID {
+			continue
+		}
+
+		pending = append(pending, PendingTimeout[Status]{
+			ID:        t.ID,
+			ForeignID: t.ForeignID,
+			RunID:     t.RunID,
+			Status:    Status(t.Status),
+			ExpireAt:  t.ExpireAt,
+			CreatedAt: t.CreatedAt,
+			Name:      t.Name,
+		})
+	}
+
+	sort.SliceStable(pending, func(i, j int) bool {
+		return pending[i].ExpireAt.Before(pending[j].ExpireAt)
+	})
+
+	return pending, nil
+}
+
+// PendingTimeoutCounts returns the number of timeouts that are scheduled for the workflow's runs per status.
+func (w *Workflow[Type, Status]) PendingTimeoutCounts(ctx context.Context) (map[Status]int, error) {
+	timeouts, err := w.pendingTimeouts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[Status]int)
+	for _, t := range timeouts {
+		counts[Status(t.Status)]++
+	}
+
+	return counts, nil
+}
+
+func (w *Workflow[Type, Status]) pendingTimeouts(ctx context.Context) ([]TimeoutRecord, error) {
+	if w.timeoutStore == nil {
+		return nil, fmt.Errorf("list pending timeouts failed: %w", errNoTimeoutStore)
+	}
+
+	timeouts, err := w.timeoutStore.List(ctx, w.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []TimeoutRecord
+	for _, t := range timeouts {
+		// Not every TimeoutStore excludes the timeouts that have been completed.
+		if t.Completed {
+			continue
+		}
+
+		pending = append(pending, t)
+	}
+
+	return pending, nil
+}
@@ -0,0 +1,65 @@
+package workflow
+
+import (
+	"context"
+	"time"
+)
+
+// AdaptivePolling results in the polling interval of the process backing off, by doubling, from its PollingFrequency
+// towards max each time the process polls without finding any work and snapping back to the PollingFrequency as soon
+// as work is found. This reduces the load of idle workflows without sacrificing latency once the workflow is active.
+//
+// Step consumers provide max to the EventStreamer using WithReceiverMaxPollFrequency and so the backing off of
+// step consumers is dependent on the EventStreamer supporting it. Timeout pollers back off when no timeouts have
+// expired.
+func AdaptivePolling(max time.Duration) Option {
+	return func(opt *options) {
+		opt.maxPollingFrequency = max
+	}
+}
+
+// WithOutboxAdaptivePolling results in the outbox consumer's polling interval backing off, by doubling, from the
+// outbox polling frequency towards max while the outbox is empty and snapping back once events are found.
+func WithOutboxAdaptivePolling(max time.Duration) BuildOption {
+	return func(bo *buildOptions) {
+		bo.outboxConfig.maxPollingFrequency = max
+	}
+}
+
+// pollInterval tracks the interval between polls that backs off from min towards max while idle. Adaptive polling
+// is disabled when max is not greater than min.
+type pollInterval struct {
+	min     time.Duration
+	max     time.Duration
+	current time.Duration
+}
+
+func newPollInterval(min, max time.Duration) *pollInterval {
+	return &pollInterval{
+		min:     min,
+		max:     max,
+		current: min,
+	}
+}
+
+// reset snaps the interval back to min once work has been found.
+func (p *pollInterval) reset() {
+	p.current = p.min
+}
+
+// next returns the interval to wait before polling again based on whether the last poll found work.
+func (p *pollInterval) next(active bool) time.Duration {
+	if active || p.max <= p.min {
+		p.reset()
+		return p.current
+	}
+
+	interval := p.current
+	p.current = min(max(p.current*2, time.Millisecond), p.max)
+	return interval
+}
+
+// wait blocks for the next interval based on whether the last poll found work.
+func (p *pollInterval) wait(ctx context.Context, active bool) error {
+	return wait(ctx, p.next(active))
+}
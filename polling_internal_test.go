@@ -0,0 +1,52 @@
+package workflow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPollInterval(t *testing.T) {
+	testCases := []struct {
+		name     string
+		min      time.Duration
+		max      time.Duration
+		active   []bool
+		expected []time.Duration
+	}{
+		{
+			name:     "Backs off while idle",
+			min:      100 * time.Millisecond,
+			max:      time.Second,
+			active:   []bool{false, false, false, false, false, false},
+			expected: []time.Duration{100, 200, 400, 800, 1000, 1000},
+		},
+		{
+			name:     "Snaps back on activity",
+			min:      100 * time.Millisecond,
+			max:      time.Second,
+			active:   []bool{false, false, false, true, false},
+			expected: []time.Duration{100, 200, 400, 100, 100},
+		},
+		{
+			name:     "Disabled without max",
+			min:      100 * time.Millisecond,
+			active:   []bool{false, false, false},
+			expected: []time.Duration{100, 100, 100},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := newPollInterval(tc.min, tc.max)
+
+			var actual []time.Duration
+			for _, active := range tc.active {
+				actual = append(actual, p.next(active)/time.Millisecond)
+			}
+
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
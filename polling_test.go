@@ -0,0 +1,90 @@
+package workflow_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+// receiverOptionsStreamer records the ReceiverOptions that each receiver is created with.
+type receiverOptionsStreamer struct {
+	workflow.EventStreamer
+
+	mu      sync.Mutex
+	options map[string]workflow.ReceiverOptions
+}
+
+func (s *receiverOptionsStreamer) NewReceiver(
+	ctx context.Context,
+	topic string,
+	name string,
+	opts ...workflow.ReceiverOption,
+) (workflow.EventReceiver, error) {
+	var options workflow.ReceiverOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	s.mu.Lock()
+	s.options[name] = options
+	s.mu.Unlock()
+
+	return s.EventStreamer.NewReceiver(ctx, topic, name, opts...)
+}
+
+func TestAdaptivePolling(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("adaptive polling")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return StatusMiddle, nil
+	}, StatusMiddle).WithOptions(
+		workflow.PollingFrequency(10*time.Millisecond),
+		workflow.AdaptivePolling(time.Second),
+	)
+	b.AddStep(StatusMiddle, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	streamer := &receiverOptionsStreamer{
+		EventStreamer: memstreamer.New(),
+		options:       make(map[string]workflow.ReceiverOptions),
+	}
+	wf := b.Build(
+		streamer,
+		memrecordstore.New(),
+		memrolescheduler.New(),
+		workflow.WithOutboxPollingFrequency(10*time.Millisecond),
+		workflow.WithOutboxAdaptivePolling(time.Second),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	// Leave the workflow idle so that the pollers back off before triggering a run.
+	time.Sleep(200 * time.Millisecond)
+
+	runID, err := wf.Trigger(ctx, "foreignID", StatusStart)
+	require.Nil(t, err)
+
+	_, err = wf.Await(ctx, "foreignID", runID, StatusEnd, workflow.WithAwaitPollingFrequency(10*time.Millisecond))
+	require.Nil(t, err)
+
+	streamer.mu.Lock()
+	defer streamer.mu.Unlock()
+
+	require.Equal(t, workflow.ReceiverOptions{
+		PollFrequency:    10 * time.Millisecond,
+		MaxPollFrequency: time.Second,
+	}, streamer.options["adaptive_polling-9-consumer-1-of-1"])
+
+	// Consumers without AdaptivePolling keep polling at their PollingFrequency.
+	require.Zero(t, streamer.options["adaptive_polling-10-consumer-1-of-1"].MaxPollFrequency)
+}
@@ -0,0 +1,130 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const defaultPreflightTimeout = 10 * time.Second
+
+// Preflighter can optionally be implemented by any adapter (EventStreamer, RecordStore, TimeoutStore, RoleScheduler,
+// etc.) to verify adapter specific requirements such as lease TTLs, topic configuration, or schema versions when
+// Workflow.Preflight is called.
+type Preflighter interface {
+	Preflight(ctx context.Context) error
+}
+
+// WithPreflight results in Preflight being called at the start of Run. If any of the checks fail then Run will panic
+// with the error instead of starting the workflow's consumers in a degraded state.
+func WithPreflight() BuildOption {
+	return func(bo *buildOptions) {
+		bo.preflight = true
+	}
+}
+
+// Preflight verifies the connectivity and required capabilities of the workflow's adapters. All checks are run and
+// any failures are returned together as a single error. Preflight does not require Run to have been called and can
+// be used as a readiness check.
+func (w *Workflow[Type, Status]) Preflight(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultPreflightTimeout)
+	defer cancel()
+
+	var errs []error
+	check := func(adapter string, err error) {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("preflight %s: %w", adapter, err))
+		}
+	}
+
+	check("event streamer", w.preflightEventStreamer(ctx))
+	check("record store", w.preflightRecordStore(ctx))
+	check("role scheduler", w.preflightRoleScheduler(ctx))
+
+	if len(w.timeouts) > 0 || len(w.timers) > 0 || w.timeoutStore != nil {
+		check("timeout store", w.preflightTimeoutStore(ctx))
+	}
+
+	if w.legalHoldStore != nil {
+		_, err := w.legalHoldStore.List(ctx, w.Name(), "")
+		check("legal hold store", err)
+		check("legal hold store", runPreflighter(ctx, w.legalHoldStore))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (w *Workflow[Type, Status]) preflightEventStreamer(ctx context.Context) error {
+	if w.eventStreamer == nil {
+		return errors.New("no EventStreamer provided to Build")
+	}
+
+	sender, err := w.eventStreamer.NewSender(ctx, RunStateChangeTopic(w.Name()))
+	if err != nil {
+		return fmt.Errorf("unable to create sender: %w", err)
+	}
+
+	err = sender.Close()
+	if err != nil {
+		return fmt.Errorf("unable to close sender: %w", err)
+	}
+
+	return runPreflighter(ctx, w.eventStreamer)
+}
+
+func (w *Workflow[Type, Status]) preflightRecordStore(ctx context.Context) error {
+	if w.recordStore == nil {
+		return errors.New("no RecordStore provided to Build")
+	}
+
+	_, err := w.recordStore.Latest(ctx, w.Name(), "workflow-preflight")
+	if err != nil && !errors.Is(err, ErrRecordNotFound) {
+		return fmt.Errorf("unable to lookup records: %w", err)
+	}
+
+	_, err = w.recordStore.ListOutboxEvents(ctx, w.Name(), 1)
+	if err != nil {
+		return fmt.Errorf("unable to list outbox events, ensure the store supports the transactional outbox: %w", err)
+	}
+
+	return runPreflighter(ctx, unwrapRecordStore(w.recordStore))
+}
+
+func (w *Workflow[Type, Status]) preflightRoleScheduler(ctx context.Context) error {
+	if w.scheduler == nil {
+		return errors.New("no RoleScheduler provided to Build")
+	}
+
+	_, cancel, err := w.scheduler.Await(ctx, makeRole(w.Name(), "preflight"))
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("role was not assigned within %v, ensure roles are being released: %w", defaultPreflightTimeout, err)
+	} else if err != nil {
+		return fmt.Errorf("unable to await role: %w", err)
+	}
+	cancel()
+
+	return runPreflighter(ctx, w.scheduler)
+}
+
+func (w *Workflow[Type, Status]) preflightTimeoutStore(ctx context.Context) error {
+	if w.timeoutStore == nil {
+		return errors.New("timeouts are configured but no TimeoutStore provided, use WithTimeoutStore")
+	}
+
+	_, err := w.timeoutStore.List(ctx, w.Name())
+	if err != nil {
+		return fmt.Errorf("unable to list timeouts: %w", err)
+	}
+
+	return runPreflighter(ctx, w.timeoutStore)
+}
+
+func runPreflighter(ctx context.Context, adapter any) error {
+	p, ok := adapter.(Preflighter)
+	if !ok {
+		return nil
+	}
+
+	return p.Preflight(ctx)
+}
@@ -0,0 +1,97 @@
+package workflow_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+	"github.com/luno/workflow/adapters/memtimeoutstore"
+)
+
+type leaseScheduler struct {
+	workflow.RoleScheduler
+	err error
+}
+
+func (s *leaseScheduler) Preflight(ctx context.Context) error {
+	return s.err
+}
+
+func preflightBuilder() *workflow.Builder[string, status] {
+	b := workflow.NewBuilder[string, status]("preflight")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+	b.AddTimeout(
+		StatusMiddle,
+		workflow.DurationTimerFunc[string, status](time.Hour),
+		func(ctx context.Context, r *workflow.Run[string, status], now time.Time) (status, error) {
+			return StatusEnd, nil
+		},
+		StatusEnd,
+	)
+	return b
+}
+
+func TestPreflight(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Passes with in-memory adapters", func(t *testing.T) {
+		wf := preflightBuilder().Build(
+			memstreamer.New(),
+			memrecordstore.New(),
+			memrolescheduler.New(),
+			workflow.WithTimeoutStore(memtimeoutstore.New()),
+		)
+
+		require.Nil(t, wf.Preflight(ctx))
+	})
+
+	t.Run("Reports all missing adapters", func(t *testing.T) {
+		b := workflow.NewBuilder[string, status]("preflight")
+		b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+			return StatusEnd, nil
+		}, StatusEnd)
+		wf := b.Build(nil, nil, nil)
+
+		err := wf.Preflight(ctx)
+		require.NotNil(t, err)
+		require.Contains(t, err.Error(), "preflight event streamer: no EventStreamer provided to Build")
+		require.Contains(t, err.Error(), "preflight record store: no RecordStore provided to Build")
+		require.Contains(t, err.Error(), "preflight role scheduler: no RoleScheduler provided to Build")
+	})
+
+	t.Run("Runs adapter specific checks", func(t *testing.T) {
+		leaseErr := errors.New("lease ttl shorter than polling frequency")
+		wf := preflightBuilder().Build(
+			memstreamer.New(),
+			memrecordstore.New(),
+			&leaseScheduler{RoleScheduler: memrolescheduler.New(), err: leaseErr},
+			workflow.WithTimeoutStore(memtimeoutstore.New()),
+		)
+
+		err := wf.Preflight(ctx)
+		require.True(t, errors.Is(err, leaseErr))
+	})
+
+	t.Run("Run panics when preflight fails", func(t *testing.T) {
+		wf := preflightBuilder().Build(
+			memstreamer.New(),
+			memrecordstore.New(),
+			&leaseScheduler{RoleScheduler: memrolescheduler.New(), err: errors.New("lease ttl too short")},
+			workflow.WithTimeoutStore(memtimeoutstore.New()),
+			workflow.WithPreflight(),
+		)
+
+		require.Panics(t, func() {
+			wf.Run(ctx)
+		})
+	})
+}
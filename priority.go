@@ -0,0 +1,236 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ErrInvalidPriority is returned by Trigger when the priority provided using WithPriority is not one of the priority
+// lanes configured using WithPriorityLanes.
+var ErrInvalidPriority = errors.New("priority lane not configured for workflow")
+
+// WithPriorityLanes adds the provided number of priority lanes, in addition to the default lane, to every status of
+// the workflow. Runs triggered using WithPriority are published to the lane of their priority, for their entire
+// lifetime, and the consumers of each status always consume the highest priority lane that has events before the
+// lower priority lanes. This allows urgent runs to jump ahead of bulk traffic on the same status without a separate
+// workflow.
+//
+// Each lane is consumed in order, and acknowledged independently, and so the events of a lane are only consumed once
+// the previous event of the same lane has been acknowledged. Priority lanes are not supported by AddBatchStep.
+func WithPriorityLanes(lanes int) BuildOption {
+	return func(bo *buildOptions) {
+		bo.priorityLanes = lanes
+	}
+}
+
+// WithPriority sets the priority lane of the run. Priority 0 is the default lane and the highest priority is the
+// number of lanes configured using WithPriorityLanes.
+func WithPriority[Type any, Status StatusType](priority int) TriggerOption[Type, Status] {
+	return func(o *triggerOpts[Type, Status]) {
+		o.priority = priority
+	}
+}
+
+func validatePriority(priority, lanes int) error {
+	if priority < 0 || priority > lanes {
+		return fmt.Errorf("trigger failed: %w, meta: %v", ErrInvalidPriority, map[string]string{
+			"priority": strconv.Itoa(priority),
+			"lanes":    strconv.Itoa(lanes),
+		})
+	}
+
+	return nil
+}
+
+// PriorityTopic is the topic of the priority lane of the status topic. The default lane, priority 0, is the status
+// topic itself.
+func PriorityTopic(topic string, priority int) string {
+	if priority == 0 {
+		return topic
+	}
+
+	return topic + topicSeparator + "p" + strconv.Itoa(priority)
+}
+
+// newStatusReceiver returns an EventReceiver of the status that consumes all the priority lanes of the status,
+// highest priority first.
+func (w *Workflow[Type, Status]) newStatusReceiver(
+	ctx context.Context,
+	status Status,
+	name string,
+	opts ...ReceiverOption,
+) (EventReceiver, error) {
+	topic := w.topic(status)
+	if w.priorityLanes == 0 {
+		return w.eventStreamer.NewReceiver(ctx, topic, name, opts...)
+	}
+
+	var lanes []EventReceiver
+	for priority := w.priorityLanes; priority >= 0; priority-- {
+		receiver, err := w.eventStreamer.NewReceiver(
+			ctx,
+			PriorityTopic(topic, priority),
+			laneReceiverName(name, priority),
+			opts...,
+		)
+		if err != nil {
+			for _, lane := range lanes {
+				_ = lane.Close()
+			}
+
+			return nil, err
+		}
+
+		lanes = append(lanes, receiver)
+	}
+
+	return newPriorityReceiver(lanes), nil
+}
+
+// laneReceiverName is the name of the receiver of the priority lane. The default lane, priority 0, uses the name of
+// the status receiver.
+func laneReceiverName(name string, priority int) string {
+	if priority == 0 {
+		return name
+	}
+
+	return makeRole(name, "p"+strconv.Itoa(priority))
+}
+
+type laneEvent struct {
+	event *Event
+	ack   Ack
+	err   error
+}
+
+type lane struct {
+	receiver EventReceiver
+	events   chan laneEvent
+	acked    chan struct{}
+}
+
+// priorityReceiver receives from each lane concurrently and provides the event of the highest priority lane that has
+// an event ready.
+type priorityReceiver struct {
+	lanes  []*lane
+	ready  chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// newPriorityReceiver expects the lanes to be ordered from the highest priority to the lowest.
+func newPriorityReceiver(receivers []EventReceiver) *priorityReceiver {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &priorityReceiver{
+		ready:  make(chan struct{}, len(receivers)),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+
+	for _, receiver := range receivers {
+		l := &lane{
+			receiver: receiver,
+			events:   make(chan laneEvent, 1),
+			acked:    make(chan struct{}, 1),
+		}
+
+		r.lanes = append(r.lanes, l)
+		go r.receive(ctx, l)
+	}
+
+	return r
+}
+
+func (r *priorityReceiver) receive(ctx context.Context, l *lane) {
+	for {
+		e, ack, err := l.receiver.Recv(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case l.events <- laneEvent{event: e, ack: ack, err: err}:
+		}
+
+		select {
+		case r.ready <- struct{}{}:
+		default:
+		}
+
+		if err != nil {
+			return
+		}
+
+		// The next event of the lane is only received once the current event has been acknowledged so that the
+		// events of the lane are consumed in order.
+		select {
+		case <-ctx.Done():
+			return
+		case <-l.acked:
+		}
+	}
+}
+
+func (r *priorityReceiver) Recv(ctx context.Context) (*Event, Ack, error) {
+	for {
+		for _, l := range r.lanes {
+			select {
+			case le := <-l.events:
+				if le.err != nil {
+					return nil, nil, le.err
+				}
+
+				return le.event, func() error {
+					err := le.ack()
+					if err != nil {
+						return err
+					}
+
+					select {
+					case l.acked <- struct{}{}:
+					default:
+					}
+
+					return nil
+				}, nil
+			default:
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-r.ctx.Done():
+			// The receiver has been closed.
+			return nil, nil, r.ctx.Err()
+		case <-r.ready:
+		}
+	}
+}
+
+// Sharded returns true when every lane only receives the events of its shard.
+func (r *priorityReceiver) Sharded() bool {
+	for _, l := range r.lanes {
+		if !isSharded(l.receiver) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (r *priorityReceiver) Close() error {
+	r.cancel()
+
+	var firstErr error
+	for _, l := range r.lanes {
+		err := l.receiver.Close()
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+var _ ShardedReceiver = (*priorityReceiver)(nil)
@@ -0,0 +1,109 @@
+package workflow
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/luno/workflow/internal/outboxpb"
+)
+
+func TestPriorityTopic(t *testing.T) {
+	require.Equal(t, "example-1", PriorityTopic("example-1", 0))
+	require.Equal(t, "example-1-p2", PriorityTopic("example-1", 2))
+}
+
+func TestValidatePriority(t *testing.T) {
+	require.Nil(t, validatePriority(0, 0))
+	require.Nil(t, validatePriority(2, 2))
+	require.ErrorIs(t, validatePriority(1, 0), ErrInvalidPriority)
+	require.ErrorIs(t, validatePriority(-1, 2), ErrInvalidPriority)
+}
+
+func TestMakeOutboxEventData_Priority(t *testing.T) {
+	data, err := MakeOutboxEventData(Record{
+		WorkflowName: "example",
+		ForeignID:    "andrew",
+		RunID:        "run-id",
+		RunState:     RunStateRunning,
+		Status:       int(statusMiddle),
+		Meta: RecordMeta{
+			Priority: 1,
+		},
+	})
+	require.Nil(t, err)
+
+	var r outboxpb.OutboxRecord
+	err = proto.Unmarshal(data.Data, &r)
+	require.Nil(t, err)
+	require.Equal(t, "example-2-p1", r.Headers[string(HeaderTopic)])
+}
+
+// laneReceiver provides the events that are written to its channel.
+type laneReceiver struct {
+	events chan *Event
+	closed bool
+}
+
+func (r *laneReceiver) Recv(ctx context.Context) (*Event, Ack, error) {
+	select {
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	case e := <-r.events:
+		return e, func() error { return nil }, nil
+	}
+}
+
+func (r *laneReceiver) Close() error {
+	r.closed = true
+	return nil
+}
+
+func Test_priorityReceiver(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	t.Cleanup(cancel)
+
+	high := &laneReceiver{events: make(chan *Event, 10)}
+	low := &laneReceiver{events: make(chan *Event, 10)}
+
+	low.events <- &Event{ID: 1}
+	low.events <- &Event{ID: 2}
+
+	receiver := newPriorityReceiver([]EventReceiver{high, low})
+
+	e, ack, err := receiver.Recv(ctx)
+	require.Nil(t, err)
+	require.Equal(t, int64(1), e.ID)
+
+	// The high priority event must be received before the remaining low priority event.
+	high.events <- &Event{ID: 3}
+	require.Eventually(t, func() bool {
+		return len(high.events) == 0
+	}, time.Second, time.Millisecond)
+	require.Nil(t, ack())
+
+	// Wait for the low priority lane to receive its next event.
+	require.Eventually(t, func() bool {
+		return len(low.events) == 0
+	}, time.Second, time.Millisecond)
+
+	e, ack, err = receiver.Recv(ctx)
+	require.Nil(t, err)
+	require.Equal(t, int64(3), e.ID)
+	require.Nil(t, ack())
+
+	e, ack, err = receiver.Recv(ctx)
+	require.Nil(t, err)
+	require.Equal(t, int64(2), e.ID)
+	require.Nil(t, ack())
+
+	require.Nil(t, receiver.Close())
+	require.True(t, high.closed)
+	require.True(t, low.closed)
+
+	_, _, err = receiver.Recv(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,93 @@
+package workflow_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestPriorityLanes(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		processed []string
+	)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	b := workflow.NewBuilder[string, status]("priority")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		if r.ForeignID == "bulk-0" {
+			// Hold the first run until the urgent run has been published.
+			close(started)
+			<-release
+		}
+
+		mu.Lock()
+		processed = append(processed, r.ForeignID)
+		mu.Unlock()
+
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	recordStore := memrecordstore.New()
+	wf := b.Build(
+		memstreamer.New(),
+		recordStore,
+		memrolescheduler.New(),
+		workflow.WithPriorityLanes(1),
+		workflow.WithOutboxPollingFrequency(10*time.Millisecond),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	_, err := wf.Trigger(ctx, "bulk-0", StatusStart)
+	require.Nil(t, err)
+
+	<-started
+
+	for i := 1; i <= 3; i++ {
+		_, err := wf.Trigger(ctx, "bulk-"+strconv.Itoa(i), StatusStart)
+		require.Nil(t, err)
+	}
+
+	urgentRunID, err := wf.Trigger(ctx, "urgent", StatusStart, workflow.WithPriority[string, status](1))
+	require.Nil(t, err)
+
+	require.Eventually(t, func() bool {
+		events, err := recordStore.ListOutboxEvents(ctx, wf.Name(), 100)
+		require.Nil(t, err)
+		return len(events) == 0
+	}, time.Second, 10*time.Millisecond)
+
+	// Allow the receivers of each lane to poll the published events before the first run is released.
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+
+	run, err := wf.Await(ctx, "urgent", urgentRunID, StatusEnd, workflow.WithAwaitPollingFrequency(10*time.Millisecond))
+	require.Nil(t, err)
+	require.Equal(t, 1, run.Meta.Priority)
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(processed) == 5
+	}, 5*time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Equal(t, []string{"bulk-0", "urgent", "bulk-1", "bulk-2", "bulk-3"}, processed)
+
+	_, err = wf.Trigger(ctx, "too-urgent", StatusStart, workflow.WithPriority[string, status](2))
+	require.ErrorIs(t, err, workflow.ErrInvalidPriority)
+}
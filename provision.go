@@ -0,0 +1,118 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TopicConfig describes a topic that the workflow produces events to and consumes events from.
+type TopicConfig struct {
+	Name string
+	// Partitions is the highest ParallelCount of the consumers of the topic and is always at least 1.
+	Partitions int
+	// Retention is configured using WithTopicRetention and is zero when the adapter's default should be used.
+	Retention time.Duration
+}
+
+// TopicProvisioner can optionally be implemented by an EventStreamer to create the topics, or queues, of a workflow
+// with the right configuration before they are used. ProvisionTopics is called with all the topics of the workflow
+// when Run is called and must not fail, nor modify the topic, when a topic already exists.
+type TopicProvisioner interface {
+	ProvisionTopics(ctx context.Context, topics []TopicConfig) error
+}
+
+// WithTopicRetention sets the Retention of the workflow's topics that are provided to the TopicProvisioner.
+func WithTopicRetention(d time.Duration) BuildOption {
+	return func(bo *buildOptions) {
+		bo.topicRetention = d
+	}
+}
+
+// Topics returns the configuration of every topic that the workflow produces events to, ordered by status with the
+// run state change and delete topics last.
+func (w *Workflow[Type, Status]) Topics() []TopicConfig {
+	var topics []TopicConfig
+	for _, status := range w.statusGraph.Nodes() {
+		parallelCount := w.defaultOpts.parallelCount
+		if config, ok := w.consumers[Status(status)]; ok && config.parallelCount != 0 {
+			parallelCount = config.parallelCount
+		}
+
+		partitions := max(parallelCount, 1)
+
+		for priority := 0; priority <= w.priorityLanes; priority++ {
+			topics = append(topics, TopicConfig{
+				Name:       PriorityTopic(w.topic(Status(status)), priority),
+				Partitions: partitions,
+				Retention:  w.topicRetention,
+			})
+		}
+	}
+
+	topics = append(topics,
+		TopicConfig{
+			Name:       RunStateChangeTopic(w.Name()),
+			Partitions: 1,
+			Retention:  w.topicRetention,
+		},
+		TopicConfig{
+			Name:       DeleteTopic(w.Name()),
+			Partitions: 1,
+			Retention:  w.topicRetention,
+		},
+	)
+
+	return topics
+}
+
+// ProvisionTopics provides the workflow's Topics to the EventStreamer, and the dead-letter queue's topic to its
+// EventStreamer, when they implement TopicProvisioner. ProvisionTopics is called by Run and can also be called
+// before Run, such as during a deployment, to fail fast when the topics cannot be created.
+func (w *Workflow[Type, Status]) ProvisionTopics(ctx context.Context) error {
+	var errs []error
+	if provisioner, ok := unwrapEventStreamer(w.eventStreamer).(TopicProvisioner); ok {
+		err := provisioner.ProvisionTopics(ctx, w.Topics())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("provision topics: %w", err))
+		}
+	}
+
+	if provisioner, ok := unwrapEventStreamer(w.deadLetterStreamer).(TopicProvisioner); ok {
+		err := provisioner.ProvisionTopics(ctx, []TopicConfig{{
+			Name:       w.deadLetterTopic,
+			Partitions: 1,
+			Retention:  w.topicRetention,
+		}})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("provision dead-letter topic: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// provisionTopics logs, instead of failing Run, when the topics cannot be provisioned as the consumers retry until the
+// topics are available.
+func (w *Workflow[Type, Status]) provisionTopics(ctx context.Context) {
+	err := w.ProvisionTopics(ctx)
+	if err != nil {
+		w.logger.Error(ctx, fmt.Errorf("failed to provision topics: %w, meta: %v", err, map[string]string{
+			"workflow_name": w.Name(),
+		}))
+	}
+}
+
+// unwrapEventStreamer returns the underlying EventStreamer so that its optional interfaces can be found. Decorators
+// of the EventStreamer, such as those in adapters/instrumented, are unwrapped using their Unwrap method.
+func unwrapEventStreamer(streamer EventStreamer) EventStreamer {
+	for {
+		u, ok := streamer.(interface{ Unwrap() EventStreamer })
+		if !ok {
+			return streamer
+		}
+
+		streamer = u.Unwrap()
+	}
+}
@@ -0,0 +1,71 @@
+package workflow_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/instrumented"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+type provisioningStreamer struct {
+	workflow.EventStreamer
+
+	mu     sync.Mutex
+	topics []workflow.TopicConfig
+}
+
+func (s *provisioningStreamer) ProvisionTopics(ctx context.Context, topics []workflow.TopicConfig) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.topics = append(s.topics, topics...)
+	return nil
+}
+
+func TestProvisionTopics(t *testing.T) {
+	streamer := &provisioningStreamer{EventStreamer: memstreamer.New()}
+
+	b := workflow.NewBuilder[string, status]("provision")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return StatusMiddle, nil
+	}, StatusMiddle).WithOptions(workflow.ParallelCount(3))
+	b.AddStep(StatusMiddle, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	wf := b.Build(
+		// Decorated EventStreamers must still be provisioned.
+		instrumented.NewEventStreamer("provision", streamer),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+		workflow.WithTopicRetention(time.Hour),
+		workflow.WithPriorityLanes(1),
+	)
+
+	expected := []workflow.TopicConfig{
+		{Name: "provision-9", Partitions: 3, Retention: time.Hour},
+		{Name: "provision-9-p1", Partitions: 3, Retention: time.Hour},
+		{Name: "provision-10", Partitions: 1, Retention: time.Hour},
+		{Name: "provision-10-p1", Partitions: 1, Retention: time.Hour},
+		{Name: "provision-11", Partitions: 1, Retention: time.Hour},
+		{Name: "provision-11-p1", Partitions: 1, Retention: time.Hour},
+		{Name: "provision-run-state-change", Partitions: 1, Retention: time.Hour},
+		{Name: "provision-delete", Partitions: 1, Retention: time.Hour},
+	}
+	require.Equal(t, expected, wf.Topics())
+
+	wf.Run(context.Background())
+	t.Cleanup(wf.Stop)
+
+	streamer.mu.Lock()
+	defer streamer.mu.Unlock()
+	require.Equal(t, expected, streamer.topics)
+}
@@ -0,0 +1,92 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/luno/workflow/internal/metrics"
+)
+
+// ErrRunNotQuarantined is returned by ReprocessQuarantined when the run is not in RunStateQuarantined.
+var ErrRunNotQuarantined = errors.New("run not quarantined")
+
+// QuarantineAlertFunc is called every time a run is moved into RunStateQuarantined. The record contains the raw
+// Object that could not be unmarshalled and err is the unmarshal error.
+type QuarantineAlertFunc func(ctx context.Context, record *Record, err error)
+
+// WithUnmarshalQuarantine moves runs whose Object can no longer be unmarshalled into Type, such as after a
+// breaking change to Type, into RunStateQuarantined instead of retrying the event indefinitely. The stored Object
+// is left untouched and the unmarshal error is kept in RecordMeta.QuarantineReason. Once a fix has been released,
+// ReprocessQuarantined can be called to move the run back into RunStateRunning so that it is consumed again.
+//
+// alert is optional and is called every time a run is quarantined, in addition to the error being logged and the
+// quarantined run metric being incremented.
+func WithUnmarshalQuarantine(alert QuarantineAlertFunc) BuildOption {
+	return func(bo *buildOptions) {
+		bo.unmarshalQuarantine = true
+		bo.quarantineAlert = alert
+	}
+}
+
+type quarantineFunc func(ctx context.Context, processName string, record *Record, err error) error
+
+// quarantineFunc returns nil when the workflow has not been built using WithUnmarshalQuarantine.
+func (w *Workflow[Type, Status]) quarantineFunc() quarantineFunc {
+	if !w.unmarshalQuarantine {
+		return nil
+	}
+
+	return func(ctx context.Context, processName string, record *Record, err error) error {
+		if !runStateTransitions[record.RunState][RunStateQuarantined] {
+			return err
+		}
+
+		previousRunState := record.RunState
+		record.RunState = RunStateQuarantined
+		record.Meta.QuarantineReason = err.Error()
+		record.UpdatedAt = w.clock.Now()
+
+		storeErr := updateRecord(withTransitionError(ctx, err), w.recordStore.Store, record, previousRunState)
+		if storeErr != nil {
+			return storeErr
+		}
+
+		metrics.RunsQuarantined.WithLabelValues(w.Name(), processName).Inc()
+		w.logger.Error(ctx, fmt.Errorf("run quarantined: %v, meta: %v", err, map[string]string{
+			"workflow_name": w.Name(),
+			"process_name":  processName,
+			"run_id":        record.RunID,
+			"foreign_id":    record.ForeignID,
+			"status":        Status(record.Status).String(),
+		}))
+
+		if w.quarantineAlert != nil {
+			w.quarantineAlert(ctx, record, err)
+		}
+
+		return nil
+	}
+}
+
+// ReprocessQuarantined moves a run that has been quarantined, by WithUnmarshalQuarantine, back into RunStateRunning
+// so that the step of its current status consumes it again. This should be called once a version that is able to
+// unmarshal the run's Object has been released. ErrRunNotQuarantined is returned if the run is not quarantined.
+func (w *Workflow[Type, Status]) ReprocessQuarantined(ctx context.Context, runID string) error {
+	record, err := w.recordStore.Lookup(ctx, runID)
+	if err != nil {
+		return err
+	}
+
+	if record.WorkflowName != w.Name() || record.RunState != RunStateQuarantined {
+		return fmt.Errorf("reprocess failed: %w, meta: %v", ErrRunNotQuarantined, map[string]string{
+			"run_id":    runID,
+			"run_state": record.RunState.String(),
+		})
+	}
+
+	record.RunState = RunStateRunning
+	record.Meta.QuarantineReason = ""
+	record.UpdatedAt = w.clock.Now()
+	return updateRecord(ctx, w.recordStore.Store, record, RunStateQuarantined)
+}
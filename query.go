@@ -0,0 +1,191 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	defaultListRunsLimit = 100
+	listRunsPageSize     = 100
+)
+
+// RunFilter narrows down the runs returned by ListRuns. The zero value of each field disables that filter.
+type RunFilter[Status StatusType] struct {
+	// Statuses only includes runs that are currently in one of the statuses.
+	Statuses []Status
+	// RunStates only includes runs that are currently in one of the run states.
+	RunStates []RunState
+	// ForeignIDPrefix only includes runs whose foreign ID starts with the prefix.
+	ForeignIDPrefix string
+	// CreatedFrom only includes runs created at, or after, the time.
+	CreatedFrom time.Time
+	// CreatedTo only includes runs created before the time.
+	CreatedTo time.Time
+
+	// Offset is the number of matching runs to skip which allows for paging through the runs.
+	Offset int64
+	// Limit is the maximum number of runs returned and defaults to 100.
+	Limit int
+	// Order is the order of the runs by when they were created and defaults to OrderTypeAscending.
+	Order OrderType
+}
+
+// scanned returns true when the filter includes filters that are not supported by RecordStore.List and so the
+// records need to be filtered after being listed.
+func (f RunFilter[Status]) scanned() bool {
+	return f.ForeignIDPrefix != "" || !f.CreatedFrom.IsZero() || !f.CreatedTo.IsZero()
+}
+
+func (f RunFilter[Status]) matches(r *Record) bool {
+	if !strings.HasPrefix(r.ForeignID, f.ForeignIDPrefix) {
+		return false
+	}
+
+	if !f.CreatedFrom.IsZero() && r.CreatedAt.Before(f.CreatedFrom) {
+		return false
+	}
+
+	if !f.CreatedTo.IsZero() && !r.CreatedAt.Before(f.CreatedTo) {
+		return false
+	}
+
+	return true
+}
+
+func (f RunFilter[Status]) recordFilters() []RecordFilter {
+	var filters []RecordFilter
+	if len(f.Statuses) > 0 {
+		filters = append(filters, FilterByStatus(f.Statuses...))
+	}
+
+	if len(f.RunStates) > 0 {
+		filters = append(filters, FilterByRunState(f.RunStates...))
+	}
+
+	return filters
+}
+
+// ListRuns returns the workflow's runs that match the filter with their Object decoded using the workflow's Codec.
+// Filtering by foreign ID prefix or creation time is done after the records are listed from the RecordStore and so
+// these filters scan all the records that match the other filters.
+func (w *Workflow[Type, Status]) ListRuns(
+	ctx context.Context,
+	filter RunFilter[Status],
+) ([]TypedRecord[Type, Status], error) {
+	records, err := w.listRecords(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var runs []TypedRecord[Type, Status]
+	for i := range records {
+		typed, err := newTypedRecord[Type, Status](w.codec, &records[i])
+		if err != nil {
+			return nil, fmt.Errorf("list runs: %w, meta: %v", err, map[string]string{
+				"run_id": records[i].RunID,
+			})
+		}
+
+		runs = append(runs, *typed)
+	}
+
+	return runs, nil
+}
+
+// listRecords returns the workflow's records that match the filter.
+func (w *Workflow[Type, Status]) listRecords(ctx context.Context, filter RunFilter[Status]) ([]Record, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultListRunsLimit
+	}
+
+	order := filter.Order
+	if order == OrderTypeUnknown {
+		order = OrderTypeAscending
+	}
+
+	// The offset can be provided to the RecordStore when all the filters are supported by the RecordStore. Otherwise
+	// the matching records are counted to skip the offset.
+	storeOffset, skip := filter.Offset, int64(0)
+	if filter.scanned() {
+		storeOffset, skip = 0, filter.Offset
+	}
+
+	pageSize := max(limit, listRunsPageSize)
+
+	var matched []Record
+	for {
+		records, err := w.recordStore.List(ctx, w.Name(), storeOffset, pageSize, order, filter.recordFilters()...)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range records {
+			if !filter.matches(&records[i]) {
+				continue
+			}
+
+			if skip > 0 {
+				skip--
+				continue
+			}
+
+			matched = append(matched, records[i])
+			if len(matched) >= limit {
+				return matched, nil
+			}
+		}
+
+		if len(records) < pageSize {
+			return matched, nil
+		}
+
+		storeOffset += int64(len(records))
+	}
+}
+
+// GetRun returns the run with its Object decoded using the workflow's Codec. ErrRecordNotFound is returned when the
+// run does not exist or does not belong to the workflow and foreign ID. Runs that are not found in the RecordStore are
+// looked up in the ArchiveStore when one is configured using WithArchiveStore.
+func (w *Workflow[Type, Status]) GetRun(
+	ctx context.Context,
+	foreignID, runID string,
+) (*TypedRecord[Type, Status], error) {
+	record, err := w.recordStore.Lookup(ctx, runID)
+	record, archived, err := w.lookupArchived(ctx, runID, record, err)
+	if err != nil {
+		return nil, err
+	}
+
+	if record.WorkflowName != w.Name() || record.ForeignID != foreignID {
+		return nil, fmt.Errorf("get run: %w, meta: %v", ErrRecordNotFound, map[string]string{
+			"foreign_id": foreignID,
+			"run_id":     runID,
+		})
+	}
+
+	typed, err := newTypedRecord[Type, Status](w.codec, record)
+	if err != nil {
+		return nil, err
+	}
+
+	typed.Archived = archived
+	return typed, nil
+}
+
+func newTypedRecord[Type any, Status StatusType](codec Codec, r *Record) (*TypedRecord[Type, Status], error) {
+	var t Type
+	err := codec.Unmarshal(r.Object, &t)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TypedRecord[Type, Status]{
+		Record: *r,
+		Status: Status(r.Status),
+		Object: &t,
+	}, nil
+}
@@ -0,0 +1,174 @@
+package workflow_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	clock_testing "k8s.io/utils/clock/testing"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestListRuns(t *testing.T) {
+	b := workflow.NewBuilder[MyType, status]("list runs")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		// Block the step so that the runs remain in the status they were triggered in.
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}, StatusEnd)
+
+	now := time.Date(2024, time.April, 19, 0, 0, 0, 0, time.UTC)
+	clock := clock_testing.NewFakeClock(now)
+	recordStore := memrecordstore.New()
+	wf := b.Build(
+		memstreamer.New(),
+		recordStore,
+		memrolescheduler.New(),
+		workflow.WithClock(clock),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	foreignIDs := []string{"user-1", "order-1", "user-2", "user-3"}
+	for i, foreignID := range foreignIDs {
+		_, err := wf.Trigger(ctx, foreignID, StatusStart, workflow.WithInitialValue[MyType, status](&MyType{
+			UserID: int64(i),
+		}))
+		require.Nil(t, err)
+
+		clock.Step(time.Hour)
+	}
+
+	// Move user-3 onto the next status.
+	latest, err := recordStore.Latest(ctx, wf.Name(), "user-3")
+	require.Nil(t, err)
+	latest.Status = int(StatusEnd)
+	latest.RunState = workflow.RunStateCompleted
+	err = recordStore.Store(ctx, latest)
+	require.Nil(t, err)
+
+	testCases := []struct {
+		name     string
+		filter   workflow.RunFilter[status]
+		expected []string
+	}{
+		{
+			name:     "All runs",
+			expected: []string{"user-1", "order-1", "user-2", "user-3"},
+		},
+		{
+			name:     "By status",
+			filter:   workflow.RunFilter[status]{Statuses: []status{StatusStart}},
+			expected: []string{"user-1", "order-1", "user-2"},
+		},
+		{
+			name:     "By run state",
+			filter:   workflow.RunFilter[status]{RunStates: []workflow.RunState{workflow.RunStateCompleted}},
+			expected: []string{"user-3"},
+		},
+		{
+			name:     "By foreign ID prefix",
+			filter:   workflow.RunFilter[status]{ForeignIDPrefix: "user-"},
+			expected: []string{"user-1", "user-2", "user-3"},
+		},
+		{
+			name: "By time range",
+			filter: workflow.RunFilter[status]{
+				CreatedFrom: now.Add(time.Hour),
+				CreatedTo:   now.Add(3 * time.Hour),
+			},
+			expected: []string{"order-1", "user-2"},
+		},
+		{
+			name: "Paged with scanned filters",
+			filter: workflow.RunFilter[status]{
+				ForeignIDPrefix: "user-",
+				Offset:          1,
+				Limit:           1,
+			},
+			expected: []string{"user-2"},
+		},
+		{
+			name: "Paged",
+			filter: workflow.RunFilter[status]{
+				Offset: 2,
+				Limit:  5,
+			},
+			expected: []string{"user-2", "user-3"},
+		},
+		{
+			name: "Descending",
+			filter: workflow.RunFilter[status]{
+				ForeignIDPrefix: "user-",
+				Order:           workflow.OrderTypeDescending,
+			},
+			expected: []string{"user-3", "user-2", "user-1"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			runs, err := wf.ListRuns(ctx, tc.filter)
+			require.Nil(t, err)
+
+			var actual []string
+			for _, run := range runs {
+				actual = append(actual, run.ForeignID)
+				require.Equal(t, int(run.Status), run.Record.Status)
+			}
+
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+
+	runs, err := wf.ListRuns(ctx, workflow.RunFilter[status]{ForeignIDPrefix: "user-3"})
+	require.Nil(t, err)
+	require.Len(t, runs, 1)
+	require.Equal(t, StatusEnd, runs[0].Status)
+	require.Equal(t, int64(3), runs[0].Object.UserID)
+}
+
+func TestGetRun(t *testing.T) {
+	b := workflow.NewBuilder[MyType, status]("get run")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	recordStore := memrecordstore.New()
+	wf := b.Build(
+		memstreamer.New(),
+		recordStore,
+		memrolescheduler.New(),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "foreignID", StatusStart, workflow.WithInitialValue[MyType, status](&MyType{
+		Name: "Andrew",
+	}))
+	require.Nil(t, err)
+
+	_, err = wf.Await(ctx, "foreignID", runID, StatusEnd, workflow.WithAwaitPollingFrequency(10*time.Millisecond))
+	require.Nil(t, err)
+
+	run, err := wf.GetRun(ctx, "foreignID", runID)
+	require.Nil(t, err)
+	require.Equal(t, runID, run.RunID)
+	require.Equal(t, StatusEnd, run.Status)
+	require.Equal(t, "Andrew", run.Object.Name)
+
+	_, err = wf.GetRun(ctx, "other", runID)
+	require.ErrorIs(t, err, workflow.ErrRecordNotFound)
+
+	_, err = wf.GetRun(ctx, "foreignID", "missing")
+	require.ErrorIs(t, err, workflow.ErrRecordNotFound)
+}
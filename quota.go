@@ -0,0 +1,186 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+// ErrQuotaExceeded is returned by Trigger, wrapped in a *QuotaExceededError, when triggering the run would exceed one of
+// the quotas configured using WithTriggerQuota.
+var ErrQuotaExceeded = errors.New("run quota exceeded")
+
+// Quota limits the number of runs that can be triggered within each Window.
+type Quota struct {
+	// Name identifies the quota in QuotaExceededError.
+	Name string
+	// Limit is the maximum number of runs that can be triggered within a Window.
+	Limit int
+	// Window is the period that Limit applies to. Windows start at the Unix epoch, truncated to the Window, and so
+	// a Window of 24 hours resets at midnight UTC.
+	Window time.Duration
+	// Tenant returns the tenant of the foreign ID that is being triggered and each tenant is given its own Limit.
+	// All the runs of the workflow share the Limit when Tenant is nil.
+	Tenant func(foreignID string) string
+}
+
+// QuotaExceededError is returned by Trigger when a Quota has been exceeded. It wraps ErrQuotaExceeded and provides
+// how long until the Quota allows runs to be triggered again.
+type QuotaExceededError struct {
+	Quota     string
+	Tenant    string
+	ForeignID string
+	Limit     int
+	// RetryAfter is how long until the current Window ends and the Quota resets.
+	RetryAfter time.Duration
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("trigger failed: %v, meta: %v", ErrQuotaExceeded, map[string]string{
+		"quota":       e.Quota,
+		"tenant":      e.Tenant,
+		"foreign_id":  e.ForeignID,
+		"limit":       strconv.Itoa(e.Limit),
+		"retry_after": e.RetryAfter.String(),
+	})
+}
+
+func (e *QuotaExceededError) Unwrap() error {
+	return ErrQuotaExceeded
+}
+
+// QuotaExceededHook is called when Trigger is rejected due to a Quota being exceeded, such as to alert or bill the
+// tenant. The hook is called before Trigger returns and so should not block.
+type QuotaExceededHook func(ctx context.Context, err *QuotaExceededError)
+
+// WithTriggerQuota adds a Quota to the workflow which rejects triggers that would exceed it with a
+// *QuotaExceededError. The option can be provided multiple times, such as for a global quota and a per tenant quota,
+// and a run is only triggered when it is within all the quotas. Quotas are counted by each instance of the workflow
+// and triggers that are skipped as duplicates, rejected, or that fail to be stored, do not count towards the quotas.
+func WithTriggerQuota(q Quota) BuildOption {
+	return func(bo *buildOptions) {
+		bo.triggerQuotas = append(bo.triggerQuotas, q)
+	}
+}
+
+// WithQuotaExceededHook sets the hook that is called whenever a Quota configured using WithTriggerQuota rejects a
+// trigger.
+func WithQuotaExceededHook(hook QuotaExceededHook) BuildOption {
+	return func(bo *buildOptions) {
+		bo.quotaExceededHook = hook
+	}
+}
+
+type quotaWindow struct {
+	start time.Time
+	end   time.Time
+	count int
+}
+
+// quotas counts the runs that have been triggered within the current window of each quota.
+type quotas struct {
+	clock  clock.Clock
+	quotas []Quota
+	hook   QuotaExceededHook
+
+	mu      sync.Mutex
+	windows map[string]*quotaWindow
+	// nextPrune is when the windows that have ended are next removed so that the windows of tenants that are no
+	// longer triggering runs are not kept.
+	nextPrune time.Time
+}
+
+func newQuotas(qs []Quota, hook QuotaExceededHook, clock clock.Clock) *quotas {
+	return &quotas{
+		clock:   clock,
+		quotas:  qs,
+		hook:    hook,
+		windows: make(map[string]*quotaWindow),
+	}
+}
+
+// take reserves the run in every quota when it is within all the quotas and otherwise returns the
+// *QuotaExceededError of the first quota that it exceeds. The returned release func returns the reservation and is
+// called when the run could not be triggered so that only the runs that are stored count towards the quotas.
+func (q *quotas) take(ctx context.Context, foreignID string) (release func(), err error) {
+	q.mu.Lock()
+	now := q.clock.Now()
+	q.prune(now)
+
+	windows := make([]*quotaWindow, 0, len(q.quotas))
+	for i, quota := range q.quotas {
+		var tenant string
+		if quota.Tenant != nil {
+			tenant = quota.Tenant(foreignID)
+		}
+
+		start := now.Truncate(quota.Window)
+		key := strconv.Itoa(i) + "/" + tenant
+		window, ok := q.windows[key]
+		if !ok || !window.start.Equal(start) {
+			window = &quotaWindow{start: start, end: start.Add(quota.Window)}
+			q.windows[key] = window
+		}
+
+		if window.count >= quota.Limit {
+			q.mu.Unlock()
+
+			err := &QuotaExceededError{
+				Quota:      quota.Name,
+				Tenant:     tenant,
+				ForeignID:  foreignID,
+				Limit:      quota.Limit,
+				RetryAfter: window.end.Sub(now),
+			}
+
+			if q.hook != nil {
+				q.hook(ctx, err)
+			}
+
+			return nil, err
+		}
+
+		windows = append(windows, window)
+	}
+
+	for _, window := range windows {
+		window.count++
+	}
+
+	q.mu.Unlock()
+
+	return func() {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+
+		for _, window := range windows {
+			window.count--
+		}
+	}, nil
+}
+
+// prune removes the windows that ended before now, at most once per the shortest window of the quotas. The caller
+// must hold the lock.
+func (q *quotas) prune(now time.Time) {
+	if now.Before(q.nextPrune) {
+		return
+	}
+
+	for key, window := range q.windows {
+		if !now.Before(window.end) {
+			delete(q.windows, key)
+		}
+	}
+
+	interval := q.quotas[0].Window
+	for _, quota := range q.quotas[1:] {
+		interval = min(interval, quota.Window)
+	}
+
+	q.nextPrune = now.Add(interval)
+}
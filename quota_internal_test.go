@@ -0,0 +1,55 @@
+package workflow
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	clock_testing "k8s.io/utils/clock/testing"
+)
+
+func TestQuotasPrune(t *testing.T) {
+	clock := clock_testing.NewFakeClock(time.Date(2024, time.April, 19, 23, 0, 0, 0, time.UTC))
+	q := newQuotas([]Quota{
+		{
+			Name:   "tenant",
+			Limit:  1,
+			Window: time.Hour,
+			Tenant: func(foreignID string) string {
+				return foreignID
+			},
+		},
+	}, nil, clock)
+
+	ctx := context.Background()
+	for _, tenant := range []string{"a", "b", "c"} {
+		_, err := q.take(ctx, tenant)
+		require.Nil(t, err)
+	}
+
+	require.Len(t, q.windows, 3)
+
+	// The windows of the tenants that have not triggered runs since the previous window are removed.
+	clock.Step(time.Hour)
+	_, err := q.take(ctx, "a")
+	require.Nil(t, err)
+	require.Len(t, q.windows, 1)
+	require.Equal(t, 1, q.windows["0/a"].count)
+}
+
+func TestQuotasRelease(t *testing.T) {
+	clock := clock_testing.NewFakeClock(time.Date(2024, time.April, 19, 23, 0, 0, 0, time.UTC))
+	q := newQuotas([]Quota{{Name: "global", Limit: 1, Window: time.Hour}}, nil, clock)
+
+	ctx := context.Background()
+	release, err := q.take(ctx, "a")
+	require.Nil(t, err)
+
+	_, err = q.take(ctx, "b")
+	require.ErrorIs(t, err, ErrQuotaExceeded)
+
+	release()
+	_, err = q.take(ctx, "b")
+	require.Nil(t, err)
+}
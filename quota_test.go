@@ -0,0 +1,140 @@
+package workflow_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	clock_testing "k8s.io/utils/clock/testing"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestWithTriggerQuota(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("quota")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	clock := clock_testing.NewFakeClock(time.Date(2024, time.April, 19, 23, 0, 0, 0, time.UTC))
+
+	var exceeded []*workflow.QuotaExceededError
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+		workflow.WithClock(clock),
+		workflow.WithTriggerQuota(workflow.Quota{
+			Name:   "global",
+			Limit:  3,
+			Window: 24 * time.Hour,
+		}),
+		workflow.WithTriggerQuota(workflow.Quota{
+			Name:   "tenant",
+			Limit:  2,
+			Window: 24 * time.Hour,
+			Tenant: func(foreignID string) string {
+				tenant, _, _ := strings.Cut(foreignID, "/")
+				return tenant
+			},
+		}),
+		workflow.WithQuotaExceededHook(func(ctx context.Context, err *workflow.QuotaExceededError) {
+			exceeded = append(exceeded, err)
+		}),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	trigger := func(foreignID string) error {
+		_, err := wf.Trigger(ctx, foreignID, StatusStart)
+		return err
+	}
+
+	require.Nil(t, trigger("a/1"))
+	require.Nil(t, trigger("a/2"))
+
+	err := trigger("a/3")
+	require.ErrorIs(t, err, workflow.ErrQuotaExceeded)
+
+	var quotaErr *workflow.QuotaExceededError
+	require.True(t, errors.As(err, &quotaErr))
+	require.Equal(t, &workflow.QuotaExceededError{
+		Quota:      "tenant",
+		Tenant:     "a",
+		ForeignID:  "a/3",
+		Limit:      2,
+		RetryAfter: time.Hour,
+	}, quotaErr)
+
+	// The rejected trigger does not count towards the global quota.
+	require.Nil(t, trigger("b/1"))
+
+	err = trigger("c/1")
+	require.True(t, errors.As(err, &quotaErr))
+	require.Equal(t, "global", quotaErr.Quota)
+
+	require.Len(t, exceeded, 2)
+	require.Equal(t, "tenant", exceeded[0].Quota)
+	require.Equal(t, "global", exceeded[1].Quota)
+
+	// The quotas reset once the window ends.
+	clock.Step(time.Hour)
+	require.Nil(t, trigger("a/3"))
+}
+
+type failingStore struct {
+	workflow.RecordStore
+	err error
+}
+
+func (s *failingStore) Store(ctx context.Context, r *workflow.Record) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	return s.RecordStore.Store(ctx, r)
+}
+
+func TestWithTriggerQuota_StoreFailure(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("quota")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	store := &failingStore{RecordStore: memrecordstore.New()}
+	wf := b.Build(
+		memstreamer.New(),
+		store,
+		memrolescheduler.New(),
+		workflow.WithTriggerQuota(workflow.Quota{
+			Name:   "global",
+			Limit:  1,
+			Window: 24 * time.Hour,
+		}),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	testErr := errors.New("constraint violation")
+	store.err = testErr
+	_, err := wf.Trigger(ctx, "1", StatusStart)
+	require.ErrorIs(t, err, testErr)
+
+	// The run that failed to be stored does not count towards the quota.
+	store.err = nil
+	_, err = wf.Trigger(ctx, "1", StatusStart)
+	require.Nil(t, err)
+
+	_, err = wf.Trigger(ctx, "2", StatusStart)
+	require.ErrorIs(t, err, workflow.ErrQuotaExceeded)
+}
@@ -0,0 +1,140 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+// ErrRateLimited is returned by Trigger when the rate limit configured using WithTriggerRateLimit has been exceeded.
+// The run is not created and the trigger can be retried once the rate limit allows it.
+var ErrRateLimited = errors.New("trigger rate limit exceeded")
+
+// WithTriggerRateLimit limits the rate at which runs can be triggered to perSecond runs per second with bursts of up
+// to burst runs. Triggers that exceed the rate limit fail with ErrRateLimited instead of storing the run which
+// protects the RecordStore, and the workflow's steps, from bursty callers and bulk scripts. The rate limit is per
+// instance of the workflow and so the total rate across all instances is the rate limit multiplied by the number of
+// instances that are triggering runs.
+func WithTriggerRateLimit(perSecond float64, burst int) BuildOption {
+	return func(bo *buildOptions) {
+		bo.triggerRateLimit = &rateLimit{
+			perSecond: perSecond,
+			burst:     max(burst, 1),
+		}
+	}
+}
+
+type rateLimit struct {
+	perSecond float64
+	burst     int
+}
+
+// rateLimiter is a token bucket that is refilled at the rate limit's perSecond up to its burst.
+type rateLimiter struct {
+	clock clock.Clock
+	limit rateLimit
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(limit rateLimit, clock clock.Clock) *rateLimiter {
+	return &rateLimiter{
+		clock:  clock,
+		limit:  limit,
+		tokens: float64(limit.burst),
+		last:   clock.Now(),
+	}
+}
+
+// take takes a token from the bucket and returns ErrRateLimited, along with how long until a token is available,
+// when the bucket is empty.
+func (l *rateLimiter) take() error {
+	retryAfter, ok := l.tryTake()
+	if !ok {
+		return fmt.Errorf("trigger failed: %w, meta: %v", ErrRateLimited, map[string]string{
+			"retry_after": retryAfter.String(),
+		})
+	}
+
+	return nil
+}
+
+// wait blocks until a token can be taken from the bucket.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	for {
+		retryAfter, ok := l.tryTake()
+		if ok {
+			return nil
+		}
+
+		err := waitUntil(ctx, l.clock, l.clock.Now().Add(retryAfter))
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// tryTake takes a token from the bucket and returns false, along with how long until a token is available, when the
+// bucket is empty.
+func (l *rateLimiter) tryTake() (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.clock.Now()
+	if elapsed := now.Sub(l.last); elapsed > 0 {
+		l.tokens = math.Min(float64(l.limit.burst), l.tokens+elapsed.Seconds()*l.limit.perSecond)
+		l.last = now
+	}
+
+	if l.tokens < 1 {
+		var retryAfter time.Duration
+		if l.limit.perSecond > 0 {
+			retryAfter = time.Duration((1 - l.tokens) / l.limit.perSecond * float64(time.Second))
+		}
+
+		return retryAfter, false
+	}
+
+	l.tokens--
+	return 0, true
+}
+
+// RateLimit limits the rate at which the step processes runs to perSecond runs per second with bursts of up to burst
+// runs, such as to keep within the quota of an external API that the step calls. The rate limit is shared by all the
+// shards of the step, regardless of the ParallelCount, but is per instance of the workflow. Runs that are skipped
+// before reaching the step, such as those that have already moved on, are not counted. Rate limits are not supported
+// by batch steps.
+func RateLimit(perSecond float64, burst int) Option {
+	return func(opt *options) {
+		opt.rateLimit = &rateLimit{
+			perSecond: perSecond,
+			burst:     max(burst, 1),
+		}
+	}
+}
+
+// rateLimitConsumer waits for the rate limiter before calling the consumer.
+func rateLimitConsumer[Type any, Status StatusType](
+	limiter *rateLimiter,
+	consumer ConsumerFunc[Type, Status],
+) ConsumerFunc[Type, Status] {
+	if limiter == nil {
+		return consumer
+	}
+
+	return func(ctx context.Context, r *Run[Type, Status]) (Status, error) {
+		err := limiter.wait(ctx)
+		if err != nil {
+			return 0, err
+		}
+
+		return consumer(ctx, r)
+	}
+}
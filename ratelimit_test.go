@@ -0,0 +1,154 @@
+package workflow_test
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	clock_testing "k8s.io/utils/clock/testing"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestWithTriggerRateLimit(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("rate limit")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	clock := clock_testing.NewFakeClock(time.Date(2024, time.April, 19, 0, 0, 0, 0, time.UTC))
+	recordStore := memrecordstore.New()
+	wf := b.Build(
+		memstreamer.New(),
+		recordStore,
+		memrolescheduler.New(),
+		workflow.WithClock(clock),
+		workflow.WithTriggerRateLimit(2, 3),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	trigger := func(i int) error {
+		_, err := wf.Trigger(ctx, strconv.Itoa(i), StatusStart)
+		return err
+	}
+
+	// The burst is available immediately.
+	for i := 0; i < 3; i++ {
+		require.Nil(t, trigger(i))
+	}
+
+	err := trigger(3)
+	require.ErrorIs(t, err, workflow.ErrRateLimited)
+
+	_, err = recordStore.Latest(ctx, wf.Name(), "3")
+	require.ErrorIs(t, err, workflow.ErrRecordNotFound)
+
+	// A token is added every half a second.
+	clock.Step(500 * time.Millisecond)
+	require.Nil(t, trigger(3))
+	require.ErrorIs(t, trigger(4), workflow.ErrRateLimited)
+
+	// The bucket never holds more than the burst.
+	clock.Step(time.Hour)
+	for i := 4; i < 7; i++ {
+		require.Nil(t, trigger(i))
+	}
+	require.ErrorIs(t, trigger(7), workflow.ErrRateLimited)
+}
+
+func TestWithTriggerRateLimit_duplicateTriggers(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("rate limit")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	clock := clock_testing.NewFakeClock(time.Date(2024, time.April, 19, 0, 0, 0, 0, time.UTC))
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+		workflow.WithClock(clock),
+		workflow.WithTriggerRateLimit(1, 1),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "example", StatusStart, workflow.WithDedupWindow[string, status](time.Hour))
+	require.Nil(t, err)
+
+	// Deduplicated triggers do not store a run and so do not use up the tokens.
+	for i := 0; i < 3; i++ {
+		dupRunID, err := wf.Trigger(ctx, "example", StatusStart, workflow.WithDedupWindow[string, status](time.Hour))
+		require.Nil(t, err)
+		require.Equal(t, runID, dupRunID)
+	}
+
+	clock.Step(time.Second)
+	_, err = wf.Trigger(ctx, "other", StatusStart)
+	require.Nil(t, err)
+}
+
+func TestStepRateLimit(t *testing.T) {
+	var calls atomic.Int64
+	b := workflow.NewBuilder[string, status]("step rate limit")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		calls.Add(1)
+		return StatusEnd, nil
+	}, StatusEnd).WithOptions(
+		workflow.ParallelCount(3),
+		workflow.RateLimit(1, 2),
+	)
+
+	clock := clock_testing.NewFakeClock(time.Date(2024, time.April, 19, 0, 0, 0, 0, time.UTC))
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+		workflow.WithClock(clock),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	for i := 0; i < 4; i++ {
+		_, err := wf.Trigger(ctx, strconv.Itoa(i), StatusStart)
+		require.Nil(t, err)
+	}
+
+	// The burst is shared by the shards of the step and the remaining runs wait for more tokens.
+	require.Eventually(t, func() bool {
+		return calls.Load() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.Never(t, func() bool {
+		return calls.Load() > 2
+	}, 200*time.Millisecond, 10*time.Millisecond)
+
+	require.Eventually(t, func() bool {
+		clock.Step(time.Second)
+		return calls.Load() == 4
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestStepRateLimitValidation(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("step rate limit")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd).WithOptions(workflow.RateLimit(0, 1))
+
+	require.PanicsWithValue(t, "'AddStep(Start,' rate limit requires a positive rate", func() {
+		b.Build(memstreamer.New(), memrecordstore.New(), memrolescheduler.New())
+	})
+}
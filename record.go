@@ -1,6 +1,9 @@
 package workflow
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // Record is the cornerstone of Workflow. Record must always be wire compatible with no generics as it's intended
 // purpose is to be the stored structure of a Run.
@@ -11,13 +14,60 @@ type Record struct {
 	RunState     RunState
 	Status       int
 	Object       []byte
+	Meta         RecordMeta
 	CreatedAt    time.Time
 	UpdatedAt    time.Time
 }
 
+// RecordMeta holds data that workflow needs to persist with a Record that is not part of the user's Object. RecordMeta
+// must remain JSON serializable as adapters are expected to store it as a single encoded value.
+type RecordMeta struct {
+	// Version is the graph version, configured with WithGraphVersion, of the workflow that triggered the run.
+	Version int `json:"version,omitempty"`
+	// Pinned is true when the run was triggered by a workflow built using Builder.Version. Pinned runs continue on
+	// the graph of their Version and their events are published to the Version's topics.
+	Pinned bool `json:"pinned,omitempty"`
+	// StepHistory is the list of statuses, in order, whose step has moved the run onto its next status.
+	StepHistory []int `json:"step_history,omitempty"`
+	// History is the list of transitions, in order, of the run's status and run state which is returned by
+	// RunHistory.
+	History []Transition `json:"history,omitempty"`
+	// Metadata is the run's metadata, set using WithMetadata or Run.SetMetadata, which is kept separately from the
+	// run's Object.
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// Snapshots are the encoded copies of the run's Object taken using Run.Snapshot.
+	Snapshots map[string][]byte `json:"snapshots,omitempty"`
+	// TraceParent is the W3C traceparent of the span that last processed the run when tracing is enabled using
+	// WithTracerProvider.
+	TraceParent string `json:"trace_parent,omitempty"`
+	// QuarantineReason is the error that caused the run to be moved into RunStateQuarantined.
+	QuarantineReason string `json:"quarantine_reason,omitempty"`
+	// Priority is the priority lane, provided using WithPriority, that the run's events are published to.
+	Priority int `json:"priority,omitempty"`
+	// Variables are the JSON encoded values of the run's variables, set using SetVar, which are kept separately from
+	// the run's Object.
+	Variables map[string]json.RawMessage `json:"variables,omitempty"`
+	// StartAt is the time that the run, triggered using WithStartAt or WithDelay, becomes consumable and is cleared
+	// once the run has started.
+	StartAt *time.Time `json:"start_at,omitempty"`
+	// ReplayedSteps are the statuses, in order, whose steps moved the run on before the run was replayed using
+	// ReplayRun and that have not yet moved the run on again since. See Run.Replaying.
+	ReplayedSteps []int `json:"replayed_steps,omitempty"`
+	// SearchIndex are the values of the search indexes, configured using WithSearchIndex, that were extracted from
+	// the run's Object when the record was stored.
+	SearchIndex map[string]string `json:"search_index,omitempty"`
+	// SideEffects are the results of the run's side effects, keyed by the key provided to Run.SideEffect.
+	SideEffects map[string][]byte `json:"side_effects,omitempty"`
+	// Checkpoint is the progress of the step of the run's current status that was saved using Run.Checkpoint and is
+	// cleared once the run moves onto its next status.
+	Checkpoint *Checkpoint `json:"checkpoint,omitempty"`
+}
+
 // TypedRecord differs from Record in that it contains a Typed Object and Typed Status
 type TypedRecord[Type any, Status StatusType] struct {
 	Record
 	Status Status
 	Object *Type
+	// Archived is true when the run was read from the ArchiveStore configured using WithArchiveStore.
+	Archived bool
 }
@@ -0,0 +1,48 @@
+package workflow
+
+type redactFunc func(codec Codec, objectCodec Codec, wr *Record) ([]byte, error)
+
+// WithRedaction registers a function that masks the PII, or any other sensitive data, of a run's Object so that
+// admin tooling, such as the webui adapter, only shows a scrubbed view of the Object. fn is provided with a copy of
+// the run's Object and the stored Object is never modified.
+func WithRedaction[Type any](fn func(object *Type) error) BuildOption {
+	return func(bo *buildOptions) {
+		bo.redact = func(codec Codec, objectCodec Codec, wr *Record) ([]byte, error) {
+			var t Type
+			err := codec.Unmarshal(wr.Object, &t)
+			if err != nil {
+				return nil, err
+			}
+
+			err = fn(&t)
+			if err != nil {
+				return nil, err
+			}
+
+			return objectCodec.Marshal(&t)
+		}
+	}
+}
+
+// Redact returns the Object of the record with the redaction, configured using WithRedaction, applied. The Object
+// is returned as is when no redaction has been configured. Compressed and encrypted Objects are always returned in
+// their plain form.
+func (w *Workflow[Type, Status]) Redact(record *Record) ([]byte, error) {
+	objectCodec := unwrapCodec(w.codec)
+	if w.redact != nil {
+		return w.redact(w.codec, objectCodec, record)
+	}
+
+	switch w.codec.(type) {
+	case *compressedCodec, *encryptedCodec:
+		var t Type
+		err := w.codec.Unmarshal(record.Object, &t)
+		if err != nil {
+			return nil, err
+		}
+
+		return objectCodec.Marshal(&t)
+	default:
+		return record.Object, nil
+	}
+}
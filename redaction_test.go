@@ -0,0 +1,56 @@
+package workflow_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestRedact(t *testing.T) {
+	build := func(opts ...workflow.BuildOption) *workflow.Workflow[MyType, status] {
+		b := workflow.NewBuilder[MyType, status]("redaction")
+		b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+			return StatusEnd, nil
+		}, StatusEnd)
+
+		return b.Build(memstreamer.New(), memrecordstore.New(), memrolescheduler.New(), opts...)
+	}
+
+	object, err := workflow.Marshal(&MyType{
+		UserID: 9,
+		Email:  "andrew@workflow.com",
+	})
+	require.Nil(t, err)
+
+	record := &workflow.Record{Object: object}
+
+	t.Run("Redacts object", func(t *testing.T) {
+		wf := build(workflow.WithRedaction(func(object *MyType) error {
+			object.Email = "****"
+			return nil
+		}))
+
+		redacted, err := wf.Redact(record)
+		require.Nil(t, err)
+
+		var actual MyType
+		err = workflow.Unmarshal(redacted, &actual)
+		require.Nil(t, err)
+		require.Equal(t, MyType{UserID: 9, Email: "****"}, actual)
+
+		// The record's Object must remain unchanged.
+		require.Equal(t, object, record.Object)
+	})
+
+	t.Run("Returns object when no redaction is configured", func(t *testing.T) {
+		redacted, err := build().Redact(record)
+		require.Nil(t, err)
+		require.Equal(t, object, redacted)
+	})
+}
@@ -0,0 +1,217 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/luno/workflow/internal/metrics"
+)
+
+// ReplayRun resets the run to the status, which must be a status whose step has already moved the run on, so that
+// the run is consumed again from the status, such as after deploying a fix for a buggy step. The run's Object is kept
+// as it is and the steps from the status onwards are called again. Steps with side effects that must not be repeated
+// can check Run.Replaying or be given a replay consumer using WithReplayConsumer. Completed and paused runs are
+// replayed as well as running runs. The replay is recorded in the run's history along with the reason and the actor
+// set on the context using WithActor. ErrInvalidTransition is returned when the run has not been moved on from the
+// status or when the run has been cancelled, quarantined, or had its data deleted.
+func (w *Workflow[Type, Status]) ReplayRun(ctx context.Context, runID string, fromStatus Status, reason string) error {
+	record, err := w.recordStore.Lookup(ctx, runID)
+	if err != nil {
+		return err
+	}
+
+	if record.WorkflowName != w.Name() {
+		return fmt.Errorf("replay run: %w, meta: %v", ErrRecordNotFound, map[string]string{
+			"run_id": runID,
+		})
+	}
+
+	meta := map[string]string{
+		"run_id":      runID,
+		"run_state":   record.RunState.String(),
+		"from_status": fromStatus.String(),
+	}
+
+	switch record.RunState {
+	case RunStateRunning, RunStatePaused, RunStateCompleted:
+	default:
+		return fmt.Errorf("replay run: %w, meta: %v", ErrInvalidTransition, meta)
+	}
+
+	// The run is replayed from the latest time that the step of the status moved the run on.
+	index := -1
+	for i, status := range record.Meta.StepHistory {
+		if status == int(fromStatus) {
+			index = i
+		}
+	}
+
+	if index == -1 {
+		return fmt.Errorf("replay run: %w: run has not been moved on from the status, meta: %v",
+			ErrInvalidTransition, meta)
+	}
+
+	ctx = withTransitionProcess(ctx, "", w.clock)
+	ctx = withTransitionReason(ctx, reason)
+
+	previousStatus := record.Status
+	previousRunState := record.RunState
+	record.Status = int(fromStatus)
+	record.RunState = RunStateRunning
+	record.UpdatedAt = w.clock.Now()
+
+	// The steps that are replayed are removed from the run's step history, so that they are only compensated once,
+	// and are kept as the replayed steps until they move the run on again.
+	record.Meta.ReplayedSteps = slices.Clone(record.Meta.StepHistory[index:])
+	record.Meta.StepHistory = slices.Clone(record.Meta.StepHistory[:index])
+	record.Meta.History = appendTransition(ctx, record.Meta.History, Transition{
+		FromStatus:   previousStatus,
+		ToStatus:     int(fromStatus),
+		FromRunState: previousRunState,
+		ToRunState:   RunStateRunning,
+		At:           record.UpdatedAt,
+		Replay:       true,
+	})
+
+	metrics.RunStateChanges.WithLabelValues(w.Name(), previousRunState.String(), RunStateRunning.String()).Inc()
+
+	err = w.recordStore.Store(ctx, record)
+	if err != nil {
+		return err
+	}
+
+	w.logger.event(ctx, DebugEvent{
+		Type:    DebugEventRunReplayed,
+		Message: "replayed run",
+		Meta: map[string]string{
+			"workflow_name": w.Name(),
+			"run_id":        runID,
+			"from_status":   fromStatus.String(),
+			"reason":        reason,
+			"actor":         transitionFromContext(ctx).actor,
+		},
+	})
+
+	return nil
+}
+
+// ReplayAll replays all the runs that match the filter from the status, such as to recover from a buggy step that
+// was deployed, at the rate set using WithBulkRateLimit. See ReplayRun. Runs created after ReplayAll started are not
+// included and runs that have not been moved on from the status are skipped. The filter's Limit is the number of runs
+// listed per page and the filter's Offset and Order are ignored.
+func (w *Workflow[Type, Status]) ReplayAll(
+	ctx context.Context,
+	filter RunFilter[Status],
+	fromStatus Status,
+	opts ...BulkOption,
+) (*BulkResult, error) {
+	o := bulkOpts{
+		perSecond: defaultBulkRatePerSecond,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.perSecond <= 0 {
+		return nil, errors.New("replay all: rate limit requires a positive rate")
+	}
+
+	filter.Order = OrderTypeAscending
+	if filter.CreatedTo.IsZero() || filter.CreatedTo.After(w.clock.Now()) {
+		filter.CreatedTo = w.clock.Now()
+	}
+
+	// Replayed runs can move in and out of the filter and so all the matching runs are listed before any of them are
+	// replayed.
+	var runIDs []string
+	for filter.Offset = 0; ; {
+		records, err := w.listRecords(ctx, filter)
+		if err != nil {
+			return &BulkResult{}, fmt.Errorf("replay all: %w, meta: %v", err, map[string]string{
+				"offset": fmt.Sprint(filter.Offset),
+			})
+		}
+
+		if len(records) == 0 {
+			break
+		}
+
+		for _, record := range records {
+			runIDs = append(runIDs, record.RunID)
+		}
+
+		filter.Offset += int64(len(records))
+	}
+
+	limiter := newRateLimiter(rateLimit{perSecond: o.perSecond, burst: 1}, w.clock)
+
+	var result BulkResult
+	for _, runID := range runIDs {
+		err := limiter.wait(ctx)
+		if err != nil {
+			return &result, err
+		}
+
+		err = w.ReplayRun(ctx, runID, fromStatus, o.reason)
+		switch {
+		case errors.Is(err, ErrInvalidTransition):
+			result.Skipped++
+		case err != nil:
+			result.Failed++
+			w.logger.Error(ctx, fmt.Errorf("replay run: %w, meta: %v", err, map[string]string{
+				"workflow_name": w.Name(),
+				"run_id":        runID,
+				"from_status":   fromStatus.String(),
+			}))
+		default:
+			result.Updated++
+		}
+	}
+
+	return &result, nil
+}
+
+// Replaying returns true when the run is being consumed again in its current status after being replayed using
+// ReplayRun or ReplayAll, and so the side effects of the step, such as sending an email, have already happened.
+func (r *Run[Type, Status]) Replaying() bool {
+	return slices.Contains(r.Meta.ReplayedSteps, int(r.Status))
+}
+
+// WithReplayConsumer sets the consumer that is called instead of the step while the run is being replayed, see
+// Run.Replaying, so that steps with side effects can move the run on without repeating them.
+func (s *stepUpdater[Type, Status]) WithReplayConsumer(c ConsumerFunc[Type, Status]) *stepUpdater[Type, Status] {
+	consumer := s.workflow.consumers[s.from]
+	consumer.replay = c
+	s.workflow.consumers[s.from] = consumer
+	return s
+}
+
+func replayConsumer[Type any, Status StatusType](
+	replay ConsumerFunc[Type, Status],
+	consumer ConsumerFunc[Type, Status],
+) ConsumerFunc[Type, Status] {
+	if replay == nil {
+		return consumer
+	}
+
+	return func(ctx context.Context, r *Run[Type, Status]) (Status, error) {
+		if r.Replaying() {
+			return replay(ctx, r)
+		}
+
+		return consumer(ctx, r)
+	}
+}
+
+// replayedStep returns the replayed steps without the first replayed step of the status once it has moved the run on.
+func replayedStep(replayed []int, status int) []int {
+	i := slices.Index(replayed, status)
+	if i == -1 {
+		return replayed
+	}
+
+	return slices.Delete(slices.Clone(replayed), i, i+1)
+}
@@ -0,0 +1,109 @@
+package workflow_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestReplayRun(t *testing.T) {
+	var (
+		emailsSent atomic.Int64
+		fixed      atomic.Bool
+	)
+
+	b := workflow.NewBuilder[string, status]("replay")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		emailsSent.Add(1)
+		return StatusMiddle, nil
+	}, StatusMiddle).WithReplayConsumer(func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return StatusMiddle, nil
+	})
+	b.AddStep(StatusMiddle, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		*r.Object = "buggy"
+		if fixed.Load() {
+			*r.Object = "fixed"
+		}
+
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	recordStore := memrecordstore.New()
+	wf := b.Build(memstreamer.New(), recordStore, memrolescheduler.New())
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runIDs := make([]string, 2)
+	for i, foreignID := range []string{"foreignID-1", "foreignID-2"} {
+		runID, err := wf.Trigger(ctx, foreignID, StatusStart)
+		require.Nil(t, err)
+
+		run, err := wf.Await(ctx, foreignID, runID, StatusEnd)
+		require.Nil(t, err)
+		require.Equal(t, "buggy", *run.Object)
+
+		runIDs[i] = runID
+	}
+
+	fixed.Store(true)
+
+	awaitFixed := func(runID string) {
+		require.Eventually(t, func() bool {
+			record, err := recordStore.Lookup(ctx, runID)
+			require.Nil(t, err)
+			return record.Status == int(StatusEnd) && string(record.Object) == `"fixed"`
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+
+	ctx = workflow.WithActor(ctx, "operator@example.com")
+
+	// The run has not been moved on from its terminal status.
+	err := wf.ReplayRun(ctx, runIDs[0], StatusEnd, "fix deployed")
+	require.ErrorIs(t, err, workflow.ErrInvalidTransition)
+
+	err = wf.ReplayRun(ctx, runIDs[0], StatusStart, "fix deployed")
+	require.Nil(t, err)
+	awaitFixed(runIDs[0])
+
+	// The side effect of the first step was not repeated.
+	require.Equal(t, int64(2), emailsSent.Load())
+
+	record, err := recordStore.Lookup(ctx, runIDs[0])
+	require.Nil(t, err)
+	require.Equal(t, workflow.RunStateCompleted, record.RunState)
+	require.Equal(t, []int{int(StatusStart), int(StatusMiddle)}, record.Meta.StepHistory)
+	require.Empty(t, record.Meta.ReplayedSteps)
+
+	history, err := wf.RunHistory(ctx, runIDs[0])
+	require.Nil(t, err)
+
+	var replays []workflow.Transition
+	for _, transition := range history {
+		if transition.Replay {
+			replays = append(replays, transition)
+		}
+	}
+	require.Len(t, replays, 1)
+	require.Equal(t, int(StatusEnd), replays[0].FromStatus)
+	require.Equal(t, int(StatusStart), replays[0].ToStatus)
+	require.Equal(t, "operator@example.com", replays[0].Actor)
+	require.Equal(t, "fix deployed", replays[0].Reason)
+
+	res, err := wf.ReplayAll(ctx, workflow.RunFilter[status]{}, StatusMiddle, workflow.WithBulkReason("fix deployed"))
+	require.Nil(t, err)
+	require.Equal(t, &workflow.BulkResult{Updated: 2}, res)
+
+	awaitFixed(runIDs[0])
+	awaitFixed(runIDs[1])
+	require.Equal(t, int64(2), emailsSent.Load())
+}
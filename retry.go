@@ -0,0 +1,129 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// RetryPolicy replaces the flat ErrBackOff of the step with an exponential backoff per run. The first retry of a run
+// waits initial and every consecutive failure of the same run multiplies the wait by multiplier, up to max. Jitter is
+// the fraction of the wait, between 0 and 1, that is randomly taken off the wait so that the shards of the step, and
+// the instances of the workflow, don't retry in lockstep. The attempts of a run are reset once the step succeeds, or
+// the run is paused by PauseAfterErrCount, and are tracked per instance of the workflow. Retry policies are not
+// supported by batch steps.
+func RetryPolicy(initial, max time.Duration, multiplier, jitter float64) Option {
+	return func(opt *options) {
+		opt.retryPolicy = &retryPolicyConfig{
+			initial:    initial,
+			max:        max,
+			multiplier: multiplier,
+			jitter:     jitter,
+		}
+	}
+}
+
+type retryPolicyConfig struct {
+	initial    time.Duration
+	max        time.Duration
+	multiplier float64
+	jitter     float64
+}
+
+func (c retryPolicyConfig) validate() bool {
+	return c.initial > 0 && c.max >= c.initial && c.multiplier >= 1 && c.jitter >= 0 && c.jitter <= 1
+}
+
+// backOff returns the wait before the attempt, where the first retry is attempt 1, without jitter applied.
+func (c retryPolicyConfig) backOff(attempt int) time.Duration {
+	d := float64(c.initial) * math.Pow(c.multiplier, float64(attempt-1))
+	if d > float64(c.max) {
+		return c.max
+	}
+
+	return time.Duration(d)
+}
+
+// retryPolicy tracks the consecutive failures of the runs consumed by a step and is shared by the step's shards.
+type retryPolicy struct {
+	config retryPolicyConfig
+
+	mu       sync.Mutex
+	attempts map[string]int
+}
+
+func newRetryPolicy(config retryPolicyConfig) *retryPolicy {
+	return &retryPolicy{
+		config:   config,
+		attempts: make(map[string]int),
+	}
+}
+
+// result records the outcome of consuming the run and returns the number of consecutive failures of the run along
+// with how long to wait before the run is retried.
+func (p *retryPolicy) result(runID string, err error) (int, time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err == nil {
+		delete(p.attempts, runID)
+		return 0, 0
+	}
+
+	p.attempts[runID]++
+	attempt := p.attempts[runID]
+	d := p.config.backOff(attempt)
+	if p.config.jitter > 0 {
+		d -= time.Duration(rand.Float64() * p.config.jitter * float64(d))
+	}
+
+	return attempt, d
+}
+
+// retryError is returned by the consumer of a step with a RetryPolicy so that the process backs off for RetryAfter
+// instead of the step's ErrBackOff.
+type retryError struct {
+	Err        error
+	Attempt    int
+	RetryAfter time.Duration
+}
+
+func (e *retryError) Error() string {
+	return fmt.Sprintf("%v, attempt: %d, retry_after: %s", e.Err, e.Attempt, e.RetryAfter)
+}
+
+func (e *retryError) Unwrap() error {
+	return e.Err
+}
+
+// retryPolicyConsumeFn records the outcome of consuming each event with the retry policy and returns a retryError
+// when the event's run failed.
+func retryPolicyConsumeFn(
+	policy *retryPolicy,
+	consumeFn func(ctx context.Context, e *Event) error,
+) func(ctx context.Context, e *Event) error {
+	if policy == nil {
+		return consumeFn
+	}
+
+	return func(ctx context.Context, e *Event) error {
+		err := consumeFn(ctx, e)
+		if ctx.Err() != nil {
+			return err
+		}
+
+		attempt, retryAfter := policy.result(e.ForeignID, err)
+		if err != nil {
+			return &retryError{
+				Err:        err,
+				Attempt:    attempt,
+				RetryAfter: retryAfter,
+			}
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,50 @@
+package workflow
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryPolicyResult(t *testing.T) {
+	p := newRetryPolicy(retryPolicyConfig{
+		initial:    time.Second,
+		max:        5 * time.Second,
+		multiplier: 2,
+	})
+
+	testErr := errors.New("test error")
+	for i, expected := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second} {
+		attempt, retryAfter := p.result("runID", testErr)
+		require.Equal(t, i+1, attempt)
+		require.Equal(t, expected, retryAfter)
+	}
+
+	// The attempts of other runs are tracked separately.
+	attempt, retryAfter := p.result("otherRunID", testErr)
+	require.Equal(t, 1, attempt)
+	require.Equal(t, time.Second, retryAfter)
+
+	// Succeeding resets the attempts of the run.
+	_, _ = p.result("runID", nil)
+	attempt, retryAfter = p.result("runID", testErr)
+	require.Equal(t, 1, attempt)
+	require.Equal(t, time.Second, retryAfter)
+}
+
+func TestRetryPolicyJitter(t *testing.T) {
+	p := newRetryPolicy(retryPolicyConfig{
+		initial:    time.Second,
+		max:        time.Second,
+		multiplier: 1,
+		jitter:     0.5,
+	})
+
+	for range 100 {
+		_, retryAfter := p.result("runID", errors.New("test error"))
+		require.GreaterOrEqual(t, retryAfter, 500*time.Millisecond)
+		require.LessOrEqual(t, retryAfter, time.Second)
+	}
+}
@@ -0,0 +1,109 @@
+package workflow_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		calls []time.Time
+	)
+
+	b := workflow.NewBuilder[MyType, status]("retry policy")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		calls = append(calls, time.Now())
+		if len(calls) < 4 {
+			return 0, errors.New("downstream unavailable")
+		}
+
+		return StatusEnd, nil
+	}, StatusEnd).WithOptions(workflow.RetryPolicy(50*time.Millisecond, 400*time.Millisecond, 4, 0))
+
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "foreignID", StatusStart)
+	require.Nil(t, err)
+
+	_, err = wf.Await(ctx, "foreignID", runID, StatusEnd, workflow.WithAwaitPollingFrequency(10*time.Millisecond))
+	require.Nil(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, calls, 4)
+
+	// The backoff grows with every failure of the run and is capped at the max.
+	for i, backOff := range []time.Duration{50 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond} {
+		require.GreaterOrEqual(t, calls[i+1].Sub(calls[i]), backOff)
+	}
+}
+
+func TestRetryPolicyValidation(t *testing.T) {
+	testCases := []struct {
+		name       string
+		initial    time.Duration
+		max        time.Duration
+		multiplier float64
+		jitter     float64
+	}{
+		{
+			name:       "No initial backoff",
+			max:        time.Second,
+			multiplier: 2,
+		},
+		{
+			name:       "Max less than initial",
+			initial:    time.Second,
+			max:        time.Millisecond,
+			multiplier: 2,
+		},
+		{
+			name:       "Multiplier less than 1",
+			initial:    time.Second,
+			max:        time.Minute,
+			multiplier: 0.5,
+		},
+		{
+			name:       "Jitter greater than 1",
+			initial:    time.Second,
+			max:        time.Minute,
+			multiplier: 2,
+			jitter:     1.5,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := workflow.NewBuilder[MyType, status]("retry policy")
+			b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+				return StatusEnd, nil
+			}, StatusEnd).WithOptions(workflow.RetryPolicy(tc.initial, tc.max, tc.multiplier, tc.jitter))
+
+			require.Panics(t, func() {
+				b.Build(memstreamer.New(), memrecordstore.New(), memrolescheduler.New())
+			})
+		})
+	}
+}
@@ -13,13 +13,27 @@ type Run[Type any, Status StatusType] struct {
 	// stopper provides controls over the run state of the record. Run is not serializable and is not
 	// intended to be and thus Record exists as a serializable representation of a record.
 	controller RunStateController
+
+	// codec is used to encode the snapshots of the Object.
+	codec Codec
+
+	// record is the record that the run was built from and store stores it, such as when the result of a side effect
+	// is recorded. Both are nil for runs created using NewTestingRun.
+	record *Record
+	store  storeFunc
+
+	// heartbeat is set for the steps that are configured using WithHeartbeatTimeout.
+	heartbeat func()
+
+	// reasons are the decision reasons recorded using Reason that are persisted with the run's next transition.
+	reasons []DecisionReason
 }
 
 // Pause is intended to be used inside a workflow process where (Status, error) are the return signature. This allows
 // the user to simply type "return r.Pause(ctx)" to pause a record from inside a workflow which results in the record
 // being temporarily left alone and will not be processed until it is resumed.
 func (r *Run[Type, Status]) Pause(ctx context.Context) (Status, error) {
-	err := r.controller.Pause(ctx)
+	err := r.controller.Pause(withDecisionReasons(ctx, r.reasons))
 	if err != nil {
 		return 0, err
 	}
@@ -27,6 +41,18 @@ func (r *Run[Type, Status]) Pause(ctx context.Context) (Status, error) {
 	return Status(SkipTypeRunStateUpdate), nil
 }
 
+// Reason records a structured reason for the decision that the step is making, such as
+// r.Reason("kyc_rejected", map[string]string{"check": "sanctions"}), which is persisted with the transition that the
+// step makes, such as to the next status or by pausing or cancelling the run, and is returned by RunHistory. Reason
+// can be called several times to record each of the reasons of the decision. The reasons are discarded when the step
+// errors or skips the run.
+func (r *Run[Type, Status]) Reason(code string, details map[string]string) {
+	r.reasons = append(r.reasons, DecisionReason{
+		Code:    code,
+		Details: details,
+	})
+}
+
 // Skip is a util function to skip the update and move on to the next event (consumer) or execution (callback)
 func (r *Run[Type, Status]) Skip() (Status, error) {
 	return Status(SkipTypeDefault), nil
@@ -36,7 +62,7 @@ func (r *Run[Type, Status]) Skip() (Status, error) {
 // the user to simply type "return r.Cancel(ctx)" to cancel a record from inside a workflow which results in the record
 // being permanently left alone and will not be processed.
 func (r *Run[Type, Status]) Cancel(ctx context.Context) (Status, error) {
-	err := r.controller.Cancel(ctx)
+	err := r.controller.Cancel(withDecisionReasons(ctx, r.reasons))
 	if err != nil {
 		return 0, err
 	}
@@ -44,9 +70,9 @@ func (r *Run[Type, Status]) Cancel(ctx context.Context) (Status, error) {
 	return Status(SkipTypeRunStateUpdate), nil
 }
 
-func buildRun[Type any, Status StatusType](store storeFunc, wr *Record) (*Run[Type, Status], error) {
+func buildRun[Type any, Status StatusType](store storeFunc, codec Codec, wr *Record) (*Run[Type, Status], error) {
 	var t Type
-	err := Unmarshal(wr.Object, &t)
+	err := codec.Unmarshal(wr.Object, &t)
 	if err != nil {
 		return nil, err
 	}
@@ -66,6 +92,9 @@ func buildRun[Type any, Status StatusType](store storeFunc, wr *Record) (*Run[Ty
 			Object: &t,
 		},
 		controller: controller,
+		codec:      codec,
+		record:     wr,
+		store:      store,
 	}
 
 	return &record, nil
@@ -0,0 +1,45 @@
+package workflow
+
+import "errors"
+
+// ErrConsumerRunMode is returned by the API, such as Trigger, Callback, and Schedule, of workflows built using
+// WithRunMode(RunModeConsumer) as those deployments only host the background consumers.
+var ErrConsumerRunMode = errors.New("workflow does not host the API in consumer run mode")
+
+// RunMode determines which parts of the workflow are hosted by a deployment once Run has been called. Deployments
+// using different modes must be built from the same definition and adapters.
+type RunMode int
+
+const (
+	// RunModeAll hosts the API (Trigger, Callback, Schedule, Await, etc.) as well as all the background consumers.
+	// This is the default.
+	RunModeAll RunMode = 0
+	// RunModeProducer only hosts the API. Run does not start any background consumers, timeouts, or the outbox
+	// consumer. At least one other deployment must be running the workflow using RunModeAll or RunModeConsumer.
+	RunModeProducer RunMode = 1
+	// RunModeConsumer hosts the background consumers, timeouts, hooks, and the outbox consumer. It is intended for
+	// deployments that are not receiving API traffic and can be scaled independently of the API. Trigger, Callback,
+	// and Schedule return ErrConsumerRunMode, whereas the child runs triggered by the steps are still triggered.
+	RunModeConsumer RunMode = 2
+)
+
+func (m RunMode) String() string {
+	switch m {
+	case RunModeAll:
+		return "All"
+	case RunModeProducer:
+		return "Producer"
+	case RunModeConsumer:
+		return "Consumer"
+	default:
+		return "Unknown"
+	}
+}
+
+// WithRunMode allows a deployment to only host the API (RunModeProducer) or only the background consumers
+// (RunModeConsumer) so that the synchronous API path and the async processing path can be scaled independently.
+func WithRunMode(mode RunMode) BuildOption {
+	return func(bo *buildOptions) {
+		bo.runMode = mode
+	}
+}
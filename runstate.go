@@ -17,7 +17,8 @@ const (
 	RunStateCompleted            RunState = 5
 	RunStateDataDeleted          RunState = 6
 	RunStateRequestedDataDeleted RunState = 7
-	runStateSentinel             RunState = 8
+	RunStateQuarantined          RunState = 8
+	runStateSentinel             RunState = 9
 )
 
 func (rs RunState) String() string {
@@ -38,6 +39,8 @@ func (rs RunState) String() string {
 		return "Data Deleted"
 	case RunStateRequestedDataDeleted:
 		return "Requested Data Deleted"
+	case RunStateQuarantined:
+		return "Quarantined"
 	default:
 		return "RunState(" + strconv.FormatInt(int64(rs), 10) + ")"
 	}
@@ -58,10 +61,11 @@ func (rs RunState) Finished() bool {
 
 // Stopped is the type of status that requires consumers to ignore the workflow run as it is in a stopped state. Only
 // paused workflow runs can be resumed and must be done so via the workflow API or the Run methods. All cancelled
-// workflow runs are cancelled permanently and cannot be undone whereas Pausing can be resumed.
+// workflow runs are cancelled permanently and cannot be undone whereas Pausing can be resumed. Quarantined workflow
+// runs can only be moved back into RunStateRunning using ReprocessQuarantined.
 func (rs RunState) Stopped() bool {
 	switch rs {
-	case RunStatePaused, RunStateCancelled, RunStateRequestedDataDeleted, RunStateDataDeleted:
+	case RunStatePaused, RunStateCancelled, RunStateRequestedDataDeleted, RunStateDataDeleted, RunStateQuarantined:
 		return true
 	default:
 		return false
@@ -94,7 +98,7 @@ func NewRunStateController(store storeFunc, wr *Record) RunStateController {
 	}
 }
 
-type customDelete func(wr *Record) ([]byte, error)
+type customDelete func(codec Codec, wr *Record) ([]byte, error)
 
 type runStateControllerImpl struct {
 	record *Record
@@ -130,13 +134,15 @@ func (rsc *runStateControllerImpl) update(ctx context.Context, rs RunState) erro
 
 var runStateTransitions = map[RunState]map[RunState]bool{
 	RunStateInitiated: {
-		RunStateRunning: true,
-		RunStatePaused:  true,
+		RunStateRunning:     true,
+		RunStatePaused:      true,
+		RunStateQuarantined: true,
 	},
 	RunStateRunning: {
-		RunStateCompleted: true,
-		RunStatePaused:    true,
-		RunStateCancelled: true,
+		RunStateCompleted:   true,
+		RunStatePaused:      true,
+		RunStateCancelled:   true,
+		RunStateQuarantined: true,
 	},
 	RunStatePaused: {
 		RunStateRunning:   true,
@@ -154,4 +160,8 @@ var runStateTransitions = map[RunState]map[RunState]bool{
 	RunStateDataDeleted: {
 		RunStateRequestedDataDeleted: true,
 	},
+	RunStateQuarantined: {
+		RunStateRunning:   true,
+		RunStateCancelled: true,
+	},
 }
@@ -319,6 +319,30 @@ func TestRunStateControllerTransitions(t *testing.T) {
 			to:    RunStateCancelled,
 			valid: false,
 		},
+		{
+			name:  "Quarantined to Running [valid]",
+			from:  RunStateQuarantined,
+			to:    RunStateRunning,
+			valid: true,
+		},
+		{
+			name:  "Quarantined to Cancelled [valid]",
+			from:  RunStateQuarantined,
+			to:    RunStateCancelled,
+			valid: true,
+		},
+		{
+			name:  "Quarantined to Paused [invalid]",
+			from:  RunStateQuarantined,
+			to:    RunStatePaused,
+			valid: false,
+		},
+		{
+			name:  "Quarantined to Completed [invalid]",
+			from:  RunStateQuarantined,
+			to:    RunStateCompleted,
+			valid: false,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -196,18 +196,18 @@ func TestIsFinished(t *testing.T) {
 
 func TestRunStateValid(t *testing.T) {
 	testCases := map[workflow.RunState]bool{
-		workflow.RunState(-1):                     false,
-		workflow.RunStateUnknown:                  false,
-		workflow.RunStateInitiated:                true,
-		workflow.RunStateRunning:                  true,
-		workflow.RunStatePaused:                   true,
-		workflow.RunStateCancelled:                true,
-		workflow.RunStateCompleted:                true,
-		workflow.RunStateDataDeleted:              true,
-		workflow.RunStateRequestedDataDeleted:     true,
-		workflow.RunStateRequestedDataDeleted + 1: false,
-		workflow.RunStateRequestedDataDeleted + 2: false,
-		workflow.RunState(9999):                   false,
+		workflow.RunState(-1):                 false,
+		workflow.RunStateUnknown:              false,
+		workflow.RunStateInitiated:            true,
+		workflow.RunStateRunning:              true,
+		workflow.RunStatePaused:               true,
+		workflow.RunStateCancelled:            true,
+		workflow.RunStateCompleted:            true,
+		workflow.RunStateDataDeleted:          true,
+		workflow.RunStateRequestedDataDeleted: true,
+		workflow.RunStateQuarantined:          true,
+		workflow.RunStateQuarantined + 1:      false,
+		workflow.RunState(9999):               false,
 	}
 
 	for state, expected := range testCases {
@@ -217,18 +217,18 @@ func TestRunStateValid(t *testing.T) {
 
 func TestRunStateFinished(t *testing.T) {
 	testCases := map[workflow.RunState]bool{
-		workflow.RunState(-1):                     false,
-		workflow.RunStateUnknown:                  false,
-		workflow.RunStateInitiated:                false,
-		workflow.RunStateRunning:                  false,
-		workflow.RunStatePaused:                   false,
-		workflow.RunStateCancelled:                true,
-		workflow.RunStateCompleted:                true,
-		workflow.RunStateDataDeleted:              true,
-		workflow.RunStateRequestedDataDeleted:     true,
-		workflow.RunStateRequestedDataDeleted + 1: false,
-		workflow.RunStateRequestedDataDeleted + 2: false,
-		workflow.RunState(9999):                   false,
+		workflow.RunState(-1):                 false,
+		workflow.RunStateUnknown:              false,
+		workflow.RunStateInitiated:            false,
+		workflow.RunStateRunning:              false,
+		workflow.RunStatePaused:               false,
+		workflow.RunStateCancelled:            true,
+		workflow.RunStateCompleted:            true,
+		workflow.RunStateDataDeleted:          true,
+		workflow.RunStateRequestedDataDeleted: true,
+		workflow.RunStateQuarantined:          false,
+		workflow.RunStateQuarantined + 1:      false,
+		workflow.RunState(9999):               false,
 	}
 
 	for state, expected := range testCases {
@@ -238,18 +238,18 @@ func TestRunStateFinished(t *testing.T) {
 
 func TestRunStateStopped(t *testing.T) {
 	testCases := map[workflow.RunState]bool{
-		workflow.RunState(-1):                     false,
-		workflow.RunStateUnknown:                  false,
-		workflow.RunStateInitiated:                false,
-		workflow.RunStateRunning:                  false,
-		workflow.RunStatePaused:                   true,
-		workflow.RunStateCancelled:                true,
-		workflow.RunStateCompleted:                false,
-		workflow.RunStateDataDeleted:              true,
-		workflow.RunStateRequestedDataDeleted:     true,
-		workflow.RunStateRequestedDataDeleted + 1: false,
-		workflow.RunStateRequestedDataDeleted + 2: false,
-		workflow.RunState(9999):                   false,
+		workflow.RunState(-1):                 false,
+		workflow.RunStateUnknown:              false,
+		workflow.RunStateInitiated:            false,
+		workflow.RunStateRunning:              false,
+		workflow.RunStatePaused:               true,
+		workflow.RunStateCancelled:            true,
+		workflow.RunStateCompleted:            false,
+		workflow.RunStateDataDeleted:          true,
+		workflow.RunStateRequestedDataDeleted: true,
+		workflow.RunStateQuarantined:          true,
+		workflow.RunStateQuarantined + 1:      false,
+		workflow.RunState(9999):               false,
 	}
 
 	for state, expected := range testCases {
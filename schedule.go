@@ -21,12 +21,19 @@ func (w *Workflow[Type, Status]) Schedule(
 		return fmt.Errorf("schedule failed: workflow is not running")
 	}
 
+	if w.runMode == RunModeConsumer {
+		return fmt.Errorf("schedule failed: %w", ErrConsumerRunMode)
+	}
+
 	if !w.statusGraph.IsValid(int(startingStatus)) {
-		w.logger.Debug(
-			w.ctx,
-			fmt.Sprintf("ensure %v is configured for workflow: %v", startingStatus, w.Name()),
-			map[string]string{},
-		)
+		w.logger.event(w.ctx, DebugEvent{
+			Type:    DebugEventStatusNotConfigured,
+			Message: fmt.Sprintf("ensure %v is configured for workflow: %v", startingStatus, w.Name()),
+			Meta: map[string]string{
+				"workflow_name":   w.Name(),
+				"starting_status": startingStatus.String(),
+			},
+		})
 
 		return fmt.Errorf("schedule failed: status provided is not configured for workflow: %s", startingStatus)
 	}
@@ -45,7 +52,7 @@ func (w *Workflow[Type, Status]) Schedule(
 	processName := makeRole(startingStatus.String(), foreignID, "scheduler", spec)
 
 	w.launching.Add(1)
-	w.run(role, processName, func(ctx context.Context) error {
+	w.run(role, processName, shutdownOrderFirst, func(ctx context.Context) error {
 		latestEntry, err := w.recordStore.Latest(ctx, w.Name(), foreignID)
 		if errors.Is(err, ErrRecordNotFound) {
 			// NoReturnErr: Rather use zero value for lastRunID and use current clock for first run.
@@ -74,6 +81,10 @@ func (w *Workflow[Type, Status]) Schedule(
 			tOpts = append(tOpts, WithInitialValue[Type, Status](options.initialValue))
 		}
 
+		if options.dedupWindow > 0 {
+			tOpts = append(tOpts, WithDedupWindow[Type, Status](options.dedupWindow))
+		}
+
 		// If a filter has been provided then allow the ability to skip scheduling when false is returned along with
 		// a nil error.
 		var shouldTrigger bool
@@ -122,6 +133,7 @@ func waitUntil(ctx context.Context, clock clock.Clock, until time.Time) error {
 type scheduleOpts[Type any, Status StatusType] struct {
 	initialValue   *Type
 	scheduleFilter func(ctx context.Context) (bool, error)
+	dedupWindow    time.Duration
 }
 
 type ScheduleOption[Type any, Status StatusType] func(o *scheduleOpts[Type, Status])
@@ -139,3 +151,12 @@ func WithScheduleFilter[Type any, Status StatusType](
 		o.scheduleFilter = fn
 	}
 }
+
+// WithScheduleDedupWindow prevents the schedule from creating a new run if the latest run of the foreignID was created
+// with the same starting status within the window. This protects against misfires, such as a schedule firing more
+// than once after a restart, creating duplicate runs. See WithDedupWindow.
+func WithScheduleDedupWindow[Type any, Status StatusType](window time.Duration) ScheduleOption[Type, Status] {
+	return func(o *scheduleOpts[Type, Status]) {
+		o.dedupWindow = window
+	}
+}
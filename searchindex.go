@@ -0,0 +1,148 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+)
+
+// SearchIndexFunc returns the value of the search index for the run's Object, such as the ID of the customer.
+type SearchIndexFunc[Type any] func(object *Type) string
+
+type searchIndex struct {
+	name string
+	// fn is a SearchIndexFunc of the workflow's Type which is only known at Build.
+	fn any
+}
+
+// WithSearchIndex adds a search index whose value is extracted from the run's Object using fn every time the run is
+// stored. The values are stored in the record's Meta.SearchIndex so that a RecordStore that implements
+// SearchableRecordStore can store them in queryable columns and SearchRuns can look up runs by their business
+// attributes without scanning and unmarshalling every Object. Runs that were stored before the search index was added
+// are only found once they are stored again. The option can be provided once for each search index.
+func WithSearchIndex[Type any](name string, fn SearchIndexFunc[Type]) BuildOption {
+	return func(bo *buildOptions) {
+		bo.searchIndexes = append(bo.searchIndexes, searchIndex{name: name, fn: fn})
+	}
+}
+
+// SearchableRecordStore can optionally be implemented by a RecordStore to support the search indexes configured using
+// WithSearchIndex. Implementations should all be tested with adaptertest.RunSearchableRecordStoreTest.
+type SearchableRecordStore interface {
+	// Search returns the workflow's records whose value of the search index, as provided in the record's
+	// Meta.SearchIndex when the record was stored, matches the value. The records are ordered by when they were
+	// created.
+	Search(
+		ctx context.Context,
+		workflowName string,
+		index string,
+		value string,
+		offset int64,
+		limit int,
+	) ([]Record, error)
+}
+
+// buildSearchIndexes converts the untyped search indexes provided via the BuildOptions into the workflow's Type.
+func buildSearchIndexes[Type any](indexes []searchIndex) map[string]SearchIndexFunc[Type] {
+	if len(indexes) == 0 {
+		return nil
+	}
+
+	typed := make(map[string]SearchIndexFunc[Type])
+	for _, index := range indexes {
+		if index.name == "" {
+			panic("search indexes need to be named")
+		}
+
+		if _, ok := typed[index.name]; ok {
+			panic("search index '" + index.name + "' names need to be unique")
+		}
+
+		fn, ok := index.fn.(SearchIndexFunc[Type])
+		if !ok {
+			panic("search index '" + index.name + "' must have the same Type as the workflow")
+		}
+
+		typed[index.name] = fn
+	}
+
+	return typed
+}
+
+// SearchRuns returns the workflow's runs whose value of the search index, configured using WithSearchIndex, matches
+// the value. The runs are ordered by when they were created and limit defaults to 100.
+func (w *Workflow[Type, Status]) SearchRuns(
+	ctx context.Context,
+	index string,
+	value string,
+	offset int64,
+	limit int,
+) ([]TypedRecord[Type, Status], error) {
+	if _, ok := w.searchIndexes[index]; !ok {
+		return nil, fmt.Errorf("search runs: search index not found, meta: %v", map[string]string{
+			"workflow_name": w.Name(),
+			"index":         index,
+		})
+	}
+
+	if limit <= 0 {
+		limit = defaultListRunsLimit
+	}
+
+	// The RecordStore is checked to implement SearchableRecordStore when the workflow is built.
+	store := unwrapRecordStore(w.recordStore).(SearchableRecordStore)
+	records, err := store.Search(ctx, w.Name(), index, value, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("search runs: %w, meta: %v", err, map[string]string{
+			"index": index,
+		})
+	}
+
+	runs := make([]TypedRecord[Type, Status], 0, len(records))
+	for _, record := range records {
+		var t Type
+		err := w.codec.Unmarshal(record.Object, &t)
+		if err != nil {
+			return nil, err
+		}
+
+		runs = append(runs, TypedRecord[Type, Status]{
+			Record: record,
+			Status: Status(record.Status),
+			Object: &t,
+		})
+	}
+
+	return runs, nil
+}
+
+// searchIndexRecordStore sets the values of the workflow's search indexes on the records that are stored.
+type searchIndexRecordStore[Type any, Status StatusType] struct {
+	RecordStore
+	workflow *Workflow[Type, Status]
+}
+
+func (s *searchIndexRecordStore[Type, Status]) Store(ctx context.Context, record *Record) error {
+	if record.WorkflowName != s.workflow.Name() {
+		return s.RecordStore.Store(ctx, record)
+	}
+
+	var t Type
+	err := s.workflow.codec.Unmarshal(record.Object, &t)
+	if err != nil {
+		// Runs whose Object cannot be unmarshalled, such as those being quarantined, keep their previous values.
+		return s.RecordStore.Store(ctx, record)
+	}
+
+	values := make(map[string]string, len(s.workflow.searchIndexes))
+	for name, fn := range s.workflow.searchIndexes {
+		values[name] = fn(&t)
+	}
+
+	record.Meta.SearchIndex = values
+
+	return s.RecordStore.Store(ctx, record)
+}
+
+func (s *searchIndexRecordStore[Type, Status]) Unwrap() RecordStore {
+	return s.RecordStore
+}
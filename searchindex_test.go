@@ -0,0 +1,112 @@
+package workflow_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestSearchRuns(t *testing.T) {
+	b := workflow.NewBuilder[MyType, status]("search runs")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		r.Object.Email = r.Object.Name + "@example.com"
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+		workflow.WithSearchIndex("user_id", func(o *MyType) string {
+			return strconv.FormatInt(o.UserID, 10)
+		}),
+		workflow.WithSearchIndex("email", func(o *MyType) string {
+			return o.Email
+		}),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	var runIDs []string
+	for i, name := range []string{"andrew", "bob", "andrew"} {
+		foreignID := "foreignID-" + strconv.Itoa(i)
+		runID, err := wf.Trigger(ctx, foreignID, StatusStart, workflow.WithInitialValue[MyType, status](&MyType{
+			UserID: int64(len(name)),
+			Name:   name,
+		}))
+		require.Nil(t, err)
+
+		_, err = wf.Await(ctx, foreignID, runID, StatusEnd)
+		require.Nil(t, err)
+
+		runIDs = append(runIDs, runID)
+	}
+
+	runs, err := wf.SearchRuns(ctx, "user_id", "6", 0, 0)
+	require.Nil(t, err)
+	require.Len(t, runs, 2)
+	require.Equal(t, runIDs[0], runs[0].RunID)
+	require.Equal(t, runIDs[2], runs[1].RunID)
+	require.Equal(t, "andrew", runs[0].Object.Name)
+
+	// The values of the search indexes are updated as the steps update the Object.
+	runs, err = wf.SearchRuns(ctx, "email", "bob@example.com", 0, 0)
+	require.Nil(t, err)
+	require.Len(t, runs, 1)
+	require.Equal(t, runIDs[1], runs[0].RunID)
+	require.Equal(t, StatusEnd, runs[0].Status)
+
+	runs, err = wf.SearchRuns(ctx, "user_id", "6", 1, 1)
+	require.Nil(t, err)
+	require.Len(t, runs, 1)
+	require.Equal(t, runIDs[2], runs[0].RunID)
+
+	_, err = wf.SearchRuns(ctx, "unknown", "6", 0, 0)
+	require.ErrorContains(t, err, "search index not found")
+}
+
+func TestWithSearchIndexValidation(t *testing.T) {
+	testCases := []struct {
+		name     string
+		opts     []workflow.BuildOption
+		expected string
+	}{
+		{
+			name: "Duplicate names",
+			opts: []workflow.BuildOption{
+				workflow.WithSearchIndex("name", func(o *MyType) string { return o.Name }),
+				workflow.WithSearchIndex("name", func(o *MyType) string { return o.Email }),
+			},
+			expected: "search index 'name' names need to be unique",
+		},
+		{
+			name: "Different Type",
+			opts: []workflow.BuildOption{
+				workflow.WithSearchIndex("name", func(o *string) string { return *o }),
+			},
+			expected: "search index 'name' must have the same Type as the workflow",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := workflow.NewBuilder[MyType, status]("search runs")
+			b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+				return StatusEnd, nil
+			}, StatusEnd)
+
+			require.PanicsWithValue(t, tc.expected, func() {
+				b.Build(memstreamer.New(), memrecordstore.New(), memrolescheduler.New(), tc.opts...)
+			})
+		})
+	}
+}
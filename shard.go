@@ -0,0 +1,160 @@
+package workflow
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/luno/workflow/internal/metrics"
+)
+
+// hotShardFactor is how many times more events than the average shard of the consumer a shard needs to have
+// processed to be considered hot.
+const hotShardFactor = 2
+
+// Stats provides the statistics of the workflow's processes that are running on this instance.
+type Stats struct {
+	// Shards holds the statistics of each shard of the step consumers, ordered by process name.
+	Shards []ShardStats
+	// HotShards are the shards that have processed more than twice the events of the average shard of the same
+	// consumer. Hot shards indicate that events are not evenly distributed across the shards of the consumer.
+	HotShards []ShardStats
+}
+
+// ShardStats are the statistics of a single shard of a step consumer. Consumers configured with a ParallelCount of
+// less than 2 have a single shard.
+type ShardStats struct {
+	// ProcessName is the process name of the consumer that the shard belongs to and excludes the shard.
+	ProcessName string
+	Status      int
+	Shard       int
+	TotalShards int
+	// ProcessedEvents is the number of events that have been processed by the shard since the workflow was run.
+	ProcessedEvents int64
+	// Lag is the age of the last event processed by the shard when it was processed.
+	Lag time.Duration
+	// Hot is true when the shard has processed more than twice the events of the average shard of the consumer.
+	Hot bool
+}
+
+// Stats returns the statistics of the workflow's processes that are running on this instance.
+func (w *Workflow[Type, Status]) Stats() Stats {
+	w.shardStatsMu.Lock()
+	defer w.shardStatsMu.Unlock()
+
+	processedByConsumer := make(map[string]int64)
+	for _, s := range w.shardStats {
+		processedByConsumer[s.ProcessName] += s.ProcessedEvents
+	}
+
+	var stats Stats
+	for _, s := range w.shardStats {
+		shard := *s
+		if shard.TotalShards > 1 {
+			average := float64(processedByConsumer[shard.ProcessName]) / float64(shard.TotalShards)
+			shard.Hot = float64(shard.ProcessedEvents) > average*hotShardFactor
+		}
+
+		stats.Shards = append(stats.Shards, shard)
+		if shard.Hot {
+			stats.HotShards = append(stats.HotShards, shard)
+		}
+	}
+
+	compare := func(a, b ShardStats) int {
+		if a.ProcessName != b.ProcessName {
+			if a.ProcessName < b.ProcessName {
+				return -1
+			}
+
+			return 1
+		}
+
+		return a.Shard - b.Shard
+	}
+	slices.SortFunc(stats.Shards, compare)
+	slices.SortFunc(stats.HotShards, compare)
+
+	return stats
+}
+
+// observeShard records the processing of the events by the shard and exports the shard's lag and throughput
+// metrics when the consumer has more than one shard.
+func (w *Workflow[Type, Status]) observeShard(
+	processName string,
+	status Status,
+	shard, totalShards int,
+	events ...*Event,
+) {
+	if len(events) == 0 {
+		return
+	}
+
+	lag := w.clock.Since(events[len(events)-1].CreatedAt)
+
+	w.shardStatsMu.Lock()
+	if w.shardStats == nil {
+		w.shardStats = make(map[string]*ShardStats)
+	}
+
+	key := makeRole(processName, strconv.Itoa(shard))
+	s, ok := w.shardStats[key]
+	if !ok {
+		s = &ShardStats{
+			ProcessName: processName,
+			Status:      int(status),
+			Shard:       shard,
+			TotalShards: totalShards,
+		}
+		w.shardStats[key] = s
+	}
+
+	s.ProcessedEvents += int64(len(events))
+	s.Lag = lag
+	w.shardStatsMu.Unlock()
+
+	if totalShards < 2 {
+		return
+	}
+
+	shardLabel := strconv.Itoa(shard)
+	metrics.ShardLag.WithLabelValues(w.Name(), processName, shardLabel).Set(lag.Seconds())
+	metrics.ShardProcessedEvents.WithLabelValues(w.Name(), processName, shardLabel).Add(float64(len(events)))
+}
+
+// shardObserved records the events successfully processed by the shard's consumer.
+func (w *Workflow[Type, Status]) shardObserved(
+	processName string,
+	status Status,
+	shard, totalShards int,
+	fn func(ctx context.Context, e *Event) error,
+) func(ctx context.Context, e *Event) error {
+	return func(ctx context.Context, e *Event) error {
+		err := fn(ctx, e)
+		if err != nil {
+			return err
+		}
+
+		w.observeShard(processName, status, shard, totalShards, e)
+		return nil
+	}
+}
+
+// shardObservedBatch records the batches of events successfully processed by the shard's batch consumer.
+func (w *Workflow[Type, Status]) shardObservedBatch(
+	processName string,
+	status Status,
+	shard, totalShards int,
+	fn func(ctx context.Context, events []*Event) error,
+) func(ctx context.Context, events []*Event) error {
+	return func(ctx context.Context, events []*Event) error {
+		err := fn(ctx, events)
+		if err != nil {
+			return err
+		}
+
+		w.observeShard(processName, status, shard, totalShards, events...)
+		return nil
+	}
+}
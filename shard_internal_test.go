@@ -0,0 +1,94 @@
+package workflow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	clock_testing "k8s.io/utils/clock/testing"
+
+	"github.com/luno/workflow/internal/metrics"
+)
+
+func TestStats_hotShards(t *testing.T) {
+	metrics.ShardLag.Reset()
+	metrics.ShardProcessedEvents.Reset()
+	t.Cleanup(func() {
+		metrics.ShardLag.Reset()
+		metrics.ShardProcessedEvents.Reset()
+	})
+
+	now := time.Date(2024, time.April, 19, 0, 0, 0, 0, time.UTC)
+	w := Workflow[string, testStatus]{
+		name:  "shard stats",
+		clock: clock_testing.NewFakeClock(now),
+	}
+
+	require.Equal(t, Stats{}, w.Stats())
+
+	event := func(age time.Duration) *Event {
+		return &Event{CreatedAt: now.Add(-age)}
+	}
+
+	// Shard 1 processes most of the events.
+	for i := 0; i < 10; i++ {
+		w.observeShard("start-consumer", statusStart, 1, 3, event(time.Minute))
+	}
+	w.observeShard("start-consumer", statusStart, 2, 3, event(time.Second))
+	w.observeShard("start-consumer", statusStart, 3, 3, event(time.Second))
+
+	// Consumers with a single shard are never hot.
+	w.observeShard("middle-consumer", statusMiddle, 1, 1, event(time.Second), event(time.Second))
+
+	hot := ShardStats{
+		ProcessName:     "start-consumer",
+		Status:          int(statusStart),
+		Shard:           1,
+		TotalShards:     3,
+		ProcessedEvents: 10,
+		Lag:             time.Minute,
+		Hot:             true,
+	}
+
+	require.Equal(t, Stats{
+		Shards: []ShardStats{
+			{
+				ProcessName:     "middle-consumer",
+				Status:          int(statusMiddle),
+				Shard:           1,
+				TotalShards:     1,
+				ProcessedEvents: 2,
+				Lag:             time.Second,
+			},
+			hot,
+			{
+				ProcessName:     "start-consumer",
+				Status:          int(statusStart),
+				Shard:           2,
+				TotalShards:     3,
+				ProcessedEvents: 1,
+				Lag:             time.Second,
+			},
+			{
+				ProcessName:     "start-consumer",
+				Status:          int(statusStart),
+				Shard:           3,
+				TotalShards:     3,
+				ProcessedEvents: 1,
+				Lag:             time.Second,
+			},
+		},
+		HotShards: []ShardStats{hot},
+	}, w.Stats())
+
+	// Metrics are only exported for consumers with more than one shard.
+	processed := metrics.ShardProcessedEvents.WithLabelValues("shard stats", "start-consumer", "1")
+	require.Equal(t, 10.0, testutil.ToFloat64(processed))
+
+	lag := metrics.ShardLag.WithLabelValues("shard stats", "start-consumer", "1")
+	require.Equal(t, time.Minute.Seconds(), testutil.ToFloat64(lag))
+
+	processed = metrics.ShardProcessedEvents.WithLabelValues("shard stats", "middle-consumer", "1")
+	require.Equal(t, 0.0, testutil.ToFloat64(processed))
+}
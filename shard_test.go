@@ -0,0 +1,142 @@
+package workflow_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestStats(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("stats")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd).WithOptions(
+		workflow.ParallelCount(3),
+		workflow.PollingFrequency(10*time.Millisecond),
+	)
+
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	const runs = 9
+	for i := 0; i < runs; i++ {
+		_, err := wf.Trigger(ctx, strconv.Itoa(i), StatusStart)
+		require.Nil(t, err)
+	}
+
+	require.Eventually(t, func() bool {
+		var processed int64
+		for _, shard := range wf.Stats().Shards {
+			processed += shard.ProcessedEvents
+		}
+
+		return processed == runs
+	}, 5*time.Second, 10*time.Millisecond)
+
+	stats := wf.Stats()
+	require.Len(t, stats.Shards, 3)
+
+	for i, shard := range stats.Shards {
+		require.Equal(t, "start-consumer", shard.ProcessName)
+		require.Equal(t, int(StatusStart), shard.Status)
+		require.Equal(t, i+1, shard.Shard)
+		require.Equal(t, 3, shard.TotalShards)
+	}
+}
+
+// shardedStreamer provides receivers that assign the events to shards themselves, in the reverse order to workflow,
+// so that the events are never consumed if workflow also filters the events by shard.
+type shardedStreamer struct {
+	workflow.EventStreamer
+}
+
+func (s *shardedStreamer) NewReceiver(
+	ctx context.Context,
+	topic string,
+	name string,
+	opts ...workflow.ReceiverOption,
+) (workflow.EventReceiver, error) {
+	var options workflow.ReceiverOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	receiver, err := s.EventStreamer.NewReceiver(ctx, topic, name, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &shardedReceiver{EventReceiver: receiver, options: options}, nil
+}
+
+type shardedReceiver struct {
+	workflow.EventReceiver
+
+	options workflow.ReceiverOptions
+}
+
+func (r *shardedReceiver) Recv(ctx context.Context) (*workflow.Event, workflow.Ack, error) {
+	for {
+		e, ack, err := r.EventReceiver.Recv(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if r.options.TotalShards < 2 || e.ID%int64(r.options.TotalShards) == int64(r.options.TotalShards-r.options.Shard) {
+			return e, ack, nil
+		}
+
+		err = ack()
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+}
+
+func (r *shardedReceiver) Sharded() bool {
+	return r.options.TotalShards > 1
+}
+
+func TestShardedReceiver(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("sharded receiver")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd).WithOptions(
+		workflow.ParallelCount(2),
+		workflow.PollingFrequency(10*time.Millisecond),
+	)
+
+	wf := b.Build(
+		&shardedStreamer{EventStreamer: memstreamer.New()},
+		memrecordstore.New(),
+		memrolescheduler.New(),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	for i := 0; i < 4; i++ {
+		foreignID := strconv.Itoa(i)
+		runID, err := wf.Trigger(ctx, foreignID, StatusStart)
+		require.Nil(t, err)
+
+		_, err = wf.Await(ctx, foreignID, runID, StatusEnd, workflow.WithAwaitPollingFrequency(10*time.Millisecond))
+		require.Nil(t, err)
+	}
+}
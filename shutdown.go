@@ -0,0 +1,135 @@
+package workflow
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// ShutdownOrderFunc customises the order in which processes are stopped when Stop is called. It is provided with the
+// name of the process, as found in States, and its default order and returns the order to use instead. All processes
+// with a lower order are stopped, and have exited, before processes with a higher order are stopped.
+//
+// By default, connectors and schedules are stopped first, followed by the step consumers and timeouts in the order
+// of their status in the workflow's graph (upstream before downstream), followed by hooks and the delete consumer,
+// and lastly the outbox consumer so that all events from the stopped processes are published.
+type ShutdownOrderFunc func(processName string, defaultOrder int) int
+
+// WithShutdownOrder allows customising the order in which processes are stopped when Stop is called.
+func WithShutdownOrder(fn ShutdownOrderFunc) BuildOption {
+	return func(bo *buildOptions) {
+		bo.shutdownOrder = fn
+	}
+}
+
+// shutdownOrderFirst is the default order of processes that create new runs such as connectors and schedules.
+const shutdownOrderFirst = 0
+
+// statusShutdownOrder returns the default order of the processes that consume the provided status.
+func (w *Workflow[Type, Status]) statusShutdownOrder(status Status) int {
+	return w.statusGraph.Depths()[int(status)] + 1
+}
+
+// hookShutdownOrder returns the default order of processes that respond to changes in run state.
+func (w *Workflow[Type, Status]) hookShutdownOrder() int {
+	var maxDepth int
+	for _, depth := range w.statusGraph.Depths() {
+		maxDepth = max(maxDepth, depth)
+	}
+
+	return maxDepth + 2
+}
+
+// outboxShutdownOrder returns the default order of the outbox consumer which is always stopped last.
+func (w *Workflow[Type, Status]) outboxShutdownOrder() int {
+	return w.hookShutdownOrder() + 1
+}
+
+type shutdownStage struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+type shutdownStages struct {
+	mu     sync.Mutex
+	stages map[int]shutdownStage
+	orders map[string]int
+}
+
+// stageContext returns the context that the process should run with. The context is cancelled when Stop reaches the
+// order of the process.
+func (w *Workflow[Type, Status]) stageContext(processName string, order int) context.Context {
+	if w.shutdownOrder != nil {
+		order = w.shutdownOrder(processName, order)
+	}
+
+	w.stages.mu.Lock()
+	defer w.stages.mu.Unlock()
+
+	if w.stages.stages == nil {
+		w.stages.stages = make(map[int]shutdownStage)
+		w.stages.orders = make(map[string]int)
+	}
+
+	w.stages.orders[processName] = order
+
+	stage, ok := w.stages.stages[order]
+	if !ok {
+		ctx, cancel := context.WithCancel(w.ctx)
+		stage = shutdownStage{ctx: ctx, cancel: cancel}
+		w.stages.stages[order] = stage
+	}
+
+	return stage.ctx
+}
+
+// stopStages cancels each stage in order and waits for all of its processes to exit before moving onto the next.
+func (w *Workflow[Type, Status]) stopStages() {
+	w.stages.mu.Lock()
+	orders := make([]int, 0, len(w.stages.stages))
+	for order := range w.stages.stages {
+		orders = append(orders, order)
+	}
+	w.stages.mu.Unlock()
+
+	slices.Sort(orders)
+
+	for _, order := range orders {
+		w.stages.mu.Lock()
+		stage := w.stages.stages[order]
+		w.stages.mu.Unlock()
+
+		stage.cancel()
+
+		w.awaitShutdown(func(processName string) bool {
+			w.stages.mu.Lock()
+			defer w.stages.mu.Unlock()
+
+			return w.stages.orders[processName] == order
+		})
+	}
+}
+
+// awaitShutdown blocks until all the processes that match have exited.
+func (w *Workflow[Type, Status]) awaitShutdown(match func(processName string) bool) {
+	for {
+		var runningProcesses int
+		for processName, state := range w.States() {
+			if !match(processName) {
+				continue
+			}
+
+			switch state {
+			case StateUnknown, StateShutdown:
+				continue
+			default:
+				runningProcesses++
+			}
+		}
+
+		// Once all processes have exited then return
+		if runningProcesses == 0 {
+			return
+		}
+	}
+}
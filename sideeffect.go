@@ -0,0 +1,52 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"maps"
+)
+
+// SideEffect calls fn, which should perform an externally visible action such as charging a card or sending an email,
+// and records its result with the run in the RecordStore before returning it. Calling SideEffect again with the same
+// key, such as when the step is retried after an error or the run is replayed using ReplayRun, returns the recorded
+// result without calling fn. Errors returned by fn are not recorded and so fn is called again on the next attempt.
+//
+// The result is recorded as soon as fn succeeds, rather than when the step moves the run onto its next status, which
+// publishes an event for the run's current status that the step skips once the run has moved on. Keys only need to be
+// unique within a run and should be named after the action, such as "charge-card". Runs created using NewTestingRun
+// keep the results in memory.
+func (r *Run[Type, Status]) SideEffect(ctx context.Context, key string, fn func() ([]byte, error)) ([]byte, error) {
+	if result, ok := r.Meta.SideEffects[key]; ok {
+		return result, nil
+	}
+
+	result, err := fn()
+	if err != nil {
+		return nil, err
+	}
+
+	// The side effects are copied as the run's Meta may be shared with the record it was built from.
+	sideEffects := maps.Clone(r.Meta.SideEffects)
+	if sideEffects == nil {
+		sideEffects = make(map[string][]byte)
+	}
+
+	sideEffects[key] = result
+	r.Meta.SideEffects = sideEffects
+
+	if r.store == nil {
+		return result, nil
+	}
+
+	r.record.Meta.SideEffects = sideEffects
+	err = r.store(ctx, r.record)
+	if err != nil {
+		return nil, fmt.Errorf("store side effect: %w, meta: %v", err, map[string]string{
+			"run_id":     r.RunID,
+			"foreign_id": r.ForeignID,
+			"key":        key,
+		})
+	}
+
+	return result, nil
+}
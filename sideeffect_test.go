@@ -0,0 +1,88 @@
+package workflow_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestSideEffect(t *testing.T) {
+	var (
+		charges  atomic.Int64
+		attempts atomic.Int64
+	)
+
+	b := workflow.NewBuilder[string, status]("side effect")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		result, err := r.SideEffect(ctx, "charge-card", func() ([]byte, error) {
+			return []byte("charge-" + strconv.FormatInt(charges.Add(1), 10)), nil
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		// The step fails after charging the card so that the charge is retried.
+		if attempts.Add(1) == 1 {
+			return 0, errors.New("downstream unavailable")
+		}
+
+		*r.Object = string(result)
+		return StatusEnd, nil
+	}, StatusEnd).WithOptions(
+		workflow.ErrBackOff(time.Millisecond),
+	)
+
+	recordStore := memrecordstore.New()
+	wf := b.Build(memstreamer.New(), recordStore, memrolescheduler.New())
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "foreignID", StatusStart)
+	require.Nil(t, err)
+
+	run, err := wf.Await(ctx, "foreignID", runID, StatusEnd)
+	require.Nil(t, err)
+	require.Equal(t, "charge-1", *run.Object)
+	require.Equal(t, int64(1), charges.Load())
+	require.Equal(t, []byte("charge-1"), run.Meta.SideEffects["charge-card"])
+}
+
+func TestSideEffectError(t *testing.T) {
+	r := workflow.NewTestingRun[string, status](t, workflow.Record{}, "")
+	ctx := context.Background()
+
+	var calls int
+	_, err := r.SideEffect(ctx, "send-email", func() ([]byte, error) {
+		calls++
+		return nil, errors.New("smtp unavailable")
+	})
+	require.ErrorContains(t, err, "smtp unavailable")
+
+	// Errors are not recorded and so the side effect is called again.
+	result, err := r.SideEffect(ctx, "send-email", func() ([]byte, error) {
+		calls++
+		return []byte("sent"), nil
+	})
+	require.Nil(t, err)
+	require.Equal(t, []byte("sent"), result)
+
+	result, err = r.SideEffect(ctx, "send-email", func() ([]byte, error) {
+		calls++
+		return []byte("sent again"), nil
+	})
+	require.Nil(t, err)
+	require.Equal(t, []byte("sent"), result)
+	require.Equal(t, 2, calls)
+}
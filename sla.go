@@ -0,0 +1,90 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"k8s.io/utils/clock"
+)
+
+// ErrSLABreached is returned by a step, configured using DeadlineFromSLA, whose run has not moved on from the step's
+// status within the status' SLA.
+var ErrSLABreached = errors.New("sla breached")
+
+// SLA defines how long a run is expected to take to move on from the step's status, measured from when the run
+// entered the status. See DeadlineFromSLA to bound the step by the time that remains of the SLA.
+func SLA(d time.Duration) Option {
+	return func(opt *options) {
+		opt.sla = d
+	}
+}
+
+// DeadlineFromSLA sets the deadline of the context provided to the step to the time that the run's SLA, configured
+// using SLA, expires. The step gets less time the longer the run has been waiting in the status, such as after
+// retries, so that slow downstream calls are cut off before the SLA is breached rather than after. A step that returns
+// after the deadline returns ErrSLABreached, regardless of its result, and a run that has already breached its SLA
+// returns ErrSLABreached without calling the step so that PauseAfterErrCount can be used to hand the run over to an
+// operator. DeadlineFromSLA is not supported by batch steps.
+func DeadlineFromSLA() Option {
+	return func(opt *options) {
+		opt.deadlineFromSLA = true
+	}
+}
+
+// slaDeadlineConsumer calls the consumer with a context that is cancelled once the run's SLA expires.
+func slaDeadlineConsumer[Type any, Status StatusType](
+	clock clock.Clock,
+	sla time.Duration,
+	consumer ConsumerFunc[Type, Status],
+) ConsumerFunc[Type, Status] {
+	if sla <= 0 {
+		return consumer
+	}
+
+	return func(ctx context.Context, r *Run[Type, Status]) (Status, error) {
+		expiresAt := statusEnteredAt(&r.Record).Add(sla)
+		remaining := expiresAt.Sub(clock.Now())
+		if remaining <= 0 {
+			return 0, fmt.Errorf("%w, meta: %v", ErrSLABreached, map[string]string{
+				"run_id":     r.RunID,
+				"sla":        sla.String(),
+				"expired_at": expiresAt.String(),
+			})
+		}
+
+		stepCtx, cancel := context.WithTimeoutCause(ctx, remaining, ErrSLABreached)
+		defer cancel()
+
+		next, err := consumer(stepCtx, r)
+		if errors.Is(context.Cause(stepCtx), ErrSLABreached) {
+			return 0, fmt.Errorf("%w, meta: %v", ErrSLABreached, map[string]string{
+				"run_id":     r.RunID,
+				"sla":        sla.String(),
+				"expired_at": expiresAt.String(),
+			})
+		}
+
+		return next, err
+	}
+}
+
+// statusEnteredAt returns when the run entered its current status according to its history, ignoring the changes to
+// the run's run state, such as pausing and resuming the run, that have been made since. The time that the record was
+// last updated is returned when the transition is no longer in the run's history.
+func statusEnteredAt(r *Record) time.Time {
+	for i := len(r.Meta.History) - 1; i >= 0; i-- {
+		t := r.Meta.History[i]
+		if t.ToStatus != r.Status {
+			break
+		}
+
+		runStateChange := t.FromStatus == t.ToStatus && t.FromRunState != t.ToRunState && !t.Forced && !t.Replay
+		if !runStateChange {
+			return t.At
+		}
+	}
+
+	return r.UpdatedAt
+}
@@ -0,0 +1,73 @@
+package workflow
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusEnteredAt(t *testing.T) {
+	start := time.Date(2024, time.April, 9, 0, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time {
+		return start.Add(time.Duration(minutes) * time.Minute)
+	}
+
+	testCases := []struct {
+		name    string
+		history []Transition
+		want    time.Time
+	}{
+		{
+			name: "Created",
+			history: []Transition{
+				{ToStatus: 1, ToRunState: RunStateInitiated, At: at(0)},
+				{FromStatus: 1, ToStatus: 1, FromRunState: RunStateInitiated, ToRunState: RunStateRunning, At: at(1)},
+			},
+			want: at(0),
+		},
+		{
+			name: "Paused and resumed",
+			history: []Transition{
+				{ToStatus: 2, ToRunState: RunStateInitiated, At: at(0)},
+				{FromStatus: 2, ToStatus: 1, FromRunState: RunStateRunning, ToRunState: RunStateRunning, At: at(5)},
+				{FromStatus: 1, ToStatus: 1, FromRunState: RunStateRunning, ToRunState: RunStatePaused, At: at(6)},
+				{FromStatus: 1, ToStatus: 1, FromRunState: RunStatePaused, ToRunState: RunStateRunning, At: at(7)},
+			},
+			want: at(5),
+		},
+		{
+			name: "Moved onto the same status",
+			history: []Transition{
+				{ToStatus: 1, ToRunState: RunStateInitiated, At: at(0)},
+				{FromStatus: 1, ToStatus: 1, FromRunState: RunStateRunning, ToRunState: RunStateRunning, At: at(3)},
+			},
+			want: at(3),
+		},
+		{
+			name: "Forced",
+			history: []Transition{
+				{ToStatus: 1, ToRunState: RunStateInitiated, At: at(0)},
+				{FromStatus: 1, ToStatus: 1, FromRunState: RunStatePaused, ToRunState: RunStateRunning, At: at(4), Forced: true},
+			},
+			want: at(4),
+		},
+		{
+			name: "Transition no longer in history",
+			history: []Transition{
+				{FromStatus: 1, ToStatus: 1, FromRunState: RunStateRunning, ToRunState: RunStatePaused, At: at(6)},
+			},
+			want: at(9),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &Record{
+				Status:    1,
+				UpdatedAt: at(9),
+				Meta:      RecordMeta{History: tc.history},
+			}
+			require.Equal(t, tc.want, statusEnteredAt(r))
+		})
+	}
+}
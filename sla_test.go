@@ -0,0 +1,134 @@
+package workflow_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestDeadlineFromSLA(t *testing.T) {
+	deadlines := make(chan time.Time, 1)
+
+	b := workflow.NewBuilder[string, status]("sla deadline")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		deadline, ok := ctx.Deadline()
+		require.True(t, ok)
+
+		deadlines <- deadline
+		return StatusEnd, nil
+	}, StatusEnd).WithOptions(
+		workflow.SLA(time.Hour),
+		workflow.DeadlineFromSLA(),
+	)
+
+	recordStore := memrecordstore.New()
+	wf := b.Build(memstreamer.New(), recordStore, memrolescheduler.New())
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "foreignID", StatusStart)
+	require.Nil(t, err)
+
+	record, err := wf.Await(ctx, "foreignID", runID, StatusEnd)
+	require.Nil(t, err)
+
+	// The deadline is an hour from when the run entered the status rather than from when the step was called.
+	require.WithinDuration(t, record.CreatedAt.Add(time.Hour), <-deadlines, time.Second)
+}
+
+func TestDeadlineFromSLABreached(t *testing.T) {
+	var (
+		attempts atomic.Int64
+		causes   = make(chan error, 1)
+	)
+
+	b := workflow.NewBuilder[string, status]("sla deadline")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		attempts.Add(1)
+		<-ctx.Done()
+		causes <- context.Cause(ctx)
+		return 0, ctx.Err()
+	}, StatusEnd).WithOptions(
+		workflow.SLA(time.Second),
+		workflow.DeadlineFromSLA(),
+		workflow.ErrBackOff(time.Millisecond),
+		workflow.PauseAfterErrCount(2),
+	)
+
+	recordStore := memrecordstore.New()
+	wf := b.Build(memstreamer.New(), recordStore, memrolescheduler.New())
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "foreignID", StatusStart)
+	require.Nil(t, err)
+
+	require.ErrorIs(t, <-causes, workflow.ErrSLABreached)
+
+	// The step is not called again once the run has breached its SLA and the run is paused after the next error.
+	require.Eventually(t, func() bool {
+		record, err := recordStore.Lookup(ctx, runID)
+		require.Nil(t, err)
+
+		return record.RunState == workflow.RunStatePaused
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, int64(1), attempts.Load())
+}
+
+func TestDeadlineFromSLAValidation(t *testing.T) {
+	testCases := []struct {
+		name  string
+		build func(b *workflow.Builder[string, status])
+		panic string
+	}{
+		{
+			name: "Without SLA",
+			build: func(b *workflow.Builder[string, status]) {
+				b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+					return StatusEnd, nil
+				}, StatusEnd).WithOptions(workflow.DeadlineFromSLA())
+			},
+			panic: "'AddStep(Start,' sla deadline requires an sla",
+		},
+		{
+			name: "Negative SLA",
+			build: func(b *workflow.Builder[string, status]) {
+				b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+					return StatusEnd, nil
+				}, StatusEnd).WithOptions(workflow.SLA(-time.Second))
+			},
+			panic: "'AddStep(Start,' sla needs to be positive",
+		},
+		{
+			name: "Batch step",
+			build: func(b *workflow.Builder[string, status]) {
+				b.AddBatchStep(StatusStart, func(ctx context.Context, runs []*workflow.Run[string, status]) (workflow.BatchResult[status], error) {
+					return nil, nil
+				}, 10, time.Second, StatusEnd).WithOptions(workflow.SLA(time.Second), workflow.DeadlineFromSLA())
+			},
+			panic: "'AddBatchStep(Start,' sla deadlines are not supported by batch steps",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			b := workflow.NewBuilder[string, status]("sla deadline")
+			tc.build(b)
+
+			require.PanicsWithValue(t, tc.panic, func() {
+				b.Build(memstreamer.New(), memrecordstore.New(), memrolescheduler.New())
+			})
+		})
+	}
+}
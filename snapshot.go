@@ -0,0 +1,151 @@
+package workflow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+)
+
+// ErrSnapshotNotFound is returned by Run.Diff when the run does not have a snapshot with the provided name.
+var ErrSnapshotNotFound = errors.New("snapshot not found")
+
+// Snapshot stores a copy of the run's Object under the name so that a later step can detect what has changed since
+// using Diff. The snapshot is stored with the run when the step moves the run onto its next status and taking another
+// snapshot with the same name replaces it.
+func (r *Run[Type, Status]) Snapshot(name string) error {
+	b, err := r.runCodec().Marshal(r.Object)
+	if err != nil {
+		return fmt.Errorf("snapshot: %w, meta: %v", err, map[string]string{
+			"snapshot": name,
+		})
+	}
+
+	// The snapshots are copied as the run's Meta may be shared with the record it was built from.
+	snapshots := maps.Clone(r.Meta.Snapshots)
+	if snapshots == nil {
+		snapshots = make(map[string][]byte)
+	}
+
+	snapshots[name] = b
+	r.Meta.Snapshots = snapshots
+	return nil
+}
+
+// SnapshotDiff describes the changes to the run's Object since a snapshot was taken.
+type SnapshotDiff struct {
+	// Changed is true when the Object differs from the snapshot.
+	Changed bool
+	// Paths are the JSON paths of the fields that differ from the snapshot, such as "items[2].price", in sorted
+	// order. The path is empty when the Object is not encoded as a JSON object or array.
+	Paths []string
+}
+
+// Diff compares the run's Object to the snapshot taken using Snapshot. The Object and the snapshot are compared
+// using their JSON encoding and so only the exported fields are compared. ErrSnapshotNotFound is returned when the
+// run does not have the snapshot.
+func (r *Run[Type, Status]) Diff(name string) (*SnapshotDiff, error) {
+	b, ok := r.Meta.Snapshots[name]
+	if !ok {
+		return nil, fmt.Errorf("diff: %w, meta: %v", ErrSnapshotNotFound, map[string]string{
+			"snapshot": name,
+		})
+	}
+
+	var snapshot Type
+	err := r.runCodec().Unmarshal(b, &snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("diff: %w, meta: %v", err, map[string]string{
+			"snapshot": name,
+		})
+	}
+
+	before, err := jsonValue(&snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := jsonValue(r.Object)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	diffValues("", before, after, &paths)
+	slices.Sort(paths)
+
+	return &SnapshotDiff{
+		Changed: len(paths) > 0,
+		Paths:   paths,
+	}, nil
+}
+
+func (r *Run[Type, Status]) runCodec() Codec {
+	if r.codec == nil {
+		return JSONCodec{}
+	}
+
+	return r.codec
+}
+
+func jsonValue(v any) (any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var value any
+	err = json.Unmarshal(b, &value)
+	if err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// diffValues appends the paths of the values that differ between the decoded JSON values a and b.
+func diffValues(path string, a, b any, paths *[]string) {
+	switch a := a.(type) {
+	case map[string]any:
+		b, ok := b.(map[string]any)
+		if !ok {
+			break
+		}
+
+		keys := slices.Collect(maps.Keys(a))
+		for key := range b {
+			if _, ok := a[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+
+		for _, key := range keys {
+			fieldPath := key
+			if path != "" {
+				fieldPath = path + "." + key
+			}
+
+			diffValues(fieldPath, a[key], b[key], paths)
+		}
+
+		return
+	case []any:
+		b, ok := b.([]any)
+		if !ok || len(a) != len(b) {
+			break
+		}
+
+		for i := range a {
+			diffValues(path+"["+strconv.Itoa(i)+"]", a[i], b[i], paths)
+		}
+
+		return
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		*paths = append(*paths, path)
+	}
+}
@@ -0,0 +1,134 @@
+package workflow_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestSnapshot(t *testing.T) {
+	b := workflow.NewBuilder[MyType, status]("snapshot")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		err := r.Snapshot("pre-pricing")
+		if err != nil {
+			return 0, err
+		}
+
+		r.Object.Name = "priced"
+		return StatusMiddle, nil
+	}, StatusMiddle)
+
+	diffs := make(chan *workflow.SnapshotDiff, 1)
+	b.AddStep(StatusMiddle, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		diff, err := r.Diff("pre-pricing")
+		if err != nil {
+			return 0, err
+		}
+
+		diffs <- diff
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "foreignID", StatusStart, workflow.WithInitialValue[MyType, status](&MyType{
+		UserID: 1,
+		Name:   "unpriced",
+	}))
+	require.Nil(t, err)
+
+	_, err = wf.Await(ctx, "foreignID", runID, StatusEnd, workflow.WithAwaitPollingFrequency(10*time.Millisecond))
+	require.Nil(t, err)
+
+	require.Equal(t, &workflow.SnapshotDiff{
+		Changed: true,
+		Paths:   []string{"Name"},
+	}, <-diffs)
+}
+
+func TestDiff(t *testing.T) {
+	type item struct {
+		SKU   string `json:"sku"`
+		Price int    `json:"price"`
+	}
+
+	type order struct {
+		Items  []item            `json:"items"`
+		Labels map[string]string `json:"labels"`
+	}
+
+	testCases := []struct {
+		name     string
+		change   func(o *order)
+		expected *workflow.SnapshotDiff
+	}{
+		{
+			name:     "Unchanged",
+			change:   func(o *order) {},
+			expected: &workflow.SnapshotDiff{},
+		},
+		{
+			name: "Nested fields",
+			change: func(o *order) {
+				o.Items[1].Price = 30
+				o.Labels["region"] = "eu"
+			},
+			expected: &workflow.SnapshotDiff{
+				Changed: true,
+				Paths:   []string{"items[1].price", "labels.region"},
+			},
+		},
+		{
+			name: "Added and removed fields",
+			change: func(o *order) {
+				o.Items = append(o.Items, item{SKU: "c"})
+				delete(o.Labels, "tier")
+				o.Labels["new"] = "value"
+			},
+			expected: &workflow.SnapshotDiff{
+				Changed: true,
+				Paths:   []string{"items", "labels.new", "labels.tier"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			run := workflow.NewTestingRun[order, status](t, workflow.Record{}, order{
+				Items:  []item{{SKU: "a", Price: 10}, {SKU: "b", Price: 20}},
+				Labels: map[string]string{"tier": "gold"},
+			})
+
+			err := run.Snapshot("before")
+			require.Nil(t, err)
+
+			tc.change(run.Object)
+
+			diff, err := run.Diff("before")
+			require.Nil(t, err)
+			require.Equal(t, tc.expected, diff)
+		})
+	}
+
+	t.Run("Snapshot not found", func(t *testing.T) {
+		run := workflow.NewTestingRun[order, status](t, workflow.Record{}, order{})
+
+		_, err := run.Diff("missing")
+		require.ErrorIs(t, err, workflow.ErrSnapshotNotFound)
+	})
+}
@@ -14,6 +14,13 @@ const (
 	StateShutdown State = 1
 	StateRunning  State = 2
 	StateIdle     State = 3
+	// StateStoreUnavailable is the state of a process that is backing off due to the RecordStore being unavailable.
+	StateStoreUnavailable State = 4
+	// StateFenced is the state of a process that has stopped consuming due to a newer workflow definition being
+	// registered. See WithDeploymentFencing.
+	StateFenced State = 5
+	// StatePaused is the state of a connector's consumer that has been paused using PauseConnector.
+	StatePaused State = 6
 )
 
 var stateStrings = map[State]string{
@@ -21,6 +28,10 @@ var stateStrings = map[State]string{
 	StateShutdown: "Shutdown",
 	StateRunning:  "Running",
 	StateIdle:     "Idle",
+
+	StateStoreUnavailable: "StoreUnavailable",
+	StateFenced:           "Fenced",
+	StatePaused:           "Paused",
 }
 
 func (s State) String() string {
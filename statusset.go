@@ -0,0 +1,105 @@
+package workflow
+
+// OnStatuses returns the set of the statuses so that default options and middleware can be applied to the steps of
+// all the statuses in one call, such as to give every step of a large graph's payment statuses the same SLA and retry
+// policy:
+//
+//	b.OnStatuses(StatusCharge, StatusRefund, StatusSettle).WithOptions(SLA(time.Minute), ErrBackOff(time.Second))
+//
+// The set applies to the steps of the statuses regardless of whether they are added before or after the set.
+func (b *Builder[Type, Status]) OnStatuses(statuses ...Status) *statusSet[Type, Status] {
+	return &statusSet[Type, Status]{
+		statuses: statuses,
+		workflow: b.workflow,
+	}
+}
+
+type statusSet[Type any, Status StatusType] struct {
+	statuses []Status
+	workflow *Workflow[Type, Status]
+}
+
+// WithOptions sets the default options of the steps of the statuses. The options that a step is given using its own
+// WithOptions take precedence over the defaults of the set, and when a status is in several sets the options of the
+// latest set take precedence. Options that are not supported by batch steps result in Build panicking in the same way
+// as when they are provided to the batch step itself.
+func (s *statusSet[Type, Status]) WithOptions(opts ...Option) *statusSet[Type, Status] {
+	for _, status := range s.statuses {
+		s.workflow.statusOptions[status] = append(s.workflow.statusOptions[status], opts...)
+	}
+
+	return s
+}
+
+// WithConsumerMiddleware wraps the steps of the statuses with the middleware. The middleware of the statuses is
+// applied within the middleware of the workflow, provided using the WithConsumerMiddleware BuildOption, and in the
+// order provided where the first middleware is the outermost.
+func (s *statusSet[Type, Status]) WithConsumerMiddleware(
+	mw ...ConsumerMiddleware[Type, Status],
+) *statusSet[Type, Status] {
+	for _, status := range s.statuses {
+		s.workflow.statusMiddleware[status] = append(s.workflow.statusMiddleware[status], mw...)
+	}
+
+	return s
+}
+
+// withDefaults sets the options of the consumer that were not set by the consumer's own options from the defaults.
+func (c *consumerConfig[Type, Status]) withDefaults(defaults []Option) {
+	var o options
+	for _, opt := range defaults {
+		opt(&o)
+	}
+
+	if c.pollingFrequency == 0 {
+		c.pollingFrequency = o.pollingFrequency
+	}
+
+	if c.maxPollingFrequency == 0 {
+		c.maxPollingFrequency = o.maxPollingFrequency
+	}
+
+	if c.parallelCount == 0 {
+		c.parallelCount = o.parallelCount
+	}
+
+	if c.errBackOff == 0 {
+		c.errBackOff = o.errBackOff
+	}
+
+	if c.lag == 0 {
+		c.lag = o.lag
+	}
+
+	if c.lagAlert == 0 {
+		c.lagAlert = o.lagAlert
+	}
+
+	if c.pauseAfterErrCount == 0 {
+		c.pauseAfterErrCount = o.pauseAfterErrCount
+	}
+
+	if c.rateLimit == nil {
+		c.rateLimit = o.rateLimit
+	}
+
+	if c.circuitBreaker == nil {
+		c.circuitBreaker = o.circuitBreaker
+	}
+
+	if c.retryPolicy == nil {
+		c.retryPolicy = o.retryPolicy
+	}
+
+	if c.stepTimeout == 0 {
+		c.stepTimeout = o.stepTimeout
+	}
+
+	if c.sla == 0 {
+		c.sla = o.sla
+	}
+
+	if !c.deadlineFromSLA {
+		c.deadlineFromSLA = o.deadlineFromSLA
+	}
+}
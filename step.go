@@ -11,20 +11,29 @@ import (
 	"github.com/luno/workflow/internal/metrics"
 )
 
-func consumeStepEvents[Type any, Status StatusType](
+// stepConsumerRole is the role of the step consumer's shard which is also used as the name of its cursor.
+func stepConsumerRole[Type any, Status StatusType](
 	w *Workflow[Type, Status],
 	currentStatus Status,
-	p consumerConfig[Type, Status],
 	shard, totalShards int,
-) {
-	role := makeRole(
-		w.Name(),
+) string {
+	return makeRole(
+		w.roleName(),
 		strconv.FormatInt(int64(currentStatus), 10),
 		"consumer",
 		strconv.FormatInt(int64(shard), 10),
 		"of",
 		strconv.FormatInt(int64(totalShards), 10),
 	)
+}
+
+func consumeStepEvents[Type any, Status StatusType](
+	w *Workflow[Type, Status],
+	currentStatus Status,
+	p consumerConfig[Type, Status],
+	shard, totalShards int,
+) {
+	role := stepConsumerRole(w, currentStatus, shard, totalShards)
 
 	// processName can change in value if the string value of the status enum is changed. It should not be used for
 	// storing in the record store, event streamer, timeoutstore, or offset store.
@@ -36,7 +45,9 @@ func consumeStepEvents[Type any, Status StatusType](
 		strconv.FormatInt(int64(totalShards), 10),
 	)
 
-	topic := Topic(w.Name(), int(currentStatus))
+	// shardProcessName is the process name of the consumer without the shard and is used to group the statistics of
+	// the consumer's shards.
+	shardProcessName := makeRole(currentStatus.String(), "consumer")
 
 	errBackOff := w.defaultOpts.errBackOff
 	if p.errBackOff > 0 {
@@ -48,6 +59,11 @@ func consumeStepEvents[Type any, Status StatusType](
 		pollingFrequency = p.pollingFrequency
 	}
 
+	maxPollingFrequency := w.defaultOpts.maxPollingFrequency
+	if p.maxPollingFrequency > 0 {
+		maxPollingFrequency = p.maxPollingFrequency
+	}
+
 	lagAlert := w.defaultOpts.lagAlert
 	if p.lagAlert > 0 {
 		lagAlert = p.lagAlert
@@ -63,44 +79,127 @@ func consumeStepEvents[Type any, Status StatusType](
 		lag = p.lag
 	}
 
-	w.run(role, processName, func(ctx context.Context) error {
-		stream, err := w.eventStreamer.NewReceiver(
-			ctx,
-			topic,
-			role,
+	w.run(role, processName, w.statusShutdownOrder(currentStatus), func(ctx context.Context) error {
+		ctx = withLagAlertFunc(ctx, func(ctx context.Context, info LagAlertInfo) {
+			info.Status = currentStatus.String()
+			w.lagAlert(ctx, info)
+		})
+
+		receiverOpts := []ReceiverOption{
 			WithReceiverPollFrequency(pollingFrequency),
-		)
+			WithReceiverMaxPollFrequency(maxPollingFrequency),
+		}
+		if totalShards > 1 {
+			receiverOpts = append(receiverOpts, WithReceiverShard(shard, totalShards))
+		}
+
+		stream, err := w.newStatusReceiver(ctx, currentStatus, role, receiverOpts...)
 		if err != nil {
 			return err
 		}
 		defer stream.Close()
 
-		updater := newUpdater[Type, Status](w.recordStore.Lookup, w.recordStore.Store, w.statusGraph, w.clock)
-		return consume(
-			ctx,
-			w.Name(),
-			processName,
-			stream,
-			stepConsumer(
+		updater := newUpdater[Type, Status](w.recordStore.Lookup, w.recordStore.Store, w.codec, w.statusGraph, w.clock)
+		filters := []EventFilter{
+			w.versionFilter(),
+			filterUnstarted(w.clock),
+		}
+
+		// Receivers that only receive the events of their shard don't need their events to be filtered by shard.
+		if !isSharded(stream) {
+			filters = append(filters, shardFilter(shard, totalShards))
+		}
+
+		if p.batch != nil {
+			consumeFn := w.fence.guardBatch(w.versionGuardBatch(batchStepConsumer(
 				w.Name(),
 				processName,
-				p.consumer,
+				p.batch.consumer,
 				currentStatus,
 				w.recordStore.Lookup,
 				w.recordStore.Store,
+				w.codec,
 				w.logger,
 				updater,
 				pauseAfterErrCount,
 				w.errorCounter,
-			),
+				w.quarantineFunc(),
+				w.deadLetterFunc(),
+			)))
+
+			return consumeBatch(
+				ctx,
+				w.Name(),
+				processName,
+				stream,
+				p.batch.size,
+				p.batch.flushInterval,
+				w.shardObservedBatch(shardProcessName, currentStatus, shard, totalShards, consumeFn),
+				w.clock,
+				lag,
+				lagAlert,
+				filters...,
+			)
+		}
+
+		consumeFn := w.fence.guard(w.versionGuard(newStepConsumeFn(w, currentStatus, p, processName, updater, pauseAfterErrCount)))
+
+		return consume(
+			ctx,
+			w.Name(),
+			processName,
+			stream,
+			w.shardObserved(shardProcessName, currentStatus, shard, totalShards, consumeFn),
 			w.clock,
 			lag,
 			lagAlert,
-			shardFilter(shard, totalShards),
+			filters...,
 		)
 	}, errBackOff)
 }
 
+// newStepConsumeFn returns the function that consumes the events of the status using the step consumer with the
+// workflow's middleware and tracing applied.
+func newStepConsumeFn[Type any, Status StatusType](
+	w *Workflow[Type, Status],
+	currentStatus Status,
+	p consumerConfig[Type, Status],
+	processName string,
+	updater updater[Type, Status],
+	pauseAfterErrCount int,
+) func(ctx context.Context, e *Event) error {
+	var slaDeadline time.Duration
+	if p.deadlineFromSLA {
+		slaDeadline = p.sla
+	}
+
+	timeoutConsumer := stepTimeoutConsumer(p.stepTimeout, slaDeadlineConsumer(w.clock, slaDeadline, p.consumer))
+	consumer := stepConfigConsumer(p.stepConfig, replayConsumer(p.replay, circuitBreakerConsumer(
+		p.breaker,
+		rateLimitConsumer(p.limiter, concurrencyKeyConsumer(
+			w,
+			currentStatus,
+			p,
+			heartbeatConsumer(w, currentStatus, p.heartbeatTimeout, timeoutConsumer),
+		)),
+	)))
+	return retryPolicyConsumeFn(p.retrier, stepConsumer(
+		w.Name(),
+		processName,
+		w.traceStep(w.applyConsumerMiddleware(currentStatus, consumer)),
+		currentStatus,
+		w.recordStore.Lookup,
+		w.recordStore.Store,
+		w.codec,
+		w.logger,
+		updater,
+		pauseAfterErrCount,
+		w.errorCounter,
+		w.quarantineFunc(),
+		w.deadLetterFunc(),
+	))
+}
+
 func stepConsumer[Type any, Status StatusType](
 	workflowName string,
 	processName string,
@@ -108,55 +207,50 @@ func stepConsumer[Type any, Status StatusType](
 	currentStatus Status,
 	lookupFn lookupFunc,
 	store storeFunc,
-	logger Logger,
+	codec Codec,
+	logger *logger,
 	updater updater[Type, Status],
 	pauseAfterErrCount int,
 	errorCounter errorcounter.ErrorCounter,
+	quarantine quarantineFunc,
+	deadLetter deadLetterFunc,
 ) func(ctx context.Context, e *Event) error {
 	return func(ctx context.Context, e *Event) error {
-		record, err := lookupFn(ctx, e.ForeignID)
-		if errors.Is(err, ErrRecordNotFound) {
-			metrics.ProcessSkippedEvents.WithLabelValues(workflowName, processName, "record not found").Inc()
-			return nil
-		} else if err != nil {
-			return err
-		}
-
-		// Check to see if record is in expected state. If the status isn't in the expected state then skip for
-		// idempotency.
-		if record.Status != int(currentStatus) {
-			metrics.ProcessSkippedEvents.WithLabelValues(workflowName, processName, "record status not in expected state").
-				Inc()
-			return nil
-		}
-
-		if record.RunState.Stopped() {
-			logger.Debug(ctx, "Skipping consumption of stopped workflow record", map[string]string{
-				"event_id":       strconv.FormatInt(e.ID, 10),
-				"workflow":       record.WorkflowName,
-				"run_id":         record.RunID,
-				"foreign_id":     record.ForeignID,
-				"process_name":   processName,
-				"current_status": strconv.FormatInt(int64(record.Status), 10),
-				"run_state":      record.RunState.String(),
-			})
-			metrics.ProcessSkippedEvents.WithLabelValues(workflowName, processName, "record stopped").Inc()
-			return nil
-		}
-
-		run, err := buildRun[Type, Status](store, record)
+		run, err := consumableRun[Type, Status](
+			ctx,
+			workflowName,
+			processName,
+			e,
+			currentStatus,
+			lookupFn,
+			store,
+			codec,
+			logger,
+			quarantine,
+		)
 		if err != nil {
 			return err
+		} else if run == nil {
+			return nil
 		}
 
 		next, err := stepLogic(ctx, run)
 		if err != nil {
 			originalErr := err
-			paused, err := maybePause(ctx, pauseAfterErrCount, errorCounter, originalErr, processName, run, logger)
+			paused, err := maybePause(
+				ctx,
+				pauseAfterErrCount,
+				errorCounter,
+				originalErr,
+				processName,
+				run,
+				logger,
+				deadLetter,
+			)
 			if err != nil {
 				return fmt.Errorf("pause error: %v, meta: %v", err, map[string]string{
-					"run_id":     record.RunID,
-					"foreign_id": record.ForeignID,
+					"run_id":     run.RunID,
+					"foreign_id": run.ForeignID,
 				})
 			}
 
@@ -168,29 +262,109 @@ func stepConsumer[Type any, Status StatusType](
 
 			// The record was not paused and the original error is not nil. Pass back up for retrying.
 			return fmt.Errorf("consumer error: %v, meta: %v", originalErr, map[string]string{
-				"run_id":     record.RunID,
-				"foreign_id": record.ForeignID,
+				"run_id":     run.RunID,
+				"foreign_id": run.ForeignID,
 			})
 		}
 
 		if skipUpdate(next) {
-			logger.Debug(ctx, "skipping update", map[string]string{
-				"description":   skipUpdateDescription(next),
-				"workflow_name": workflowName,
-				"foreign_id":    run.ForeignID,
-				"run_id":        run.RunID,
-				"run_state":     run.RunState.String(),
-				"record_status": run.Status.String(),
+			logger.event(ctx, DebugEvent{
+				Type:    DebugEventSkipped,
+				Message: "skipping update",
+				Meta: map[string]string{
+					"description":   skipUpdateDescription(next),
+					"workflow_name": workflowName,
+					"process_name":  processName,
+					"foreign_id":    run.ForeignID,
+					"run_id":        run.RunID,
+					"run_state":     run.RunState.String(),
+					"record_status": run.Status.String(),
+					"reason":        "next value specified skip",
+				},
 			})
 
 			metrics.ProcessSkippedEvents.WithLabelValues(workflowName, processName, "next value specified skip").Inc()
 			return nil
 		}
 
-		return updater(ctx, Status(record.Status), next, run)
+		return updater(ctx, currentStatus, next, run)
 	}
 }
 
+// consumableRun looks up the run of the event and returns a nil Run when the run should not be consumed by the step
+// of the current status.
+func consumableRun[Type any, Status StatusType](
+	ctx context.Context,
+	workflowName string,
+	processName string,
+	e *Event,
+	currentStatus Status,
+	lookupFn lookupFunc,
+	store storeFunc,
+	codec Codec,
+	logger *logger,
+	quarantine quarantineFunc,
+) (*Run[Type, Status], error) {
+	skipped := func(reason string) {
+		metrics.ProcessSkippedEvents.WithLabelValues(workflowName, processName, reason).Inc()
+		logger.event(ctx, DebugEvent{
+			Type:    DebugEventSkipped,
+			Message: "skipping event",
+			Meta: map[string]string{
+				"workflow_name": workflowName,
+				"process_name":  processName,
+				"event_id":      strconv.FormatInt(e.ID, 10),
+				"run_id":        e.ForeignID,
+				"reason":        reason,
+			},
+		})
+	}
+
+	record, err := lookupFn(ctx, e.ForeignID)
+	if errors.Is(err, ErrRecordNotFound) {
+		skipped("record not found")
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	// Check to see if record is in expected state. If the status isn't in the expected state then skip for
+	// idempotency.
+	if record.Status != int(currentStatus) {
+		skipped("record status not in expected state")
+		return nil, nil
+	}
+
+	if record.RunState.Stopped() {
+		logger.event(ctx, DebugEvent{
+			Type:    DebugEventSkipped,
+			Message: "Skipping consumption of stopped workflow record",
+			Meta: map[string]string{
+				"event_id":       strconv.FormatInt(e.ID, 10),
+				"workflow":       record.WorkflowName,
+				"run_id":         record.RunID,
+				"foreign_id":     record.ForeignID,
+				"process_name":   processName,
+				"current_status": strconv.FormatInt(int64(record.Status), 10),
+				"run_state":      record.RunState.String(),
+				"reason":         "record stopped",
+			},
+		})
+		metrics.ProcessSkippedEvents.WithLabelValues(workflowName, processName, "record stopped").Inc()
+		return nil, nil
+	}
+
+	run, err := buildRun[Type, Status](store, codec, record)
+	if err != nil && quarantine != nil {
+		// Retrying will not resolve the Object being unable to be unmarshalled and so the run is moved aside.
+		return nil, quarantine(ctx, processName, record, err)
+	} else if err != nil {
+		return nil, err
+	}
+
+	return run, nil
+}
+
 func wait(ctx context.Context, d time.Duration) error {
 	if d == 0 {
 		return nil
@@ -24,6 +24,7 @@ func Test_stepConsumer(t *testing.T) {
 		ctx:          ctx,
 		clock:        clock_testing.NewFakeClock(time.Date(2024, time.April, 19, 0, 0, 0, 0, time.UTC)),
 		errorCounter: counter,
+		codec:        JSONCodec{},
 		logger: &logger{
 			inner: internal_logger.New(os.Stdout),
 		},
@@ -86,10 +87,13 @@ func Test_stepConsumer(t *testing.T) {
 			testStatus(current.Status),
 			lookup,
 			store,
+			w.codec,
 			w.logger,
 			updater,
 			0,
 			w.errorCounter,
+			nil,
+			nil,
 		)(ctx, &Event{})
 		require.Nil(t, err)
 
@@ -137,10 +141,13 @@ func Test_stepConsumer(t *testing.T) {
 			testStatus(current.Status),
 			lookup,
 			store,
+			w.codec,
 			w.logger,
 			updater,
 			0,
 			w.errorCounter,
+			nil,
+			nil,
 		)(ctx, &Event{})
 		require.Nil(t, err)
 
@@ -186,10 +193,13 @@ func Test_stepConsumer(t *testing.T) {
 			testStatus(current.Status),
 			lookup,
 			store,
+			w.codec,
 			w.logger,
 			updater,
 			0,
 			w.errorCounter,
+			nil,
+			nil,
 		)(ctx, &Event{})
 		require.Nil(t, err)
 
@@ -237,10 +247,13 @@ func Test_stepConsumer(t *testing.T) {
 			testStatus(current.Status),
 			lookup,
 			store,
+			w.codec,
 			w.logger,
 			updater,
 			3,
 			w.errorCounter,
+			nil,
+			nil,
 		)
 		require.Nil(t, err)
 
@@ -0,0 +1,46 @@
+package workflow
+
+import (
+	"context"
+)
+
+// StepConfigValidator is implemented by step configs, see WithStepConfig, that validate their fields when the workflow
+// is built.
+type StepConfigValidator interface {
+	Validate() error
+}
+
+// WithStepConfig attaches a config, such as a struct of thresholds and endpoints, to the step so that the step's
+// tunables live with the workflow definition rather than in package level variables. The config is provided to the
+// step through its context and can be retrieved using StepConfig. Configs that implement StepConfigValidator are
+// validated when the workflow is built and Build panics if the config is invalid. Step configs are not supported by
+// batch steps.
+func (s *stepUpdater[Type, Status]) WithStepConfig(cfg any) *stepUpdater[Type, Status] {
+	consumer := s.workflow.consumers[s.from]
+	consumer.stepConfig = cfg
+	s.workflow.consumers[s.from] = consumer
+	return s
+}
+
+// StepConfig returns the config of type C that was attached to the step using WithStepConfig. False is returned when
+// the context is not that of a step or the step's config is not of type C.
+func StepConfig[C any](ctx context.Context) (C, bool) {
+	cfg, ok := ctx.Value(stepConfigContextKey{}).(C)
+	return cfg, ok
+}
+
+type stepConfigContextKey struct{}
+
+// stepConfigConsumer calls the consumer with the step's config added to its context.
+func stepConfigConsumer[Type any, Status StatusType](
+	cfg any,
+	consumer ConsumerFunc[Type, Status],
+) ConsumerFunc[Type, Status] {
+	if cfg == nil {
+		return consumer
+	}
+
+	return func(ctx context.Context, r *Run[Type, Status]) (Status, error) {
+		return consumer(context.WithValue(ctx, stepConfigContextKey{}, cfg), r)
+	}
+}
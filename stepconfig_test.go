@@ -0,0 +1,89 @@
+package workflow_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+type thresholdConfig struct {
+	Threshold int
+}
+
+func (c thresholdConfig) Validate() error {
+	if c.Threshold <= 0 {
+		return errors.New("threshold needs to be positive")
+	}
+
+	return nil
+}
+
+func TestStepConfig(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("step config")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		cfg, ok := workflow.StepConfig[thresholdConfig](ctx)
+		if !ok {
+			return 0, errors.New("missing step config")
+		}
+
+		if cfg.Threshold > 5 {
+			return StatusEnd, nil
+		}
+
+		return StatusMiddle, nil
+	}, StatusMiddle, StatusEnd).WithStepConfig(thresholdConfig{Threshold: 10})
+
+	b.AddStep(StatusMiddle, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		_, ok := workflow.StepConfig[thresholdConfig](ctx)
+		if ok {
+			return 0, errors.New("unexpected step config")
+		}
+
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "foreignID", StatusStart)
+	require.Nil(t, err)
+
+	_, err = wf.Await(ctx, "foreignID", runID, StatusEnd)
+	require.Nil(t, err)
+
+	history, err := wf.RunHistory(ctx, runID)
+	require.Nil(t, err)
+
+	for _, transition := range history {
+		require.NotEqual(t, int(StatusMiddle), transition.ToStatus)
+	}
+}
+
+func TestStepConfigValidation(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("step config")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd).WithStepConfig(thresholdConfig{})
+
+	require.PanicsWithValue(t, "'AddStep(Start,' invalid step config: threshold needs to be positive", func() {
+		b.Build(
+			memstreamer.New(),
+			memrecordstore.New(),
+			memrolescheduler.New(),
+		)
+	})
+}
@@ -0,0 +1,48 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrStepTimeout is returned by a step, configured using StepTimeout, that has not returned within its timeout.
+var ErrStepTimeout = errors.New("step timeout")
+
+// StepTimeout cancels the context provided to the step once the step has been running for the duration so that a
+// single hung call, such as an HTTP request without a timeout, cannot hold up the step's shard forever. A step that
+// returns after its timeout has expired returns ErrStepTimeout, regardless of its result, and is retried like any
+// other error of the step, such as with the step's ErrBackOff and PauseAfterErrCount. The step needs to respect the
+// context's cancellation, see WithHeartbeatTimeout for steps that may not. Step timeouts are not supported by batch
+// steps.
+func StepTimeout(d time.Duration) Option {
+	return func(opt *options) {
+		opt.stepTimeout = d
+	}
+}
+
+// stepTimeoutConsumer calls the consumer with a context that is cancelled once the timeout expires.
+func stepTimeoutConsumer[Type any, Status StatusType](
+	timeout time.Duration,
+	consumer ConsumerFunc[Type, Status],
+) ConsumerFunc[Type, Status] {
+	if timeout <= 0 {
+		return consumer
+	}
+
+	return func(ctx context.Context, r *Run[Type, Status]) (Status, error) {
+		stepCtx, cancel := context.WithTimeoutCause(ctx, timeout, ErrStepTimeout)
+		defer cancel()
+
+		next, err := consumer(stepCtx, r)
+		if errors.Is(context.Cause(stepCtx), ErrStepTimeout) {
+			return 0, fmt.Errorf("%w, meta: %v", ErrStepTimeout, map[string]string{
+				"run_id":  r.RunID,
+				"timeout": timeout.String(),
+			})
+		}
+
+		return next, err
+	}
+}
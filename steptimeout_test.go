@@ -0,0 +1,64 @@
+package workflow_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestStepTimeout(t *testing.T) {
+	var (
+		attempts atomic.Int64
+		causes   = make(chan error, 1)
+	)
+
+	b := workflow.NewBuilder[string, status]("step timeout")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		// The first attempt hangs until its context is cancelled.
+		if attempts.Add(1) == 1 {
+			<-ctx.Done()
+			causes <- context.Cause(ctx)
+			return 0, ctx.Err()
+		}
+
+		return StatusEnd, nil
+	}, StatusEnd).WithOptions(
+		workflow.StepTimeout(50*time.Millisecond),
+		workflow.ErrBackOff(time.Millisecond),
+	)
+
+	wf := b.Build(memstreamer.New(), memrecordstore.New(), memrolescheduler.New())
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "foreignID", StatusStart)
+	require.Nil(t, err)
+
+	_, err = wf.Await(ctx, "foreignID", runID, StatusEnd)
+	require.Nil(t, err)
+	require.Equal(t, int64(2), attempts.Load())
+	require.ErrorIs(t, <-causes, workflow.ErrStepTimeout)
+}
+
+func TestStepTimeoutBatchStep(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("step timeout")
+	b.AddBatchStep(StatusStart, func(ctx context.Context, runs []*workflow.Run[string, status]) (workflow.BatchResult[status], error) {
+		return nil, nil
+	}, 10, time.Second, StatusEnd).WithOptions(
+		workflow.StepTimeout(time.Second),
+	)
+
+	require.PanicsWithValue(t, "'AddBatchStep(Start,' step timeouts are not supported by batch steps", func() {
+		b.Build(memstreamer.New(), memrecordstore.New(), memrolescheduler.New())
+	})
+}
@@ -44,3 +44,15 @@ type TimeoutStore interface {
 	List(ctx context.Context, workflowName string) ([]TimeoutRecord, error)
 	ListValid(ctx context.Context, workflowName string, status int, now time.Time) ([]TimeoutRecord, error)
 }
+
+// NamedTimeoutStore can optionally be implemented by a TimeoutStore to support the named timers added using AddTimer.
+// The name of the timer must be returned as the Name of the TimeoutRecord by List and ListValid.
+type NamedTimeoutStore interface {
+	CreateNamed(
+		ctx context.Context,
+		workflowName, foreignID, runID string,
+		status int,
+		name string,
+		expireAt time.Time,
+	) error
+}
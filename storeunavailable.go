@@ -0,0 +1,190 @@
+package workflow
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"time"
+)
+
+// ErrStoreUnavailable is the sentinel that all StoreUnavailableError values match when using errors.Is.
+var ErrStoreUnavailable = errors.New("record store unavailable")
+
+// StoreUnavailableError is returned when the RecordStore fails in a way that the StoreUnavailablePolicy classifies as
+// the store being unavailable. It is safe to retry the call that returned it once RetryAfter has elapsed.
+type StoreUnavailableError struct {
+	// Op is the RecordStore method that failed.
+	Op string
+	// RetryAfter is the suggested duration to wait before retrying.
+	RetryAfter time.Duration
+	// Err is the original error returned by the RecordStore.
+	Err error
+}
+
+func (e *StoreUnavailableError) Error() string {
+	return fmt.Sprintf("%v: op=%s, retry_after=%s: %v", ErrStoreUnavailable, e.Op, e.RetryAfter, e.Err)
+}
+
+func (e *StoreUnavailableError) Unwrap() error {
+	return e.Err
+}
+
+func (e *StoreUnavailableError) Is(target error) bool {
+	return target == ErrStoreUnavailable
+}
+
+// Retryable always returns true and allows callers to identify retryable errors without depending on this type.
+func (e *StoreUnavailableError) Retryable() bool {
+	return true
+}
+
+// StoreUnavailablePolicy defines how workflow classifies and responds to RecordStore errors.
+type StoreUnavailablePolicy struct {
+	// BackOff is how long consumers wait before retrying when the store is unavailable and is provided to callers of
+	// Trigger as StoreUnavailableError.RetryAfter.
+	BackOff time.Duration
+	// IsUnavailable classifies an error returned by the RecordStore. If not provided then only transient connection
+	// errors, such as network errors, refused or reset connections, and broken database connections, are treated as
+	// the store being unavailable. All other errors, such as constraint violations, are reported as process errors.
+	IsUnavailable func(err error) bool
+}
+
+func defaultStoreUnavailablePolicy() StoreUnavailablePolicy {
+	return StoreUnavailablePolicy{
+		BackOff:       defaultStoreUnavailableBackOff,
+		IsUnavailable: isStoreUnavailable,
+	}
+}
+
+// WithStoreUnavailablePolicy allows for configuring how the workflow detects and responds to the RecordStore being
+// unavailable. Whilst the store is unavailable, consumers move into StateStoreUnavailable and back off for the
+// duration of the policy's BackOff instead of the process's ErrBackOff.
+func WithStoreUnavailablePolicy(p StoreUnavailablePolicy) BuildOption {
+	return func(bo *buildOptions) {
+		if p.BackOff > 0 {
+			bo.storeUnavailablePolicy.BackOff = p.BackOff
+		}
+
+		if p.IsUnavailable != nil {
+			bo.storeUnavailablePolicy.IsUnavailable = p.IsUnavailable
+		}
+	}
+}
+
+// isStoreUnavailable classifies the transient errors of the RecordStore's connection as the store being unavailable.
+// Errors that retrying will not fix, such as constraint violations or encoding failures, are not.
+func isStoreUnavailable(err error) bool {
+	switch {
+	case errors.Is(err, ErrRecordNotFound),
+		errors.Is(err, ErrOutboxRecordNotFound),
+		errors.Is(err, context.Canceled),
+		errors.Is(err, context.DeadlineExceeded):
+		return false
+	case errors.Is(err, driver.ErrBadConn),
+		errors.Is(err, sql.ErrConnDone),
+		errors.Is(err, io.ErrUnexpectedEOF),
+		errors.Is(err, syscall.ECONNREFUSED),
+		errors.Is(err, syscall.ECONNRESET),
+		errors.Is(err, syscall.ECONNABORTED),
+		errors.Is(err, syscall.EPIPE):
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	// Adapters can mark their own errors as transient.
+	var retryable interface{ Retryable() bool }
+	if errors.As(err, &retryable) {
+		return retryable.Retryable()
+	}
+
+	return false
+}
+
+// policyRecordStore wraps the RecordStore provided to the workflow and converts errors that the policy classifies as
+// unavailability into StoreUnavailableError.
+type policyRecordStore struct {
+	RecordStore
+	policy StoreUnavailablePolicy
+}
+
+func newPolicyRecordStore(store RecordStore, policy StoreUnavailablePolicy) RecordStore {
+	if store == nil {
+		return nil
+	}
+
+	return &policyRecordStore{
+		RecordStore: store,
+		policy:      policy,
+	}
+}
+
+func (s *policyRecordStore) classify(op string, err error) error {
+	if err == nil || !s.policy.IsUnavailable(err) {
+		return err
+	}
+
+	return &StoreUnavailableError{
+		Op:         op,
+		RetryAfter: s.policy.BackOff,
+		Err:        err,
+	}
+}
+
+func (s *policyRecordStore) Store(ctx context.Context, record *Record) error {
+	return s.classify("Store", s.RecordStore.Store(ctx, record))
+}
+
+func (s *policyRecordStore) Lookup(ctx context.Context, runID string) (*Record, error) {
+	r, err := s.RecordStore.Lookup(ctx, runID)
+	return r, s.classify("Lookup", err)
+}
+
+func (s *policyRecordStore) Latest(ctx context.Context, workflowName, foreignID string) (*Record, error) {
+	r, err := s.RecordStore.Latest(ctx, workflowName, foreignID)
+	return r, s.classify("Latest", err)
+}
+
+func (s *policyRecordStore) List(
+	ctx context.Context,
+	workflowName string,
+	offsetID int64,
+	limit int,
+	order OrderType,
+	filters ...RecordFilter,
+) ([]Record, error) {
+	ls, err := s.RecordStore.List(ctx, workflowName, offsetID, limit, order, filters...)
+	return ls, s.classify("List", err)
+}
+
+func (s *policyRecordStore) ListOutboxEvents(ctx context.Context, workflowName string, limit int64) ([]OutboxEvent, error) {
+	ls, err := s.RecordStore.ListOutboxEvents(ctx, workflowName, limit)
+	return ls, s.classify("ListOutboxEvents", err)
+}
+
+func (s *policyRecordStore) DeleteOutboxEvent(ctx context.Context, id string) error {
+	return s.classify("DeleteOutboxEvent", s.RecordStore.DeleteOutboxEvent(ctx, id))
+}
+
+// unwrapRecordStore returns the underlying RecordStore so that its optional interfaces can be found. Decorators of
+// the RecordStore, such as those in adapters/instrumented, are unwrapped using their Unwrap method.
+func unwrapRecordStore(store RecordStore) RecordStore {
+	for {
+		switch s := store.(type) {
+		case *policyRecordStore:
+			store = s.RecordStore
+		case interface{ Unwrap() RecordStore }:
+			store = s.Unwrap()
+		default:
+			return store
+		}
+	}
+}
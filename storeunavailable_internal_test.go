@@ -0,0 +1,107 @@
+package workflow
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow/internal/graph"
+)
+
+type unavailableRecordStore struct {
+	RecordStore
+	err error
+}
+
+func (s *unavailableRecordStore) Latest(ctx context.Context, workflowName, foreignID string) (*Record, error) {
+	return nil, s.err
+}
+
+func (s *unavailableRecordStore) Lookup(ctx context.Context, runID string) (*Record, error) {
+	return nil, s.err
+}
+
+func TestPolicyRecordStore(t *testing.T) {
+	ctx := context.Background()
+	connErr := &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+
+	t.Run("Classifies errors as unavailable", func(t *testing.T) {
+		store := newPolicyRecordStore(&unavailableRecordStore{err: connErr}, defaultStoreUnavailablePolicy())
+
+		_, err := store.Lookup(ctx, "run-id")
+		require.True(t, errors.Is(err, ErrStoreUnavailable))
+		require.True(t, errors.Is(err, connErr))
+
+		var storeErr *StoreUnavailableError
+		require.True(t, errors.As(err, &storeErr))
+		require.Equal(t, "Lookup", storeErr.Op)
+		require.Equal(t, defaultStoreUnavailableBackOff, storeErr.RetryAfter)
+		require.True(t, storeErr.Retryable())
+	})
+
+	t.Run("Does not classify ErrRecordNotFound as unavailable", func(t *testing.T) {
+		store := newPolicyRecordStore(&unavailableRecordStore{err: ErrRecordNotFound}, defaultStoreUnavailablePolicy())
+
+		_, err := store.Lookup(ctx, "run-id")
+		require.True(t, errors.Is(err, ErrRecordNotFound))
+		require.False(t, errors.Is(err, ErrStoreUnavailable))
+	})
+
+	t.Run("Does not classify permanent errors as unavailable", func(t *testing.T) {
+		constraintErr := errors.New("duplicate key value violates unique constraint")
+		store := newPolicyRecordStore(&unavailableRecordStore{err: constraintErr}, defaultStoreUnavailablePolicy())
+
+		_, err := store.Lookup(ctx, "run-id")
+		require.True(t, errors.Is(err, constraintErr))
+		require.False(t, errors.Is(err, ErrStoreUnavailable))
+	})
+
+	t.Run("Classifies broken connections as unavailable", func(t *testing.T) {
+		for _, err := range []error{
+			driver.ErrBadConn,
+			fmt.Errorf("query: %w", syscall.ECONNRESET),
+			io.ErrUnexpectedEOF,
+		} {
+			require.True(t, isStoreUnavailable(err), err)
+		}
+	})
+
+	t.Run("Custom policy", func(t *testing.T) {
+		var bo buildOptions
+		bo.storeUnavailablePolicy = defaultStoreUnavailablePolicy()
+		WithStoreUnavailablePolicy(StoreUnavailablePolicy{
+			BackOff: time.Minute,
+			IsUnavailable: func(err error) bool {
+				return false
+			},
+		})(&bo)
+
+		store := newPolicyRecordStore(&unavailableRecordStore{err: connErr}, bo.storeUnavailablePolicy)
+
+		_, err := store.Lookup(ctx, "run-id")
+		require.False(t, errors.Is(err, ErrStoreUnavailable))
+		require.Equal(t, time.Minute, bo.storeUnavailablePolicy.BackOff)
+	})
+
+	t.Run("Trigger returns typed retryable error", func(t *testing.T) {
+		w := &Workflow[string, testStatus]{
+			name:        "example",
+			calledRun:   true,
+			codec:       JSONCodec{},
+			statusGraph: graph.New(),
+			recordStore: newPolicyRecordStore(&unavailableRecordStore{err: connErr}, defaultStoreUnavailablePolicy()),
+		}
+		w.statusGraph.AddTransition(int(statusStart), int(statusEnd))
+
+		_, err := w.Trigger(ctx, "andrew", statusStart)
+		require.True(t, errors.Is(err, ErrStoreUnavailable))
+	})
+}
@@ -0,0 +1,250 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/luno/workflow/internal/metrics"
+)
+
+// SubWorkflowTriggerFunc provides the initial value of the child workflow's run from the parent's run.
+type SubWorkflowTriggerFunc[Type any, Status StatusType, ChildType any] func(
+	ctx context.Context,
+	r *Run[Type, Status],
+) (ChildType, error)
+
+// SubWorkflowResumeFunc is called once the child workflow's run has completed. It provides the completed child run
+// so that its output can be copied onto the parent's run and returns the parent's next status.
+type SubWorkflowResumeFunc[Type any, Status StatusType, ChildType any, ChildStatus StatusType] func(
+	ctx context.Context,
+	r *Run[Type, Status],
+	child *TypedRecord[ChildType, ChildStatus],
+) (Status, error)
+
+// AddSubWorkflow adds a step that triggers a run of the child workflow when the parent's run reaches the provided
+// status. The child's run uses the parent's run ID as its foreignID and starts at childStatus with the value returned
+// by trigger. The parent's run remains in the status until the child's run completes, after which resume is called
+// with the child's run and the parent's run moves onto the status returned by resume.
+//
+// The child workflow must be built before the parent workflow is run and must be running in order to trigger the
+// child's run. Child runs that do not complete, such as those that are cancelled, leave the parent's run in the
+// status. What happens to the child's run when the parent's run is cancelled is configured using
+// WithChildCancelPolicy.
+func AddSubWorkflow[Type any, Status StatusType, ChildType any, ChildStatus StatusType](
+	b *Builder[Type, Status],
+	from Status,
+	child *Workflow[ChildType, ChildStatus],
+	childStatus ChildStatus,
+	trigger SubWorkflowTriggerFunc[Type, Status, ChildType],
+	resume SubWorkflowResumeFunc[Type, Status, ChildType, ChildStatus],
+	allowedDestinations ...Status,
+) *stepUpdater[Type, Status] {
+	b.workflow.subWorkflows = append(b.workflow.subWorkflows, subWorkflow[Type, Status]{
+		from:      from,
+		childName: child.Name(),
+		child: func() (EventStreamer, lookupFunc) {
+			return child.eventStreamer, child.recordStore.Lookup
+		},
+		childRecordStore: func() RecordStore {
+			return child.recordStore
+		},
+		resume: func(ctx context.Context, r *Run[Type, Status], record *Record) (Status, error) {
+			// The child's codec is only configured once the child workflow has been built.
+			var t ChildType
+			err := child.codec.Unmarshal(record.Object, &t)
+			if err != nil {
+				return 0, err
+			}
+
+			return resume(ctx, r, &TypedRecord[ChildType, ChildStatus]{
+				Record: *record,
+				Status: ChildStatus(record.Status),
+				Object: &t,
+			})
+		},
+	})
+
+	return b.AddStep(from, func(ctx context.Context, r *Run[Type, Status]) (Status, error) {
+		t, err := trigger(ctx, r)
+		if err != nil {
+			return 0, err
+		}
+
+		_, err = child.triggerChild(ctx, r.RunID, childStatus, WithInitialValue[ChildType, ChildStatus](&t))
+		if err != nil && !errors.Is(err, ErrWorkflowInProgress) {
+			return 0, err
+		}
+
+		// The parent's run remains in its current status until the child's run has completed.
+		return r.Skip()
+	}, allowedDestinations...)
+}
+
+type subWorkflow[Type any, Status StatusType] struct {
+	from      Status
+	childName string
+	// child returns the EventStreamer and lookupFunc of the child workflow which are only available once the child
+	// workflow has been built.
+	child            func() (EventStreamer, lookupFunc)
+	childRecordStore func() RecordStore
+	resume           func(ctx context.Context, r *Run[Type, Status], record *Record) (Status, error)
+	// join is only set for the children added using AddChildWorkflows and is called, instead of resume, once all the
+	// children have finished.
+	join func(ctx context.Context, r *Run[Type, Status], children []*Record) (Status, error)
+}
+
+func subWorkflowConsumer[Type any, Status StatusType](w *Workflow[Type, Status], sw subWorkflow[Type, Status]) {
+	role := makeRole(
+		w.roleName(),
+		strconv.FormatInt(int64(sw.from), 10),
+		"sub-workflow",
+		sw.childName,
+		"consumer",
+	)
+
+	// processName can change in value if the string value of the status enum is changed. It should not be used for
+	// storing in the record store, event streamer, timeoutstore, or offset store.
+	processName := makeRole(sw.from.String(), "sub-workflow", sw.childName, "consumer")
+
+	w.run(role, processName, w.statusShutdownOrder(sw.from), func(ctx context.Context) error {
+		streamer, lookupChild := sw.child()
+		stream, err := streamer.NewReceiver(
+			ctx,
+			RunStateChangeTopic(sw.childName),
+			role,
+			WithReceiverPollFrequency(w.defaultOpts.pollingFrequency),
+		)
+		if err != nil {
+			return err
+		}
+		defer stream.Close()
+
+		// Children added using AddChildWorkflows are joined once they have finished, whether they completed or not.
+		filter := filterByRunState(RunStateCompleted)
+		if sw.join != nil {
+			filter = filterByFinished()
+		}
+
+		updater := newUpdater[Type, Status](w.recordStore.Lookup, w.recordStore.Store, w.codec, w.statusGraph, w.clock)
+		return consume(
+			ctx,
+			w.Name(),
+			processName,
+			stream,
+			w.fence.guard(resumeParent(
+				w.Name(),
+				processName,
+				w.compatibilityPolicy,
+				w.version,
+				sw,
+				lookupChild,
+				sw.childRecordStore().Latest,
+				w.recordStore.Lookup,
+				w.recordStore.Store,
+				w.codec,
+				updater,
+			)),
+			w.clock,
+			0,
+			w.defaultOpts.lagAlert,
+			filter,
+		)
+	}, w.defaultOpts.errBackOff)
+}
+
+func resumeParent[Type any, Status StatusType](
+	workflowName string,
+	processName string,
+	policy CompatibilityPolicy,
+	hostVersion int,
+	sw subWorkflow[Type, Status],
+	lookupChild lookupFunc,
+	latestChild latestLookup,
+	lookup lookupFunc,
+	store storeFunc,
+	codec Codec,
+	updater updater[Type, Status],
+) func(ctx context.Context, e *Event) error {
+	return func(ctx context.Context, e *Event) error {
+		child, err := lookupChild(ctx, e.ForeignID)
+		if errors.Is(err, ErrRecordNotFound) {
+			metrics.ProcessSkippedEvents.WithLabelValues(workflowName, processName, "record not found").Inc()
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		// The child's foreignID is the run ID of the parent's run that triggered it unless it was triggered using
+		// AddChildWorkflows.
+		parentRunID := child.ForeignID
+		if sw.join != nil {
+			parentRunID = child.Meta.Metadata[metadataParentRunID]
+		}
+
+		record, err := lookup(ctx, parentRunID)
+		if errors.Is(err, ErrRecordNotFound) {
+			metrics.ProcessSkippedEvents.WithLabelValues(workflowName, processName, "record not found").Inc()
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if record.WorkflowName != workflowName || record.Status != int(sw.from) {
+			metrics.ProcessSkippedEvents.WithLabelValues(workflowName, processName, "record status not in expected state").
+				Inc()
+			return nil
+		}
+
+		if !isCompatible(policy, hostVersion, record.Meta.Version) {
+			metrics.ProcessSkippedEvents.WithLabelValues(workflowName, processName, "incompatible run version").Inc()
+			return nil
+		}
+
+		if record.RunState.Stopped() {
+			metrics.ProcessSkippedEvents.WithLabelValues(workflowName, processName, "record stopped").Inc()
+			return nil
+		}
+
+		run, err := buildRun[Type, Status](store, codec, record)
+		if err != nil {
+			return err
+		}
+
+		var next Status
+		if sw.join != nil {
+			var (
+				children []*Record
+				finished bool
+			)
+			children, finished, err = childRuns(ctx, latestChild, sw.childName, record.RunID)
+			if err != nil {
+				return err
+			}
+
+			if !finished {
+				metrics.ProcessSkippedEvents.WithLabelValues(workflowName, processName, "child runs in progress").Inc()
+				return nil
+			}
+
+			next, err = sw.join(ctx, run, children)
+		} else {
+			next, err = sw.resume(ctx, run, child)
+		}
+		if err != nil {
+			return fmt.Errorf("sub-workflow resume error: %v, meta: %v", err, map[string]string{
+				"run_id":       record.RunID,
+				"foreign_id":   record.ForeignID,
+				"child_run_id": child.RunID,
+			})
+		}
+
+		if skipUpdate(next) {
+			metrics.ProcessSkippedEvents.WithLabelValues(workflowName, processName, "next value specified skip").Inc()
+			return nil
+		}
+
+		return updater(ctx, sw.from, next, run)
+	}
+}
@@ -0,0 +1,127 @@
+package workflow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_resumeParent(t *testing.T) {
+	ctx := context.Background()
+
+	value := "data"
+	b, err := Marshal(&value)
+	require.Nil(t, err)
+
+	child := &Record{
+		WorkflowName: "child",
+		ForeignID:    "parent-run-id",
+		RunID:        "child-run-id",
+		RunState:     RunStateCompleted,
+		Status:       int(statusEnd),
+		Object:       b,
+	}
+
+	lookupChild := func(ctx context.Context, runID string) (*Record, error) {
+		require.Equal(t, "child-run-id", runID)
+		return child, nil
+	}
+
+	testCases := []struct {
+		name          string
+		parent        *Record
+		expectResumed bool
+	}{
+		{
+			name: "Resume parent in expected status",
+			parent: &Record{
+				WorkflowName: "parent",
+				RunID:        "parent-run-id",
+				RunState:     RunStateRunning,
+				Status:       int(statusStart),
+				Object:       b,
+			},
+			expectResumed: true,
+		},
+		{
+			name: "Skip parent that has moved on",
+			parent: &Record{
+				WorkflowName: "parent",
+				RunID:        "parent-run-id",
+				RunState:     RunStateRunning,
+				Status:       int(statusMiddle),
+				Object:       b,
+			},
+		},
+		{
+			name: "Skip runs of other workflows",
+			parent: &Record{
+				WorkflowName: "other",
+				RunID:        "parent-run-id",
+				RunState:     RunStateRunning,
+				Status:       int(statusStart),
+				Object:       b,
+			},
+		},
+		{
+			name: "Skip parent triggered by another version",
+			parent: &Record{
+				WorkflowName: "parent",
+				RunID:        "parent-run-id",
+				RunState:     RunStateRunning,
+				Status:       int(statusStart),
+				Object:       b,
+				Meta:         RecordMeta{Version: 2, Pinned: true},
+			},
+		},
+		{
+			name: "Skip stopped parent",
+			parent: &Record{
+				WorkflowName: "parent",
+				RunID:        "parent-run-id",
+				RunState:     RunStatePaused,
+				Status:       int(statusStart),
+				Object:       b,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var resumed bool
+			sw := subWorkflow[string, testStatus]{
+				from:      statusStart,
+				childName: "child",
+				resume: func(ctx context.Context, r *Run[string, testStatus], record *Record) (testStatus, error) {
+					require.Equal(t, child.RunID, record.RunID)
+					return statusEnd, nil
+				},
+			}
+
+			err := resumeParent[string, testStatus](
+				"parent",
+				"process_name",
+				SkipOtherVersions,
+				1,
+				sw,
+				lookupChild,
+				nil,
+				func(ctx context.Context, runID string) (*Record, error) {
+					require.Equal(t, "parent-run-id", runID)
+					return tc.parent, nil
+				},
+				nil,
+				JSONCodec{},
+				func(ctx context.Context, current testStatus, next testStatus, r *Run[string, testStatus]) error {
+					require.Equal(t, statusStart, current)
+					require.Equal(t, statusEnd, next)
+					resumed = true
+					return nil
+				},
+			)(ctx, &Event{ForeignID: "child-run-id"})
+			require.Nil(t, err)
+			require.Equal(t, tc.expectResumed, resumed)
+		})
+	}
+}
@@ -109,7 +109,7 @@ func Require[Type any, Status StatusType](
 	})
 
 	var actual Type
-	err := Unmarshal(wr.Object, &actual)
+	err := w.codec.Unmarshal(wr.Object, &actual)
 	require.Nil(t, err)
 
 	// Due to nuances in encoding libraries such as json with the ability to implement custom
@@ -117,11 +117,11 @@ func Require[Type any, Status StatusType](
 	// than the one provided unbeknown to the user. Calling Marshal and Unmarshal on `expected`
 	// means that the same operations take place on the type and thus the unmarshaled versions
 	// should match.
-	encoded, err := Marshal(&expected)
+	encoded, err := w.codec.Marshal(&expected)
 	require.Nil(t, err)
 
 	var normalisedExpected Type
-	err = Unmarshal(encoded, &normalisedExpected)
+	err = w.codec.Unmarshal(encoded, &normalisedExpected)
 	require.Nil(t, err)
 
 	require.Equal(t, normalisedExpected, actual)
@@ -143,7 +143,7 @@ func WaitFor[Type any, Status StatusType](
 	}
 
 	waitFor(t, w, foreignID, func(r *Record) (bool, error) {
-		run, err := buildRun[Type, Status](w.recordStore.Store, r)
+		run, err := buildRun[Type, Status](w.recordStore.Store, w.codec, r)
 		require.Nil(t, err)
 
 		return fn(run)
@@ -156,7 +156,7 @@ func waitFor[Type any, Status StatusType](
 	foreignID string,
 	fn func(r *Record) (bool, error),
 ) *Record {
-	testingStore, ok := w.recordStore.(TestingRecordStore)
+	testingStore, ok := unwrapRecordStore(w.recordStore).(TestingRecordStore)
 	if !ok {
 		panic("TestingRecordStore implementation for record store dependency required")
 	}
@@ -18,6 +18,10 @@ type TimeoutRecord struct {
 	Completed    bool
 	ExpireAt     time.Time
 	CreatedAt    time.Time
+
+	// Name is the name of the timer, added using AddTimer, that the timeout was created for. It is empty for the
+	// timeouts of AddTimeout.
+	Name string
 }
 
 // pollTimeouts attempts to find the very next expired timeout and execute it
@@ -27,10 +31,10 @@ func pollTimeouts[Type any, Status StatusType](
 	status Status,
 	timeouts timeouts[Type, Status],
 	processName string,
-	pollingFrequency time.Duration,
+	poll *pollInterval,
 	pauseAfterErrCount int,
 ) error {
-	updateFn := newUpdater[Type, Status](w.recordStore.Lookup, w.recordStore.Store, w.statusGraph, w.clock)
+	updateFn := newUpdater[Type, Status](w.recordStore.Lookup, w.recordStore.Store, w.codec, w.statusGraph, w.clock)
 	store := w.recordStore.Store
 
 	for {
@@ -38,12 +42,29 @@ func pollTimeouts[Type any, Status StatusType](
 			return ctx.Err()
 		}
 
-		expiredTimeouts, err := w.timeoutStore.ListValid(ctx, w.Name(), int(status), w.clock.Now())
+		err := w.fence.check(ctx)
+		if err != nil {
+			return err
+		}
+
+		now := w.clock.Now()
+		expiredTimeouts, err := w.timeoutStore.ListValid(ctx, w.Name(), int(status), now)
 		if err != nil {
 			return err
 		}
 
 		for _, expiredTimeout := range expiredTimeouts {
+			if expiredTimeout.Name != timeouts.name {
+				// The timeout belongs to another timer of the status which has its own poller.
+				continue
+			}
+
+			if expiredTimeout.ExpireAt.After(now) {
+				// Timeout stores that compare against their own clock can return timeouts that have not yet expired
+				// according to the clock of this host. These are left until they expire so that they never fire early.
+				continue
+			}
+
 			r, err := w.recordStore.Latest(ctx, expiredTimeout.WorkflowName, expiredTimeout.ForeignID)
 			if err != nil {
 				return err
@@ -60,14 +81,26 @@ func pollTimeouts[Type any, Status StatusType](
 				continue
 			}
 
+			if !isCompatible(w.compatibilityPolicy, w.version, r.Meta.Version) {
+				metrics.ProcessSkippedEvents.WithLabelValues(w.Name(), processName, "incompatible run version").Inc()
+
+				// Continue to next expired timeout and leave it for a host running a compatible version
+				continue
+			}
+
 			if r.RunState.Stopped() {
-				w.logger.Debug(ctx, "Skipping processing of timeout of stopped workflow record", map[string]string{
-					"workflow":       r.WorkflowName,
-					"run_id":         r.RunID,
-					"foreign_id":     r.ForeignID,
-					"process_name":   processName,
-					"current_status": strconv.FormatInt(int64(r.Status), 10),
-					"run_state":      r.RunState.String(),
+				w.logger.event(ctx, DebugEvent{
+					Type:    DebugEventSkipped,
+					Message: "Skipping processing of timeout of stopped workflow record",
+					Meta: map[string]string{
+						"workflow":       r.WorkflowName,
+						"run_id":         r.RunID,
+						"foreign_id":     r.ForeignID,
+						"process_name":   processName,
+						"current_status": strconv.FormatInt(int64(r.Status), 10),
+						"run_state":      r.RunState.String(),
+						"reason":         "record stopped",
+					},
 				})
 
 				// Continue to next expired timeout
@@ -88,16 +121,18 @@ func pollTimeouts[Type any, Status StatusType](
 					processName,
 					pauseAfterErrCount,
 				)
+				metrics.ObserveWithExemplar(
+					metrics.ProcessLatency.WithLabelValues(w.Name(), processName),
+					w.clock.Since(t0).Seconds(),
+					exemplar(r.RunID, r.Meta.TraceParent),
+				)
 				if err != nil {
-					metrics.ProcessLatency.WithLabelValues(w.Name(), processName).Observe(w.clock.Since(t0).Seconds())
 					return err
 				}
-
-				metrics.ProcessLatency.WithLabelValues(w.Name(), processName).Observe(w.clock.Since(t0).Seconds())
 			}
 		}
 
-		err = wait(ctx, pollingFrequency)
+		err = poll.wait(ctx, len(expiredTimeouts) > 0)
 		if err != nil {
 			return err
 		}
@@ -118,14 +153,40 @@ func processTimeout[Type any, Status StatusType](
 	processName string,
 	pauseAfterErrCount int,
 ) error {
-	run, err := buildRun[Type, Status](store, record)
-	if err != nil {
+	run, err := buildRun[Type, Status](store, w.codec, record)
+	if quarantine := w.quarantineFunc(); err != nil && quarantine != nil {
+		// The timeout is left uncompleted so that it expires again if the run is reprocessed.
+		return quarantine(ctx, processName, record, err)
+	} else if err != nil {
 		return err
 	}
 
-	next, err := config.TimeoutFunc(ctx, run, w.clock.Now())
+	ctx, span := w.startRunSpan(ctx, &run.Record, "timeout")
+
+	var next Status
+	if config.ReminderFunc != nil {
+		err = remind(ctx, w, config, run, timeout)
+	} else {
+		next, err = config.TimeoutFunc(ctx, run, w.clock.Now())
+	}
+
+	var vetoed bool
+	if err == nil && !skipUpdate(next) && config.VetoFunc != nil {
+		vetoed, err = vetoTimeout(ctx, w, config.VetoFunc, record.RunID, Status(timeout.Status))
+	}
+
+	w.endRunSpan(span, next, err)
 	if err != nil {
-		_, err := maybePause(ctx, pauseAfterErrCount, w.errorCounter, err, processName, run, w.logger)
+		_, err := maybePause(
+			ctx,
+			pauseAfterErrCount,
+			w.errorCounter,
+			err,
+			processName,
+			run,
+			w.logger,
+			w.deadLetterFunc(),
+		)
 		if err != nil {
 			return fmt.Errorf("pause error: %v, meta: %v", err, map[string]string{
 				"run_id":     record.RunID,
@@ -136,14 +197,40 @@ func processTimeout[Type any, Status StatusType](
 		return nil
 	}
 
+	if config.ReminderFunc != nil {
+		// Reminders never move the run on and the next reminder has already been scheduled.
+		return completeFn(ctx, timeout.ID)
+	}
+
+	if vetoed {
+		w.logger.event(ctx, DebugEvent{
+			Type:    DebugEventTimeoutVetoed,
+			Message: "timeout vetoed",
+			Meta: map[string]string{
+				"workflow_name": w.Name(),
+				"foreign_id":    run.ForeignID,
+				"run_id":        run.RunID,
+				"record_status": run.Status.String(),
+			},
+		})
+
+		metrics.ProcessSkippedEvents.WithLabelValues(w.Name(), processName, "timeout vetoed").Inc()
+		return w.timeoutStore.Cancel(ctx, timeout.ID)
+	}
+
 	if skipUpdate(next) {
-		w.logger.Debug(ctx, "skipping update", map[string]string{
-			"description":   skipUpdateDescription(next),
-			"workflow_name": w.Name(),
-			"foreign_id":    run.ForeignID,
-			"run_id":        run.RunID,
-			"run_state":     run.RunState.String(),
-			"record_status": run.Status.String(),
+		w.logger.event(ctx, DebugEvent{
+			Type:    DebugEventSkipped,
+			Message: "skipping update",
+			Meta: map[string]string{
+				"description":   skipUpdateDescription(next),
+				"workflow_name": w.Name(),
+				"foreign_id":    run.ForeignID,
+				"run_id":        run.RunID,
+				"run_state":     run.RunState.String(),
+				"record_status": run.Status.String(),
+				"reason":        "next value specified skip",
+			},
 		})
 
 		metrics.ProcessSkippedEvents.WithLabelValues(w.Name(), processName, "next value specified skip").Inc()
@@ -160,16 +247,49 @@ func processTimeout[Type any, Status StatusType](
 }
 
 type timeouts[Type any, Status StatusType] struct {
-	pollingFrequency   time.Duration
-	errBackOff         time.Duration
-	lagAlert           time.Duration
-	pauseAfterErrCount int
-	transitions        []timeout[Type, Status]
+	// name is only set for the timers added using AddTimer which each have their own processes.
+	name             string
+	pollingFrequency time.Duration
+	// maxPollingFrequency is only configured when using AdaptivePolling.
+	maxPollingFrequency time.Duration
+	errBackOff          time.Duration
+	lagAlert            time.Duration
+	pauseAfterErrCount  int
+	transitions         []timeout[Type, Status]
 }
 
 type timeout[Type any, Status StatusType] struct {
 	TimerFunc   TimerFunc[Type, Status]
 	TimeoutFunc TimeoutFunc[Type, Status]
+	VetoFunc    TimeoutVetoFunc[Type, Status]
+	// ReminderFunc is only set for reminders added using AddReminder and is called instead of the TimeoutFunc.
+	ReminderFunc ReminderFunc[Type, Status]
+}
+
+// vetoTimeout looks up the latest version of the run and returns true when the run has moved on from the status of
+// the timeout or the veto returns true.
+func vetoTimeout[Type any, Status StatusType](
+	ctx context.Context,
+	w *Workflow[Type, Status],
+	veto TimeoutVetoFunc[Type, Status],
+	runID string,
+	status Status,
+) (bool, error) {
+	latest, err := w.recordStore.Lookup(ctx, runID)
+	if err != nil {
+		return false, err
+	}
+
+	if Status(latest.Status) != status || latest.RunState.Finished() {
+		return true, nil
+	}
+
+	run, err := buildRun[Type, Status](w.recordStore.Store, w.codec, latest)
+	if err != nil {
+		return false, err
+	}
+
+	return veto(ctx, run, w.clock.Now())
 }
 
 func timeoutPoller[Type any, Status StatusType](
@@ -177,10 +297,10 @@ func timeoutPoller[Type any, Status StatusType](
 	status Status,
 	timeouts timeouts[Type, Status],
 ) {
-	role := makeRole(w.Name(), strconv.FormatInt(int64(status), 10), "timeout-consumer")
+	role := makeRole(w.roleName(), strconv.FormatInt(int64(status), 10), timerRole(timeouts.name, "timeout-consumer"))
 	// readableRole can change in value if the string value of the status enum is changed. It should not be used for
 	// storing in the record store, event streamer, timeout store, or offset store.
-	processName := makeRole(status.String(), "timeout-consumer")
+	processName := makeRole(status.String(), timerRole(timeouts.name, "timeout-consumer"))
 
 	errBackOff := w.defaultOpts.errBackOff
 	if timeouts.errBackOff > 0 {
@@ -192,13 +312,19 @@ func timeoutPoller[Type any, Status StatusType](
 		pollingFrequency = timeouts.pollingFrequency
 	}
 
+	maxPollingFrequency := w.defaultOpts.maxPollingFrequency
+	if timeouts.maxPollingFrequency > 0 {
+		maxPollingFrequency = timeouts.maxPollingFrequency
+	}
+
 	pauseAfterErrCount := w.defaultOpts.pauseAfterErrCount
 	if timeouts.pauseAfterErrCount != 0 {
 		pauseAfterErrCount = timeouts.pauseAfterErrCount
 	}
 
-	w.run(role, processName, func(ctx context.Context) error {
-		err := pollTimeouts(ctx, w, status, timeouts, processName, pollingFrequency, pauseAfterErrCount)
+	poll := newPollInterval(pollingFrequency, maxPollingFrequency)
+	w.run(role, processName, w.statusShutdownOrder(status), func(ctx context.Context) error {
+		err := pollTimeouts(ctx, w, status, timeouts, processName, poll, pauseAfterErrCount)
 		if err != nil {
 			return err
 		}
@@ -212,8 +338,12 @@ func timeoutAutoInserterConsumer[Type any, Status StatusType](
 	status Status,
 	timeouts timeouts[Type, Status],
 ) {
-	role := makeRole(w.Name(), strconv.FormatInt(int64(status), 10), "timeout-auto-inserter-consumer")
-	processName := makeRole(status.String(), "timeout-auto-inserter-consumer")
+	role := makeRole(
+		w.roleName(),
+		strconv.FormatInt(int64(status), 10),
+		timerRole(timeouts.name, "timeout-auto-inserter-consumer"),
+	)
+	processName := makeRole(status.String(), timerRole(timeouts.name, "timeout-auto-inserter-consumer"))
 
 	pauseAfterErrCount := w.defaultOpts.pauseAfterErrCount
 	if timeouts.pauseAfterErrCount != 0 {
@@ -230,15 +360,22 @@ func timeoutAutoInserterConsumer[Type any, Status StatusType](
 		pollingFrequency = timeouts.pollingFrequency
 	}
 
+	maxPollingFrequency := w.defaultOpts.maxPollingFrequency
+	if timeouts.maxPollingFrequency > 0 {
+		maxPollingFrequency = timeouts.maxPollingFrequency
+	}
+
 	lagAlert := w.defaultOpts.lagAlert
 	if timeouts.lagAlert > 0 {
 		lagAlert = timeouts.lagAlert
 	}
 
-	w.run(role, processName, func(ctx context.Context) error {
+	w.run(role, processName, w.statusShutdownOrder(status), func(ctx context.Context) error {
 		consumerFunc := func(ctx context.Context, r *Run[Type, Status]) (Status, error) {
 			for _, config := range timeouts.transitions {
-				expireAt, err := config.TimerFunc(ctx, r, w.clock.Now())
+				// The clock of this host can be behind the clock of the host that moved the run into the status
+				// and so the timer is never evaluated from a time before the run was last updated.
+				expireAt, err := config.TimerFunc(ctx, r, notBefore(w.clock.Now(), r.UpdatedAt))
 				if err != nil {
 					return 0, err
 				}
@@ -248,7 +385,7 @@ func timeoutAutoInserterConsumer[Type any, Status StatusType](
 					continue
 				}
 
-				err = w.timeoutStore.Create(ctx, r.WorkflowName, r.ForeignID, r.RunID, int(status), expireAt)
+				err = w.createTimeout(ctx, r, int(status), timeouts.name, expireAt)
 				if err != nil {
 					return 0, err
 				}
@@ -258,39 +395,44 @@ func timeoutAutoInserterConsumer[Type any, Status StatusType](
 			return 0, nil
 		}
 
-		topic := Topic(w.Name(), int(status))
-		stream, err := w.eventStreamer.NewReceiver(
+		stream, err := w.newStatusReceiver(
 			ctx,
-			topic,
+			status,
 			role,
 			WithReceiverPollFrequency(pollingFrequency),
+			WithReceiverMaxPollFrequency(maxPollingFrequency),
 		)
 		if err != nil {
 			return err
 		}
 		defer stream.Close()
 
-		updater := newUpdater[Type, Status](w.recordStore.Lookup, w.recordStore.Store, w.statusGraph, w.clock)
+		updater := newUpdater[Type, Status](w.recordStore.Lookup, w.recordStore.Store, w.codec, w.statusGraph, w.clock)
 		return consume(
 			ctx,
 			w.Name(),
 			processName,
 			stream,
-			stepConsumer(
+			w.fence.guard(w.versionGuard(stepConsumer(
 				w.Name(),
 				processName,
 				consumerFunc,
 				status,
 				w.recordStore.Lookup,
 				w.recordStore.Store,
+				w.codec,
 				w.logger,
 				updater,
 				pauseAfterErrCount,
 				w.errorCounter,
-			),
+				w.quarantineFunc(),
+				w.deadLetterFunc(),
+			))),
 			w.clock,
 			0,
 			lagAlert,
+			w.versionFilter(),
+			filterUnstarted(w.clock),
 		)
 	}, errBackOff)
 }
@@ -305,3 +447,8 @@ type TimerFunc[Type any, Status StatusType] func(ctx context.Context, r *Run[Typ
 // called again until a nil error is returned. If true is returned with a nil error then the provided record and any
 // modifications made to it will be stored and the status updated - continuing the workflow.
 type TimeoutFunc[Type any, Status StatusType] func(ctx context.Context, r *Run[Type, Status], now time.Time) (Status, error)
+
+// TimeoutVetoFunc is called with the latest version of the run, which is looked up after the TimeoutFunc has run, and
+// returns true to cancel the timeout instead of moving the run on. A non-nil error is handled in the same way as an
+// error returned by the TimeoutFunc.
+type TimeoutVetoFunc[Type any, Status StatusType] func(ctx context.Context, r *Run[Type, Status], now time.Time) (bool, error)
@@ -22,6 +22,7 @@ func TestProcessTimeout(t *testing.T) {
 		ctx:          ctx,
 		clock:        clock_testing.NewFakeClock(time.Date(2024, time.April, 19, 0, 0, 0, 0, time.UTC)),
 		errorCounter: counter,
+		codec:        JSONCodec{},
 		logger:       &logger{},
 	}
 
@@ -203,3 +204,45 @@ func TestProcessTimeout(t *testing.T) {
 		})
 	}
 }
+
+// createdTimeouts records the timeouts that are created.
+type createdTimeouts struct {
+	TimeoutStore
+
+	expireAts []time.Time
+}
+
+func (s *createdTimeouts) Create(ctx context.Context, workflowName, foreignID, runID string, status int, expireAt time.Time) error {
+	s.expireAts = append(s.expireAts, expireAt)
+	return nil
+}
+
+func TestRemindAfterClockStepsBack(t *testing.T) {
+	expireAt := time.Date(2024, time.April, 19, 10, 0, 0, 0, time.UTC)
+	store := &createdTimeouts{}
+	w := &Workflow[string, testStatus]{
+		// The clock has been corrected backwards since the reminder expired.
+		clock:        clock_testing.NewFakeClock(expireAt.Add(-30 * time.Minute)),
+		timeoutStore: store,
+	}
+
+	var remindedAt time.Time
+	config := timeout[string, testStatus]{
+		// The reminder is sent on the hour.
+		TimerFunc: func(ctx context.Context, r *Run[string, testStatus], now time.Time) (time.Time, error) {
+			return now.Truncate(time.Hour).Add(time.Hour), nil
+		},
+		ReminderFunc: func(ctx context.Context, r *Run[string, testStatus], now time.Time) error {
+			remindedAt = now
+			return nil
+		},
+	}
+
+	r := &Run[string, testStatus]{}
+	err := remind(context.Background(), w, config, r, TimeoutRecord{Status: int(statusStart), ExpireAt: expireAt})
+	require.Nil(t, err)
+
+	require.Equal(t, expireAt, remindedAt)
+	// The next reminder is not scheduled for the time of the reminder that has just fired.
+	require.Equal(t, []time.Time{expireAt.Add(time.Hour)}, store.expireAts)
+}
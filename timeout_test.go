@@ -0,0 +1,445 @@
+package workflow_test
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	clock_testing "k8s.io/utils/clock/testing"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+	"github.com/luno/workflow/adapters/memtimeoutstore"
+	workflowtesting "github.com/luno/workflow/adapters/testing"
+)
+
+func TestTimeoutWithVeto(t *testing.T) {
+	testCases := []struct {
+		name     string
+		veto     bool
+		expected status
+	}{
+		{
+			name:     "Not vetoed - timeout moves the run on",
+			expected: StatusEnd,
+		},
+		{
+			name:     "Vetoed - timeout is cancelled",
+			veto:     true,
+			expected: StatusStart,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var vetoedRunID string
+			b := workflow.NewBuilder[MyType, status]("timeout_veto")
+			b.AddTimeout(
+				StatusStart,
+				workflow.DurationTimerFunc[MyType, status](time.Hour),
+				func(ctx context.Context, r *workflow.Run[MyType, status], now time.Time) (status, error) {
+					return StatusEnd, nil
+				},
+				StatusEnd,
+			).WithVeto(func(ctx context.Context, r *workflow.Run[MyType, status], now time.Time) (bool, error) {
+				vetoedRunID = r.RunID
+				return tc.veto, nil
+			})
+
+			now := time.Date(2024, time.April, 9, 0, 0, 0, 0, time.UTC)
+			clock := clock_testing.NewFakeClock(now)
+			timeoutStore := memtimeoutstore.New()
+			recordStore := memrecordstore.New()
+			wf := b.Build(
+				memstreamer.New(),
+				recordStore,
+				memrolescheduler.New(),
+				workflow.WithTimeoutStore(timeoutStore),
+				workflow.WithClock(clock),
+			)
+
+			ctx := context.Background()
+			wf.Run(ctx)
+			t.Cleanup(wf.Stop)
+
+			runID, err := wf.Trigger(ctx, "foreignID", StatusStart)
+			require.Nil(t, err)
+
+			workflow.AwaitTimeoutInsert(t, wf, "foreignID", runID, StatusStart)
+
+			clock.Step(time.Hour)
+
+			require.Eventually(t, func() bool {
+				timeouts, err := timeoutStore.List(ctx, wf.Name())
+				require.Nil(t, err)
+
+				if len(timeouts) == 0 {
+					return true
+				}
+
+				return timeouts[0].Completed
+			}, 5*time.Second, 10*time.Millisecond)
+
+			require.Equal(t, runID, vetoedRunID)
+
+			r, err := recordStore.Lookup(ctx, runID)
+			require.Nil(t, err)
+			require.Equal(t, int(tc.expected), r.Status)
+		})
+	}
+}
+
+func TestPendingTimeouts(t *testing.T) {
+	b := workflow.NewBuilder[MyType, status]("pending_timeouts")
+	for _, d := range []time.Duration{2 * time.Hour, time.Hour} {
+		b.AddTimeout(
+			StatusStart,
+			workflow.DurationTimerFunc[MyType, status](d),
+			func(ctx context.Context, r *workflow.Run[MyType, status], now time.Time) (status, error) {
+				return StatusEnd, nil
+			},
+			StatusEnd,
+		)
+	}
+
+	now := time.Date(2024, time.April, 9, 0, 0, 0, 0, time.UTC)
+	clock := clock_testing.NewFakeClock(now)
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+		workflow.WithTimeoutStore(memtimeoutstore.New()),
+		workflow.WithClock(clock),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "first", StatusStart)
+	require.Nil(t, err)
+
+	_, err = wf.Trigger(ctx, "second", StatusStart)
+	require.Nil(t, err)
+
+	require.Eventually(t, func() bool {
+		counts, err := wf.PendingTimeoutCounts(ctx)
+		require.Nil(t, err)
+
+		return counts[StatusStart] == 4
+	}, 5*time.Second, 10*time.Millisecond)
+
+	pending, err := wf.PendingTimeouts(ctx, runID)
+	require.Nil(t, err)
+	require.Len(t, pending, 2)
+
+	for i, expireAt := range []time.Time{now.Add(time.Hour), now.Add(2 * time.Hour)} {
+		require.Equal(t, "first", pending[i].ForeignID)
+		require.Equal(t, runID, pending[i].RunID)
+		require.Equal(t, StatusStart, pending[i].Status)
+		require.Equal(t, expireAt, pending[i].ExpireAt)
+	}
+}
+
+func TestPendingTimeoutsWithoutTimeoutStore(t *testing.T) {
+	b := workflow.NewBuilder[MyType, status]("pending_timeouts")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+	)
+
+	_, err := wf.PendingTimeouts(context.Background(), "runID")
+	require.ErrorContains(t, err, "no TimeoutStore configured for workflow")
+}
+
+func TestAddTimer(t *testing.T) {
+	b := workflow.NewBuilder[MyType, status]("timers")
+	b.AddTimer(
+		StatusStart,
+		"escalate",
+		workflow.DurationTimerFunc[MyType, status](time.Hour),
+		func(ctx context.Context, r *workflow.Run[MyType, status], now time.Time) (status, error) {
+			return StatusMiddle, nil
+		},
+		StatusMiddle,
+	).WithOptions(workflow.PollingFrequency(10 * time.Millisecond))
+	b.AddTimer(
+		StatusStart,
+		"cancel",
+		workflow.DurationTimerFunc[MyType, status](2*time.Hour),
+		func(ctx context.Context, r *workflow.Run[MyType, status], now time.Time) (status, error) {
+			return StatusEnd, nil
+		},
+		StatusEnd,
+	).WithOptions(workflow.PollingFrequency(10 * time.Millisecond))
+
+	now := time.Date(2024, time.April, 9, 0, 0, 0, 0, time.UTC)
+	clock := clock_testing.NewFakeClock(now)
+	recordStore := memrecordstore.New()
+	wf := b.Build(
+		memstreamer.New(),
+		recordStore,
+		memrolescheduler.New(),
+		workflow.WithTimeoutStore(memtimeoutstore.New()),
+		workflow.WithClock(clock),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	escalatedRunID, err := wf.Trigger(ctx, "escalated", StatusStart)
+	require.Nil(t, err)
+
+	cancelledRunID, err := wf.Trigger(ctx, "cancelled", StatusStart)
+	require.Nil(t, err)
+
+	require.Eventually(t, func() bool {
+		counts, err := wf.PendingTimeoutCounts(ctx)
+		require.Nil(t, err)
+
+		return counts[StatusStart] == 4
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// Only the escalation of the second run is cancelled.
+	err = wf.CancelTimer(ctx, cancelledRunID, "escalate")
+	require.Nil(t, err)
+
+	err = wf.CancelTimer(ctx, cancelledRunID, "escalate")
+	require.ErrorIs(t, err, workflow.ErrTimeoutNotFound)
+
+	pending, err := wf.PendingTimeouts(ctx, cancelledRunID)
+	require.Nil(t, err)
+	require.Len(t, pending, 1)
+	require.Equal(t, "cancel", pending[0].Name)
+
+	// Only the escalation timer of the first run expires.
+	clock.Step(time.Hour)
+
+	_, err = wf.Await(ctx, "escalated", escalatedRunID, StatusMiddle, workflow.WithAwaitPollingFrequency(10*time.Millisecond))
+	require.Nil(t, err)
+
+	r, err := recordStore.Lookup(ctx, cancelledRunID)
+	require.Nil(t, err)
+	require.Equal(t, int(StatusStart), r.Status)
+
+	clock.Step(time.Hour)
+
+	_, err = wf.Await(ctx, "cancelled", cancelledRunID, StatusEnd, workflow.WithAwaitPollingFrequency(10*time.Millisecond))
+	require.Nil(t, err)
+
+	r, err = recordStore.Lookup(ctx, escalatedRunID)
+	require.Nil(t, err)
+	require.Equal(t, int(StatusMiddle), r.Status)
+}
+
+func TestAddTimerValidation(t *testing.T) {
+	timer := workflow.DurationTimerFunc[MyType, status](time.Hour)
+	timeout := func(ctx context.Context, r *workflow.Run[MyType, status], now time.Time) (status, error) {
+		return StatusEnd, nil
+	}
+
+	t.Run("Timers need to be named", func(t *testing.T) {
+		b := workflow.NewBuilder[MyType, status]("timers")
+		require.PanicsWithValue(t, "'AddTimer(Start,' timers need to be named", func() {
+			b.AddTimer(StatusStart, "", timer, timeout, StatusEnd)
+		})
+	})
+
+	t.Run("Timer names need to be unique", func(t *testing.T) {
+		b := workflow.NewBuilder[MyType, status]("timers")
+		b.AddTimer(StatusStart, "reminder", timer, timeout, StatusEnd)
+		require.PanicsWithValue(t, "'AddTimer(Start,' timer names need to be unique", func() {
+			b.AddTimer(StatusStart, "reminder", timer, timeout, StatusEnd)
+		})
+	})
+}
+
+func TestAddReminder(t *testing.T) {
+	var reminders atomic.Int64
+	b := workflow.NewBuilder[MyType, status]("reminders")
+	b.AddReminder(
+		StatusStart,
+		"nag",
+		workflow.DurationTimerFunc[MyType, status](24*time.Hour),
+		func(ctx context.Context, r *workflow.Run[MyType, status], now time.Time) error {
+			reminders.Add(1)
+			return nil
+		},
+	).WithOptions(workflow.PollingFrequency(10 * time.Millisecond))
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		return r.Skip()
+	}, StatusEnd)
+
+	now := time.Date(2024, time.April, 9, 0, 0, 0, 0, time.UTC)
+	clock := clock_testing.NewFakeClock(now)
+	recordStore := memrecordstore.New()
+	wf := b.Build(
+		memstreamer.New(),
+		recordStore,
+		memrolescheduler.New(),
+		workflow.WithTimeoutStore(memtimeoutstore.New()),
+		workflow.WithClock(clock),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "foreignID", StatusStart)
+	require.Nil(t, err)
+
+	expireAt := now.Add(24 * time.Hour)
+	for i := int64(1); i <= 3; i++ {
+		require.Eventually(t, func() bool {
+			pending, err := wf.PendingTimeouts(ctx, runID)
+			require.Nil(t, err)
+
+			return len(pending) == 1 && pending[0].ExpireAt.Equal(expireAt)
+		}, 5*time.Second, 10*time.Millisecond)
+
+		if i == 3 {
+			break
+		}
+
+		clock.Step(24 * time.Hour)
+		expireAt = expireAt.Add(24 * time.Hour)
+
+		require.Eventually(t, func() bool {
+			return reminders.Load() == i
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+
+	// Reminders never move the run on.
+	r, err := recordStore.Lookup(ctx, runID)
+	require.Nil(t, err)
+	require.Equal(t, int(StatusStart), r.Status)
+
+	err = wf.CancelTimer(ctx, runID, "nag")
+	require.Nil(t, err)
+
+	pending, err := wf.PendingTimeouts(ctx, runID)
+	require.Nil(t, err)
+	require.Empty(t, pending)
+}
+
+func TestTimerWithSkewedClock(t *testing.T) {
+	adapters := workflowtesting.New()
+
+	// The run is moved into StatusMiddle by a host with the correct time.
+	steps := workflow.NewBuilder[MyType, status]("skewed clocks")
+	steps.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		return StatusMiddle, nil
+	}, StatusMiddle)
+	steps.AddCallback(StatusMiddle, func(ctx context.Context, r *workflow.Run[MyType, status], reader io.Reader) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	stepsWf := workflowtesting.Build(steps, adapters)
+
+	// The timer is inserted by a host whose clock is ten minutes behind.
+	timers := workflow.NewBuilder[MyType, status]("skewed clocks")
+	timers.AddTimeout(
+		StatusMiddle,
+		workflow.DurationTimerFunc[MyType, status](time.Hour),
+		func(ctx context.Context, r *workflow.Run[MyType, status], now time.Time) (status, error) {
+			return StatusEnd, nil
+		},
+		StatusEnd,
+	)
+
+	timersWf := workflowtesting.Build(
+		timers,
+		adapters,
+		workflow.WithClock(adapters.SkewedClock(-10*time.Minute)),
+	)
+
+	ctx := context.Background()
+	stepsWf.Run(ctx)
+	t.Cleanup(stepsWf.Stop)
+	timersWf.Run(ctx)
+	t.Cleanup(timersWf.Stop)
+
+	runID, err := stepsWf.Trigger(ctx, "foreignID", StatusStart)
+	require.Nil(t, err)
+
+	workflow.AwaitTimeoutInsert(t, timersWf, "foreignID", runID, StatusMiddle)
+
+	// The timer is evaluated from when the run was moved into StatusMiddle rather than the earlier time of the
+	// skewed host so that it does not expire early.
+	pending, err := timersWf.PendingTimeouts(ctx, runID)
+	require.Nil(t, err)
+	require.Len(t, pending, 1)
+	require.Equal(t, workflowtesting.DefaultStartTime.Add(time.Hour), pending[0].ExpireAt)
+}
+
+// aheadTimeoutStore lists the timeouts that have expired according to its own clock which is ahead of the clock of
+// the workflow.
+type aheadTimeoutStore struct {
+	workflow.TimeoutStore
+
+	ahead time.Duration
+}
+
+func (s *aheadTimeoutStore) ListValid(
+	ctx context.Context,
+	workflowName string,
+	status int,
+	now time.Time,
+) ([]workflow.TimeoutRecord, error) {
+	return s.TimeoutStore.ListValid(ctx, workflowName, status, now.Add(s.ahead))
+}
+
+func TestTimeoutStoreWithSkewedClock(t *testing.T) {
+	b := workflow.NewBuilder[MyType, status]("skewed timeout store")
+	b.AddTimeout(
+		StatusStart,
+		workflow.DurationTimerFunc[MyType, status](time.Hour),
+		func(ctx context.Context, r *workflow.Run[MyType, status], now time.Time) (status, error) {
+			return StatusEnd, nil
+		},
+		StatusEnd,
+	).WithOptions(workflow.PollingFrequency(10 * time.Millisecond))
+
+	adapters := workflowtesting.New()
+	wf := workflowtesting.Build(
+		b,
+		adapters,
+		workflow.WithTimeoutStore(&aheadTimeoutStore{
+			TimeoutStore: adapters.TimeoutStore,
+			ahead:        time.Hour,
+		}),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "foreignID", StatusStart)
+	require.Nil(t, err)
+
+	workflow.AwaitTimeoutInsert(t, wf, "foreignID", runID, StatusStart)
+
+	// The timeout store considers the timeout to have expired but the workflow's clock does not.
+	adapters.Clock.Step(30 * time.Minute)
+	require.Never(t, func() bool {
+		r, err := adapters.RecordStore.Lookup(ctx, runID)
+		require.Nil(t, err)
+
+		return r.Status == int(StatusEnd)
+	}, 200*time.Millisecond, 10*time.Millisecond)
+
+	adapters.Clock.Step(30 * time.Minute)
+	_, err = wf.Await(ctx, "foreignID", runID, StatusEnd)
+	require.Nil(t, err)
+}
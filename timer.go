@@ -0,0 +1,194 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// timerKey identifies a timer added using AddTimer.
+type timerKey[Status StatusType] struct {
+	status Status
+	name   string
+}
+
+// AddTimer adds a named timer to the status. Unlike the timeouts added using AddTimeout, which share the processes
+// of the status and are all called when any of them expires, each timer has its own processes and options and only
+// its own TimeoutFunc is called when it expires. This allows a status to have several independent timers, such as a
+// reminder after a day, an escalation after three days, and a cancellation after a week, which can be cancelled
+// individually using CancelTimer. The name of the timer is used in the roles of its processes and must be unique
+// amongst the timers of the status.
+//
+// Timers require a TimeoutStore that implements NamedTimeoutStore.
+func (b *Builder[Type, Status]) AddTimer(
+	from Status,
+	name string,
+	timer TimerFunc[Type, Status],
+	tf TimeoutFunc[Type, Status],
+	allowedDestinations ...Status,
+) *timeoutUpdater[Type, Status] {
+	if name == "" {
+		panic("'AddTimer(" + from.String() + ",' timers need to be named")
+	}
+
+	key := timerKey[Status]{status: from, name: name}
+	if _, ok := b.workflow.timers[key]; ok {
+		panic("'AddTimer(" + from.String() + ",' timer names need to be unique")
+	}
+
+	b.addTransitions(TransitionKindTimer, from, allowedDestinations...)
+
+	b.workflow.timers[key] = timeouts[Type, Status]{
+		name: name,
+		transitions: []timeout[Type, Status]{
+			{
+				TimerFunc:   timer,
+				TimeoutFunc: tf,
+			},
+		},
+	}
+
+	return &timeoutUpdater[Type, Status]{
+		from:     from,
+		name:     name,
+		workflow: b.workflow,
+	}
+}
+
+// CancelTimer cancels the pending timeouts of the run's timer, added using AddTimer, so that the timer does not
+// expire whilst the run's other timers are left in place. ErrTimeoutNotFound is returned when the run has no pending
+// timeouts for the timer.
+func (w *Workflow[Type, Status]) CancelTimer(ctx context.Context, runID, name string) error {
+	pending, err := w.PendingTimeouts(ctx, runID)
+	if err != nil {
+		return err
+	}
+
+	var cancelled bool
+	for _, t := range pending {
+		if t.Name != name {
+			continue
+		}
+
+		err := w.timeoutStore.Cancel(ctx, t.ID)
+		if err != nil {
+			return err
+		}
+
+		cancelled = true
+	}
+
+	if !cancelled {
+		return fmt.Errorf("cancel timer: %w, meta: %v", ErrTimeoutNotFound, map[string]string{
+			"run_id": runID,
+			"timer":  name,
+		})
+	}
+
+	return nil
+}
+
+// createTimeout creates the timeout of the run using NamedTimeoutStore when the timeout belongs to a named timer.
+func (w *Workflow[Type, Status]) createTimeout(
+	ctx context.Context,
+	r *Run[Type, Status],
+	status int,
+	name string,
+	expireAt time.Time,
+) error {
+	if name == "" {
+		return w.timeoutStore.Create(ctx, r.WorkflowName, r.ForeignID, r.RunID, status, expireAt)
+	}
+
+	store, ok := unwrapTimeoutStore(w.timeoutStore).(NamedTimeoutStore)
+	if !ok {
+		return errors.New("timeout store does not implement NamedTimeoutStore")
+	}
+
+	return store.CreateNamed(ctx, r.WorkflowName, r.ForeignID, r.RunID, status, name, expireAt)
+}
+
+// timerRole returns the role of a timer's process which is the process name prefixed by the name of the timer for
+// the timers added using AddTimer.
+func timerRole(name, process string) string {
+	if name == "" {
+		return process
+	}
+
+	return makeRole("timer", name, process)
+}
+
+// unwrapTimeoutStore returns the underlying TimeoutStore so that its optional interfaces can be found. Decorators of
+// the TimeoutStore, such as those in adapters/instrumented, are unwrapped using their Unwrap method.
+func unwrapTimeoutStore(store TimeoutStore) TimeoutStore {
+	for {
+		u, ok := store.(interface{ Unwrap() TimeoutStore })
+		if !ok {
+			return store
+		}
+
+		store = u.Unwrap()
+	}
+}
+
+// ReminderFunc is called every time a reminder, added using AddReminder, fires such as to send the reminder. Changes
+// made to the run are not stored. A non-nil error is retried in the same way as an error returned by a TimeoutFunc.
+type ReminderFunc[Type any, Status StatusType] func(ctx context.Context, r *Run[Type, Status], now time.Time) error
+
+// AddReminder adds a named timer to the status that calls remind when it fires without moving the run on. After
+// every reminder the timer is called again to schedule the next reminder, and so the reminders repeat whilst the run
+// remains in the status, until the timer returns the zero time or a time that is not after now. For example, a
+// DurationTimerFunc of a day reminds the run every day until it moves on. Reminders are delivered at least once and
+// can be cancelled individually using CancelTimer.
+//
+// Reminders are timers, and so the name must be unique amongst the timers of the status, and require a TimeoutStore
+// that implements NamedTimeoutStore.
+func (b *Builder[Type, Status]) AddReminder(
+	from Status,
+	name string,
+	timer TimerFunc[Type, Status],
+	remind ReminderFunc[Type, Status],
+) *timeoutUpdater[Type, Status] {
+	u := b.AddTimer(from, name, timer, nil)
+	u.get().transitions[0].ReminderFunc = remind
+	return u
+}
+
+// remind calls the reminder of the run and schedules the run's next reminder.
+func remind[Type any, Status StatusType](
+	ctx context.Context,
+	w *Workflow[Type, Status],
+	config timeout[Type, Status],
+	r *Run[Type, Status],
+	timeout TimeoutRecord,
+) error {
+	// The clock can step backwards after the reminder expires, such as when it is corrected, and so the next reminder
+	// is scheduled from no earlier than when this one expired to prevent it from firing twice.
+	now := notBefore(w.clock.Now(), timeout.ExpireAt)
+	err := config.ReminderFunc(ctx, r, now)
+	if err != nil {
+		return err
+	}
+
+	next, err := config.TimerFunc(ctx, r, now)
+	if err != nil {
+		return err
+	}
+
+	if !next.After(now) {
+		return nil
+	}
+
+	return w.createTimeout(ctx, r, timeout.Status, timeout.Name, next)
+}
+
+// notBefore returns now unless t is after it, such as when the clock of this host is behind the clock that produced
+// t or has stepped backwards, in which case t is returned so that time never appears to go backwards.
+func notBefore(now, t time.Time) time.Time {
+	if t.After(now) {
+		return t
+	}
+
+	return now
+}
@@ -18,6 +18,17 @@ func Topic(workflowName string, statusType int) string {
 	}, topicSeparator)
 }
 
+// VersionedTopic is the topic of the status for runs that are pinned to a version of the workflow using
+// Builder.Version.
+func VersionedTopic(workflowName string, version int, statusType int) string {
+	name := strings.ReplaceAll(workflowName, " ", emptySpaceReplacement)
+	return strings.Join([]string{
+		name,
+		"v" + strconv.FormatInt(int64(version), 10),
+		strconv.FormatInt(int64(statusType), 10),
+	}, topicSeparator)
+}
+
 func DeleteTopic(workflowName string) string {
 	name := strings.ReplaceAll(workflowName, " ", emptySpaceReplacement)
 	return strings.Join([]string{
@@ -0,0 +1,128 @@
+package workflow
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const tracerName = "github.com/luno/workflow"
+
+// WithTracerProvider enables OpenTelemetry tracing of the workflow's runs. A span is created when a run is triggered
+// and for every step, callback, and timeout that processes the run. The span context is stored with the run's Record
+// and added as a header to its events so that the entire run appears as a single distributed trace.
+func WithTracerProvider(tp trace.TracerProvider) BuildOption {
+	return func(bo *buildOptions) {
+		bo.tracerProvider = tp
+	}
+}
+
+var tracePropagator = propagation.TraceContext{}
+
+// injectTraceParent returns the W3C traceparent of the span in the context.
+func injectTraceParent(ctx context.Context) string {
+	carrier := propagation.MapCarrier{}
+	tracePropagator.Inject(ctx, carrier)
+	return carrier.Get("traceparent")
+}
+
+// extractTraceParent returns a context containing the remote span context of the traceparent.
+func extractTraceParent(ctx context.Context, traceParent string) context.Context {
+	if traceParent == "" {
+		return ctx
+	}
+
+	return tracePropagator.Extract(ctx, propagation.MapCarrier{"traceparent": traceParent})
+}
+
+// startRunSpan starts a new span, as a child of the record's span, and updates the record to reference the new span
+// so that the next process continues the trace. A noop span is returned if tracing is not enabled.
+func (w *Workflow[Type, Status]) startRunSpan(
+	ctx context.Context,
+	record *Record,
+	name string,
+) (context.Context, trace.Span) {
+	if w.tracer == nil {
+		return ctx, noop.Span{}
+	}
+
+	ctx, span := w.tracer.Start(
+		extractTraceParent(ctx, record.Meta.TraceParent),
+		w.Name()+" "+name,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("workflow.name", w.Name()),
+			attribute.String("workflow.foreign_id", record.ForeignID),
+			attribute.String("workflow.run_id", record.RunID),
+			attribute.String("workflow.status", Status(record.Status).String()),
+		),
+	)
+
+	record.Meta.TraceParent = injectTraceParent(ctx)
+	return ctx, span
+}
+
+// endRunSpan records the outcome of the process on the span and ends it.
+func (w *Workflow[Type, Status]) endRunSpan(span trace.Span, next Status, err error) {
+	if w.tracer == nil {
+		return
+	}
+
+	defer span.End()
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return
+	}
+
+	if skipUpdate(next) {
+		span.SetAttributes(attribute.String("workflow.skip", skipUpdateDescription(next)))
+		return
+	}
+
+	span.SetAttributes(attribute.String("workflow.next_status", next.String()))
+	if w.statusGraph.IsTerminal(int(next)) {
+		span.AddEvent("run completed")
+	}
+}
+
+// traceStep wraps the ConsumerFunc in a span when tracing is enabled.
+func (w *Workflow[Type, Status]) traceStep(fn ConsumerFunc[Type, Status]) ConsumerFunc[Type, Status] {
+	if w.tracer == nil {
+		return fn
+	}
+
+	return func(ctx context.Context, r *Run[Type, Status]) (Status, error) {
+		ctx, span := w.startRunSpan(ctx, &r.Record, "step")
+		next, err := fn(ctx, r)
+		w.endRunSpan(span, next, err)
+		return next, err
+	}
+}
+
+// traceTrigger starts the root span of a new run and stores its span context with the record.
+func (w *Workflow[Type, Status]) traceTrigger(ctx context.Context, record *Record) trace.Span {
+	if w.tracer == nil {
+		return noop.Span{}
+	}
+
+	ctx, span := w.tracer.Start(
+		ctx,
+		w.Name()+" trigger",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("workflow.name", w.Name()),
+			attribute.String("workflow.foreign_id", record.ForeignID),
+			attribute.String("workflow.run_id", record.RunID),
+			attribute.String("workflow.status", Status(record.Status).String()),
+		),
+	)
+
+	record.Meta.TraceParent = injectTraceParent(ctx)
+	return span
+}
@@ -0,0 +1,68 @@
+package workflow_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestWithTracerProvider(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	b := workflow.NewBuilder[MyType, status]("tracing")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		return StatusMiddle, nil
+	}, StatusMiddle)
+	b.AddStep(StatusMiddle, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	recordStore := memrecordstore.New()
+	wf := b.Build(
+		memstreamer.New(),
+		recordStore,
+		memrolescheduler.New(),
+		workflow.WithTracerProvider(tp),
+	)
+
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "example", StatusStart)
+	require.Nil(t, err)
+
+	_, err = wf.Await(ctx, "example", runID, StatusEnd)
+	require.Nil(t, err)
+
+	spans := recorder.Ended()
+	require.Equal(t, 3, len(spans))
+
+	trigger, start, middle := spans[0], spans[1], spans[2]
+	require.Equal(t, "tracing trigger", trigger.Name())
+	require.Equal(t, "tracing step", start.Name())
+	require.Equal(t, "tracing step", middle.Name())
+
+	// All spans of the run belong to the same trace and are chained in order.
+	require.Equal(t, trigger.SpanContext().TraceID(), middle.SpanContext().TraceID())
+	require.Equal(t, trigger.SpanContext().SpanID(), start.Parent().SpanID())
+	require.Equal(t, start.SpanContext().SpanID(), middle.Parent().SpanID())
+	require.Equal(t, "run completed", middle.Events()[0].Name)
+
+	record, err := recordStore.Lookup(ctx, runID)
+	require.Nil(t, err)
+	require.Contains(t, record.Meta.TraceParent, middle.SpanContext().SpanID().String())
+}
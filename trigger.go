@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"time"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/codes"
 )
 
 func (w *Workflow[Type, Status]) Trigger(
@@ -13,6 +16,21 @@ func (w *Workflow[Type, Status]) Trigger(
 	foreignID string,
 	startingStatus Status,
 	opts ...TriggerOption[Type, Status],
+) (runID string, err error) {
+	if w.runMode == RunModeConsumer {
+		return "", fmt.Errorf("trigger failed: %w", ErrConsumerRunMode)
+	}
+
+	return trigger(ctx, w, w.recordStore.Latest, foreignID, startingStatus, opts...)
+}
+
+// triggerChild triggers the run of a child workflow. Unlike Trigger, child runs are triggered by workflows built
+// using RunModeConsumer as they are triggered by the steps of the parent rather than by the API.
+func (w *Workflow[Type, Status]) triggerChild(
+	ctx context.Context,
+	foreignID string,
+	startingStatus Status,
+	opts ...TriggerOption[Type, Status],
 ) (runID string, err error) {
 	return trigger(ctx, w, w.recordStore.Latest, foreignID, startingStatus, opts...)
 }
@@ -29,12 +47,16 @@ func trigger[Type any, Status StatusType](
 		return "", fmt.Errorf("trigger failed: workflow is not running")
 	}
 
-	if !w.statusGraph.IsValid(int(startingStatus)) {
-		w.logger.Debug(
-			w.ctx,
-			fmt.Sprintf("ensure %v is configured for workflow: %v", startingStatus, w.Name()),
-			map[string]string{},
-		)
+	// Clients created using NewClient leave the validation of the starting status to the workflow's consumers.
+	if !w.undefined && !w.statusGraph.IsValid(int(startingStatus)) {
+		w.logger.event(w.ctx, DebugEvent{
+			Type:    DebugEventStatusNotConfigured,
+			Message: fmt.Sprintf("ensure %v is configured for workflow: %v", startingStatus, w.Name()),
+			Meta: map[string]string{
+				"workflow_name":   w.Name(),
+				"starting_status": startingStatus.String(),
+			},
+		})
 
 		return "", fmt.Errorf("trigger failed: status provided is not configured for workflow: %s", startingStatus)
 	}
@@ -44,12 +66,17 @@ func trigger[Type any, Status StatusType](
 		fn(&o)
 	}
 
+	err = validatePriority(o.priority, w.priorityLanes)
+	if err != nil {
+		return "", err
+	}
+
 	var t Type
 	if o.initialValue != nil {
 		t = *o.initialValue
 	}
 
-	object, err := Marshal(&t)
+	object, err := w.codec.Marshal(&t)
 	if err != nil {
 		return "", err
 	}
@@ -61,17 +88,64 @@ func trigger[Type any, Status StatusType](
 		return "", err
 	}
 
+	if isDuplicateRun(lastRecord, int(startingStatus), o.dedupWindow, w.clock.Now()) {
+		w.logger.event(ctx, DebugEvent{
+			Type:    DebugEventTriggerDeduplicated,
+			Message: "skipping duplicate trigger",
+			Meta: map[string]string{
+				"workflow_name":   w.Name(),
+				"foreign_id":      foreignID,
+				"run_id":          lastRecord.RunID,
+				"starting_status": startingStatus.String(),
+			},
+		})
+
+		return lastRecord.RunID, nil
+	}
+
 	// Check that the last run has completed before triggering a new run.
 	if lastRecord.RunState.Valid() && !lastRecord.RunState.Finished() {
 		// Cannot trigger a new run for this foreignID if there is a workflow in progress.
 		return "", ErrWorkflowInProgress
 	}
 
+	// The rate limit is only applied to triggers that store a run so that deduplicated triggers, and triggers of
+	// foreign IDs that are in progress, do not use up the tokens.
+	if w.triggerLimiter != nil {
+		err := w.triggerLimiter.take()
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if w.triggerQuotas != nil {
+		release, err := w.triggerQuotas.take(ctx, foreignID)
+		if err != nil {
+			return "", err
+		}
+
+		defer func() {
+			if runID == "" {
+				// The run was not stored and so does not count towards the quotas.
+				release()
+			}
+		}()
+	}
+
 	uid, err := uuid.NewUUID()
 	if err != nil {
 		return "", err
 	}
 
+	var startAt *time.Time
+	if o.delay > 0 {
+		o.startAt = w.clock.Now().Add(o.delay)
+	}
+
+	if o.startAt.After(w.clock.Now()) {
+		startAt = &o.startAt
+	}
+
 	runID = uid.String()
 	wr := &Record{
 		WorkflowName: w.Name(),
@@ -80,12 +154,39 @@ func trigger[Type any, Status StatusType](
 		RunState:     RunStateInitiated,
 		Status:       int(startingStatus),
 		Object:       object,
-		CreatedAt:    w.clock.Now(),
-		UpdatedAt:    w.clock.Now(),
+		Meta: RecordMeta{
+			Version:  w.version,
+			Pinned:   w.pinned,
+			Priority: o.priority,
+			Metadata: maps.Clone(o.metadata),
+			StartAt:  startAt,
+		},
+		CreatedAt: w.clock.Now(),
+		UpdatedAt: w.clock.Now(),
+	}
+
+	span := w.traceTrigger(ctx, wr)
+	defer span.End()
+
+	// Triggers made outside of a workflow process are timestamped using the workflow's clock.
+	if transitionFromContext(ctx).clock == nil {
+		ctx = withTransitionProcess(ctx, "", w.clock)
+	}
+
+	// The start is scheduled before the run is stored so that a stored run is always started.
+	if startAt != nil {
+		err = w.scheduleStart(ctx, wr, *startAt)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			return "", err
+		}
 	}
 
 	err = updateRecord(ctx, w.recordStore.Store, wr, RunStateUnknown)
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return "", err
 	}
 
@@ -94,6 +195,11 @@ func trigger[Type any, Status StatusType](
 
 type triggerOpts[Type any, Status StatusType] struct {
 	initialValue *Type
+	dedupWindow  time.Duration
+	priority     int
+	metadata     map[string]string
+	startAt      time.Time
+	delay        time.Duration
 }
 
 type TriggerOption[Type any, Status StatusType] func(o *triggerOpts[Type, Status])
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -45,4 +46,85 @@ func Test_trigger(t *testing.T) {
 		}, "1", statusStart)
 		require.True(t, errors.Is(err, ErrWorkflowInProgress))
 	})
+	t.Run("Returns the existing run ID when the run is a duplicate", func(t *testing.T) {
+		ctx := context.Background()
+		w.calledRun = true
+
+		runID, err := trigger(ctx, w, func(ctx context.Context, workflowName, foreignID string) (*Record, error) {
+			return &Record{
+				WorkflowName: "trigger test",
+				ForeignID:    "1",
+				RunID:        "existing",
+				RunState:     RunStateCompleted,
+				Status:       int(statusMiddle),
+				Meta: RecordMeta{
+					StepHistory: []int{int(statusStart)},
+				},
+				CreatedAt: w.clock.Now().Add(-time.Minute),
+			}, nil
+		}, "1", statusStart, WithDedupWindow[string, testStatus](time.Hour))
+		require.Nil(t, err)
+		require.Equal(t, "existing", runID)
+	})
+}
+
+func Test_isDuplicateRun(t *testing.T) {
+	now := time.Now()
+	testCases := []struct {
+		name     string
+		record   *Record
+		window   time.Duration
+		expected bool
+	}{
+		{
+			name:   "No previous run",
+			record: &Record{},
+			window: time.Hour,
+		},
+		{
+			name: "Dedup disabled",
+			record: &Record{
+				RunState:  RunStateCompleted,
+				Status:    int(statusStart),
+				CreatedAt: now,
+			},
+		},
+		{
+			name: "Previous run within window",
+			record: &Record{
+				RunState:  RunStateRunning,
+				Status:    int(statusStart),
+				CreatedAt: now.Add(-time.Minute),
+			},
+			window:   time.Hour,
+			expected: true,
+		},
+		{
+			name: "Previous run outside of window",
+			record: &Record{
+				RunState:  RunStateCompleted,
+				Status:    int(statusStart),
+				CreatedAt: now.Add(-2 * time.Hour),
+			},
+			window: time.Hour,
+		},
+		{
+			name: "Previous run started at a different status",
+			record: &Record{
+				RunState: RunStateCompleted,
+				Status:   int(statusEnd),
+				Meta: RecordMeta{
+					StepHistory: []int{int(statusMiddle)},
+				},
+				CreatedAt: now,
+			},
+			window: time.Hour,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, isDuplicateRun(tc.record, int(statusStart), tc.window, now))
+		})
+	}
 }
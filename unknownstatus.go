@@ -0,0 +1,110 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/luno/workflow/internal/metrics"
+)
+
+// ErrUnknownStatus is the error recorded for runs that are parked, or forwarded to the dead-letter queue, by
+// UnknownStatusPolicy because their event is for a status that the workflow does not know.
+var ErrUnknownStatus = errors.New("unknown status")
+
+// UnknownStatusPolicy defines what happens to the events that are received for statuses that the workflow does not
+// know, such as the statuses that have been removed from the workflow while runs were still in them.
+type UnknownStatusPolicy int
+
+const (
+	// UnknownStatusLog logs the event as an error and skips it, and is the default.
+	UnknownStatusLog UnknownStatusPolicy = 0
+	// UnknownStatusPark pauses the run of the event, recording ErrUnknownStatus in its history, so that an operator
+	// can move the run on using ForceTransition.
+	UnknownStatusPark UnknownStatusPolicy = 1
+	// UnknownStatusForward publishes the run of the event to the dead-letter queue, configured using
+	// WithDeadLetterQueue, with ErrUnknownStatus as its error, so that the run can be triaged externally.
+	UnknownStatusForward UnknownStatusPolicy = 2
+)
+
+func (p UnknownStatusPolicy) String() string {
+	switch p {
+	case UnknownStatusLog:
+		return "Log"
+	case UnknownStatusPark:
+		return "Park"
+	case UnknownStatusForward:
+		return "Forward"
+	default:
+		return "Unknown"
+	}
+}
+
+// WithUnknownStatusPolicy defines what happens to the events that the workflow receives for statuses that it does not
+// know, rather than the events being dropped. Every such event is counted by the
+// workflow_process_unknown_status_events_count metric regardless of the policy. The events of statuses are only
+// received by consumers of other statuses when the workflow is built using WithMultiplexedConsumers as the other
+// consumers only receive the events of their own status. UnknownStatusForward requires WithDeadLetterQueue.
+func WithUnknownStatusPolicy(policy UnknownStatusPolicy) BuildOption {
+	return func(bo *buildOptions) {
+		bo.unknownStatusPolicy = policy
+	}
+}
+
+// unknownStatus handles the event, received by the process, of a status that the workflow does not know using the
+// workflow's UnknownStatusPolicy.
+func (w *Workflow[Type, Status]) unknownStatus(ctx context.Context, processName string, e *Event) error {
+	metrics.UnknownStatusEvents.WithLabelValues(w.Name(), processName, strconv.Itoa(e.Type)).Inc()
+
+	meta := map[string]string{
+		"workflow_name": w.Name(),
+		"process_name":  processName,
+		"topic":         e.Headers[HeaderTopic],
+		"event_id":      strconv.FormatInt(e.ID, 10),
+		"run_id":        e.ForeignID,
+		"status":        strconv.Itoa(e.Type),
+		"policy":        w.unknownStatusPolicy.String(),
+	}
+
+	if w.unknownStatusPolicy == UnknownStatusLog {
+		w.logger.Error(ctx, fmt.Errorf("%w, meta: %v", ErrUnknownStatus, meta))
+		return nil
+	}
+
+	record, err := w.recordStore.Lookup(ctx, e.ForeignID)
+	if errors.Is(err, ErrRecordNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	// Runs that have since moved on, or stopped, are no longer waiting on the unknown status.
+	if record.Status != e.Type || record.RunState.Stopped() {
+		return nil
+	}
+
+	statusErr := fmt.Errorf("%w: %d", ErrUnknownStatus, e.Type)
+	switch w.unknownStatusPolicy {
+	case UnknownStatusPark:
+		if !runStateTransitions[record.RunState][RunStatePaused] {
+			return nil
+		}
+
+		previousRunState := record.RunState
+		record.RunState = RunStatePaused
+		record.UpdatedAt = w.clock.Now()
+		err = updateRecord(withTransitionError(ctx, statusErr), w.recordStore.Store, record, previousRunState)
+		if err != nil {
+			return err
+		}
+	case UnknownStatusForward:
+		err = w.deadLetterFunc()(ctx, processName, record, statusErr)
+		if err != nil {
+			return err
+		}
+	}
+
+	w.logger.Error(ctx, fmt.Errorf("%w, meta: %v", ErrUnknownStatus, meta))
+	return nil
+}
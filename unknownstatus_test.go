@@ -0,0 +1,93 @@
+package workflow_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+// removedStatus is a status that has been removed from the workflow whilst runs were still in it.
+const removedStatus = 99
+
+// removedTopicStreamer continues to receive the topic of the removed status using multiplexed receivers, such as a
+// consumer group that is still subscribed to the topic.
+type removedTopicStreamer struct {
+	*memstreamer.StreamConstructor
+	removedTopic string
+}
+
+func (s *removedTopicStreamer) NewMultiplexedReceiver(
+	ctx context.Context,
+	topics []string,
+	name string,
+	opts ...workflow.ReceiverOption,
+) (workflow.EventReceiver, error) {
+	return s.StreamConstructor.NewMultiplexedReceiver(ctx, append(topics, s.removedTopic), name, opts...)
+}
+
+func TestWithUnknownStatusPolicy(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("unknown status")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	recordStore := memrecordstore.New()
+	wf := b.Build(
+		&removedTopicStreamer{
+			StreamConstructor: memstreamer.New(),
+			removedTopic:      workflow.Topic("unknown status", removedStatus),
+		},
+		recordStore,
+		memrolescheduler.New(),
+		workflow.WithMultiplexedConsumers(),
+		workflow.WithUnknownStatusPolicy(workflow.UnknownStatusPark),
+		workflow.WithOutboxPollingFrequency(10*time.Millisecond),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	err := recordStore.Store(ctx, &workflow.Record{
+		WorkflowName: wf.Name(),
+		ForeignID:    "foreignID",
+		RunID:        "runID",
+		RunState:     workflow.RunStateRunning,
+		Status:       removedStatus,
+		Object:       []byte(`"object"`),
+	})
+	require.Nil(t, err)
+
+	require.Eventually(t, func() bool {
+		record, err := recordStore.Lookup(ctx, "runID")
+		require.Nil(t, err)
+		return record.RunState == workflow.RunStatePaused
+	}, 5*time.Second, 10*time.Millisecond)
+
+	history, err := wf.RunHistory(ctx, "runID")
+	require.Nil(t, err)
+	require.Equal(t, "unknown status: 99", history[len(history)-1].Error)
+}
+
+func TestWithUnknownStatusPolicy_forwardRequiresDeadLetterQueue(t *testing.T) {
+	b := workflow.NewBuilder[string, status]("unknown status")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	require.PanicsWithValue(t, "cannot forward the events of unknown statuses without a dead-letter queue. Use WithDeadLetterQueue to configure one", func() {
+		b.Build(
+			memstreamer.New(),
+			memrecordstore.New(),
+			memrolescheduler.New(),
+			workflow.WithUnknownStatusPolicy(workflow.UnknownStatusForward),
+		)
+	})
+}
@@ -2,7 +2,7 @@ package workflow
 
 import "encoding/json"
 
-// Unmarshal create a single point of change if the decoding changes.
+// Unmarshal decodes using JSONCodec, the default Codec of a workflow.
 func Unmarshal[T any](b []byte, t *T) error {
 	return json.Unmarshal(b, t)
 }
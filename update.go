@@ -3,6 +3,7 @@ package workflow
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"k8s.io/utils/clock"
 
@@ -17,9 +18,15 @@ type (
 	updater[Type any, Status StatusType] func(ctx context.Context, current Status, next Status, run *Run[Type, Status]) error
 )
 
-func newUpdater[Type any, Status StatusType](lookup lookupFunc, store storeFunc, graph *graph.Graph, clock clock.Clock) updater[Type, Status] {
+func newUpdater[Type any, Status StatusType](
+	lookup lookupFunc,
+	store storeFunc,
+	codec Codec,
+	graph *graph.Graph,
+	clock clock.Clock,
+) updater[Type, Status] {
 	return func(ctx context.Context, current Status, next Status, record *Run[Type, Status]) error {
-		object, err := Marshal(&record.Object)
+		object, err := codec.Marshal(record.Object)
 		if err != nil {
 			return err
 		}
@@ -37,10 +44,24 @@ func newUpdater[Type any, Status StatusType](lookup lookupFunc, store storeFunc,
 			RunState:     runState,
 			Status:       int(next),
 			Object:       object,
+			Meta:         record.Meta,
 			CreatedAt:    record.CreatedAt,
 			UpdatedAt:    clock.Now(),
 		}
 
+		// Keep track of the steps that have been completed so that they can be compensated if the run is cancelled.
+		updatedRecord.Meta.StepHistory = append(slices.Clone(record.Meta.StepHistory), int(current))
+		updatedRecord.Meta.ReplayedSteps = replayedStep(record.Meta.ReplayedSteps, int(current))
+		// The checkpoint of the step is cleared as the step has finished.
+		updatedRecord.Meta.Checkpoint = nil
+		updatedRecord.Meta.History = appendTransition(withDecisionReasons(ctx, record.reasons), record.Meta.History, Transition{
+			FromStatus:   int(current),
+			ToStatus:     int(next),
+			FromRunState: record.RunState,
+			ToRunState:   runState,
+			At:           updatedRecord.UpdatedAt,
+		})
+
 		latest, err := lookup(ctx, updatedRecord.RunID)
 		if err != nil {
 			return err
@@ -59,6 +80,11 @@ func newUpdater[Type any, Status StatusType](lookup lookupFunc, store storeFunc,
 
 		// Push run state changes for observability
 		metrics.RunStateChanges.WithLabelValues(record.WorkflowName, record.RunState.String(), updatedRecord.RunState.String()).Inc()
+		metrics.ObserveWithExemplar(
+			metrics.TimeInStatus.WithLabelValues(record.WorkflowName, current.String()),
+			clock.Since(record.UpdatedAt).Seconds(),
+			exemplar(record.RunID, record.Meta.TraceParent),
+		)
 
 		return store(ctx, updatedRecord)
 	}
@@ -89,6 +115,23 @@ func validateTransition[Status StatusType](current, next Status, graph *graph.Gr
 }
 
 func updateRecord(ctx context.Context, store storeFunc, record *Record, previousRunState RunState) error {
+	t := Transition{
+		ToStatus:     record.Status,
+		FromRunState: previousRunState,
+		ToRunState:   record.RunState,
+	}
+
+	// Runs are created when their previous run state is unknown.
+	if previousRunState != RunStateUnknown {
+		t.FromStatus = record.Status
+	}
+
+	if transitionFromContext(ctx).clock == nil {
+		t.At = record.UpdatedAt
+	}
+
+	record.Meta.History = appendTransition(ctx, record.Meta.History, t)
+
 	// Push run state changes for observability
 	metrics.RunStateChanges.WithLabelValues(record.WorkflowName, previousRunState.String(), record.RunState.String()).Inc()
 
@@ -155,7 +155,7 @@ func TestUpdater(t *testing.T) {
 				return nil
 			}
 
-			updater := newUpdater[string, testStatus](tc.lookup, store, g, c)
+			updater := newUpdater[string, testStatus](tc.lookup, store, JSONCodec{}, g, c)
 			err := updater(ctx, tc.current, tc.update.Status, &tc.update)
 			if err != nil {
 				require.Equal(t, tc.expectedErr.Error(), err.Error())
@@ -0,0 +1,78 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+)
+
+// Var is the typed key of a run variable. Run variables are a scratch space that is stored with the run, separately
+// from its Object, for data that belongs to the engine or its extensions, such as attempt counts, middleware state,
+// or saga markers, rather than to the user's domain. Variables are read and written using GetVar, SetVar, and
+// DeleteVar and, like metadata, are stored with the run when a step moves the run onto its next status.
+//
+// Vars should be declared once, as package level variables, so that the name is always read with the same type.
+type Var[T any] struct {
+	name string
+}
+
+// NewVar returns the key of the run variable with the provided name. Extensions should prefix the name, such as
+// "saga.compensated", so that their variables don't clash with those of other extensions.
+func NewVar[T any](name string) Var[T] {
+	return Var[T]{name: name}
+}
+
+// Name returns the name that the variable is stored under.
+func (v Var[T]) Name() string {
+	return v.name
+}
+
+// GetVar returns the value of the run's variable and false when the run does not have the variable.
+func GetVar[T any, Type any, Status StatusType](r *Run[Type, Status], v Var[T]) (T, bool, error) {
+	var value T
+	b, ok := r.Meta.Variables[v.name]
+	if !ok {
+		return value, false, nil
+	}
+
+	err := json.Unmarshal(b, &value)
+	if err != nil {
+		return value, false, fmt.Errorf("get var: %w, meta: %v", err, map[string]string{
+			"var": v.name,
+		})
+	}
+
+	return value, true, nil
+}
+
+// SetVar sets the value of the run's variable. Values are encoded as JSON and so only the exported fields of structs
+// are stored.
+func SetVar[T any, Type any, Status StatusType](r *Run[Type, Status], v Var[T], value T) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("set var: %w, meta: %v", err, map[string]string{
+			"var": v.name,
+		})
+	}
+
+	// The variables are copied as the run's Meta may be shared with the record it was built from.
+	variables := maps.Clone(r.Meta.Variables)
+	if variables == nil {
+		variables = make(map[string]json.RawMessage)
+	}
+
+	variables[v.name] = b
+	r.Meta.Variables = variables
+	return nil
+}
+
+// DeleteVar removes the variable from the run.
+func DeleteVar[T any, Type any, Status StatusType](r *Run[Type, Status], v Var[T]) {
+	if _, ok := r.Meta.Variables[v.name]; !ok {
+		return
+	}
+
+	variables := maps.Clone(r.Meta.Variables)
+	delete(variables, v.name)
+	r.Meta.Variables = variables
+}
@@ -0,0 +1,95 @@
+package workflow_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+type sagaMarker struct {
+	Step        string
+	Compensated bool
+}
+
+var (
+	attemptsVar = workflow.NewVar[int]("test.attempts")
+	sagaVar     = workflow.NewVar[sagaMarker]("test.saga")
+)
+
+func TestRunVariables(t *testing.T) {
+	b := workflow.NewBuilder[MyType, status]("variables")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		err := workflow.SetVar(r, attemptsVar, 1)
+		if err != nil {
+			return 0, err
+		}
+
+		err = workflow.SetVar(r, sagaVar, sagaMarker{Step: "reserve"})
+		if err != nil {
+			return 0, err
+		}
+
+		return StatusMiddle, nil
+	}, StatusMiddle)
+	b.AddStep(StatusMiddle, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		attempts, ok, err := workflow.GetVar(r, attemptsVar)
+		if err != nil {
+			return 0, err
+		} else if !ok {
+			return 0, nil
+		}
+
+		err = workflow.SetVar(r, attemptsVar, attempts+1)
+		if err != nil {
+			return 0, err
+		}
+
+		workflow.DeleteVar(r, sagaVar)
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "foreignID", StatusStart)
+	require.Nil(t, err)
+
+	run, err := wf.Await(ctx, "foreignID", runID, StatusEnd, workflow.WithAwaitPollingFrequency(10*time.Millisecond))
+	require.Nil(t, err)
+
+	attempts, ok, err := workflow.GetVar(run, attemptsVar)
+	require.Nil(t, err)
+	require.True(t, ok)
+	require.Equal(t, 2, attempts)
+
+	_, ok, err = workflow.GetVar(run, sagaVar)
+	require.Nil(t, err)
+	require.False(t, ok)
+
+	// The Object is not affected by the variables.
+	require.Equal(t, MyType{}, *run.Object)
+}
+
+func TestGetVarWithMismatchedType(t *testing.T) {
+	r := &workflow.Run[MyType, status]{}
+	err := workflow.SetVar(r, workflow.NewVar[string]("test.name"), "not a number")
+	require.Nil(t, err)
+
+	_, ok, err := workflow.GetVar(r, workflow.NewVar[int]("test.name"))
+	require.NotNil(t, err)
+	require.False(t, ok)
+}
@@ -0,0 +1,161 @@
+package workflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ErrIncompatibleVersion is returned by a process when it receives an event of a run that was triggered by a version
+// of the workflow that the host is unable to process. The process gives up its role, without acknowledging the event,
+// so that a host running a compatible version can take over the role and process the event.
+var ErrIncompatibleVersion = errors.New("run triggered by an incompatible version of the workflow")
+
+// CompatibilityPolicy decides whether a host running hostVersion of the workflow is able to process a run that was
+// triggered with runVersion of the workflow. Returning false results in the host giving up the roles of its processes
+// when they receive the run's events, and skipping the run's timeouts, so that they can be handled by a host running a
+// compatible version.
+type CompatibilityPolicy func(hostVersion, runVersion int) bool
+
+// SkipNewerVersions is the default CompatibilityPolicy and allows hosts to process runs triggered by the same or
+// older versions of the workflow but not runs triggered by newer versions.
+func SkipNewerVersions(hostVersion, runVersion int) bool {
+	return runVersion <= hostVersion
+}
+
+// SkipOtherVersions only allows hosts to process runs triggered by the exact same version of the workflow.
+func SkipOtherVersions(hostVersion, runVersion int) bool {
+	return runVersion == hostVersion
+}
+
+// WithGraphVersion sets the version of the workflow's definition. The version should be incremented whenever the
+// status graph changes in a way that older binaries would be unable to process. The version is stored with each run
+// when it is triggered and is added to all of the run's events as HeaderVersion so that hosts running a different
+// version can apply the CompatibilityPolicy. A version of 0 (the default) disables the compatibility checks.
+// WithGraphVersion cannot be used with Builder.Version, which sets the version of pinned workflows.
+func WithGraphVersion(version int) BuildOption {
+	return func(bo *buildOptions) {
+		bo.version = version
+	}
+}
+
+// WithCompatibilityPolicy allows for overriding the default CompatibilityPolicy of SkipNewerVersions.
+func WithCompatibilityPolicy(p CompatibilityPolicy) BuildOption {
+	return func(bo *buildOptions) {
+		bo.compatibilityPolicy = p
+	}
+}
+
+// isCompatible returns true if the host is able to process a run of the provided version. Hosts without a version
+// and runs triggered before versioning was enabled are always considered compatible.
+func isCompatible(policy CompatibilityPolicy, hostVersion, runVersion int) bool {
+	if hostVersion == 0 || runVersion == 0 || policy == nil {
+		return true
+	}
+
+	return policy(hostVersion, runVersion)
+}
+
+// incompatibleEvent returns true if the event is of a run that was triggered by a version of the workflow that the
+// host is unable to process.
+func incompatibleEvent(policy CompatibilityPolicy, hostVersion int, e *Event) bool {
+	v, ok := e.Headers[HeaderVersion]
+	if !ok {
+		return false
+	}
+
+	runVersion, err := strconv.Atoi(v)
+	if err != nil {
+		return false
+	}
+
+	return !isCompatible(policy, hostVersion, runVersion)
+}
+
+// versionFilter filters out the events of runs triggered by other versions of a pinned workflow. Each pinned version
+// has its own roles and cursors and so the events are still processed by the version that triggered the run.
+func (w *Workflow[Type, Status]) versionFilter() EventFilter {
+	return func(e *Event) bool {
+		return w.pinned && incompatibleEvent(w.compatibilityPolicy, w.version, e)
+	}
+}
+
+// versionGuard wraps the consumer function and returns ErrIncompatibleVersion, without processing or acknowledging
+// the event, when the event is of a run that the host is unable to process. The versions of workflows that are not
+// pinned share their roles and cursors and so the event must be left for a host running a compatible version.
+func (w *Workflow[Type, Status]) versionGuard(fn func(ctx context.Context, e *Event) error) func(ctx context.Context, e *Event) error {
+	if w.version == 0 || w.pinned {
+		return fn
+	}
+
+	return func(ctx context.Context, e *Event) error {
+		if incompatibleEvent(w.compatibilityPolicy, w.version, e) {
+			return w.incompatibleVersionErr(e)
+		}
+
+		return fn(ctx, e)
+	}
+}
+
+// versionGuardBatch is the equivalent of versionGuard for batch consumers.
+func (w *Workflow[Type, Status]) versionGuardBatch(fn func(ctx context.Context, events []*Event) error) func(ctx context.Context, events []*Event) error {
+	if w.version == 0 || w.pinned {
+		return fn
+	}
+
+	return func(ctx context.Context, events []*Event) error {
+		for _, e := range events {
+			if incompatibleEvent(w.compatibilityPolicy, w.version, e) {
+				return w.incompatibleVersionErr(e)
+			}
+		}
+
+		return fn(ctx, events)
+	}
+}
+
+func (w *Workflow[Type, Status]) incompatibleVersionErr(e *Event) error {
+	return fmt.Errorf("%w, meta: %v", ErrIncompatibleVersion, map[string]string{
+		"run_id":       e.ForeignID,
+		"run_version":  e.Headers[HeaderVersion],
+		"host_version": strconv.FormatInt(int64(w.version), 10),
+	})
+}
+
+// Version pins the runs of the workflow to the provided version of its graph so that multiple versions of the
+// workflow, with different status graphs, can be run side by side. Runs continue on the graph of the version that
+// triggered them whilst new runs use the graph of the version that Trigger is called on. The version is stored with
+// each run's Record and the events of each version's statuses are published to their own topics (see VersionedTopic).
+//
+// Each version has its own roles and only processes the runs that it triggered. Callback returns
+// ErrRunVersionMismatch when the run was triggered by another version. Connectors and schedules should only be
+// added to the latest version.
+func (b *Builder[Type, Status]) Version(version int) *Builder[Type, Status] {
+	if version <= 0 {
+		panic("version must be greater than zero")
+	}
+
+	b.workflow.version = version
+	b.workflow.pinned = true
+	return b
+}
+
+// roleName is the workflow name used in the roles of processes that consume the workflow's runs. Pinned versions
+// include their version so that each version has its own roles and cursors when run side by side.
+func (w *Workflow[Type, Status]) roleName() string {
+	if !w.pinned {
+		return w.Name()
+	}
+
+	return w.Name() + topicSeparator + "v" + strconv.FormatInt(int64(w.version), 10)
+}
+
+// topic returns the topic that the events of the status are published to.
+func (w *Workflow[Type, Status]) topic(status Status) string {
+	if !w.pinned {
+		return Topic(w.Name(), int(status))
+	}
+
+	return VersionedTopic(w.Name(), w.version, int(status))
+}
@@ -0,0 +1,171 @@
+package workflow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/luno/workflow/internal/outboxpb"
+)
+
+func TestIncompatibleEvent(t *testing.T) {
+	testCases := []struct {
+		name        string
+		policy      CompatibilityPolicy
+		hostVersion int
+		headers     map[Header]string
+		expected    bool
+	}{
+		{
+			name:        "Legacy events without a version are consumed",
+			policy:      SkipNewerVersions,
+			hostVersion: 1,
+			headers:     map[Header]string{},
+			expected:    false,
+		},
+		{
+			name:        "Hosts without a version consume all events",
+			policy:      SkipNewerVersions,
+			hostVersion: 0,
+			headers:     map[Header]string{HeaderVersion: "2"},
+			expected:    false,
+		},
+		{
+			name:        "Older versions are consumed",
+			policy:      SkipNewerVersions,
+			hostVersion: 2,
+			headers:     map[Header]string{HeaderVersion: "1"},
+			expected:    false,
+		},
+		{
+			name:        "Newer versions are skipped",
+			policy:      SkipNewerVersions,
+			hostVersion: 1,
+			headers:     map[Header]string{HeaderVersion: "2"},
+			expected:    true,
+		},
+		{
+			name:        "Older versions are skipped when only the same version is allowed",
+			policy:      SkipOtherVersions,
+			hostVersion: 2,
+			headers:     map[Header]string{HeaderVersion: "1"},
+			expected:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, incompatibleEvent(tc.policy, tc.hostVersion, &Event{Headers: tc.headers}))
+		})
+	}
+}
+
+func TestVersionGuard(t *testing.T) {
+	var consumed []string
+	consumeFn := func(ctx context.Context, e *Event) error {
+		consumed = append(consumed, e.ForeignID)
+		return nil
+	}
+
+	newer := &Event{ForeignID: "newer", Headers: map[Header]string{HeaderVersion: "2"}}
+	older := &Event{ForeignID: "older", Headers: map[Header]string{HeaderVersion: "1"}}
+
+	t.Run("Incompatible events are left for a compatible host", func(t *testing.T) {
+		consumed = nil
+		w := &Workflow[string, testStatus]{version: 1, compatibilityPolicy: SkipNewerVersions}
+
+		guard := w.versionGuard(consumeFn)
+		require.ErrorIs(t, guard(context.Background(), newer), ErrIncompatibleVersion)
+		require.Nil(t, guard(context.Background(), older))
+		require.False(t, w.versionFilter()(newer))
+
+		batchGuard := w.versionGuardBatch(func(ctx context.Context, events []*Event) error {
+			for _, e := range events {
+				consumed = append(consumed, e.ForeignID)
+			}
+			return nil
+		})
+		require.ErrorIs(t, batchGuard(context.Background(), []*Event{older, newer}), ErrIncompatibleVersion)
+		require.Equal(t, []string{"older"}, consumed)
+	})
+
+	t.Run("Pinned versions filter out the events of other versions", func(t *testing.T) {
+		consumed = nil
+		w := &Workflow[string, testStatus]{version: 1, pinned: true, compatibilityPolicy: SkipOtherVersions}
+
+		require.True(t, w.versionFilter()(newer))
+		require.False(t, w.versionFilter()(older))
+		require.Nil(t, w.versionGuard(consumeFn)(context.Background(), older))
+		require.Equal(t, []string{"older"}, consumed)
+	})
+}
+
+func TestMakeOutboxEventData_Version(t *testing.T) {
+	data, err := MakeOutboxEventData(Record{
+		WorkflowName: "example",
+		ForeignID:    "andrew",
+		RunID:        "run-id",
+		RunState:     RunStateRunning,
+		Status:       int(statusMiddle),
+		Meta: RecordMeta{
+			Version: 3,
+		},
+	})
+	require.Nil(t, err)
+
+	var r outboxpb.OutboxRecord
+	err = proto.Unmarshal(data.Data, &r)
+	require.Nil(t, err)
+	require.Equal(t, "3", r.Headers[string(HeaderVersion)])
+}
+
+func TestMakeOutboxEventData_Pinned(t *testing.T) {
+	data, err := MakeOutboxEventData(Record{
+		WorkflowName: "example",
+		ForeignID:    "andrew",
+		RunID:        "run-id",
+		RunState:     RunStateRunning,
+		Status:       int(statusMiddle),
+		Meta: RecordMeta{
+			Version: 3,
+			Pinned:  true,
+		},
+	})
+	require.Nil(t, err)
+
+	var r outboxpb.OutboxRecord
+	err = proto.Unmarshal(data.Data, &r)
+	require.Nil(t, err)
+	require.Equal(t, "example-v3-2", r.Headers[string(HeaderTopic)])
+}
+
+func TestBuilderVersion(t *testing.T) {
+	b := NewBuilder[string, testStatus]("example").Version(2)
+	b.AddStep(statusStart, func(ctx context.Context, r *Run[string, testStatus]) (testStatus, error) {
+		return statusEnd, nil
+	}, statusEnd)
+
+	w := b.Build(nil, nil, nil, WithCompatibilityPolicy(SkipNewerVersions))
+	require.Equal(t, 2, w.version)
+	require.True(t, w.pinned)
+	require.Equal(t, "example-v2", w.roleName())
+	require.Equal(t, "example-v2-1", w.topic(statusStart))
+
+	// Pinned versions only process the runs that they triggered.
+	require.False(t, isCompatible(w.compatibilityPolicy, w.version, 1))
+
+	require.PanicsWithValue(t, "version must be greater than zero", func() {
+		NewBuilder[string, testStatus]("example").Version(0)
+	})
+
+	require.PanicsWithValue(t, "cannot configure WithGraphVersion for a workflow pinned using Builder.Version", func() {
+		b := NewBuilder[string, testStatus]("example").Version(2)
+		b.AddStep(statusStart, func(ctx context.Context, r *Run[string, testStatus]) (testStatus, error) {
+			return statusEnd, nil
+		}, statusEnd)
+
+		b.Build(nil, nil, nil, WithGraphVersion(3))
+	})
+}
@@ -0,0 +1,380 @@
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// HeaderWebhookSignature is the HTTP header of the HMAC-SHA256 signature of the body of the webhooks sent by the
+	// workflow, see VerifyWebhook.
+	HeaderWebhookSignature = "X-Workflow-Signature"
+
+	webhookMaxAttempts = 3
+	webhookBackOff     = time.Second
+	webhookTimeout     = 10 * time.Second
+	// webhookFlushTimeout is how long the webhooks that are still queued when the workflow is stopped are given to be
+	// sent.
+	webhookFlushTimeout = 5 * time.Second
+	// webhookQueueSize is the number of webhooks that can be waiting to be sent to each url.
+	webhookQueueSize = 1000
+)
+
+type WebhookEvent int
+
+const (
+	WebhookEventUnknown WebhookEvent = 0
+	// WebhookEventRunCompleted is sent when a run completes.
+	WebhookEventRunCompleted WebhookEvent = 1
+	// WebhookEventRunPaused is sent when a run is paused, such as by an operator using PauseRun.
+	WebhookEventRunPaused WebhookEvent = 2
+	// WebhookEventRunFailed is sent when a run is paused after its step failed repeatedly and exceeded its
+	// PauseAfterErrCount.
+	WebhookEventRunFailed WebhookEvent = 3
+	// WebhookEventRunCancelled is sent when a run is cancelled.
+	WebhookEventRunCancelled WebhookEvent = 4
+	// WebhookEventLagAlert is sent when a process of the workflow starts consuming events that are older than its
+	// LagAlert.
+	WebhookEventLagAlert WebhookEvent = 5
+)
+
+func (e WebhookEvent) String() string {
+	switch e {
+	case WebhookEventRunCompleted:
+		return "RunCompleted"
+	case WebhookEventRunPaused:
+		return "RunPaused"
+	case WebhookEventRunFailed:
+		return "RunFailed"
+	case WebhookEventRunCancelled:
+		return "RunCancelled"
+	case WebhookEventLagAlert:
+		return "LagAlert"
+	default:
+		return "Unknown"
+	}
+}
+
+// WebhookPayload is the JSON body of the webhooks sent by the workflow.
+type WebhookPayload struct {
+	Event        string `json:"event"`
+	WorkflowName string `json:"workflow_name"`
+	// ForeignID, RunID, Status, and RunState are set when the event relates to a specific run.
+	ForeignID string `json:"foreign_id,omitempty"`
+	RunID     string `json:"run_id,omitempty"`
+	Status    string `json:"status,omitempty"`
+	RunState  string `json:"run_state,omitempty"`
+	// Process is set for lag alerts and is the name of the process that is lagging.
+	Process string `json:"process,omitempty"`
+	// Message describes the event, such as the error of the step that failed the run.
+	Message string    `json:"message,omitempty"`
+	At      time.Time `json:"at"`
+}
+
+type webhook struct {
+	url    string
+	secret []byte
+	events map[WebhookEvent]bool
+	// queue holds the bodies that are waiting to be sent to the url, see sendQueuedWebhooks.
+	queue chan []byte
+}
+
+// subscribed returns true when the webhook is sent for the event.
+func (h webhook) subscribed(e WebhookEvent) bool {
+	return len(h.events) == 0 || h.events[e]
+}
+
+// WithWebhook POSTs a WebhookPayload to the url for each of the events, or for all the events when none are provided,
+// so that external systems, such as Slack or PagerDuty bridges, can react to the workflow's runs without polling. The
+// body is signed using HMAC-SHA256 with the secret and the signature is sent in the HeaderWebhookSignature header,
+// see VerifyWebhook. The webhook is retried when the url does not respond with a 2xx status code. Webhooks are queued
+// and sent in the background, so that a slow url does not hold up the workflow's processes, and are dropped, and
+// logged as an error, when more than 1000 webhooks are waiting to be sent to the url. The webhooks that are still
+// queued when the workflow is stopped are sent, without being retried, for up to 5 seconds before they are dropped. The events of runs are queued by a consumer of the workflow's run state changes and lag alerts are
+// queued by the process that is lagging. WithWebhook can be provided multiple times to send webhooks to several urls.
+func WithWebhook(url string, secret string, events ...WebhookEvent) BuildOption {
+	return func(bo *buildOptions) {
+		h := webhook{
+			url:    url,
+			secret: []byte(secret),
+			events: make(map[WebhookEvent]bool),
+		}
+
+		for _, e := range events {
+			h.events[e] = true
+		}
+
+		bo.webhooks = append(bo.webhooks, h)
+	}
+}
+
+// SignWebhook returns the signature of the webhook body using the secret as sent in the HeaderWebhookSignature
+// header.
+func SignWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhook returns true when the signature, from the HeaderWebhookSignature header, is the signature of the
+// webhook body using the secret.
+func VerifyWebhook(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(SignWebhook(secret, body)), []byte(signature))
+}
+
+// sendWebhooks queues the payload to be sent to the webhooks that are subscribed to the event. The payload is dropped,
+// and logged as an error, for the webhooks whose queue is full.
+func (w *Workflow[Type, Status]) sendWebhooks(ctx context.Context, event WebhookEvent, p WebhookPayload) error {
+	p.Event = event.String()
+	p.WorkflowName = w.Name()
+	p.At = w.clock.Now()
+
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	for _, h := range w.webhooks {
+		if !h.subscribed(event) {
+			continue
+		}
+
+		select {
+		case h.queue <- body:
+		default:
+			w.logger.Error(ctx, fmt.Errorf("send webhook: queue full, meta: %v", map[string]string{
+				"url":        h.url,
+				"event":      p.Event,
+				"run_id":     p.RunID,
+				"queue_size": strconv.Itoa(webhookQueueSize),
+			}))
+		}
+	}
+
+	return nil
+}
+
+// webhookSender sends the webhooks that are queued for the url. The webhooks are queued in memory by the processes of
+// this host and so, unlike the other processes, the sender runs on every host rather than awaiting a role. The sender
+// is stopped along with the outbox consumer, once the processes that queue webhooks have exited, and flushes the
+// webhooks that are left in the queue before exiting.
+func webhookSender[Type any, Status StatusType](w *Workflow[Type, Status], index int, h webhook) {
+	processName := makeRole("webhook", "sender", strconv.Itoa(index+1))
+	ctx := w.stageContext(processName, w.outboxShutdownOrder())
+	w.updateState(processName, StateRunning)
+	defer w.updateState(processName, StateShutdown)
+	// Mark that another go routine has launched and been added to internal state
+	w.launching.Done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.flushWebhooks(ctx, h)
+			return
+		case body := <-h.queue:
+			err := sendWebhook(ctx, h, body)
+			if err != nil && ctx.Err() == nil {
+				w.logger.Error(ctx, err)
+			}
+		}
+	}
+}
+
+// flushWebhooks makes a single attempt to send each of the webhooks that are left in the queue, giving up once
+// webhookFlushTimeout has passed.
+func (w *Workflow[Type, Status]) flushWebhooks(ctx context.Context, h webhook) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), webhookFlushTimeout)
+	defer cancel()
+
+	// The sender is the only receiver from the queue and so the queue is never emptied between checking its length
+	// and receiving from it.
+	for len(h.queue) > 0 {
+		if ctx.Err() != nil {
+			w.logger.Error(ctx, fmt.Errorf("flush webhook: %w, meta: %v", ctx.Err(), map[string]string{
+				"url":     h.url,
+				"dropped": strconv.Itoa(len(h.queue)),
+			}))
+			return
+		}
+
+		body := <-h.queue
+		err := postWebhook(ctx, h, body)
+		if err != nil {
+			w.logger.Error(ctx, fmt.Errorf("flush webhook: %w, meta: %v", err, map[string]string{
+				"url": h.url,
+			}))
+		}
+	}
+}
+
+// sendWebhook POSTs the body to the webhook's url, retrying with a backoff until the url responds with a 2xx status
+// code or webhookMaxAttempts is reached.
+func sendWebhook(ctx context.Context, h webhook, body []byte) error {
+	var err error
+	for attempt := range webhookMaxAttempts {
+		if attempt > 0 {
+			t := time.NewTimer(webhookBackOff << (attempt - 1))
+			select {
+			case <-ctx.Done():
+				t.Stop()
+				return ctx.Err()
+			case <-t.C:
+			}
+		}
+
+		err = postWebhook(ctx, h, body)
+		if err == nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("send webhook: %w, meta: %v", err, map[string]string{
+		"url":      h.url,
+		"attempts": strconv.Itoa(webhookMaxAttempts),
+	})
+}
+
+func postWebhook(ctx context.Context, h webhook, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	if len(h.secret) > 0 {
+		req.Header.Set(HeaderWebhookSignature, SignWebhook(string(h.secret), body))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Drain the body so that the connection can be reused.
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// runWebhookEvents are the run states of the run state change events that webhooks are sent for.
+var runWebhookEvents = map[RunState][]WebhookEvent{
+	RunStateCompleted: {WebhookEventRunCompleted},
+	RunStatePaused:    {WebhookEventRunPaused, WebhookEventRunFailed},
+	RunStateCancelled: {WebhookEventRunCancelled},
+}
+
+// sendsRunWebhooks returns true when any of the workflow's webhooks are sent for the events of runs.
+func (w *Workflow[Type, Status]) sendsRunWebhooks() bool {
+	for _, events := range runWebhookEvents {
+		for _, e := range events {
+			for _, h := range w.webhooks {
+				if h.subscribed(e) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+func webhookConsumer[Type any, Status StatusType](w *Workflow[Type, Status]) {
+	role := makeRole(
+		w.roleName(),
+		"webhook",
+		"consumer",
+	)
+
+	processName := makeRole("webhook", "consumer")
+	w.run(role, processName, w.hookShutdownOrder(), func(ctx context.Context) error {
+		stream, err := w.eventStreamer.NewReceiver(
+			ctx,
+			RunStateChangeTopic(w.Name()),
+			role,
+			WithReceiverPollFrequency(w.defaultOpts.pollingFrequency),
+		)
+		if err != nil {
+			return err
+		}
+		defer stream.Close()
+
+		return consume(
+			ctx,
+			w.Name(),
+			processName,
+			stream,
+			w.versionGuard(runWebhook(w)),
+			w.clock,
+			0,
+			w.defaultOpts.lagAlert,
+			w.versionFilter(),
+		)
+	}, w.defaultOpts.errBackOff)
+}
+
+// runWebhook sends the webhooks for the run state change events. The run state of the event is used, rather than the
+// run's current run state, so that each change is sent even when the run has changed again since.
+func runWebhook[Type any, Status StatusType](w *Workflow[Type, Status]) func(ctx context.Context, e *Event) error {
+	return func(ctx context.Context, e *Event) error {
+		rs, err := strconv.ParseInt(e.Headers[HeaderRunState], 10, 64)
+		if err != nil {
+			return nil
+		}
+
+		runState := RunState(rs)
+		if _, ok := runWebhookEvents[runState]; !ok {
+			return nil
+		}
+
+		record, err := w.recordStore.Lookup(ctx, e.ForeignID)
+		if err != nil {
+			return err
+		}
+
+		p := WebhookPayload{
+			ForeignID: record.ForeignID,
+			RunID:     record.RunID,
+			Status:    Status(record.Status).String(),
+			RunState:  runState.String(),
+		}
+
+		event := runWebhookEvents[runState][0]
+		if runState == RunStatePaused {
+			// Runs that are paused with an error were paused after exceeding their PauseAfterErrCount.
+			p.Message = pausedError(record.Meta.History)
+			if p.Message != "" {
+				event = WebhookEventRunFailed
+			}
+		}
+
+		return w.sendWebhooks(ctx, event, p)
+	}
+}
+
+// pausedError returns the error of the latest transition of the run that paused it.
+func pausedError(history []Transition) string {
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].ToRunState == RunStatePaused {
+			return history[i].Error
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,71 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendWebhooksQueues(t *testing.T) {
+	b := NewBuilder[string, testStatus]("webhook")
+	b.AddStep(statusStart, func(ctx context.Context, r *Run[string, testStatus]) (testStatus, error) {
+		return statusEnd, nil
+	}, statusEnd)
+
+	w := b.Build(nil, nil, nil, WithWebhook("http://localhost:0/webhook", "secret", WebhookEventRunCompleted))
+
+	ctx := context.Background()
+	p := WebhookPayload{RunID: "run-id"}
+
+	// The webhooks are queued rather than sent and so do not wait on the url.
+	for range webhookQueueSize {
+		err := w.sendWebhooks(ctx, WebhookEventRunCompleted, p)
+		require.Nil(t, err)
+	}
+
+	// Events that the webhook is not subscribed to are not queued.
+	err := w.sendWebhooks(ctx, WebhookEventRunPaused, p)
+	require.Nil(t, err)
+
+	// Webhooks are dropped once the queue is full.
+	err = w.sendWebhooks(ctx, WebhookEventRunCompleted, p)
+	require.Nil(t, err)
+	require.Len(t, w.webhooks[0].queue, webhookQueueSize)
+
+	var queued WebhookPayload
+	err = json.Unmarshal(<-w.webhooks[0].queue, &queued)
+	require.Nil(t, err)
+	require.Equal(t, "RunCompleted", queued.Event)
+	require.Equal(t, "run-id", queued.RunID)
+}
+
+func TestFlushWebhooks(t *testing.T) {
+	received := make(chan struct{}, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+	}))
+	t.Cleanup(srv.Close)
+
+	b := NewBuilder[string, testStatus]("webhook")
+	b.AddStep(statusStart, func(ctx context.Context, r *Run[string, testStatus]) (testStatus, error) {
+		return statusEnd, nil
+	}, statusEnd)
+
+	w := b.Build(nil, nil, nil, WithWebhook(srv.URL, "secret"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	for range 3 {
+		err := w.sendWebhooks(ctx, WebhookEventRunCompleted, WebhookPayload{RunID: "run-id"})
+		require.Nil(t, err)
+	}
+
+	// The webhooks that are left in the queue are sent once the workflow has been stopped.
+	cancel()
+	w.flushWebhooks(ctx, w.webhooks[0])
+	require.Len(t, received, 3)
+	require.Empty(t, w.webhooks[0].queue)
+}
@@ -0,0 +1,100 @@
+package workflow_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+)
+
+func TestWebhook(t *testing.T) {
+	const secret = "secret"
+
+	payloads := make(chan workflow.WebhookPayload, 10)
+	var failed bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.Nil(t, err)
+		require.True(t, workflow.VerifyWebhook(secret, body, r.Header.Get(workflow.HeaderWebhookSignature)))
+
+		// The first webhook fails and is retried.
+		if !failed {
+			failed = true
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		var p workflow.WebhookPayload
+		err = json.Unmarshal(body, &p)
+		require.Nil(t, err)
+
+		payloads <- p
+	}))
+	t.Cleanup(srv.Close)
+
+	b := workflow.NewBuilder[string, status]("webhook")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[string, status]) (status, error) {
+		if r.ForeignID == "failing" {
+			return 0, errors.New("downstream unavailable")
+		}
+
+		return StatusEnd, nil
+	}, StatusEnd).WithOptions(
+		workflow.PauseAfterErrCount(1),
+		workflow.ErrBackOff(time.Millisecond),
+	)
+
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+		workflow.WithWebhook(srv.URL, secret, workflow.WebhookEventRunCompleted, workflow.WebhookEventRunFailed),
+	)
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "completing", StatusStart)
+	require.Nil(t, err)
+
+	p := <-payloads
+	require.Equal(t, "RunCompleted", p.Event)
+	require.Equal(t, "webhook", p.WorkflowName)
+	require.Equal(t, "completing", p.ForeignID)
+	require.Equal(t, runID, p.RunID)
+	require.Equal(t, StatusEnd.String(), p.Status)
+
+	runID, err = wf.Trigger(ctx, "failing", StatusStart)
+	require.Nil(t, err)
+
+	p = <-payloads
+	require.Equal(t, "RunFailed", p.Event)
+	require.Equal(t, runID, p.RunID)
+	require.Equal(t, workflow.RunStatePaused.String(), p.RunState)
+	require.Equal(t, "downstream unavailable", p.Message)
+
+	// The webhooks are sent by a process of the workflow that is stopped along with the others.
+	wf.Stop()
+	require.Equal(t, workflow.StateShutdown, wf.States()["webhook-sender-1"])
+}
+
+func TestVerifyWebhook(t *testing.T) {
+	body := []byte(`{"event":"RunCompleted"}`)
+	signature := workflow.SignWebhook("secret", body)
+
+	require.True(t, workflow.VerifyWebhook("secret", body, signature))
+	require.False(t, workflow.VerifyWebhook("other", body, signature))
+	require.False(t, workflow.VerifyWebhook("secret", []byte(`{}`), signature))
+}
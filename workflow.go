@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/trace"
 	"k8s.io/utils/clock"
 
 	"github.com/luno/workflow/internal/errorcounter"
@@ -15,7 +16,9 @@ import (
 	"github.com/luno/workflow/internal/metrics"
 )
 
-type API[Type any, Status StatusType] interface {
+// ClientAPI is the part of the API that is available to services that interact with a workflow without running it,
+// such as the Client returned by NewClient. Both Workflow and Client implement ClientAPI.
+type ClientAPI[Type any, Status StatusType] interface {
 	// Name returns the name of the implemented workflow.
 	Name() string
 
@@ -28,6 +31,8 @@ type API[Type any, Status StatusType] interface {
 	// This especially helps when connecting other workflows as the foreignID is the only way to connect the streams. The
 	// same goes for Callback as you will need the foreignID to connect the callback back to the workflow instance that
 	// was run.
+	//
+	// If the RecordStore is unavailable then a StoreUnavailableError is returned which is safe to retry.
 	Trigger(
 		ctx context.Context,
 		foreignID string,
@@ -35,22 +40,29 @@ type API[Type any, Status StatusType] interface {
 		opts ...TriggerOption[Type, Status],
 	) (runID string, err error)
 
-	// Schedule takes a cron spec and will call Trigger at the specified intervals. Schedule is a blocking call and all
-	// schedule errors will be retried indefinitely. The same options are available for Schedule as they are
-	// for Trigger.
-	Schedule(foreignID string, startingStatus Status, spec string, opts ...ScheduleOption[Type, Status]) error
-
 	// Await is a blocking call that returns the typed Run when the workflow of the specified run ID reaches the
-	// specified status.
+	// specified status. Errors from the RecordStore are returned to the caller and not retried.
 	Await(ctx context.Context, foreignID, runID string, status Status, opts ...AwaitOption) (*Run[Type, Status], error)
 
 	// Callback can be used if Builder.AddCallback has been defined for the provided status. The data in the reader
 	// will be passed to the CallbackFunc that you specify and so the serialisation and deserialisation is in the
-	// hands of the user.
+	// hands of the user. A *StatusMismatchError, which wraps ErrStatusMismatch, is returned when the run is not in the
+	// provided status.
 	Callback(ctx context.Context, foreignID string, status Status, payload io.Reader) error
+}
+
+type API[Type any, Status StatusType] interface {
+	ClientAPI[Type, Status]
+
+	// Schedule takes a cron spec and will call Trigger at the specified intervals. Schedule is a blocking call and all
+	// schedule errors will be retried indefinitely. The same options are available for Schedule as they are
+	// for Trigger.
+	Schedule(foreignID string, startingStatus Status, spec string, opts ...ScheduleOption[Type, Status]) error
 
 	// Run must be called in order to start up all the background consumers / consumers required to run the workflow. Run
-	// only needs to be called once. Any subsequent calls to run are safe and are noop.
+	// only needs to be called once. Any subsequent calls to run are safe and are noop. If the workflow was built
+	// using WithPreflight then Run will panic if any of the adapter checks fail. Workflows built with
+	// WithRunMode(RunModeProducer) do not start any background consumers.
 	Run(ctx context.Context)
 
 	// Stop tells the workflow to shut down gracefully.
@@ -65,22 +77,86 @@ type Workflow[Type any, Status StatusType] struct {
 	calledRun bool
 	once      sync.Once
 	logger    *logger
-
-	eventStreamer EventStreamer
-	recordStore   RecordStore
-	timeoutStore  TimeoutStore
-	scheduler     RoleScheduler
+	preflight bool
+	runMode   RunMode
+
+	// undefined is true for clients created using NewClient which do not know the statuses of the workflow.
+	undefined bool
+
+	// dedicatedOutboxDrain is true when the outbox consumer and timeout pollers are run by a separate process
+	// using RunOutboxDrain.
+	dedicatedOutboxDrain bool
+
+	eventStreamer  EventStreamer
+	recordStore    RecordStore
+	timeoutStore   TimeoutStore
+	legalHoldStore LegalHoldStore
+	archiveStore   ArchiveStore
+	triggerLimiter *rateLimiter
+	triggerQuotas  *quotas
+	scheduler      RoleScheduler
+	lockStore      LockStore
+	blobStore      BlobStore
+
+	callbackVerifier CallbackVerifier
+	// searchIndexes are the SearchIndexFuncs, configured using WithSearchIndex, by the name of the search index.
+	searchIndexes map[string]SearchIndexFunc[Type]
 
 	consumers        map[Status]consumerConfig[Type, Status]
 	callback         map[Status][]callback[Type, Status]
+	namedCallbacks   map[string]namedCallback[Type, Status]
 	timeouts         map[Status]timeouts[Type, Status]
+	timers           map[timerKey[Status]]timeouts[Type, Status]
 	connectorConfigs []*connectorConfig[Type, Status]
 
 	defaultOpts         options
 	outboxConfig        outboxConfig
 	pausedRecordsRetry  pausedRecordsRetry
+	codec               Codec
+	priorityLanes       int
+	topicRetention      time.Duration
+	multiplex           bool
 	customDelete        customDelete
+	redact              redactFunc
+	unmarshalQuarantine bool
+	quarantineAlert     QuarantineAlertFunc
+
+	owner           Owner
+	alertHook       AlertHook
+	lagAlertHandler LagAlertHandler
+	webhooks        []webhook
+	stuckRunAlert   time.Duration
+
+	deadLetterStreamer  EventStreamer
+	deadLetterTopic     string
+	unknownStatusPolicy UnknownStatusPolicy
 	runStateChangeHooks map[RunState]RunStateChangeHookFunc[Type, Status]
+	compensations       map[Status]CompensationFunc[Type, Status]
+	subWorkflows        []subWorkflow[Type, Status]
+	childCancelPolicy   ChildCancelPolicy
+
+	shutdownOrder ShutdownOrderFunc
+	stages        shutdownStages
+
+	// tracer is only configured when built with WithTracerProvider.
+	tracer trace.Tracer
+
+	consumerMiddleware []ConsumerMiddleware[Type, Status]
+
+	// statusOptions and statusMiddleware are configured for sets of statuses using OnStatuses.
+	statusOptions    map[Status][]Option
+	statusMiddleware map[Status][]ConsumerMiddleware[Type, Status]
+
+	// version is the graph version of this host's definition of the workflow and compatibilityPolicy determines
+	// which versions of runs this host is able to process.
+	version             int
+	compatibilityPolicy CompatibilityPolicy
+	// pinned is true when built using Builder.Version and results in runs continuing on the graph of the version
+	// that triggered them.
+	pinned bool
+	// fence is only configured when built with WithDeploymentFencing and stops consumers from processing once a
+	// newer definition of the workflow has been registered.
+	fence *fence
 
 	internalStateMu sync.Mutex
 	// internalState holds the State of all expected consumers and timeout go routines using their role names
@@ -93,7 +169,28 @@ type Workflow[Type any, Status StatusType] struct {
 	// and block until this transition is complete.
 	launching sync.WaitGroup
 
+	shardStatsMu sync.Mutex
+	// shardStats holds the ShardStats of the step consumers that are running on this instance using their process
+	// names as the key.
+	shardStats map[string]*ShardStats
+
+	lagAlertingMu sync.Mutex
+	// lagAlerting holds whether each of the processes running on this instance is lagging beyond its lag alert using
+	// the process names as the key.
+	lagAlerting map[string]bool
+
+	// awaitHub is only configured when built with WithSharedAwait and shares the receivers of Await between its
+	// callers.
+	awaitHub *awaitHub
+
+	estimateStatsMu sync.Mutex
+	// statusStats are the statistics of the latest runs that are used by EstimateCompletion and are computed again
+	// once they are older than estimateStatsTTL.
+	statusStats *statusStats
+
 	statusGraph *graph.Graph
+	// transitionKinds are the kinds of the builder methods, such as AddStep, that added each transition.
+	transitionKinds map[graph.Transition][]TransitionKind
 	// errorCounter keeps a central in-mem state of errors from consumers and timeouts in order to implement
 	// PauseAfterErrCount. The tracking of errors is done in a way where errors need to be unique per process
 	// (consumer / timeout).
@@ -107,18 +204,38 @@ func (w *Workflow[Type, Status]) Name() string {
 func (w *Workflow[Type, Status]) Run(ctx context.Context) {
 	// Ensure that the background consumers are only initialized once
 	w.once.Do(func() {
+		w.provisionTopics(ctx)
+
+		if w.preflight {
+			err := w.Preflight(ctx)
+			if err != nil {
+				panic(err)
+			}
+		}
+
 		ctx, cancel := context.WithCancel(ctx)
 		w.ctx = ctx
 		w.cancel = cancel
 		w.calledRun = true
 
-		// Start the outbox consumer
-		track(w, func() {
-			outboxConsumer(w, w.outboxConfig)
-		})
+		// Deployments that only host the API rely on other deployments to run the background consumers.
+		if w.runMode == RunModeProducer {
+			return
+		}
+
+		// Start the outbox consumer and timeout pollers unless they are run by a dedicated process using
+		// RunOutboxDrain.
+		if !w.dedicatedOutboxDrain {
+			w.launchOutboxDrain()
+		}
 
 		// Start the state step consumers
 		for currentStatus, config := range w.consumers {
+			// Multiplexed consumers are started below and share a receiver per shard.
+			if w.multiplexed(config) {
+				continue
+			}
+
 			parallelCount := w.defaultOpts.parallelCount
 			if config.parallelCount != 0 {
 				parallelCount = config.parallelCount
@@ -139,38 +256,44 @@ func (w *Workflow[Type, Status]) Run(ctx context.Context) {
 			}
 		}
 
+		for totalShards, statuses := range w.multiplexedGroups() {
+			for shard := 1; shard <= totalShards; shard++ {
+				track(w, func() {
+					consumeMultiplexedEvents(w, statuses, shard, totalShards)
+				})
+			}
+		}
+
+		// Start the consumers that resume runs once their sub-workflow's run has completed
+		for _, sw := range w.subWorkflows {
+			track(w, func() {
+				subWorkflowConsumer(w, sw)
+			})
+		}
+
 		// Only start timeout consumers if the timeout store is provided. This allows for the timeout store to
 		// be optional for workflows where the timeout feature is not needed.
 		if w.timeoutStore != nil {
 			for status, timeouts := range w.timeouts {
 				track(w, func() {
-					timeoutPoller(w, status, timeouts)
+					timeoutAutoInserterConsumer(w, status, timeouts)
 				})
+			}
+
+			for key, timeouts := range w.timers {
 				track(w, func() {
-					timeoutAutoInserterConsumer(w, status, timeouts)
+					timeoutAutoInserterConsumer(w, key.status, timeouts)
 				})
 			}
 		}
 
 		// Start the connected stream consumers
 		for _, config := range w.connectorConfigs {
-			parallelCount := w.defaultOpts.parallelCount
-			if config.parallelCount != 0 {
-				parallelCount = config.parallelCount
-			}
-
-			if parallelCount < 2 {
-				// Launch all consumers in runners
+			totalShards := w.connectorShards(config)
+			for i := 1; i <= totalShards; i++ {
 				track(w, func() {
-					connectorConsumer(w, config, 1, 1)
+					connectorConsumer(w, config, i, totalShards)
 				})
-			} else {
-				// Run as sharded parallel consumers
-				for i := 1; i <= config.parallelCount; i++ {
-					track(w, func() {
-						connectorConsumer(w, config, i, config.parallelCount)
-					})
-				}
 			}
 		}
 
@@ -182,6 +305,18 @@ func (w *Workflow[Type, Status]) Run(ctx context.Context) {
 			})
 		}
 
+		if len(w.compensations) > 0 {
+			track(w, func() {
+				compensationConsumer(w)
+			})
+		}
+
+		if w.childCancelPolicy == ChildCancelCascade && len(w.subWorkflows) > 0 {
+			track(w, func() {
+				cancelChildrenConsumer(w)
+			})
+		}
+
 		// Launch the delete consumer which will manage all data deletion requests.
 		track(w, func() {
 			deleteConsumer(w)
@@ -193,6 +328,26 @@ func (w *Workflow[Type, Status]) Run(ctx context.Context) {
 				pausedRecordsRetryConsumer(w)
 			})
 		}
+
+		// The webhooks that are queued by the processes, such as by the webhook consumer, are sent in the background.
+		for i, h := range w.webhooks {
+			track(w, func() {
+				webhookSender(w, i, h)
+			})
+		}
+
+		if w.sendsRunWebhooks() {
+			track(w, func() {
+				webhookConsumer(w)
+			})
+		}
+
+		// Stuck runs are only checked for when there is an alert hook to raise the alerts with.
+		if w.stuckRunAlert > 0 && w.alertHook != nil {
+			track(w, func() {
+				stuckRunAlertConsumer(w)
+			})
+		}
 	})
 
 	w.launching.Wait()
@@ -209,31 +364,55 @@ func track[Type any, Status StatusType](w *Workflow[Type, Status], fn func()) {
 func (w *Workflow[Type, Status]) run(
 	role string,
 	processName string,
+	shutdownOrder int,
 	process func(ctx context.Context) error,
 	errBackOff time.Duration,
 ) {
+	ctx := w.stageContext(processName, shutdownOrder)
 	w.updateState(processName, StateIdle)
 	defer w.updateState(processName, StateShutdown)
 	// Mark that another go routine has launched and been added to internal state
 	w.launching.Done()
 
+	w.logger.event(ctx, DebugEvent{
+		Type:    DebugEventProcessStarted,
+		Message: "launching process",
+		Meta: map[string]string{
+			"workflow_name": w.Name(),
+			"role":          role,
+			"process_name":  processName,
+		},
+	})
+
 	for {
 		err := runOnce(
-			w.ctx,
+			ctx,
 			w.Name(),
 			role,
 			processName,
 			w.updateState,
 			w.scheduler.Await,
-			process,
+			func(ctx context.Context) error {
+				ctx = withTransitionProcess(ctx, processName, w.clock)
+				ctx = withLagAlertFunc(ctx, func(ctx context.Context, info LagAlertInfo) {
+					w.lagAlert(ctx, info)
+				})
+
+				return process(ctx)
+			},
 			w.logger,
 			w.clock,
 			errBackOff,
 		)
 		if err != nil {
-			w.logger.Debug(w.ctx, "shutting down process", map[string]string{
-				"role":         role,
-				"process_name": processName,
+			w.logger.event(ctx, DebugEvent{
+				Type:    DebugEventProcessStopped,
+				Message: "shutting down process",
+				Meta: map[string]string{
+					"workflow_name": w.Name(),
+					"role":          role,
+					"process_name":  processName,
+				},
 			})
 
 			return
@@ -264,6 +443,7 @@ func runOnce(
 
 	updateState(processName, StateIdle)
 
+	parent := ctx
 	ctx, cancel, err := awaitRole(ctx, role)
 	if errors.Is(err, context.Canceled) {
 		// Exit cleanly if error returned is cancellation of context
@@ -279,14 +459,104 @@ func runOnce(
 	updateState(processName, StateRunning)
 
 	err = process(ctx)
+	var storeErr *StoreUnavailableError
 	if errors.Is(err, context.Canceled) {
 		// Context can be cancelled by the role scheduler and thus return nil to attempt to gain the role again
 		// and if the parent context was cancelled then that will exit safely.
+		if parent.Err() == nil {
+			logger.event(parent, DebugEvent{
+				Type:    DebugEventRoleLost,
+				Message: "role lost",
+				Meta: map[string]string{
+					"workflow_name": workflowName,
+					"role":          role,
+					"process_name":  processName,
+				},
+			})
+		}
+
 		return nil
+	} else if errors.Is(err, ErrFenced) {
+		// Fenced processes give up their role, so that the hosts running the newer definition can take it over, and
+		// back off before checking again in case the newer definition has been rolled back.
+		cancel()
+		updateState(processName, StateFenced)
+		metrics.ProcessFenced.WithLabelValues(workflowName, processName).Inc()
+		logger.event(parent, DebugEvent{
+			Type:    DebugEventProcessFenced,
+			Message: "process fenced by newer workflow definition",
+			Meta: map[string]string{
+				"workflow_name": workflowName,
+				"role":          role,
+				"process_name":  processName,
+			},
+		})
+
+		timer := clock.NewTimer(errBackOff)
+		select {
+		case <-parent.Done():
+			return nil
+		case <-timer.C():
+			// Return nil to try again
+			return nil
+		}
+	} else if errors.Is(err, ErrIncompatibleVersion) {
+		// The role is given up, whilst backing off, so that a host running a compatible version of the workflow can
+		// take over the role and process the run's event.
+		cancel()
+		logger.event(parent, DebugEvent{
+			Type:    DebugEventIncompatibleVersion,
+			Message: "role released for an incompatible workflow version",
+			Meta: map[string]string{
+				"workflow_name": workflowName,
+				"role":          role,
+				"process_name":  processName,
+				"error":         err.Error(),
+			},
+		})
+
+		timer := clock.NewTimer(errBackOff)
+		select {
+		case <-parent.Done():
+			return nil
+		case <-timer.C():
+			// Return nil to try again
+			return nil
+		}
+	} else if errors.As(err, &storeErr) {
+		// The record store being unavailable is not an error of the process and is reported through its own state
+		// and metric rather than the process error count and error logs.
+		updateState(processName, StateStoreUnavailable)
+		metrics.ProcessStoreUnavailable.WithLabelValues(workflowName, processName).Inc()
+		logger.event(ctx, DebugEvent{
+			Type:    DebugEventStoreUnavailable,
+			Message: "record store unavailable",
+			Meta: map[string]string{
+				"workflow_name": workflowName,
+				"role":          role,
+				"process_name":  processName,
+				"error":         storeErr.Error(),
+			},
+		})
+
+		timer := clock.NewTimer(storeErr.RetryAfter)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C():
+			// Return nil to try again
+			return nil
+		}
 	} else if err != nil {
 		logger.Error(ctx, fmt.Errorf("run error [role=%s], [process=%s]: %v", role, processName, err))
 		metrics.ProcessErrors.WithLabelValues(workflowName, processName).Inc()
 
+		// Steps with a RetryPolicy back off for as long as the policy requires for the run that failed.
+		var retryErr *retryError
+		if errors.As(err, &retryErr) {
+			errBackOff = retryErr.RetryAfter
+		}
+
 		timer := clock.NewTimer(errBackOff)
 		select {
 		case <-ctx.Done():
@@ -307,23 +577,13 @@ func (w *Workflow[Type, Status]) Stop() {
 		return
 	}
 
+	// Stop the processes in order so that runs don't accumulate in statuses whose consumers have already exited.
+	w.stopStages()
+
 	// Cancel the parent context of the workflow to gracefully shutdown.
 	w.cancel()
 
-	for {
-		var runningProcesses int
-		for _, state := range w.States() {
-			switch state {
-			case StateUnknown, StateShutdown:
-				continue
-			default:
-				runningProcesses++
-			}
-		}
-
-		// Once all processes have exited then return
-		if runningProcesses == 0 {
-			return
-		}
-	}
+	w.awaitShutdown(func(processName string) bool {
+		return true
+	})
 }
@@ -178,4 +178,89 @@ func Test_runOnce(t *testing.T) {
 		expected := []string{StateIdle.String(), StateRunning.String()}
 		require.Equal(t, expected, stateChanges)
 	})
+
+	t.Run("Store unavailability backs off without logging an error", func(t *testing.T) {
+		var stateChanges []string
+		buf := bytes.NewBuffer([]byte{})
+		err := runOnce(
+			ctx,
+			"workflow-1",
+			"role-1",
+			"process-1",
+			func(processName string, s State) {
+				stateChanges = append(stateChanges, s.String())
+			},
+			func(ctx context.Context, role string) (context.Context, context.CancelFunc, error) {
+				ctx, cancel := context.WithCancel(ctx)
+				return ctx, cancel, nil
+			},
+			func(ctx context.Context) error {
+				return &StoreUnavailableError{
+					Op:         "Lookup",
+					RetryAfter: time.Millisecond,
+					Err:        errors.New("connection refused"),
+				}
+			},
+			&logger{
+				debugMode: false,
+				inner:     internal_logger.New(buf),
+			},
+			clock.RealClock{},
+			time.Hour,
+		)
+		require.Nil(t, err)
+		require.Empty(t, buf.String())
+
+		expected := []string{StateIdle.String(), StateRunning.String(), StateStoreUnavailable.String()}
+		require.Equal(t, expected, stateChanges)
+	})
+
+	// Processes that are unable to process the events give up their role so that another host can take it over.
+	for name, processErr := range map[string]error{
+		"Fenced process releases the role before backing off":       ErrFenced,
+		"Incompatible version releases the role before backing off": ErrIncompatibleVersion,
+	} {
+		t.Run(name, func(t *testing.T) {
+			released := make(chan struct{})
+			fakeClock := clock_testing.NewFakeClock(time.Now())
+			errs := make(chan error, 1)
+			go func() {
+				errs <- runOnce(
+					ctx,
+					"workflow-1",
+					"role-1",
+					"process-1",
+					func(processName string, s State) {},
+					func(ctx context.Context, role string) (context.Context, context.CancelFunc, error) {
+						ctx, cancel := context.WithCancel(ctx)
+						go func() {
+							<-ctx.Done()
+							close(released)
+						}()
+						return ctx, cancel, nil
+					},
+					func(ctx context.Context) error {
+						return processErr
+					},
+					&logger{
+						debugMode: false,
+						inner:     internal_logger.New(bytes.NewBuffer([]byte{})),
+					},
+					fakeClock,
+					time.Hour,
+				)
+			}()
+
+			// The role must be released before the back off has passed.
+			select {
+			case <-released:
+			case <-time.After(5 * time.Second):
+				t.Fatal("role was not released whilst backing off")
+			}
+
+			require.Eventually(t, fakeClock.HasWaiters, 5*time.Second, time.Millisecond)
+			fakeClock.Step(time.Hour)
+			require.Nil(t, <-errs)
+		})
+	}
 }
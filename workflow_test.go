@@ -3,9 +3,12 @@ package workflow_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -637,6 +640,7 @@ func TestExpectedProcesses(t *testing.T) {
 		"completed-run-state-change-hook-consumer":    true,
 		"delete-consumer":                             true,
 		"paused-records-retry-consumer":               true,
+		"scheduled-start-consumer":                    true,
 	}
 
 	w := acceptanceTestWorkflow().Build(
@@ -653,3 +657,625 @@ func TestExpectedProcesses(t *testing.T) {
 		require.Truef(t, expected[process], "process '%s' is missing expected value", process)
 	}
 }
+
+func TestCompensation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	b := workflow.NewBuilder[MyType, status]("compensation")
+
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		return StatusMiddle, nil
+	}, StatusMiddle)
+
+	b.AddStep(StatusMiddle, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		// Fail terminally so that the previous step is compensated.
+		return r.Cancel(ctx)
+	}, StatusEnd)
+
+	compensated := make(chan status, 1)
+	b.AddCompensation(StatusStart, func(ctx context.Context, r *workflow.TypedRecord[MyType, status]) error {
+		compensated <- StatusStart
+		return nil
+	})
+
+	b.AddCompensation(StatusMiddle, func(ctx context.Context, r *workflow.TypedRecord[MyType, status]) error {
+		// The step consuming StatusMiddle cancelled the run and did not complete, so it must not be compensated.
+		compensated <- StatusMiddle
+		return nil
+	})
+
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+	)
+
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	_, err := wf.Trigger(ctx, "example", StatusStart)
+	require.Nil(t, err)
+
+	select {
+	case <-ctx.Done():
+		t.Fail()
+	case s := <-compensated:
+		require.Equal(t, StatusStart, s)
+	}
+}
+
+func TestCompensation_TerminalFailure(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	b := workflow.NewBuilder[MyType, status]("compensation")
+
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		return StatusMiddle, nil
+	}, StatusMiddle)
+
+	b.AddStep(StatusMiddle, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		return 0, errors.New("provider rejected the request")
+	}, StatusEnd).WithOptions(
+		workflow.PauseAfterErrCount(1),
+		workflow.ErrBackOff(time.Millisecond),
+	)
+
+	compensated := make(chan status, 1)
+	b.AddCompensation(StatusStart, func(ctx context.Context, r *workflow.TypedRecord[MyType, status]) error {
+		compensated <- StatusStart
+		return nil
+	})
+
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+		// Paused runs are not resumed and so the run has failed terminally once it is paused.
+		workflow.DisablePauseRetry(),
+	)
+
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	_, err := wf.Trigger(ctx, "example", StatusStart)
+	require.Nil(t, err)
+
+	select {
+	case <-time.After(5 * time.Second):
+		t.Fatal("run was not compensated")
+	case s := <-compensated:
+		require.Equal(t, StatusStart, s)
+	}
+}
+
+func TestCompensation_ResumeAfterCompensation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	b := workflow.NewBuilder[MyType, status]("compensation")
+
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		return StatusMiddle, nil
+	}, StatusMiddle)
+
+	b.AddStep(StatusMiddle, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		return 0, errors.New("provider rejected the request")
+	}, StatusEnd).WithOptions(
+		workflow.PauseAfterErrCount(1),
+		workflow.ErrBackOff(time.Millisecond),
+	)
+
+	b.AddCompensation(StatusStart, func(ctx context.Context, r *workflow.TypedRecord[MyType, status]) error {
+		return nil
+	})
+
+	recordStore := memrecordstore.New()
+	wf := b.Build(
+		memstreamer.New(),
+		recordStore,
+		memrolescheduler.New(),
+		workflow.DisablePauseRetry(),
+	)
+
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "example", StatusStart)
+	require.Nil(t, err)
+
+	// The run is cancelled once it has been rolled back rather than being left paused.
+	require.Eventually(t, func() bool {
+		r, err := recordStore.Lookup(ctx, runID)
+		require.Nil(t, err)
+		return r.RunState == workflow.RunStateCancelled
+	}, 5*time.Second, 10*time.Millisecond)
+
+	err = wf.ResumeRun(ctx, runID)
+	require.ErrorIs(t, err, workflow.ErrInvalidTransition)
+}
+
+func TestDedicatedOutboxDrain(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	streamer := memstreamer.New()
+	recordStore := memrecordstore.New()
+	roleScheduler := memrolescheduler.New()
+
+	build := func(opts ...workflow.BuildOption) *workflow.Workflow[MyType, status] {
+		b := workflow.NewBuilder[MyType, status]("dedicated outbox drain")
+		b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+			return StatusEnd, nil
+		}, StatusEnd)
+
+		return b.Build(streamer, recordStore, roleScheduler, opts...)
+	}
+
+	consumers := build(workflow.WithDedicatedOutboxDrain())
+	consumers.Run(ctx)
+	t.Cleanup(consumers.Stop)
+
+	drain := build()
+	drain.RunOutboxDrain(ctx)
+	t.Cleanup(drain.Stop)
+
+	_, ok := consumers.States()["outbox-consumer"]
+	require.False(t, ok)
+
+	require.Equal(t, map[string]workflow.State{
+		"outbox-consumer": drain.States()["outbox-consumer"],
+	}, drain.States())
+
+	runID, err := consumers.Trigger(ctx, "example", StatusStart)
+	require.Nil(t, err)
+
+	_, err = consumers.Await(ctx, "example", runID, StatusEnd)
+	require.Nil(t, err)
+}
+
+func TestSubWorkflow(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	streamer := memstreamer.New()
+	recordStore := memrecordstore.New()
+	roleScheduler := memrolescheduler.New()
+
+	cb := workflow.NewBuilder[MyType, status]("child")
+	cb.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		r.Object.Email = fmt.Sprintf("user-%v@example.com", r.Object.UserID)
+		return StatusEnd, nil
+	}, StatusEnd)
+	child := cb.Build(streamer, recordStore, roleScheduler)
+
+	pb := workflow.NewBuilder[MyType, status]("parent")
+	workflow.AddSubWorkflow(
+		pb,
+		StatusStart,
+		child,
+		StatusStart,
+		func(ctx context.Context, r *workflow.Run[MyType, status]) (MyType, error) {
+			return MyType{UserID: r.Object.UserID}, nil
+		},
+		func(ctx context.Context, r *workflow.Run[MyType, status], child *workflow.TypedRecord[MyType, status]) (status, error) {
+			r.Object.Email = child.Object.Email
+			return StatusEnd, nil
+		},
+		StatusEnd,
+	)
+	parent := pb.Build(streamer, recordStore, roleScheduler)
+
+	child.Run(ctx)
+	t.Cleanup(child.Stop)
+	parent.Run(ctx)
+	t.Cleanup(parent.Stop)
+
+	runID, err := parent.Trigger(ctx, "example", StatusStart, workflow.WithInitialValue[MyType, status](&MyType{
+		UserID: expectedUserID,
+	}))
+	require.Nil(t, err)
+
+	run, err := parent.Await(ctx, "example", runID, StatusEnd)
+	require.Nil(t, err)
+	require.Equal(t, fmt.Sprintf("user-%v@example.com", expectedUserID), run.Object.Email)
+}
+
+func TestRunModes(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	streamer := memstreamer.New()
+	recordStore := memrecordstore.New()
+	roleScheduler := memrolescheduler.New()
+
+	build := func(mode workflow.RunMode) *workflow.Workflow[MyType, status] {
+		b := workflow.NewBuilder[MyType, status]("run modes")
+		b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+			return StatusEnd, nil
+		}, StatusEnd)
+
+		return b.Build(streamer, recordStore, roleScheduler, workflow.WithRunMode(mode))
+	}
+
+	producer := build(workflow.RunModeProducer)
+	producer.Run(ctx)
+	t.Cleanup(producer.Stop)
+	require.Empty(t, producer.States())
+
+	consumer := build(workflow.RunModeConsumer)
+	consumer.Run(ctx)
+	t.Cleanup(consumer.Stop)
+	require.NotEmpty(t, consumer.States())
+
+	// Deployments that only host the background consumers do not serve the API.
+	_, err := consumer.Trigger(ctx, "example", StatusStart)
+	require.ErrorIs(t, err, workflow.ErrConsumerRunMode)
+
+	err = consumer.Callback(ctx, "example", StatusStart, nil)
+	require.ErrorIs(t, err, workflow.ErrConsumerRunMode)
+
+	err = consumer.Schedule("example", StatusStart, "@hourly")
+	require.ErrorIs(t, err, workflow.ErrConsumerRunMode)
+
+	runID, err := producer.Trigger(ctx, "example", StatusStart)
+	require.Nil(t, err)
+
+	_, err = producer.Await(ctx, "example", runID, StatusEnd)
+	require.Nil(t, err)
+}
+
+func TestShutdownOrder(t *testing.T) {
+	b := workflow.NewBuilder[MyType, status]("shutdown order")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		return StatusMiddle, nil
+	}, StatusMiddle)
+	b.AddStep(StatusMiddle, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+	b.OnComplete(func(ctx context.Context, record *workflow.TypedRecord[MyType, status]) error {
+		return nil
+	})
+
+	var mu sync.Mutex
+	orders := make(map[string]int)
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+		workflow.WithShutdownOrder(func(processName string, defaultOrder int) int {
+			mu.Lock()
+			defer mu.Unlock()
+
+			orders[processName] = defaultOrder
+			return defaultOrder
+		}),
+	)
+
+	wf.Run(context.Background())
+	wf.Stop()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	require.Less(t, orders["start-consumer-1-of-1"], orders["middle-consumer-1-of-1"])
+	require.Less(t, orders["middle-consumer-1-of-1"], orders["completed-run-state-change-hook-consumer"])
+	require.Less(t, orders["completed-run-state-change-hook-consumer"], orders["outbox-consumer"])
+	require.Equal(t, orders["delete-consumer"], orders["completed-run-state-change-hook-consumer"])
+
+	for process, state := range wf.States() {
+		require.Equal(t, workflow.StateShutdown, state, process)
+	}
+}
+
+func TestUnmarshalQuarantine(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	b := workflow.NewBuilder[MyType, status]("quarantine")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+
+	alerts := make(chan string, 1)
+	recordStore := memrecordstore.New()
+	wf := b.Build(
+		memstreamer.New(),
+		recordStore,
+		memrolescheduler.New(),
+		workflow.WithUnmarshalQuarantine(func(ctx context.Context, record *workflow.Record, err error) {
+			alerts <- record.RunID
+		}),
+	)
+
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	// Store a run whose Object no longer matches the shape of MyType.
+	runID := "quarantined-run"
+	err := recordStore.Store(ctx, &workflow.Record{
+		WorkflowName: wf.Name(),
+		ForeignID:    "example",
+		RunID:        runID,
+		RunState:     workflow.RunStateInitiated,
+		Status:       int(StatusStart),
+		Object:       []byte(`{"UserID": "not a number"}`),
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	})
+	require.Nil(t, err)
+
+	select {
+	case <-ctx.Done():
+		t.Fail()
+	case id := <-alerts:
+		require.Equal(t, runID, id)
+	}
+
+	record, err := recordStore.Lookup(ctx, runID)
+	require.Nil(t, err)
+	require.Equal(t, workflow.RunStateQuarantined, record.RunState)
+	require.Equal(t, `{"UserID": "not a number"}`, string(record.Object))
+	require.NotEmpty(t, record.Meta.QuarantineReason)
+
+	// Simulate a fix being shipped that is able to read the run's Object.
+	record.Object, err = workflow.Marshal(&MyType{UserID: 1})
+	require.Nil(t, err)
+	err = recordStore.Store(ctx, record)
+	require.Nil(t, err)
+
+	err = wf.ReprocessQuarantined(ctx, runID)
+	require.Nil(t, err)
+
+	err = wf.ReprocessQuarantined(ctx, runID)
+	require.ErrorIs(t, err, workflow.ErrRunNotQuarantined)
+
+	_, err = wf.Await(ctx, "example", runID, StatusEnd)
+	require.Nil(t, err)
+}
+
+func TestSideBySideVersions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	streamer := memstreamer.New()
+	recordStore := memrecordstore.New()
+
+	v1 := workflow.NewBuilder[MyType, status]("side by side").Version(1)
+	v1.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		return StatusMiddle, nil
+	}, StatusMiddle)
+	v1.AddStep(StatusMiddle, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+	// Each version is run by its own deployment and so does not share the role scheduler with the other.
+	wfV1 := v1.Build(streamer, recordStore, memrolescheduler.New())
+
+	// Version 2 removes StatusMiddle from the graph.
+	v2 := workflow.NewBuilder[MyType, status]("side by side").Version(2)
+	v2.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		return StatusEnd, nil
+	}, StatusEnd)
+	wfV2 := v2.Build(streamer, recordStore, memrolescheduler.New())
+
+	wfV1.Run(ctx)
+	t.Cleanup(wfV1.Stop)
+	wfV2.Run(ctx)
+	t.Cleanup(wfV2.Stop)
+
+	runV1, err := wfV1.Trigger(ctx, "in-flight", StatusStart)
+	require.Nil(t, err)
+
+	runV2, err := wfV2.Trigger(ctx, "new", StatusStart)
+	require.Nil(t, err)
+
+	_, err = wfV1.Await(ctx, "in-flight", runV1, StatusEnd)
+	require.Nil(t, err)
+
+	_, err = wfV2.Await(ctx, "new", runV2, StatusEnd)
+	require.Nil(t, err)
+
+	var v1Statuses []status
+	for _, r := range recordStore.Snapshots(wfV1.Name(), "in-flight", runV1) {
+		require.Equal(t, 1, r.Meta.Version)
+		v1Statuses = append(v1Statuses, status(r.Status))
+	}
+	require.Contains(t, v1Statuses, StatusMiddle)
+
+	for _, r := range recordStore.Snapshots(wfV2.Name(), "new", runV2) {
+		require.Equal(t, 2, r.Meta.Version)
+		require.NotEqual(t, StatusMiddle, status(r.Status))
+	}
+}
+
+func TestMixedVersionHosts(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	streamer := memstreamer.New()
+	recordStore := memrecordstore.New()
+	scheduler := memrolescheduler.New()
+
+	build := func(version int) *workflow.Workflow[MyType, status] {
+		b := workflow.NewBuilder[MyType, status]("mixed versions")
+		b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+			r.Object.Name = "processed by v" + strconv.Itoa(version)
+			return StatusEnd, nil
+		}, StatusEnd)
+
+		return b.Build(
+			streamer,
+			recordStore,
+			scheduler,
+			workflow.WithGraphVersion(version),
+			workflow.WithDefaultOptions(
+				workflow.PollingFrequency(10*time.Millisecond),
+				workflow.ErrBackOff(10*time.Millisecond),
+			),
+		)
+	}
+
+	// The old host is started first and so takes the roles that are shared with the new host.
+	oldHost := build(1)
+	oldHost.Run(ctx)
+	t.Cleanup(oldHost.Stop)
+
+	newHost := build(2)
+	newHost.Run(ctx)
+	t.Cleanup(newHost.Stop)
+
+	runID, err := newHost.Trigger(ctx, "andrew", StatusStart)
+	require.Nil(t, err)
+
+	r, err := newHost.Await(ctx, "andrew", runID, StatusEnd, workflow.WithAwaitPollingFrequency(10*time.Millisecond))
+	require.Nil(t, err)
+	require.Equal(t, "processed by v2", r.Object.Name)
+}
+
+func TestDeadLetterQueue(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	var fixed atomic.Bool
+	b := workflow.NewBuilder[MyType, status]("dead letter")
+	b.AddStep(StatusStart, func(ctx context.Context, r *workflow.Run[MyType, status]) (status, error) {
+		if !fixed.Load() {
+			return 0, errors.New("downstream rejected request")
+		}
+
+		return StatusEnd, nil
+	}, StatusEnd).WithOptions(
+		workflow.PauseAfterErrCount(2),
+		workflow.ErrBackOff(time.Millisecond),
+	)
+
+	dlq := memstreamer.New()
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+		workflow.WithDeadLetterQueue(dlq, "dead-letters"),
+		workflow.DisablePauseRetry(),
+	)
+
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runID, err := wf.Trigger(ctx, "example", StatusStart)
+	require.Nil(t, err)
+
+	receiver, err := dlq.NewReceiver(ctx, "dead-letters", "triage")
+	require.Nil(t, err)
+	t.Cleanup(func() {
+		require.Nil(t, receiver.Close())
+	})
+
+	e, ack, err := receiver.Recv(ctx)
+	require.Nil(t, err)
+	require.Nil(t, ack())
+
+	dl, err := workflow.ParseDeadLetter(e)
+	require.Nil(t, err)
+	require.Equal(t, runID, dl.Record.RunID)
+	require.Equal(t, "example", dl.Record.ForeignID)
+	require.Equal(t, int(StatusStart), dl.Record.Status)
+	require.Equal(t, "start-consumer-1-of-1", dl.ProcessName)
+	require.Equal(t, "downstream rejected request", dl.Error)
+
+	fixed.Store(true)
+
+	// The run is only paused once it has been published to the dead-letter queue and so the re-drive is retried
+	// until the run is able to be resumed.
+	require.Eventually(t, func() bool {
+		return wf.RedriveDeadLetter(ctx, runID) == nil
+	}, 5*time.Second, 10*time.Millisecond)
+
+	_, err = wf.Await(ctx, "example", runID, StatusEnd)
+	require.Nil(t, err)
+}
+
+func TestBatchStep(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(func() {
+		cancel()
+	})
+
+	var (
+		mu         sync.Mutex
+		batchSizes []int
+	)
+	b := workflow.NewBuilder[MyType, status]("batch")
+	b.AddBatchStep(
+		StatusStart,
+		func(ctx context.Context, runs []*workflow.Run[MyType, status]) (workflow.BatchResult[status], error) {
+			mu.Lock()
+			batchSizes = append(batchSizes, len(runs))
+			mu.Unlock()
+
+			result := make(workflow.BatchResult[status])
+			for _, r := range runs {
+				r.Object.Name = "processed in batch"
+				result[r.RunID] = workflow.BatchOutcome[status]{Next: StatusEnd}
+			}
+
+			return result, nil
+		},
+		3,
+		50*time.Millisecond,
+		StatusEnd,
+	)
+
+	wf := b.Build(
+		memstreamer.New(),
+		memrecordstore.New(),
+		memrolescheduler.New(),
+	)
+
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	runIDs := make(map[string]string)
+	for i := range 5 {
+		foreignID := strconv.Itoa(i)
+		runID, err := wf.Trigger(ctx, foreignID, StatusStart)
+		require.Nil(t, err)
+		runIDs[foreignID] = runID
+	}
+
+	for foreignID, runID := range runIDs {
+		r, err := wf.Await(ctx, foreignID, runID, StatusEnd)
+		require.Nil(t, err)
+		require.Equal(t, "processed in batch", r.Object.Name)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	var total int
+	for _, size := range batchSizes {
+		require.LessOrEqual(t, size, 3)
+		total += size
+	}
+	require.Equal(t, 5, total)
+}
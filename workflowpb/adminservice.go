@@ -0,0 +1,279 @@
+package workflowpb
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/luno/workflow"
+)
+
+// AdminService implements the Admin gRPC service for the workflows that it is created with so that ops tooling and
+// UIs can administer their runs without binding to the types of the workflows. Register it with a gRPC server using
+// RegisterAdminServer.
+type AdminService struct {
+	UnimplementedAdminServer
+
+	workflows map[string]workflow.AdminWorkflow
+}
+
+var _ AdminServer = (*AdminService)(nil)
+
+// NewAdminService returns an AdminService for the workflows, such as the *workflow.Workflow of each workflow run by the
+// service. The names of the workflows must be unique.
+func NewAdminService(workflows ...workflow.AdminWorkflow) *AdminService {
+	s := &AdminService{
+		workflows: make(map[string]workflow.AdminWorkflow),
+	}
+
+	for _, w := range workflows {
+		if _, ok := s.workflows[w.Name()]; ok {
+			panic("workflowpb: duplicate workflow registered with admin service: " + w.Name())
+		}
+
+		s.workflows[w.Name()] = w
+	}
+
+	return s
+}
+
+func (s *AdminService) ListWorkflows(ctx context.Context, req *ListWorkflowsRequest) (*ListWorkflowsResponse, error) {
+	var names []string
+	for name := range s.workflows {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return &ListWorkflowsResponse{WorkflowNames: names}, nil
+}
+
+func (s *AdminService) ListRuns(ctx context.Context, req *ListRunsRequest) (*ListRunsResponse, error) {
+	w, err := s.lookup(req.WorkflowName)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := workflow.AdminRunFilter{
+		ForeignIDPrefix: req.ForeignIdPrefix,
+		Offset:          req.Offset,
+		Limit:           int(req.Limit),
+		Order:           workflow.OrderTypeAscending,
+	}
+
+	for _, s := range req.Statuses {
+		filter.Statuses = append(filter.Statuses, int(s))
+	}
+
+	for _, rs := range req.RunStates {
+		filter.RunStates = append(filter.RunStates, workflow.RunState(rs))
+	}
+
+	if req.CreatedFrom != nil {
+		filter.CreatedFrom = req.CreatedFrom.AsTime()
+	}
+
+	if req.CreatedTo != nil {
+		filter.CreatedTo = req.CreatedTo.AsTime()
+	}
+
+	if req.Descending {
+		filter.Order = workflow.OrderTypeDescending
+	}
+
+	records, err := w.AdminListRuns(ctx, filter)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	var runs []*Record
+	for i := range records {
+		runs = append(runs, ToProto(&records[i]))
+	}
+
+	return &ListRunsResponse{Runs: runs}, nil
+}
+
+func (s *AdminService) GetRun(ctx context.Context, req *GetRunRequest) (*GetRunResponse, error) {
+	w, err := s.lookup(req.WorkflowName)
+	if err != nil {
+		return nil, err
+	}
+
+	record, err := w.AdminGetRun(ctx, req.RunId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &GetRunResponse{Run: ToProto(record)}, nil
+}
+
+func (s *AdminService) RunHistory(ctx context.Context, req *RunHistoryRequest) (*RunHistoryResponse, error) {
+	w, err := s.lookup(req.WorkflowName)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := w.RunHistory(ctx, req.RunId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	var transitions []*Transition
+	for _, t := range history {
+		transitions = append(transitions, &Transition{
+			FromStatus:   int32(t.FromStatus),
+			ToStatus:     int32(t.ToStatus),
+			FromRunState: int32(t.FromRunState),
+			ToRunState:   int32(t.ToRunState),
+			At:           timestamppb.New(t.At),
+			Process:      t.Process,
+			Error:        t.Error,
+			Actor:        t.Actor,
+			Reason:       t.Reason,
+			Forced:       t.Forced,
+			Replay:       t.Replay,
+		})
+	}
+
+	return &RunHistoryResponse{Transitions: transitions}, nil
+}
+
+func (s *AdminService) PauseRun(ctx context.Context, req *PauseRunRequest) (*PauseRunResponse, error) {
+	w, err := s.lookup(req.WorkflowName)
+	if err != nil {
+		return nil, err
+	}
+
+	err = w.PauseRun(withActor(ctx, req.Actor), req.RunId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &PauseRunResponse{}, nil
+}
+
+func (s *AdminService) ResumeRun(ctx context.Context, req *ResumeRunRequest) (*ResumeRunResponse, error) {
+	w, err := s.lookup(req.WorkflowName)
+	if err != nil {
+		return nil, err
+	}
+
+	err = w.ResumeRun(withActor(ctx, req.Actor), req.RunId)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &ResumeRunResponse{}, nil
+}
+
+func (s *AdminService) CancelRun(ctx context.Context, req *CancelRunRequest) (*CancelRunResponse, error) {
+	w, err := s.lookup(req.WorkflowName)
+	if err != nil {
+		return nil, err
+	}
+
+	err = w.CancelRun(withActor(ctx, req.Actor), req.RunId, req.Reason)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &CancelRunResponse{}, nil
+}
+
+func (s *AdminService) ForceTransition(
+	ctx context.Context,
+	req *ForceTransitionRequest,
+) (*ForceTransitionResponse, error) {
+	w, err := s.lookup(req.WorkflowName)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []workflow.ForceTransitionOption
+	if req.GraphOverride {
+		opts = append(opts, workflow.WithGraphOverride())
+	}
+
+	err = w.AdminForceTransition(withActor(ctx, req.Actor), req.RunId, int(req.ToStatus), req.Reason, opts...)
+	if err != nil {
+		return nil, toStatusError(err)
+	}
+
+	return &ForceTransitionResponse{}, nil
+}
+
+func (s *AdminService) GetGraph(ctx context.Context, req *GetGraphRequest) (*GetGraphResponse, error) {
+	w, err := s.lookup(req.WorkflowName)
+	if err != nil {
+		return nil, err
+	}
+
+	g := w.Graph()
+	pb := &Graph{
+		WorkflowName: g.WorkflowName,
+	}
+
+	for _, s := range g.Statuses {
+		pb.Statuses = append(pb.Statuses, &GraphStatus{
+			Status:      int32(s.Status),
+			Description: s.Description,
+			Starting:    s.Starting,
+			Terminal:    s.Terminal,
+		})
+	}
+
+	for _, t := range g.Transitions {
+		var kinds []string
+		for _, k := range t.Kinds {
+			kinds = append(kinds, string(k))
+		}
+
+		pb.Transitions = append(pb.Transitions, &GraphTransition{
+			From:  int32(t.From),
+			To:    int32(t.To),
+			Kinds: kinds,
+		})
+	}
+
+	return &GetGraphResponse{Graph: pb}, nil
+}
+
+func (s *AdminService) lookup(workflowName string) (workflow.AdminWorkflow, error) {
+	w, ok := s.workflows[workflowName]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "workflow not registered: %q", workflowName)
+	}
+
+	return w, nil
+}
+
+// withActor sets the actor of the request on the context so that it is recorded in the run's history.
+func withActor(ctx context.Context, actor string) context.Context {
+	if actor == "" {
+		return ctx
+	}
+
+	return workflow.WithActor(ctx, actor)
+}
+
+// toStatusError converts the errors of the workflow into gRPC status errors so that clients can distinguish between
+// runs that do not exist and runs that are not in a state to be updated.
+func toStatusError(err error) error {
+	switch {
+	case errors.Is(err, workflow.ErrRecordNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, workflow.ErrInvalidTransition):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -0,0 +1,180 @@
+package workflowpb_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/luno/workflow"
+	"github.com/luno/workflow/adapters/memrecordstore"
+	"github.com/luno/workflow/adapters/memrolescheduler"
+	"github.com/luno/workflow/adapters/memstreamer"
+	"github.com/luno/workflow/workflowpb"
+)
+
+type orderStatus int
+
+const (
+	orderStatusUnknown  orderStatus = 0
+	orderStatusCreated  orderStatus = 1
+	orderStatusApproved orderStatus = 2
+	orderStatusShipped  orderStatus = 3
+)
+
+func (s orderStatus) String() string {
+	switch s {
+	case orderStatusCreated:
+		return "Created"
+	case orderStatusApproved:
+		return "Approved"
+	case orderStatusShipped:
+		return "Shipped"
+	default:
+		return "Unknown"
+	}
+}
+
+func newAdminClient(t *testing.T, workflows ...workflow.AdminWorkflow) workflowpb.AdminClient {
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	workflowpb.RegisterAdminServer(srv, workflowpb.NewAdminService(workflows...))
+
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.Nil(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return workflowpb.NewAdminClient(conn)
+}
+
+func TestAdminService(t *testing.T) {
+	b := workflow.NewBuilder[string, orderStatus]("orders")
+	approve := func(ctx context.Context, r *workflow.Run[string, orderStatus], reader io.Reader) (orderStatus, error) {
+		return orderStatusApproved, nil
+	}
+	ship := func(ctx context.Context, r *workflow.Run[string, orderStatus], reader io.Reader) (orderStatus, error) {
+		return orderStatusShipped, nil
+	}
+	b.AddCallback(orderStatusCreated, approve, orderStatusApproved)
+	b.AddCallback(orderStatusApproved, ship, orderStatusShipped)
+
+	wf := b.Build(memstreamer.New(), memrecordstore.New(), memrolescheduler.New())
+
+	ctx := context.Background()
+	wf.Run(ctx)
+	t.Cleanup(wf.Stop)
+
+	value := "order"
+	runID, err := wf.Trigger(ctx, "order-1", orderStatusCreated, workflow.WithInitialValue[string, orderStatus](&value))
+	require.Nil(t, err)
+
+	client := newAdminClient(t, wf)
+
+	workflows, err := client.ListWorkflows(ctx, &workflowpb.ListWorkflowsRequest{})
+	require.Nil(t, err)
+	require.Equal(t, []string{"orders"}, workflows.WorkflowNames)
+
+	runs, err := client.ListRuns(ctx, &workflowpb.ListRunsRequest{
+		WorkflowName: "orders",
+		Statuses:     []int32{int32(orderStatusCreated)},
+	})
+	require.Nil(t, err)
+	require.Len(t, runs.Runs, 1)
+	require.Equal(t, runID, runs.Runs[0].RunId)
+
+	run, err := client.GetRun(ctx, &workflowpb.GetRunRequest{WorkflowName: "orders", RunId: runID})
+	require.Nil(t, err)
+	require.Equal(t, "order-1", run.Run.ForeignId)
+	require.Equal(t, `"order"`, string(run.Run.Object))
+
+	_, err = client.PauseRun(ctx, &workflowpb.PauseRunRequest{WorkflowName: "orders", RunId: runID, Actor: "alice"})
+	require.Nil(t, err)
+
+	_, err = client.ResumeRun(ctx, &workflowpb.ResumeRunRequest{WorkflowName: "orders", RunId: runID, Actor: "alice"})
+	require.Nil(t, err)
+
+	// Resuming a run that is not paused is rejected.
+	_, err = client.ResumeRun(ctx, &workflowpb.ResumeRunRequest{WorkflowName: "orders", RunId: runID})
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = client.ForceTransition(ctx, &workflowpb.ForceTransitionRequest{
+		WorkflowName: "orders",
+		RunId:        runID,
+		Actor:        "alice",
+		Reason:       "approved manually",
+		ToStatus:     int32(orderStatusApproved),
+	})
+	require.Nil(t, err)
+
+	_, err = client.CancelRun(ctx, &workflowpb.CancelRunRequest{
+		WorkflowName: "orders",
+		RunId:        runID,
+		Actor:        "bob",
+		Reason:       "customer request",
+	})
+	require.Nil(t, err)
+
+	history, err := client.RunHistory(ctx, &workflowpb.RunHistoryRequest{WorkflowName: "orders", RunId: runID})
+	require.Nil(t, err)
+
+	last := history.Transitions[len(history.Transitions)-1]
+	require.Equal(t, int32(workflow.RunStateCancelled), last.ToRunState)
+	require.Equal(t, "bob", last.Actor)
+	require.Equal(t, "customer request", last.Reason)
+
+	var forced *workflowpb.Transition
+	for _, tr := range history.Transitions {
+		if tr.Forced {
+			forced = tr
+		}
+	}
+	require.NotNil(t, forced)
+	require.Equal(t, int32(orderStatusApproved), forced.ToStatus)
+	require.Equal(t, "alice", forced.Actor)
+
+	graph, err := client.GetGraph(ctx, &workflowpb.GetGraphRequest{WorkflowName: "orders"})
+	require.Nil(t, err)
+	require.Equal(t, "orders", graph.Graph.WorkflowName)
+	require.Len(t, graph.Graph.Statuses, 3)
+	require.Len(t, graph.Graph.Transitions, 2)
+	require.Equal(t, int32(orderStatusCreated), graph.Graph.Transitions[0].From)
+	require.Equal(t, int32(orderStatusApproved), graph.Graph.Transitions[0].To)
+	require.Equal(t, []string{"callback"}, graph.Graph.Transitions[0].Kinds)
+}
+
+func TestAdminServiceNotFound(t *testing.T) {
+	b := workflow.NewBuilder[string, orderStatus]("orders")
+	b.AddStep(orderStatusCreated, func(ctx context.Context, r *workflow.Run[string, orderStatus]) (orderStatus, error) {
+		return orderStatusShipped, nil
+	}, orderStatusShipped)
+
+	wf := b.Build(memstreamer.New(), memrecordstore.New(), memrolescheduler.New())
+	client := newAdminClient(t, wf)
+	ctx := context.Background()
+
+	_, err := client.GetRun(ctx, &workflowpb.GetRunRequest{WorkflowName: "payments", RunId: "run"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.GetRun(ctx, &workflowpb.GetRunRequest{WorkflowName: "orders", RunId: "run"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
@@ -1,3 +1,4 @@
 package workflowpb
 
 //go:generate protoc -I=. --go_out=. ./workflow.proto
+//go:generate protoc -I=. --go_out=. --go-grpc_out=. ./admin.proto